@@ -0,0 +1,123 @@
+package main
+
+import (
+	"database/sql"
+	"log"
+
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/config"
+	buyerDomain "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/buyer/domain"
+	buyerRepository "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/buyer/repository/mariaDB"
+	buyerService "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/buyer/service"
+	carryDomain "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/carry/domain"
+	carryRepository "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/carry/repository/mariadb"
+	carryService "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/carry/services"
+	employeeDomain "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/employees/domain"
+	employeeRepository "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/employees/repository/mariadb"
+	employeeService "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/employees/service"
+	inboundOrdersDomain "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/inboud_orders/domain"
+	inboundOrdersRepository "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/inboud_orders/repository"
+	inboundOrdersService "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/inboud_orders/service"
+	integrityDomain "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/integrity/domain"
+	integrityRepository "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/integrity/repository/mariadb"
+	integrityService "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/integrity/service"
+	localityDomain "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/locality/domain"
+	localityRepository "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/locality/repository/mariadb"
+	localityService "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/locality/services"
+	productDomain "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product/domain"
+	productRepository "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product/repository/mariadb"
+	productService "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product/service"
+	productBatchDomain "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_batch/domain"
+	productBatchRepository "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_batch/repository/mariadb"
+	productBatchService "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_batch/service"
+	productRecordsDomain "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_records/domain"
+	productRecordsRepository "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_records/repository/mariadb"
+	productRecordsService "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_records/service"
+	productTypeDomain "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_type/domain"
+	productTypeRepository "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_type/repository/mariadb"
+	productTypeService "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_type/service"
+	purchaseOrdersDomain "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/purchase_orders/domain"
+	purchaseOrdersRepository "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/purchase_orders/repository/mariaDB"
+	purchaseOrdersService "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/purchase_orders/service"
+	sectionDomain "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/section/domain"
+	sectionRepository "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/section/repository/mariadb"
+	sectionService "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/section/service"
+	sellerDomain "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/seller/domain"
+	sellerRepository "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/seller/repository/mariadb"
+	sellerService "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/seller/services"
+	userDomain "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/users/domain"
+	userRepository "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/users/repository/mariadb"
+	userService "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/users/service"
+	warehouseDomain "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/warehouse/domain"
+	warehouseRepository "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/warehouse/repository/mariadb"
+	warehouseService "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/warehouse/services"
+)
+
+// app wires the domain services the same way the HTTP routes do, so the CLI
+// goes through the same rules as the API.
+type app struct {
+	db *sql.DB
+
+	batches        productBatchDomain.ProductBatchService
+	buyers         buyerDomain.BuyerService
+	carriers       carryDomain.CarryService
+	employees      employeeDomain.EmployeeService
+	inboundOrders  inboundOrdersDomain.InboundOrdersService
+	integrity      integrityDomain.IntegrityService
+	localities     localityDomain.LocalityService
+	productRecords productRecordsDomain.ProductRecordsService
+	productTypes   productTypeDomain.ProductTypeService
+	products       productDomain.ProductService
+	purchaseOrders purchaseOrdersDomain.PurchaseOrdersService
+	sections       sectionDomain.SectionService
+	sellers        sellerDomain.ServiceSeller
+	users          userDomain.UserService
+	warehouses     warehouseDomain.WarehouseService
+}
+
+func newApp() *app {
+	db := config.ConnectDb("mysql")
+
+	buyers := buyerRepository.NewmariadbBuyerRepository(db)
+	employees := employeeRepository.NewMariaDBEmployeeRepository(db)
+	localities := localityRepository.NewMariadbLocalityRepository(db)
+	products := productRepository.CreateProductRepository(db)
+	productRecords := productRecordsRepository.CreateProductRecordsRepository(db)
+	productTypes := productTypeRepository.NewMariadbProductTypeRepository(db)
+	purchaseOrders := purchaseOrdersRepository.NewMariadbPurchaseOrdersRepository(db)
+	sections := sectionRepository.NewMariadbSectionRepository(db)
+	sellers := sellerRepository.NewMariaDBSellerRepository(db)
+	warehouses := warehouseRepository.NewMariadbWarehouseRepository(db)
+
+	codeFormat, err := config.ProductCodeFormat()
+	if err != nil {
+		log.Fatal("invalid product code format: ", err)
+	}
+
+	return &app{
+		db: db,
+
+		batches: productBatchService.NewProductBatchService(
+			productBatchRepository.NewMariadbProductBatchRepository(db),
+			products,
+			sections,
+			employees,
+		),
+		buyers:    buyerService.NewBuyerService(buyers, purchaseOrders),
+		carriers:  carryService.NewCarryService(carryRepository.NewMariadbCarryRepository(db)),
+		employees: employeeService.NewEmployeeService(employees),
+		inboundOrders: inboundOrdersService.NewInboundOrderService(
+			inboundOrdersRepository.NewMariaDBInboundRepositoryRepository(db),
+			employees,
+		),
+		integrity:      integrityService.NewIntegrityService(integrityRepository.NewMariadbIntegrityRepository(db)),
+		localities:     localityService.NewLocalityService(localities, sellers),
+		productRecords: productRecordsService.CreateProductRecordsService(productRecords, products),
+		productTypes:   productTypeService.NewProductTypeService(productTypes),
+		products:       productService.CreateProductService(products, productRecords, productTypes, sellers, codeFormat),
+		purchaseOrders: purchaseOrdersService.NewPurchaseOrdersService(purchaseOrders, buyers),
+		sections:       sectionService.NewServiceSection(sections, productTypes, warehouses),
+		sellers:        sellerService.NewSellerService(sellers),
+		users:          userService.NewUserService(userRepository.NewMariadbUserRepository(db)),
+		warehouses:     warehouseService.NewWarehouseService(warehouses, localities),
+	}
+}
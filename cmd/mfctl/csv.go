@@ -0,0 +1,257 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	localityDomain "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/locality/domain"
+	productDomain "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product/domain"
+	sellerDomain "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/seller/domain"
+)
+
+// entity describes a resource that can be moved in and out as CSV. The
+// columns are the json names of the model, the same ones the API uses.
+type entity struct {
+	required []string
+	create   func(ctx context.Context, app *app, row map[string]string) error
+	export   func(ctx context.Context, app *app) (interface{}, error)
+}
+
+var entities = map[string]entity{
+	"sellers": {
+		required: []string{"cid", "company_name", "address", "telephone", "locality_id"},
+		create: func(ctx context.Context, app *app, row map[string]string) error {
+			var seller sellerDomain.Seller
+			if err := decodeRow(row, &seller); err != nil {
+				return err
+			}
+			_, err := app.sellers.Create(ctx, &seller)
+			return err
+		},
+		export: func(ctx context.Context, app *app) (interface{}, error) {
+			return app.sellers.GetAll(ctx)
+		},
+	},
+	"products": {
+		required: []string{"product_code", "description", "width", "height", "length", "net_weight",
+			"expiration_rate", "recommended_freezing_temperature", "freezing_rate", "product_type_id", "seller_id"},
+		create: func(ctx context.Context, app *app, row map[string]string) error {
+			var product productDomain.Product
+			if err := decodeRow(row, &product); err != nil {
+				return err
+			}
+			_, err := app.products.Create(ctx, &product)
+			return err
+		},
+		export: func(ctx context.Context, app *app) (interface{}, error) {
+			return app.products.GetAll(ctx)
+		},
+	},
+	"localities": {
+		required: []string{"locality_name", "province_name", "country_name"},
+		create: func(ctx context.Context, app *app, row map[string]string) error {
+			var locality localityDomain.LocalityModel
+			if err := decodeRow(row, &locality); err != nil {
+				return err
+			}
+			_, err := app.localities.CreateLocality(ctx, &locality)
+			return err
+		},
+		export: func(ctx context.Context, app *app) (interface{}, error) {
+			return app.localities.GetAll(ctx)
+		},
+	},
+}
+
+func lookupEntity(args []string) (string, entity, []string, error) {
+	if len(args) == 0 {
+		return "", entity{}, nil, errors.New("missing entity, want sellers, products or localities")
+	}
+
+	e, ok := entities[args[0]]
+	if !ok {
+		return "", entity{}, nil, fmt.Errorf("unknown entity %q, want sellers, products or localities", args[0])
+	}
+
+	return args[0], e, args[1:], nil
+}
+
+func runImport(ctx context.Context, app *app, args []string, out io.Writer) error {
+	name, e, args, err := lookupEntity(args)
+	if err != nil {
+		return err
+	}
+
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	file := flags.String("file", "", "CSV file with a header row")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *file == "" {
+		return errors.New("-file is required")
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	created, failed, err := importCSV(ctx, app, e, f, out)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "%s: %d created, %d failed\n", name, created, failed)
+	if failed > 0 {
+		return fmt.Errorf("%d rows failed", failed)
+	}
+	return nil
+}
+
+// importCSV creates one resource per row and keeps going on errors, so a
+// single bad line does not hide the problems in the rest of the file.
+func importCSV(ctx context.Context, app *app, e entity, in io.Reader, out io.Writer) (int, int, error) {
+	reader := csv.NewReader(in)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return 0, 0, fmt.Errorf("reading header: %w", err)
+	}
+	for i := range header {
+		header[i] = strings.TrimSpace(header[i])
+	}
+
+	for _, column := range e.required {
+		if !contains(header, column) {
+			return 0, 0, fmt.Errorf("missing column %q", column)
+		}
+	}
+
+	created, failed := 0, 0
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			fmt.Fprintf(out, "line %d: %v\n", line, err)
+			failed++
+			continue
+		}
+
+		row := make(map[string]string, len(header))
+		for i, column := range header {
+			row[column] = strings.TrimSpace(record[i])
+		}
+
+		if err := e.create(ctx, app, row); err != nil {
+			fmt.Fprintf(out, "line %d: %v\n", line, err)
+			failed++
+			continue
+		}
+		created++
+	}
+
+	return created, failed, nil
+}
+
+func runExport(ctx context.Context, app *app, args []string, out io.Writer) error {
+	_, e, args, err := lookupEntity(args)
+	if err != nil {
+		return err
+	}
+
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	file := flags.String("file", "", "output file, stdout when empty")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	rows, err := e.export(ctx, app)
+	if err != nil {
+		return err
+	}
+
+	if *file != "" {
+		f, err := os.Create(*file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+
+	return render(out, formatCSV, rows)
+}
+
+// decodeRow fills the fields of target from the row, matching columns to
+// json names. The id column is ignored so exported files can be imported
+// back into another database.
+func decodeRow(row map[string]string, target interface{}) error {
+	v := reflect.ValueOf(target).Elem()
+
+	for _, f := range fields(v.Type()) {
+		if f.name == "id" {
+			continue
+		}
+
+		raw, ok := row[f.name]
+		if !ok || raw == "" {
+			continue
+		}
+
+		if err := setField(v.FieldByIndex(f.index), raw); err != nil {
+			return fmt.Errorf("%s: %w", f.name, err)
+		}
+	}
+
+	return nil
+}
+
+func setField(v reflect.Value, raw string) error {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		value, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", raw)
+		}
+		v.SetInt(value)
+	case reflect.Float32, reflect.Float64:
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", raw)
+		}
+		v.SetFloat(value)
+	case reflect.Bool:
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", raw)
+		}
+		v.SetBool(value)
+	default:
+		return fmt.Errorf("unsupported column type %s", v.Kind())
+	}
+
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/seed"
+)
+
+// runGenerate fills the database with a deterministic, consistent data set
+// for demos and load tests. See seed.Generator for what gets created.
+func runGenerate(ctx context.Context, app *app, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("generate", flag.ContinueOnError)
+	seedValue := flags.Int64("seed", 1, "random seed, the same seed generates the same data")
+	scale := flags.Int("scale", 1, "multiplies the default volume")
+	dir := flags.String("dir", "db/fixtures", "directory with reference.sql")
+	format := flags.String("format", formatTable, "table, json or csv")
+
+	// Totals default to the scaled volume, per-parent counts do not scale.
+	defaults := seed.DefaultConfig(1, 1)
+	localities := flags.Int("localities", 0, fmt.Sprintf("localities (default %d x scale)", defaults.Localities))
+	sellers := flags.Int("sellers", 0, fmt.Sprintf("sellers (default %d x scale)", defaults.Sellers))
+	warehouses := flags.Int("warehouses", 0, fmt.Sprintf("warehouses (default %d x scale)", defaults.Warehouses))
+	buyers := flags.Int("buyers", 0, fmt.Sprintf("buyers (default %d x scale)", defaults.Buyers))
+	carriers := flags.Int("carriers", 0, fmt.Sprintf("carriers (default %d x scale)", defaults.Carriers))
+	productsPerSeller := flags.Int("products-per-seller", defaults.ProductsPerSeller, "products of each seller")
+	sectionsPerWarehouse := flags.Int("sections-per-warehouse", defaults.SectionsPerWarehouse, "sections of each warehouse")
+	batchesPerSection := flags.Int("batches-per-section", defaults.BatchesPerSection, "product batches stored in each section")
+	employeesPerWarehouse := flags.Int("employees-per-warehouse", defaults.EmployeesPerWarehouse, "employees of each warehouse")
+	ordersPerBuyer := flags.Int("orders-per-buyer", defaults.PurchaseOrdersPerBuyer, "purchase orders of each buyer")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	config := seed.DefaultConfig(*seedValue, *scale)
+	override(&config.Localities, *localities)
+	override(&config.Sellers, *sellers)
+	override(&config.Warehouses, *warehouses)
+	override(&config.Buyers, *buyers)
+	override(&config.Carriers, *carriers)
+	config.ProductsPerSeller = *productsPerSeller
+	config.SectionsPerWarehouse = *sectionsPerWarehouse
+	config.BatchesPerSection = *batchesPerSection
+	config.EmployeesPerWarehouse = *employeesPerWarehouse
+	config.PurchaseOrdersPerBuyer = *ordersPerBuyer
+
+	if err := applyReference(ctx, app, *dir); err != nil {
+		return err
+	}
+
+	generator := seed.NewGenerator(seed.Services{
+		Localities:     app.localities,
+		Sellers:        app.sellers,
+		Products:       app.products,
+		ProductRecords: app.productRecords,
+		Warehouses:     app.warehouses,
+		Sections:       app.sections,
+		Batches:        app.batches,
+		Buyers:         app.buyers,
+		Carriers:       app.carriers,
+		Employees:      app.employees,
+		InboundOrders:  app.inboundOrders,
+		PurchaseOrders: app.purchaseOrders,
+	}, config)
+
+	summary, err := generator.Run(ctx)
+	if renderErr := render(out, *format, summary); renderErr != nil && err == nil {
+		err = renderErr
+	}
+
+	return err
+}
+
+func override(target *int, value int) {
+	if value > 0 {
+		*target = value
+	}
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+)
+
+// runIntegrity prints every check and fails when any of them found
+// violations, so it can gate deploys or run from cron.
+func runIntegrity(ctx context.Context, app *app, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("integrity", flag.ContinueOnError)
+	format := flags.String("format", formatTable, "table, json or csv")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	results, err := app.integrity.Check(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := render(out, *format, results); err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, result := range results {
+		if result.Violations > 0 {
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d checks found violations", failed, len(results))
+	}
+	return nil
+}
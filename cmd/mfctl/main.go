@@ -0,0 +1,64 @@
+// Command mfctl operates a Mercado Fresco database: migrations, fixtures,
+// generated demo data, users and API keys, CSV import/export, reports and
+// integrity checks. It reads the same .env as the server.
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+type command struct {
+	usage string
+	run   func(ctx context.Context, app *app, args []string, out io.Writer) error
+}
+
+var commands = map[string]command{
+	"migrate":   {"migrate [-file db/CreateDB.sql]", runMigrate},
+	"seed":      {"seed [-dir db/fixtures]", runSeed},
+	"generate":  {"generate [-seed N] [-scale N] [-sellers N ...]", runGenerate},
+	"user":      {"user create -username NAME (password from MFCTL_PASSWORD or stdin)", runUser},
+	"apikey":    {"apikey create -username NAME [-name LABEL]", runApiKey},
+	"import":    {"import sellers|products|localities -file FILE.csv", runImport},
+	"export":    {"export sellers|products|localities [-file FILE.csv]", runExport},
+	"report":    {"report NAME [-id ID] [-format table|json|csv]", runReport},
+	"integrity": {"integrity [-format table|json|csv]", runIntegrity},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage(os.Stderr)
+		os.Exit(2)
+	}
+
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		usage(os.Stderr)
+		os.Exit(2)
+	}
+
+	app := newApp()
+	defer app.db.Close()
+
+	if err := cmd.run(context.Background(), app, os.Args[2:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "mfctl:", err)
+		os.Exit(1)
+	}
+}
+
+func usage(w io.Writer) {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(w, "usage: mfctl <command> [flags]")
+	fmt.Fprintln(w)
+	for _, name := range names {
+		fmt.Fprintln(w, "  mfctl "+commands[name].usage)
+	}
+}
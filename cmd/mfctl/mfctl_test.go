@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	employeeDomain "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/employees/domain"
+	productDomain "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product/domain"
+	sellerDomain "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/seller/domain"
+)
+
+func TestSplitStatements(t *testing.T) {
+	t.Run("split_ok: should drop comments and split on trailing semicolons", func(t *testing.T) {
+		script := "-- comment\nCREATE TABLE a (\n  id INT);\n\nUSE `db` ;\nINSERT INTO a VALUES (1)"
+
+		statements := splitStatements(script)
+
+		assert.Equal(t, []string{"CREATE TABLE a (\n  id INT)", "USE `db`", "INSERT INTO a VALUES (1)"}, statements)
+	})
+
+	t.Run("split_schema: should split the bundled schema", func(t *testing.T) {
+		script, err := os.ReadFile("../../db/CreateDB.sql")
+		assert.NoError(t, err)
+
+		statements := splitStatements(string(script))
+
+		assert.NotEmpty(t, statements)
+		for _, statement := range statements {
+			assert.False(t, strings.HasSuffix(statement, ";"))
+			assert.False(t, strings.HasPrefix(statement, "--"))
+		}
+	})
+}
+
+func TestRender(t *testing.T) {
+	sellers := &[]sellerDomain.Seller{
+		{Id: 1, Cid: 10, CompanyName: "Frutas do Vale", Address: "Rua A", Telephone: "123", LocalityId: 2},
+	}
+
+	t.Run("render_csv: should use json names as the header", func(t *testing.T) {
+		var out bytes.Buffer
+
+		assert.NoError(t, render(&out, formatCSV, sellers))
+		assert.Equal(t, "id,cid,company_name,address,telephone,locality_id\n1,10,Frutas do Vale,Rua A,123,2\n", out.String())
+	})
+
+	t.Run("render_table: should print a header and a line per row", func(t *testing.T) {
+		var out bytes.Buffer
+
+		assert.NoError(t, render(&out, formatTable, sellers))
+
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		assert.Len(t, lines, 2)
+		assert.True(t, strings.HasPrefix(lines[0], "ID"))
+		assert.Contains(t, lines[1], "Frutas do Vale")
+	})
+
+	t.Run("render_embedded: should flatten embedded structs", func(t *testing.T) {
+		var out bytes.Buffer
+		report := employeeDomain.EmployeeInboundOrdersReport{}
+
+		assert.NoError(t, render(&out, formatCSV, report))
+
+		header := strings.Split(strings.Split(out.String(), "\n")[0], ",")
+		assert.Contains(t, header, "card_number_id")
+	})
+
+	t.Run("render_empty: should print nothing as csv", func(t *testing.T) {
+		var out bytes.Buffer
+
+		assert.NoError(t, render(&out, formatCSV, &[]sellerDomain.Seller{}))
+		assert.Empty(t, out.String())
+	})
+
+	t.Run("render_unknown_format: should return an error", func(t *testing.T) {
+		assert.Error(t, render(&bytes.Buffer{}, "xml", sellers))
+	})
+}
+
+func TestDecodeRow(t *testing.T) {
+	t.Run("decode_ok: should fill fields by json name and ignore id", func(t *testing.T) {
+		var product productDomain.Product
+
+		err := decodeRow(map[string]string{
+			"id":              "99",
+			"product_code":    "FRU-0001",
+			"width":           "12.5",
+			"product_type_id": "3",
+		}, &product)
+
+		assert.NoError(t, err)
+		assert.Equal(t, productDomain.Product{ProductCode: "FRU-0001", Width: 12.5, ProductTypeId: 3}, product)
+	})
+
+	t.Run("decode_invalid: should name the column that failed", func(t *testing.T) {
+		var product productDomain.Product
+
+		err := decodeRow(map[string]string{"width": "wide"}, &product)
+
+		assert.EqualError(t, err, `width: invalid number "wide"`)
+	})
+}
+
+func TestImportCSV(t *testing.T) {
+	t.Run("import_missing_column: should reject the file before creating anything", func(t *testing.T) {
+		_, _, err := importCSV(context.Background(), nil, entities["sellers"], strings.NewReader("cid,company_name\n1,a\n"), &bytes.Buffer{})
+
+		assert.EqualError(t, err, `missing column "address"`)
+	})
+}
+
+func TestReadPassword(t *testing.T) {
+	t.Run("read_password_stdin: should read the first line of a pipe", func(t *testing.T) {
+		password, err := readPassword(strings.NewReader("s3nh4forte\nignored\n"), &bytes.Buffer{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "s3nh4forte", password)
+	})
+
+	t.Run("read_password_env: should prefer MFCTL_PASSWORD", func(t *testing.T) {
+		t.Setenv(passwordEnv, "fromenv123")
+
+		password, err := readPassword(strings.NewReader("s3nh4forte\n"), &bytes.Buffer{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "fromenv123", password)
+	})
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// runMigrate applies the schema script. Every table is created with
+// IF NOT EXISTS, so running it against an existing database is safe.
+func runMigrate(ctx context.Context, app *app, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	file := flags.String("file", "db/CreateDB.sql", "schema script to apply")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	script, err := os.ReadFile(*file)
+	if err != nil {
+		return err
+	}
+
+	// The script relies on session state (USE, SET @OLD_...), so every
+	// statement has to go through the same connection.
+	conn, err := app.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	statements := splitStatements(string(script))
+	for i, statement := range statements {
+		if _, err := conn.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("statement %d: %w\n%s", i+1, err, statement)
+		}
+	}
+
+	fmt.Fprintf(out, "applied %d statements from %s\n", len(statements), *file)
+	return nil
+}
+
+// splitStatements breaks a SQL script into statements. It drops "--" comment
+// lines and ends a statement at a line ending with ";", which is how the
+// Workbench generated scripts are laid out.
+func splitStatements(script string) []string {
+	var statements []string
+	var current strings.Builder
+
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+
+		current.WriteString(line)
+		current.WriteString("\n")
+
+		if strings.HasSuffix(trimmed, ";") {
+			statement := strings.TrimSuffix(strings.TrimSpace(current.String()), ";")
+			statements = append(statements, strings.TrimSpace(statement))
+			current.Reset()
+		}
+	}
+
+	if rest := strings.TrimSpace(current.String()); rest != "" {
+		statements = append(statements, rest)
+	}
+
+	return statements
+}
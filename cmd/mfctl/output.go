@@ -0,0 +1,178 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatCSV   = "csv"
+)
+
+// render prints a struct or a slice of structs. Columns are the json names
+// of the fields, in declaration order, with embedded structs flattened.
+func render(out io.Writer, format string, value interface{}) error {
+	switch format {
+	case formatJSON:
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(value)
+	case formatTable, formatCSV:
+	default:
+		return fmt.Errorf("unknown format %q, want table, json or csv", format)
+	}
+
+	rows := rowsOf(value)
+	if len(rows) == 0 {
+		if format == formatTable {
+			fmt.Fprintln(out, "no rows")
+		}
+		return nil
+	}
+
+	header := columns(rows[0].Type())
+	records := make([][]string, 0, len(rows))
+	for _, row := range rows {
+		records = append(records, cells(row))
+	}
+
+	if format == formatCSV {
+		return writeCSV(out, header, records)
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, strings.ToUpper(strings.Join(header, "\t")))
+	for _, record := range records {
+		fmt.Fprintln(w, strings.Join(record, "\t"))
+	}
+	return w.Flush()
+}
+
+func writeCSV(out io.Writer, header []string, records [][]string) error {
+	w := csv.NewWriter(out)
+	if err := w.Write(header); err != nil {
+		return err
+	}
+	if err := w.WriteAll(records); err != nil {
+		return err
+	}
+	return w.Error()
+}
+
+// rowsOf dereferences pointers and returns the struct values to print.
+func rowsOf(value interface{}) []reflect.Value {
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Slice {
+		return []reflect.Value{v}
+	}
+
+	rows := make([]reflect.Value, 0, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		row := v.Index(i)
+		for row.Kind() == reflect.Ptr {
+			row = row.Elem()
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// field is an exported struct field reachable from the row type, addressed
+// by its index path so embedded structs can be flattened.
+type field struct {
+	name  string
+	index []int
+}
+
+func fields(t reflect.Type) []field {
+	var result []field
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+
+		if f.Anonymous && f.Type.Kind() == reflect.Struct {
+			for _, inner := range fields(f.Type) {
+				result = append(result, field{inner.name, append([]int{i}, inner.index...)})
+			}
+			continue
+		}
+
+		name := f.Name
+		if tag, ok := f.Tag.Lookup("json"); ok {
+			tag = strings.Split(tag, ",")[0]
+			if tag == "-" {
+				continue
+			}
+			if tag != "" {
+				name = tag
+			}
+		}
+
+		result = append(result, field{name, []int{i}})
+	}
+
+	return result
+}
+
+func columns(t reflect.Type) []string {
+	var names []string
+	for _, f := range fields(t) {
+		names = append(names, f.name)
+	}
+	return names
+}
+
+func cells(row reflect.Value) []string {
+	var values []string
+	for _, f := range fields(row.Type()) {
+		values = append(values, cell(row.FieldByIndex(f.index)))
+	}
+	return values
+}
+
+func cell(v reflect.Value) string {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+
+	switch value := v.Interface().(type) {
+	case time.Time:
+		if value.IsZero() {
+			return ""
+		}
+		return value.Format(time.RFC3339)
+	case json.RawMessage:
+		return string(value)
+	}
+
+	if v.Kind() == reflect.Slice {
+		items := make([]string, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			items = append(items, cell(v.Index(i)))
+		}
+		return strings.Join(items, ",")
+	}
+
+	return fmt.Sprint(v.Interface())
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	sectionDomain "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/section/domain"
+)
+
+// report runs one of the API reports. byId is used when -id is given and
+// all otherwise, matching the optional id query parameter of the endpoints.
+type report struct {
+	all  func(ctx context.Context, app *app) (interface{}, error)
+	byId func(ctx context.Context, app *app, id int64) (interface{}, error)
+}
+
+var reports = map[string]report{
+	"sections-products": {
+		all: func(ctx context.Context, app *app) (interface{}, error) {
+			return app.sections.GetAllProductCountBySection(ctx)
+		},
+		byId: func(ctx context.Context, app *app, id int64) (interface{}, error) {
+			return app.sections.GetByIdProductCountBySection(ctx, id)
+		},
+	},
+	"sections-occupancy": {
+		all: func(ctx context.Context, app *app) (interface{}, error) {
+			return app.sections.GetOccupancy(ctx, sectionDomain.OccupancyFilter{})
+		},
+		byId: func(ctx context.Context, app *app, id int64) (interface{}, error) {
+			return app.sections.GetOccupancy(ctx, sectionDomain.OccupancyFilter{WarehouseId: id})
+		},
+	},
+	"localities-sellers": {
+		all: func(ctx context.Context, app *app) (interface{}, error) {
+			return app.localities.GetAllReportSeller(ctx)
+		},
+		byId: func(ctx context.Context, app *app, id int64) (interface{}, error) {
+			return app.localities.GetByIdReportSeller(ctx, id)
+		},
+	},
+	"localities-carriers": {
+		all: func(ctx context.Context, app *app) (interface{}, error) {
+			return app.localities.ReportCarrie(ctx, 0)
+		},
+		byId: func(ctx context.Context, app *app, id int64) (interface{}, error) {
+			return app.localities.ReportCarrie(ctx, id)
+		},
+	},
+	"employees-inbound-orders": {
+		all: func(ctx context.Context, app *app) (interface{}, error) {
+			return app.employees.GetAllReportInboundOrders(ctx)
+		},
+		byId: func(ctx context.Context, app *app, id int64) (interface{}, error) {
+			return app.employees.GetReportInboundOrdersById(ctx, id)
+		},
+	},
+	"buyers-purchase-orders": {
+		all: func(ctx context.Context, app *app) (interface{}, error) {
+			return app.buyers.GetAllPurchaseOrdersReports(ctx)
+		},
+		byId: func(ctx context.Context, app *app, id int64) (interface{}, error) {
+			return app.buyers.GetPurchaseOrdersReports(ctx, id)
+		},
+	},
+	"products-records": {
+		all: func(ctx context.Context, app *app) (interface{}, error) {
+			return app.products.GetAllReportProductRecords(ctx)
+		},
+		byId: func(ctx context.Context, app *app, id int64) (interface{}, error) {
+			return app.products.GetReportProductRecordsById(ctx, id)
+		},
+	},
+}
+
+func reportNames() string {
+	names := make([]string, 0, len(reports))
+	for name := range reports {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+func runReport(ctx context.Context, app *app, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New("missing report name, want one of " + reportNames())
+	}
+
+	r, ok := reports[args[0]]
+	if !ok {
+		return fmt.Errorf("unknown report %q, want one of %s", args[0], reportNames())
+	}
+
+	flags := flag.NewFlagSet("report", flag.ContinueOnError)
+	id := flags.Int64("id", 0, "restrict the report to one resource")
+	format := flags.String("format", formatTable, "table, json or csv")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	var result interface{}
+	var err error
+	if *id > 0 {
+		result, err = r.byId(ctx, app, *id)
+	} else {
+		result, err = r.all(ctx, app)
+	}
+	if err != nil {
+		return err
+	}
+
+	return render(out, *format, result)
+}
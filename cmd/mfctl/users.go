@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// passwordEnv names the variable the password can come from instead of
+// stdin. A flag would leave it in the shell history and in ps.
+const passwordEnv = "MFCTL_PASSWORD"
+
+func runUser(ctx context.Context, app *app, args []string, out io.Writer) error {
+	if len(args) == 0 || args[0] != "create" {
+		return errors.New("usage: mfctl user create -username NAME (password from " + passwordEnv + " or stdin)")
+	}
+
+	flags := flag.NewFlagSet("user create", flag.ContinueOnError)
+	username := flags.String("username", "", "login name")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	password, err := readPassword(os.Stdin, os.Stderr)
+	if err != nil {
+		return err
+	}
+
+	user, err := app.users.Create(ctx, *username, password)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "created user %q with id %d\n", user.Username, user.Id)
+	return nil
+}
+
+func runApiKey(ctx context.Context, app *app, args []string, out io.Writer) error {
+	if len(args) == 0 || args[0] != "create" {
+		return errors.New("usage: mfctl apikey create -username NAME [-name LABEL]")
+	}
+
+	flags := flag.NewFlagSet("apikey create", flag.ContinueOnError)
+	username := flags.String("username", "", "owner of the key")
+	name := flags.String("name", "default", "label to tell keys apart")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	apiKey, key, err := app.users.CreateApiKey(ctx, *username, *name)
+	if err != nil {
+		return err
+	}
+
+	// Only the hash is stored, so this is the one chance to see the key.
+	fmt.Fprintf(out, "created API key %q (%s...) for %q\n", apiKey.Name, apiKey.Prefix, *username)
+	fmt.Fprintln(out, key)
+	return nil
+}
+
+// readPassword takes the password from MFCTL_PASSWORD, or else from the
+// first line of in. A terminal gets a prompt and does not echo what is typed.
+func readPassword(in io.Reader, prompt io.Writer) (string, error) {
+	if password, ok := os.LookupEnv(passwordEnv); ok {
+		return password, nil
+	}
+
+	if file, ok := in.(*os.File); ok && term.IsTerminal(int(file.Fd())) {
+		fmt.Fprint(prompt, "password: ")
+		password, err := term.ReadPassword(int(file.Fd()))
+		fmt.Fprintln(prompt)
+		return string(password), err
+	}
+
+	line, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
@@ -0,0 +1,120 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/events/domain"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/pkg/httputil"
+)
+
+const (
+	pollInterval      = time.Second
+	heartbeatInterval = 15 * time.Second
+)
+
+type EventController struct {
+	service domain.EventService
+}
+
+func NewEventController(service domain.EventService) *EventController {
+	return &EventController{service: service}
+}
+
+// Stream godoc
+// @Summary      Stream live warehouse activity
+// @Description  Server-sent events for new inbound orders, batches, section capacity changes and temperature alerts.
+// @Description  Each message id is the event id; reconnecting clients resume after the Last-Event-ID header.
+// @Description  Events committed late may arrive after others with a higher id; clients should dedupe by id.
+// @Tags         Events
+// @Produce      text/event-stream
+// @Param warehouse_id query int false "Only events of this warehouse"
+// @Param event_type query string false "Comma separated event types"
+// @Param Last-Event-ID header int false "Resume after this event id"
+// @Success      200  {object}  domain.Event
+// @Failure      400  {object}  httputil.HTTPError
+// @Failure      500  {object}  httputil.HTTPError
+// @Router /events/stream [get]
+func (c *EventController) Stream() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var filter domain.EventFilter
+
+		if warehouseId := ctx.Query("warehouse_id"); warehouseId != "" {
+			id, err := strconv.ParseInt(warehouseId, 10, 64)
+			if err != nil {
+				httputil.NewError(ctx, http.StatusBadRequest, errors.New("invalid warehouse_id"))
+				return
+			}
+			filter.WarehouseId = id
+		}
+
+		if eventTypes := ctx.Query("event_type"); eventTypes != "" {
+			filter.EventTypes = strings.Split(eventTypes, ",")
+		}
+
+		var lastEventId *int64
+		if header := ctx.GetHeader("Last-Event-ID"); header != "" {
+			id, err := strconv.ParseInt(header, 10, 64)
+			if err != nil {
+				httputil.NewError(ctx, http.StatusBadRequest, domain.ErrInvalidLastEventId)
+				return
+			}
+			lastEventId = &id
+		}
+
+		cursor, err := c.service.GetCursor(ctx.Request.Context(), lastEventId, filter)
+		if errors.Is(err, domain.ErrUnknownEventType) || errors.Is(err, domain.ErrInvalidLastEventId) {
+			httputil.NewError(ctx, http.StatusBadRequest, err)
+			return
+		}
+		if err != nil {
+			httputil.NewError(ctx, http.StatusInternalServerError, err)
+			return
+		}
+
+		ctx.Header("Content-Type", "text/event-stream")
+		ctx.Header("Cache-Control", "no-cache")
+		ctx.Header("Connection", "keep-alive")
+		ctx.Header("X-Accel-Buffering", "no")
+		ctx.Status(http.StatusOK)
+		ctx.Writer.WriteHeaderNow()
+		ctx.Writer.Flush()
+
+		poll := time.NewTicker(pollInterval)
+		defer poll.Stop()
+
+		heartbeat := time.NewTicker(heartbeatInterval)
+		defer heartbeat.Stop()
+
+		for {
+			events, err := c.service.GetAfter(ctx.Request.Context(), cursor, filter)
+			if err != nil {
+				// Closing the stream makes the client reconnect with its Last-Event-ID.
+				return
+			}
+
+			for _, event := range events {
+				ctx.Render(-1, sse.Event{
+					Id:    strconv.FormatInt(event.Id, 10),
+					Event: event.EventType,
+					Data:  event,
+				})
+			}
+			ctx.Writer.Flush()
+
+			select {
+			case <-ctx.Request.Context().Done():
+				return
+			case <-heartbeat.C:
+				ctx.Writer.WriteString(": heartbeat\n\n")
+				ctx.Writer.Flush()
+			case <-poll.C:
+			}
+		}
+	}
+}
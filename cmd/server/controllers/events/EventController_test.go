@@ -0,0 +1,137 @@
+package controllers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	controllers "github.com/vinigracindo/mercado-fresco-stranger-strings/cmd/server/controllers/events"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/events/domain"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/events/domain/mocks"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/pkg/testutil"
+)
+
+const EndpointEventStream = "/api/v1/events/stream"
+
+func setUpEventRoutes(service domain.EventService) *gin.Engine {
+	r := testutil.SetUpRouter()
+	r.GET(EndpointEventStream, controllers.NewEventController(service).Stream())
+	return r
+}
+
+// executeStreamRequest serves the request until the client goes away, the
+// way a dashboard closing the connection ends the stream.
+func executeStreamRequest(r *gin.Engine, path string, lastEventId string) *httptest.ResponseRecorder {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	request := httptest.NewRequest(http.MethodGet, path, nil).WithContext(ctx)
+	if lastEventId != "" {
+		request.Header.Set("Last-Event-ID", lastEventId)
+	}
+	response := httptest.NewRecorder()
+
+	r.ServeHTTP(response, request)
+
+	return response
+}
+
+func TestEventController_Stream(t *testing.T) {
+	t.Run("stream_ok: should push new events with their id and type", func(t *testing.T) {
+		service := mocks.NewEventService(t)
+
+		filter := domain.EventFilter{WarehouseId: 1, EventTypes: []string{"inbound_order.created"}}
+		event := domain.Event{
+			Id:          43,
+			EventType:   "inbound_order.created",
+			AggregateId: 3,
+			WarehouseId: 1,
+			Data:        json.RawMessage(`{"id":3}`),
+		}
+
+		cursor := domain.NewCursor(42)
+
+		service.On("GetCursor", mock.Anything, (*int64)(nil), filter).Return(cursor, nil).Once()
+		service.On("GetAfter", mock.Anything, cursor, filter).Return([]domain.Event{event}, nil).Once()
+		service.On("GetAfter", mock.Anything, cursor, filter).Return([]domain.Event{}, nil).Maybe()
+
+		r := setUpEventRoutes(service)
+
+		response := executeStreamRequest(r, EndpointEventStream+"?warehouse_id=1&event_type=inbound_order.created", "")
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, "text/event-stream", response.Header().Get("Content-Type"))
+		assert.Contains(t, response.Body.String(), "id:43\n")
+		assert.Contains(t, response.Body.String(), "event:inbound_order.created\n")
+		assert.Contains(t, response.Body.String(), `"data":{"id":3}`)
+	})
+
+	t.Run("stream_reconnect: should resume after the Last-Event-ID header", func(t *testing.T) {
+		service := mocks.NewEventService(t)
+
+		lastEventId := int64(7)
+
+		cursor := domain.NewCursor(lastEventId)
+
+		service.On("GetCursor", mock.Anything, &lastEventId, domain.EventFilter{}).Return(cursor, nil).Once()
+		service.On("GetAfter", mock.Anything, cursor, domain.EventFilter{}).Return([]domain.Event{}, nil)
+
+		r := setUpEventRoutes(service)
+
+		response := executeStreamRequest(r, EndpointEventStream, "7")
+
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+
+	t.Run("stream_invalid_warehouse_id: should return 400", func(t *testing.T) {
+		service := mocks.NewEventService(t)
+
+		r := setUpEventRoutes(service)
+
+		response := executeStreamRequest(r, EndpointEventStream+"?warehouse_id=abc", "")
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("stream_invalid_last_event_id: should return 400", func(t *testing.T) {
+		service := mocks.NewEventService(t)
+
+		r := setUpEventRoutes(service)
+
+		response := executeStreamRequest(r, EndpointEventStream, "abc")
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("stream_unknown_event_type: should return 400", func(t *testing.T) {
+		service := mocks.NewEventService(t)
+
+		filter := domain.EventFilter{EventTypes: []string{"unknown"}}
+		service.On("GetCursor", mock.Anything, (*int64)(nil), filter).Return(nil, domain.ErrUnknownEventType).Once()
+
+		r := setUpEventRoutes(service)
+
+		response := executeStreamRequest(r, EndpointEventStream+"?event_type=unknown", "")
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("stream_error: should return 500 when the stream cannot start", func(t *testing.T) {
+		service := mocks.NewEventService(t)
+
+		service.On("GetCursor", mock.Anything, (*int64)(nil), domain.EventFilter{}).Return(nil, errors.New("any error")).Once()
+
+		r := setUpEventRoutes(service)
+
+		response := executeStreamRequest(r, EndpointEventStream, "")
+
+		assert.Equal(t, http.StatusInternalServerError, response.Code)
+	})
+}
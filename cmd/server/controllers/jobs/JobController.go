@@ -0,0 +1,132 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/jobs/domain"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/pkg/httputil"
+)
+
+type JobController struct {
+	service domain.JobService
+}
+
+func NewJobController(service domain.JobService) *JobController {
+	return &JobController{service: service}
+}
+
+// GetAll godoc
+// @Summary      List jobs
+// @Description  List background jobs with their schedule, state and last run
+// @Tags         Jobs
+// @Produce      json
+// @Success      200  {array}   domain.Job
+// @Failure      500  {object}  httputil.HTTPError
+// @Router /jobs [get]
+func (c *JobController) GetAll() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		jobs, err := c.service.GetAll(ctx.Request.Context())
+		if err != nil {
+			httputil.NewError(ctx, http.StatusInternalServerError, err)
+			return
+		}
+
+		httputil.NewResponse(ctx, http.StatusOK, jobs)
+	}
+}
+
+// GetRuns godoc
+// @Summary      Job history
+// @Description  List the latest runs of a job with their duration and error
+// @Tags         Jobs
+// @Produce      json
+// @Param name path string true "Job name"
+// @Success      200  {array}   domain.JobRun
+// @Failure      404  {object}  httputil.HTTPError
+// @Failure      500  {object}  httputil.HTTPError
+// @Router /jobs/{name}/runs [get]
+func (c *JobController) GetRuns() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		runs, err := c.service.GetRuns(ctx.Request.Context(), ctx.Param("name"))
+		if err != nil {
+			httputil.NewError(ctx, statusFromError(err), err)
+			return
+		}
+
+		httputil.NewResponse(ctx, http.StatusOK, runs)
+	}
+}
+
+// Pause godoc
+// @Summary      Pause job
+// @Description  Stop scheduled runs of a job on every replica
+// @Tags         Jobs
+// @Param name path string true "Job name"
+// @Success      204
+// @Failure      404  {object}  httputil.HTTPError
+// @Failure      500  {object}  httputil.HTTPError
+// @Router /jobs/{name}/pause [post]
+func (c *JobController) Pause() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if err := c.service.Pause(ctx.Request.Context(), ctx.Param("name")); err != nil {
+			httputil.NewError(ctx, statusFromError(err), err)
+			return
+		}
+
+		httputil.NewResponse(ctx, http.StatusNoContent, nil)
+	}
+}
+
+// Resume godoc
+// @Summary      Resume job
+// @Description  Resume scheduled runs of a paused job
+// @Tags         Jobs
+// @Param name path string true "Job name"
+// @Success      204
+// @Failure      404  {object}  httputil.HTTPError
+// @Failure      500  {object}  httputil.HTTPError
+// @Router /jobs/{name}/resume [post]
+func (c *JobController) Resume() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if err := c.service.Resume(ctx.Request.Context(), ctx.Param("name")); err != nil {
+			httputil.NewError(ctx, statusFromError(err), err)
+			return
+		}
+
+		httputil.NewResponse(ctx, http.StatusNoContent, nil)
+	}
+}
+
+// Trigger godoc
+// @Summary      Trigger job
+// @Description  Start a run now, even when the job is paused
+// @Tags         Jobs
+// @Param name path string true "Job name"
+// @Success      202
+// @Failure      404  {object}  httputil.HTTPError
+// @Failure      409  {object}  httputil.HTTPError
+// @Failure      500  {object}  httputil.HTTPError
+// @Router /jobs/{name}/trigger [post]
+func (c *JobController) Trigger() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if err := c.service.Trigger(ctx.Request.Context(), ctx.Param("name")); err != nil {
+			httputil.NewError(ctx, statusFromError(err), err)
+			return
+		}
+
+		httputil.NewResponse(ctx, http.StatusAccepted, nil)
+	}
+}
+
+func statusFromError(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrJobNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrJobAlreadyRunning):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,155 @@
+package controllers_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	controllers "github.com/vinigracindo/mercado-fresco-stranger-strings/cmd/server/controllers/jobs"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/jobs/domain"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/jobs/domain/mocks"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/pkg/testutil"
+)
+
+const EndpointJobs = "/api/v1/jobs"
+
+func setUpJobRoutes(service domain.JobService) *gin.Engine {
+	controller := controllers.NewJobController(service)
+
+	r := testutil.SetUpRouter()
+	group := r.Group(EndpointJobs)
+	group.GET("/", controller.GetAll())
+	group.GET("/:name/runs", controller.GetRuns())
+	group.POST("/:name/pause", controller.Pause())
+	group.POST("/:name/resume", controller.Resume())
+	group.POST("/:name/trigger", controller.Trigger())
+
+	return r
+}
+
+func TestJobController_GetAll(t *testing.T) {
+	t.Run("get_all_ok: should list jobs", func(t *testing.T) {
+		jobs := []domain.Job{{Name: "logs.retention", Schedule: "0 3 * * *"}}
+
+		service := mocks.NewJobService(t)
+		service.On("GetAll", mock.Anything).Return(jobs, nil).Once()
+
+		r := setUpJobRoutes(service)
+
+		response := testutil.ExecuteTestRequest(r, http.MethodGet, EndpointJobs+"/", nil)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.JSONEq(t, testutil.StringJSON(map[string]interface{}{"data": jobs}), response.Body.String())
+	})
+
+	t.Run("get_all_error: should return 500", func(t *testing.T) {
+		service := mocks.NewJobService(t)
+		service.On("GetAll", mock.Anything).Return(nil, errors.New("any error")).Once()
+
+		r := setUpJobRoutes(service)
+
+		response := testutil.ExecuteTestRequest(r, http.MethodGet, EndpointJobs+"/", nil)
+
+		assert.Equal(t, http.StatusInternalServerError, response.Code)
+	})
+}
+
+func TestJobController_GetRuns(t *testing.T) {
+	t.Run("get_runs_ok: should list the job history", func(t *testing.T) {
+		runs := []domain.JobRun{{Id: 1, JobName: "logs.retention", Status: domain.RunStatusSucceeded, DurationMs: 30}}
+
+		service := mocks.NewJobService(t)
+		service.On("GetRuns", mock.Anything, "logs.retention").Return(runs, nil).Once()
+
+		r := setUpJobRoutes(service)
+
+		response := testutil.ExecuteTestRequest(r, http.MethodGet, EndpointJobs+"/logs.retention/runs", nil)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.JSONEq(t, testutil.StringJSON(map[string]interface{}{"data": runs}), response.Body.String())
+	})
+
+	t.Run("get_runs_not_found: should return 404", func(t *testing.T) {
+		service := mocks.NewJobService(t)
+		service.On("GetRuns", mock.Anything, "unknown").Return(nil, domain.ErrJobNotFound).Once()
+
+		r := setUpJobRoutes(service)
+
+		response := testutil.ExecuteTestRequest(r, http.MethodGet, EndpointJobs+"/unknown/runs", nil)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
+func TestJobController_PauseResume(t *testing.T) {
+	t.Run("pause_ok: should return 204", func(t *testing.T) {
+		service := mocks.NewJobService(t)
+		service.On("Pause", mock.Anything, "logs.retention").Return(nil).Once()
+
+		r := setUpJobRoutes(service)
+
+		response := testutil.ExecuteTestRequest(r, http.MethodPost, EndpointJobs+"/logs.retention/pause", nil)
+
+		assert.Equal(t, http.StatusNoContent, response.Code)
+	})
+
+	t.Run("pause_not_found: should return 404", func(t *testing.T) {
+		service := mocks.NewJobService(t)
+		service.On("Pause", mock.Anything, "unknown").Return(domain.ErrJobNotFound).Once()
+
+		r := setUpJobRoutes(service)
+
+		response := testutil.ExecuteTestRequest(r, http.MethodPost, EndpointJobs+"/unknown/pause", nil)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+
+	t.Run("resume_ok: should return 204", func(t *testing.T) {
+		service := mocks.NewJobService(t)
+		service.On("Resume", mock.Anything, "logs.retention").Return(nil).Once()
+
+		r := setUpJobRoutes(service)
+
+		response := testutil.ExecuteTestRequest(r, http.MethodPost, EndpointJobs+"/logs.retention/resume", nil)
+
+		assert.Equal(t, http.StatusNoContent, response.Code)
+	})
+}
+
+func TestJobController_Trigger(t *testing.T) {
+	t.Run("trigger_ok: should return 202", func(t *testing.T) {
+		service := mocks.NewJobService(t)
+		service.On("Trigger", mock.Anything, "logs.retention").Return(nil).Once()
+
+		r := setUpJobRoutes(service)
+
+		response := testutil.ExecuteTestRequest(r, http.MethodPost, EndpointJobs+"/logs.retention/trigger", nil)
+
+		assert.Equal(t, http.StatusAccepted, response.Code)
+	})
+
+	t.Run("trigger_running: should return 409", func(t *testing.T) {
+		service := mocks.NewJobService(t)
+		service.On("Trigger", mock.Anything, "logs.retention").Return(domain.ErrJobAlreadyRunning).Once()
+
+		r := setUpJobRoutes(service)
+
+		response := testutil.ExecuteTestRequest(r, http.MethodPost, EndpointJobs+"/logs.retention/trigger", nil)
+
+		assert.Equal(t, http.StatusConflict, response.Code)
+	})
+
+	t.Run("trigger_not_found: should return 404", func(t *testing.T) {
+		service := mocks.NewJobService(t)
+		service.On("Trigger", mock.Anything, "unknown").Return(domain.ErrJobNotFound).Once()
+
+		r := setUpJobRoutes(service)
+
+		response := testutil.ExecuteTestRequest(r, http.MethodPost, EndpointJobs+"/unknown/trigger", nil)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
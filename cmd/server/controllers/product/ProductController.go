@@ -6,13 +6,18 @@ import (
 	"strconv"
 
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product/domain"
+	productType "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_type/domain"
+	seller "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/seller/domain"
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/pkg/httputil"
 
 	"github.com/gin-gonic/gin"
 )
 
+// RequestProductPost is also the body of a full replacement. Without a
+// product_code, a new product gets a generated one and a replaced product
+// keeps its own.
 type RequestProductPost struct {
-	ProductCode                    string  `json:"product_code" binding:"required"`
+	ProductCode                    string  `json:"product_code"`
 	Description                    string  `json:"description" binding:"required"`
 	Width                          float64 `json:"width" binding:"required"`
 	Height                         float64 `json:"height" binding:"required"`
@@ -26,7 +31,17 @@ type RequestProductPost struct {
 }
 
 type RequestProductPatch struct {
-	Description string `json:"description" binding:"required"`
+	ProductCode                    *string  `json:"product_code"`
+	Description                    *string  `json:"description"`
+	Width                          *float64 `json:"width"`
+	Height                         *float64 `json:"height"`
+	Length                         *float64 `json:"length"`
+	NetWeight                      *float64 `json:"net_weight"`
+	ExpirationRate                 *float64 `json:"expiration_rate"`
+	RecommendedFreezingTemperature *float64 `json:"recommended_freezing_temperature"`
+	FreezingRate                   *float64 `json:"freezing_rate"`
+	ProductTypeId                  *int64   `json:"product_type_id"`
+	SellerId                       *int64   `json:"seller_id"`
 }
 
 type ProductController struct {
@@ -94,13 +109,14 @@ func (c *ProductController) GetById() gin.HandlerFunc {
 
 // Create godoc
 // @Summary      Create product
-// @Description  create product
+// @Description  create product; the product_code is generated in the configured format when omitted
 // @Tags         Products
 // @Accept       json
 // @Produce      json
 // @Param Product body RequestProductPost true "Create product"
 // @Success      201  {object} domain.Product
 // @Failure      400  {object}  httputil.HTTPError
+// @Failure      409  {object}  httputil.HTTPError
 // @Failure      422  {object}  httputil.HTTPError
 // @Router /products [post]
 func (c *ProductController) Create() gin.HandlerFunc {
@@ -130,7 +146,7 @@ func (c *ProductController) Create() gin.HandlerFunc {
 		newProduct, err := c.service.Create(ctx.Request.Context(), &model)
 
 		if err != nil {
-			httputil.NewError(ctx, http.StatusConflict, err)
+			httputil.NewError(ctx, statusFromError(err), err)
 			return
 		}
 
@@ -138,19 +154,21 @@ func (c *ProductController) Create() gin.HandlerFunc {
 	}
 }
 
-// UpdateDescription godoc
-// @Summary      Update product fullname
-// @Description  Update product description
+// Update godoc
+// @Summary      Update product
+// @Description  Update any subset of the product fields
 // @Tags         Products
 // @Accept       json
 // @Produce      json
 // @Param id path int true "Product ID"
-// @Param Product body RequestProductPatch true "Update field"
+// @Param Product body RequestProductPatch true "Fields to update"
 // @Success      200  {object} domain.Product
 // @Failure      400  {object}  httputil.HTTPError
 // @Failure      404  {object}  httputil.HTTPError
+// @Failure      409  {object}  httputil.HTTPError
+// @Failure      422  {object}  httputil.HTTPError
 // @Router /products/{id} [patch]
-func (c *ProductController) UpdateDescription() gin.HandlerFunc {
+func (c *ProductController) Update() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 
 		id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
@@ -163,14 +181,26 @@ func (c *ProductController) UpdateDescription() gin.HandlerFunc {
 		var productDTO RequestProductPatch
 
 		if err := ctx.ShouldBindJSON(&productDTO); err != nil {
-			httputil.NewError(ctx, http.StatusBadRequest, err)
+			httputil.NewError(ctx, http.StatusUnprocessableEntity, err)
 			return
 		}
 
-		productUpdate, err := c.service.UpdateDescription(ctx.Request.Context(), id, productDTO.Description)
+		productUpdate, err := c.service.Update(ctx.Request.Context(), id, domain.ProductPatch{
+			ProductCode:                    productDTO.ProductCode,
+			Description:                    productDTO.Description,
+			Width:                          productDTO.Width,
+			Height:                         productDTO.Height,
+			Length:                         productDTO.Length,
+			NetWeight:                      productDTO.NetWeight,
+			ExpirationRate:                 productDTO.ExpirationRate,
+			RecommendedFreezingTemperature: productDTO.RecommendedFreezingTemperature,
+			FreezingRate:                   productDTO.FreezingRate,
+			ProductTypeId:                  productDTO.ProductTypeId,
+			SellerId:                       productDTO.SellerId,
+		})
 
 		if err != nil {
-			httputil.NewError(ctx, http.StatusNotFound, err)
+			httputil.NewError(ctx, statusFromError(err), err)
 			return
 		}
 
@@ -179,6 +209,62 @@ func (c *ProductController) UpdateDescription() gin.HandlerFunc {
 	}
 }
 
+// Replace godoc
+// @Summary      Replace product
+// @Description  Replace all the product fields
+// @Tags         Products
+// @Accept       json
+// @Produce      json
+// @Param id path int true "Product ID"
+// @Param Product body RequestProductPost true "Product"
+// @Success      200  {object} domain.Product
+// @Failure      400  {object}  httputil.HTTPError
+// @Failure      404  {object}  httputil.HTTPError
+// @Failure      409  {object}  httputil.HTTPError
+// @Failure      422  {object}  httputil.HTTPError
+// @Router /products/{id} [put]
+func (c *ProductController) Replace() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+
+		id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+
+		if err != nil {
+			httputil.NewError(ctx, http.StatusBadRequest, errors.New("invalid id"))
+			return
+		}
+
+		var productDTO RequestProductPost
+
+		if err := ctx.ShouldBindJSON(&productDTO); err != nil {
+			httputil.NewError(ctx, http.StatusUnprocessableEntity, errors.New("invalid input. Check the data entered"))
+			return
+		}
+
+		model := domain.Product{
+			ProductCode:                    productDTO.ProductCode,
+			Description:                    productDTO.Description,
+			Width:                          productDTO.Width,
+			Height:                         productDTO.Height,
+			Length:                         productDTO.Length,
+			NetWeight:                      productDTO.NetWeight,
+			ExpirationRate:                 productDTO.ExpirationRate,
+			RecommendedFreezingTemperature: productDTO.RecommendedFreezingTemperature,
+			FreezingRate:                   productDTO.FreezingRate,
+			ProductTypeId:                  productDTO.ProductTypeId,
+			SellerId:                       productDTO.SellerId,
+		}
+
+		product, err := c.service.Replace(ctx.Request.Context(), id, &model)
+
+		if err != nil {
+			httputil.NewError(ctx, statusFromError(err), err)
+			return
+		}
+
+		httputil.NewResponse(ctx, http.StatusOK, product)
+	}
+}
+
 // Delete godoc
 // @Summary      Delete product
 // @Description  Delete product by id
@@ -259,3 +345,24 @@ func (c *ProductController) getAllReportProductRecords(ctx *gin.Context) {
 	}
 	httputil.NewResponse(ctx, http.StatusOK, result)
 }
+
+func statusFromError(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrProductIdNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrProductCodeAlreadyExists):
+		return http.StatusConflict
+	case errors.Is(err, productType.ErrProductTypeNotFound),
+		errors.Is(err, seller.ErrIDNotFound),
+		errors.Is(err, domain.ErrInvalidProductCode),
+		errors.Is(err, domain.ErrProductCodeFormat),
+		errors.Is(err, domain.ErrInvalidDescription),
+		errors.Is(err, domain.ErrInvalidDimensions),
+		errors.Is(err, domain.ErrInvalidNetWeight),
+		errors.Is(err, domain.ErrInvalidRate),
+		errors.Is(err, domain.ErrInvalidFreezingTemperature):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
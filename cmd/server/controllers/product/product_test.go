@@ -7,10 +7,13 @@ import (
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/cmd/server/controllers/product"
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product/domain"
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product/domain/mocks"
+	productType "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_type/domain"
+	seller "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/seller/domain"
 	"net/http"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/pkg/testutil"
 )
 
@@ -86,11 +89,9 @@ func TestProductController_Create(t *testing.T) {
 
 	t.Run("create_conflict: when the product_code already exists, should return code 409", func(t *testing.T) {
 
-		expectedError := errors.New("the product code has already been registered")
-
 		mockService.
 			On("Create", context.TODO(), &bodyProduct).
-			Return(nil, expectedError).
+			Return(nil, domain.ErrProductCodeAlreadyExists).
 			Once()
 
 		requestBody, _ := json.Marshal(bodyProduct)
@@ -99,6 +100,51 @@ func TestProductController_Create(t *testing.T) {
 		assert.Equal(t, http.StatusConflict, response.Code)
 		assert.Equal(t, "{\"code\":409,\"message\":\"the product code has already been registered\"}", response.Body.String())
 	})
+
+	t.Run("create_product_type_not_found: when the product type does not exist, should return code 422", func(t *testing.T) {
+
+		mockService.
+			On("Create", mock.Anything, &bodyProduct).
+			Return(nil, productType.ErrProductTypeNotFound).
+			Once()
+
+		requestBody, _ := json.Marshal(bodyProduct)
+		response := testutil.ExecuteTestRequest(router, http.MethodPost, EndpointProduct, requestBody)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+	})
+
+	t.Run("create_code_format: when the product code does not match the configured format, should return code 422", func(t *testing.T) {
+
+		mockService.
+			On("Create", mock.Anything, &bodyProduct).
+			Return(nil, domain.ErrProductCodeFormat).
+			Once()
+
+		requestBody, _ := json.Marshal(bodyProduct)
+		response := testutil.ExecuteTestRequest(router, http.MethodPost, EndpointProduct, requestBody)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+	})
+
+	t.Run("create_generated_code: when product_code is omitted, should return the generated code with code 201", func(t *testing.T) {
+
+		body := bodyProduct
+		body.ProductCode = ""
+		generated := expectedProduct
+		generated.ProductCode = "PRD-000001"
+
+		mockService.
+			On("Create", mock.Anything, &body).
+			Return(&generated, nil).
+			Once()
+
+		requestBody, _ := json.Marshal(body)
+		response := testutil.ExecuteTestRequest(router, http.MethodPost, EndpointProduct, requestBody)
+
+		assert.Equal(t, http.StatusCreated, response.Code)
+		assert.Contains(t, response.Body.String(), "PRD-000001")
+	})
 }
 
 func TestProductController_GetAll(t *testing.T) {
@@ -194,13 +240,13 @@ func TestProductController_GetById(t *testing.T) {
 	})
 }
 
-func TestProductController_UpdateDescription(t *testing.T) {
+func TestProductController_Update(t *testing.T) {
 
 	mockService := mocks.NewProductService(t)
 	controller := controllers.CreateProductController(mockService)
 
 	router := testutil.SetUpRouter()
-	router.PATCH(EndpointProduct+"/:id", controller.UpdateDescription())
+	router.PATCH(EndpointProduct+"/:id", controller.Update())
 
 	t.Run("update_invalid_id_parse_error: when product id is not parsed, should return code 400", func(t *testing.T) {
 
@@ -210,53 +256,65 @@ func TestProductController_UpdateDescription(t *testing.T) {
 		assert.Equal(t, "{\"code\":400,\"message\":\"invalid id\"}", response.Body.String())
 	})
 
-	t.Run("update_invalid_body: when the body is invalid, should return code 400", func(t *testing.T) {
-
-		controller := controllers.CreateProductController(nil)
-		router := testutil.SetUpRouter()
-		router.PATCH(EndpointProduct+"/:id", controller.UpdateDescription())
+	t.Run("update_invalid_body: when the body is invalid, should return code 422", func(t *testing.T) {
 
 		response := testutil.ExecuteTestRequest(router, http.MethodPatch, EndpointProduct+"/1", []byte{})
 
-		assert.Equal(t, http.StatusBadRequest, response.Code)
-		assert.Equal(t, "{\"code\":400,\"message\":\"EOF\"}", response.Body.String())
+		assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+		assert.Equal(t, "{\"code\":422,\"message\":\"EOF\"}", response.Body.String())
 	})
 
-	t.Run("update_invalid_field_value: when the field is empty,should return code 400", func(t *testing.T) {
+	t.Run("update_invalid_field_value: when a dimension is not positive, should return code 422", func(t *testing.T) {
 
-		body := domain.Product{
-			Description: "",
-		}
+		width := float64(-1)
 
-		controller := controllers.CreateProductController(nil)
-		router := testutil.SetUpRouter()
-		router.PATCH(EndpointProduct+"/:id", controller.UpdateDescription())
+		mockService.
+			On("Update", mock.Anything, int64(1), domain.ProductPatch{Width: &width}).
+			Return(nil, domain.ErrInvalidDimensions).
+			Once()
 
-		requestBody, _ := json.Marshal(body)
-		response := testutil.ExecuteTestRequest(router, http.MethodPatch, EndpointProduct+"/1", requestBody)
+		response := testutil.ExecuteTestRequest(router, http.MethodPatch, EndpointProduct+"/1", []byte(`{"width": -1}`))
 
-		assert.Equal(t, http.StatusBadRequest, response.Code)
-		assert.Equal(t, "{\"code\":400,\"message\":\"Key: 'RequestProductPatch.Description' Error:Field validation for 'Description' failed on the 'required' tag\"}", response.Body.String())
+		assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+		assert.Equal(t, "{\"code\":422,\"message\":\"width, height and length must be positive\"}", response.Body.String())
 	})
 
-	t.Run("update_non_existent: when the product does not exist, should return code 404", func(t *testing.T) {
+	t.Run("update_seller_not_found: when the seller does not exist, should return code 422", func(t *testing.T) {
 
-		body := domain.Product{
-			Description: "Yogurt",
-		}
+		mockService.
+			On("Update", mock.Anything, int64(1), mock.AnythingOfType("domain.ProductPatch")).
+			Return(nil, seller.ErrIDNotFound).
+			Once()
 
-		expectedError := errors.New("the product id was not found")
+		response := testutil.ExecuteTestRequest(router, http.MethodPatch, EndpointProduct+"/1", []byte(`{"seller_id": 99}`))
+
+		assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+	})
+
+	t.Run("update_conflict: when the product_code already exists, should return code 409", func(t *testing.T) {
 
 		mockService.
-			On("UpdateDescription", context.TODO(), int64(8), body.Description).
-			Return(nil, expectedError).
+			On("Update", mock.Anything, int64(1), mock.AnythingOfType("domain.ProductPatch")).
+			Return(nil, domain.ErrProductCodeAlreadyExists).
 			Once()
 
-		requestBody, _ := json.Marshal(body)
-		response := testutil.ExecuteTestRequest(router, http.MethodPatch, EndpointProduct+"/8", requestBody)
+		response := testutil.ExecuteTestRequest(router, http.MethodPatch, EndpointProduct+"/1", []byte(`{"product_code": "PROD03"}`))
+
+		assert.Equal(t, http.StatusConflict, response.Code)
+		assert.Equal(t, "{\"code\":409,\"message\":\"the product code has already been registered\"}", response.Body.String())
+	})
+
+	t.Run("update_non_existent: when the product does not exist, should return code 404", func(t *testing.T) {
+
+		mockService.
+			On("Update", mock.Anything, int64(8), mock.AnythingOfType("domain.ProductPatch")).
+			Return(nil, domain.ErrProductIdNotFound).
+			Once()
+
+		response := testutil.ExecuteTestRequest(router, http.MethodPatch, EndpointProduct+"/8", []byte(`{"description": "Yogurt"}`))
 
 		assert.Equal(t, http.StatusNotFound, response.Code)
-		assert.Equal(t, "{\"code\":404,\"message\":\"the product id was not found\"}", response.Body.String())
+		assert.Equal(t, "{\"code\":404,\"message\":\"product id not found\"}", response.Body.String())
 	})
 
 	t.Run("update_ok: when the request is successful, should return code 200", func(t *testing.T) {
@@ -276,17 +334,14 @@ func TestProductController_UpdateDescription(t *testing.T) {
 			SellerId:                       2,
 		}
 
-		body := domain.Product{
-			Description: "Yogurt light",
-		}
+		description := "Yogurt light"
 
 		mockService.
-			On("UpdateDescription", context.TODO(), expectedProduct.Id, expectedProduct.Description).
+			On("Update", mock.Anything, expectedProduct.Id, domain.ProductPatch{Description: &description}).
 			Return(&expectedProduct, nil).
 			Once()
 
-		requestBody, _ := json.Marshal(body)
-		response := testutil.ExecuteTestRequest(router, http.MethodPatch, EndpointProduct+"/1", requestBody)
+		response := testutil.ExecuteTestRequest(router, http.MethodPatch, EndpointProduct+"/1", []byte(`{"description": "Yogurt light"}`))
 
 		assert.Equal(t, http.StatusOK, response.Code)
 
@@ -296,6 +351,49 @@ func TestProductController_UpdateDescription(t *testing.T) {
 	})
 }
 
+func TestProductController_Replace(t *testing.T) {
+
+	mockService := mocks.NewProductService(t)
+	controller := controllers.CreateProductController(mockService)
+
+	router := testutil.SetUpRouter()
+	router.PUT(EndpointProduct+"/:id", controller.Replace())
+
+	t.Run("replace_ok: when the request is successful, should return code 200", func(t *testing.T) {
+
+		mockService.
+			On("Replace", mock.Anything, int64(1), &bodyProduct).
+			Return(&expectedProduct, nil).
+			Once()
+
+		requestBody, _ := json.Marshal(bodyProduct)
+		response := testutil.ExecuteTestRequest(router, http.MethodPut, EndpointProduct+"/1", requestBody)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.JSONEq(t, testutil.StringJSON(map[string]interface{}{"data": expectedProduct}), response.Body.String())
+	})
+
+	t.Run("replace_fail: when the JSON does not contain every field, should return code 422", func(t *testing.T) {
+
+		response := testutil.ExecuteTestRequest(router, http.MethodPut, EndpointProduct+"/1", []byte(`{"description": "Yogurt"}`))
+
+		assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+	})
+
+	t.Run("replace_non_existent: when the product does not exist, should return code 404", func(t *testing.T) {
+
+		mockService.
+			On("Replace", mock.Anything, int64(8), &bodyProduct).
+			Return(nil, domain.ErrProductIdNotFound).
+			Once()
+
+		requestBody, _ := json.Marshal(bodyProduct)
+		response := testutil.ExecuteTestRequest(router, http.MethodPut, EndpointProduct+"/8", requestBody)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
 func TestProductController_Delete(t *testing.T) {
 
 	mockService := mocks.NewProductService(t)
@@ -1,11 +1,18 @@
 package controllers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	employees "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/employees/domain"
+	product "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product/domain"
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_batch/domain"
+	purchaseOrders "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/purchase_orders/domain"
+	section "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/section/domain"
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/pkg/httputil"
 )
 
@@ -20,6 +27,53 @@ type RequestProductBatchPost struct {
 	MinumumTemperature float64 `json:"minimum_temperature" binding:"required"`
 	ProductId          int64   `json:"product_id" binding:"required"`
 	SectionId          int64   `json:"section_id" binding:"required"`
+
+	CompatibilityOverride *RequestCompatibilityOverride `json:"compatibility_override"`
+}
+
+// RequestCompatibilityOverride stores a batch in a section of another product
+// type or temperature. Any caller may send it: the API has no authentication,
+// so by is whatever name the requester gives. Both fields end up in the
+// audit log.
+type RequestCompatibilityOverride struct {
+	By     string `json:"by" binding:"required"`
+	Reason string `json:"reason" binding:"required"`
+}
+
+// RequestProductBatchPatch changes the batch. A quantity change goes to the
+// stock ledger with reason and employee_id; naming purchase_order_id makes
+// it a pick for that order.
+type RequestProductBatchPatch struct {
+	CurrentQuantity    *int64   `json:"current_quantity"`
+	CurrentTemperature *float64 `json:"current_temperature"`
+	Reason             string   `json:"reason"`
+	EmployeeId         *int64   `json:"employee_id"`
+	PurchaseOrderId    *int64   `json:"purchase_order_id"`
+}
+
+type RequestProductBatchStatus struct {
+	Status string `json:"status" binding:"required"`
+	Reason string `json:"reason"`
+}
+
+type RequestSuggestSection struct {
+	ProductId          int64    `json:"product_id" binding:"required"`
+	Quantity           int64    `json:"quantity" binding:"required"`
+	WarehouseId        int64    `json:"warehouse_id"`
+	MinimumTemperature *float64 `json:"minimum_temperature"`
+}
+
+// RequestProductBatchTransfer moves the batch to section_id. Without a
+// quantity the whole batch moves; a smaller one splits it.
+type RequestProductBatchTransfer struct {
+	SectionId int64 `json:"section_id" binding:"required"`
+	// Quantity is the number of units to move. 0, or leaving it out, moves
+	// the whole batch, as does its full current quantity.
+	Quantity   int64  `json:"quantity"`
+	EmployeeId int64  `json:"employee_id" binding:"required"`
+	Reason     string `json:"reason" binding:"required"`
+
+	CompatibilityOverride *RequestCompatibilityOverride `json:"compatibility_override"`
 }
 
 type ProductBatchController struct {
@@ -33,7 +87,10 @@ func NewProductBatchController(service domain.ProductBatchService) *ProductBatch
 
 // Create godoc
 // @Summary      Create product batch
-// @Description  create product batch
+// @Description  create product batch, taking its current quantity from the free capacity of the section (409 when it does not fit); the response carries the computed shelf life and a warning when the due date disagrees with it.
+// @Description  The current quantity must be between 0 and the initial quantity (422 otherwise).
+// @Description  The product must be of the section product type and the section must get as cold as the batch minimum temperature (409 otherwise),
+// @Description  unless a compatibility_override names who overrides it and why, which is recorded in the audit log as given: the API does not authenticate who sends it.
 // @Tags         Product batches
 // @Accept       json
 // @Produce      json
@@ -76,8 +133,17 @@ func (c *ProductBatchController) Create() gin.HandlerFunc {
 			SectionId:          request.SectionId,
 		}
 
+		if override := request.CompatibilityOverride; override != nil {
+			model.Override = &domain.CompatibilityOverride{By: override.By, Reason: override.Reason}
+		}
+
 		newProductBatch, err := c.service.Create(ctx.Request.Context(), &model)
 
+		if errors.Is(err, domain.ErrInvalidOverride) || errors.Is(err, domain.ErrInvalidQuantity) {
+			httputil.NewError(ctx, http.StatusUnprocessableEntity, err)
+			return
+		}
+
 		if err != nil {
 			httputil.NewError(ctx, http.StatusConflict, err)
 			return
@@ -86,3 +152,443 @@ func (c *ProductBatchController) Create() gin.HandlerFunc {
 		httputil.NewResponse(ctx, http.StatusCreated, newProductBatch)
 	}
 }
+
+// GetById godoc
+// @Summary      Get product batch by ID
+// @Description  Get a product batch with its computed shelf life
+// @Tags         Product batches
+// @Produce      json
+// @Param id path int true "Product batch ID"
+// @Success      200  {object}  domain.ProductBatch
+// @Failure      400  {object}  httputil.HTTPError
+// @Failure      404  {object}  httputil.HTTPError
+// @Router /productBatches/{id} [get]
+func (c *ProductBatchController) GetById() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+		if err != nil {
+			httputil.NewError(ctx, http.StatusBadRequest, errors.New("invalid id"))
+			return
+		}
+
+		productBatch, err := c.service.GetById(ctx.Request.Context(), id)
+		if err != nil {
+			httputil.NewError(ctx, statusFromError(err), err)
+			return
+		}
+
+		httputil.NewResponse(ctx, http.StatusOK, productBatch)
+	}
+}
+
+// GetAll godoc
+// @Summary      List product batches
+// @Description  List product batches, optionally filtered. Bounds are inclusive; due dates are YYYY-MM-DD.
+// @Tags         Product batches
+// @Produce      json
+// @Param status query string false "active, quarantined or discarded"
+// @Param product_id query int false "Product ID"
+// @Param section_id query int false "Section ID"
+// @Param warehouse_id query int false "Warehouse ID"
+// @Param due_date_from query string false "Earliest due date"
+// @Param due_date_to query string false "Latest due date"
+// @Param min_temperature query number false "Minimum current temperature"
+// @Param max_temperature query number false "Maximum current temperature"
+// @Param min_quantity query int false "Minimum current quantity"
+// @Param max_quantity query int false "Maximum current quantity"
+// @Success      200  {array}   domain.ProductBatch
+// @Failure      400  {object}  httputil.HTTPError
+// @Failure      500  {object}  httputil.HTTPError
+// @Router /productBatches [get]
+func (c *ProductBatchController) GetAll() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		filter, err := parseFilter(ctx)
+		if err != nil {
+			httputil.NewError(ctx, http.StatusBadRequest, err)
+			return
+		}
+
+		productBatches, err := c.service.GetAll(ctx.Request.Context(), filter)
+		if err != nil {
+			httputil.NewError(ctx, statusFromError(err), err)
+			return
+		}
+
+		httputil.NewResponse(ctx, http.StatusOK, productBatches)
+	}
+}
+
+// Update godoc
+// @Summary      Update product batch
+// @Description  Update the current quantity and/or current temperature of a product batch.
+// @Description  The section capacity follows the quantity; a 409 states the free space when it does not fit.
+// @Description  A quantity change is recorded in the stock ledger as an adjustment, or as a pick when it names the
+// @Description  purchase order it is for; a pick must lower the quantity of an active batch.
+// @Tags         Product batches
+// @Accept       json
+// @Produce      json
+// @Param id path int true "Product batch ID"
+// @Param ProductBatch body RequestProductBatchPatch true "Update product batch"
+// @Success      200  {object}  domain.ProductBatch
+// @Failure      400  {object}  httputil.HTTPError
+// @Failure      404  {object}  httputil.HTTPError
+// @Failure      422  {object}  httputil.HTTPError
+// @Router /productBatches/{id} [patch]
+func (c *ProductBatchController) Update() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+		if err != nil {
+			httputil.NewError(ctx, http.StatusBadRequest, errors.New("invalid id"))
+			return
+		}
+
+		var request RequestProductBatchPatch
+
+		if err := ctx.ShouldBindJSON(&request); err != nil {
+			httputil.NewError(ctx, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		productBatch, err := c.service.Update(ctx.Request.Context(), id, domain.ProductBatchPatch{
+			CurrentQuantity:    request.CurrentQuantity,
+			CurrentTemperature: request.CurrentTemperature,
+			Reason:             request.Reason,
+			EmployeeId:         request.EmployeeId,
+			PurchaseOrderId:    request.PurchaseOrderId,
+		})
+		if err != nil {
+			httputil.NewError(ctx, statusFromError(err), err)
+			return
+		}
+
+		httputil.NewResponse(ctx, http.StatusOK, productBatch)
+	}
+}
+
+// Delete godoc
+// @Summary      Delete product batch
+// @Description  Delete a product batch written by mistake, with its receipt, freeing its space in the section. Batches that inbound orders reference or with other stock movements have to be discarded
+// @Tags         Product batches
+// @Param id path int true "Product batch ID"
+// @Success      204
+// @Failure      400  {object}  httputil.HTTPError
+// @Failure      404  {object}  httputil.HTTPError
+// @Failure      409  {object}  httputil.HTTPError
+// @Router /productBatches/{id} [delete]
+func (c *ProductBatchController) Delete() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+		if err != nil {
+			httputil.NewError(ctx, http.StatusBadRequest, errors.New("invalid id"))
+			return
+		}
+
+		if err := c.service.Delete(ctx.Request.Context(), id); err != nil {
+			httputil.NewError(ctx, statusFromError(err), err)
+			return
+		}
+
+		httputil.NewResponse(ctx, http.StatusNoContent, nil)
+	}
+}
+
+// UpdateStatus godoc
+// @Summary      Change product batch status
+// @Description  Quarantine, release or discard a product batch. An active batch can be quarantined or discarded,
+// @Description  a quarantined batch released (active) or discarded, and a discarded batch, whose current quantity drops to 0, is final.
+// @Tags         Product batches
+// @Accept       json
+// @Produce      json
+// @Param id path int true "Product batch ID"
+// @Param Status body RequestProductBatchStatus true "New status"
+// @Success      200  {object}  domain.ProductBatch
+// @Failure      400  {object}  httputil.HTTPError
+// @Failure      404  {object}  httputil.HTTPError
+// @Failure      409  {object}  httputil.HTTPError
+// @Failure      422  {object}  httputil.HTTPError
+// @Router /productBatches/{id}/status [put]
+func (c *ProductBatchController) UpdateStatus() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+		if err != nil {
+			httputil.NewError(ctx, http.StatusBadRequest, errors.New("invalid id"))
+			return
+		}
+
+		var request RequestProductBatchStatus
+
+		if err := ctx.ShouldBindJSON(&request); err != nil {
+			httputil.NewError(ctx, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		productBatch, err := c.service.UpdateStatus(ctx.Request.Context(), id, request.Status, request.Reason)
+		if err != nil {
+			httputil.NewError(ctx, statusFromError(err), err)
+			return
+		}
+
+		httputil.NewResponse(ctx, http.StatusOK, productBatch)
+	}
+}
+
+// GetExpiringReport godoc
+// @Summary      Expiring product batches
+// @Description  Active batches with stock due within the next days (already expired ones included),
+// @Description  grouped by warehouse and section with quantities and value at the current purchase price.
+// @Tags         Product batches
+// @Produce      json
+// @Param days query int false "Days ahead, 1 to 365 (default 7)"
+// @Success      200  {object}  domain.ExpiringReport
+// @Failure      400  {object}  httputil.HTTPError
+// @Failure      500  {object}  httputil.HTTPError
+// @Router /productBatches/expiring [get]
+func (c *ProductBatchController) GetExpiringReport() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		days := domain.DefaultExpiringDays
+
+		if value := ctx.Query("days"); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil {
+				httputil.NewError(ctx, http.StatusBadRequest, domain.ErrInvalidDays)
+				return
+			}
+			days = parsed
+		}
+
+		report, err := c.service.GetExpiringReport(ctx.Request.Context(), days)
+		if err != nil {
+			httputil.NewError(ctx, statusFromError(err), err)
+			return
+		}
+
+		httputil.NewResponse(ctx, http.StatusOK, report)
+	}
+}
+
+// SuggestSection godoc
+// @Summary      Suggest a section for a new batch
+// @Description  Rank the sections, of one warehouse or of all, a new batch could be put away in, by product type match,
+// @Description  temperature compatibility, free capacity and consolidation with stock of the same product, with the
+// @Description  reasons for each. Sections the batch cannot go to are listed last as not eligible.
+// @Description  The minimum temperature defaults to the recommended freezing temperature of the product.
+// @Tags         Product batches
+// @Accept       json
+// @Produce      json
+// @Param Request body RequestSuggestSection true "Incoming batch"
+// @Success      200  {object}  domain.PutAwaySuggestion
+// @Failure      404  {object}  httputil.HTTPError
+// @Failure      422  {object}  httputil.HTTPError
+// @Router /productBatches/suggestSection [post]
+func (c *ProductBatchController) SuggestSection() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var request RequestSuggestSection
+
+		if err := ctx.ShouldBindJSON(&request); err != nil {
+			httputil.NewError(ctx, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		suggestion, err := c.service.SuggestSection(ctx.Request.Context(), domain.PutAwayRequest{
+			ProductId:          request.ProductId,
+			Quantity:           request.Quantity,
+			WarehouseId:        request.WarehouseId,
+			MinimumTemperature: request.MinimumTemperature,
+		})
+		if err != nil {
+			httputil.NewError(ctx, statusFromError(err), err)
+			return
+		}
+
+		httputil.NewResponse(ctx, http.StatusOK, suggestion)
+	}
+}
+
+// Transfer godoc
+// @Summary      Transfer product batch to another section
+// @Description  Move the whole batch, or split quantity units off into a new batch, to another section of any warehouse.
+// @Description  A quantity of 0 or none moves the whole batch; a batch without stock cannot be transferred (422).
+// @Description  Both section capacities change atomically (409 when the target has no room) and the stock ledger records
+// @Description  the units leaving and entering with the employee, reason and time. The target section must be compatible
+// @Description  with the batch as on create (409 otherwise) unless a compatibility_override names who overrides it and why.
+// @Tags         Product batches
+// @Accept       json
+// @Produce      json
+// @Param id path int true "Product batch ID"
+// @Param Transfer body RequestProductBatchTransfer true "Target section and quantity"
+// @Success      200  {object}  domain.TransferResult
+// @Failure      400  {object}  httputil.HTTPError
+// @Failure      404  {object}  httputil.HTTPError
+// @Failure      409  {object}  httputil.HTTPError
+// @Failure      422  {object}  httputil.HTTPError
+// @Router /productBatches/{id}/transfer [post]
+func (c *ProductBatchController) Transfer() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+		if err != nil {
+			httputil.NewError(ctx, http.StatusBadRequest, errors.New("invalid id"))
+			return
+		}
+
+		var request RequestProductBatchTransfer
+
+		if err := ctx.ShouldBindJSON(&request); err != nil {
+			httputil.NewError(ctx, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		transfer := domain.Transfer{
+			ProductBatchId: id,
+			ToSectionId:    request.SectionId,
+			Quantity:       request.Quantity,
+			EmployeeId:     request.EmployeeId,
+			Reason:         request.Reason,
+		}
+
+		if override := request.CompatibilityOverride; override != nil {
+			transfer.Override = &domain.CompatibilityOverride{By: override.By, Reason: override.Reason}
+		}
+
+		result, err := c.service.Transfer(ctx.Request.Context(), transfer)
+		if err != nil {
+			httputil.NewError(ctx, statusFromError(err), err)
+			return
+		}
+
+		httputil.NewResponse(ctx, http.StatusOK, result)
+	}
+}
+
+// GetMovements godoc
+// @Summary      Product batch stock movements
+// @Description  The stock ledger of a product batch: its receipt, transfers, adjustments, picks and disposal, oldest
+// @Description  first, with signed quantities and the document behind each. The movements add up to ledger_quantity,
+// @Description  and consistent tells whether that matches the current quantity.
+// @Tags         Product batches
+// @Produce      json
+// @Param id path int true "Product batch ID"
+// @Success      200  {object}  domain.StockLedger
+// @Failure      400  {object}  httputil.HTTPError
+// @Failure      404  {object}  httputil.HTTPError
+// @Router /productBatches/{id}/movements [get]
+func (c *ProductBatchController) GetMovements() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+		if err != nil {
+			httputil.NewError(ctx, http.StatusBadRequest, errors.New("invalid id"))
+			return
+		}
+
+		ledger, err := c.service.GetMovements(ctx.Request.Context(), id)
+		if err != nil {
+			httputil.NewError(ctx, statusFromError(err), err)
+			return
+		}
+
+		httputil.NewResponse(ctx, http.StatusOK, ledger)
+	}
+}
+
+func parseFilter(ctx *gin.Context) (domain.ProductBatchFilter, error) {
+	var filter domain.ProductBatchFilter
+
+	ids := map[string]*int64{
+		"product_id":   &filter.ProductId,
+		"section_id":   &filter.SectionId,
+		"warehouse_id": &filter.WarehouseId,
+	}
+	for name, target := range ids {
+		if value := ctx.Query(name); value != "" {
+			id, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || id <= 0 {
+				return filter, fmt.Errorf("invalid %s %q", name, value)
+			}
+			*target = id
+		}
+	}
+
+	if value := ctx.Query("status"); value != "" {
+		if !domain.IsStatus(value) {
+			return filter, domain.ErrInvalidStatus
+		}
+		filter.Status = value
+	}
+
+	dates := map[string]**time.Time{
+		"due_date_from": &filter.DueDateFrom,
+		"due_date_to":   &filter.DueDateTo,
+	}
+	for name, target := range dates {
+		if value := ctx.Query(name); value != "" {
+			date, err := time.Parse("2006-01-02", value)
+			if err != nil {
+				return filter, fmt.Errorf("invalid %s %q: use YYYY-MM-DD", name, value)
+			}
+			if name == "due_date_to" {
+				date = date.Add(24*time.Hour - time.Microsecond)
+			}
+			*target = &date
+		}
+	}
+
+	temperatures := map[string]**float64{
+		"min_temperature": &filter.MinTemperature,
+		"max_temperature": &filter.MaxTemperature,
+	}
+	for name, target := range temperatures {
+		if value := ctx.Query(name); value != "" {
+			temperature, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return filter, fmt.Errorf("invalid %s %q", name, value)
+			}
+			*target = &temperature
+		}
+	}
+
+	quantities := map[string]**int64{
+		"min_quantity": &filter.MinQuantity,
+		"max_quantity": &filter.MaxQuantity,
+	}
+	for name, target := range quantities {
+		if value := ctx.Query(name); value != "" {
+			quantity, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return filter, fmt.Errorf("invalid %s %q", name, value)
+			}
+			*target = &quantity
+		}
+	}
+
+	return filter, nil
+}
+
+func statusFromError(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrProductBatchNotFound),
+		errors.Is(err, product.ErrProductIdNotFound),
+		errors.Is(err, section.ErrSectionNotFound),
+		errors.Is(err, employees.ErrEmployeeNotFound),
+		errors.Is(err, purchaseOrders.ErrIDNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrProductBatchInUse),
+		errors.Is(err, domain.ErrProductBatchHasHistory),
+		errors.Is(err, domain.ErrCapacityExceeded),
+		errors.Is(err, domain.ErrIncompatibleSection),
+		errors.Is(err, domain.ErrSameSection),
+		errors.Is(err, section.ErrSectionArchived),
+		errors.Is(err, domain.ErrStatusTransition),
+		errors.Is(err, domain.ErrProductBatchDiscarded):
+		return http.StatusConflict
+	case errors.Is(err, domain.ErrInvalidDateRange), errors.Is(err, domain.ErrInvalidDays):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrInvalidQuantity),
+		errors.Is(err, domain.ErrInvalidStatus),
+		errors.Is(err, domain.ErrInvalidOverride),
+		errors.Is(err, domain.ErrTransferQuantity),
+		errors.Is(err, domain.ErrTransferReason),
+		errors.Is(err, domain.ErrInvalidPick):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
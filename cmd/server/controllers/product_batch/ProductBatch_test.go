@@ -5,13 +5,17 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/go-playground/assert/v2"
+	"github.com/stretchr/testify/mock"
 	controllers "github.com/vinigracindo/mercado-fresco-stranger-strings/cmd/server/controllers/product_batch"
+	employees "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/employees/domain"
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_batch/domain"
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_batch/domain/mocks"
+	purchaseOrders "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/purchase_orders/domain"
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/pkg/testutil"
 )
 
@@ -112,3 +116,507 @@ func TestProductBatch_Create(t *testing.T) {
 	})
 
 }
+
+func TestProductBatch_Create_Compatibility(t *testing.T) {
+	mockService := mocks.NewProductBatchService(t)
+	controller := controllers.NewProductBatchController(mockService)
+	router := testutil.SetUpRouter()
+	router.POST(EndpointProductBatch, controller.Create())
+
+	t.Run("create_override: should pass the override on and return code 201", func(t *testing.T) {
+		expectedProductBatch := makeProductBatch()
+		expectedProductBatch.Override = &domain.CompatibilityOverride{By: "maria", Reason: "freezer under repair"}
+
+		mockService.
+			On("Create", mock.Anything, &expectedProductBatch).
+			Return(&expectedProductBatch, nil).
+			Once()
+
+		request := makeProductBatchRequest()
+		request.CompatibilityOverride = &controllers.RequestCompatibilityOverride{By: "maria", Reason: "freezer under repair"}
+		reqBody, _ := json.Marshal(request)
+		response := testutil.ExecuteTestRequest(router, http.MethodPost, EndpointProductBatch, reqBody)
+
+		assert.Equal(t, http.StatusCreated, response.Code)
+	})
+
+	t.Run("create_incompatible: should return code 409", func(t *testing.T) {
+		mockService.
+			On("Create", mock.Anything, mock.Anything).
+			Return(nil, fmt.Errorf("%w: product type 1 does not match section product type 3", domain.ErrIncompatibleSection)).
+			Once()
+
+		reqBody, _ := json.Marshal(makeProductBatchRequest())
+		response := testutil.ExecuteTestRequest(router, http.MethodPost, EndpointProductBatch, reqBody)
+
+		assert.Equal(t, http.StatusConflict, response.Code)
+	})
+
+	t.Run("create_invalid_quantity: should return code 422 for a negative or over-initial current quantity", func(t *testing.T) {
+		for _, quantity := range []int64{-1, 2} {
+			expectedProductBatch := makeProductBatch()
+			expectedProductBatch.CurrentQuantity = quantity
+
+			mockService.
+				On("Create", mock.Anything, &expectedProductBatch).
+				Return(nil, domain.ErrInvalidQuantity).
+				Once()
+
+			request := makeProductBatchRequest()
+			request.CurrentQuantity = quantity
+			reqBody, _ := json.Marshal(request)
+			response := testutil.ExecuteTestRequest(router, http.MethodPost, EndpointProductBatch, reqBody)
+
+			assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+		}
+	})
+
+	t.Run("create_invalid_override: should return code 422", func(t *testing.T) {
+		mockService.
+			On("Create", mock.Anything, mock.Anything).
+			Return(nil, domain.ErrInvalidOverride).
+			Once()
+
+		reqBody, _ := json.Marshal(makeProductBatchRequest())
+		response := testutil.ExecuteTestRequest(router, http.MethodPost, EndpointProductBatch, reqBody)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+	})
+}
+
+func TestProductBatch_GetById(t *testing.T) {
+	expectedProductBatch := makeProductBatch()
+
+	mockService := mocks.NewProductBatchService(t)
+	controller := controllers.NewProductBatchController(mockService)
+	router := testutil.SetUpRouter()
+	router.GET(EndpointProductBatch+"/:id", controller.GetById())
+
+	t.Run("get_by_id_ok: should return code 200 and the batch", func(t *testing.T) {
+		mockService.On("GetById", mock.Anything, int64(1)).Return(&expectedProductBatch, nil).Once()
+
+		response := testutil.ExecuteTestRequest(router, http.MethodGet, EndpointProductBatch+"/1", nil)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+
+	t.Run("get_by_id_not_found: should return code 404", func(t *testing.T) {
+		mockService.On("GetById", mock.Anything, int64(2)).Return(nil, domain.ErrProductBatchNotFound).Once()
+
+		response := testutil.ExecuteTestRequest(router, http.MethodGet, EndpointProductBatch+"/2", nil)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+
+	t.Run("get_by_id_invalid_id: should return code 400", func(t *testing.T) {
+		response := testutil.ExecuteTestRequest(router, http.MethodGet, EndpointProductBatch+"/abc", nil)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+}
+
+func TestProductBatch_GetAll(t *testing.T) {
+	expectedProductBatch := makeProductBatch()
+
+	mockService := mocks.NewProductBatchService(t)
+	controller := controllers.NewProductBatchController(mockService)
+	router := testutil.SetUpRouter()
+	router.GET(EndpointProductBatch, controller.GetAll())
+
+	t.Run("get_all_filtered: should pass the query filters to the service", func(t *testing.T) {
+		minTemperature := -20.0
+		maxQuantity := int64(50)
+		dueDateTo := time.Date(2022, time.January, 31, 0, 0, 0, 0, time.UTC).Add(24*time.Hour - time.Microsecond)
+		filter := domain.ProductBatchFilter{
+			WarehouseId:    2,
+			DueDateTo:      &dueDateTo,
+			MinTemperature: &minTemperature,
+			MaxQuantity:    &maxQuantity,
+		}
+
+		mockService.
+			On("GetAll", mock.Anything, filter).
+			Return([]domain.ProductBatch{expectedProductBatch}, nil).
+			Once()
+
+		response := testutil.ExecuteTestRequest(router, http.MethodGet,
+			EndpointProductBatch+"?warehouse_id=2&due_date_to=2022-01-31&min_temperature=-20&max_quantity=50", nil)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+
+	t.Run("get_all_invalid_filter: should return code 400", func(t *testing.T) {
+		response := testutil.ExecuteTestRequest(router, http.MethodGet, EndpointProductBatch+"?due_date_from=yesterday", nil)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("get_all_invalid_date_range: should return code 400", func(t *testing.T) {
+		mockService.On("GetAll", mock.Anything, mock.Anything).Return(nil, domain.ErrInvalidDateRange).Once()
+
+		response := testutil.ExecuteTestRequest(router, http.MethodGet,
+			EndpointProductBatch+"?due_date_from=2022-02-01&due_date_to=2022-01-01", nil)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+}
+
+func TestProductBatch_Update(t *testing.T) {
+	expectedProductBatch := makeProductBatch()
+
+	mockService := mocks.NewProductBatchService(t)
+	controller := controllers.NewProductBatchController(mockService)
+	router := testutil.SetUpRouter()
+	router.PATCH(EndpointProductBatch+"/:id", controller.Update())
+
+	quantity := int64(5)
+
+	t.Run("update_ok: should return code 200", func(t *testing.T) {
+		mockService.
+			On("Update", mock.Anything, int64(1), domain.ProductBatchPatch{CurrentQuantity: &quantity}).
+			Return(&expectedProductBatch, nil).
+			Once()
+
+		response := testutil.ExecuteTestRequest(router, http.MethodPatch, EndpointProductBatch+"/1", []byte(`{"current_quantity": 5}`))
+
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+
+	t.Run("update_invalid_quantity: should return code 422", func(t *testing.T) {
+		mockService.
+			On("Update", mock.Anything, int64(1), domain.ProductBatchPatch{CurrentQuantity: &quantity}).
+			Return(nil, domain.ErrInvalidQuantity).
+			Once()
+
+		response := testutil.ExecuteTestRequest(router, http.MethodPatch, EndpointProductBatch+"/1", []byte(`{"current_quantity": 5}`))
+
+		assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+	})
+
+	t.Run("update_capacity_exceeded: should return code 409 with the free space", func(t *testing.T) {
+		mockService.
+			On("Update", mock.Anything, int64(1), domain.ProductBatchPatch{CurrentQuantity: &quantity}).
+			Return(nil, domain.NewCapacityError(1, 4, 2)).
+			Once()
+
+		response := testutil.ExecuteTestRequest(router, http.MethodPatch, EndpointProductBatch+"/1", []byte(`{"current_quantity": 5}`))
+
+		assert.Equal(t, http.StatusConflict, response.Code)
+		assert.Equal(t, true, strings.Contains(response.Body.String(), "room for 2 more units"))
+	})
+
+	t.Run("update_not_found: should return code 404", func(t *testing.T) {
+		mockService.
+			On("Update", mock.Anything, int64(2), mock.Anything).
+			Return(nil, domain.ErrProductBatchNotFound).
+			Once()
+
+		response := testutil.ExecuteTestRequest(router, http.MethodPatch, EndpointProductBatch+"/2", []byte(`{"current_temperature": -4}`))
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+
+	t.Run("update_pick: should pass the purchase order on and return code 200", func(t *testing.T) {
+		employeeId := int64(7)
+		purchaseOrderId := int64(9)
+
+		mockService.
+			On("Update", mock.Anything, int64(1), domain.ProductBatchPatch{
+				CurrentQuantity: &quantity,
+				Reason:          "order 9",
+				EmployeeId:      &employeeId,
+				PurchaseOrderId: &purchaseOrderId,
+			}).
+			Return(&expectedProductBatch, nil).
+			Once()
+
+		response := testutil.ExecuteTestRequest(router, http.MethodPatch, EndpointProductBatch+"/1",
+			[]byte(`{"current_quantity": 5, "reason": "order 9", "employee_id": 7, "purchase_order_id": 9}`))
+
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+
+	t.Run("update_pick_purchase_order_not_found: should return code 404", func(t *testing.T) {
+		mockService.
+			On("Update", mock.Anything, int64(1), mock.Anything).
+			Return(nil, purchaseOrders.ErrIDNotFound).
+			Once()
+
+		response := testutil.ExecuteTestRequest(router, http.MethodPatch, EndpointProductBatch+"/1",
+			[]byte(`{"current_quantity": 5, "purchase_order_id": 99}`))
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+
+	t.Run("update_invalid_pick: should return code 422", func(t *testing.T) {
+		mockService.
+			On("Update", mock.Anything, int64(1), mock.Anything).
+			Return(nil, domain.ErrInvalidPick).
+			Once()
+
+		response := testutil.ExecuteTestRequest(router, http.MethodPatch, EndpointProductBatch+"/1",
+			[]byte(`{"current_quantity": 5, "purchase_order_id": 9}`))
+
+		assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+	})
+}
+
+func TestProductBatch_GetMovements(t *testing.T) {
+	mockService := mocks.NewProductBatchService(t)
+	controller := controllers.NewProductBatchController(mockService)
+	router := testutil.SetUpRouter()
+	router.GET(EndpointProductBatch+"/:id/movements", controller.GetMovements())
+
+	t.Run("get_movements_ok: should return code 200 with the ledger", func(t *testing.T) {
+		mockService.
+			On("GetMovements", mock.Anything, int64(1)).
+			Return(&domain.StockLedger{
+				ProductBatchId:  1,
+				CurrentQuantity: 10,
+				LedgerQuantity:  10,
+				Consistent:      true,
+				Movements:       []domain.StockMovement{{Id: 1, ProductBatchId: 1, Type: domain.MovementReceipt, Quantity: 10}},
+			}, nil).
+			Once()
+
+		response := testutil.ExecuteTestRequest(router, http.MethodGet, EndpointProductBatch+"/1/movements", nil)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, true, strings.Contains(response.Body.String(), `"movement_type":"receipt"`))
+	})
+
+	t.Run("get_movements_not_found: should return code 404", func(t *testing.T) {
+		mockService.
+			On("GetMovements", mock.Anything, int64(2)).
+			Return(nil, domain.ErrProductBatchNotFound).
+			Once()
+
+		response := testutil.ExecuteTestRequest(router, http.MethodGet, EndpointProductBatch+"/2/movements", nil)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+
+	t.Run("get_movements_invalid_id: should return code 400", func(t *testing.T) {
+		response := testutil.ExecuteTestRequest(router, http.MethodGet, EndpointProductBatch+"/x/movements", nil)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+}
+
+func TestProductBatch_Delete(t *testing.T) {
+	mockService := mocks.NewProductBatchService(t)
+	controller := controllers.NewProductBatchController(mockService)
+	router := testutil.SetUpRouter()
+	router.DELETE(EndpointProductBatch+"/:id", controller.Delete())
+
+	t.Run("delete_ok: should return code 204", func(t *testing.T) {
+		mockService.On("Delete", mock.Anything, int64(1)).Return(nil).Once()
+
+		response := testutil.ExecuteTestRequest(router, http.MethodDelete, EndpointProductBatch+"/1", nil)
+
+		assert.Equal(t, http.StatusNoContent, response.Code)
+	})
+
+	t.Run("delete_in_use: should return code 409", func(t *testing.T) {
+		mockService.On("Delete", mock.Anything, int64(1)).Return(domain.ErrProductBatchInUse).Once()
+
+		response := testutil.ExecuteTestRequest(router, http.MethodDelete, EndpointProductBatch+"/1", nil)
+
+		assert.Equal(t, http.StatusConflict, response.Code)
+	})
+
+	t.Run("delete_has_history: should return code 409 when the batch has stock movements", func(t *testing.T) {
+		mockService.On("Delete", mock.Anything, int64(1)).Return(domain.ErrProductBatchHasHistory).Once()
+
+		response := testutil.ExecuteTestRequest(router, http.MethodDelete, EndpointProductBatch+"/1", nil)
+
+		assert.Equal(t, http.StatusConflict, response.Code)
+	})
+}
+
+func TestProductBatch_UpdateStatus(t *testing.T) {
+	expectedProductBatch := makeProductBatch()
+
+	mockService := mocks.NewProductBatchService(t)
+	controller := controllers.NewProductBatchController(mockService)
+	router := testutil.SetUpRouter()
+	router.PUT(EndpointProductBatch+"/:id/status", controller.UpdateStatus())
+
+	t.Run("update_status_ok: should return code 200", func(t *testing.T) {
+		mockService.
+			On("UpdateStatus", mock.Anything, int64(1), domain.StatusQuarantined, "cold chain broken").
+			Return(&expectedProductBatch, nil).
+			Once()
+
+		response := testutil.ExecuteTestRequest(router, http.MethodPut, EndpointProductBatch+"/1/status",
+			[]byte(`{"status": "quarantined", "reason": "cold chain broken"}`))
+
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+
+	t.Run("update_status_transition: should return code 409", func(t *testing.T) {
+		mockService.
+			On("UpdateStatus", mock.Anything, int64(1), domain.StatusActive, "").
+			Return(nil, fmt.Errorf("%w: discarded to active", domain.ErrStatusTransition)).
+			Once()
+
+		response := testutil.ExecuteTestRequest(router, http.MethodPut, EndpointProductBatch+"/1/status", []byte(`{"status": "active"}`))
+
+		assert.Equal(t, http.StatusConflict, response.Code)
+	})
+
+	t.Run("update_status_missing: should return code 422 without a status", func(t *testing.T) {
+		response := testutil.ExecuteTestRequest(router, http.MethodPut, EndpointProductBatch+"/1/status", []byte(`{}`))
+
+		assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+	})
+}
+
+func TestProductBatch_GetExpiringReport(t *testing.T) {
+	mockService := mocks.NewProductBatchService(t)
+	controller := controllers.NewProductBatchController(mockService)
+	router := testutil.SetUpRouter()
+	router.GET(EndpointProductBatch+"/expiring", controller.GetExpiringReport())
+
+	t.Run("get_expiring_report_default: should use 7 days", func(t *testing.T) {
+		mockService.
+			On("GetExpiringReport", mock.Anything, domain.DefaultExpiringDays).
+			Return(&domain.ExpiringReport{Days: domain.DefaultExpiringDays}, nil).
+			Once()
+
+		response := testutil.ExecuteTestRequest(router, http.MethodGet, EndpointProductBatch+"/expiring", nil)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+
+	t.Run("get_expiring_report_invalid_days: should return code 400", func(t *testing.T) {
+		mockService.On("GetExpiringReport", mock.Anything, 400).Return(nil, domain.ErrInvalidDays).Once()
+
+		response := testutil.ExecuteTestRequest(router, http.MethodGet, EndpointProductBatch+"/expiring?days=400", nil)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+}
+
+func TestProductBatch_SuggestSection(t *testing.T) {
+	mockService := mocks.NewProductBatchService(t)
+	controller := controllers.NewProductBatchController(mockService)
+	router := testutil.SetUpRouter()
+	router.POST(EndpointProductBatch+"/suggestSection", controller.SuggestSection())
+
+	t.Run("suggest_section_ok: should return code 200 with the ranked candidates", func(t *testing.T) {
+		minimumTemperature := -20.0
+		suggestion := &domain.PutAwaySuggestion{
+			ProductId:          1,
+			Quantity:           50,
+			MinimumTemperature: minimumTemperature,
+			Candidates:         []domain.SectionCandidate{{SectionId: 2, Eligible: true, Score: 100, Reasons: []string{"stores product type 1"}}},
+		}
+
+		mockService.
+			On("SuggestSection", mock.Anything, domain.PutAwayRequest{
+				ProductId:          1,
+				Quantity:           50,
+				WarehouseId:        3,
+				MinimumTemperature: &minimumTemperature,
+			}).
+			Return(suggestion, nil).
+			Once()
+
+		body := []byte(`{"product_id": 1, "quantity": 50, "warehouse_id": 3, "minimum_temperature": -20}`)
+		response := testutil.ExecuteTestRequest(router, http.MethodPost, EndpointProductBatch+"/suggestSection", body)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, testutil.StringJSON(map[string]interface{}{"data": suggestion}), response.Body.String())
+	})
+
+	t.Run("suggest_section_missing_quantity: should return code 422", func(t *testing.T) {
+		response := testutil.ExecuteTestRequest(router, http.MethodPost, EndpointProductBatch+"/suggestSection", []byte(`{"product_id": 1}`))
+
+		assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+	})
+
+	t.Run("suggest_section_invalid_quantity: should return code 422", func(t *testing.T) {
+		mockService.On("SuggestSection", mock.Anything, mock.Anything).Return(nil, domain.ErrInvalidQuantity).Once()
+
+		body := []byte(`{"product_id": 1, "quantity": -5}`)
+		response := testutil.ExecuteTestRequest(router, http.MethodPost, EndpointProductBatch+"/suggestSection", body)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+	})
+}
+
+func TestProductBatch_Transfer(t *testing.T) {
+	source := makeProductBatch()
+	target := makeProductBatch()
+	target.Id = 2
+	target.SectionId = 2
+
+	mockService := mocks.NewProductBatchService(t)
+	controller := controllers.NewProductBatchController(mockService)
+	router := testutil.SetUpRouter()
+	router.POST(EndpointProductBatch+"/:id/transfer", controller.Transfer())
+
+	t.Run("transfer_ok: should pass the transfer on and return code 200", func(t *testing.T) {
+		mockService.
+			On("Transfer", mock.Anything, domain.Transfer{
+				ProductBatchId: 1,
+				ToSectionId:    2,
+				Quantity:       4,
+				EmployeeId:     7,
+				Reason:         "rebalance",
+				Override:       &domain.CompatibilityOverride{By: "maria", Reason: "freezer under repair"},
+			}).
+			Return(&domain.TransferResult{Source: &source, Target: &target, Split: true}, nil).
+			Once()
+
+		response := testutil.ExecuteTestRequest(router, http.MethodPost, EndpointProductBatch+"/1/transfer", []byte(`{
+			"section_id": 2, "quantity": 4, "employee_id": 7, "reason": "rebalance",
+			"compatibility_override": {"by": "maria", "reason": "freezer under repair"}
+		}`))
+
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+
+	t.Run("transfer_capacity_exceeded: should return code 409", func(t *testing.T) {
+		mockService.
+			On("Transfer", mock.Anything, mock.Anything).
+			Return(nil, domain.NewCapacityError(2, 4, 1)).
+			Once()
+
+		response := testutil.ExecuteTestRequest(router, http.MethodPost, EndpointProductBatch+"/1/transfer",
+			[]byte(`{"section_id": 2, "employee_id": 7, "reason": "rebalance"}`))
+
+		assert.Equal(t, http.StatusConflict, response.Code)
+	})
+
+	t.Run("transfer_employee_not_found: should return code 404", func(t *testing.T) {
+		mockService.
+			On("Transfer", mock.Anything, mock.Anything).
+			Return(nil, employees.ErrEmployeeNotFound).
+			Once()
+
+		response := testutil.ExecuteTestRequest(router, http.MethodPost, EndpointProductBatch+"/1/transfer",
+			[]byte(`{"section_id": 2, "employee_id": 9, "reason": "rebalance"}`))
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+
+	t.Run("transfer_invalid_quantity: should return code 422", func(t *testing.T) {
+		mockService.
+			On("Transfer", mock.Anything, mock.Anything).
+			Return(nil, domain.ErrTransferQuantity).
+			Once()
+
+		response := testutil.ExecuteTestRequest(router, http.MethodPost, EndpointProductBatch+"/1/transfer",
+			[]byte(`{"section_id": 2, "quantity": 99, "employee_id": 7, "reason": "rebalance"}`))
+
+		assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+	})
+
+	t.Run("transfer_missing_fields: should return code 422", func(t *testing.T) {
+		response := testutil.ExecuteTestRequest(router, http.MethodPost, EndpointProductBatch+"/1/transfer", []byte(`{"section_id": 2}`))
+
+		assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+	})
+}
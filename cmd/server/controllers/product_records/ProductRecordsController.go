@@ -2,10 +2,13 @@ package controllers
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	product "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product/domain"
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_records/domain"
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/pkg/httputil"
 )
@@ -71,3 +74,126 @@ func (c *ProductRecordsController) Create() gin.HandlerFunc {
 		httputil.NewResponse(ctx, http.StatusCreated, newProductRecords)
 	}
 }
+
+// GetByProductId godoc
+// @Summary      List the price history of a product
+// @Description  Product records ordered by date, with the margin of each one.
+// @Description  from and to are inclusive and accept YYYY-MM-DD or RFC3339.
+// @Tags         Products
+// @Produce      json
+// @Param id path int true "Product ID"
+// @Param from query string false "Start of the range"
+// @Param to query string false "End of the range"
+// @Success      200  {array}   domain.ProductPrice
+// @Failure      400  {object}  httputil.HTTPError
+// @Failure      404  {object}  httputil.HTTPError
+// @Router /products/{id}/records [get]
+func (c *ProductRecordsController) GetByProductId() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+
+		productId, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+		if err != nil {
+			httputil.NewError(ctx, http.StatusBadRequest, errors.New("invalid id"))
+			return
+		}
+
+		var from, to *time.Time
+
+		if value := ctx.Query("from"); value != "" {
+			date, err := parseTime(value, false)
+			if err != nil {
+				httputil.NewError(ctx, http.StatusBadRequest, err)
+				return
+			}
+			from = &date
+		}
+
+		if value := ctx.Query("to"); value != "" {
+			date, err := parseTime(value, true)
+			if err != nil {
+				httputil.NewError(ctx, http.StatusBadRequest, err)
+				return
+			}
+			to = &date
+		}
+
+		prices, err := c.service.GetByProductId(ctx.Request.Context(), productId, from, to)
+		if err != nil {
+			httputil.NewError(ctx, statusFromError(err), err)
+			return
+		}
+
+		httputil.NewResponse(ctx, http.StatusOK, prices)
+	}
+}
+
+// GetPriceAt godoc
+// @Summary      Get the price of a product
+// @Description  Price effective at the given time: the latest product record up to it, with its margin.
+// @Description  at defaults to now and accepts YYYY-MM-DD (end of that day) or RFC3339.
+// @Tags         Products
+// @Produce      json
+// @Param id path int true "Product ID"
+// @Param at query string false "Point in time"
+// @Success      200  {object}  domain.ProductPrice
+// @Failure      400  {object}  httputil.HTTPError
+// @Failure      404  {object}  httputil.HTTPError
+// @Router /products/{id}/price [get]
+func (c *ProductRecordsController) GetPriceAt() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+
+		productId, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+		if err != nil {
+			httputil.NewError(ctx, http.StatusBadRequest, errors.New("invalid id"))
+			return
+		}
+
+		at := time.Now().UTC()
+
+		if value := ctx.Query("at"); value != "" {
+			at, err = parseTime(value, true)
+			if err != nil {
+				httputil.NewError(ctx, http.StatusBadRequest, err)
+				return
+			}
+		}
+
+		price, err := c.service.GetPriceAt(ctx.Request.Context(), productId, at)
+		if err != nil {
+			httputil.NewError(ctx, statusFromError(err), err)
+			return
+		}
+
+		httputil.NewResponse(ctx, http.StatusOK, price)
+	}
+}
+
+// parseTime accepts an RFC3339 timestamp or a bare date. A bare date stands
+// for the end of that day when endOfDay is set, so the day is included.
+func parseTime(value string, endOfDay bool) (time.Time, error) {
+	if date, err := time.Parse(time.RFC3339, value); err == nil {
+		return date, nil
+	}
+
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q: use YYYY-MM-DD or RFC3339", value)
+	}
+
+	if endOfDay {
+		date = date.Add(24*time.Hour - time.Microsecond)
+	}
+
+	return date, nil
+}
+
+func statusFromError(err error) int {
+	switch {
+	case errors.Is(err, product.ErrProductIdNotFound), errors.Is(err, domain.ErrPriceNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrInvalidDateRange):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	controllers "github.com/vinigracindo/mercado-fresco-stranger-strings/cmd/server/controllers/product_records"
+	product "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product/domain"
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_records/domain"
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_records/domain/mocks"
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/pkg/testutil"
@@ -95,3 +97,125 @@ func TestProductRecordsController_Create(t *testing.T) {
 	})
 
 }
+
+const EndpointProducts = "/api/v1/products"
+
+func TestProductRecordsController_GetByProductId(t *testing.T) {
+
+	date := time.Date(2022, 4, 4, 0, 0, 0, 0, time.UTC)
+	price := domain.NewProductPrice(domain.ProductRecords{Id: 1, LastUpdateDate: date, PurchasePrice: 10, SalePrice: 15, ProductId: 1})
+
+	mockService := mocks.NewProductRecordsService(t)
+	controller := controllers.CreateProductRecordsController(mockService)
+
+	router := testutil.SetUpRouter()
+	router.GET(EndpointProducts+"/:id/records", controller.GetByProductId())
+
+	t.Run("get_by_product_id_ok: should return code 200 with the history", func(t *testing.T) {
+
+		from := date
+		to := time.Date(2022, 4, 30, 23, 59, 59, 999999000, time.UTC)
+
+		mockService.
+			On("GetByProductId", mock.Anything, int64(1), &from, &to).
+			Return([]domain.ProductPrice{price}, nil).
+			Once()
+
+		response := testutil.ExecuteTestRequest(router, http.MethodGet, EndpointProducts+"/1/records?from=2022-04-04&to=2022-04-30", nil)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.JSONEq(t, "{\"data\":[{\"id\":1,\"last_update_date\":\"2022-04-04T00:00:00Z\",\"purchase_price\":10,\"sale_price\":15,"+
+			"\"product_id\":1,\"margin\":5,\"margin_percentage\":33.33}]}", response.Body.String())
+	})
+
+	t.Run("get_by_product_id_invalid_date: when a bound is not a date, should return code 400", func(t *testing.T) {
+
+		response := testutil.ExecuteTestRequest(router, http.MethodGet, EndpointProducts+"/1/records?from=yesterday", nil)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("get_by_product_id_invalid_range: when from is after to, should return code 400", func(t *testing.T) {
+
+		mockService.
+			On("GetByProductId", mock.Anything, int64(1), mock.Anything, mock.Anything).
+			Return(nil, domain.ErrInvalidDateRange).
+			Once()
+
+		response := testutil.ExecuteTestRequest(router, http.MethodGet, EndpointProducts+"/1/records?from=2022-05-01&to=2022-04-01", nil)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("get_by_product_id_non_existent: when the product does not exist, should return code 404", func(t *testing.T) {
+
+		mockService.
+			On("GetByProductId", mock.Anything, int64(9), (*time.Time)(nil), (*time.Time)(nil)).
+			Return(nil, product.ErrProductIdNotFound).
+			Once()
+
+		response := testutil.ExecuteTestRequest(router, http.MethodGet, EndpointProducts+"/9/records", nil)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
+func TestProductRecordsController_GetPriceAt(t *testing.T) {
+
+	date := time.Date(2022, 4, 4, 0, 0, 0, 0, time.UTC)
+	price := domain.NewProductPrice(domain.ProductRecords{Id: 1, LastUpdateDate: date, PurchasePrice: 8, SalePrice: 10, ProductId: 1})
+
+	mockService := mocks.NewProductRecordsService(t)
+	controller := controllers.CreateProductRecordsController(mockService)
+
+	router := testutil.SetUpRouter()
+	router.GET(EndpointProducts+"/:id/price", controller.GetPriceAt())
+
+	t.Run("get_price_at_ok: should return code 200 with the effective price", func(t *testing.T) {
+
+		at := time.Date(2022, 4, 10, 15, 0, 0, 0, time.UTC)
+
+		mockService.
+			On("GetPriceAt", mock.Anything, int64(1), at).
+			Return(&price, nil).
+			Once()
+
+		response := testutil.ExecuteTestRequest(router, http.MethodGet, EndpointProducts+"/1/price?at=2022-04-10T15:00:00Z", nil)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.JSONEq(t, "{\"data\":{\"id\":1,\"last_update_date\":\"2022-04-04T00:00:00Z\",\"purchase_price\":8,\"sale_price\":10,"+
+			"\"product_id\":1,\"margin\":2,\"margin_percentage\":20}}", response.Body.String())
+	})
+
+	t.Run("get_price_at_default_now: without at, should use the current time", func(t *testing.T) {
+
+		mockService.
+			On("GetPriceAt", mock.Anything, int64(1), mock.AnythingOfType("time.Time")).
+			Return(&price, nil).
+			Once()
+
+		response := testutil.ExecuteTestRequest(router, http.MethodGet, EndpointProducts+"/1/price", nil)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+
+	t.Run("get_price_at_not_found: when there is no price yet, should return code 404", func(t *testing.T) {
+
+		mockService.
+			On("GetPriceAt", mock.Anything, int64(1), mock.AnythingOfType("time.Time")).
+			Return(nil, domain.ErrPriceNotFound).
+			Once()
+
+		response := testutil.ExecuteTestRequest(router, http.MethodGet, EndpointProducts+"/1/price?at=2020-01-01", nil)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+		assert.Equal(t, "{\"code\":404,\"message\":\"no price recorded for the product at the given time\"}", response.Body.String())
+	})
+
+	t.Run("get_price_at_invalid_id: when product id is not parsed, should return code 400", func(t *testing.T) {
+
+		response := testutil.ExecuteTestRequest(router, http.MethodGet, EndpointProducts+"/abc/price", nil)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+}
@@ -0,0 +1,204 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_type/domain"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/pkg/httputil"
+)
+
+type RequestProductTypePost struct {
+	Description        string   `json:"description" binding:"required"`
+	Category           string   `json:"category" binding:"required"`
+	MinimumTemperature *float64 `json:"minimum_temperature" binding:"required"`
+	MaximumTemperature *float64 `json:"maximum_temperature" binding:"required"`
+}
+
+type RequestProductTypePatch struct {
+	Description        *string  `json:"description"`
+	Category           *string  `json:"category"`
+	MinimumTemperature *float64 `json:"minimum_temperature"`
+	MaximumTemperature *float64 `json:"maximum_temperature"`
+}
+
+type ProductTypeController struct {
+	service domain.ProductTypeService
+}
+
+func NewProductTypeController(service domain.ProductTypeService) *ProductTypeController {
+	return &ProductTypeController{service: service}
+}
+
+// GetAll godoc
+// @Summary      List all product types
+// @Description  Get all product types with their storage category and temperature range
+// @Tags         ProductTypes
+// @Produce      json
+// @Success      200  {array}   domain.ProductType
+// @Failure      500  {object}  httputil.HTTPError
+// @Router /productTypes [get]
+func (c *ProductTypeController) GetAll() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		productTypes, err := c.service.GetAll(ctx.Request.Context())
+		if err != nil {
+			httputil.NewError(ctx, http.StatusInternalServerError, err)
+			return
+		}
+
+		httputil.NewResponse(ctx, http.StatusOK, productTypes)
+	}
+}
+
+// GetById godoc
+// @Summary      Get product type by ID
+// @Description  Get product type by ID
+// @Tags         ProductTypes
+// @Produce      json
+// @Param id path int true "Product type ID"
+// @Success      200  {object}  domain.ProductType
+// @Failure      400  {object}  httputil.HTTPError
+// @Failure      404  {object}  httputil.HTTPError
+// @Router /productTypes/{id} [get]
+func (c *ProductTypeController) GetById() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+		if err != nil {
+			httputil.NewError(ctx, http.StatusBadRequest, errors.New("invalid id"))
+			return
+		}
+
+		productType, err := c.service.GetById(ctx.Request.Context(), id)
+		if err != nil {
+			httputil.NewError(ctx, statusFromError(err), err)
+			return
+		}
+
+		httputil.NewResponse(ctx, http.StatusOK, productType)
+	}
+}
+
+// Create godoc
+// @Summary      Create product type
+// @Description  Create a product type. Category is one of frozen, refrigerated or ambient.
+// @Tags         ProductTypes
+// @Accept       json
+// @Produce      json
+// @Param ProductType body RequestProductTypePost true "Create product type"
+// @Success      201  {object}  domain.ProductType
+// @Failure      409  {object}  httputil.HTTPError
+// @Failure      422  {object}  httputil.HTTPError
+// @Router /productTypes [post]
+func (c *ProductTypeController) Create() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var request RequestProductTypePost
+
+		if err := ctx.ShouldBindJSON(&request); err != nil {
+			httputil.NewError(ctx, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		productType, err := c.service.Create(ctx.Request.Context(), &domain.ProductType{
+			Description:        request.Description,
+			Category:           request.Category,
+			MinimumTemperature: *request.MinimumTemperature,
+			MaximumTemperature: *request.MaximumTemperature,
+		})
+		if err != nil {
+			httputil.NewError(ctx, statusFromError(err), err)
+			return
+		}
+
+		httputil.NewResponse(ctx, http.StatusCreated, productType)
+	}
+}
+
+// Update godoc
+// @Summary      Update product type
+// @Description  Update any of the product type fields
+// @Tags         ProductTypes
+// @Accept       json
+// @Produce      json
+// @Param id path int true "Product type ID"
+// @Param ProductType body RequestProductTypePatch true "Update product type"
+// @Success      200  {object}  domain.ProductType
+// @Failure      400  {object}  httputil.HTTPError
+// @Failure      404  {object}  httputil.HTTPError
+// @Failure      409  {object}  httputil.HTTPError
+// @Failure      422  {object}  httputil.HTTPError
+// @Router /productTypes/{id} [patch]
+func (c *ProductTypeController) Update() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+		if err != nil {
+			httputil.NewError(ctx, http.StatusBadRequest, errors.New("invalid id"))
+			return
+		}
+
+		var request RequestProductTypePatch
+
+		if err := ctx.ShouldBindJSON(&request); err != nil {
+			httputil.NewError(ctx, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		productType, err := c.service.Update(
+			ctx.Request.Context(),
+			id,
+			request.Description,
+			request.Category,
+			request.MinimumTemperature,
+			request.MaximumTemperature,
+		)
+		if err != nil {
+			httputil.NewError(ctx, statusFromError(err), err)
+			return
+		}
+
+		httputil.NewResponse(ctx, http.StatusOK, productType)
+	}
+}
+
+// Delete godoc
+// @Summary      Delete product type
+// @Description  Delete a product type that no product or section uses
+// @Tags         ProductTypes
+// @Param id path int true "Product type ID"
+// @Success      204
+// @Failure      400  {object}  httputil.HTTPError
+// @Failure      404  {object}  httputil.HTTPError
+// @Failure      409  {object}  httputil.HTTPError
+// @Router /productTypes/{id} [delete]
+func (c *ProductTypeController) Delete() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+		if err != nil {
+			httputil.NewError(ctx, http.StatusBadRequest, errors.New("invalid id"))
+			return
+		}
+
+		if err := c.service.Delete(ctx.Request.Context(), id); err != nil {
+			httputil.NewError(ctx, statusFromError(err), err)
+			return
+		}
+
+		httputil.NewResponse(ctx, http.StatusNoContent, nil)
+	}
+}
+
+func statusFromError(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrProductTypeNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrProductTypeAlreadyExists), errors.Is(err, domain.ErrProductTypeInUse):
+		return http.StatusConflict
+	case errors.Is(err, domain.ErrInvalidDescription),
+		errors.Is(err, domain.ErrInvalidCategory),
+		errors.Is(err, domain.ErrInvalidTemperatureRange):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
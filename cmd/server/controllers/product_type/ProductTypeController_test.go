@@ -0,0 +1,198 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	controllers "github.com/vinigracindo/mercado-fresco-stranger-strings/cmd/server/controllers/product_type"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_type/domain"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_type/domain/mocks"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/pkg/testutil"
+)
+
+const EndpointProductTypes = "/api/v1/productTypes"
+
+var mockProductType = domain.ProductType{
+	Id:                 1,
+	Description:        "Frozen",
+	Category:           domain.CategoryFrozen,
+	MinimumTemperature: -25,
+	MaximumTemperature: -18,
+}
+
+func setUpProductTypeRoutes(service domain.ProductTypeService) *gin.Engine {
+	controller := controllers.NewProductTypeController(service)
+
+	r := testutil.SetUpRouter()
+	group := r.Group(EndpointProductTypes)
+	group.GET("/", controller.GetAll())
+	group.GET("/:id", controller.GetById())
+	group.POST("/", controller.Create())
+	group.PATCH("/:id", controller.Update())
+	group.DELETE("/:id", controller.Delete())
+
+	return r
+}
+
+func TestProductTypeController_GetAll(t *testing.T) {
+	t.Run("get_all_ok: should return 200 with all product types", func(t *testing.T) {
+		service := mocks.NewProductTypeService(t)
+		service.On("GetAll", mock.Anything).Return([]domain.ProductType{mockProductType}, nil).Once()
+
+		response := testutil.ExecuteTestRequest(setUpProductTypeRoutes(service), http.MethodGet, EndpointProductTypes+"/", nil)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.JSONEq(t, testutil.StringJSON(map[string]interface{}{"data": []domain.ProductType{mockProductType}}), response.Body.String())
+	})
+}
+
+func TestProductTypeController_GetById(t *testing.T) {
+	t.Run("get_by_id_ok: should return 200 with the product type", func(t *testing.T) {
+		service := mocks.NewProductTypeService(t)
+		service.On("GetById", mock.Anything, int64(1)).Return(&mockProductType, nil).Once()
+
+		response := testutil.ExecuteTestRequest(setUpProductTypeRoutes(service), http.MethodGet, EndpointProductTypes+"/1", nil)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+
+	t.Run("get_by_id_non_existent: should return 404", func(t *testing.T) {
+		service := mocks.NewProductTypeService(t)
+		service.On("GetById", mock.Anything, int64(9)).Return(nil, domain.ErrProductTypeNotFound).Once()
+
+		response := testutil.ExecuteTestRequest(setUpProductTypeRoutes(service), http.MethodGet, EndpointProductTypes+"/9", nil)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+
+	t.Run("get_by_id_invalid_id: should return 400", func(t *testing.T) {
+		service := mocks.NewProductTypeService(t)
+
+		response := testutil.ExecuteTestRequest(setUpProductTypeRoutes(service), http.MethodGet, EndpointProductTypes+"/abc", nil)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+}
+
+func TestProductTypeController_Create(t *testing.T) {
+	minimum, maximum := float64(-25), float64(-18)
+
+	t.Run("create_ok: should return 201 with the product type", func(t *testing.T) {
+		service := mocks.NewProductTypeService(t)
+		service.On("Create", mock.Anything, mock.AnythingOfType("*domain.ProductType")).Return(&mockProductType, nil).Once()
+
+		body, _ := json.Marshal(controllers.RequestProductTypePost{
+			Description:        "Frozen",
+			Category:           domain.CategoryFrozen,
+			MinimumTemperature: &minimum,
+			MaximumTemperature: &maximum,
+		})
+
+		response := testutil.ExecuteTestRequest(setUpProductTypeRoutes(service), http.MethodPost, EndpointProductTypes+"/", body)
+
+		assert.Equal(t, http.StatusCreated, response.Code)
+		assert.JSONEq(t, testutil.StringJSON(map[string]interface{}{"data": mockProductType}), response.Body.String())
+	})
+
+	t.Run("create_unprocessable_entity: should return 422 when temperatures are missing", func(t *testing.T) {
+		service := mocks.NewProductTypeService(t)
+
+		body := []byte(`{"description": "Frozen", "category": "frozen"}`)
+		response := testutil.ExecuteTestRequest(setUpProductTypeRoutes(service), http.MethodPost, EndpointProductTypes+"/", body)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+	})
+
+	t.Run("create_invalid_category: should return 422", func(t *testing.T) {
+		service := mocks.NewProductTypeService(t)
+		service.On("Create", mock.Anything, mock.AnythingOfType("*domain.ProductType")).Return(nil, domain.ErrInvalidCategory).Once()
+
+		body, _ := json.Marshal(controllers.RequestProductTypePost{
+			Description:        "Hot",
+			Category:           "hot",
+			MinimumTemperature: &minimum,
+			MaximumTemperature: &maximum,
+		})
+
+		response := testutil.ExecuteTestRequest(setUpProductTypeRoutes(service), http.MethodPost, EndpointProductTypes+"/", body)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+	})
+
+	t.Run("create_conflict: should return 409 when the description exists", func(t *testing.T) {
+		service := mocks.NewProductTypeService(t)
+		service.On("Create", mock.Anything, mock.AnythingOfType("*domain.ProductType")).Return(nil, domain.ErrProductTypeAlreadyExists).Once()
+
+		body, _ := json.Marshal(controllers.RequestProductTypePost{
+			Description:        "Frozen",
+			Category:           domain.CategoryFrozen,
+			MinimumTemperature: &minimum,
+			MaximumTemperature: &maximum,
+		})
+
+		response := testutil.ExecuteTestRequest(setUpProductTypeRoutes(service), http.MethodPost, EndpointProductTypes+"/", body)
+
+		assert.Equal(t, http.StatusConflict, response.Code)
+	})
+}
+
+func TestProductTypeController_Update(t *testing.T) {
+	t.Run("update_ok: should return 200 with the updated product type", func(t *testing.T) {
+		maximum := float64(-15)
+		expected := mockProductType
+		expected.MaximumTemperature = maximum
+
+		service := mocks.NewProductTypeService(t)
+		service.On("Update", mock.Anything, int64(1), (*string)(nil), (*string)(nil), (*float64)(nil), &maximum).Return(&expected, nil).Once()
+
+		body := []byte(`{"maximum_temperature": -15}`)
+		response := testutil.ExecuteTestRequest(setUpProductTypeRoutes(service), http.MethodPatch, EndpointProductTypes+"/1", body)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.JSONEq(t, testutil.StringJSON(map[string]interface{}{"data": expected}), response.Body.String())
+	})
+
+	t.Run("update_invalid_range: should return 422", func(t *testing.T) {
+		service := mocks.NewProductTypeService(t)
+		service.On("Update", mock.Anything, int64(1), mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, domain.ErrInvalidTemperatureRange).Once()
+
+		body := []byte(`{"minimum_temperature": 10}`)
+		response := testutil.ExecuteTestRequest(setUpProductTypeRoutes(service), http.MethodPatch, EndpointProductTypes+"/1", body)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+	})
+
+	t.Run("update_non_existent: should return 404", func(t *testing.T) {
+		service := mocks.NewProductTypeService(t)
+		service.On("Update", mock.Anything, int64(9), mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, domain.ErrProductTypeNotFound).Once()
+
+		body := []byte(`{"description": "Chilled"}`)
+		response := testutil.ExecuteTestRequest(setUpProductTypeRoutes(service), http.MethodPatch, EndpointProductTypes+"/9", body)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
+func TestProductTypeController_Delete(t *testing.T) {
+	t.Run("delete_ok: should return 204", func(t *testing.T) {
+		service := mocks.NewProductTypeService(t)
+		service.On("Delete", mock.Anything, int64(1)).Return(nil).Once()
+
+		response := testutil.ExecuteTestRequest(setUpProductTypeRoutes(service), http.MethodDelete, EndpointProductTypes+"/1", nil)
+
+		assert.Equal(t, http.StatusNoContent, response.Code)
+	})
+
+	t.Run("delete_in_use: should return 409", func(t *testing.T) {
+		service := mocks.NewProductTypeService(t)
+		service.On("Delete", mock.Anything, int64(1)).Return(domain.ErrProductTypeInUse).Once()
+
+		response := testutil.ExecuteTestRequest(setUpProductTypeRoutes(service), http.MethodDelete, EndpointProductTypes+"/1", nil)
+
+		assert.Equal(t, http.StatusConflict, response.Code)
+	})
+}
@@ -2,11 +2,15 @@ package controllers
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	productType "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_type/domain"
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/section/domain"
+	warehouse "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/warehouse/domain"
 	httputil "github.com/vinigracindo/mercado-fresco-stranger-strings/pkg/httputil"
 )
 
@@ -22,7 +26,14 @@ type requestSectionPost struct {
 }
 
 type requestSectionPatch struct {
-	CurrentCapacity int64 `json:"current_capacity" binding:"required"`
+	SectionNumber      *int64   `json:"section_number"`
+	CurrentTemperature *float64 `json:"current_temperature"`
+	MinimumTemperature *float64 `json:"minimum_temperature"`
+	CurrentCapacity    *int64   `json:"current_capacity"`
+	MinimumCapacity    *int64   `json:"minimum_capacity"`
+	MaximumCapacity    *int64   `json:"maximum_capacity"`
+	WarehouseId        *int64   `json:"warehouse_id"`
+	ProductTypeId      *int64   `json:"product_type_id"`
 }
 
 type ControllerSection struct {
@@ -37,14 +48,18 @@ func NewSection(s domain.SectionService) *ControllerSection {
 
 // Sections godoc
 // @Summary      Delete section
-// @Description  Delete section by id
+// @Description  Delete section by id. A section that holds batches, even discarded ones, cannot be deleted: the 409 lists
+// @Description  them in details. mode=archive hides the section from the listings instead, keeping its history; only
+// @Description  batches that still hold stock prevent it
 // @Tags         Sections
 // @Accept       json
 // @Produce      json
 // @Param id path int true "Section ID"
+// @Param mode query string false "delete (default) or archive"
 // @Success      204
 // @Failure      400  {object}  httputil.HTTPError
 // @Failure      404  {object}  httputil.HTTPError
+// @Failure      409  {object}  httputil.HTTPError
 // @Router /sections/{id} [delete]
 func (c *ControllerSection) Delete() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
@@ -54,9 +69,24 @@ func (c *ControllerSection) Delete() gin.HandlerFunc {
 			return
 		}
 
-		err = c.service.Delete(ctx.Request.Context(), id)
+		switch ctx.DefaultQuery("mode", "delete") {
+		case "delete":
+			err = c.service.Delete(ctx.Request.Context(), id)
+		case "archive":
+			err = c.service.Archive(ctx.Request.Context(), id)
+		default:
+			httputil.NewError(ctx, http.StatusBadRequest, domain.ErrInvalidDeleteMode)
+			return
+		}
+
+		var inUse *domain.SectionInUseError
+		if errors.As(err, &inUse) {
+			httputil.NewErrorWithDetails(ctx, http.StatusConflict, err, gin.H{"product_batches": inUse.Batches})
+			return
+		}
+
 		if err != nil {
-			httputil.NewError(ctx, http.StatusNotFound, err)
+			httputil.NewError(ctx, statusFromError(err), err)
 			return
 		}
 		httputil.NewResponse(ctx, http.StatusNoContent, "")
@@ -64,18 +94,50 @@ func (c *ControllerSection) Delete() gin.HandlerFunc {
 }
 
 // Sections godoc
-// @Summary      Update currentCapacity
-// @Description  Update currentCapacity field by id
+// @Summary      Restore section
+// @Description  Bring an archived section back to the listings
+// @Tags         Sections
+// @Produce      json
+// @Param id path int true "Section ID"
+// @Success      200  {object} domain.SectionModel
+// @Failure      400  {object}  httputil.HTTPError
+// @Failure      404  {object}  httputil.HTTPError
+// @Failure      409  {object}  httputil.HTTPError
+// @Router /sections/{id}/restore [post]
+func (c *ControllerSection) Restore() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+		if err != nil {
+			httputil.NewError(ctx, http.StatusBadRequest, err)
+			return
+		}
+
+		section, err := c.service.Restore(ctx.Request.Context(), id)
+		if err != nil {
+			httputil.NewError(ctx, statusFromError(err), err)
+			return
+		}
+		httputil.NewResponse(ctx, http.StatusOK, section)
+	}
+}
+
+// Sections godoc
+// @Summary      Update section
+// @Description  Update any section fields by id except current_capacity, which follows the product batches.
+// @Description  The result must keep 0 <= minimum <= maximum and current <= maximum capacity. The warehouse and
+// @Description  product type only change while the section holds no stock
 // @Tags         Sections
 // @Accept       json
 // @Produce      json
 // @Param id path int true "Section ID"
-// @Param Section body requestSectionPatch true "Update field"
+// @Param Section body requestSectionPatch true "Fields to update"
 // @Success      200  {object} domain.SectionModel
 // @Failure      400  {object}  httputil.HTTPError
 // @Failure      404  {object}  httputil.HTTPError
+// @Failure      409  {object}  httputil.HTTPError
+// @Failure      422  {object}  httputil.HTTPError
 // @Router /sections/{id} [patch]
-func (c *ControllerSection) UpdateCurrentCapacity() gin.HandlerFunc {
+func (c *ControllerSection) Update() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
 		if err != nil {
@@ -88,15 +150,22 @@ func (c *ControllerSection) UpdateCurrentCapacity() gin.HandlerFunc {
 			httputil.NewError(ctx, http.StatusBadRequest, err)
 			return
 		}
-
-		if req.CurrentCapacity < 0 {
-			httputil.NewError(ctx, http.StatusBadRequest, errors.New("the field CurrentCapacity invalid"))
+		if req.CurrentCapacity != nil {
+			httputil.NewError(ctx, http.StatusUnprocessableEntity, domain.ErrCapacityReadOnly)
 			return
 		}
 
-		section, err := c.service.UpdateCurrentCapacity(ctx.Request.Context(), id, req.CurrentCapacity)
+		section, err := c.service.Update(ctx.Request.Context(), id, domain.SectionPatch{
+			SectionNumber:      req.SectionNumber,
+			CurrentTemperature: req.CurrentTemperature,
+			MinimumTemperature: req.MinimumTemperature,
+			MinimumCapacity:    req.MinimumCapacity,
+			MaximumCapacity:    req.MaximumCapacity,
+			WarehouseId:        req.WarehouseId,
+			ProductTypeId:      req.ProductTypeId,
+		})
 		if err != nil {
-			httputil.NewError(ctx, http.StatusNotFound, err)
+			httputil.NewError(ctx, statusFromError(err), err)
 			return
 		}
 		httputil.NewResponse(ctx, http.StatusOK, section)
@@ -136,7 +205,11 @@ func (c ControllerSection) Create() gin.HandlerFunc {
 		)
 
 		if err != nil {
-			httputil.NewError(ctx, http.StatusConflict, err)
+			status := statusFromError(err)
+			if status == http.StatusInternalServerError {
+				status = http.StatusConflict
+			}
+			httputil.NewError(ctx, status, err)
 			return
 		}
 		httputil.NewResponse(ctx, http.StatusCreated, &response)
@@ -172,6 +245,36 @@ func (c *ControllerSection) GetById() gin.HandlerFunc {
 	}
 }
 
+// Sections godoc
+// @Summary      Find section by number
+// @Description  get a section by the code of its warehouse and its section number there
+// @Tags         Sections
+// @Accept       json
+// @Produce      json
+// @Param warehouse_code query string true "Warehouse code"
+// @Param section_number query int true "Section number"
+// @Success      200  {object} domain.SectionModel
+// @Failure      400  {object}  httputil.HTTPError
+// @Failure      404  {object}  httputil.HTTPError
+// @Router /sections/byNumber [get]
+func (c *ControllerSection) GetByNumber() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		sectionNumber, err := strconv.ParseInt(ctx.Query("section_number"), 10, 64)
+		if err != nil {
+			httputil.NewError(ctx, http.StatusBadRequest, domain.ErrInvalidSectionLookup)
+			return
+		}
+
+		section, err := c.service.GetByNumber(ctx.Request.Context(), ctx.Query("warehouse_code"), sectionNumber)
+		if err != nil {
+			httputil.NewError(ctx, statusFromError(err), err)
+			return
+		}
+
+		httputil.NewResponse(ctx, http.StatusOK, section)
+	}
+}
+
 // Sections godoc
 // @Summary      List all sections
 // @Description  get sections
@@ -241,3 +344,127 @@ func (controller ControllerSection) getAllReportProductRecords(ctx *gin.Context)
 	}
 	httputil.NewResponse(ctx, http.StatusOK, result)
 }
+
+// Sections godoc
+// @Summary      Occupancy report
+// @Description  capacities, utilization percentage, distinct products, batches and nearest due date per section,
+// @Description  ordered by warehouse code and section number. Use format=csv to download it as CSV
+// @Tags         Sections
+// @Produce      json
+// @Produce      text/csv
+// @Param warehouse_id    query int    false "Warehouse ID"
+// @Param product_type_id query int    false "Product type ID"
+// @Param format          query string false "json (default) or csv"
+// @Success      200  {object} []domain.SectionOccupancy
+// @Failure      400  {object}  httputil.HTTPError
+// @Failure      404  {object}  httputil.HTTPError
+// @Router /sections/reportOccupancy [get]
+func (c *ControllerSection) GetOccupancy() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var filter domain.OccupancyFilter
+
+		ids := map[string]*int64{
+			"warehouse_id":    &filter.WarehouseId,
+			"product_type_id": &filter.ProductTypeId,
+		}
+		for name, target := range ids {
+			if value := ctx.Query(name); value != "" {
+				id, err := strconv.ParseInt(value, 10, 64)
+				if err != nil || id <= 0 {
+					httputil.NewError(ctx, http.StatusBadRequest, fmt.Errorf("invalid %s %q", name, value))
+					return
+				}
+				*target = id
+			}
+		}
+
+		format := ctx.DefaultQuery("format", "json")
+		if format != "json" && format != "csv" {
+			httputil.NewError(ctx, http.StatusBadRequest, fmt.Errorf("invalid format %q, want json or csv", format))
+			return
+		}
+
+		occupancy, err := c.service.GetOccupancy(ctx.Request.Context(), filter)
+		if errors.Is(err, warehouse.ErrWarehouseNotFound) || errors.Is(err, productType.ErrProductTypeNotFound) {
+			httputil.NewError(ctx, http.StatusNotFound, err)
+			return
+		}
+		if err != nil {
+			httputil.NewError(ctx, statusFromError(err), err)
+			return
+		}
+
+		if format == "csv" {
+			httputil.NewCSV(ctx, "sections-occupancy.csv", occupancyHeader, occupancyRecords(occupancy))
+			return
+		}
+
+		httputil.NewResponse(ctx, http.StatusOK, occupancy)
+	}
+}
+
+var occupancyHeader = []string{
+	"warehouse_id",
+	"warehouse_code",
+	"section_id",
+	"section_number",
+	"product_type_id",
+	"maximum_capacity",
+	"minimum_capacity",
+	"current_capacity",
+	"utilization",
+	"below_minimum",
+	"distinct_products",
+	"batches",
+	"nearest_due_date",
+}
+
+func occupancyRecords(occupancy []domain.SectionOccupancy) [][]string {
+	records := make([][]string, 0, len(occupancy))
+	for _, o := range occupancy {
+		nearestDueDate := ""
+		if o.NearestDueDate != nil {
+			nearestDueDate = o.NearestDueDate.Format(time.RFC3339)
+		}
+
+		records = append(records, []string{
+			strconv.FormatInt(o.WarehouseId, 10),
+			o.WarehouseCode,
+			strconv.FormatInt(o.SectionId, 10),
+			strconv.FormatInt(o.SectionNumber, 10),
+			strconv.FormatInt(o.ProductTypeId, 10),
+			strconv.FormatInt(o.MaximumCapacity, 10),
+			strconv.FormatInt(o.MinimumCapacity, 10),
+			strconv.FormatInt(o.CurrentCapacity, 10),
+			strconv.FormatFloat(o.Utilization, 'f', 2, 64),
+			strconv.FormatBool(o.BelowMinimum),
+			strconv.FormatInt(o.DistinctProducts, 10),
+			strconv.FormatInt(o.Batches, 10),
+			nearestDueDate,
+		})
+	}
+	return records
+}
+
+func statusFromError(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrSectionNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrSectionNumberTaken),
+		errors.Is(err, domain.ErrSectionInUse),
+		errors.Is(err, domain.ErrSectionHoldsStock),
+		errors.Is(err, domain.ErrSectionHasHistory),
+		errors.Is(err, domain.ErrSectionArchived),
+		errors.Is(err, domain.ErrSectionNotArchived):
+		return http.StatusConflict
+	case errors.Is(err, domain.ErrInvalidSectionLookup):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrInvalidCapacity),
+		errors.Is(err, domain.ErrInvalidSectionNumber),
+		errors.Is(err, productType.ErrProductTypeNotFound),
+		errors.Is(err, warehouse.ErrWarehouseNotFound):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -6,11 +6,14 @@ import (
 	"fmt"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	controllers "github.com/vinigracindo/mercado-fresco-stranger-strings/cmd/server/controllers/section"
+	productType "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_type/domain"
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/section/domain"
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/section/domain/mocks"
+	warehouse "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/warehouse/domain"
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/pkg/testutil"
 )
 
@@ -116,6 +119,28 @@ func TestSectionController_Create(t *testing.T) {
 		assert.Equal(t, http.StatusConflict, response.Code)
 		assert.JSONEq(t, "{\"code\":409,\"message\":\"already a section with this code\"}", response.Body.String())
 	})
+
+	t.Run("create_product_type_not_found: when the product type does not exist, should return code 422", func(t *testing.T) {
+		mockService.
+			On("Create",
+				ctx,
+				expectedSection.SectionNumber,
+				expectedSection.CurrentTemperature,
+				expectedSection.MinimumTemperature,
+				expectedSection.CurrentCapacity,
+				expectedSection.MinimumCapacity,
+				expectedSection.MaximumCapacity,
+				expectedSection.WarehouseId,
+				expectedSection.ProductTypeId,
+			).
+			Return(domain.SectionModel{}, productType.ErrProductTypeNotFound).
+			Once()
+
+		requestBody, _ := json.Marshal(bodySection)
+		response := testutil.ExecuteTestRequest(r, http.MethodPost, EndpointSection, requestBody)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+	})
 }
 
 func TestSectionController_GetAll(t *testing.T) {
@@ -197,25 +222,20 @@ func TestSectionController_Update(t *testing.T) {
 	controller := controllers.NewSection(mockService)
 
 	r := testutil.SetUpRouter()
-	r.PATCH(EndpointSection+"/:id", controller.UpdateCurrentCapacity())
-
-	var bodyUpdate = domain.SectionModel{
-		CurrentCapacity: int64(1),
-	}
+	r.PATCH(EndpointSection+"/:id", controller.Update())
 
-	var bodyFailSectionUpdate = domain.SectionModel{
-		CurrentCapacity: -1,
-	}
+	minimumCapacity := int64(1)
+	maximumCapacity := int64(10)
+	patch := domain.SectionPatch{MinimumCapacity: &minimumCapacity, MaximumCapacity: &maximumCapacity}
+	bodyUpdate := []byte(`{"minimum_capacity":1,"maximum_capacity":10}`)
 
 	t.Run("update_ok: when the request is successful, should return code 200", func(t *testing.T) {
 		mockService.
-			On("UpdateCurrentCapacity", ctx, int64(1), int64(1)).
+			On("Update", ctx, int64(1), patch).
 			Return(&expectedSection, nil).
 			Once()
 
-		requestBody, _ := json.Marshal(bodyUpdate)
-
-		response := testutil.ExecuteTestRequest(r, http.MethodPatch, EndpointSection+"/1", requestBody)
+		response := testutil.ExecuteTestRequest(r, http.MethodPatch, EndpointSection+"/1", bodyUpdate)
 
 		assert.Equal(t, http.StatusOK, response.Code)
 		assert.JSONEq(t, "{\"data\":{\"id\":1,\"section_number\":1,\"current_temperature\":1,\"minimum_temperature\":1,\"current_capacity\":1,\"minimum_capacity\":1,\"maximum_capacity\":1,\"warehouse_id\":1,\"product_type_id\":1}}", response.Body.String())
@@ -223,17 +243,56 @@ func TestSectionController_Update(t *testing.T) {
 
 	t.Run("update_non_existent: when the section does not exist, should return code 404", func(t *testing.T) {
 		mockService.
-			On("UpdateCurrentCapacity", ctx, int64(1), int64(1)).
-			Return(nil, fmt.Errorf("section not found")).
+			On("Update", ctx, int64(1), patch).
+			Return(nil, domain.ErrSectionNotFound).
 			Once()
 
-		requestBody, _ := json.Marshal(bodyUpdate)
-		response := testutil.ExecuteTestRequest(r, http.MethodPatch, EndpointSection+"/1", requestBody)
+		response := testutil.ExecuteTestRequest(r, http.MethodPatch, EndpointSection+"/1", bodyUpdate)
 
 		assert.Equal(t, http.StatusNotFound, response.Code)
 		assert.JSONEq(t, "{\"code\":404,\"message\":\"section not found\"}", response.Body.String())
 	})
 
+	t.Run("update_invalid_capacity: when the capacities contradict each other, should return code 422", func(t *testing.T) {
+		mockService.
+			On("Update", ctx, int64(1), patch).
+			Return(nil, fmt.Errorf("%w: got minimum 1, current 20, maximum 10", domain.ErrInvalidCapacity)).
+			Once()
+
+		response := testutil.ExecuteTestRequest(r, http.MethodPatch, EndpointSection+"/1", bodyUpdate)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+	})
+
+	t.Run("update_number_taken: when the section number exists in the warehouse, should return code 409", func(t *testing.T) {
+		mockService.
+			On("Update", ctx, int64(1), patch).
+			Return(nil, domain.ErrSectionNumberTaken).
+			Once()
+
+		response := testutil.ExecuteTestRequest(r, http.MethodPatch, EndpointSection+"/1", bodyUpdate)
+
+		assert.Equal(t, http.StatusConflict, response.Code)
+	})
+
+	t.Run("update_holds_stock: when the section would change warehouse while holding stock, should return code 409", func(t *testing.T) {
+		mockService.
+			On("Update", ctx, int64(1), patch).
+			Return(nil, domain.ErrSectionHoldsStock).
+			Once()
+
+		response := testutil.ExecuteTestRequest(r, http.MethodPatch, EndpointSection+"/1", bodyUpdate)
+
+		assert.Equal(t, http.StatusConflict, response.Code)
+	})
+
+	t.Run("update_current_capacity: when the body sets the current capacity, should return code 422", func(t *testing.T) {
+		response := testutil.ExecuteTestRequest(r, http.MethodPatch, EndpointSection+"/1", []byte(`{"current_capacity":1}`))
+
+		assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+		assert.Contains(t, response.Body.String(), domain.ErrCapacityReadOnly.Error())
+	})
+
 	t.Run("update_invalid_id_parse_error: when section id is not parsed, should return code 400", func(t *testing.T) {
 		response := testutil.ExecuteTestRequest(r, http.MethodPatch, EndpointSection+"/idInvalid", []byte{})
 
@@ -241,21 +300,46 @@ func TestSectionController_Update(t *testing.T) {
 		assert.JSONEq(t, "{\"code\":400,\"message\":\"strconv.ParseInt: parsing \\\"idInvalid\\\": invalid syntax\"}", response.Body.String())
 	})
 
-	t.Run("update_invalid_field_value: when the field is negative,should return code 400", func(t *testing.T) {
-		requestBody, _ := json.Marshal(bodyFailSectionUpdate)
-		response := testutil.ExecuteTestRequest(r, http.MethodPatch, EndpointSection+"/1", requestBody)
+	t.Run("update_invalid_body: when the body is invalid, should return code 400", func(t *testing.T) {
+		response := testutil.ExecuteTestRequest(r, http.MethodPatch, EndpointSection+"/1", []byte(`{"maximum_capacity":"one"}`))
 
 		assert.Equal(t, http.StatusBadRequest, response.Code)
-		assert.JSONEq(t, "{\"code\":400,\"message\":\"the field CurrentCapacity invalid\"}", response.Body.String())
 	})
+}
 
-	t.Run("update_invalid_body: when the body is invalid, should return code 400", func(t *testing.T) {
-		requestBody, _ := json.Marshal(bodyFailSection)
+func TestSectionController_GetByNumber(t *testing.T) {
+	mockService := mocks.NewSectionService(t)
+	controller := controllers.NewSection(mockService)
+
+	r := testutil.SetUpRouter()
+	r.GET(EndpointSection+"/byNumber", controller.GetByNumber())
+
+	t.Run("get_by_number_ok: when the section exists, should return code 200", func(t *testing.T) {
+		mockService.
+			On("GetByNumber", ctx, "WH-01", int64(1)).
+			Return(expectedSection, nil).
+			Once()
+
+		response := testutil.ExecuteTestRequest(r, http.MethodGet, EndpointSection+"/byNumber?warehouse_code=WH-01&section_number=1", []byte{})
+
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+
+	t.Run("get_by_number_not_found: when the section does not exist, should return code 404", func(t *testing.T) {
+		mockService.
+			On("GetByNumber", ctx, "WH-01", int64(2)).
+			Return(domain.SectionModel{}, domain.ErrSectionNotFound).
+			Once()
 
-		response := testutil.ExecuteTestRequest(r, http.MethodPatch, EndpointSection+"/1", requestBody)
+		response := testutil.ExecuteTestRequest(r, http.MethodGet, EndpointSection+"/byNumber?warehouse_code=WH-01&section_number=2", []byte{})
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+
+	t.Run("get_by_number_bad_request: when the section number is missing, should return code 400", func(t *testing.T) {
+		response := testutil.ExecuteTestRequest(r, http.MethodGet, EndpointSection+"/byNumber?warehouse_code=WH-01", []byte{})
 
 		assert.Equal(t, http.StatusBadRequest, response.Code)
-		assert.JSONEq(t, "{\"code\":400,\"message\":\"Key: 'requestSectionPatch.CurrentCapacity' Error:Field validation for 'CurrentCapacity' failed on the 'required' tag\"}", response.Body.String())
 	})
 }
 
@@ -280,7 +364,7 @@ func TestSectionController_Delete(t *testing.T) {
 	t.Run("delete_non_existent: when the section does not exist, should return code 404", func(t *testing.T) {
 		mockService.
 			On("Delete", ctx, int64(1)).
-			Return(fmt.Errorf("section not found")).
+			Return(domain.ErrSectionNotFound).
 			Once()
 
 		response := testutil.ExecuteTestRequest(r, http.MethodDelete, EndpointSection+"/1", []byte{})
@@ -295,6 +379,89 @@ func TestSectionController_Delete(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, response.Code)
 		assert.JSONEq(t, "{\"code\":400,\"message\":\"strconv.ParseInt: parsing \\\"idInvalid\\\": invalid syntax\"}", response.Body.String())
 	})
+
+	t.Run("delete_in_use: when the section holds batches, should return code 409 listing them", func(t *testing.T) {
+		mockService.
+			On("Delete", ctx, int64(1)).
+			Return(&domain.SectionInUseError{Batches: []domain.SectionBatch{
+				{Id: 4, BatchNumber: 12, ProductId: 2, Status: "active", CurrentQuantity: 10},
+			}}).
+			Once()
+
+		response := testutil.ExecuteTestRequest(r, http.MethodDelete, EndpointSection+"/1", []byte{})
+
+		assert.Equal(t, http.StatusConflict, response.Code)
+		assert.JSONEq(t, `{"code":409,"message":"section holds product batches: 1","details":{"product_batches":[{"id":4,"batch_number":12,"product_id":2,"status":"active","current_quantity":10}]}}`, response.Body.String())
+	})
+
+	t.Run("delete_has_history: when stock movements point to the section, should return code 409", func(t *testing.T) {
+		mockService.
+			On("Delete", ctx, int64(1)).
+			Return(domain.ErrSectionHasHistory).
+			Once()
+
+		response := testutil.ExecuteTestRequest(r, http.MethodDelete, EndpointSection+"/1", []byte{})
+
+		assert.Equal(t, http.StatusConflict, response.Code)
+	})
+
+	t.Run("archive_ok: when mode is archive, should archive and return code 204", func(t *testing.T) {
+		mockService.
+			On("Archive", ctx, int64(1)).
+			Return(nil).
+			Once()
+
+		response := testutil.ExecuteTestRequest(r, http.MethodDelete, EndpointSection+"/1?mode=archive", []byte{})
+
+		assert.Equal(t, http.StatusNoContent, response.Code)
+	})
+
+	t.Run("delete_invalid_mode: when mode is unknown, should return code 400", func(t *testing.T) {
+		response := testutil.ExecuteTestRequest(r, http.MethodDelete, EndpointSection+"/1?mode=purge", []byte{})
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+}
+
+func TestSectionController_Restore(t *testing.T) {
+	mockService := mocks.NewSectionService(t)
+	controller := controllers.NewSection(mockService)
+
+	r := testutil.SetUpRouter()
+	r.POST(EndpointSection+"/:id/restore", controller.Restore())
+
+	t.Run("restore_ok: when the section is archived, should return code 200", func(t *testing.T) {
+		mockService.
+			On("Restore", ctx, int64(1)).
+			Return(expectedSection, nil).
+			Once()
+
+		response := testutil.ExecuteTestRequest(r, http.MethodPost, EndpointSection+"/1/restore", []byte{})
+
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+
+	t.Run("restore_not_archived: when the section is not archived, should return code 409", func(t *testing.T) {
+		mockService.
+			On("Restore", ctx, int64(1)).
+			Return(domain.SectionModel{}, domain.ErrSectionNotArchived).
+			Once()
+
+		response := testutil.ExecuteTestRequest(r, http.MethodPost, EndpointSection+"/1/restore", []byte{})
+
+		assert.Equal(t, http.StatusConflict, response.Code)
+	})
+
+	t.Run("restore_non_existent: when the section does not exist, should return code 404", func(t *testing.T) {
+		mockService.
+			On("Restore", ctx, int64(1)).
+			Return(domain.SectionModel{}, domain.ErrSectionNotFound).
+			Once()
+
+		response := testutil.ExecuteTestRequest(r, http.MethodPost, EndpointSection+"/1/restore", []byte{})
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
 }
 
 func TestSectionController_GetReportProductsBySection(t *testing.T) {
@@ -362,3 +529,76 @@ func TestSectionController_GetReportProductsBySection(t *testing.T) {
 		assert.Equal(t, "{\"error\":\"invalid id\"}", response.Body.String())
 	})
 }
+
+func TestSectionController_GetOccupancy(t *testing.T) {
+	mockService := mocks.NewSectionService(t)
+	controller := controllers.NewSection(mockService)
+
+	r := testutil.SetUpRouter()
+	r.GET(EndpointSection+"/reportOccupancy", controller.GetOccupancy())
+
+	dueDate := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+	occupancy := []domain.SectionOccupancy{{
+		WarehouseId:      1,
+		WarehouseCode:    "WH-01",
+		SectionId:        1,
+		SectionNumber:    1,
+		ProductTypeId:    2,
+		MaximumCapacity:  10,
+		MinimumCapacity:  1,
+		CurrentCapacity:  5,
+		Utilization:      50,
+		DistinctProducts: 2,
+		Batches:          3,
+		NearestDueDate:   &dueDate,
+	}}
+
+	t.Run("get_occupancy_json: should return code 200 with the report", func(t *testing.T) {
+		mockService.
+			On("GetOccupancy", ctx, domain.OccupancyFilter{WarehouseId: 1, ProductTypeId: 2}).
+			Return(occupancy, nil).
+			Once()
+
+		response := testutil.ExecuteTestRequest(r, http.MethodGet, EndpointSection+"/reportOccupancy?warehouse_id=1&product_type_id=2", []byte{})
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Contains(t, response.Body.String(), "\"utilization\":50")
+	})
+
+	t.Run("get_occupancy_csv: should return the report as CSV", func(t *testing.T) {
+		mockService.
+			On("GetOccupancy", ctx, domain.OccupancyFilter{}).
+			Return(occupancy, nil).
+			Once()
+
+		response := testutil.ExecuteTestRequest(r, http.MethodGet, EndpointSection+"/reportOccupancy?format=csv", []byte{})
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, "text/csv; charset=utf-8", response.Header().Get("Content-Type"))
+		assert.Equal(t,
+			"warehouse_id,warehouse_code,section_id,section_number,product_type_id,maximum_capacity,minimum_capacity,current_capacity,utilization,below_minimum,distinct_products,batches,nearest_due_date\n"+
+				"1,WH-01,1,1,2,10,1,5,50.00,false,2,3,2026-01-10T00:00:00Z\n",
+			response.Body.String())
+	})
+
+	t.Run("get_occupancy_invalid_filter: should return code 400", func(t *testing.T) {
+		response := testutil.ExecuteTestRequest(r, http.MethodGet, EndpointSection+"/reportOccupancy?warehouse_id=abc", []byte{})
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+
+		response = testutil.ExecuteTestRequest(r, http.MethodGet, EndpointSection+"/reportOccupancy?format=xml", []byte{})
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("get_occupancy_warehouse_not_found: should return code 404", func(t *testing.T) {
+		mockService.
+			On("GetOccupancy", ctx, domain.OccupancyFilter{WarehouseId: 9}).
+			Return(nil, warehouse.ErrWarehouseNotFound).
+			Once()
+
+		response := testutil.ExecuteTestRequest(r, http.MethodGet, EndpointSection+"/reportOccupancy?warehouse_id=9", []byte{})
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -186,3 +187,86 @@ func (c SellerController) Delete() gin.HandlerFunc {
 		httputil.NewResponse(ctx, http.StatusNoContent, err)
 	}
 }
+
+// Seller godoc
+// @Summary      List the products of a seller
+// @Description  Paginated products of the seller with their current sale price
+// @Tags         Seller
+// @Produce      json
+// @Param id path int true "Seller ID"
+// @Param page query int false "Page, starting at 1"
+// @Param page_size query int false "Products per page, up to 100 (default 20)"
+// @Success      200  {object} domain.SellerProductsPage
+// @Failure      400  {object}  httputil.HTTPError
+// @Failure      404  {object}  httputil.HTTPError
+// @Router /sellers/{id}/products [get]
+func (c SellerController) GetProducts() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+
+		id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+		if err != nil {
+			httputil.NewError(ctx, http.StatusBadRequest, err)
+			return
+		}
+
+		page, err := strconv.ParseInt(ctx.DefaultQuery("page", "1"), 10, 64)
+		if err != nil {
+			httputil.NewError(ctx, http.StatusBadRequest, domain.ErrInvalidPage)
+			return
+		}
+
+		pageSize, err := strconv.ParseInt(ctx.DefaultQuery("page_size", strconv.Itoa(domain.DefaultPageSize)), 10, 64)
+		if err != nil {
+			httputil.NewError(ctx, http.StatusBadRequest, domain.ErrInvalidPage)
+			return
+		}
+
+		products, err := c.service.GetProducts(ctx.Request.Context(), id, page, pageSize)
+		if err != nil {
+			httputil.NewError(ctx, statusFromError(err), err)
+			return
+		}
+
+		httputil.NewResponse(ctx, http.StatusOK, products)
+	}
+}
+
+// Seller godoc
+// @Summary      Summarize the catalog of a seller
+// @Description  Product counts by product type, products without any price record and active stock in batches
+// @Tags         Seller
+// @Produce      json
+// @Param id path int true "Seller ID"
+// @Success      200  {object} domain.SellerSummary
+// @Failure      400  {object}  httputil.HTTPError
+// @Failure      404  {object}  httputil.HTTPError
+// @Router /sellers/{id}/summary [get]
+func (c SellerController) GetSummary() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+
+		id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+		if err != nil {
+			httputil.NewError(ctx, http.StatusBadRequest, err)
+			return
+		}
+
+		summary, err := c.service.GetSummary(ctx.Request.Context(), id)
+		if err != nil {
+			httputil.NewError(ctx, statusFromError(err), err)
+			return
+		}
+
+		httputil.NewResponse(ctx, http.StatusOK, summary)
+	}
+}
+
+func statusFromError(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrIDNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrInvalidPage):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -8,7 +8,9 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	controllers "github.com/vinigracindo/mercado-fresco-stranger-strings/cmd/server/controllers/seller"
+	product "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product/domain"
 
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/seller/domain"
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/seller/domain/mocks"
@@ -307,3 +309,108 @@ func TestSellerController_Delete(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, response.Code)
 	})
 }
+
+func TestSellerController_GetProducts(t *testing.T) {
+	mockService := mocks.NewServiceSeller(t)
+	controller := controllers.NewSeller(mockService)
+
+	r := testutil.SetUpRouter()
+	r.GET(EndpointSeller+"/:id/products", controller.GetProducts())
+
+	page := domain.SellerProductsPage{
+		Products: []domain.SellerProduct{{Product: product.Product{Id: 21, ProductCode: "FRU-0021", SellerId: 1}}},
+		Page:     2,
+		PageSize: 10,
+		Total:    11,
+	}
+
+	t.Run("get_products_ok: should return code 200 with the page", func(t *testing.T) {
+		mockService.
+			On("GetProducts", mock.Anything, int64(1), int64(2), int64(10)).
+			Return(&page, nil).
+			Once()
+
+		response := testutil.ExecuteTestRequest(r, http.MethodGet, EndpointSeller+"/1/products?page=2&page_size=10", nil)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.JSONEq(t, testutil.StringJSON(map[string]interface{}{"data": page}), response.Body.String())
+	})
+
+	t.Run("get_products_default_page: should ask for the first page of the default size", func(t *testing.T) {
+		mockService.
+			On("GetProducts", mock.Anything, int64(1), int64(1), int64(domain.DefaultPageSize)).
+			Return(&page, nil).
+			Once()
+
+		response := testutil.ExecuteTestRequest(r, http.MethodGet, EndpointSeller+"/1/products", nil)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+
+	t.Run("get_products_invalid_page: should return code 400", func(t *testing.T) {
+		response := testutil.ExecuteTestRequest(r, http.MethodGet, EndpointSeller+"/1/products?page=abc", nil)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+
+		mockService.
+			On("GetProducts", mock.Anything, int64(1), int64(1), int64(500)).
+			Return(nil, domain.ErrInvalidPage).
+			Once()
+
+		response = testutil.ExecuteTestRequest(r, http.MethodGet, EndpointSeller+"/1/products?page_size=500", nil)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("get_products_not_found: when the seller does not exist, should return code 404", func(t *testing.T) {
+		mockService.
+			On("GetProducts", mock.Anything, int64(9), int64(1), int64(domain.DefaultPageSize)).
+			Return(nil, domain.ErrIDNotFound).
+			Once()
+
+		response := testutil.ExecuteTestRequest(r, http.MethodGet, EndpointSeller+"/9/products", nil)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
+func TestSellerController_GetSummary(t *testing.T) {
+	mockService := mocks.NewServiceSeller(t)
+	controller := controllers.NewSeller(mockService)
+
+	r := testutil.SetUpRouter()
+	r.GET(EndpointSeller+"/:id/summary", controller.GetSummary())
+
+	t.Run("get_summary_ok: should return code 200 with the summary", func(t *testing.T) {
+		summary := domain.SellerSummary{
+			SellerId:             1,
+			Products:             2,
+			ProductsByType:       []domain.ProductTypeCount{{ProductTypeId: 1, Description: "Frozen", Products: 2}},
+			ProductsWithoutPrice: 1,
+			ActiveBatches:        3,
+			ActiveStock:          120,
+		}
+
+		mockService.
+			On("GetSummary", mock.Anything, int64(1)).
+			Return(&summary, nil).
+			Once()
+
+		response := testutil.ExecuteTestRequest(r, http.MethodGet, EndpointSeller+"/1/summary", nil)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.JSONEq(t, "{\"data\":{\"seller_id\":1,\"products\":2,\"products_by_type\":[{\"product_type_id\":1,\"description\":\"Frozen\",\"products\":2}],"+
+			"\"products_without_price\":1,\"active_batches\":3,\"active_stock\":120}}", response.Body.String())
+	})
+
+	t.Run("get_summary_not_found: when the seller does not exist, should return code 404", func(t *testing.T) {
+		mockService.
+			On("GetSummary", mock.Anything, int64(9)).
+			Return(nil, domain.ErrIDNotFound).
+			Once()
+
+		response := testutil.ExecuteTestRequest(r, http.MethodGet, EndpointSeller+"/9/summary", nil)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
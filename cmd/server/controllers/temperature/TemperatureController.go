@@ -0,0 +1,306 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	productBatch "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_batch/domain"
+	section "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/section/domain"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/temperature/domain"
+	warehouse "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/warehouse/domain"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/pkg/httputil"
+)
+
+type RequestReading struct {
+	SectionId      int64      `json:"section_id" binding:"required"`
+	ProductBatchId *int64     `json:"product_batch_id"`
+	Temperature    *float64   `json:"temperature" binding:"required"`
+	RecordedAt     *time.Time `json:"recorded_at" binding:"required"`
+}
+
+type RequestReadingsPost struct {
+	Readings []RequestReading `json:"readings" binding:"required,dive"`
+}
+
+type TemperatureController struct {
+	service domain.TemperatureService
+}
+
+func NewTemperatureController(service domain.TemperatureService) *TemperatureController {
+	return &TemperatureController{service: service}
+}
+
+// Ingest godoc
+// @Summary      Ingest temperature readings
+// @Description  Store a batch of timestamped temperature readings of sections or of product batches in them.
+// @Description  The current temperature of each section and product batch follows its latest reading.
+// @Description  The whole batch is rejected when any reading is invalid.
+// @Tags         Temperatures
+// @Accept       json
+// @Produce      json
+// @Param Readings body RequestReadingsPost true "Temperature readings"
+// @Success      201  {object}  domain.IngestResult
+// @Failure      422  {object}  httputil.HTTPError
+// @Router /temperatures [post]
+func (c *TemperatureController) Ingest() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var request RequestReadingsPost
+
+		if err := ctx.ShouldBindJSON(&request); err != nil {
+			httputil.NewError(ctx, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		readings := make([]domain.Reading, 0, len(request.Readings))
+		for _, reading := range request.Readings {
+			readings = append(readings, domain.Reading{
+				SectionId:      reading.SectionId,
+				ProductBatchId: reading.ProductBatchId,
+				Temperature:    *reading.Temperature,
+				RecordedAt:     *reading.RecordedAt,
+			})
+		}
+
+		result, err := c.service.Ingest(ctx.Request.Context(), readings)
+		if err != nil {
+			httputil.NewError(ctx, statusFromError(err), err)
+			return
+		}
+
+		httputil.NewResponse(ctx, http.StatusCreated, result)
+	}
+}
+
+// GetSectionSeries godoc
+// @Summary      Section temperatures
+// @Description  Temperature readings of a section, or of a product batch in it, in [from, to) grouped in buckets
+// @Description  with their min, max and average. The range defaults to the last 24 hours and the resolution
+// @Description  to the finest that fits in 1000 buckets. Readings older than the raw retention are kept hourly.
+// @Tags         Sections
+// @Produce      json
+// @Param id path int true "Section ID"
+// @Param from query string false "Start, RFC 3339"
+// @Param to query string false "End, RFC 3339"
+// @Param resolution query string false "Bucket size, e.g. 5m, 1h or 1d"
+// @Param product_batch_id query int false "Product batch ID"
+// @Success      200  {object}  domain.Series
+// @Failure      400  {object}  httputil.HTTPError
+// @Failure      404  {object}  httputil.HTTPError
+// @Router /sections/{id}/temperatures [get]
+func (c *TemperatureController) GetSectionSeries() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+		if err != nil {
+			httputil.NewError(ctx, http.StatusBadRequest, errors.New("invalid id"))
+			return
+		}
+
+		query, err := parseSeriesQuery(ctx, time.Now().UTC())
+		if err != nil {
+			httputil.NewError(ctx, http.StatusBadRequest, err)
+			return
+		}
+		query.SectionId = id
+
+		series, err := c.service.GetSeries(ctx.Request.Context(), query)
+		if err != nil {
+			httputil.NewError(ctx, statusFromError(err), err)
+			return
+		}
+
+		httputil.NewResponse(ctx, http.StatusOK, series)
+	}
+}
+
+// GetIncidents godoc
+// @Summary      Temperature incidents
+// @Description  Excursions of sections or product batches above their threshold that overlap [from, to),
+// @Description  with their peak and the batches affected. Pending incidents are only listed when asked for by status.
+// @Tags         Temperatures
+// @Produce      json
+// @Param warehouse_id query int false "Warehouse ID"
+// @Param section_id query int false "Section ID"
+// @Param status query string false "pending, open or closed"
+// @Param from query string false "Start, RFC 3339"
+// @Param to query string false "End, RFC 3339"
+// @Success      200  {array}   domain.Incident
+// @Failure      400  {object}  httputil.HTTPError
+// @Router /temperatures/incidents [get]
+func (c *TemperatureController) GetIncidents() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		filter, err := parseIncidentFilter(ctx)
+		if err != nil {
+			httputil.NewError(ctx, http.StatusBadRequest, err)
+			return
+		}
+
+		incidents, err := c.service.GetIncidents(ctx.Request.Context(), filter)
+		if err != nil {
+			httputil.NewError(ctx, statusFromError(err), err)
+			return
+		}
+
+		httputil.NewResponse(ctx, http.StatusOK, incidents)
+	}
+}
+
+// GetWarehouseCompliance godoc
+// @Summary      Cold-chain compliance
+// @Description  Share of the date range each section of the warehouse spent within its threshold, with its
+// @Description  readings, incidents and minutes of excursion. Both dates are inclusive and default to the last 30 days.
+// @Tags         Warehouses
+// @Produce      json
+// @Param id path int true "Warehouse ID"
+// @Param from query string false "First day, YYYY-MM-DD"
+// @Param to query string false "Last day, YYYY-MM-DD"
+// @Success      200  {object}  domain.ComplianceReport
+// @Failure      400  {object}  httputil.HTTPError
+// @Failure      404  {object}  httputil.HTTPError
+// @Router /warehouses/{id}/compliance [get]
+func (c *TemperatureController) GetWarehouseCompliance() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+		if err != nil {
+			httputil.NewError(ctx, http.StatusBadRequest, errors.New("invalid id"))
+			return
+		}
+
+		today := time.Now().UTC().Truncate(24 * time.Hour)
+		to := today.Add(24 * time.Hour)
+		if value := ctx.Query("to"); value != "" {
+			day, err := time.Parse("2006-01-02", value)
+			if err != nil {
+				httputil.NewError(ctx, http.StatusBadRequest, fmt.Errorf("invalid to %q: use YYYY-MM-DD", value))
+				return
+			}
+			to = day.Add(24 * time.Hour)
+		}
+
+		from := to.AddDate(0, 0, -domain.DefaultComplianceDays)
+		if value := ctx.Query("from"); value != "" {
+			day, err := time.Parse("2006-01-02", value)
+			if err != nil {
+				httputil.NewError(ctx, http.StatusBadRequest, fmt.Errorf("invalid from %q: use YYYY-MM-DD", value))
+				return
+			}
+			from = day
+		}
+
+		report, err := c.service.GetComplianceReport(ctx.Request.Context(), id, from, to)
+		if err != nil {
+			httputil.NewError(ctx, statusFromError(err), err)
+			return
+		}
+
+		httputil.NewResponse(ctx, http.StatusOK, report)
+	}
+}
+
+func parseIncidentFilter(ctx *gin.Context) (domain.IncidentFilter, error) {
+	filter := domain.IncidentFilter{Status: ctx.Query("status")}
+
+	ids := map[string]*int64{
+		"warehouse_id": &filter.WarehouseId,
+		"section_id":   &filter.SectionId,
+	}
+	for name, target := range ids {
+		if value := ctx.Query(name); value != "" {
+			id, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || id <= 0 {
+				return filter, fmt.Errorf("invalid %s %q", name, value)
+			}
+			*target = id
+		}
+	}
+
+	times := map[string]**time.Time{
+		"from": &filter.From,
+		"to":   &filter.To,
+	}
+	for name, target := range times {
+		if value := ctx.Query(name); value != "" {
+			parsed, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return filter, fmt.Errorf("invalid %s %q: use RFC 3339", name, value)
+			}
+			*target = &parsed
+		}
+	}
+
+	return filter, nil
+}
+
+func parseSeriesQuery(ctx *gin.Context, now time.Time) (domain.SeriesQuery, error) {
+	query := domain.SeriesQuery{To: now}
+
+	if value := ctx.Query("to"); value != "" {
+		to, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return query, fmt.Errorf("invalid to %q: use RFC 3339", value)
+		}
+		query.To = to
+	}
+
+	query.From = query.To.Add(-domain.DefaultRange)
+	if value := ctx.Query("from"); value != "" {
+		from, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return query, fmt.Errorf("invalid from %q: use RFC 3339", value)
+		}
+		query.From = from
+	}
+
+	if value := ctx.Query("resolution"); value != "" {
+		resolution, err := parseResolution(value)
+		if err != nil {
+			return query, fmt.Errorf("%w %q: use e.g. 5m, 1h or 1d", domain.ErrInvalidResolution, value)
+		}
+		query.Resolution = resolution
+	}
+
+	if value := ctx.Query("product_batch_id"); value != "" {
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || id <= 0 {
+			return query, fmt.Errorf("invalid product_batch_id %q", value)
+		}
+		query.ProductBatchId = &id
+	}
+
+	return query, nil
+}
+
+// parseResolution accepts Go durations plus whole days, e.g. 1d.
+func parseResolution(value string) (time.Duration, error) {
+	if days := strings.TrimSuffix(value, "d"); days != value {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			return 0, errors.New("invalid days")
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(value)
+}
+
+func statusFromError(err error) int {
+	switch {
+	case errors.Is(err, section.ErrSectionNotFound),
+		errors.Is(err, productBatch.ErrProductBatchNotFound),
+		errors.Is(err, warehouse.ErrWarehouseNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrInvalidRange),
+		errors.Is(err, domain.ErrInvalidResolution),
+		errors.Is(err, domain.ErrInvalidIncidentStatus):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrNoReadings),
+		errors.Is(err, domain.ErrTooManyReadings),
+		errors.Is(err, domain.ErrInvalidReading):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
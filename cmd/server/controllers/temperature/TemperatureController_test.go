@@ -0,0 +1,195 @@
+package controllers_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	controllers "github.com/vinigracindo/mercado-fresco-stranger-strings/cmd/server/controllers/temperature"
+	section "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/section/domain"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/temperature/domain"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/temperature/domain/mocks"
+	warehouse "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/warehouse/domain"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/pkg/testutil"
+)
+
+const (
+	EndpointTemperatures        = "/api/v1/temperatures/"
+	EndpointSectionTemperatures = "/api/v1/sections/1/temperatures"
+)
+
+func setUpTemperatureRoutes(service domain.TemperatureService) *gin.Engine {
+	controller := controllers.NewTemperatureController(service)
+
+	r := testutil.SetUpRouter()
+	r.POST(EndpointTemperatures, controller.Ingest())
+	r.GET("/api/v1/sections/:id/temperatures", controller.GetSectionSeries())
+	r.GET("/api/v1/temperatures/incidents", controller.GetIncidents())
+	r.GET("/api/v1/warehouses/:id/compliance", controller.GetWarehouseCompliance())
+
+	return r
+}
+
+func TestTemperatureController_Ingest(t *testing.T) {
+	recordedAt := time.Date(2022, 8, 10, 12, 0, 0, 0, time.UTC)
+	batchId := int64(7)
+
+	t.Run("ingest_ok: should return 201 with the accepted readings", func(t *testing.T) {
+		service := mocks.NewTemperatureService(t)
+		service.
+			On("Ingest", mock.Anything, []domain.Reading{
+				{SectionId: 1, Temperature: 0, RecordedAt: recordedAt},
+				{SectionId: 1, ProductBatchId: &batchId, Temperature: -17.5, RecordedAt: recordedAt},
+			}).
+			Return(&domain.IngestResult{Accepted: 2}, nil).
+			Once()
+
+		body := []byte(`{"readings": [
+			{"section_id": 1, "temperature": 0, "recorded_at": "2022-08-10T12:00:00Z"},
+			{"section_id": 1, "product_batch_id": 7, "temperature": -17.5, "recorded_at": "2022-08-10T12:00:00Z"}
+		]}`)
+
+		response := testutil.ExecuteTestRequest(setUpTemperatureRoutes(service), http.MethodPost, EndpointTemperatures, body)
+
+		assert.Equal(t, http.StatusCreated, response.Code)
+		assert.JSONEq(t, `{"data": {"accepted": 2}}`, response.Body.String())
+	})
+
+	t.Run("ingest_missing_timestamp: should return 422", func(t *testing.T) {
+		service := mocks.NewTemperatureService(t)
+
+		body := []byte(`{"readings": [{"section_id": 1, "temperature": -18}]}`)
+
+		response := testutil.ExecuteTestRequest(setUpTemperatureRoutes(service), http.MethodPost, EndpointTemperatures, body)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+	})
+
+	t.Run("ingest_invalid: should return 422 when the service rejects a reading", func(t *testing.T) {
+		service := mocks.NewTemperatureService(t)
+		service.
+			On("Ingest", mock.Anything, mock.Anything).
+			Return(nil, fmt.Errorf("reading 0: %w: section 9 not found", domain.ErrInvalidReading)).
+			Once()
+
+		body := []byte(`{"readings": [{"section_id": 9, "temperature": -18, "recorded_at": "2022-08-10T12:00:00Z"}]}`)
+
+		response := testutil.ExecuteTestRequest(setUpTemperatureRoutes(service), http.MethodPost, EndpointTemperatures, body)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+	})
+}
+
+func TestTemperatureController_GetSectionSeries(t *testing.T) {
+	from := time.Date(2022, 8, 10, 0, 0, 0, 0, time.UTC)
+	to := from.Add(6 * time.Hour)
+
+	t.Run("get_series_ok: should return 200 with the series", func(t *testing.T) {
+		service := mocks.NewTemperatureService(t)
+		query := domain.SeriesQuery{SectionId: 1, From: from, To: to, Resolution: 24 * time.Hour}
+		series := domain.NewSeries(query, nil)
+
+		service.On("GetSeries", mock.Anything, query).Return(series, nil).Once()
+
+		url := EndpointSectionTemperatures + "?from=2022-08-10T00:00:00Z&to=2022-08-10T06:00:00Z&resolution=1d"
+		response := testutil.ExecuteTestRequest(setUpTemperatureRoutes(service), http.MethodGet, url, nil)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.JSONEq(t, testutil.StringJSON(map[string]interface{}{"data": series}), response.Body.String())
+	})
+
+	t.Run("get_series_default_range: should query the last 24 hours", func(t *testing.T) {
+		service := mocks.NewTemperatureService(t)
+		service.
+			On("GetSeries", mock.Anything, mock.MatchedBy(func(query domain.SeriesQuery) bool {
+				return query.To.Sub(query.From) == domain.DefaultRange && query.Resolution == 0
+			})).
+			Return(&domain.Series{}, nil).
+			Once()
+
+		response := testutil.ExecuteTestRequest(setUpTemperatureRoutes(service), http.MethodGet, EndpointSectionTemperatures, nil)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+
+	t.Run("get_series_bad_resolution: should return 400", func(t *testing.T) {
+		service := mocks.NewTemperatureService(t)
+
+		response := testutil.ExecuteTestRequest(setUpTemperatureRoutes(service), http.MethodGet, EndpointSectionTemperatures+"?resolution=often", nil)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("get_series_not_found: should return 404 for an unknown section", func(t *testing.T) {
+		service := mocks.NewTemperatureService(t)
+		service.On("GetSeries", mock.Anything, mock.Anything).Return(nil, section.ErrSectionNotFound).Once()
+
+		response := testutil.ExecuteTestRequest(setUpTemperatureRoutes(service), http.MethodGet, EndpointSectionTemperatures, nil)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
+func TestTemperatureController_GetIncidents(t *testing.T) {
+	t.Run("get_incidents_ok: should pass the filter on and return 200", func(t *testing.T) {
+		service := mocks.NewTemperatureService(t)
+		from := time.Date(2022, 8, 1, 0, 0, 0, 0, time.UTC)
+
+		service.
+			On("GetIncidents", mock.Anything, domain.IncidentFilter{WarehouseId: 2, Status: domain.IncidentOpen, From: &from}).
+			Return([]domain.Incident{}, nil).
+			Once()
+
+		url := "/api/v1/temperatures/incidents?warehouse_id=2&status=open&from=2022-08-01T00:00:00Z"
+		response := testutil.ExecuteTestRequest(setUpTemperatureRoutes(service), http.MethodGet, url, nil)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+
+	t.Run("get_incidents_invalid_status: should return 400", func(t *testing.T) {
+		service := mocks.NewTemperatureService(t)
+		service.On("GetIncidents", mock.Anything, mock.Anything).Return(nil, domain.ErrInvalidIncidentStatus).Once()
+
+		response := testutil.ExecuteTestRequest(setUpTemperatureRoutes(service), http.MethodGet, "/api/v1/temperatures/incidents?status=resolved", nil)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+}
+
+func TestTemperatureController_GetWarehouseCompliance(t *testing.T) {
+	t.Run("compliance_ok: should make both dates inclusive", func(t *testing.T) {
+		service := mocks.NewTemperatureService(t)
+		from := time.Date(2022, 8, 1, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2022, 8, 8, 0, 0, 0, 0, time.UTC)
+		report := domain.NewComplianceReport(2, "WH-2", from, to, to, []domain.SectionCompliance{}, []domain.Incident{})
+
+		service.On("GetComplianceReport", mock.Anything, int64(2), from, to).Return(report, nil).Once()
+
+		url := "/api/v1/warehouses/2/compliance?from=2022-08-01&to=2022-08-07"
+		response := testutil.ExecuteTestRequest(setUpTemperatureRoutes(service), http.MethodGet, url, nil)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.JSONEq(t, testutil.StringJSON(map[string]interface{}{"data": report}), response.Body.String())
+	})
+
+	t.Run("compliance_bad_date: should return 400", func(t *testing.T) {
+		service := mocks.NewTemperatureService(t)
+
+		response := testutil.ExecuteTestRequest(setUpTemperatureRoutes(service), http.MethodGet, "/api/v1/warehouses/2/compliance?from=01/08/2022", nil)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("compliance_not_found: should return 404", func(t *testing.T) {
+		service := mocks.NewTemperatureService(t)
+		service.On("GetComplianceReport", mock.Anything, int64(9), mock.Anything, mock.Anything).Return(nil, warehouse.ErrWarehouseNotFound).Once()
+
+		response := testutil.ExecuteTestRequest(setUpTemperatureRoutes(service), http.MethodGet, "/api/v1/warehouses/9/compliance", nil)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
@@ -1,11 +1,13 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 
+	locality "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/locality/domain"
 	warehouse "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/warehouse/domain"
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/pkg/httputil"
 )
@@ -20,8 +22,12 @@ type RequestWarehousePost struct {
 }
 
 type RequestWarehousePatch struct {
-	MinimunCapacity    int64   `json:"minimun_capacity" binding:"required"`
-	MinimunTemperature float64 `json:"minimun_temperature" binding:"required"`
+	Address            *string  `json:"address"`
+	Telephone          *string  `json:"telephone"`
+	WarehouseCode      *string  `json:"warehouse_code"`
+	MinimunCapacity    *int64   `json:"minimun_capacity"`
+	MinimunTemperature *float64 `json:"minimun_temperature"`
+	LocalityID         *int64   `json:"locality_id"`
 }
 
 type Warehouse struct {
@@ -44,6 +50,7 @@ func NewWarehouse(s warehouse.WarehouseService) *Warehouse {
 // @Success      201  {object}  warehouse.WarehouseModel
 // @Failure      409  {object}  httputil.HTTPError
 // @Failure      422  {object}  httputil.HTTPError
+// @Failure      500  {object}  httputil.HTTPError
 // @Router /warehouses [post]
 func (w Warehouse) CreateWarehouse() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
@@ -58,7 +65,7 @@ func (w Warehouse) CreateWarehouse() gin.HandlerFunc {
 		newWh, err := w.service.Create(ctx.Request.Context(), wh.Address, wh.Telephone, wh.WarehouseCode, wh.MinimunTemperature, wh.MinimunCapacity, wh.LocalityID)
 
 		if err != nil {
-			httputil.NewError(ctx, http.StatusConflict, err)
+			httputil.NewError(ctx, statusFromError(err), err)
 			return
 		}
 
@@ -160,42 +167,114 @@ func (w Warehouse) DeleteWarehouse() gin.HandlerFunc {
 
 // Warehouse godoc
 // @Summary      Update warehouse
-// @Description  Update warehouse
+// @Description  Update the fields sent; the others are left untouched
 // @Tags         Warehouse
 // @Accept       json
 // @Produce      json
 // @Param id path int true "Warehouse ID"
 // @Param Warehouse body RequestWarehousePatch true "Update warehouse"
-// @Success      201  {object} warehouse.WarehouseModel
+// @Success      200  {object} warehouse.WarehouseModel
+// @Failure      400  {object}  httputil.HTTPError
+// @Failure      404  {object}  httputil.HTTPError
 // @Failure      409  {object}  httputil.HTTPError
 // @Failure      422  {object}  httputil.HTTPError
 // @Router /warehouses/{id} [patch]
 func (w Warehouse) UpdateWarehouse() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
-		if paramId, check := ctx.Params.Get("id"); check {
-			id, err := strconv.Atoi(paramId)
+		id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+		if err != nil {
+			httputil.NewError(ctx, http.StatusBadRequest, err)
+			return
+		}
 
-			if err != nil {
-				httputil.NewError(ctx, http.StatusBadRequest, err)
-			}
+		var body RequestWarehousePatch
+		if err := ctx.ShouldBindJSON(&body); err != nil {
+			httputil.NewError(ctx, http.StatusBadRequest, err)
+			return
+		}
 
-			var body RequestWarehousePatch
-			var patchWh warehouse.WarehouseModel
+		patchWh, err := w.service.Update(ctx.Request.Context(), id, warehouse.WarehousePatch{
+			Address:            body.Address,
+			Telephone:          body.Telephone,
+			WarehouseCode:      body.WarehouseCode,
+			MinimunCapacity:    body.MinimunCapacity,
+			MinimunTemperature: body.MinimunTemperature,
+			LocalityID:         body.LocalityID,
+		})
+		if err != nil {
+			httputil.NewError(ctx, statusFromError(err), err)
+			return
+		}
 
-			if err := ctx.ShouldBindJSON(&body); err != nil {
-				httputil.NewError(ctx, http.StatusBadRequest, err)
-				return
-			}
+		httputil.NewResponse(ctx, http.StatusOK, patchWh)
+	}
+}
 
-			patchWh, err = w.service.UpdateTempAndCap(ctx.Request.Context(), int64(id), body.MinimunTemperature, body.MinimunCapacity)
+// Warehouse godoc
+// @Summary      Warehouse inventory
+// @Description  quantity and value at the latest purchase price per product, batches nearing expiry and section
+// @Description  utilization. Only active batches that still hold units count as stock; quarantined batches still take
+// @Description  up room in the utilization and are reported apart as quarantined_batches and quarantined_quantity
+// @Tags         Warehouse
+// @Produce      json
+// @Param id            path  int    true  "Warehouse ID"
+// @Param group_by      query string false "product_type or seller"
+// @Param expiring_days query int    false "Days ahead a batch counts as nearing expiry (default 7, max 365)"
+// @Success      200  {object} warehouse.WarehouseInventory
+// @Failure      400  {object}  httputil.HTTPError
+// @Failure      404  {object}  httputil.HTTPError
+// @Router /warehouses/{id}/inventory [get]
+func (w Warehouse) GetInventory() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+		if err != nil {
+			httputil.NewError(ctx, http.StatusBadRequest, err)
+			return
+		}
 
+		query := warehouse.InventoryQuery{
+			WarehouseId: id,
+			GroupBy:     ctx.Query("group_by"),
+		}
+
+		if value := ctx.Query("expiring_days"); value != "" {
+			days, err := strconv.Atoi(value)
 			if err != nil {
-				httputil.NewError(ctx, http.StatusNotFound, err)
+				httputil.NewError(ctx, http.StatusBadRequest, warehouse.ErrInvalidExpiringDays)
 				return
 			}
+			query.ExpiringDays = days
+		}
 
-			httputil.NewResponse(ctx, http.StatusOK, patchWh)
+		inventory, err := w.service.GetInventory(ctx.Request.Context(), query)
+		switch {
+		case errors.Is(err, warehouse.ErrWarehouseNotFound):
+			httputil.NewError(ctx, http.StatusNotFound, err)
+			return
+		case errors.Is(err, warehouse.ErrInvalidInventoryGroup), errors.Is(err, warehouse.ErrInvalidExpiringDays):
+			httputil.NewError(ctx, http.StatusBadRequest, err)
+			return
+		case err != nil:
+			httputil.NewError(ctx, http.StatusInternalServerError, err)
 			return
 		}
+
+		httputil.NewResponse(ctx, http.StatusOK, inventory)
+	}
+}
+
+func statusFromError(err error) int {
+	switch {
+	case errors.Is(err, warehouse.ErrWarehouseNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, warehouse.ErrWarehouseCodeTaken):
+		return http.StatusConflict
+	case errors.Is(err, warehouse.ErrInvalidAddress),
+		errors.Is(err, warehouse.ErrInvalidWarehouseCode),
+		errors.Is(err, warehouse.ErrInvalidTelephone),
+		errors.Is(err, locality.ErrLocalityNotFound):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
 	}
 }
@@ -8,7 +8,9 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	controllers "github.com/vinigracindo/mercado-fresco-stranger-strings/cmd/server/controllers/warehouse"
+	locality "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/locality/domain"
 	warehouse "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/warehouse/domain"
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/warehouse/domain/mocks"
 
@@ -84,16 +86,14 @@ func Test_Controller_Warehouse_CreateWarehouse(t *testing.T) {
 
 		service := mocks.NewWarehouseService(t)
 
-		errMsg := fmt.Errorf("error: already a warehouse with the code: %s", body.WarehouseCode)
-
 		service.On("Create",
-			context.TODO(),
+			mock.Anything,
 			body.Address,
 			body.Telephone,
 			body.WarehouseCode,
 			body.MinimunTemperature,
 			body.MinimunCapacity,
-			body.LocalityID).Return(warehouse.WarehouseModel{}, errMsg)
+			body.LocalityID).Return(warehouse.WarehouseModel{}, warehouse.ErrWarehouseCodeTaken)
 
 		controller := controllers.NewWarehouse(service)
 
@@ -108,6 +108,30 @@ func Test_Controller_Warehouse_CreateWarehouse(t *testing.T) {
 		assert.Equal(t, http.StatusConflict, response.Code)
 	})
 
+	t.Run("create_invalid: return 422 when a field has an invalid format or the locality does not exist", func(t *testing.T) {
+		for _, err := range []error{warehouse.ErrInvalidWarehouseCode, warehouse.ErrInvalidTelephone, locality.ErrLocalityNotFound} {
+			service := mocks.NewWarehouseService(t)
+			service.On("Create",
+				mock.Anything,
+				body.Address,
+				body.Telephone,
+				body.WarehouseCode,
+				body.MinimunTemperature,
+				body.MinimunCapacity,
+				body.LocalityID).Return(warehouse.WarehouseModel{}, err).Once()
+
+			controller := controllers.NewWarehouse(service)
+
+			requestBody, _ := json.Marshal(body)
+
+			r := testutil.SetUpRouter()
+			r.POST(EndpointWarehouse, controller.CreateWarehouse())
+			response := testutil.ExecuteTestRequest(r, http.MethodPost, EndpointWarehouse, requestBody)
+
+			assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+		}
+	})
+
 	t.Run("create_fail: when json object do not have all necessary fields, return 422 code", func(t *testing.T) {
 
 		controller := controllers.NewWarehouse(nil)
@@ -213,9 +237,20 @@ func Test_Controller_Warehouse_GetByID(t *testing.T) {
 }
 
 func Test_Controller_Warehouse_Update(t *testing.T) {
+	mincap := int64(66)
+	mintemp := 999.0
+	code := "od79"
+
 	body := controllers.RequestWarehousePatch{
-		MinimunCapacity:    66,
-		MinimunTemperature: 999.0,
+		MinimunCapacity:    &mincap,
+		MinimunTemperature: &mintemp,
+		WarehouseCode:      &code,
+	}
+
+	patch := warehouse.WarehousePatch{
+		MinimunCapacity:    &mincap,
+		MinimunTemperature: &mintemp,
+		WarehouseCode:      &code,
 	}
 
 	t.Run("update_ok: if warehouses was successfully updated return 200 code", func(t *testing.T) {
@@ -224,11 +259,7 @@ func Test_Controller_Warehouse_Update(t *testing.T) {
 		url := fmt.Sprintf("%s/%d", EndpointWarehouse, id)
 
 		service := mocks.NewWarehouseService(t)
-		service.On("UpdateTempAndCap",
-			context.TODO(),
-			int64(id),
-			999.0,
-			int64(66)).Return(listPossiblesWarehouses[0], nil)
+		service.On("Update", mock.Anything, id, patch).Return(listPossiblesWarehouses[0], nil)
 
 		controller := controllers.NewWarehouse(service)
 
@@ -251,14 +282,9 @@ func Test_Controller_Warehouse_Update(t *testing.T) {
 	t.Run("update_non_existent: if does not find warehouses with the id, return 404 code", func(t *testing.T) {
 		var id int64 = 9999
 		url := fmt.Sprintf("%s/%d", EndpointWarehouse, id)
-		errMsg := fmt.Errorf("erros: no warehouse was found with id %d", id)
 
 		service := mocks.NewWarehouseService(t)
-		service.On("UpdateTempAndCap",
-			context.TODO(),
-			int64(id),
-			999.0,
-			int64(66)).Return(listPossiblesWarehouses[0], errMsg)
+		service.On("Update", mock.Anything, id, patch).Return(warehouse.WarehouseModel{}, warehouse.ErrWarehouseNotFound)
 
 		controller := controllers.NewWarehouse(service)
 
@@ -273,6 +299,46 @@ func Test_Controller_Warehouse_Update(t *testing.T) {
 		assert.Equal(t, http.StatusNotFound, response.Code)
 	})
 
+	t.Run("update_duplicate_code: return 409 code when the code belongs to another warehouse", func(t *testing.T) {
+		var id int64 = 1
+		url := fmt.Sprintf("%s/%d", EndpointWarehouse, id)
+
+		service := mocks.NewWarehouseService(t)
+		service.On("Update", mock.Anything, id, patch).Return(warehouse.WarehouseModel{}, warehouse.ErrWarehouseCodeTaken)
+
+		controller := controllers.NewWarehouse(service)
+
+		requestBody, _ := json.Marshal(body)
+
+		r := testutil.SetUpRouter()
+
+		r.PATCH(EndpointWarehouse+"/:id", controller.UpdateWarehouse())
+
+		response := testutil.ExecuteTestRequest(r, http.MethodPatch, url, requestBody)
+
+		assert.Equal(t, http.StatusConflict, response.Code)
+	})
+
+	t.Run("update_invalid: return 422 code when a field is invalid or the locality does not exist", func(t *testing.T) {
+		var id int64 = 1
+		url := fmt.Sprintf("%s/%d", EndpointWarehouse, id)
+
+		for _, err := range []error{warehouse.ErrInvalidWarehouseCode, warehouse.ErrInvalidTelephone, warehouse.ErrInvalidAddress, locality.ErrLocalityNotFound} {
+			service := mocks.NewWarehouseService(t)
+			service.On("Update", mock.Anything, id, patch).Return(warehouse.WarehouseModel{}, err).Once()
+
+			controller := controllers.NewWarehouse(service)
+
+			requestBody, _ := json.Marshal(body)
+
+			r := testutil.SetUpRouter()
+			r.PATCH(EndpointWarehouse+"/:id", controller.UpdateWarehouse())
+			response := testutil.ExecuteTestRequest(r, http.MethodPatch, url, requestBody)
+
+			assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+		}
+	})
+
 	t.Run("update_non_id: return 422 code when id is of a invalid type", func(t *testing.T) {
 		url := fmt.Sprintf("%s/abc", EndpointWarehouse)
 		controller := controllers.NewWarehouse(nil)
@@ -355,3 +421,63 @@ func Test_Controller_Warehouse_Delete(t *testing.T) {
 		assert.Equal(t, http.StatusNoContent, response.Code)
 	})
 }
+
+func Test_Controller_Warehouse_GetInventory(t *testing.T) {
+	inventory := &warehouse.WarehouseInventory{
+		WarehouseId:     1,
+		WarehouseCode:   "od78",
+		ExpiringDays:    7,
+		Products:        []warehouse.InventoryProduct{},
+		ExpiringBatches: []warehouse.InventoryBatch{},
+		Sections:        []warehouse.InventorySection{},
+	}
+
+	t.Run("get_inventory_ok: should return code 200 with the inventory", func(t *testing.T) {
+		service := mocks.NewWarehouseService(t)
+		service.On("GetInventory", mock.Anything, warehouse.InventoryQuery{WarehouseId: 1, GroupBy: "seller", ExpiringDays: 30}).
+			Return(inventory, nil).
+			Once()
+		controller := controllers.NewWarehouse(service)
+
+		r := testutil.SetUpRouter()
+		r.GET(EndpointWarehouse+"/:id/inventory", controller.GetInventory())
+		response := testutil.ExecuteTestRequest(r, http.MethodGet, EndpointWarehouse+"/1/inventory?group_by=seller&expiring_days=30", []byte{})
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Contains(t, response.Body.String(), "\"warehouse_code\":\"od78\"")
+	})
+
+	t.Run("get_inventory_not_found: should return code 404", func(t *testing.T) {
+		service := mocks.NewWarehouseService(t)
+		service.On("GetInventory", mock.Anything, warehouse.InventoryQuery{WarehouseId: 9}).
+			Return(nil, warehouse.ErrWarehouseNotFound).
+			Once()
+		controller := controllers.NewWarehouse(service)
+
+		r := testutil.SetUpRouter()
+		r.GET(EndpointWarehouse+"/:id/inventory", controller.GetInventory())
+		response := testutil.ExecuteTestRequest(r, http.MethodGet, EndpointWarehouse+"/9/inventory", []byte{})
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+
+	t.Run("get_inventory_bad_request: should return code 400 for an invalid query", func(t *testing.T) {
+		service := mocks.NewWarehouseService(t)
+		service.On("GetInventory", mock.Anything, warehouse.InventoryQuery{WarehouseId: 1, GroupBy: "locality"}).
+			Return(nil, warehouse.ErrInvalidInventoryGroup).
+			Once()
+		controller := controllers.NewWarehouse(service)
+
+		r := testutil.SetUpRouter()
+		r.GET(EndpointWarehouse+"/:id/inventory", controller.GetInventory())
+
+		response := testutil.ExecuteTestRequest(r, http.MethodGet, EndpointWarehouse+"/1/inventory?group_by=locality", []byte{})
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+
+		response = testutil.ExecuteTestRequest(r, http.MethodGet, EndpointWarehouse+"/1/inventory?expiring_days=soon", []byte{})
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+
+		response = testutil.ExecuteTestRequest(r, http.MethodGet, EndpointWarehouse+"/abc/inventory", []byte{})
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+}
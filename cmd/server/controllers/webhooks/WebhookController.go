@@ -0,0 +1,224 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/webhooks/domain"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/pkg/httputil"
+)
+
+type RequestWebhookPost struct {
+	Url        string   `json:"url" binding:"required"`
+	Secret     string   `json:"secret" binding:"required"`
+	EventTypes []string `json:"event_types" binding:"required"`
+}
+
+type RequestWebhookPatch struct {
+	Url        *string   `json:"url"`
+	EventTypes *[]string `json:"event_types"`
+	Active     *bool     `json:"active"`
+}
+
+type WebhookController struct {
+	service domain.WebhookService
+}
+
+func NewWebhookController(service domain.WebhookService) *WebhookController {
+	return &WebhookController{service: service}
+}
+
+// GetAll godoc
+// @Summary      List all webhooks
+// @Description  Get all webhook subscriptions
+// @Tags         Webhooks
+// @Produce      json
+// @Success      200  {array}   domain.Webhook
+// @Failure      500  {object}  httputil.HTTPError
+// @Router /webhooks [get]
+func (c *WebhookController) GetAll() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		webhooks, err := c.service.GetAll(ctx.Request.Context())
+		if err != nil {
+			httputil.NewError(ctx, http.StatusInternalServerError, err)
+			return
+		}
+
+		httputil.NewResponse(ctx, http.StatusOK, webhooks)
+	}
+}
+
+// GetById godoc
+// @Summary      Get webhook by ID
+// @Description  Get webhook subscription by ID
+// @Tags         Webhooks
+// @Produce      json
+// @Param id path int true "Webhook ID"
+// @Success      200  {object}  domain.Webhook
+// @Failure      400  {object}  httputil.HTTPError
+// @Failure      404  {object}  httputil.HTTPError
+// @Router /webhooks/{id} [get]
+func (c *WebhookController) GetById() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+		if err != nil {
+			httputil.NewError(ctx, http.StatusBadRequest, errors.New("invalid id"))
+			return
+		}
+
+		webhook, err := c.service.GetById(ctx.Request.Context(), id)
+		if err != nil {
+			httputil.NewError(ctx, http.StatusNotFound, err)
+			return
+		}
+
+		httputil.NewResponse(ctx, http.StatusOK, webhook)
+	}
+}
+
+// Create godoc
+// @Summary      Create webhook
+// @Description  Subscribe an url to domain events. Deliveries are signed with HMAC-SHA256 of the body using the secret.
+// @Tags         Webhooks
+// @Accept       json
+// @Produce      json
+// @Param Webhook body RequestWebhookPost true "Create webhook"
+// @Success      201  {object}  domain.Webhook
+// @Failure      422  {object}  httputil.HTTPError
+// @Router /webhooks [post]
+func (c *WebhookController) Create() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var request RequestWebhookPost
+
+		if err := ctx.ShouldBindJSON(&request); err != nil {
+			httputil.NewError(ctx, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		webhook, err := c.service.Create(ctx.Request.Context(), request.Url, request.Secret, request.EventTypes)
+		if err != nil {
+			httputil.NewError(ctx, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		httputil.NewResponse(ctx, http.StatusCreated, webhook)
+	}
+}
+
+// Update godoc
+// @Summary      Update webhook
+// @Description  Update webhook url, event types or active flag
+// @Tags         Webhooks
+// @Accept       json
+// @Produce      json
+// @Param id path int true "Webhook ID"
+// @Param Webhook body RequestWebhookPatch true "Update webhook"
+// @Success      200  {object}  domain.Webhook
+// @Failure      400  {object}  httputil.HTTPError
+// @Failure      404  {object}  httputil.HTTPError
+// @Failure      422  {object}  httputil.HTTPError
+// @Router /webhooks/{id} [patch]
+func (c *WebhookController) Update() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+		if err != nil {
+			httputil.NewError(ctx, http.StatusBadRequest, errors.New("invalid id"))
+			return
+		}
+
+		var request RequestWebhookPatch
+
+		if err := ctx.ShouldBindJSON(&request); err != nil {
+			httputil.NewError(ctx, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		webhook, err := c.service.Update(ctx.Request.Context(), id, request.Url, request.EventTypes, request.Active)
+
+		if errors.Is(err, domain.ErrWebhookNotFound) {
+			httputil.NewError(ctx, http.StatusNotFound, err)
+			return
+		}
+
+		if err != nil {
+			httputil.NewError(ctx, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		httputil.NewResponse(ctx, http.StatusOK, webhook)
+	}
+}
+
+// Delete godoc
+// @Summary      Delete webhook
+// @Description  Delete webhook subscription by ID
+// @Tags         Webhooks
+// @Param id path int true "Webhook ID"
+// @Success      204
+// @Failure      400  {object}  httputil.HTTPError
+// @Failure      404  {object}  httputil.HTTPError
+// @Router /webhooks/{id} [delete]
+func (c *WebhookController) Delete() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+		if err != nil {
+			httputil.NewError(ctx, http.StatusBadRequest, errors.New("invalid id"))
+			return
+		}
+
+		if err := c.service.Delete(ctx.Request.Context(), id); err != nil {
+			httputil.NewError(ctx, http.StatusNotFound, err)
+			return
+		}
+
+		httputil.NewResponse(ctx, http.StatusNoContent, nil)
+	}
+}
+
+// GetDeadLetters godoc
+// @Summary      List dead-lettered deliveries
+// @Description  List deliveries that exhausted their retries
+// @Tags         Webhooks
+// @Produce      json
+// @Success      200  {array}   domain.Delivery
+// @Failure      500  {object}  httputil.HTTPError
+// @Router /webhooks/deadLetters [get]
+func (c *WebhookController) GetDeadLetters() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		deliveries, err := c.service.GetDeadLetters(ctx.Request.Context())
+		if err != nil {
+			httputil.NewError(ctx, http.StatusInternalServerError, err)
+			return
+		}
+
+		httputil.NewResponse(ctx, http.StatusOK, deliveries)
+	}
+}
+
+// RetryDelivery godoc
+// @Summary      Retry dead-lettered delivery
+// @Description  Put a dead-lettered delivery back in the queue with a fresh set of attempts
+// @Tags         Webhooks
+// @Param id path int true "Delivery ID"
+// @Success      204
+// @Failure      400  {object}  httputil.HTTPError
+// @Failure      404  {object}  httputil.HTTPError
+// @Router /webhooks/deadLetters/{id}/retry [post]
+func (c *WebhookController) RetryDelivery() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+		if err != nil {
+			httputil.NewError(ctx, http.StatusBadRequest, errors.New("invalid id"))
+			return
+		}
+
+		if err := c.service.RetryDelivery(ctx.Request.Context(), id); err != nil {
+			httputil.NewError(ctx, http.StatusNotFound, err)
+			return
+		}
+
+		httputil.NewResponse(ctx, http.StatusNoContent, nil)
+	}
+}
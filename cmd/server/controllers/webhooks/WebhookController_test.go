@@ -0,0 +1,251 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	controllers "github.com/vinigracindo/mercado-fresco-stranger-strings/cmd/server/controllers/webhooks"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/webhooks/domain"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/webhooks/domain/mocks"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/pkg/testutil"
+)
+
+const EndpointWebhooks = "/api/v1/webhooks"
+
+var mockWebhook = domain.Webhook{
+	Id:         1,
+	Url:        "https://erp.example.com/hooks",
+	Secret:     "s3cr3t",
+	EventTypes: []string{"purchase_order.created"},
+	Active:     true,
+}
+
+func setUpWebhookRoutes(service domain.WebhookService) *gin.Engine {
+	controller := controllers.NewWebhookController(service)
+
+	r := testutil.SetUpRouter()
+	group := r.Group(EndpointWebhooks)
+	group.GET("/deadLetters", controller.GetDeadLetters())
+	group.POST("/deadLetters/:id/retry", controller.RetryDelivery())
+	group.GET("/", controller.GetAll())
+	group.GET("/:id", controller.GetById())
+	group.POST("/", controller.Create())
+	group.PATCH("/:id", controller.Update())
+	group.DELETE("/:id", controller.Delete())
+
+	return r
+}
+
+func TestWebhookController_Create(t *testing.T) {
+	t.Run("create_ok: should return 201 with the webhook", func(t *testing.T) {
+		service := mocks.NewWebhookService(t)
+		service.
+			On("Create", mock.Anything, mockWebhook.Url, mockWebhook.Secret, mockWebhook.EventTypes).
+			Return(&mockWebhook, nil).
+			Once()
+
+		body, _ := json.Marshal(controllers.RequestWebhookPost{
+			Url:        mockWebhook.Url,
+			Secret:     mockWebhook.Secret,
+			EventTypes: mockWebhook.EventTypes,
+		})
+
+		r := testutil.SetUpRouter()
+		r.POST(EndpointWebhooks, controllers.NewWebhookController(service).Create())
+
+		response := testutil.ExecuteTestRequest(r, http.MethodPost, EndpointWebhooks, body)
+
+		assert.Equal(t, http.StatusCreated, response.Code)
+		assert.JSONEq(t, testutil.StringJSON(map[string]interface{}{"data": mockWebhook}), response.Body.String())
+	})
+
+	t.Run("create_unprocessable_entity: should return 422 when body is invalid", func(t *testing.T) {
+		service := mocks.NewWebhookService(t)
+
+		r := testutil.SetUpRouter()
+		r.POST(EndpointWebhooks, controllers.NewWebhookController(service).Create())
+
+		response := testutil.ExecuteTestRequest(r, http.MethodPost, EndpointWebhooks, []byte(`{"url": "https://erp.example.com"}`))
+
+		assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+	})
+
+	t.Run("create_invalid: should return 422 when the service rejects the webhook", func(t *testing.T) {
+		service := mocks.NewWebhookService(t)
+		service.
+			On("Create", mock.Anything, "ftp://x", "s", []string{"purchase_order.created"}).
+			Return(nil, domain.ErrInvalidURL).
+			Once()
+
+		body, _ := json.Marshal(controllers.RequestWebhookPost{Url: "ftp://x", Secret: "s", EventTypes: []string{"purchase_order.created"}})
+
+		r := testutil.SetUpRouter()
+		r.POST(EndpointWebhooks, controllers.NewWebhookController(service).Create())
+
+		response := testutil.ExecuteTestRequest(r, http.MethodPost, EndpointWebhooks, body)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+	})
+}
+
+func TestWebhookController_GetById(t *testing.T) {
+	t.Run("find_by_id_existent: should return the webhook", func(t *testing.T) {
+		service := mocks.NewWebhookService(t)
+		service.On("GetById", mock.Anything, int64(1)).Return(&mockWebhook, nil).Once()
+
+		r := setUpWebhookRoutes(service)
+
+		response := testutil.ExecuteTestRequest(r, http.MethodGet, EndpointWebhooks+"/1", nil)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.JSONEq(t, testutil.StringJSON(map[string]interface{}{"data": mockWebhook}), response.Body.String())
+	})
+
+	t.Run("find_by_id_non_existent: should return 404", func(t *testing.T) {
+		service := mocks.NewWebhookService(t)
+		service.On("GetById", mock.Anything, int64(1)).Return(nil, domain.ErrWebhookNotFound).Once()
+
+		r := setUpWebhookRoutes(service)
+
+		response := testutil.ExecuteTestRequest(r, http.MethodGet, EndpointWebhooks+"/1", nil)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+
+	t.Run("find_by_id_invalid_id: should return 400", func(t *testing.T) {
+		service := mocks.NewWebhookService(t)
+
+		r := setUpWebhookRoutes(service)
+
+		response := testutil.ExecuteTestRequest(r, http.MethodGet, EndpointWebhooks+"/abc", nil)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+}
+
+func TestWebhookController_Update(t *testing.T) {
+	t.Run("update_ok: should return the updated webhook", func(t *testing.T) {
+		active := false
+		updated := mockWebhook
+		updated.Active = false
+
+		service := mocks.NewWebhookService(t)
+		service.
+			On("Update", mock.Anything, int64(1), (*string)(nil), (*[]string)(nil), &active).
+			Return(&updated, nil).
+			Once()
+
+		r := setUpWebhookRoutes(service)
+
+		response := testutil.ExecuteTestRequest(r, http.MethodPatch, EndpointWebhooks+"/1", []byte(`{"active": false}`))
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.JSONEq(t, testutil.StringJSON(map[string]interface{}{"data": updated}), response.Body.String())
+	})
+
+	t.Run("update_non_existent: should return 404", func(t *testing.T) {
+		service := mocks.NewWebhookService(t)
+		service.
+			On("Update", mock.Anything, int64(1), mock.Anything, mock.Anything, mock.Anything).
+			Return(nil, domain.ErrWebhookNotFound).
+			Once()
+
+		r := setUpWebhookRoutes(service)
+
+		response := testutil.ExecuteTestRequest(r, http.MethodPatch, EndpointWebhooks+"/1", []byte(`{"active": false}`))
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+
+	t.Run("update_invalid: should return 422 when the service rejects the change", func(t *testing.T) {
+		service := mocks.NewWebhookService(t)
+		service.
+			On("Update", mock.Anything, int64(1), mock.Anything, mock.Anything, mock.Anything).
+			Return(nil, domain.ErrUnknownEventType).
+			Once()
+
+		r := setUpWebhookRoutes(service)
+
+		response := testutil.ExecuteTestRequest(r, http.MethodPatch, EndpointWebhooks+"/1", []byte(`{"event_types": ["unknown"]}`))
+
+		assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+	})
+}
+
+func TestWebhookController_Delete(t *testing.T) {
+	t.Run("delete_ok: should return 204", func(t *testing.T) {
+		service := mocks.NewWebhookService(t)
+		service.On("Delete", mock.Anything, int64(1)).Return(nil).Once()
+
+		r := setUpWebhookRoutes(service)
+
+		response := testutil.ExecuteTestRequest(r, http.MethodDelete, EndpointWebhooks+"/1", nil)
+
+		assert.Equal(t, http.StatusNoContent, response.Code)
+	})
+
+	t.Run("delete_non_existent: should return 404", func(t *testing.T) {
+		service := mocks.NewWebhookService(t)
+		service.On("Delete", mock.Anything, int64(1)).Return(domain.ErrWebhookNotFound).Once()
+
+		r := setUpWebhookRoutes(service)
+
+		response := testutil.ExecuteTestRequest(r, http.MethodDelete, EndpointWebhooks+"/1", nil)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
+func TestWebhookController_DeadLetters(t *testing.T) {
+	t.Run("get_dead_letters_ok: should list dead-lettered deliveries", func(t *testing.T) {
+		deliveries := []domain.Delivery{{Id: 1, WebhookId: 1, EventId: 1, Status: domain.DeliveryStatusDead, Attempts: 8}}
+
+		service := mocks.NewWebhookService(t)
+		service.On("GetDeadLetters", mock.Anything).Return(deliveries, nil).Once()
+
+		r := setUpWebhookRoutes(service)
+
+		response := testutil.ExecuteTestRequest(r, http.MethodGet, EndpointWebhooks+"/deadLetters", nil)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.JSONEq(t, testutil.StringJSON(map[string]interface{}{"data": deliveries}), response.Body.String())
+	})
+
+	t.Run("get_dead_letters_error: should return 500", func(t *testing.T) {
+		service := mocks.NewWebhookService(t)
+		service.On("GetDeadLetters", mock.Anything).Return(nil, errors.New("any error")).Once()
+
+		r := setUpWebhookRoutes(service)
+
+		response := testutil.ExecuteTestRequest(r, http.MethodGet, EndpointWebhooks+"/deadLetters", nil)
+
+		assert.Equal(t, http.StatusInternalServerError, response.Code)
+	})
+
+	t.Run("retry_ok: should return 204", func(t *testing.T) {
+		service := mocks.NewWebhookService(t)
+		service.On("RetryDelivery", mock.Anything, int64(1)).Return(nil).Once()
+
+		r := setUpWebhookRoutes(service)
+
+		response := testutil.ExecuteTestRequest(r, http.MethodPost, EndpointWebhooks+"/deadLetters/1/retry", nil)
+
+		assert.Equal(t, http.StatusNoContent, response.Code)
+	})
+
+	t.Run("retry_non_existent: should return 404", func(t *testing.T) {
+		service := mocks.NewWebhookService(t)
+		service.On("RetryDelivery", mock.Anything, int64(1)).Return(domain.ErrDeliveryNotFound).Once()
+
+		r := setUpWebhookRoutes(service)
+
+		response := testutil.ExecuteTestRequest(r, http.MethodPost, EndpointWebhooks+"/deadLetters/1/retry", nil)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
@@ -0,0 +1,69 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/config"
+	jobService "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/jobs/service"
+	productBatchRepository "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_batch/repository/mariadb"
+	productBatchService "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_batch/service"
+	temperatureRepository "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/temperature/repository/mariadb"
+	temperatureService "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/temperature/service"
+	webhookRepository "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/webhooks/repository/mariadb"
+	webhookService "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/webhooks/service"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/libs/logger"
+)
+
+const logRetention = 30 * 24 * time.Hour
+
+func registerJobs(scheduler *jobService.Scheduler, db *sql.DB) {
+	// Webhook delivery of outbox events
+	webhookDispatcher := webhookService.NewDispatcher(
+		webhookRepository.NewMariadbWebhookRepository(db),
+		webhookRepository.NewMariadbDeliveryRepository(db),
+		&http.Client{Timeout: 10 * time.Second},
+	)
+
+	mustRegister(scheduler, "webhooks.dispatch", "@every 10s", 5*time.Minute, webhookDispatcher.Dispatch)
+
+	// Expiry alerts for stored product batches
+	expiryAlertDays, err := config.ExpiryAlertDays()
+	if err != nil {
+		log.Fatal("invalid expiry alert days: ", err)
+	}
+	expiryScanner := productBatchService.NewExpiryScanner(
+		productBatchRepository.NewMariadbProductBatchRepository(db),
+		logger.Logger,
+		expiryAlertDays,
+	)
+
+	mustRegister(scheduler, "product_batches.expiry_scan", "0 * * * *", 5*time.Minute, expiryScanner.Scan)
+
+	// Hourly rollup of section temperature readings
+	temperatureRetention, err := config.TemperatureRawRetention()
+	if err != nil {
+		log.Fatal("invalid temperature retention: ", err)
+	}
+	temperatureDownsampler := temperatureService.NewDownsampler(
+		temperatureRepository.NewMariadbTemperatureRepository(db),
+		logger.Logger,
+		temperatureRetention,
+	)
+
+	mustRegister(scheduler, "temperatures.downsample", "30 * * * *", 10*time.Minute, temperatureDownsampler.Run)
+
+	mustRegister(scheduler, "logs.retention", "0 3 * * *", 10*time.Minute, func(ctx context.Context) error {
+		_, err := logger.Logger.DeleteBefore(ctx, time.Now().Add(-logRetention))
+		return err
+	})
+}
+
+func mustRegister(scheduler *jobService.Scheduler, name string, spec string, timeout time.Duration, run jobService.JobFunc) {
+	if err := scheduler.Register(name, spec, timeout, run); err != nil {
+		log.Fatal("could not register job "+name, err)
+	}
+}
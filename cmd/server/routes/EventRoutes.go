@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"database/sql"
+
+	"github.com/gin-gonic/gin"
+	controllers "github.com/vinigracindo/mercado-fresco-stranger-strings/cmd/server/controllers/events"
+	repository "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/events/repository/mariadb"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/events/service"
+)
+
+func EventRoutes(routes *gin.RouterGroup, db *sql.DB) {
+	eventRepository := repository.NewMariadbEventRepository(db)
+	eventService := service.NewEventService(eventRepository)
+	eventController := controllers.NewEventController(eventService)
+
+	routes.GET("/stream", eventController.Stream())
+}
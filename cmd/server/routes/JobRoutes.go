@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	controllers "github.com/vinigracindo/mercado-fresco-stranger-strings/cmd/server/controllers/jobs"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/jobs/domain"
+)
+
+// JobRoutes takes the scheduler instead of the database because the jobs
+// and their running state live in the scheduler started by the server.
+func JobRoutes(routes *gin.RouterGroup, scheduler domain.JobService) {
+	jobController := controllers.NewJobController(scheduler)
+
+	routes.GET("/", jobController.GetAll())
+	routes.GET("/:name/runs", jobController.GetRuns())
+	routes.POST("/:name/pause", jobController.Pause())
+	routes.POST("/:name/resume", jobController.Resume())
+	routes.POST("/:name/trigger", jobController.Trigger())
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	controllers "github.com/vinigracindo/mercado-fresco-stranger-strings/cmd/server/controllers/product_batch"
+	employeeRepository "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/employees/repository/mariadb"
 	productRepository "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product/repository/mariadb"
 	repository "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_batch/repository/mariadb"
 	sectionRepository "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/section/repository/mariadb"
@@ -16,9 +17,19 @@ func ProductBatchRoutes(routes *gin.RouterGroup, db *sql.DB) {
 	productBatchRepository := repository.NewMariadbProductBatchRepository(db)
 	productRepository := productRepository.CreateProductRepository(db)
 	sectionRepository := sectionRepository.NewMariadbSectionRepository(db)
+	employeeRepository := employeeRepository.NewMariaDBEmployeeRepository(db)
 
-	productBatchService := service.NewProductBatchService(productBatchRepository, productRepository, sectionRepository)
+	productBatchService := service.NewProductBatchService(productBatchRepository, productRepository, sectionRepository, employeeRepository)
 	productBatchController := controllers.NewProductBatchController(productBatchService)
 
 	routes.POST("/", productBatchController.Create())
+	routes.GET("/", productBatchController.GetAll())
+	routes.GET("/expiring", productBatchController.GetExpiringReport())
+	routes.POST("/suggestSection", productBatchController.SuggestSection())
+	routes.GET("/:id", productBatchController.GetById())
+	routes.PATCH("/:id", productBatchController.Update())
+	routes.DELETE("/:id", productBatchController.Delete())
+	routes.PUT("/:id/status", productBatchController.UpdateStatus())
+	routes.POST("/:id/transfer", productBatchController.Transfer())
+	routes.GET("/:id/movements", productBatchController.GetMovements())
 }
@@ -2,28 +2,49 @@ package routes
 
 import (
 	"database/sql"
+	"log"
+
 	"github.com/gin-gonic/gin"
 	controllers "github.com/vinigracindo/mercado-fresco-stranger-strings/cmd/server/controllers/product"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/config"
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product/repository/mariadb"
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product/service"
 
+	productRecordsControllers "github.com/vinigracindo/mercado-fresco-stranger-strings/cmd/server/controllers/product_records"
 	mariadbProductRecords "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_records/repository/mariadb"
+	productRecordsService "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_records/service"
+	mariadbProductType "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_type/repository/mariadb"
+	mariadbSeller "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/seller/repository/mariadb"
 )
 
 func ProductRoutes(routes *gin.RouterGroup, db *sql.DB) {
 
 	productRepository := mariadb.CreateProductRepository(db)
 	productRecordsRepository := mariadbProductRecords.CreateProductRecordsRepository(db)
+	productTypeRepository := mariadbProductType.NewMariadbProductTypeRepository(db)
+	sellerRepository := mariadbSeller.NewMariaDBSellerRepository(db)
+
+	codeFormat, err := config.ProductCodeFormat()
+	if err != nil {
+		log.Fatal("invalid product code format: ", err)
+	}
 
-	productService := service.CreateProductService(productRepository, productRecordsRepository)
+	productService := service.CreateProductService(productRepository, productRecordsRepository, productTypeRepository, sellerRepository, codeFormat)
 	productController := controllers.CreateProductController(productService)
 
+	recordsService := productRecordsService.CreateProductRecordsService(productRecordsRepository, productRepository)
+	recordsController := productRecordsControllers.CreateProductRecordsController(recordsService)
+
 	routes.GET("/", productController.GetAll())
 	routes.GET("/:id", productController.GetById())
 	routes.POST("/", productController.Create())
-	routes.PATCH("/:id", productController.UpdateDescription())
+	routes.PATCH("/:id", productController.Update())
+	routes.PUT("/:id", productController.Replace())
 	routes.DELETE("/:id", productController.Delete())
 
 	routes.GET("/reportRecords", productController.GetReportProductRecords())
 
+	routes.GET("/:id/records", recordsController.GetByProductId())
+	routes.GET("/:id/price", recordsController.GetPriceAt())
+
 }
@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"database/sql"
+
+	"github.com/gin-gonic/gin"
+	controllers "github.com/vinigracindo/mercado-fresco-stranger-strings/cmd/server/controllers/product_type"
+	repository "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_type/repository/mariadb"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_type/service"
+)
+
+func ProductTypeRoutes(routes *gin.RouterGroup, db *sql.DB) {
+	productTypeRepository := repository.NewMariadbProductTypeRepository(db)
+	productTypeService := service.NewProductTypeService(productTypeRepository)
+	productTypeController := controllers.NewProductTypeController(productTypeService)
+
+	routes.GET("/", productTypeController.GetAll())
+	routes.GET("/:id", productTypeController.GetById())
+	routes.POST("/", productTypeController.Create())
+	routes.PATCH("/:id", productTypeController.Update())
+	routes.DELETE("/:id", productTypeController.Delete())
+}
@@ -5,21 +5,30 @@ import (
 
 	"github.com/gin-gonic/gin"
 	controllers "github.com/vinigracindo/mercado-fresco-stranger-strings/cmd/server/controllers/section"
+	productTypeRepository "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_type/repository/mariadb"
 	repository "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/section/repository/mariadb"
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/section/service"
+	warehouseRepository "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/warehouse/repository/mariadb"
 )
 
 func SectionRoutes(routes *gin.RouterGroup, db *sql.DB) {
 	sectionRepository := repository.NewMariadbSectionRepository(db)
-	sectionService := service.NewServiceSection(sectionRepository)
+	productTypeRepository := productTypeRepository.NewMariadbProductTypeRepository(db)
+	warehouseRepository := warehouseRepository.NewMariadbWarehouseRepository(db)
+	sectionService := service.NewServiceSection(sectionRepository, productTypeRepository, warehouseRepository)
 	sectionController := controllers.NewSection(sectionService)
+	temperatureController := newTemperatureController(db)
 
 	//report product by section route
 	routes.GET("/reportProducts", sectionController.GetReportProductsBySection())
+	routes.GET("/reportOccupancy", sectionController.GetOccupancy())
+	routes.GET("/byNumber", sectionController.GetByNumber())
 
 	routes.DELETE("/:id", sectionController.Delete())
-	routes.PATCH("/:id", sectionController.UpdateCurrentCapacity())
+	routes.POST("/:id/restore", sectionController.Restore())
+	routes.PATCH("/:id", sectionController.Update())
 	routes.POST("/", sectionController.Create())
 	routes.GET("/:id", sectionController.GetById())
+	routes.GET("/:id/temperatures", temperatureController.GetSectionSeries())
 	routes.GET("/", sectionController.GetAll())
 }
@@ -19,4 +19,7 @@ func SellerRoutes(routes *gin.RouterGroup, db *sql.DB) {
 	routes.POST("/", sellerController.Create())
 	routes.PATCH("/:id", sellerController.Update())
 	routes.DELETE("/:id", sellerController.Delete())
+
+	routes.GET("/:id/products", sellerController.GetProducts())
+	routes.GET("/:id/summary", sellerController.GetSummary())
 }
@@ -0,0 +1,44 @@
+package routes
+
+import (
+	"database/sql"
+	"log"
+
+	"github.com/gin-gonic/gin"
+	controllers "github.com/vinigracindo/mercado-fresco-stranger-strings/cmd/server/controllers/temperature"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/config"
+	productRepository "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product/repository/mariadb"
+	productBatchRepository "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_batch/repository/mariadb"
+	sectionRepository "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/section/repository/mariadb"
+	repository "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/temperature/repository/mariadb"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/temperature/service"
+	warehouseRepository "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/warehouse/repository/mariadb"
+)
+
+func TemperatureRoutes(routes *gin.RouterGroup, db *sql.DB) {
+	temperatureController := newTemperatureController(db)
+
+	routes.GET("/incidents", temperatureController.GetIncidents())
+	routes.POST("/", temperatureController.Ingest())
+}
+
+// newTemperatureController is shared with the section and warehouse routes,
+// which serve the temperature series of a section and the cold-chain
+// compliance of a warehouse.
+func newTemperatureController(db *sql.DB) *controllers.TemperatureController {
+	excursionDuration, err := config.ExcursionDuration()
+	if err != nil {
+		log.Fatal("invalid excursion duration: ", err)
+	}
+
+	temperatureService := service.NewTemperatureService(
+		repository.NewMariadbTemperatureRepository(db),
+		sectionRepository.NewMariadbSectionRepository(db),
+		productBatchRepository.NewMariadbProductBatchRepository(db),
+		productRepository.CreateProductRepository(db),
+		warehouseRepository.NewMariadbWarehouseRepository(db),
+		excursionDuration,
+	)
+
+	return controllers.NewTemperatureController(temperatureService)
+}
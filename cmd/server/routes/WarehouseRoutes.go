@@ -5,17 +5,21 @@ import (
 
 	"github.com/gin-gonic/gin"
 	controllers "github.com/vinigracindo/mercado-fresco-stranger-strings/cmd/server/controllers/warehouse"
+	localityRepository "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/locality/repository/mariadb"
 	repository "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/warehouse/repository/mariadb"
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/warehouse/services"
 )
 
 func WarehouseRoutes(routes *gin.RouterGroup, db *sql.DB) {
 	warehouseRepository := repository.NewMariadbWarehouseRepository(db)
-	warehouseService := services.NewWarehouseService(warehouseRepository)
+	warehouseService := services.NewWarehouseService(warehouseRepository, localityRepository.NewMariadbLocalityRepository(db))
 	warehouseController := controllers.NewWarehouse(warehouseService)
+	temperatureController := newTemperatureController(db)
 
 	routes.GET("/", warehouseController.GetAllWarehouse())
 	routes.GET("/:id", warehouseController.GetWarehouseByID())
+	routes.GET("/:id/compliance", temperatureController.GetWarehouseCompliance())
+	routes.GET("/:id/inventory", warehouseController.GetInventory())
 	routes.POST("/", warehouseController.CreateWarehouse())
 	routes.DELETE("/:id", warehouseController.DeleteWarehouse())
 	routes.PATCH("/:id", warehouseController.UpdateWarehouse())
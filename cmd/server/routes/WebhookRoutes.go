@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"database/sql"
+
+	"github.com/gin-gonic/gin"
+	controllers "github.com/vinigracindo/mercado-fresco-stranger-strings/cmd/server/controllers/webhooks"
+	repository "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/webhooks/repository/mariadb"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/webhooks/service"
+)
+
+func WebhookRoutes(routes *gin.RouterGroup, db *sql.DB) {
+	webhookRepository := repository.NewMariadbWebhookRepository(db)
+	deliveryRepository := repository.NewMariadbDeliveryRepository(db)
+
+	webhookService := service.NewWebhookService(webhookRepository, deliveryRepository)
+	webhookController := controllers.NewWebhookController(webhookService)
+
+	routes.GET("/deadLetters", webhookController.GetDeadLetters())
+	routes.POST("/deadLetters/:id/retry", webhookController.RetryDelivery())
+
+	routes.GET("/", webhookController.GetAll())
+	routes.GET("/:id", webhookController.GetById())
+	routes.POST("/", webhookController.Create())
+	routes.PATCH("/:id", webhookController.Update())
+	routes.DELETE("/:id", webhookController.Delete())
+}
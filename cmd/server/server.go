@@ -1,7 +1,9 @@
 package server
 
 import (
+	"context"
 	"fmt"
+
 	docs "github.com/vinigracindo/mercado-fresco-stranger-strings/docs/specs"
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/libs/logger"
 
@@ -11,6 +13,8 @@ import (
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/cmd/server/http/ping"
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/cmd/server/routes"
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/config"
+	jobRepository "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/jobs/repository/mariadb"
+	jobService "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/jobs/service"
 )
 
 type APIServer struct{}
@@ -26,6 +30,11 @@ func (api *APIServer) Run(port int) {
 
 	logger.InitializeLogger(db)
 
+	// Background jobs
+	jobScheduler := jobService.NewScheduler(jobRepository.NewMariadbJobRepository(db))
+	registerJobs(jobScheduler, db)
+	go jobScheduler.Start(context.Background())
+
 	router := gin.Default()
 
 	// Swagger
@@ -38,10 +47,12 @@ func (api *APIServer) Run(port int) {
 
 	apiV1 := router.Group("api/v1")
 	routes.SectionRoutes(apiV1.Group("/sections"), db)
+	routes.TemperatureRoutes(apiV1.Group("/temperatures"), db)
 	routes.EmployeeRoutes(apiV1.Group("/employees"), db)
 	routes.InboundOrdersRoutes(apiV1.Group("/inboundOrders"), db)
 	routes.ProductRoutes(apiV1.Group("/products"), db)
 	routes.ProductRecordsRoutes(apiV1.Group("/productRecords"), db)
+	routes.ProductTypeRoutes(apiV1.Group("/productTypes"), db)
 	routes.WarehouseRoutes(apiV1.Group("/warehouses"), db)
 	routes.SellerRoutes(apiV1.Group("/sellers"), db)
 	routes.BuyerRoutes(apiV1.Group("/buyers"), db)
@@ -49,6 +60,9 @@ func (api *APIServer) Run(port int) {
 	routes.LocalityRoutes(apiV1.Group("/localities"), db)
 	routes.ProductBatchRoutes(apiV1.Group("/productBatches"), db)
 	routes.PurchaseOrdersRoutes(apiV1.Group("/purchaseOrders"), db)
+	routes.WebhookRoutes(apiV1.Group("/webhooks"), db)
+	routes.EventRoutes(apiV1.Group("/events"), db)
+	routes.JobRoutes(apiV1.Group("/jobs"), jobScheduler)
 
 	router.Run(fmt.Sprintf(":%d", port))
 }
@@ -0,0 +1,28 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	productBatchDomain "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_batch/domain"
+)
+
+// ExpiryAlertDays reads how many days ahead of the due date the expiry scan
+// alerts, defaulting to productBatchDomain.DefaultExpiringDays.
+func ExpiryAlertDays() (int, error) {
+	value := os.Getenv("EXPIRY_ALERT_DAYS")
+	if value == "" {
+		return productBatchDomain.DefaultExpiringDays, nil
+	}
+
+	days, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("EXPIRY_ALERT_DAYS: %w", err)
+	}
+	if days < 1 || days > productBatchDomain.MaxExpiringDays {
+		return 0, fmt.Errorf("EXPIRY_ALERT_DAYS: %w", productBatchDomain.ErrInvalidDays)
+	}
+
+	return days, nil
+}
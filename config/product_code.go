@@ -0,0 +1,45 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	productDomain "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product/domain"
+)
+
+// ProductCodeFormat reads the product code format from the environment,
+// falling back to productDomain.DefaultCodeFormat for unset variables.
+func ProductCodeFormat() (productDomain.CodeFormat, error) {
+	format := productDomain.DefaultCodeFormat
+
+	if value := os.Getenv("PRODUCT_CODE_PREFIX_BY"); value != "" {
+		format.PrefixBy = value
+	}
+	if value := os.Getenv("PRODUCT_CODE_PREFIX"); value != "" {
+		format.Prefix = value
+	}
+	if value := os.Getenv("PRODUCT_CODE_DIGITS"); value != "" {
+		digits, err := strconv.Atoi(value)
+		if err != nil {
+			return format, fmt.Errorf("PRODUCT_CODE_DIGITS: %w", err)
+		}
+		format.Digits = digits
+	}
+	if value := os.Getenv("PRODUCT_CODE_CHECK_DIGIT"); value != "" {
+		checkDigit, err := strconv.ParseBool(value)
+		if err != nil {
+			return format, fmt.Errorf("PRODUCT_CODE_CHECK_DIGIT: %w", err)
+		}
+		format.CheckDigit = checkDigit
+	}
+	if value := os.Getenv("PRODUCT_CODE_STRICT"); value != "" {
+		strict, err := strconv.ParseBool(value)
+		if err != nil {
+			return format, fmt.Errorf("PRODUCT_CODE_STRICT: %w", err)
+		}
+		format.Strict = strict
+	}
+
+	return format, format.Valid()
+}
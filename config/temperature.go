@@ -0,0 +1,50 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	temperatureDomain "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/temperature/domain"
+)
+
+// TemperatureRawRetention reads for how many days temperature readings are
+// kept as recorded before being rolled up hourly, defaulting to
+// temperatureDomain.DefaultRawRetentionDays.
+func TemperatureRawRetention() (time.Duration, error) {
+	days := temperatureDomain.DefaultRawRetentionDays
+
+	if value := os.Getenv("TEMPERATURE_RAW_RETENTION_DAYS"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return 0, fmt.Errorf("TEMPERATURE_RAW_RETENTION_DAYS: %w", err)
+		}
+		if parsed < 1 {
+			return 0, fmt.Errorf("TEMPERATURE_RAW_RETENTION_DAYS: must be at least 1, got %d", parsed)
+		}
+		days = parsed
+	}
+
+	return time.Duration(days) * 24 * time.Hour, nil
+}
+
+// ExcursionDuration reads for how many minutes a section or product batch
+// has to stay above its threshold before an incident opens, defaulting to
+// temperatureDomain.DefaultExcursionMinutes.
+func ExcursionDuration() (time.Duration, error) {
+	minutes := temperatureDomain.DefaultExcursionMinutes
+
+	if value := os.Getenv("EXCURSION_MIN_DURATION_MINUTES"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return 0, fmt.Errorf("EXCURSION_MIN_DURATION_MINUTES: %w", err)
+		}
+		if parsed < 0 {
+			return 0, fmt.Errorf("EXCURSION_MIN_DURATION_MINUTES: must not be negative, got %d", parsed)
+		}
+		minutes = parsed
+	}
+
+	return time.Duration(minutes) * time.Minute, nil
+}
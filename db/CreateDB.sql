@@ -88,7 +88,11 @@ ENGINE = InnoDB;
 CREATE TABLE IF NOT EXISTS `mercadofresco`.`product_types` (
   `id` INT NOT NULL AUTO_INCREMENT,
   `description` VARCHAR(255) NOT NULL,
-  PRIMARY KEY (`id`))
+  `category` VARCHAR(20) NOT NULL,
+  `minimum_temperature` DECIMAL(19,2) NOT NULL,
+  `maximum_temperature` DECIMAL(19,2) NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `description_UNIQUE` (`description` ASC) VISIBLE)
 ENGINE = InnoDB;
 
 
@@ -160,10 +164,12 @@ CREATE TABLE IF NOT EXISTS `mercadofresco`.`sections` (
   `maximum_capacity` INT NOT NULL,
   `warehouse_id` INT NOT NULL,
   `product_type_id` INT NOT NULL,
+  `temperature_recorded_at` DATETIME(6) NULL,
+  `archived_at` DATETIME(6) NULL,
   PRIMARY KEY (`id`),
   INDEX `product_type_id_idx` (`product_type_id` ASC) VISIBLE,
   INDEX `warehouse_id_idx` (`warehouse_id` ASC) VISIBLE,
-  UNIQUE INDEX `section_number_UNIQUE` (`section_number` ASC) VISIBLE,
+  UNIQUE INDEX `warehouse_section_number_UNIQUE` (`warehouse_id` ASC, `section_number` ASC) VISIBLE,
   CONSTRAINT `fk_product_type_sections`
     FOREIGN KEY (`product_type_id`)
     REFERENCES `mercadofresco`.`product_types` (`id`)
@@ -192,9 +198,15 @@ CREATE TABLE IF NOT EXISTS `mercadofresco`.`product_batches` (
   `minimum_temperature` DECIMAL(19,2) NOT NULL,
   `product_id` INT NOT NULL,
   `section_id` INT NOT NULL,
+  `status` VARCHAR(20) NOT NULL DEFAULT 'active',
+  `status_reason` VARCHAR(255) NULL,
+  `status_changed_at` DATETIME(6) NULL,
+  `expiry_alert` VARCHAR(20) NULL,
+  `temperature_recorded_at` DATETIME(6) NULL,
   PRIMARY KEY (`id`),
   INDEX `product_id_idx` (`product_id` ASC) VISIBLE,
   INDEX `section_id_idx` (`section_id` ASC) VISIBLE,
+  INDEX `status_due_date_idx` (`status` ASC, `due_date` ASC) VISIBLE,
   CONSTRAINT `fk_product_product_batches`
     FOREIGN KEY (`product_id`)
     REFERENCES `mercadofresco`.`products` (`id`)
@@ -208,6 +220,95 @@ CREATE TABLE IF NOT EXISTS `mercadofresco`.`product_batches` (
 ENGINE = InnoDB;
 
 
+-- -----------------------------------------------------
+-- Table `mercadofresco`.`section_temperatures`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `mercadofresco`.`section_temperatures` (
+  `id` BIGINT NOT NULL AUTO_INCREMENT,
+  `section_id` INT NOT NULL,
+  `product_batch_id` INT NULL,
+  `temperature` DECIMAL(19,2) NOT NULL,
+  `recorded_at` DATETIME(6) NOT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `section_recorded_at_idx` (`section_id` ASC, `product_batch_id` ASC, `recorded_at` ASC) VISIBLE,
+  INDEX `recorded_at_idx` (`recorded_at` ASC) VISIBLE,
+  CONSTRAINT `fk_section_section_temperatures`
+    FOREIGN KEY (`section_id`)
+    REFERENCES `mercadofresco`.`sections` (`id`)
+    ON DELETE CASCADE
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_product_batch_section_temperatures`
+    FOREIGN KEY (`product_batch_id`)
+    REFERENCES `mercadofresco`.`product_batches` (`id`)
+    ON DELETE CASCADE
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+
+-- -----------------------------------------------------
+-- Table `mercadofresco`.`section_temperature_rollups`
+-- Hourly aggregates of readings older than the raw retention.
+-- product_batch_id is 0 for readings of the section itself.
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `mercadofresco`.`section_temperature_rollups` (
+  `section_id` INT NOT NULL,
+  `product_batch_id` INT NOT NULL DEFAULT 0,
+  `bucket_start` DATETIME(6) NOT NULL,
+  `min_temperature` DECIMAL(19,2) NOT NULL,
+  `max_temperature` DECIMAL(19,2) NOT NULL,
+  `sum_temperature` DECIMAL(19,2) NOT NULL,
+  `readings` INT NOT NULL,
+  PRIMARY KEY (`section_id`, `product_batch_id`, `bucket_start`),
+  CONSTRAINT `fk_section_section_temperature_rollups`
+    FOREIGN KEY (`section_id`)
+    REFERENCES `mercadofresco`.`sections` (`id`)
+    ON DELETE CASCADE
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+
+-- -----------------------------------------------------
+-- Table `mercadofresco`.`temperature_incidents`
+-- Excursions of a section, or of a product batch in it, above its
+-- threshold. Pending incidents have not lasted long enough to alert.
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `mercadofresco`.`temperature_incidents` (
+  `id` INT NOT NULL AUTO_INCREMENT,
+  `section_id` INT NOT NULL,
+  `product_batch_id` INT NULL,
+  `threshold` DECIMAL(19,2) NOT NULL,
+  `status` VARCHAR(20) NOT NULL,
+  `started_at` DATETIME(6) NOT NULL,
+  `ended_at` DATETIME(6) NULL,
+  `peak_temperature` DECIMAL(19,2) NOT NULL,
+  `peak_at` DATETIME(6) NOT NULL,
+  `last_reading_at` DATETIME(6) NOT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `subject_status_idx` (`section_id` ASC, `product_batch_id` ASC, `status` ASC) VISIBLE,
+  INDEX `started_at_idx` (`started_at` ASC) VISIBLE,
+  CONSTRAINT `fk_section_temperature_incidents`
+    FOREIGN KEY (`section_id`)
+    REFERENCES `mercadofresco`.`sections` (`id`)
+    ON DELETE CASCADE
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+
+-- -----------------------------------------------------
+-- Table `mercadofresco`.`temperature_incident_batches`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `mercadofresco`.`temperature_incident_batches` (
+  `incident_id` INT NOT NULL,
+  `product_batch_id` INT NOT NULL,
+  PRIMARY KEY (`incident_id`, `product_batch_id`),
+  CONSTRAINT `fk_incident_temperature_incident_batches`
+    FOREIGN KEY (`incident_id`)
+    REFERENCES `mercadofresco`.`temperature_incidents` (`id`)
+    ON DELETE CASCADE
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+
 -- -----------------------------------------------------
 -- Table `mercadofresco`.`product_records`
 -- -----------------------------------------------------
@@ -395,6 +496,47 @@ CREATE TABLE IF NOT EXISTS `mercadofresco`.`inbound_orders` (
 ENGINE = InnoDB;
 
 
+-- -----------------------------------------------------
+-- Table `mercadofresco`.`stock_movements`
+-- -----------------------------------------------------
+-- Append-only ledger: the quantities of a batch add up to its current
+-- quantity. reference_type names what reference_id points at
+-- (inbound_order, product_batch or purchase_order). A batch with
+-- movements besides its unlinked receipt cannot be deleted;
+-- discarding it closes its ledger.
+CREATE TABLE IF NOT EXISTS `mercadofresco`.`stock_movements` (
+  `id` BIGINT NOT NULL AUTO_INCREMENT,
+  `product_batch_id` INT NOT NULL,
+  `section_id` INT NOT NULL,
+  `movement_type` VARCHAR(20) NOT NULL,
+  `quantity` INT NOT NULL,
+  `employee_id` INT NULL,
+  `reason` VARCHAR(255) NOT NULL,
+  `reference_type` VARCHAR(50) NULL,
+  `reference_id` INT NULL,
+  `created_at` DATETIME(6) NOT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `product_batch_created_at_idx` (`product_batch_id` ASC, `created_at` ASC) VISIBLE,
+  INDEX `section_id_idx` (`section_id` ASC) VISIBLE,
+  INDEX `employee_id_idx` (`employee_id` ASC) VISIBLE,
+  CONSTRAINT `fk_product_batch_stock_movements`
+    FOREIGN KEY (`product_batch_id`)
+    REFERENCES `mercadofresco`.`product_batches` (`id`)
+    ON DELETE RESTRICT
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_section_stock_movements`
+    FOREIGN KEY (`section_id`)
+    REFERENCES `mercadofresco`.`sections` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_employee_stock_movements`
+    FOREIGN KEY (`employee_id`)
+    REFERENCES `mercadofresco`.`employees` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+
 -- -----------------------------------------------------
 -- Table `mercadofresco`.`roles`
 -- -----------------------------------------------------
@@ -413,7 +555,30 @@ CREATE TABLE IF NOT EXISTS `mercadofresco`.`users` (
   `id` INT NOT NULL AUTO_INCREMENT,
   `passoword` VARCHAR(255) NOT NULL,
   `username` VARCHAR(255) NOT NULL,
-  PRIMARY KEY (`id`))
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `username_UNIQUE` (`username` ASC) VISIBLE)
+ENGINE = InnoDB;
+
+
+-- -----------------------------------------------------
+-- Table `mercadofresco`.`api_keys`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `mercadofresco`.`api_keys` (
+  `id` INT NOT NULL AUTO_INCREMENT,
+  `user_id` INT NOT NULL,
+  `name` VARCHAR(255) NOT NULL,
+  `prefix` VARCHAR(20) NOT NULL,
+  `key_hash` CHAR(64) NOT NULL,
+  `created_at` DATETIME(6) NOT NULL,
+  `revoked_at` DATETIME(6) NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `key_hash_UNIQUE` (`key_hash` ASC) VISIBLE,
+  INDEX `api_keys_user_id_idx` (`user_id` ASC) VISIBLE,
+  CONSTRAINT `fk_user_api_keys`
+    FOREIGN KEY (`user_id`)
+    REFERENCES `mercadofresco`.`users` (`id`)
+    ON DELETE CASCADE
+    ON UPDATE NO ACTION)
 ENGINE = InnoDB;
 
 
@@ -453,6 +618,125 @@ CREATE TABLE IF NOT EXISTS `mercadofresco`.`logs` (
     ENGINE = InnoDB;
 
 
+-- -----------------------------------------------------
+-- Table `mercadofresco`.`audit_log`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `mercadofresco`.`audit_log` (
+  `id` INT NOT NULL AUTO_INCREMENT,
+  `action` VARCHAR(255) NOT NULL,
+  `entity_type` VARCHAR(255) NOT NULL,
+  `entity_id` INT NOT NULL,
+  `actor` VARCHAR(255) NOT NULL,
+  `reason` VARCHAR(255) NOT NULL,
+  `details` TEXT NOT NULL,
+  `created_at` DATETIME(6) NOT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `audit_entity_idx` (`entity_type` ASC, `entity_id` ASC) VISIBLE)
+ENGINE = InnoDB;
+
+
+-- -----------------------------------------------------
+-- Table `mercadofresco`.`outbox_events`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `mercadofresco`.`outbox_events` (
+  `id` INT NOT NULL AUTO_INCREMENT,
+  `event_type` VARCHAR(255) NOT NULL,
+  `aggregate_id` INT NOT NULL,
+  `warehouse_id` INT NULL,
+  `payload` TEXT NOT NULL,
+  `created_at` DATETIME(6) NOT NULL,
+  `dispatched_at` DATETIME(6) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `dispatched_at_idx` (`dispatched_at` ASC) VISIBLE,
+  INDEX `outbox_warehouse_id_idx` (`warehouse_id` ASC) VISIBLE)
+ENGINE = InnoDB;
+
+
+-- -----------------------------------------------------
+-- Table `mercadofresco`.`webhooks`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `mercadofresco`.`webhooks` (
+  `id` INT NOT NULL AUTO_INCREMENT,
+  `url` VARCHAR(255) NOT NULL,
+  `secret` VARCHAR(255) NOT NULL,
+  `event_types` VARCHAR(255) NOT NULL,
+  `active` TINYINT(1) NOT NULL DEFAULT 1,
+  `created_at` DATETIME(6) NOT NULL,
+  PRIMARY KEY (`id`))
+ENGINE = InnoDB;
+
+
+-- -----------------------------------------------------
+-- Table `mercadofresco`.`webhook_deliveries`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `mercadofresco`.`webhook_deliveries` (
+  `id` INT NOT NULL AUTO_INCREMENT,
+  `webhook_id` INT NOT NULL,
+  `outbox_event_id` INT NOT NULL,
+  `status` VARCHAR(45) NOT NULL,
+  `attempts` INT NOT NULL DEFAULT 0,
+  `next_attempt_at` DATETIME(6) NOT NULL,
+  `last_error` VARCHAR(255) NOT NULL DEFAULT '',
+  `last_status_code` INT NOT NULL DEFAULT 0,
+  `delivered_at` DATETIME(6) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `webhook_id_idx` (`webhook_id` ASC) VISIBLE,
+  INDEX `outbox_event_id_idx` (`outbox_event_id` ASC) VISIBLE,
+  INDEX `status_next_attempt_at_idx` (`status` ASC, `next_attempt_at` ASC) VISIBLE,
+  CONSTRAINT `fk_webhook_webhook_deliveries`
+    FOREIGN KEY (`webhook_id`)
+    REFERENCES `mercadofresco`.`webhooks` (`id`)
+    ON DELETE CASCADE
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_outbox_event_webhook_deliveries`
+    FOREIGN KEY (`outbox_event_id`)
+    REFERENCES `mercadofresco`.`outbox_events` (`id`)
+    ON DELETE CASCADE
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+
+-- -----------------------------------------------------
+-- Table `mercadofresco`.`jobs`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `mercadofresco`.`jobs` (
+  `name` VARCHAR(100) NOT NULL,
+  `paused` TINYINT(1) NOT NULL DEFAULT 0,
+  `updated_at` DATETIME(6) NOT NULL,
+  PRIMARY KEY (`name`))
+ENGINE = InnoDB;
+
+
+-- -----------------------------------------------------
+-- Table `mercadofresco`.`job_leases`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `mercadofresco`.`job_leases` (
+  `name` VARCHAR(100) NOT NULL,
+  `owner` VARCHAR(255) NOT NULL,
+  `expires_at` DATETIME(6) NOT NULL,
+  `last_slot` DATETIME(6) NULL,
+  PRIMARY KEY (`name`))
+ENGINE = InnoDB;
+
+
+-- -----------------------------------------------------
+-- Table `mercadofresco`.`job_runs`
+-- -----------------------------------------------------
+CREATE TABLE IF NOT EXISTS `mercadofresco`.`job_runs` (
+  `id` INT NOT NULL AUTO_INCREMENT,
+  `job_name` VARCHAR(100) NOT NULL,
+  `owner` VARCHAR(255) NOT NULL,
+  `trigger` VARCHAR(20) NOT NULL,
+  `status` VARCHAR(20) NOT NULL,
+  `started_at` DATETIME(6) NOT NULL,
+  `finished_at` DATETIME(6) NULL,
+  `duration_ms` BIGINT NULL,
+  `error` TEXT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `job_runs_job_name_idx` (`job_name` ASC, `id` DESC) VISIBLE)
+ENGINE = InnoDB;
+
+
 
 SET SQL_MODE=@OLD_SQL_MODE;
 SET FOREIGN_KEY_CHECKS=@OLD_FOREIGN_KEY_CHECKS;
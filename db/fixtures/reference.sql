@@ -0,0 +1,11 @@
+-- Reference data the fixtures and the generator rely on.
+INSERT IGNORE INTO `mercadofresco`.`product_types`
+  (`id`, `description`, `category`, `minimum_temperature`, `maximum_temperature`) VALUES
+  (1, 'Frozen', 'frozen', -25.00, -18.00),
+  (2, 'Refrigerated', 'refrigerated', 0.00, 8.00),
+  (3, 'Ambient', 'ambient', 15.00, 25.00);
+
+INSERT IGNORE INTO `mercadofresco`.`order_status` (`id`, `description`) VALUES
+  (1, 'Pending'),
+  (2, 'Shipped'),
+  (3, 'Delivered');
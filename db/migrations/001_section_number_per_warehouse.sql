@@ -0,0 +1,9 @@
+-- -----------------------------------------------------
+-- Section numbers are unique per warehouse instead of
+-- globally. Apply with: mfctl migrate -file <this file>
+-- -----------------------------------------------------
+USE `mercadofresco`;
+
+ALTER TABLE `sections`
+  DROP INDEX `section_number_UNIQUE`,
+  ADD UNIQUE INDEX `warehouse_section_number_UNIQUE` (`warehouse_id` ASC, `section_number` ASC) VISIBLE;
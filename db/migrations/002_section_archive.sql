@@ -0,0 +1,8 @@
+-- -----------------------------------------------------
+-- Archived sections are hidden from the listings but keep
+-- their history. Apply with: mfctl migrate -file <this file>
+-- -----------------------------------------------------
+USE `mercadofresco`;
+
+ALTER TABLE `sections`
+  ADD COLUMN `archived_at` DATETIME(6) NULL;
@@ -0,0 +1,9 @@
+-- -----------------------------------------------------
+-- Job leases remember the last scheduled slot that ran, so
+-- replicas do not run it twice. Apply with:
+-- mfctl migrate -file <this file>
+-- -----------------------------------------------------
+USE `mercadofresco`;
+
+ALTER TABLE `job_leases`
+  ADD COLUMN `last_slot` DATETIME(6) NULL;
@@ -0,0 +1,44 @@
+-- -----------------------------------------------------
+-- Deleting a product batch no longer erases its stock
+-- ledger, and batches created before the ledger get the
+-- receipt that brings their movements up to the current
+-- quantity. Apply with: mfctl migrate -file <this file>
+-- -----------------------------------------------------
+USE `mercadofresco`;
+
+ALTER TABLE `stock_movements`
+  DROP FOREIGN KEY `fk_product_batch_stock_movements`;
+
+ALTER TABLE `stock_movements`
+  ADD CONSTRAINT `fk_product_batch_stock_movements`
+    FOREIGN KEY (`product_batch_id`)
+    REFERENCES `product_batches` (`id`)
+    ON DELETE RESTRICT
+    ON UPDATE NO ACTION;
+
+INSERT INTO `stock_movements` (
+  `product_batch_id`,
+  `section_id`,
+  `movement_type`,
+  `quantity`,
+  `employee_id`,
+  `reason`,
+  `reference_type`,
+  `reference_id`,
+  `created_at`)
+SELECT
+  pb.`id`,
+  pb.`section_id`,
+  'receipt',
+  pb.`current_quantity` - IFNULL(SUM(sm.`quantity`), 0),
+  NULL,
+  'backfilled receipt',
+  NULL,
+  NULL,
+  pb.`manufacturing_date`
+FROM `product_batches` pb
+LEFT JOIN `stock_movements` sm ON sm.`product_batch_id` = pb.`id`
+WHERE NOT EXISTS (
+  SELECT 1 FROM `stock_movements` r
+  WHERE r.`product_batch_id` = pb.`id` AND r.`movement_type` = 'receipt')
+GROUP BY pb.`id`, pb.`section_id`, pb.`current_quantity`, pb.`manufacturing_date`;
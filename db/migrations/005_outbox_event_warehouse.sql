@@ -0,0 +1,10 @@
+-- -----------------------------------------------------
+-- Outbox events carry the warehouse they belong to, so the
+-- live activity stream can filter them. Apply with:
+-- mfctl migrate -file <this file>
+-- -----------------------------------------------------
+USE `mercadofresco`;
+
+ALTER TABLE `outbox_events`
+  ADD COLUMN `warehouse_id` INT NULL,
+  ADD INDEX `outbox_warehouse_id_idx` (`warehouse_id` ASC) VISIBLE;
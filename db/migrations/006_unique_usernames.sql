@@ -0,0 +1,9 @@
+-- -----------------------------------------------------
+-- Usernames are unique, since mfctl creates users and API
+-- keys by name. Duplicated usernames have to be renamed
+-- before applying. Apply with: mfctl migrate -file <this file>
+-- -----------------------------------------------------
+USE `mercadofresco`;
+
+ALTER TABLE `users`
+  ADD UNIQUE INDEX `username_UNIQUE` (`username` ASC) VISIBLE;
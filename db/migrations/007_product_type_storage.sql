@@ -0,0 +1,19 @@
+-- -----------------------------------------------------
+-- Product types get a storage category and temperature
+-- range, and unique descriptions. Existing types become
+-- ambient with a range wide enough not to block any
+-- section; review them with PATCH /productTypes/{id}.
+-- Apply with: mfctl migrate -file <this file>
+-- -----------------------------------------------------
+USE `mercadofresco`;
+
+ALTER TABLE `product_types`
+  ADD COLUMN `category` VARCHAR(20) NOT NULL DEFAULT 'ambient',
+  ADD COLUMN `minimum_temperature` DECIMAL(19,2) NOT NULL DEFAULT -50,
+  ADD COLUMN `maximum_temperature` DECIMAL(19,2) NOT NULL DEFAULT 50,
+  ADD UNIQUE INDEX `description_UNIQUE` (`description` ASC) VISIBLE;
+
+ALTER TABLE `product_types`
+  ALTER COLUMN `category` DROP DEFAULT,
+  ALTER COLUMN `minimum_temperature` DROP DEFAULT,
+  ALTER COLUMN `maximum_temperature` DROP DEFAULT;
@@ -0,0 +1,13 @@
+-- -----------------------------------------------------
+-- Product batches can be quarantined or discarded and
+-- remember the last expiry alert sent. Existing batches
+-- are active. Apply with: mfctl migrate -file <this file>
+-- -----------------------------------------------------
+USE `mercadofresco`;
+
+ALTER TABLE `product_batches`
+  ADD COLUMN `status` VARCHAR(20) NOT NULL DEFAULT 'active',
+  ADD COLUMN `status_reason` VARCHAR(255) NULL,
+  ADD COLUMN `status_changed_at` DATETIME(6) NULL,
+  ADD COLUMN `expiry_alert` VARCHAR(20) NULL,
+  ADD INDEX `status_due_date_idx` (`status` ASC, `due_date` ASC) VISIBLE;
@@ -0,0 +1,12 @@
+-- -----------------------------------------------------
+-- Sections and product batches remember when their current
+-- temperature was last recorded by the telemetry ingest.
+-- Apply with: mfctl migrate -file <this file>
+-- -----------------------------------------------------
+USE `mercadofresco`;
+
+ALTER TABLE `sections`
+  ADD COLUMN `temperature_recorded_at` DATETIME(6) NULL;
+
+ALTER TABLE `product_batches`
+  ADD COLUMN `temperature_recorded_at` DATETIME(6) NULL;
@@ -0,0 +1,57 @@
+package domain
+
+import "time"
+
+// Cursor is the position of a stream. Outbox ids come from AUTO_INCREMENT,
+// which hands them out at insert time and not at commit: a transaction that
+// commits late makes its event visible after others with higher ids. So a
+// stream re-reads every event above Floor and skips the ones it already sent.
+type Cursor struct {
+	// LastId is the highest event id sent.
+	LastId int64
+	// Floor is the LastId the stream had one commit window ago. Every event
+	// at or below it was visible by then.
+	Floor int64
+
+	sent  map[int64]bool
+	marks []cursorMark
+}
+
+type cursorMark struct {
+	at     time.Time
+	lastId int64
+}
+
+func NewCursor(lastId int64) *Cursor {
+	return &Cursor{LastId: lastId, Floor: lastId, sent: map[int64]bool{}}
+}
+
+// Sent tells whether the event was already sent on this stream.
+func (c *Cursor) Sent(id int64) bool {
+	return id <= c.Floor || c.sent[id]
+}
+
+func (c *Cursor) MarkSent(id int64) {
+	c.sent[id] = true
+	if id > c.LastId {
+		c.LastId = id
+	}
+}
+
+// Advance records the position reached at now and raises Floor to the
+// position reached one window ago, forgetting the ids sent below it.
+func (c *Cursor) Advance(now time.Time, window time.Duration) {
+	c.marks = append(c.marks, cursorMark{at: now, lastId: c.LastId})
+
+	limit := now.Add(-window)
+	for len(c.marks) > 0 && !c.marks[0].at.After(limit) {
+		c.Floor = c.marks[0].lastId
+		c.marks = c.marks[1:]
+	}
+
+	for id := range c.sent {
+		if id <= c.Floor {
+			delete(c.sent, id)
+		}
+	}
+}
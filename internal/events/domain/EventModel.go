@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+type Event struct {
+	Id          int64           `json:"id"`
+	EventType   string          `json:"type"`
+	AggregateId int64           `json:"aggregate_id"`
+	WarehouseId int64           `json:"warehouse_id,omitempty"`
+	Data        json.RawMessage `json:"data"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+// EventFilter narrows a stream down to one warehouse and/or a set of event
+// types. Zero values mean no filtering.
+type EventFilter struct {
+	WarehouseId int64
+	EventTypes  []string
+}
+
+type EventRepository interface {
+	GetLastId(ctx context.Context) (int64, error)
+	GetAfter(ctx context.Context, lastId int64, filter EventFilter, limit int64) ([]Event, error)
+}
+
+type EventService interface {
+	GetCursor(ctx context.Context, lastEventId *int64, filter EventFilter) (*Cursor, error)
+	GetAfter(ctx context.Context, cursor *Cursor, filter EventFilter) ([]Event, error)
+}
@@ -0,0 +1,8 @@
+package domain
+
+import "errors"
+
+var (
+	ErrUnknownEventType   = errors.New("unknown event type")
+	ErrInvalidLastEventId = errors.New("invalid Last-Event-ID")
+)
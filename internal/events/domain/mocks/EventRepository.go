@@ -0,0 +1,74 @@
+// Code generated by mockery v2.13.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	domain "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/events/domain"
+)
+
+// EventRepository is an autogenerated mock type for the EventRepository type
+type EventRepository struct {
+	mock.Mock
+}
+
+// GetAfter provides a mock function with given fields: ctx, lastId, filter, limit
+func (_m *EventRepository) GetAfter(ctx context.Context, lastId int64, filter domain.EventFilter, limit int64) ([]domain.Event, error) {
+	ret := _m.Called(ctx, lastId, filter, limit)
+
+	var r0 []domain.Event
+	if rf, ok := ret.Get(0).(func(context.Context, int64, domain.EventFilter, int64) []domain.Event); ok {
+		r0 = rf(ctx, lastId, filter, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Event)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, domain.EventFilter, int64) error); ok {
+		r1 = rf(ctx, lastId, filter, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLastId provides a mock function with given fields: ctx
+func (_m *EventRepository) GetLastId(ctx context.Context) (int64, error) {
+	ret := _m.Called(ctx)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewEventRepository interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewEventRepository creates a new instance of EventRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewEventRepository(t mockConstructorTestingTNewEventRepository) *EventRepository {
+	mock := &EventRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
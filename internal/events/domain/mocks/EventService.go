@@ -0,0 +1,76 @@
+// Code generated by mockery v2.13.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	domain "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/events/domain"
+)
+
+// EventService is an autogenerated mock type for the EventService type
+type EventService struct {
+	mock.Mock
+}
+
+// GetAfter provides a mock function with given fields: ctx, cursor, filter
+func (_m *EventService) GetAfter(ctx context.Context, cursor *domain.Cursor, filter domain.EventFilter) ([]domain.Event, error) {
+	ret := _m.Called(ctx, cursor, filter)
+
+	var r0 []domain.Event
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Cursor, domain.EventFilter) []domain.Event); ok {
+		r0 = rf(ctx, cursor, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Event)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.Cursor, domain.EventFilter) error); ok {
+		r1 = rf(ctx, cursor, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCursor provides a mock function with given fields: ctx, lastEventId, filter
+func (_m *EventService) GetCursor(ctx context.Context, lastEventId *int64, filter domain.EventFilter) (*domain.Cursor, error) {
+	ret := _m.Called(ctx, lastEventId, filter)
+
+	var r0 *domain.Cursor
+	if rf, ok := ret.Get(0).(func(context.Context, *int64, domain.EventFilter) *domain.Cursor); ok {
+		r0 = rf(ctx, lastEventId, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Cursor)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *int64, domain.EventFilter) error); ok {
+		r1 = rf(ctx, lastEventId, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewEventService interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewEventService creates a new instance of EventService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewEventService(t mockConstructorTestingTNewEventService) *EventService {
+	mock := &EventService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/events/domain"
+)
+
+type mariaDbEventRepository struct {
+	db *sql.DB
+}
+
+func NewMariadbEventRepository(db *sql.DB) domain.EventRepository {
+	return &mariaDbEventRepository{db: db}
+}
+
+func (m *mariaDbEventRepository) GetLastId(ctx context.Context) (int64, error) {
+	var lastId int64
+
+	if err := m.db.QueryRowContext(ctx, SQLGetLastEventId).Scan(&lastId); err != nil {
+		return 0, err
+	}
+
+	return lastId, nil
+}
+
+func (m *mariaDbEventRepository) GetAfter(ctx context.Context, lastId int64, filter domain.EventFilter, limit int64) ([]domain.Event, error) {
+	events := []domain.Event{}
+	eventTypes := strings.Join(filter.EventTypes, ",")
+
+	rows, err := m.db.QueryContext(
+		ctx,
+		SQLGetEventsAfter,
+		lastId,
+		filter.WarehouseId,
+		filter.WarehouseId,
+		eventTypes,
+		eventTypes,
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var event domain.Event
+		var warehouseId sql.NullInt64
+		var payload string
+
+		err := rows.Scan(
+			&event.Id,
+			&event.EventType,
+			&event.AggregateId,
+			&warehouseId,
+			&payload,
+			&event.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		event.WarehouseId = warehouseId.Int64
+		event.Data = []byte(payload)
+
+		events = append(events, event)
+	}
+
+	return events, nil
+}
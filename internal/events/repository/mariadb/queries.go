@@ -0,0 +1,23 @@
+package repository
+
+const (
+	SQLGetLastEventId = `
+    SELECT IFNULL(MAX(id), 0) FROM outbox_events
+    `
+
+	SQLGetEventsAfter = `
+    SELECT
+        id,
+        event_type,
+        aggregate_id,
+        warehouse_id,
+        payload,
+        created_at
+    FROM outbox_events
+    WHERE id > ?
+    AND (? = 0 OR warehouse_id = ?)
+    AND (? = '' OR FIND_IN_SET(event_type, ?))
+    ORDER BY id
+    LIMIT ?
+    `
+)
@@ -0,0 +1,129 @@
+package repository_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/events/domain"
+	repository "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/events/repository/mariadb"
+)
+
+var now = time.Now()
+
+var eventColumns = []string{"id", "event_type", "aggregate_id", "warehouse_id", "payload", "created_at"}
+
+func TestEventRepository_GetLastId(t *testing.T) {
+	t.Run("get_last_id_ok: should return the id of the latest event", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta(repository.SQLGetLastEventId)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(42))
+
+		eventRepository := repository.NewMariadbEventRepository(db)
+
+		result, err := eventRepository.GetLastId(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(42), result)
+	})
+
+	t.Run("get_last_id_error: should return error when query fails", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta(repository.SQLGetLastEventId)).WillReturnError(errors.New("any error"))
+
+		eventRepository := repository.NewMariadbEventRepository(db)
+
+		_, err = eventRepository.GetLastId(context.Background())
+
+		assert.Error(t, err)
+	})
+}
+
+func TestEventRepository_GetAfter(t *testing.T) {
+	t.Run("get_after_ok: should return the events after the cursor matching the filter", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		rows := sqlmock.NewRows(eventColumns).
+			AddRow(11, "inbound_order.created", 3, 1, `{"id":3}`, now).
+			AddRow(12, "purchase_order.created", 4, nil, `{"id":4}`, now)
+
+		mock.ExpectQuery(regexp.QuoteMeta(repository.SQLGetEventsAfter)).
+			WithArgs(10, 1, 1, "inbound_order.created,purchase_order.created", "inbound_order.created,purchase_order.created", 100).
+			WillReturnRows(rows)
+
+		eventRepository := repository.NewMariadbEventRepository(db)
+
+		filter := domain.EventFilter{
+			WarehouseId: 1,
+			EventTypes:  []string{"inbound_order.created", "purchase_order.created"},
+		}
+
+		result, err := eventRepository.GetAfter(context.Background(), 10, filter, 100)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.Event{
+			{Id: 11, EventType: "inbound_order.created", AggregateId: 3, WarehouseId: 1, Data: json.RawMessage(`{"id":3}`), CreatedAt: now},
+			{Id: 12, EventType: "purchase_order.created", AggregateId: 4, Data: json.RawMessage(`{"id":4}`), CreatedAt: now},
+		}, result)
+	})
+
+	t.Run("get_after_no_filter: should pass empty filters", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta(repository.SQLGetEventsAfter)).
+			WithArgs(0, 0, 0, "", "", 100).
+			WillReturnRows(sqlmock.NewRows(eventColumns))
+
+		eventRepository := repository.NewMariadbEventRepository(db)
+
+		result, err := eventRepository.GetAfter(context.Background(), 0, domain.EventFilter{}, 100)
+
+		assert.NoError(t, err)
+		assert.Empty(t, result)
+	})
+
+	t.Run("get_after_scan_error: should return error when scan fail", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		rows := sqlmock.NewRows(eventColumns).AddRow("", "", "", "", "", "")
+
+		mock.ExpectQuery(regexp.QuoteMeta(repository.SQLGetEventsAfter)).WillReturnRows(rows)
+
+		eventRepository := repository.NewMariadbEventRepository(db)
+
+		_, err = eventRepository.GetAfter(context.Background(), 0, domain.EventFilter{}, 100)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("get_after_query_error: should return error when query fails", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta(repository.SQLGetEventsAfter)).WillReturnError(errors.New("any error"))
+
+		eventRepository := repository.NewMariadbEventRepository(db)
+
+		_, err = eventRepository.GetAfter(context.Background(), 0, domain.EventFilter{}, 100)
+
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/events/domain"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/libs/outbox"
+)
+
+const (
+	streamBatchSize = 100
+
+	// commitWindow bounds how long an outbox write may stay uncommitted and
+	// still reach the streams, which re-read the events of that window.
+	commitWindow = 30 * time.Second
+)
+
+type service struct {
+	repository domain.EventRepository
+}
+
+func NewEventService(r domain.EventRepository) domain.EventService {
+	return &service{repository: r}
+}
+
+// GetCursor returns where a stream starts: after the Last-Event-ID sent by a
+// reconnecting client, or after the latest event for a new one.
+func (s *service) GetCursor(ctx context.Context, lastEventId *int64, filter domain.EventFilter) (*domain.Cursor, error) {
+	for _, eventType := range filter.EventTypes {
+		if !outbox.IsEventType(eventType) {
+			return nil, domain.ErrUnknownEventType
+		}
+	}
+
+	if lastEventId != nil {
+		if *lastEventId < 0 {
+			return nil, domain.ErrInvalidLastEventId
+		}
+		return domain.NewCursor(*lastEventId), nil
+	}
+
+	lastId, err := s.repository.GetLastId(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.NewCursor(lastId), nil
+}
+
+// GetAfter returns the events the stream has not sent yet, including those
+// committed late with an id below the last one sent, and advances the cursor.
+func (s *service) GetAfter(ctx context.Context, cursor *domain.Cursor, filter domain.EventFilter) ([]domain.Event, error) {
+	events := []domain.Event{}
+
+	from := cursor.Floor
+	for {
+		page, err := s.repository.GetAfter(ctx, from, filter, streamBatchSize)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, event := range page {
+			if cursor.Sent(event.Id) {
+				continue
+			}
+			cursor.MarkSent(event.Id)
+			events = append(events, event)
+		}
+
+		if int64(len(page)) < streamBatchSize {
+			break
+		}
+		from = page[len(page)-1].Id
+	}
+
+	cursor.Advance(time.Now(), commitWindow)
+
+	return events, nil
+}
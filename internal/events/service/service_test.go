@@ -0,0 +1,150 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/events/domain"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/events/domain/mocks"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/events/service"
+)
+
+func TestEventService_GetCursor(t *testing.T) {
+	t.Run("get_cursor_new_client: should start after the latest event", func(t *testing.T) {
+		repo := mocks.NewEventRepository(t)
+		eventService := service.NewEventService(repo)
+
+		repo.On("GetLastId", mock.Anything).Return(int64(42), nil).Once()
+
+		cursor, err := eventService.GetCursor(context.TODO(), nil, domain.EventFilter{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(42), cursor.LastId)
+	})
+
+	t.Run("get_cursor_reconnect: should resume after the Last-Event-ID", func(t *testing.T) {
+		repo := mocks.NewEventRepository(t)
+		eventService := service.NewEventService(repo)
+
+		lastEventId := int64(7)
+
+		cursor, err := eventService.GetCursor(context.TODO(), &lastEventId, domain.EventFilter{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(7), cursor.LastId)
+	})
+
+	t.Run("get_cursor_invalid_last_event_id: should reject negative ids", func(t *testing.T) {
+		repo := mocks.NewEventRepository(t)
+		eventService := service.NewEventService(repo)
+
+		lastEventId := int64(-1)
+
+		_, err := eventService.GetCursor(context.TODO(), &lastEventId, domain.EventFilter{})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidLastEventId)
+	})
+
+	t.Run("get_cursor_unknown_event_type: should reject unknown event types", func(t *testing.T) {
+		repo := mocks.NewEventRepository(t)
+		eventService := service.NewEventService(repo)
+
+		filter := domain.EventFilter{EventTypes: []string{"inbound_order.created", "unknown"}}
+
+		_, err := eventService.GetCursor(context.TODO(), nil, filter)
+
+		assert.ErrorIs(t, err, domain.ErrUnknownEventType)
+	})
+
+	t.Run("get_cursor_error: should return error when the latest event cannot be read", func(t *testing.T) {
+		repo := mocks.NewEventRepository(t)
+		eventService := service.NewEventService(repo)
+
+		repo.On("GetLastId", mock.Anything).Return(int64(0), errors.New("any error")).Once()
+
+		_, err := eventService.GetCursor(context.TODO(), nil, domain.EventFilter{})
+
+		assert.Error(t, err)
+	})
+}
+
+func TestEventService_GetAfter(t *testing.T) {
+	filter := domain.EventFilter{WarehouseId: 1}
+
+	t.Run("get_after_ok: should return the next events", func(t *testing.T) {
+		repo := mocks.NewEventRepository(t)
+		eventService := service.NewEventService(repo)
+
+		expected := []domain.Event{{Id: 8, EventType: "inbound_order.created", WarehouseId: 1}}
+
+		repo.On("GetAfter", mock.Anything, int64(7), filter, mock.Anything).Return(expected, nil).Once()
+
+		result, err := eventService.GetAfter(context.TODO(), domain.NewCursor(7), filter)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, result)
+	})
+
+	t.Run("get_after_late_commit: should send an event committed after a higher id once", func(t *testing.T) {
+		repo := mocks.NewEventRepository(t)
+		eventService := service.NewEventService(repo)
+
+		cursor := domain.NewCursor(7)
+		late := domain.Event{Id: 8, EventType: "inbound_order.created", WarehouseId: 1}
+		next := domain.Event{Id: 9, EventType: "inbound_order.created", WarehouseId: 1}
+
+		// Event 8 is still uncommitted on the first poll.
+		repo.On("GetAfter", mock.Anything, int64(7), filter, mock.Anything).Return([]domain.Event{next}, nil).Once()
+		repo.On("GetAfter", mock.Anything, int64(7), filter, mock.Anything).Return([]domain.Event{late, next}, nil).Twice()
+
+		first, err := eventService.GetAfter(context.TODO(), cursor, filter)
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.Event{next}, first)
+
+		second, err := eventService.GetAfter(context.TODO(), cursor, filter)
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.Event{late}, second)
+
+		third, err := eventService.GetAfter(context.TODO(), cursor, filter)
+		assert.NoError(t, err)
+		assert.Empty(t, third)
+		assert.Equal(t, int64(9), cursor.LastId)
+	})
+
+	t.Run("get_after_error: should return error when the events cannot be read", func(t *testing.T) {
+		repo := mocks.NewEventRepository(t)
+		eventService := service.NewEventService(repo)
+
+		repo.On("GetAfter", mock.Anything, int64(7), filter, mock.Anything).Return(nil, errors.New("any error")).Once()
+
+		_, err := eventService.GetAfter(context.TODO(), domain.NewCursor(7), filter)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestCursor_Advance(t *testing.T) {
+	t.Run("advance_ok: should raise the floor to the position reached one window ago", func(t *testing.T) {
+		start := time.Date(2022, time.March, 10, 14, 0, 0, 0, time.UTC)
+		cursor := domain.NewCursor(7)
+
+		cursor.MarkSent(9)
+		cursor.Advance(start, 30*time.Second)
+
+		assert.Equal(t, int64(7), cursor.Floor)
+		assert.True(t, cursor.Sent(9))
+		assert.False(t, cursor.Sent(8))
+
+		cursor.MarkSent(12)
+		cursor.Advance(start.Add(30*time.Second), 30*time.Second)
+
+		assert.Equal(t, int64(9), cursor.Floor)
+		assert.True(t, cursor.Sent(8))
+		assert.True(t, cursor.Sent(12))
+		assert.False(t, cursor.Sent(10))
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/inboud_orders/domain"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/libs/outbox"
 )
 
 type mariaDBInboundOrdersRepository struct {
@@ -32,7 +33,12 @@ func (repo *mariaDBInboundOrdersRepository) Create(
 		WarehouseId:    warehouseId,
 	}
 
-	res, err := repo.db.ExecContext(
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return domain.InboundOrders{}, err
+	}
+
+	res, err := tx.ExecContext(
 		ctx,
 		SQLCreateInboundOrder,
 		&inboundOrders.OrderDate,
@@ -42,11 +48,26 @@ func (repo *mariaDBInboundOrdersRepository) Create(
 		&inboundOrders.WarehouseId,
 	)
 	if err != nil {
+		tx.Rollback()
 		return domain.InboundOrders{}, err
 	}
 
 	id, _ := res.LastInsertId()
 	inboundOrders.Id = id
 
+	if _, err := tx.ExecContext(ctx, SQLLinkReceipt, id, employeeId, productBatchId); err != nil {
+		tx.Rollback()
+		return domain.InboundOrders{}, err
+	}
+
+	if err := outbox.PublishWarehouseEvent(ctx, tx, outbox.InboundOrderCreated, id, warehouseId, inboundOrders); err != nil {
+		tx.Rollback()
+		return domain.InboundOrders{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return domain.InboundOrders{}, err
+	}
+
 	return inboundOrders, nil
 }
@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/inboud_orders/domain"
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/inboud_orders/repository"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/libs/outbox"
 )
 
 func TestInboundOrdersRepository_Create(t *testing.T) {
@@ -32,10 +33,20 @@ func TestInboundOrdersRepository_Create(t *testing.T) {
 		assert.NoError(t, err)
 		defer db.Close()
 
+		mock.ExpectBegin()
 		mock.
 			ExpectExec(regexp.QuoteMeta(repository.SQLCreateInboundOrder)).
 			WithArgs(now, "order#1", int64(1), int64(1), int64(1)).
 			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.
+			ExpectExec(regexp.QuoteMeta(repository.SQLLinkReceipt)).
+			WithArgs(int64(1), int64(1), int64(1)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.
+			ExpectExec(regexp.QuoteMeta(outbox.SQLCreateOutboxEvent)).
+			WithArgs(outbox.InboundOrderCreated, int64(1), int64(1), sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
 
 		inboundOrdersRepository := repository.NewMariaDBInboundRepositoryRepository(db)
 
@@ -50,6 +61,7 @@ func TestInboundOrdersRepository_Create(t *testing.T) {
 
 		assert.Nil(t, err)
 		assert.Equal(t, expectedInboundOrders, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
 	t.Run("create_fail_exec: should return error when query fails", func(t *testing.T) {
@@ -57,9 +69,42 @@ func TestInboundOrdersRepository_Create(t *testing.T) {
 		assert.NoError(t, err)
 		defer db.Close()
 
+		mock.ExpectBegin()
 		mock.
 			ExpectExec(regexp.QuoteMeta(repository.SQLCreateInboundOrder)).
 			WillReturnError(fmt.Errorf("query error"))
+		mock.ExpectRollback()
+
+		inboundOrdersRepository := repository.NewMariaDBInboundRepositoryRepository(db)
+
+		_, err = inboundOrdersRepository.Create(
+			ctx,
+			now,
+			"order#1",
+			int64(1),
+			int64(1),
+			int64(1),
+		)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("create_fail_outbox: should rollback when the domain event cannot be written", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.
+			ExpectExec(regexp.QuoteMeta(repository.SQLCreateInboundOrder)).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.
+			ExpectExec(regexp.QuoteMeta(repository.SQLLinkReceipt)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.
+			ExpectExec(regexp.QuoteMeta(outbox.SQLCreateOutboxEvent)).
+			WillReturnError(fmt.Errorf("outbox error"))
+		mock.ExpectRollback()
 
 		inboundOrdersRepository := repository.NewMariaDBInboundRepositoryRepository(db)
 
@@ -73,5 +118,6 @@ func TestInboundOrdersRepository_Create(t *testing.T) {
 		)
 
 		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 }
@@ -4,4 +4,11 @@ var (
 	SQLCreateInboundOrder = `
 	INSERT INTO inbound_orders (order_date, order_number, employee_id, product_batch_id, warehouse_id)
 	VALUES (?, ?, ?, ?, ?)`
+
+	// The stock ledger is append-only but for this: the receipt of a batch
+	// is written when the batch is, and names its inbound order once that
+	// is registered.
+	SQLLinkReceipt = `
+	UPDATE stock_movements SET reference_type = 'inbound_order', reference_id = ?, employee_id = ?
+	WHERE product_batch_id = ? AND movement_type = 'receipt' AND reference_id IS NULL`
 )
@@ -0,0 +1,18 @@
+package domain
+
+import "context"
+
+type CheckResult struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	Violations  int64   `json:"violations"`
+	SampleIds   []int64 `json:"sample_ids"`
+}
+
+type IntegrityRepository interface {
+	Run(ctx context.Context) ([]CheckResult, error)
+}
+
+type IntegrityService interface {
+	Check(ctx context.Context) ([]CheckResult, error)
+}
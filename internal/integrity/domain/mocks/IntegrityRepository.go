@@ -0,0 +1,53 @@
+// Code generated by mockery v2.13.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	domain "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/integrity/domain"
+)
+
+// IntegrityRepository is an autogenerated mock type for the IntegrityRepository type
+type IntegrityRepository struct {
+	mock.Mock
+}
+
+// Run provides a mock function with given fields: ctx
+func (_m *IntegrityRepository) Run(ctx context.Context) ([]domain.CheckResult, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []domain.CheckResult
+	if rf, ok := ret.Get(0).(func(context.Context) []domain.CheckResult); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.CheckResult)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewIntegrityRepository interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewIntegrityRepository creates a new instance of IntegrityRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewIntegrityRepository(t mockConstructorTestingTNewIntegrityRepository) *IntegrityRepository {
+	mock := &IntegrityRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
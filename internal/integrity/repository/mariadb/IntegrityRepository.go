@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/integrity/domain"
+)
+
+const sampleSize = 10
+
+type mariaDbIntegrityRepository struct {
+	db *sql.DB
+}
+
+func NewMariadbIntegrityRepository(db *sql.DB) domain.IntegrityRepository {
+	return &mariaDbIntegrityRepository{db: db}
+}
+
+func (m *mariaDbIntegrityRepository) Run(ctx context.Context) ([]domain.CheckResult, error) {
+	results := make([]domain.CheckResult, 0, len(Checks))
+
+	for _, check := range Checks {
+		result, err := m.run(ctx, check)
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, *result)
+	}
+
+	return results, nil
+}
+
+func (m *mariaDbIntegrityRepository) run(ctx context.Context, check Check) (*domain.CheckResult, error) {
+	result := domain.CheckResult{
+		Name:        check.Name,
+		Description: check.Description,
+		SampleIds:   []int64{},
+	}
+
+	rows, err := m.db.QueryContext(ctx, check.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+
+		result.Violations++
+		if len(result.SampleIds) < sampleSize {
+			result.SampleIds = append(result.SampleIds, id)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
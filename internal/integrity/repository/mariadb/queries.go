@@ -0,0 +1,89 @@
+package repository
+
+// Check is a named query returning the ids of the rows that break an invariant.
+type Check struct {
+	Name        string
+	Description string
+	Query       string
+}
+
+var Checks = []Check{
+	{
+		Name:        "sections_over_capacity",
+		Description: "sections whose current capacity exceeds the maximum capacity",
+		Query:       `SELECT id FROM sections WHERE current_capacity > maximum_capacity ORDER BY id`,
+	},
+	{
+		Name:        "sections_invalid_capacity_bounds",
+		Description: "sections whose minimum capacity exceeds the maximum capacity",
+		Query:       `SELECT id FROM sections WHERE minimum_capacity > maximum_capacity ORDER BY id`,
+	},
+	{
+		Name:        "sections_without_warehouse",
+		Description: "sections pointing to a missing warehouse",
+		Query: `
+        SELECT s.id FROM sections s
+        LEFT JOIN warehouses w ON w.id = s.warehouse_id
+        WHERE w.id IS NULL ORDER BY s.id`,
+	},
+	{
+		Name:        "batches_invalid_quantity",
+		Description: "product batches with a negative quantity or more than initially received",
+		Query:       `SELECT id FROM product_batches WHERE current_quantity < 0 OR current_quantity > initial_quantity ORDER BY id`,
+	},
+	{
+		Name:        "batches_ledger_mismatch",
+		Description: "product batches whose current quantity differs from the sum of their stock movements",
+		Query: `
+        SELECT pb.id FROM product_batches pb
+        LEFT JOIN (
+            SELECT product_batch_id, SUM(quantity) quantity FROM stock_movements GROUP BY product_batch_id
+        ) sm ON sm.product_batch_id = pb.id
+        WHERE pb.current_quantity <> IFNULL(sm.quantity, 0) ORDER BY pb.id`,
+	},
+	{
+		Name:        "batches_due_before_manufacturing",
+		Description: "product batches due before they were manufactured",
+		Query:       `SELECT id FROM product_batches WHERE due_date < manufacturing_date ORDER BY id`,
+	},
+	{
+		Name:        "batches_without_section",
+		Description: "product batches pointing to a missing section",
+		Query: `
+        SELECT pb.id FROM product_batches pb
+        LEFT JOIN sections s ON s.id = pb.section_id
+        WHERE s.id IS NULL ORDER BY pb.id`,
+	},
+	{
+		Name:        "batches_without_product",
+		Description: "product batches pointing to a missing product",
+		Query: `
+        SELECT pb.id FROM product_batches pb
+        LEFT JOIN products p ON p.id = pb.product_id
+        WHERE p.id IS NULL ORDER BY pb.id`,
+	},
+	{
+		Name:        "products_without_seller",
+		Description: "products pointing to a missing seller",
+		Query: `
+        SELECT p.id FROM products p
+        LEFT JOIN sellers s ON s.id = p.seller_id
+        WHERE p.seller_id IS NOT NULL AND s.id IS NULL ORDER BY p.id`,
+	},
+	{
+		Name:        "sellers_without_locality",
+		Description: "sellers pointing to a missing locality",
+		Query: `
+        SELECT s.id FROM sellers s
+        LEFT JOIN localities l ON l.id = s.locality_id
+        WHERE l.id IS NULL ORDER BY s.id`,
+	},
+	{
+		Name:        "inbound_orders_without_batch",
+		Description: "inbound orders pointing to a missing product batch",
+		Query: `
+        SELECT io.id FROM inbound_orders io
+        LEFT JOIN product_batches pb ON pb.id = io.product_batch_id
+        WHERE pb.id IS NULL ORDER BY io.id`,
+	},
+}
@@ -0,0 +1,58 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	repository "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/integrity/repository/mariadb"
+)
+
+func TestIntegrityRepository_Run(t *testing.T) {
+	t.Run("run_ok: should count violations and keep a sample of ids", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		for i, check := range repository.Checks {
+			rows := sqlmock.NewRows([]string{"id"})
+			if i == 0 {
+				for id := 1; id <= 12; id++ {
+					rows.AddRow(id)
+				}
+			}
+			mock.ExpectQuery(regexp.QuoteMeta(check.Query)).WillReturnRows(rows)
+		}
+
+		integrityRepository := repository.NewMariadbIntegrityRepository(db)
+
+		results, err := integrityRepository.Run(context.Background())
+
+		assert.NoError(t, err)
+		assert.Len(t, results, len(repository.Checks))
+
+		assert.Equal(t, repository.Checks[0].Name, results[0].Name)
+		assert.Equal(t, int64(12), results[0].Violations)
+		assert.Equal(t, []int64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, results[0].SampleIds)
+
+		assert.Equal(t, int64(0), results[1].Violations)
+		assert.Empty(t, results[1].SampleIds)
+	})
+
+	t.Run("run_error: should return error when a check fails", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta(repository.Checks[0].Query)).WillReturnError(errors.New("any error"))
+
+		integrityRepository := repository.NewMariadbIntegrityRepository(db)
+
+		_, err = integrityRepository.Run(context.Background())
+
+		assert.EqualError(t, err, "any error")
+	})
+}
@@ -0,0 +1,24 @@
+package service
+
+import (
+	"context"
+
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/integrity/domain"
+)
+
+type service struct {
+	repository domain.IntegrityRepository
+}
+
+func NewIntegrityService(r domain.IntegrityRepository) domain.IntegrityService {
+	return &service{repository: r}
+}
+
+func (s *service) Check(ctx context.Context) ([]domain.CheckResult, error) {
+	results, err := s.repository.Run(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
@@ -0,0 +1,40 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/integrity/domain"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/integrity/domain/mocks"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/integrity/service"
+)
+
+func TestIntegrityService_Check(t *testing.T) {
+	t.Run("check_ok: should return the results of every check", func(t *testing.T) {
+		results := []domain.CheckResult{{Name: "sections_over_capacity", Violations: 1, SampleIds: []int64{4}}}
+
+		repository := mocks.NewIntegrityRepository(t)
+		repository.On("Run", mock.Anything).Return(results, nil).Once()
+
+		integrityService := service.NewIntegrityService(repository)
+
+		result, err := integrityService.Check(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, results, result)
+	})
+
+	t.Run("check_error: should return error when the checks fail", func(t *testing.T) {
+		repository := mocks.NewIntegrityRepository(t)
+		repository.On("Run", mock.Anything).Return(nil, errors.New("any error")).Once()
+
+		integrityService := service.NewIntegrityService(repository)
+
+		_, err := integrityService.Check(context.Background())
+
+		assert.EqualError(t, err, "any error")
+	})
+}
@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	RunStatusRunning   = "running"
+	RunStatusSucceeded = "succeeded"
+	RunStatusFailed    = "failed"
+
+	TriggerScheduled = "scheduled"
+	TriggerManual    = "manual"
+)
+
+type Job struct {
+	Name      string    `json:"name"`
+	Schedule  string    `json:"schedule"`
+	Paused    bool      `json:"paused"`
+	Running   bool      `json:"running"`
+	NextRunAt time.Time `json:"next_run_at"`
+	LastRun   *JobRun   `json:"last_run"`
+}
+
+type JobRun struct {
+	Id         int64      `json:"id"`
+	JobName    string     `json:"job_name"`
+	Owner      string     `json:"owner"`
+	Trigger    string     `json:"trigger"`
+	Status     string     `json:"status"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+	DurationMs int64      `json:"duration_ms"`
+	Error      string     `json:"error"`
+}
+
+type JobRepository interface {
+	AcquireLease(ctx context.Context, name string, owner string, ttl time.Duration) (bool, error)
+	AcquireSlot(ctx context.Context, name string, owner string, slot time.Time, ttl time.Duration) (bool, error)
+	ReleaseLease(ctx context.Context, name string, owner string) error
+	GetPaused(ctx context.Context) (map[string]bool, error)
+	SetPaused(ctx context.Context, name string, paused bool) error
+	CreateRun(ctx context.Context, run *JobRun) (*JobRun, error)
+	FinishRun(ctx context.Context, run *JobRun) error
+	GetRuns(ctx context.Context, name string, limit int64) ([]JobRun, error)
+}
+
+type JobService interface {
+	GetAll(ctx context.Context) ([]Job, error)
+	GetRuns(ctx context.Context, name string) ([]JobRun, error)
+	Pause(ctx context.Context, name string) error
+	Resume(ctx context.Context, name string) error
+	Trigger(ctx context.Context, name string) error
+}
@@ -0,0 +1,10 @@
+package domain
+
+import "errors"
+
+var (
+	ErrJobNotFound       = errors.New("job not found")
+	ErrJobAlreadyRunning = errors.New("job is already running")
+	ErrJobAlreadyExists  = errors.New("job already registered")
+	ErrInvalidSchedule   = errors.New("invalid schedule")
+)
@@ -0,0 +1,185 @@
+// Code generated by mockery v2.13.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	domain "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/jobs/domain"
+
+	time "time"
+)
+
+// JobRepository is an autogenerated mock type for the JobRepository type
+type JobRepository struct {
+	mock.Mock
+}
+
+// AcquireLease provides a mock function with given fields: ctx, name, owner, ttl
+func (_m *JobRepository) AcquireLease(ctx context.Context, name string, owner string, ttl time.Duration) (bool, error) {
+	ret := _m.Called(ctx, name, owner, ttl)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Duration) bool); ok {
+		r0 = rf(ctx, name, owner, ttl)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, time.Duration) error); ok {
+		r1 = rf(ctx, name, owner, ttl)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AcquireSlot provides a mock function with given fields: ctx, name, owner, slot, ttl
+func (_m *JobRepository) AcquireSlot(ctx context.Context, name string, owner string, slot time.Time, ttl time.Duration) (bool, error) {
+	ret := _m.Called(ctx, name, owner, slot, ttl)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time, time.Duration) bool); ok {
+		r0 = rf(ctx, name, owner, slot, ttl)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, time.Time, time.Duration) error); ok {
+		r1 = rf(ctx, name, owner, slot, ttl)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateRun provides a mock function with given fields: ctx, run
+func (_m *JobRepository) CreateRun(ctx context.Context, run *domain.JobRun) (*domain.JobRun, error) {
+	ret := _m.Called(ctx, run)
+
+	var r0 *domain.JobRun
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.JobRun) *domain.JobRun); ok {
+		r0 = rf(ctx, run)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.JobRun)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.JobRun) error); ok {
+		r1 = rf(ctx, run)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FinishRun provides a mock function with given fields: ctx, run
+func (_m *JobRepository) FinishRun(ctx context.Context, run *domain.JobRun) error {
+	ret := _m.Called(ctx, run)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.JobRun) error); ok {
+		r0 = rf(ctx, run)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetPaused provides a mock function with given fields: ctx
+func (_m *JobRepository) GetPaused(ctx context.Context) (map[string]bool, error) {
+	ret := _m.Called(ctx)
+
+	var r0 map[string]bool
+	if rf, ok := ret.Get(0).(func(context.Context) map[string]bool); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]bool)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRuns provides a mock function with given fields: ctx, name, limit
+func (_m *JobRepository) GetRuns(ctx context.Context, name string, limit int64) ([]domain.JobRun, error) {
+	ret := _m.Called(ctx, name, limit)
+
+	var r0 []domain.JobRun
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) []domain.JobRun); ok {
+		r0 = rf(ctx, name, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.JobRun)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64) error); ok {
+		r1 = rf(ctx, name, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReleaseLease provides a mock function with given fields: ctx, name, owner
+func (_m *JobRepository) ReleaseLease(ctx context.Context, name string, owner string) error {
+	ret := _m.Called(ctx, name, owner)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, name, owner)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetPaused provides a mock function with given fields: ctx, name, paused
+func (_m *JobRepository) SetPaused(ctx context.Context, name string, paused bool) error {
+	ret := _m.Called(ctx, name, paused)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool) error); ok {
+		r0 = rf(ctx, name, paused)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewJobRepository interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewJobRepository creates a new instance of JobRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewJobRepository(t mockConstructorTestingTNewJobRepository) *JobRepository {
+	mock := &JobRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,118 @@
+// Code generated by mockery v2.13.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	domain "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/jobs/domain"
+)
+
+// JobService is an autogenerated mock type for the JobService type
+type JobService struct {
+	mock.Mock
+}
+
+// GetAll provides a mock function with given fields: ctx
+func (_m *JobService) GetAll(ctx context.Context) ([]domain.Job, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []domain.Job
+	if rf, ok := ret.Get(0).(func(context.Context) []domain.Job); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Job)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRuns provides a mock function with given fields: ctx, name
+func (_m *JobService) GetRuns(ctx context.Context, name string) ([]domain.JobRun, error) {
+	ret := _m.Called(ctx, name)
+
+	var r0 []domain.JobRun
+	if rf, ok := ret.Get(0).(func(context.Context, string) []domain.JobRun); ok {
+		r0 = rf(ctx, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.JobRun)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Pause provides a mock function with given fields: ctx, name
+func (_m *JobService) Pause(ctx context.Context, name string) error {
+	ret := _m.Called(ctx, name)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Resume provides a mock function with given fields: ctx, name
+func (_m *JobService) Resume(ctx context.Context, name string) error {
+	ret := _m.Called(ctx, name)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Trigger provides a mock function with given fields: ctx, name
+func (_m *JobService) Trigger(ctx context.Context, name string) error {
+	ret := _m.Called(ctx, name)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewJobService interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewJobService creates a new instance of JobService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewJobService(t mockConstructorTestingTNewJobService) *JobService {
+	mock := &JobService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
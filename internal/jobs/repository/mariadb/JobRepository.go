@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/jobs/domain"
+)
+
+type mariaDbJobRepository struct {
+	db *sql.DB
+}
+
+func NewMariadbJobRepository(db *sql.DB) domain.JobRepository {
+	return &mariaDbJobRepository{db: db}
+}
+
+func (m *mariaDbJobRepository) AcquireLease(ctx context.Context, name string, owner string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+
+	result, err := m.db.ExecContext(ctx, SQLAcquireLease, name, owner, now.Add(ttl), now, now)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// AcquireSlot takes the lease for a scheduled run unless it is held or the
+// slot has already been run, by this replica or another one.
+func (m *mariaDbJobRepository) AcquireSlot(ctx context.Context, name string, owner string, slot time.Time, ttl time.Duration) (bool, error) {
+	now := time.Now()
+
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+
+	acquired, err := m.acquireSlot(ctx, tx, name, owner, slot, now.Add(ttl), now)
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+
+	return acquired, nil
+}
+
+func (m *mariaDbJobRepository) acquireSlot(ctx context.Context, tx *sql.Tx, name string, owner string, slot time.Time, expiresAt time.Time, now time.Time) (bool, error) {
+	if _, err := tx.ExecContext(ctx, SQLCreateFreeLease, name, now); err != nil {
+		return false, err
+	}
+
+	var leaseExpiresAt time.Time
+	var lastSlot sql.NullTime
+
+	if err := tx.QueryRowContext(ctx, SQLGetLeaseForUpdate, name).Scan(&leaseExpiresAt, &lastSlot); err != nil {
+		return false, err
+	}
+
+	if leaseExpiresAt.After(now) || (lastSlot.Valid && !lastSlot.Time.Before(slot)) {
+		return false, nil
+	}
+
+	if _, err := tx.ExecContext(ctx, SQLTakeLeaseForSlot, owner, expiresAt, slot, name); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (m *mariaDbJobRepository) ReleaseLease(ctx context.Context, name string, owner string) error {
+	_, err := m.db.ExecContext(ctx, SQLReleaseLease, time.Now(), name, owner)
+	return err
+}
+
+func (m *mariaDbJobRepository) GetPaused(ctx context.Context) (map[string]bool, error) {
+	paused := map[string]bool{}
+
+	rows, err := m.db.QueryContext(ctx, SQLGetPausedJobs)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var name string
+		var isPaused bool
+
+		if err := rows.Scan(&name, &isPaused); err != nil {
+			return nil, err
+		}
+
+		paused[name] = isPaused
+	}
+
+	return paused, nil
+}
+
+func (m *mariaDbJobRepository) SetPaused(ctx context.Context, name string, paused bool) error {
+	_, err := m.db.ExecContext(ctx, SQLSetJobPaused, name, paused, time.Now())
+	return err
+}
+
+func (m *mariaDbJobRepository) CreateRun(ctx context.Context, run *domain.JobRun) (*domain.JobRun, error) {
+	result, err := m.db.ExecContext(
+		ctx,
+		SQLCreateJobRun,
+		run.JobName,
+		run.Owner,
+		run.Trigger,
+		run.Status,
+		run.StartedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	run.Id, _ = result.LastInsertId()
+
+	return run, nil
+}
+
+func (m *mariaDbJobRepository) FinishRun(ctx context.Context, run *domain.JobRun) error {
+	_, err := m.db.ExecContext(
+		ctx,
+		SQLFinishJobRun,
+		run.Status,
+		run.FinishedAt,
+		run.DurationMs,
+		run.Error,
+		run.Id,
+	)
+
+	return err
+}
+
+func (m *mariaDbJobRepository) GetRuns(ctx context.Context, name string, limit int64) ([]domain.JobRun, error) {
+	runs := []domain.JobRun{}
+
+	rows, err := m.db.QueryContext(ctx, SQLGetJobRuns, name, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var run domain.JobRun
+		var finishedAt sql.NullTime
+		var durationMs sql.NullInt64
+		var runError sql.NullString
+
+		err := rows.Scan(
+			&run.Id,
+			&run.JobName,
+			&run.Owner,
+			&run.Trigger,
+			&run.Status,
+			&run.StartedAt,
+			&finishedAt,
+			&durationMs,
+			&runError,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if finishedAt.Valid {
+			run.FinishedAt = &finishedAt.Time
+		}
+		run.DurationMs = durationMs.Int64
+		run.Error = runError.String
+
+		runs = append(runs, run)
+	}
+
+	return runs, nil
+}
@@ -0,0 +1,68 @@
+package repository
+
+const (
+	// Takes the lease if it does not exist or has expired. MariaDB reports 1
+	// affected row for the insert, 2 for the takeover and 0 when it is held.
+	SQLAcquireLease = `
+    INSERT INTO job_leases (name, owner, expires_at)
+    VALUES (?, ?, ?)
+    ON DUPLICATE KEY UPDATE
+        owner = IF(expires_at < ?, VALUES(owner), owner),
+        expires_at = IF(expires_at < ?, VALUES(expires_at), expires_at)
+    `
+
+	// The row outlives the lease so the last scheduled slot stays recorded.
+	SQLReleaseLease = `
+    UPDATE job_leases SET expires_at = ? WHERE name = ? AND owner = ?
+    `
+
+	SQLCreateFreeLease = `
+    INSERT IGNORE INTO job_leases (name, owner, expires_at) VALUES (?, '', ?)
+    `
+
+	SQLGetLeaseForUpdate = `
+    SELECT expires_at, last_slot FROM job_leases WHERE name = ? FOR UPDATE
+    `
+
+	SQLTakeLeaseForSlot = `
+    UPDATE job_leases SET owner = ?, expires_at = ?, last_slot = ? WHERE name = ?
+    `
+
+	SQLGetPausedJobs = `
+    SELECT name, paused FROM jobs
+    `
+
+	SQLSetJobPaused = `
+    INSERT INTO jobs (name, paused, updated_at)
+    VALUES (?, ?, ?)
+    ON DUPLICATE KEY UPDATE paused = VALUES(paused), updated_at = VALUES(updated_at)
+    `
+
+	SQLCreateJobRun = `
+    INSERT INTO job_runs (job_name, owner, ` + "`trigger`" + `, status, started_at)
+    VALUES (?, ?, ?, ?, ?)
+    `
+
+	SQLFinishJobRun = `
+    UPDATE job_runs
+    SET status = ?, finished_at = ?, duration_ms = ?, error = ?
+    WHERE id = ?
+    `
+
+	SQLGetJobRuns = `
+    SELECT
+        id,
+        job_name,
+        owner,
+        ` + "`trigger`" + `,
+        status,
+        started_at,
+        finished_at,
+        duration_ms,
+        error
+    FROM job_runs
+    WHERE job_name = ?
+    ORDER BY id DESC
+    LIMIT ?
+    `
+)
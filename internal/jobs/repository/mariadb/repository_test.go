@@ -0,0 +1,325 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/jobs/domain"
+	repository "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/jobs/repository/mariadb"
+)
+
+var now = time.Now()
+
+var runColumns = []string{"id", "job_name", "owner", "trigger", "status", "started_at", "finished_at", "duration_ms", "error"}
+
+func TestJobRepository_AcquireLease(t *testing.T) {
+	t.Run("acquire_lease_ok: should acquire a free lease", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(repository.SQLAcquireLease)).
+			WithArgs("logs.retention", "host-1", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		jobRepository := repository.NewMariadbJobRepository(db)
+
+		acquired, err := jobRepository.AcquireLease(context.Background(), "logs.retention", "host-1", time.Minute)
+
+		assert.NoError(t, err)
+		assert.True(t, acquired)
+	})
+
+	t.Run("acquire_lease_held: should not acquire a lease held by another owner", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(repository.SQLAcquireLease)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		jobRepository := repository.NewMariadbJobRepository(db)
+
+		acquired, err := jobRepository.AcquireLease(context.Background(), "logs.retention", "host-1", time.Minute)
+
+		assert.NoError(t, err)
+		assert.False(t, acquired)
+	})
+
+	t.Run("acquire_lease_error: should return error when query execution fails", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(repository.SQLAcquireLease)).WillReturnError(errors.New("any error"))
+
+		jobRepository := repository.NewMariadbJobRepository(db)
+
+		_, err = jobRepository.AcquireLease(context.Background(), "logs.retention", "host-1", time.Minute)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestJobRepository_AcquireSlot(t *testing.T) {
+	slot := time.Date(2022, time.March, 10, 3, 0, 0, 0, time.UTC)
+
+	t.Run("acquire_slot_ok: should take a free lease and record the slot", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(repository.SQLCreateFreeLease)).
+			WithArgs("logs.retention", sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(regexp.QuoteMeta(repository.SQLGetLeaseForUpdate)).
+			WithArgs("logs.retention").
+			WillReturnRows(sqlmock.NewRows([]string{"expires_at", "last_slot"}).AddRow(slot.Add(-time.Hour), slot.AddDate(0, 0, -1)))
+		mock.ExpectExec(regexp.QuoteMeta(repository.SQLTakeLeaseForSlot)).
+			WithArgs("host-1", sqlmock.AnyArg(), slot, "logs.retention").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		jobRepository := repository.NewMariadbJobRepository(db)
+
+		acquired, err := jobRepository.AcquireSlot(context.Background(), "logs.retention", "host-1", slot, time.Minute)
+
+		assert.NoError(t, err)
+		assert.True(t, acquired)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("acquire_slot_already_run: should not run a slot another replica has finished", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(repository.SQLCreateFreeLease)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(regexp.QuoteMeta(repository.SQLGetLeaseForUpdate)).
+			WillReturnRows(sqlmock.NewRows([]string{"expires_at", "last_slot"}).AddRow(slot.Add(time.Minute), slot))
+		mock.ExpectCommit()
+
+		jobRepository := repository.NewMariadbJobRepository(db)
+
+		acquired, err := jobRepository.AcquireSlot(context.Background(), "logs.retention", "host-1", slot, time.Minute)
+
+		assert.NoError(t, err)
+		assert.False(t, acquired)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("acquire_slot_held: should not take a lease that has not expired", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(repository.SQLCreateFreeLease)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(regexp.QuoteMeta(repository.SQLGetLeaseForUpdate)).
+			WillReturnRows(sqlmock.NewRows([]string{"expires_at", "last_slot"}).AddRow(time.Now().Add(time.Hour), nil))
+		mock.ExpectCommit()
+
+		jobRepository := repository.NewMariadbJobRepository(db)
+
+		acquired, err := jobRepository.AcquireSlot(context.Background(), "logs.retention", "host-1", slot, time.Minute)
+
+		assert.NoError(t, err)
+		assert.False(t, acquired)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("acquire_slot_error: should rollback when the lease cannot be read", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(repository.SQLCreateFreeLease)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(regexp.QuoteMeta(repository.SQLGetLeaseForUpdate)).
+			WillReturnError(errors.New("any error"))
+		mock.ExpectRollback()
+
+		jobRepository := repository.NewMariadbJobRepository(db)
+
+		_, err = jobRepository.AcquireSlot(context.Background(), "logs.retention", "host-1", slot, time.Minute)
+
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestJobRepository_ReleaseLease(t *testing.T) {
+	t.Run("release_lease_ok: should release the owned lease", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(repository.SQLReleaseLease)).
+			WithArgs(sqlmock.AnyArg(), "logs.retention", "host-1").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		jobRepository := repository.NewMariadbJobRepository(db)
+
+		err = jobRepository.ReleaseLease(context.Background(), "logs.retention", "host-1")
+
+		assert.NoError(t, err)
+	})
+}
+
+func TestJobRepository_Paused(t *testing.T) {
+	t.Run("get_paused_ok: should return the pause state by job", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		rows := sqlmock.NewRows([]string{"name", "paused"}).
+			AddRow("logs.retention", true).
+			AddRow("webhooks.dispatch", false)
+
+		mock.ExpectQuery(regexp.QuoteMeta(repository.SQLGetPausedJobs)).WillReturnRows(rows)
+
+		jobRepository := repository.NewMariadbJobRepository(db)
+
+		result, err := jobRepository.GetPaused(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]bool{"logs.retention": true, "webhooks.dispatch": false}, result)
+	})
+
+	t.Run("get_paused_error: should return error when query fails", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta(repository.SQLGetPausedJobs)).WillReturnError(errors.New("any error"))
+
+		jobRepository := repository.NewMariadbJobRepository(db)
+
+		_, err = jobRepository.GetPaused(context.Background())
+
+		assert.Error(t, err)
+	})
+
+	t.Run("set_paused_ok: should store the pause state", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(repository.SQLSetJobPaused)).
+			WithArgs("logs.retention", true, sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		jobRepository := repository.NewMariadbJobRepository(db)
+
+		err = jobRepository.SetPaused(context.Background(), "logs.retention", true)
+
+		assert.NoError(t, err)
+	})
+}
+
+func TestJobRepository_Runs(t *testing.T) {
+	t.Run("create_run_ok: should create a run", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(repository.SQLCreateJobRun)).
+			WithArgs("logs.retention", "host-1", domain.TriggerScheduled, domain.RunStatusRunning, now).
+			WillReturnResult(sqlmock.NewResult(3, 1))
+
+		jobRepository := repository.NewMariadbJobRepository(db)
+
+		result, err := jobRepository.CreateRun(context.Background(), &domain.JobRun{
+			JobName:   "logs.retention",
+			Owner:     "host-1",
+			Trigger:   domain.TriggerScheduled,
+			Status:    domain.RunStatusRunning,
+			StartedAt: now,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), result.Id)
+	})
+
+	t.Run("create_run_error: should return error when query execution fails", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(repository.SQLCreateJobRun)).WillReturnError(errors.New("any error"))
+
+		jobRepository := repository.NewMariadbJobRepository(db)
+
+		_, err = jobRepository.CreateRun(context.Background(), &domain.JobRun{})
+
+		assert.Error(t, err)
+	})
+
+	t.Run("finish_run_ok: should store the outcome of the run", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		run := domain.JobRun{Id: 3, Status: domain.RunStatusFailed, FinishedAt: &now, DurationMs: 120, Error: "boom"}
+
+		mock.ExpectExec(regexp.QuoteMeta(repository.SQLFinishJobRun)).
+			WithArgs(domain.RunStatusFailed, &now, int64(120), "boom", int64(3)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		jobRepository := repository.NewMariadbJobRepository(db)
+
+		err = jobRepository.FinishRun(context.Background(), &run)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("get_runs_ok: should return the latest runs", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		rows := sqlmock.NewRows(runColumns).
+			AddRow(4, "logs.retention", "host-1", "manual", "running", now, nil, nil, nil).
+			AddRow(3, "logs.retention", "host-1", "scheduled", "failed", now, now, 120, "boom")
+
+		mock.ExpectQuery(regexp.QuoteMeta(repository.SQLGetJobRuns)).
+			WithArgs("logs.retention", 50).
+			WillReturnRows(rows)
+
+		jobRepository := repository.NewMariadbJobRepository(db)
+
+		result, err := jobRepository.GetRuns(context.Background(), "logs.retention", 50)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.JobRun{
+			{Id: 4, JobName: "logs.retention", Owner: "host-1", Trigger: "manual", Status: "running", StartedAt: now},
+			{Id: 3, JobName: "logs.retention", Owner: "host-1", Trigger: "scheduled", Status: "failed", StartedAt: now, FinishedAt: &now, DurationMs: 120, Error: "boom"},
+		}, result)
+	})
+
+	t.Run("get_runs_scan_error: should return error when scan fail", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		rows := sqlmock.NewRows(runColumns).AddRow("", "", "", "", "", "", "", "", "")
+
+		mock.ExpectQuery(regexp.QuoteMeta(repository.SQLGetJobRuns)).WillReturnRows(rows)
+
+		jobRepository := repository.NewMariadbJobRepository(db)
+
+		_, err = jobRepository.GetRuns(context.Background(), "logs.retention", 50)
+
+		assert.Error(t, err)
+	})
+}
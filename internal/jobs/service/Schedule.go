@@ -0,0 +1,205 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/jobs/domain"
+)
+
+// Schedule computes the next activation time after a given time.
+type Schedule interface {
+	Next(t time.Time) time.Time
+}
+
+type everySchedule struct {
+	interval time.Duration
+}
+
+// Next aligns the runs to multiples of the interval, so every replica
+// computes the same slots whenever it started.
+func (s everySchedule) Next(t time.Time) time.Time {
+	return t.Truncate(s.interval).Add(s.interval)
+}
+
+// cronSchedule holds the allowed values of each cron field as a bit set.
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	domStar, dowStar              bool
+}
+
+type cronField struct {
+	min, max int
+}
+
+var (
+	minuteField = cronField{0, 59}
+	hourField   = cronField{0, 23}
+	domField    = cronField{1, 31}
+	monthField  = cronField{1, 12}
+	dowField    = cronField{0, 7}
+)
+
+var descriptors = map[string]string{
+	"@hourly":   "0 * * * *",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@weekly":   "0 0 * * 0",
+	"@monthly":  "0 0 1 * *",
+}
+
+// ParseSchedule accepts standard five field cron expressions
+// ("minute hour day-of-month month day-of-week") with lists, ranges and steps,
+// the @hourly, @daily, @weekly and @monthly descriptors, and "@every <duration>".
+func ParseSchedule(spec string) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+
+	if strings.HasPrefix(spec, "@every ") {
+		interval, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(spec, "@every ")))
+		if err != nil || interval < time.Second {
+			return nil, fmt.Errorf("%w: %s", domain.ErrInvalidSchedule, spec)
+		}
+		return everySchedule{interval: interval}, nil
+	}
+
+	if expression, ok := descriptors[spec]; ok {
+		spec = expression
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("%w: %s", domain.ErrInvalidSchedule, spec)
+	}
+
+	var schedule cronSchedule
+	var err error
+
+	if schedule.minute, err = parseCronField(fields[0], minuteField); err != nil {
+		return nil, err
+	}
+	if schedule.hour, err = parseCronField(fields[1], hourField); err != nil {
+		return nil, err
+	}
+	if schedule.dom, err = parseCronField(fields[2], domField); err != nil {
+		return nil, err
+	}
+	if schedule.month, err = parseCronField(fields[3], monthField); err != nil {
+		return nil, err
+	}
+	if schedule.dow, err = parseCronField(fields[4], dowField); err != nil {
+		return nil, err
+	}
+
+	// Sunday can be written as 0 or 7.
+	if schedule.dow&(1<<7) != 0 {
+		schedule.dow |= 1
+	}
+
+	schedule.domStar = fields[2] == "*"
+	schedule.dowStar = fields[4] == "*"
+
+	// Days that do not exist in the chosen months, like February 31st, never
+	// come around.
+	if schedule.Next(time.Now()).IsZero() {
+		return nil, fmt.Errorf("%w: %s never runs", domain.ErrInvalidSchedule, spec)
+	}
+
+	return schedule, nil
+}
+
+func parseCronField(field string, bounds cronField) (uint64, error) {
+	var bits uint64
+
+	for _, item := range strings.Split(field, ",") {
+		invalid := fmt.Errorf("%w: %s", domain.ErrInvalidSchedule, field)
+
+		rangePart, step := item, 1
+		if i := strings.Index(item, "/"); i >= 0 {
+			rangePart = item[:i]
+			value, err := strconv.Atoi(item[i+1:])
+			if err != nil || value <= 0 {
+				return 0, invalid
+			}
+			step = value
+		}
+
+		start, end := bounds.min, bounds.max
+		if rangePart != "*" {
+			if i := strings.Index(rangePart, "-"); i >= 0 {
+				from, errFrom := strconv.Atoi(rangePart[:i])
+				to, errTo := strconv.Atoi(rangePart[i+1:])
+				if errFrom != nil || errTo != nil {
+					return 0, invalid
+				}
+				start, end = from, to
+			} else {
+				value, err := strconv.Atoi(rangePart)
+				if err != nil {
+					return 0, invalid
+				}
+				start = value
+				if step == 1 {
+					end = value
+				}
+			}
+		}
+
+		if start < bounds.min || end > bounds.max || start > end {
+			return 0, invalid
+		}
+
+		for value := start; value <= end; value += step {
+			bits |= 1 << uint(value)
+		}
+	}
+
+	return bits, nil
+}
+
+func (s cronSchedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+
+	// Five years covers every valid combination, including February 29th.
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+
+		return t
+	}
+
+	return time.Time{}
+}
+
+// dayMatches follows cron semantics: when both day fields are restricted a
+// day matching either of them is enough.
+func (s cronSchedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+
+	return domMatch || dowMatch
+}
@@ -0,0 +1,348 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/jobs/domain"
+)
+
+const (
+	tickInterval = time.Second
+	historyLimit = 50
+)
+
+type JobFunc func(ctx context.Context) error
+
+// dueRun is a job whose scheduled slot has been reached.
+type dueRun struct {
+	job  *job
+	slot time.Time
+}
+
+type job struct {
+	name      string
+	spec      string
+	schedule  Schedule
+	timeout   time.Duration
+	run       JobFunc
+	nextRunAt time.Time
+	running   bool
+}
+
+// Scheduler runs registered jobs on their schedules. Every run holds a lease
+// in the database for the job timeout, so when several replicas are up only
+// one of them runs a given job at a time. Scheduled runs also record their
+// slot with the lease, so a slot is run once even when a replica picks it up
+// after another one has already finished it.
+type Scheduler struct {
+	repository domain.JobRepository
+	owner      string
+
+	mu    sync.Mutex
+	jobs  map[string]*job
+	names []string
+	wg    sync.WaitGroup
+	ctx   context.Context
+}
+
+func NewScheduler(r domain.JobRepository) *Scheduler {
+	hostname, _ := os.Hostname()
+
+	return &Scheduler{
+		repository: r,
+		owner:      fmt.Sprintf("%s-%d", hostname, os.Getpid()),
+		jobs:       map[string]*job{},
+		ctx:        context.Background(),
+	}
+}
+
+// Register adds a job. The timeout bounds a single run and is also the
+// lease duration, after which another replica may take the job over.
+func (s *Scheduler) Register(name string, spec string, timeout time.Duration, run JobFunc) error {
+	schedule, err := ParseSchedule(spec)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.jobs[name]; ok {
+		return domain.ErrJobAlreadyExists
+	}
+
+	s.jobs[name] = &job{
+		name:      name,
+		spec:      spec,
+		schedule:  schedule,
+		timeout:   timeout,
+		run:       run,
+		nextRunAt: schedule.Next(time.Now()),
+	}
+	s.names = append(s.names, name)
+
+	return nil
+}
+
+// Start runs due jobs until ctx is cancelled, then waits for the running ones.
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mu.Lock()
+	s.ctx = ctx
+	s.mu.Unlock()
+
+	ticker := time.NewTicker(tickInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.Wait()
+			return
+		case now := <-ticker.C:
+			s.RunDue(ctx, now)
+		}
+	}
+}
+
+// Wait blocks until the runs started so far have finished.
+func (s *Scheduler) Wait() {
+	s.wg.Wait()
+}
+
+func (s *Scheduler) RunDue(ctx context.Context, now time.Time) {
+	due := s.due(now)
+	if len(due) == 0 {
+		return
+	}
+
+	paused, err := s.repository.GetPaused(ctx)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+
+	for _, d := range due {
+		if paused[d.job.name] || !s.markRunning(d.job) {
+			continue
+		}
+
+		s.wg.Add(1)
+		go func(j *job, slot time.Time) {
+			defer s.wg.Done()
+			defer s.markIdle(j)
+
+			acquired, err := s.repository.AcquireSlot(ctx, j.name, s.owner, slot, j.timeout)
+			if err != nil {
+				log.Print(err)
+				return
+			}
+
+			// Another replica is running it or has already run this slot.
+			if !acquired {
+				return
+			}
+
+			s.execute(ctx, j, domain.TriggerScheduled)
+		}(d.job, d.slot)
+	}
+}
+
+func (s *Scheduler) GetAll(ctx context.Context) ([]domain.Job, error) {
+	paused, err := s.repository.GetPaused(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	jobs := make([]domain.Job, 0, len(s.names))
+	for _, name := range s.names {
+		j := s.jobs[name]
+		jobs = append(jobs, domain.Job{
+			Name:      j.name,
+			Schedule:  j.spec,
+			Paused:    paused[j.name],
+			Running:   j.running,
+			NextRunAt: j.nextRunAt,
+		})
+	}
+	s.mu.Unlock()
+
+	for i := range jobs {
+		runs, err := s.repository.GetRuns(ctx, jobs[i].Name, 1)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(runs) > 0 {
+			jobs[i].LastRun = &runs[0]
+		}
+	}
+
+	return jobs, nil
+}
+
+func (s *Scheduler) GetRuns(ctx context.Context, name string) ([]domain.JobRun, error) {
+	if _, err := s.get(name); err != nil {
+		return nil, err
+	}
+
+	return s.repository.GetRuns(ctx, name, historyLimit)
+}
+
+func (s *Scheduler) Pause(ctx context.Context, name string) error {
+	if _, err := s.get(name); err != nil {
+		return err
+	}
+
+	return s.repository.SetPaused(ctx, name, true)
+}
+
+func (s *Scheduler) Resume(ctx context.Context, name string) error {
+	if _, err := s.get(name); err != nil {
+		return err
+	}
+
+	return s.repository.SetPaused(ctx, name, false)
+}
+
+// Trigger starts a run right away, even if the job is paused. The run
+// continues in the background after the request that triggered it returns.
+func (s *Scheduler) Trigger(ctx context.Context, name string) error {
+	j, err := s.get(name)
+	if err != nil {
+		return err
+	}
+
+	if !s.markRunning(j) {
+		return domain.ErrJobAlreadyRunning
+	}
+
+	acquired, err := s.repository.AcquireLease(ctx, j.name, s.owner, j.timeout)
+	if err != nil {
+		s.markIdle(j)
+		return err
+	}
+
+	if !acquired {
+		s.markIdle(j)
+		return domain.ErrJobAlreadyRunning
+	}
+
+	s.mu.Lock()
+	runCtx := s.ctx
+	s.mu.Unlock()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer s.markIdle(j)
+
+		s.execute(runCtx, j, domain.TriggerManual)
+	}()
+
+	return nil
+}
+
+// execute runs a job whose lease is held and records the run in the history.
+func (s *Scheduler) execute(ctx context.Context, j *job, trigger string) {
+	defer func() {
+		if err := s.repository.ReleaseLease(context.Background(), j.name, s.owner); err != nil {
+			log.Print(err)
+		}
+	}()
+
+	run, err := s.repository.CreateRun(ctx, &domain.JobRun{
+		JobName:   j.name,
+		Owner:     s.owner,
+		Trigger:   trigger,
+		Status:    domain.RunStatusRunning,
+		StartedAt: time.Now(),
+	})
+	if err != nil {
+		log.Print(err)
+		return
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, j.timeout)
+	defer cancel()
+
+	err = safeRun(runCtx, j.run)
+
+	finishedAt := time.Now()
+	run.FinishedAt = &finishedAt
+	run.DurationMs = finishedAt.Sub(run.StartedAt).Milliseconds()
+	run.Status = domain.RunStatusSucceeded
+
+	if err != nil {
+		run.Status = domain.RunStatusFailed
+		run.Error = err.Error()
+	}
+
+	if err := s.repository.FinishRun(context.Background(), run); err != nil {
+		log.Print(err)
+	}
+}
+
+func safeRun(ctx context.Context, run JobFunc) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	return run(ctx)
+}
+
+func (s *Scheduler) get(name string) (*job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j, ok := s.jobs[name]
+	if !ok {
+		return nil, domain.ErrJobNotFound
+	}
+
+	return j, nil
+}
+
+func (s *Scheduler) due(now time.Time) []dueRun {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var due []dueRun
+	for _, name := range s.names {
+		j := s.jobs[name]
+		if j.nextRunAt.After(now) {
+			continue
+		}
+
+		due = append(due, dueRun{job: j, slot: j.nextRunAt})
+		j.nextRunAt = j.schedule.Next(now)
+	}
+
+	return due
+}
+
+func (s *Scheduler) markRunning(j *job) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if j.running {
+		return false
+	}
+
+	j.running = true
+	return true
+}
+
+func (s *Scheduler) markIdle(j *job) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j.running = false
+}
@@ -0,0 +1,352 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/jobs/domain"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/jobs/domain/mocks"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/jobs/service"
+)
+
+func TestParseSchedule(t *testing.T) {
+	from := time.Date(2022, time.March, 10, 14, 7, 30, 0, time.UTC)
+
+	cases := []struct {
+		spec string
+		next time.Time
+	}{
+		{"* * * * *", time.Date(2022, time.March, 10, 14, 8, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2022, time.March, 10, 14, 15, 0, 0, time.UTC)},
+		{"0 3 * * *", time.Date(2022, time.March, 11, 3, 0, 0, 0, time.UTC)},
+		{"30 9-17 * * 1-5", time.Date(2022, time.March, 10, 14, 30, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2022, time.April, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2022, time.March, 13, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 12 1,15 * *", time.Date(2022, time.March, 15, 12, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2022, time.March, 11, 0, 0, 0, 0, time.UTC)},
+		{"@every 10s", time.Date(2022, time.March, 10, 14, 7, 40, 0, time.UTC)},
+	}
+
+	for _, c := range cases {
+		t.Run("parse_ok: "+c.spec, func(t *testing.T) {
+			schedule, err := service.ParseSchedule(c.spec)
+
+			assert.NoError(t, err)
+			assert.Equal(t, c.next, schedule.Next(from))
+		})
+	}
+
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "5-1 * * * *", "*/0 * * * *", "a * * * *", "@every 1ms", "@every soon", "0 0 31 2 *", "0 0 30,31 2 *"} {
+		t.Run("parse_invalid: "+spec, func(t *testing.T) {
+			_, err := service.ParseSchedule(spec)
+
+			assert.ErrorIs(t, err, domain.ErrInvalidSchedule)
+		})
+	}
+}
+
+func TestScheduler_Register(t *testing.T) {
+	t.Run("register_invalid_schedule: should not register a job with an invalid schedule", func(t *testing.T) {
+		scheduler := service.NewScheduler(mocks.NewJobRepository(t))
+
+		err := scheduler.Register("job", "never", time.Minute, func(ctx context.Context) error { return nil })
+
+		assert.ErrorIs(t, err, domain.ErrInvalidSchedule)
+	})
+
+	t.Run("register_duplicated: should not register two jobs with the same name", func(t *testing.T) {
+		scheduler := service.NewScheduler(mocks.NewJobRepository(t))
+
+		assert.NoError(t, scheduler.Register("job", "@hourly", time.Minute, func(ctx context.Context) error { return nil }))
+
+		err := scheduler.Register("job", "@daily", time.Minute, func(ctx context.Context) error { return nil })
+
+		assert.ErrorIs(t, err, domain.ErrJobAlreadyExists)
+	})
+}
+
+func TestScheduler_RunDue(t *testing.T) {
+	t.Run("run_due_ok: should run due jobs holding the lease and record the run", func(t *testing.T) {
+		repo := mocks.NewJobRepository(t)
+		scheduler := service.NewScheduler(repo)
+
+		runs := 0
+		assert.NoError(t, scheduler.Register("job", "@every 1m", time.Minute, func(ctx context.Context) error {
+			runs++
+			return nil
+		}))
+
+		repo.On("GetPaused", mock.Anything).Return(map[string]bool{}, nil).Once()
+		repo.On("AcquireSlot", mock.Anything, "job", mock.Anything, mock.Anything, time.Minute).Return(true, nil).Once()
+		repo.
+			On("CreateRun", mock.Anything, mock.MatchedBy(func(run *domain.JobRun) bool {
+				return run.JobName == "job" && run.Trigger == domain.TriggerScheduled && run.Status == domain.RunStatusRunning
+			})).
+			Return(func(ctx context.Context, run *domain.JobRun) *domain.JobRun { return run }, nil).
+			Once()
+		repo.
+			On("FinishRun", mock.Anything, mock.MatchedBy(func(run *domain.JobRun) bool {
+				return run.Status == domain.RunStatusSucceeded && run.FinishedAt != nil && run.Error == ""
+			})).
+			Return(nil).
+			Once()
+		repo.On("ReleaseLease", mock.Anything, "job", mock.Anything).Return(nil).Once()
+
+		scheduler.RunDue(context.TODO(), time.Now().Add(time.Minute))
+		scheduler.Wait()
+
+		assert.Equal(t, 1, runs)
+	})
+
+	t.Run("run_due_failed: should record the error of a failed run", func(t *testing.T) {
+		repo := mocks.NewJobRepository(t)
+		scheduler := service.NewScheduler(repo)
+
+		assert.NoError(t, scheduler.Register("job", "@every 1m", time.Minute, func(ctx context.Context) error {
+			panic("boom")
+		}))
+
+		repo.On("GetPaused", mock.Anything).Return(map[string]bool{}, nil).Once()
+		repo.On("AcquireSlot", mock.Anything, "job", mock.Anything, mock.Anything, time.Minute).Return(true, nil).Once()
+		repo.On("CreateRun", mock.Anything, mock.Anything).
+			Return(func(ctx context.Context, run *domain.JobRun) *domain.JobRun { return run }, nil).
+			Once()
+		repo.
+			On("FinishRun", mock.Anything, mock.MatchedBy(func(run *domain.JobRun) bool {
+				return run.Status == domain.RunStatusFailed && run.Error == "panic: boom"
+			})).
+			Return(nil).
+			Once()
+		repo.On("ReleaseLease", mock.Anything, "job", mock.Anything).Return(nil).Once()
+
+		scheduler.RunDue(context.TODO(), time.Now().Add(time.Minute))
+		scheduler.Wait()
+	})
+
+	t.Run("run_due_lease_held: should skip the job when another replica holds the lease or ran the slot", func(t *testing.T) {
+		repo := mocks.NewJobRepository(t)
+		scheduler := service.NewScheduler(repo)
+
+		runs := 0
+		assert.NoError(t, scheduler.Register("job", "@every 1m", time.Minute, func(ctx context.Context) error {
+			runs++
+			return nil
+		}))
+
+		repo.On("GetPaused", mock.Anything).Return(map[string]bool{}, nil).Once()
+		repo.On("AcquireSlot", mock.Anything, "job", mock.Anything, mock.Anything, time.Minute).Return(false, nil).Once()
+
+		scheduler.RunDue(context.TODO(), time.Now().Add(time.Minute))
+		scheduler.Wait()
+
+		assert.Equal(t, 0, runs)
+	})
+
+	t.Run("run_due_replicas: should run a slot once when two replicas pick it up one after the other", func(t *testing.T) {
+		repo := mocks.NewJobRepository(t)
+		first := service.NewScheduler(repo)
+		second := service.NewScheduler(repo)
+
+		var mu sync.Mutex
+		runs := 0
+		run := func(ctx context.Context) error {
+			mu.Lock()
+			defer mu.Unlock()
+			runs++
+			return nil
+		}
+
+		assert.NoError(t, first.Register("job", "@daily", time.Minute, run))
+		assert.NoError(t, second.Register("job", "@daily", time.Minute, run))
+
+		claimed := map[time.Time]bool{}
+		repo.On("GetPaused", mock.Anything).Return(map[string]bool{}, nil).Twice()
+		repo.
+			On("AcquireSlot", mock.Anything, "job", mock.Anything, mock.Anything, time.Minute).
+			Return(func(ctx context.Context, name string, owner string, slot time.Time, ttl time.Duration) bool {
+				mu.Lock()
+				defer mu.Unlock()
+
+				if claimed[slot] {
+					return false
+				}
+				claimed[slot] = true
+				return true
+			}, nil).
+			Twice()
+		repo.On("CreateRun", mock.Anything, mock.Anything).
+			Return(func(ctx context.Context, run *domain.JobRun) *domain.JobRun { return run }, nil).
+			Once()
+		repo.On("FinishRun", mock.Anything, mock.Anything).Return(nil).Once()
+		repo.On("ReleaseLease", mock.Anything, "job", mock.Anything).Return(nil).Once()
+
+		now := time.Now().Add(24 * time.Hour)
+
+		first.RunDue(context.TODO(), now)
+		first.Wait()
+
+		// The second replica reaches the slot after the first one released it.
+		second.RunDue(context.TODO(), now.Add(time.Second))
+		second.Wait()
+
+		assert.Equal(t, 1, runs)
+	})
+
+	t.Run("run_due_paused: should skip paused jobs", func(t *testing.T) {
+		repo := mocks.NewJobRepository(t)
+		scheduler := service.NewScheduler(repo)
+
+		runs := 0
+		assert.NoError(t, scheduler.Register("job", "@every 1m", time.Minute, func(ctx context.Context) error {
+			runs++
+			return nil
+		}))
+
+		repo.On("GetPaused", mock.Anything).Return(map[string]bool{"job": true}, nil).Once()
+
+		scheduler.RunDue(context.TODO(), time.Now().Add(time.Minute))
+		scheduler.Wait()
+
+		assert.Equal(t, 0, runs)
+	})
+
+	t.Run("run_due_not_due: should not run jobs before their time", func(t *testing.T) {
+		repo := mocks.NewJobRepository(t)
+		scheduler := service.NewScheduler(repo)
+
+		assert.NoError(t, scheduler.Register("job", "@every 1m", time.Minute, func(ctx context.Context) error {
+			return nil
+		}))
+
+		scheduler.RunDue(context.TODO(), time.Now())
+		scheduler.Wait()
+	})
+}
+
+func TestScheduler_Trigger(t *testing.T) {
+	t.Run("trigger_ok: should run the job now", func(t *testing.T) {
+		repo := mocks.NewJobRepository(t)
+		scheduler := service.NewScheduler(repo)
+
+		runs := 0
+		assert.NoError(t, scheduler.Register("job", "@daily", time.Minute, func(ctx context.Context) error {
+			runs++
+			return nil
+		}))
+
+		repo.On("AcquireLease", mock.Anything, "job", mock.Anything, time.Minute).Return(true, nil).Once()
+		repo.
+			On("CreateRun", mock.Anything, mock.MatchedBy(func(run *domain.JobRun) bool {
+				return run.Trigger == domain.TriggerManual
+			})).
+			Return(func(ctx context.Context, run *domain.JobRun) *domain.JobRun { return run }, nil).
+			Once()
+		repo.On("FinishRun", mock.Anything, mock.Anything).Return(nil).Once()
+		repo.On("ReleaseLease", mock.Anything, "job", mock.Anything).Return(nil).Once()
+
+		err := scheduler.Trigger(context.TODO(), "job")
+		scheduler.Wait()
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, runs)
+	})
+
+	t.Run("trigger_running: should not run a job that is already running", func(t *testing.T) {
+		repo := mocks.NewJobRepository(t)
+		scheduler := service.NewScheduler(repo)
+
+		assert.NoError(t, scheduler.Register("job", "@daily", time.Minute, func(ctx context.Context) error {
+			return nil
+		}))
+
+		repo.On("AcquireLease", mock.Anything, "job", mock.Anything, time.Minute).Return(false, nil).Once()
+
+		err := scheduler.Trigger(context.TODO(), "job")
+
+		assert.ErrorIs(t, err, domain.ErrJobAlreadyRunning)
+	})
+
+	t.Run("trigger_not_found: should return error for unknown jobs", func(t *testing.T) {
+		scheduler := service.NewScheduler(mocks.NewJobRepository(t))
+
+		err := scheduler.Trigger(context.TODO(), "unknown")
+
+		assert.ErrorIs(t, err, domain.ErrJobNotFound)
+	})
+}
+
+func TestScheduler_Pause(t *testing.T) {
+	t.Run("pause_ok: should store the job as paused", func(t *testing.T) {
+		repo := mocks.NewJobRepository(t)
+		scheduler := service.NewScheduler(repo)
+
+		assert.NoError(t, scheduler.Register("job", "@daily", time.Minute, func(ctx context.Context) error { return nil }))
+
+		repo.On("SetPaused", mock.Anything, "job", true).Return(nil).Once()
+
+		assert.NoError(t, scheduler.Pause(context.TODO(), "job"))
+	})
+
+	t.Run("resume_ok: should store the job as not paused", func(t *testing.T) {
+		repo := mocks.NewJobRepository(t)
+		scheduler := service.NewScheduler(repo)
+
+		assert.NoError(t, scheduler.Register("job", "@daily", time.Minute, func(ctx context.Context) error { return nil }))
+
+		repo.On("SetPaused", mock.Anything, "job", false).Return(nil).Once()
+
+		assert.NoError(t, scheduler.Resume(context.TODO(), "job"))
+	})
+
+	t.Run("pause_not_found: should return error for unknown jobs", func(t *testing.T) {
+		scheduler := service.NewScheduler(mocks.NewJobRepository(t))
+
+		err := scheduler.Pause(context.TODO(), "unknown")
+
+		assert.ErrorIs(t, err, domain.ErrJobNotFound)
+	})
+}
+
+func TestScheduler_GetAll(t *testing.T) {
+	t.Run("get_all_ok: should list jobs with their state and last run", func(t *testing.T) {
+		repo := mocks.NewJobRepository(t)
+		scheduler := service.NewScheduler(repo)
+
+		assert.NoError(t, scheduler.Register("webhooks.dispatch", "@every 10s", time.Minute, func(ctx context.Context) error { return nil }))
+		assert.NoError(t, scheduler.Register("logs.retention", "0 3 * * *", time.Minute, func(ctx context.Context) error { return nil }))
+
+		lastRun := domain.JobRun{Id: 1, JobName: "logs.retention", Status: domain.RunStatusSucceeded}
+
+		repo.On("GetPaused", mock.Anything).Return(map[string]bool{"logs.retention": true}, nil).Once()
+		repo.On("GetRuns", mock.Anything, "webhooks.dispatch", int64(1)).Return([]domain.JobRun{}, nil).Once()
+		repo.On("GetRuns", mock.Anything, "logs.retention", int64(1)).Return([]domain.JobRun{lastRun}, nil).Once()
+
+		jobs, err := scheduler.GetAll(context.TODO())
+
+		assert.NoError(t, err)
+		assert.Len(t, jobs, 2)
+		assert.Equal(t, "webhooks.dispatch", jobs[0].Name)
+		assert.False(t, jobs[0].Paused)
+		assert.Nil(t, jobs[0].LastRun)
+		assert.Equal(t, "logs.retention", jobs[1].Name)
+		assert.True(t, jobs[1].Paused)
+		assert.Equal(t, &lastRun, jobs[1].LastRun)
+	})
+
+	t.Run("get_all_error: should return error when the pause state cannot be read", func(t *testing.T) {
+		repo := mocks.NewJobRepository(t)
+		scheduler := service.NewScheduler(repo)
+
+		repo.On("GetPaused", mock.Anything).Return(nil, errors.New("any error")).Once()
+
+		_, err := scheduler.GetAll(context.TODO())
+
+		assert.Error(t, err)
+	})
+}
@@ -23,6 +23,7 @@ type ReportSeller struct {
 }
 
 type LocalityService interface {
+	GetAll(ctx context.Context) (*[]LocalityModel, error)
 	ReportCarrie(ctx context.Context, locality_id int64) (*[]ReportCarrie, error)
 	CreateLocality(ctx context.Context, locality *LocalityModel) (*LocalityModel, error)
 	GetByIdReportSeller(ctx context.Context, locality_id int64) (*[]ReportSeller, error)
@@ -30,6 +31,7 @@ type LocalityService interface {
 }
 
 type LocalityRepository interface {
+	GetAll(ctx context.Context) (*[]LocalityModel, error)
 	GetById(ctx context.Context, id int64) (*LocalityModel, error)
 	GetAllReportSeller(ctx context.Context) (*[]ReportSeller, error)
 	ReportCarrie(ctx context.Context, id int64) (*[]ReportCarrie, error)
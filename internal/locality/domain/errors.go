@@ -0,0 +1,5 @@
+package domain
+
+import "errors"
+
+var ErrLocalityNotFound = errors.New("locality not found")
@@ -37,6 +37,29 @@ func (_m *LocalityRepository) CreateLocality(ctx context.Context, locality *doma
 	return r0, r1
 }
 
+// GetAll provides a mock function with given fields: ctx
+func (_m *LocalityRepository) GetAll(ctx context.Context) (*[]domain.LocalityModel, error) {
+	ret := _m.Called(ctx)
+
+	var r0 *[]domain.LocalityModel
+	if rf, ok := ret.Get(0).(func(context.Context) *[]domain.LocalityModel); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]domain.LocalityModel)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetAllReportSeller provides a mock function with given fields: ctx
 func (_m *LocalityRepository) GetAllReportSeller(ctx context.Context) (*[]domain.ReportSeller, error) {
 	ret := _m.Called(ctx)
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"log"
 
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/locality/domain"
@@ -23,18 +24,51 @@ func (m repository) GetById(ctx context.Context, id int64) (*domain.LocalityMode
 
 	var locality domain.LocalityModel
 
-	if err := result.Scan(
+	err := result.Scan(
 		&locality.Id,
 		&locality.CountryName,
 		&locality.ProvinceName,
 		&locality.LocalityName,
-	); err != nil {
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrLocalityNotFound
+	}
+	if err != nil {
 		return nil, err
 	}
 
 	return &locality, nil
 }
 
+func (m repository) GetAll(ctx context.Context) (*[]domain.LocalityModel, error) {
+	rows, err := m.db.QueryContext(ctx, QueryGetAll)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	localities := []domain.LocalityModel{}
+
+	for rows.Next() {
+		var locality domain.LocalityModel
+
+		if err := rows.Scan(
+			&locality.Id,
+			&locality.LocalityName,
+			&locality.ProvinceName,
+			&locality.CountryName,
+			&locality.ProvinceId,
+		); err != nil {
+			return nil, err
+		}
+
+		localities = append(localities, locality)
+	}
+
+	return &localities, nil
+}
+
 func (m repository) ReportCarrie(ctx context.Context, id int64) (*[]domain.ReportCarrie, error) {
 	result, err := m.db.QueryContext(ctx, QueryCarryReport, id, id)
 
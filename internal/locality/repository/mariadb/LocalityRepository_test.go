@@ -2,6 +2,7 @@ package repository_test
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"regexp"
 	"testing"
@@ -97,6 +98,70 @@ func Test_GetByIdRepository(t *testing.T) {
 		assert.Error(t, err)
 		assert.Empty(t, result)
 	})
+	t.Run("get_by_id_not_found: should return ErrLocalityNotFound", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		localityRepository := repository.NewMariadbLocalityRepository(db)
+
+		mock.
+			ExpectQuery(regexp.QuoteMeta(repository.QueryGetById)).
+			WithArgs(int64(9999)).
+			WillReturnError(sql.ErrNoRows)
+
+		result, err := localityRepository.GetById(context.TODO(), int64(9999))
+
+		assert.ErrorIs(t, err, domain.ErrLocalityNotFound)
+		assert.Nil(t, result)
+	})
+}
+
+func Test_GetAllRepository(t *testing.T) {
+	t.Run("get_all_ok: should return all localities", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		rows := sqlmock.NewRows([]string{
+			"id",
+			"locality_name",
+			"province_name",
+			"country_name",
+			"province_id",
+		}).AddRow(1, "Salvador", "Bahia", "Brasil", 1)
+
+		localityRepository := repository.NewMariadbLocalityRepository(db)
+
+		mock.
+			ExpectQuery(regexp.QuoteMeta(repository.QueryGetAll)).
+			WillReturnRows(rows)
+
+		result, err := localityRepository.GetAll(context.TODO())
+
+		expected := expectedLocality
+		expected.ProvinceId = 1
+
+		assert.Nil(t, err)
+		assert.Equal(t, &[]domain.LocalityModel{expected}, result)
+	})
+
+	t.Run("error_query_get_all: return error when try exec query", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		localityRepository := repository.NewMariadbLocalityRepository(db)
+
+		mock.
+			ExpectQuery(regexp.QuoteMeta(repository.QueryGetAll)).
+			WillReturnError(fmt.Errorf("error: invalid query"))
+
+		result, err := localityRepository.GetAll(context.TODO())
+
+		assert.Nil(t, result)
+		assert.Error(t, err)
+	})
 }
 
 func Test_GetAllReportSellerRepository(t *testing.T) {
@@ -9,6 +9,14 @@ const (
         where l.id = ?
     `
 
+	QueryGetAll = `
+        select l.id, l.locality_name, p.province_name, c.country_name, p.id as province_id
+        from localities l
+        inner join provinces p on p.id = l.province_id
+        inner join countries c on c.id = p.country_id
+        order by l.id
+    `
+
 	QueryCarryReport = `
     select 
     c.locality_id,
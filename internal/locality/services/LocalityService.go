@@ -21,6 +21,16 @@ func NewLocalityService(repoLocality locality.LocalityRepository, repoSeller sel
 	}
 }
 
+func (s service) GetAll(ctx context.Context) (*[]domain.LocalityModel, error) {
+	localities, err := s.repoLocality.GetAll(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return localities, nil
+}
+
 func (s service) ReportCarrie(ctx context.Context, locality_id int64) (*[]domain.ReportCarrie, error) {
 	localities, err := s.repoLocality.ReportCarrie(ctx, locality_id)
 
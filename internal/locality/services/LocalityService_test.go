@@ -133,6 +133,42 @@ func Test_GetByIdReportSeller(t *testing.T) {
 	})
 }
 
+func Test_GetAllLocalities(t *testing.T) {
+	repoLocality := mocks.NewLocalityRepository(t)
+	repoSeller := mocksSeller.NewRepositorySeller(t)
+	ctx := context.Background()
+
+	t.Run("GetAll_ok: should return locality list", func(t *testing.T) {
+		expected := []domain.LocalityModel{expectedLocality}
+
+		repoLocality.
+			On("GetAll", ctx).
+			Return(&expected, nil).
+			Once()
+
+		service := services.NewLocalityService(repoLocality, repoSeller)
+
+		result, err := service.GetAll(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &expected, result)
+	})
+
+	t.Run("GetAll_error: should return an error", func(t *testing.T) {
+		repoLocality.
+			On("GetAll", ctx).
+			Return(nil, errors.New("error")).
+			Once()
+
+		service := services.NewLocalityService(repoLocality, repoSeller)
+
+		result, err := service.GetAll(ctx)
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+	})
+}
+
 func Test_GetAllReportSeller(t *testing.T) {
 	repoLocality := mocks.NewLocalityRepository(t)
 	repoSeller := mocksSeller.NewRepositorySeller(t)
@@ -0,0 +1,148 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Sources of the prefix of generated product codes.
+const (
+	PrefixByNone        = "none"
+	PrefixByProductType = "product_type"
+	PrefixBySeller      = "seller"
+)
+
+const maxProductCodeLength = 64
+
+var freeProductCode = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// CodeFormat describes product codes as PREFIX-SEQUENCE, where the sequence
+// is zero padded to Digits and, with CheckDigit, followed by its Luhn digit.
+// The prefix is the fixed Prefix, the first letters of the product type
+// description or "S" plus the seller id, depending on PrefixBy.
+//
+// Codes are always generated in this format. Supplied codes only have to
+// match it when Strict is set; otherwise any code of letters, digits, dots,
+// dashes and underscores is accepted.
+type CodeFormat struct {
+	PrefixBy   string
+	Prefix     string
+	Digits     int
+	CheckDigit bool
+	Strict     bool
+}
+
+var DefaultCodeFormat = CodeFormat{
+	PrefixBy: PrefixByNone,
+	Prefix:   "PRD",
+	Digits:   6,
+}
+
+func (f CodeFormat) Valid() error {
+	switch f.PrefixBy {
+	case PrefixByNone:
+		if !freeProductCode.MatchString(f.Prefix) || strings.Contains(f.Prefix, "-") {
+			return fmt.Errorf("invalid product code prefix %q", f.Prefix)
+		}
+	case PrefixByProductType, PrefixBySeller:
+	default:
+		return fmt.Errorf("invalid product code prefix source %q", f.PrefixBy)
+	}
+	if f.Digits < 1 || f.Digits > 18 {
+		return fmt.Errorf("product code digits must be between 1 and 18, got %d", f.Digits)
+	}
+	return nil
+}
+
+// CodePrefix returns the prefix of the codes of a product of the given type
+// and seller.
+func (f CodeFormat) CodePrefix(productTypeId int64, productTypeDescription string, sellerId int64) string {
+	switch f.PrefixBy {
+	case PrefixByProductType:
+		var letters []rune
+		for _, r := range strings.ToUpper(productTypeDescription) {
+			if r <= unicode.MaxASCII && unicode.IsLetter(r) {
+				letters = append(letters, r)
+			}
+			if len(letters) == 3 {
+				break
+			}
+		}
+		if len(letters) == 0 {
+			return "T" + strconv.FormatInt(productTypeId, 10)
+		}
+		return string(letters)
+	case PrefixBySeller:
+		return "S" + strconv.FormatInt(sellerId, 10)
+	default:
+		return f.Prefix
+	}
+}
+
+func (f CodeFormat) Code(prefix string, sequence int64) string {
+	digits := fmt.Sprintf("%0*d", f.Digits, sequence)
+	if f.CheckDigit {
+		digits += strconv.Itoa(luhnDigit(digits))
+	}
+	return prefix + "-" + digits
+}
+
+// Sequence extracts the sequence from the numeric suffix of a code, as
+// stored after the prefix and the dash.
+func (f CodeFormat) Sequence(suffix int64) int64 {
+	if f.CheckDigit {
+		return suffix / 10
+	}
+	return suffix
+}
+
+// Validate checks a supplied code for a product whose codes use prefix.
+func (f CodeFormat) Validate(code, prefix string) error {
+	if code == "" {
+		return ErrInvalidProductCode
+	}
+	if len(code) > maxProductCodeLength || !freeProductCode.MatchString(code) {
+		return ErrProductCodeFormat
+	}
+	if !f.Strict {
+		return nil
+	}
+
+	digits := strings.TrimPrefix(code, prefix+"-")
+	length := f.Digits
+	if f.CheckDigit {
+		length++
+	}
+	if digits == code || len(digits) != length {
+		return ErrProductCodeFormat
+	}
+	for _, r := range digits {
+		if r < '0' || r > '9' {
+			return ErrProductCodeFormat
+		}
+	}
+	if f.CheckDigit && luhnDigit(digits[:f.Digits]) != int(digits[f.Digits]-'0') {
+		return ErrProductCodeFormat
+	}
+	return nil
+}
+
+func luhnDigit(digits string) int {
+	sum := 0
+	double := true
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return (10 - sum%10) % 10
+}
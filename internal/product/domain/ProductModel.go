@@ -17,6 +17,64 @@ type Product struct {
 	SellerId                       int64   `json:"seller_id"`
 }
 
+// Bounds for RecommendedFreezingTemperature, in degrees Celsius. Anything
+// outside them is a typo rather than a storage requirement.
+const (
+	MinFreezingTemperature = -60.0
+	MaxFreezingTemperature = 30.0
+)
+
+// ProductPatch carries a partial update; nil fields keep their current value.
+type ProductPatch struct {
+	ProductCode                    *string
+	Description                    *string
+	Width                          *float64
+	Height                         *float64
+	Length                         *float64
+	NetWeight                      *float64
+	ExpirationRate                 *float64
+	RecommendedFreezingTemperature *float64
+	FreezingRate                   *float64
+	ProductTypeId                  *int64
+	SellerId                       *int64
+}
+
+func (p ProductPatch) Apply(product *Product) {
+	if p.ProductCode != nil {
+		product.ProductCode = *p.ProductCode
+	}
+	if p.Description != nil {
+		product.Description = *p.Description
+	}
+	if p.Width != nil {
+		product.Width = *p.Width
+	}
+	if p.Height != nil {
+		product.Height = *p.Height
+	}
+	if p.Length != nil {
+		product.Length = *p.Length
+	}
+	if p.NetWeight != nil {
+		product.NetWeight = *p.NetWeight
+	}
+	if p.ExpirationRate != nil {
+		product.ExpirationRate = *p.ExpirationRate
+	}
+	if p.RecommendedFreezingTemperature != nil {
+		product.RecommendedFreezingTemperature = *p.RecommendedFreezingTemperature
+	}
+	if p.FreezingRate != nil {
+		product.FreezingRate = *p.FreezingRate
+	}
+	if p.ProductTypeId != nil {
+		product.ProductTypeId = *p.ProductTypeId
+	}
+	if p.SellerId != nil {
+		product.SellerId = *p.SellerId
+	}
+}
+
 type ProductRecordsReport struct {
 	Id                  int64  `json:"id"`
 	Description         string `json:"description"`
@@ -26,7 +84,10 @@ type ProductRecordsReport struct {
 type ProductRepository interface {
 	GetAll(ctx context.Context) (*[]Product, error)
 	GetById(ctx context.Context, id int64) (*Product, error)
+	GetByProductCode(ctx context.Context, productCode string) (*Product, error)
+	GetMaxCodeSuffix(ctx context.Context, prefix string) (int64, error)
 	Create(ctx context.Context, product *Product) (*Product, error)
+	Update(ctx context.Context, product *Product) (*Product, error)
 	UpdateDescription(ctx context.Context, product *Product) (*Product, error)
 	Delete(ctx context.Context, id int64) error
 	GetAllReportProductRecords(ctx context.Context) (*[]ProductRecordsReport, error)
@@ -36,6 +97,8 @@ type ProductService interface {
 	GetAll(ctx context.Context) (*[]Product, error)
 	GetById(ctx context.Context, id int64) (*Product, error)
 	Create(ctx context.Context, product *Product) (*Product, error)
+	Update(ctx context.Context, id int64, patch ProductPatch) (*Product, error)
+	Replace(ctx context.Context, id int64, product *Product) (*Product, error)
 	UpdateDescription(ctx context.Context, id int64, description string) (*Product, error)
 	Delete(ctx context.Context, id int64) error
 	GetReportProductRecordsById(ctx context.Context, id int64) (*[]ProductRecordsReport, error)
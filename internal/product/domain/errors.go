@@ -3,5 +3,13 @@ package domain
 import "errors"
 
 var (
-	ErrProductIdNotFound = errors.New("product id not found")
+	ErrProductIdNotFound          = errors.New("product id not found")
+	ErrProductCodeAlreadyExists   = errors.New("the product code has already been registered")
+	ErrInvalidProductCode         = errors.New("product code must not be empty")
+	ErrProductCodeFormat          = errors.New("product code does not match the configured format")
+	ErrInvalidDescription         = errors.New("description must not be empty")
+	ErrInvalidDimensions          = errors.New("width, height and length must be positive")
+	ErrInvalidNetWeight           = errors.New("net weight must be positive")
+	ErrInvalidRate                = errors.New("expiration rate and freezing rate must not be negative")
+	ErrInvalidFreezingTemperature = errors.New("recommended freezing temperature must be between -60 and 30")
 )
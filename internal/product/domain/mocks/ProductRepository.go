@@ -120,6 +120,73 @@ func (_m *ProductRepository) GetById(ctx context.Context, id int64) (*domain.Pro
 	return r0, r1
 }
 
+// GetByProductCode provides a mock function with given fields: ctx, productCode
+func (_m *ProductRepository) GetByProductCode(ctx context.Context, productCode string) (*domain.Product, error) {
+	ret := _m.Called(ctx, productCode)
+
+	var r0 *domain.Product
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.Product); ok {
+		r0 = rf(ctx, productCode)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Product)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, productCode)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetMaxCodeSuffix provides a mock function with given fields: ctx, prefix
+func (_m *ProductRepository) GetMaxCodeSuffix(ctx context.Context, prefix string) (int64, error) {
+	ret := _m.Called(ctx, prefix)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, string) int64); ok {
+		r0 = rf(ctx, prefix)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, prefix)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: ctx, product
+func (_m *ProductRepository) Update(ctx context.Context, product *domain.Product) (*domain.Product, error) {
+	ret := _m.Called(ctx, product)
+
+	var r0 *domain.Product
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Product) *domain.Product); ok {
+		r0 = rf(ctx, product)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Product)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.Product) error); ok {
+		r1 = rf(ctx, product)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpdateDescription provides a mock function with given fields: ctx, product
 func (_m *ProductRepository) UpdateDescription(ctx context.Context, product *domain.Product) (*domain.Product, error) {
 	ret := _m.Called(ctx, product)
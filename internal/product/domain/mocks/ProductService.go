@@ -143,6 +143,52 @@ func (_m *ProductService) GetReportProductRecordsById(ctx context.Context, id in
 	return r0, r1
 }
 
+// Replace provides a mock function with given fields: ctx, id, product
+func (_m *ProductService) Replace(ctx context.Context, id int64, product *domain.Product) (*domain.Product, error) {
+	ret := _m.Called(ctx, id, product)
+
+	var r0 *domain.Product
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *domain.Product) *domain.Product); ok {
+		r0 = rf(ctx, id, product)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Product)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, *domain.Product) error); ok {
+		r1 = rf(ctx, id, product)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: ctx, id, patch
+func (_m *ProductService) Update(ctx context.Context, id int64, patch domain.ProductPatch) (*domain.Product, error) {
+	ret := _m.Called(ctx, id, patch)
+
+	var r0 *domain.Product
+	if rf, ok := ret.Get(0).(func(context.Context, int64, domain.ProductPatch) *domain.Product); ok {
+		r0 = rf(ctx, id, patch)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Product)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, domain.ProductPatch) error); ok {
+		r1 = rf(ctx, id, patch)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpdateDescription provides a mock function with given fields: ctx, id, description
 func (_m *ProductService) UpdateDescription(ctx context.Context, id int64, description string) (*domain.Product, error) {
 	ret := _m.Called(ctx, id, description)
@@ -4,10 +4,19 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"strings"
+	"unicode/utf8"
 
+	"github.com/go-sql-driver/mysql"
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product/domain"
 )
 
+// errDuplicateEntry is the MariaDB error number for a UNIQUE index
+// violation; product_code is the only unique column besides the id.
+const errDuplicateEntry = 1062
+
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
 type mariaDBProductRepository struct {
 	db *sql.DB
 }
@@ -83,6 +92,53 @@ func (m mariaDBProductRepository) GetById(ctx context.Context, id int64) (*domai
 	return &product, nil
 }
 
+func (m mariaDBProductRepository) GetByProductCode(ctx context.Context, productCode string) (*domain.Product, error) {
+	row := m.db.QueryRowContext(ctx, SqlGetByProductCode, productCode)
+
+	var product domain.Product
+
+	err := row.Scan(
+		&product.Id,
+		&product.ProductCode,
+		&product.Description,
+		&product.Width,
+		&product.Height,
+		&product.Length,
+		&product.NetWeight,
+		&product.ExpirationRate,
+		&product.RecommendedFreezingTemperature,
+		&product.FreezingRate,
+		&product.ProductTypeId,
+		&product.SellerId)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrProductIdNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &product, nil
+}
+
+func (m mariaDBProductRepository) GetMaxCodeSuffix(ctx context.Context, prefix string) (int64, error) {
+	var suffix int64
+
+	err := m.db.QueryRowContext(ctx, SqlGetMaxCodeSuffix, utf8.RuneCountInString(prefix)+2, escapeLike(prefix)+"-%").Scan(&suffix)
+	if err != nil {
+		return 0, err
+	}
+
+	return suffix, nil
+}
+
+// escapeLike makes the wildcards of s match literally in a LIKE pattern that
+// uses ! as its escape character.
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
 func (m mariaDBProductRepository) Create(ctx context.Context, product *domain.Product) (*domain.Product, error) {
 
 	productResult, err := m.db.ExecContext(
@@ -102,7 +158,7 @@ func (m mariaDBProductRepository) Create(ctx context.Context, product *domain.Pr
 	)
 
 	if err != nil {
-		return nil, err
+		return nil, mapError(err)
 	}
 
 	lastId, _ := productResult.LastInsertId()
@@ -112,6 +168,32 @@ func (m mariaDBProductRepository) Create(ctx context.Context, product *domain.Pr
 	return product, nil
 }
 
+func (m mariaDBProductRepository) Update(ctx context.Context, product *domain.Product) (*domain.Product, error) {
+
+	_, err := m.db.ExecContext(
+		ctx,
+		SqlUpdate,
+		&product.ProductCode,
+		&product.Description,
+		&product.Width,
+		&product.Height,
+		&product.Length,
+		&product.NetWeight,
+		&product.ExpirationRate,
+		&product.RecommendedFreezingTemperature,
+		&product.FreezingRate,
+		&product.ProductTypeId,
+		&product.SellerId,
+		&product.Id,
+	)
+
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	return product, nil
+}
+
 func (m mariaDBProductRepository) UpdateDescription(ctx context.Context, product *domain.Product) (*domain.Product, error) {
 
 	_, err := m.db.ExecContext(
@@ -170,3 +252,11 @@ func (m mariaDBProductRepository) GetAllReportProductRecords(ctx context.Context
 
 	return &result, nil
 }
+
+func mapError(err error) error {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == errDuplicateEntry {
+		return domain.ErrProductCodeAlreadyExists
+	}
+	return err
+}
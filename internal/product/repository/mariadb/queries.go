@@ -19,6 +19,29 @@ const (
 
 	SqlGetById = "SELECT * FROM products WHERE id=?"
 
+	SqlGetByProductCode = `
+	SELECT
+	id,
+	product_code,
+	description,
+	width,
+	height,
+	length,
+	net_weight,
+	expiration_rate,
+	recommended_freezing_temperature,
+	freezing_rate,
+	product_type_id,
+	seller_id FROM products
+	WHERE product_code=?
+	`
+
+	SqlGetMaxCodeSuffix = `
+	SELECT IFNULL(MAX(CAST(SUBSTRING(product_code, ?) AS UNSIGNED)), 0)
+	FROM products
+	WHERE product_code LIKE ? ESCAPE '!'
+	`
+
 	SqlCreate = `
     INSERT INTO
     products (
@@ -35,6 +58,22 @@ const (
 	seller_id
 	)
     VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+    `
+
+	SqlUpdate = `
+    UPDATE products
+    SET product_code=?,
+	description=?,
+	width=?,
+	height=?,
+	length=?,
+	net_weight=?,
+	expiration_rate=?,
+	recommended_freezing_temperature=?,
+	freezing_rate=?,
+	product_type_id=?,
+	seller_id=?
+    WHERE id=?
     `
 
 	SqlUpdateDescription = `
@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
 	"github.com/stretchr/testify/assert"
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product/domain"
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product/repository/mariadb"
@@ -324,6 +325,82 @@ func TestMariaDBProductRepository_Create(t *testing.T) {
 	})
 }
 
+func TestMariaDBProductRepository_CreateDuplicateCode(t *testing.T) {
+
+	t.Run("create_duplicate_code: should return ErrProductCodeAlreadyExists on a unique index violation", func(t *testing.T) {
+
+		db, mock, err := sqlmock.New()
+
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.
+			ExpectExec(regexp.QuoteMeta(mariadb.SqlCreate)).
+			WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'PROD01' for key 'product_code'"})
+
+		productRepository := mariadb.CreateProductRepository(db)
+		_, err = productRepository.Create(context.TODO(), &expectedProduct)
+
+		assert.ErrorIs(t, err, domain.ErrProductCodeAlreadyExists)
+	})
+}
+
+func TestMariaDBProductRepository_GetMaxCodeSuffix(t *testing.T) {
+
+	t.Run("get_max_code_suffix_ok: should return the highest numeric suffix of the prefix", func(t *testing.T) {
+
+		db, mock, err := sqlmock.New()
+
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.
+			ExpectQuery(regexp.QuoteMeta(mariadb.SqlGetMaxCodeSuffix)).
+			WithArgs(5, "PRD-%").
+			WillReturnRows(sqlmock.NewRows([]string{"suffix"}).AddRow(41))
+
+		productRepository := mariadb.CreateProductRepository(db)
+		suffix, err := productRepository.GetMaxCodeSuffix(context.TODO(), "PRD")
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(41), suffix)
+	})
+
+	t.Run("get_max_code_suffix_wildcards: should match underscores and percent signs in the prefix literally", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.
+			ExpectQuery(regexp.QuoteMeta(mariadb.SqlGetMaxCodeSuffix)).
+			WithArgs(8, "FR!_10!%-%").
+			WillReturnRows(sqlmock.NewRows([]string{"suffix"}).AddRow(3))
+
+		productRepository := mariadb.CreateProductRepository(db)
+		suffix, err := productRepository.GetMaxCodeSuffix(context.TODO(), "FR_10%")
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), suffix)
+	})
+
+	t.Run("get_max_code_suffix_fail: should return error when query fails", func(t *testing.T) {
+
+		db, mock, err := sqlmock.New()
+
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.
+			ExpectQuery(regexp.QuoteMeta(mariadb.SqlGetMaxCodeSuffix)).
+			WillReturnError(errors.New("connection lost"))
+
+		productRepository := mariadb.CreateProductRepository(db)
+		_, err = productRepository.GetMaxCodeSuffix(context.TODO(), "PRD")
+
+		assert.Error(t, err)
+	})
+}
+
 func TestSectionRepository_Update(t *testing.T) {
 
 	dummyUpdatedProduct := domain.Product{
@@ -372,6 +449,127 @@ func TestSectionRepository_Update(t *testing.T) {
 
 }
 
+func TestMariaDBProductRepository_GetByProductCode(t *testing.T) {
+
+	t.Run("get_by_product_code_ok: should return product by code", func(t *testing.T) {
+
+		db, mock, err := sqlmock.New()
+
+		assert.NoError(t, err)
+		defer db.Close()
+
+		row := sqlmock.NewRows([]string{
+			"id",
+			"product_code",
+			"description",
+			"width",
+			"height",
+			"length",
+			"net_weight",
+			"expiration_rate",
+			"recommended_freezing_temperature",
+			"freezing_rate",
+			"product_type_id",
+			"seller_id"}).
+			AddRow(
+				expectedProduct.Id,
+				expectedProduct.ProductCode,
+				expectedProduct.Description,
+				expectedProduct.Width,
+				expectedProduct.Height,
+				expectedProduct.Length,
+				expectedProduct.NetWeight,
+				expectedProduct.ExpirationRate,
+				expectedProduct.RecommendedFreezingTemperature,
+				expectedProduct.FreezingRate,
+				expectedProduct.ProductTypeId,
+				expectedProduct.SellerId)
+
+		mock.
+			ExpectQuery(regexp.QuoteMeta(mariadb.SqlGetByProductCode)).
+			WithArgs(expectedProduct.ProductCode).
+			WillReturnRows(row)
+
+		productRepository := mariadb.CreateProductRepository(db)
+
+		result, err := productRepository.GetByProductCode(context.TODO(), expectedProduct.ProductCode)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &expectedProduct, result)
+	})
+
+	t.Run("get_by_product_code_not_found: should return ErrProductIdNotFound when no product has the code", func(t *testing.T) {
+
+		db, mock, err := sqlmock.New()
+
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.
+			ExpectQuery(regexp.QuoteMeta(mariadb.SqlGetByProductCode)).
+			WithArgs("PROD99").
+			WillReturnError(sql.ErrNoRows)
+
+		productRepository := mariadb.CreateProductRepository(db)
+
+		_, err = productRepository.GetByProductCode(context.TODO(), "PROD99")
+		assert.ErrorIs(t, err, domain.ErrProductIdNotFound)
+	})
+}
+
+func TestMariaDBProductRepository_Update(t *testing.T) {
+
+	t.Run("update_ok: should update every product field", func(t *testing.T) {
+
+		db, mock, err := sqlmock.New()
+
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.
+			ExpectExec(regexp.QuoteMeta(mariadb.SqlUpdate)).
+			WithArgs(
+				expectedProduct.ProductCode,
+				expectedProduct.Description,
+				expectedProduct.Width,
+				expectedProduct.Height,
+				expectedProduct.Length,
+				expectedProduct.NetWeight,
+				expectedProduct.ExpirationRate,
+				expectedProduct.RecommendedFreezingTemperature,
+				expectedProduct.FreezingRate,
+				expectedProduct.ProductTypeId,
+				expectedProduct.SellerId,
+				expectedProduct.Id).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		productRepository := mariadb.CreateProductRepository(db)
+
+		result, err := productRepository.Update(context.TODO(), &expectedProduct)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &expectedProduct, result)
+	})
+
+	t.Run("update_fail: should return error when query execution fails", func(t *testing.T) {
+
+		db, mock, err := sqlmock.New()
+
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.
+			ExpectExec(regexp.QuoteMeta(mariadb.SqlUpdate)).
+			WillReturnError(errors.New("any error"))
+
+		productRepository := mariadb.CreateProductRepository(db)
+
+		_, err = productRepository.Update(context.TODO(), &expectedProduct)
+
+		assert.Error(t, err)
+	})
+}
+
 func TestSectionRepository_Delete(t *testing.T) {
 
 	id := int64(1)
@@ -2,19 +2,40 @@ package service
 
 import (
 	"context"
+	"errors"
+	"strings"
+
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product/domain"
 	productRecordsRepo "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_records/domain"
+	productTypeRepo "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_type/domain"
+	sellerRepo "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/seller/domain"
 )
 
+// codeAttempts bounds how many generated codes Create tries when another
+// product takes the generated code first.
+const codeAttempts = 3
+
 type productService struct {
 	productRepository        domain.ProductRepository
 	productRecordsRepository productRecordsRepo.ProductRecordsRepository
+	productTypeRepository    productTypeRepo.ProductTypeRepository
+	sellerRepository         sellerRepo.RepositorySeller
+	codeFormat               domain.CodeFormat
 }
 
-func CreateProductService(productRepository domain.ProductRepository, productRecordsRepository productRecordsRepo.ProductRecordsRepository) domain.ProductService {
+func CreateProductService(
+	productRepository domain.ProductRepository,
+	productRecordsRepository productRecordsRepo.ProductRecordsRepository,
+	productTypeRepository productTypeRepo.ProductTypeRepository,
+	sellerRepository sellerRepo.RepositorySeller,
+	codeFormat domain.CodeFormat,
+) domain.ProductService {
 	return &productService{
 		productRepository:        productRepository,
 		productRecordsRepository: productRecordsRepository,
+		productTypeRepository:    productTypeRepository,
+		sellerRepository:         sellerRepository,
+		codeFormat:               codeFormat,
 	}
 }
 
@@ -40,13 +61,136 @@ func (s *productService) GetById(ctx context.Context, id int64) (*domain.Product
 
 func (s *productService) Create(ctx context.Context, product *domain.Product) (*domain.Product, error) {
 
-	newProduct, err := s.productRepository.Create(ctx, product)
+	productType, err := s.productTypeRepository.GetById(ctx, product.ProductTypeId)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := s.codeFormat.CodePrefix(productType.Id, productType.Description, product.SellerId)
+	product.ProductCode = strings.TrimSpace(product.ProductCode)
+
+	if product.ProductCode != "" {
+		if err := s.codeFormat.Validate(product.ProductCode, prefix); err != nil {
+			return nil, err
+		}
+
+		newProduct, err := s.productRepository.Create(ctx, product)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return newProduct, nil
+	}
+
+	for attempt := 0; attempt < codeAttempts; attempt++ {
+		suffix, err := s.productRepository.GetMaxCodeSuffix(ctx, prefix)
+		if err != nil {
+			return nil, err
+		}
+
+		product.ProductCode = s.codeFormat.Code(prefix, s.codeFormat.Sequence(suffix)+1)
+
+		newProduct, err := s.productRepository.Create(ctx, product)
+		if errors.Is(err, domain.ErrProductCodeAlreadyExists) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		return newProduct, nil
+	}
+
+	return nil, domain.ErrProductCodeAlreadyExists
+}
+
+func (s *productService) Update(ctx context.Context, id int64, patch domain.ProductPatch) (*domain.Product, error) {
+
+	product, err := s.productRepository.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	currentCode := product.ProductCode
+	patch.Apply(product)
+
+	return s.save(ctx, product, currentCode)
+}
+
+func (s *productService) Replace(ctx context.Context, id int64, product *domain.Product) (*domain.Product, error) {
+
+	current, err := s.productRepository.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	product.Id = id
+	if strings.TrimSpace(product.ProductCode) == "" {
+		product.ProductCode = current.ProductCode
+	}
+
+	return s.save(ctx, product, current.ProductCode)
+}
+
+// save validates and stores a changed product. The code is only checked
+// against the format when it changes, so products keep their legacy codes.
+func (s *productService) save(ctx context.Context, product *domain.Product, currentCode string) (*domain.Product, error) {
+	product.ProductCode = strings.TrimSpace(product.ProductCode)
+	product.Description = strings.TrimSpace(product.Description)
 
+	if err := validate(product); err != nil {
+		return nil, err
+	}
+
+	productType, err := s.productTypeRepository.GetById(ctx, product.ProductTypeId)
 	if err != nil {
 		return nil, err
 	}
 
-	return newProduct, nil
+	if _, err := s.sellerRepository.GetById(ctx, product.SellerId); err != nil {
+		return nil, err
+	}
+
+	if product.ProductCode != currentCode {
+		prefix := s.codeFormat.CodePrefix(productType.Id, productType.Description, product.SellerId)
+		if err := s.codeFormat.Validate(product.ProductCode, prefix); err != nil {
+			return nil, err
+		}
+	}
+
+	current, err := s.productRepository.GetByProductCode(ctx, product.ProductCode)
+	if err == nil && current.Id != product.Id {
+		return nil, domain.ErrProductCodeAlreadyExists
+	}
+	if err != nil && !errors.Is(err, domain.ErrProductIdNotFound) {
+		return nil, err
+	}
+
+	return s.productRepository.Update(ctx, product)
+}
+
+func validate(product *domain.Product) error {
+	if product.ProductCode == "" {
+		return domain.ErrInvalidProductCode
+	}
+	if product.Description == "" {
+		return domain.ErrInvalidDescription
+	}
+	if product.Width <= 0 || product.Height <= 0 || product.Length <= 0 {
+		return domain.ErrInvalidDimensions
+	}
+	if product.NetWeight <= 0 {
+		return domain.ErrInvalidNetWeight
+	}
+	if product.ExpirationRate < 0 || product.FreezingRate < 0 {
+		return domain.ErrInvalidRate
+	}
+	if product.RecommendedFreezingTemperature < domain.MinFreezingTemperature ||
+		product.RecommendedFreezingTemperature > domain.MaxFreezingTemperature {
+		return domain.ErrInvalidFreezingTemperature
+	}
+	return nil
 }
 
 func (s *productService) UpdateDescription(ctx context.Context, id int64, description string) (*domain.Product, error) {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product/domain"
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product/domain/mocks"
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product/service"
@@ -11,6 +12,10 @@ import (
 	"testing"
 
 	mocksProductRecords "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_records/domain/mocks"
+	productType "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_type/domain"
+	mocksProductType "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_type/domain/mocks"
+	seller "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/seller/domain"
+	mocksSeller "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/seller/domain/mocks"
 )
 
 var expectedProduct = domain.Product{
@@ -38,10 +43,18 @@ func TestProductService_Create(t *testing.T) {
 	mockProductRepository := mocks.NewProductRepository(t)
 	mockRepositoryProductRecords := mocksProductRecords.NewProductRecordsRepository(t)
 
-	productService := service.CreateProductService(mockProductRepository, mockRepositoryProductRecords)
+	mockProductTypeRepository := mocksProductType.NewProductTypeRepository(t)
+	mockSellerRepository := mocksSeller.NewRepositorySeller(t)
+
+	productService := service.CreateProductService(mockProductRepository, mockRepositoryProductRecords, mockProductTypeRepository, mockSellerRepository, domain.DefaultCodeFormat)
 
 	t.Run("create_ok: when it contains the mandatory fields, should create a product", func(t *testing.T) {
 
+		mockProductTypeRepository.
+			On("GetById", context.TODO(), expectedProduct.ProductTypeId).
+			Return(&productType.ProductType{Id: expectedProduct.ProductTypeId}, nil).
+			Once()
+
 		mockProductRepository.
 			On("Create", context.TODO(), &expectedProduct).
 			Return(&expectedProduct, nil).
@@ -55,6 +68,11 @@ func TestProductService_Create(t *testing.T) {
 
 	t.Run("create_conflict: when product_code already exists, should not create a product", func(t *testing.T) {
 
+		mockProductTypeRepository.
+			On("GetById", context.TODO(), expectedProduct.ProductTypeId).
+			Return(&productType.ProductType{Id: expectedProduct.ProductTypeId}, nil).
+			Once()
+
 		mockProductRepository.
 			On("Create", context.TODO(), &expectedProduct).
 			Return(nil, fmt.Errorf("the product code has already been registered")).
@@ -66,13 +84,140 @@ func TestProductService_Create(t *testing.T) {
 		assert.Nil(t, expectedProduct)
 		assert.Equal(t, err.Error(), "the product code has already been registered")
 	})
+
+	t.Run("create_product_type_not_found: when product_type_id does not exist, should not create a product", func(t *testing.T) {
+
+		mockProductTypeRepository.
+			On("GetById", context.TODO(), expectedProduct.ProductTypeId).
+			Return(nil, productType.ErrProductTypeNotFound).
+			Once()
+
+		result, err := productService.Create(context.TODO(), &expectedProduct)
+
+		assert.ErrorIs(t, err, productType.ErrProductTypeNotFound)
+		assert.Nil(t, result)
+	})
+}
+
+func TestProductService_Create_GeneratedCode(t *testing.T) {
+	newProduct := func() *domain.Product {
+		product := expectedProduct
+		product.Id = 0
+		product.ProductCode = ""
+		return &product
+	}
+
+	t.Run("create_generated_code: when product_code is omitted, should generate the next code", func(t *testing.T) {
+		mockProductRepository := mocks.NewProductRepository(t)
+		mockProductTypeRepository := mocksProductType.NewProductTypeRepository(t)
+		productService := service.CreateProductService(mockProductRepository, mocksProductRecords.NewProductRecordsRepository(t), mockProductTypeRepository, mocksSeller.NewRepositorySeller(t), domain.DefaultCodeFormat)
+
+		mockProductTypeRepository.
+			On("GetById", context.TODO(), expectedProduct.ProductTypeId).
+			Return(&productType.ProductType{Id: expectedProduct.ProductTypeId}, nil).
+			Once()
+
+		mockProductRepository.
+			On("GetMaxCodeSuffix", context.TODO(), "PRD").
+			Return(int64(41), nil).
+			Once()
+
+		mockProductRepository.
+			On("Create", context.TODO(), mock.MatchedBy(func(p *domain.Product) bool { return p.ProductCode == "PRD-000042" })).
+			Return(func(_ context.Context, p *domain.Product) *domain.Product { return p }, nil).
+			Once()
+
+		result, err := productService.Create(context.TODO(), newProduct())
+
+		assert.NoError(t, err)
+		assert.Equal(t, "PRD-000042", result.ProductCode)
+	})
+
+	t.Run("create_generated_code_check_digit: should prefix by product type and append a check digit", func(t *testing.T) {
+		mockProductRepository := mocks.NewProductRepository(t)
+		mockProductTypeRepository := mocksProductType.NewProductTypeRepository(t)
+		format := domain.CodeFormat{PrefixBy: domain.PrefixByProductType, Digits: 4, CheckDigit: true}
+		productService := service.CreateProductService(mockProductRepository, mocksProductRecords.NewProductRecordsRepository(t), mockProductTypeRepository, mocksSeller.NewRepositorySeller(t), format)
+
+		mockProductTypeRepository.
+			On("GetById", context.TODO(), expectedProduct.ProductTypeId).
+			Return(&productType.ProductType{Id: expectedProduct.ProductTypeId, Description: "frozen"}, nil).
+			Once()
+
+		// FRO-00067 is the last code: sequence 6 followed by its check digit 7.
+		mockProductRepository.
+			On("GetMaxCodeSuffix", context.TODO(), "FRO").
+			Return(int64(67), nil).
+			Once()
+
+		mockProductRepository.
+			On("Create", context.TODO(), mock.Anything).
+			Return(func(_ context.Context, p *domain.Product) *domain.Product { return p }, nil).
+			Once()
+
+		result, err := productService.Create(context.TODO(), newProduct())
+
+		assert.NoError(t, err)
+		assert.Equal(t, "FRO-00075", result.ProductCode)
+	})
+
+	t.Run("create_generated_code_retry: when the generated code was taken meanwhile, should try the next one", func(t *testing.T) {
+		mockProductRepository := mocks.NewProductRepository(t)
+		mockProductTypeRepository := mocksProductType.NewProductTypeRepository(t)
+		productService := service.CreateProductService(mockProductRepository, mocksProductRecords.NewProductRecordsRepository(t), mockProductTypeRepository, mocksSeller.NewRepositorySeller(t), domain.DefaultCodeFormat)
+
+		mockProductTypeRepository.
+			On("GetById", context.TODO(), expectedProduct.ProductTypeId).
+			Return(&productType.ProductType{Id: expectedProduct.ProductTypeId}, nil).
+			Once()
+
+		mockProductRepository.On("GetMaxCodeSuffix", context.TODO(), "PRD").Return(int64(1), nil).Once()
+		mockProductRepository.
+			On("Create", context.TODO(), mock.MatchedBy(func(p *domain.Product) bool { return p.ProductCode == "PRD-000002" })).
+			Return(nil, domain.ErrProductCodeAlreadyExists).
+			Once()
+
+		mockProductRepository.On("GetMaxCodeSuffix", context.TODO(), "PRD").Return(int64(2), nil).Once()
+		mockProductRepository.
+			On("Create", context.TODO(), mock.MatchedBy(func(p *domain.Product) bool { return p.ProductCode == "PRD-000003" })).
+			Return(func(_ context.Context, p *domain.Product) *domain.Product { return p }, nil).
+			Once()
+
+		result, err := productService.Create(context.TODO(), newProduct())
+
+		assert.NoError(t, err)
+		assert.Equal(t, "PRD-000003", result.ProductCode)
+	})
+
+	t.Run("create_strict_format: when the supplied code does not match the format, should return an error", func(t *testing.T) {
+		mockProductTypeRepository := mocksProductType.NewProductTypeRepository(t)
+		format := domain.DefaultCodeFormat
+		format.Strict = true
+		productService := service.CreateProductService(mocks.NewProductRepository(t), mocksProductRecords.NewProductRecordsRepository(t), mockProductTypeRepository, mocksSeller.NewRepositorySeller(t), format)
+
+		mockProductTypeRepository.
+			On("GetById", context.TODO(), expectedProduct.ProductTypeId).
+			Return(&productType.ProductType{Id: expectedProduct.ProductTypeId}, nil).
+			Once()
+
+		product := newProduct()
+		product.ProductCode = "PRD-42"
+
+		result, err := productService.Create(context.TODO(), product)
+
+		assert.ErrorIs(t, err, domain.ErrProductCodeFormat)
+		assert.Nil(t, result)
+	})
 }
 
 func TestProductService_GetAll(t *testing.T) {
 	mockProductRepository := mocks.NewProductRepository(t)
 	mockRepositoryProductRecords := mocksProductRecords.NewProductRecordsRepository(t)
 
-	productService := service.CreateProductService(mockProductRepository, mockRepositoryProductRecords)
+	mockProductTypeRepository := mocksProductType.NewProductTypeRepository(t)
+	mockSellerRepository := mocksSeller.NewRepositorySeller(t)
+
+	productService := service.CreateProductService(mockProductRepository, mockRepositoryProductRecords, mockProductTypeRepository, mockSellerRepository, domain.DefaultCodeFormat)
 
 	t.Run("get_all: when exists products, should return a list", func(t *testing.T) {
 
@@ -107,7 +252,10 @@ func TestProductService_GetById(t *testing.T) {
 	mockProductRepository := mocks.NewProductRepository(t)
 	mockRepositoryProductRecords := mocksProductRecords.NewProductRecordsRepository(t)
 
-	productService := service.CreateProductService(mockProductRepository, mockRepositoryProductRecords)
+	mockProductTypeRepository := mocksProductType.NewProductTypeRepository(t)
+	mockSellerRepository := mocksSeller.NewRepositorySeller(t)
+
+	productService := service.CreateProductService(mockProductRepository, mockRepositoryProductRecords, mockProductTypeRepository, mockSellerRepository, domain.DefaultCodeFormat)
 
 	t.Run("get_by_id_non_existent: when the element searched for by id does not exist, should return an error", func(t *testing.T) {
 
@@ -142,7 +290,10 @@ func TestProductService_UpdateDescription(t *testing.T) {
 	mockProductRepository := mocks.NewProductRepository(t)
 	mockRepositoryProductRecords := mocksProductRecords.NewProductRecordsRepository(t)
 
-	productService := service.CreateProductService(mockProductRepository, mockRepositoryProductRecords)
+	mockProductTypeRepository := mocksProductType.NewProductTypeRepository(t)
+	mockSellerRepository := mocksSeller.NewRepositorySeller(t)
+
+	productService := service.CreateProductService(mockProductRepository, mockRepositoryProductRecords, mockProductTypeRepository, mockSellerRepository, domain.DefaultCodeFormat)
 
 	dummyUpdatedProduct := domain.Product{
 		Id:          expectedProduct.Id,
@@ -198,11 +349,176 @@ func TestProductService_UpdateDescription(t *testing.T) {
 	})
 }
 
+func TestProductService_Update(t *testing.T) {
+
+	newService := func(t *testing.T) (*mocks.ProductRepository, *mocksProductType.ProductTypeRepository, *mocksSeller.RepositorySeller, domain.ProductService) {
+		mockProductRepository := mocks.NewProductRepository(t)
+		mockProductTypeRepository := mocksProductType.NewProductTypeRepository(t)
+		mockSellerRepository := mocksSeller.NewRepositorySeller(t)
+
+		productService := service.CreateProductService(mockProductRepository, mocksProductRecords.NewProductRecordsRepository(t), mockProductTypeRepository, mockSellerRepository, domain.DefaultCodeFormat)
+
+		return mockProductRepository, mockProductTypeRepository, mockSellerRepository, productService
+	}
+
+	t.Run("update_ok: when the patch is valid, should update only the given fields", func(t *testing.T) {
+		mockProductRepository, mockProductTypeRepository, mockSellerRepository, productService := newService(t)
+
+		current := expectedProduct
+		width := 2.5
+		sellerId := int64(3)
+
+		updated := expectedProduct
+		updated.Width = width
+		updated.SellerId = sellerId
+
+		mockProductRepository.On("GetById", context.TODO(), int64(1)).Return(&current, nil).Once()
+		mockProductTypeRepository.On("GetById", context.TODO(), updated.ProductTypeId).Return(&productType.ProductType{Id: updated.ProductTypeId}, nil).Once()
+		mockSellerRepository.On("GetById", context.TODO(), sellerId).Return(&seller.Seller{Id: sellerId}, nil).Once()
+		mockProductRepository.On("GetByProductCode", context.TODO(), updated.ProductCode).Return(&current, nil).Once()
+		mockProductRepository.On("Update", context.TODO(), &updated).Return(&updated, nil).Once()
+
+		prod, err := productService.Update(context.TODO(), 1, domain.ProductPatch{Width: &width, SellerId: &sellerId})
+
+		assert.NoError(t, err)
+		assert.Equal(t, &updated, prod)
+	})
+
+	t.Run("update_invalid_dimensions: when a dimension is not positive, should return an error", func(t *testing.T) {
+		mockProductRepository, _, _, productService := newService(t)
+
+		current := expectedProduct
+		height := float64(0)
+
+		mockProductRepository.On("GetById", context.TODO(), int64(1)).Return(&current, nil).Once()
+
+		_, err := productService.Update(context.TODO(), 1, domain.ProductPatch{Height: &height})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidDimensions)
+	})
+
+	t.Run("update_invalid_freezing_temperature: when the temperature is out of bounds, should return an error", func(t *testing.T) {
+		mockProductRepository, _, _, productService := newService(t)
+
+		current := expectedProduct
+		temperature := float64(-200)
+
+		mockProductRepository.On("GetById", context.TODO(), int64(1)).Return(&current, nil).Once()
+
+		_, err := productService.Update(context.TODO(), 1, domain.ProductPatch{RecommendedFreezingTemperature: &temperature})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidFreezingTemperature)
+	})
+
+	t.Run("update_seller_not_found: when the seller does not exist, should return an error", func(t *testing.T) {
+		mockProductRepository, mockProductTypeRepository, mockSellerRepository, productService := newService(t)
+
+		current := expectedProduct
+		sellerId := int64(99)
+
+		mockProductRepository.On("GetById", context.TODO(), int64(1)).Return(&current, nil).Once()
+		mockProductTypeRepository.On("GetById", context.TODO(), expectedProduct.ProductTypeId).Return(&productType.ProductType{Id: expectedProduct.ProductTypeId}, nil).Once()
+		mockSellerRepository.On("GetById", context.TODO(), sellerId).Return(nil, seller.ErrIDNotFound).Once()
+
+		_, err := productService.Update(context.TODO(), 1, domain.ProductPatch{SellerId: &sellerId})
+
+		assert.ErrorIs(t, err, seller.ErrIDNotFound)
+	})
+
+	t.Run("update_product_type_not_found: when the product type does not exist, should return an error", func(t *testing.T) {
+		mockProductRepository, mockProductTypeRepository, _, productService := newService(t)
+
+		current := expectedProduct
+		productTypeId := int64(99)
+
+		mockProductRepository.On("GetById", context.TODO(), int64(1)).Return(&current, nil).Once()
+		mockProductTypeRepository.On("GetById", context.TODO(), productTypeId).Return(nil, productType.ErrProductTypeNotFound).Once()
+
+		_, err := productService.Update(context.TODO(), 1, domain.ProductPatch{ProductTypeId: &productTypeId})
+
+		assert.ErrorIs(t, err, productType.ErrProductTypeNotFound)
+	})
+
+	t.Run("update_conflict: when the product_code belongs to another product, should return an error", func(t *testing.T) {
+		mockProductRepository, mockProductTypeRepository, mockSellerRepository, productService := newService(t)
+
+		current := expectedProduct
+		code := "PROD03"
+		other := expectedProduct
+		other.Id = 3
+		other.ProductCode = code
+
+		mockProductRepository.On("GetById", context.TODO(), int64(1)).Return(&current, nil).Once()
+		mockProductTypeRepository.On("GetById", context.TODO(), expectedProduct.ProductTypeId).Return(&productType.ProductType{Id: expectedProduct.ProductTypeId}, nil).Once()
+		mockSellerRepository.On("GetById", context.TODO(), expectedProduct.SellerId).Return(&seller.Seller{Id: expectedProduct.SellerId}, nil).Once()
+		mockProductRepository.On("GetByProductCode", context.TODO(), code).Return(&other, nil).Once()
+
+		_, err := productService.Update(context.TODO(), 1, domain.ProductPatch{ProductCode: &code})
+
+		assert.ErrorIs(t, err, domain.ErrProductCodeAlreadyExists)
+	})
+
+	t.Run("update_non_existent: when the product does not exist, should return an error", func(t *testing.T) {
+		mockProductRepository, _, _, productService := newService(t)
+
+		mockProductRepository.On("GetById", context.TODO(), int64(8)).Return(nil, domain.ErrProductIdNotFound).Once()
+
+		_, err := productService.Update(context.TODO(), 8, domain.ProductPatch{})
+
+		assert.ErrorIs(t, err, domain.ErrProductIdNotFound)
+	})
+}
+
+func TestProductService_Replace(t *testing.T) {
+
+	t.Run("replace_ok: when the product is valid, should replace every field", func(t *testing.T) {
+		mockProductRepository := mocks.NewProductRepository(t)
+		mockProductTypeRepository := mocksProductType.NewProductTypeRepository(t)
+		mockSellerRepository := mocksSeller.NewRepositorySeller(t)
+
+		productService := service.CreateProductService(mockProductRepository, mocksProductRecords.NewProductRecordsRepository(t), mockProductTypeRepository, mockSellerRepository, domain.DefaultCodeFormat)
+
+		current := expectedProduct
+		replacement := expectedProduct
+		replacement.Id = 0
+		replacement.ProductCode = "PROD09"
+		replacement.Description = "Greek yogurt"
+
+		mockProductRepository.On("GetById", context.TODO(), int64(1)).Return(&current, nil).Once()
+		mockProductTypeRepository.On("GetById", context.TODO(), replacement.ProductTypeId).Return(&productType.ProductType{Id: replacement.ProductTypeId}, nil).Once()
+		mockSellerRepository.On("GetById", context.TODO(), replacement.SellerId).Return(&seller.Seller{Id: replacement.SellerId}, nil).Once()
+		mockProductRepository.On("GetByProductCode", context.TODO(), "PROD09").Return(nil, domain.ErrProductIdNotFound).Once()
+		mockProductRepository.On("Update", context.TODO(), &replacement).Return(&replacement, nil).Once()
+
+		prod, err := productService.Replace(context.TODO(), 1, &replacement)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), prod.Id)
+		assert.Equal(t, "Greek yogurt", prod.Description)
+	})
+
+	t.Run("replace_non_existent: when the product does not exist, should return an error", func(t *testing.T) {
+		mockProductRepository := mocks.NewProductRepository(t)
+
+		productService := service.CreateProductService(mockProductRepository, mocksProductRecords.NewProductRecordsRepository(t), mocksProductType.NewProductTypeRepository(t), mocksSeller.NewRepositorySeller(t), domain.DefaultCodeFormat)
+
+		mockProductRepository.On("GetById", context.TODO(), int64(8)).Return(nil, domain.ErrProductIdNotFound).Once()
+
+		replacement := expectedProduct
+		_, err := productService.Replace(context.TODO(), 8, &replacement)
+
+		assert.ErrorIs(t, err, domain.ErrProductIdNotFound)
+	})
+}
+
 func TestProductService_Delete(t *testing.T) {
 	mockProductRepository := mocks.NewProductRepository(t)
 	mockRepositoryProductRecords := mocksProductRecords.NewProductRecordsRepository(t)
 
-	productService := service.CreateProductService(mockProductRepository, mockRepositoryProductRecords)
+	mockProductTypeRepository := mocksProductType.NewProductTypeRepository(t)
+	mockSellerRepository := mocksSeller.NewRepositorySeller(t)
+
+	productService := service.CreateProductService(mockProductRepository, mockRepositoryProductRecords, mockProductTypeRepository, mockSellerRepository, domain.DefaultCodeFormat)
 
 	t.Run("delete_non_existent: when the product does not exist, should return an error", func(t *testing.T) {
 
@@ -234,7 +550,10 @@ func TestProductService_GetReportProductRecordsById(t *testing.T) {
 	mockProductRepository := mocks.NewProductRepository(t)
 	mockRepositoryProductRecords := mocksProductRecords.NewProductRecordsRepository(t)
 
-	productService := service.CreateProductService(mockProductRepository, mockRepositoryProductRecords)
+	mockProductTypeRepository := mocksProductType.NewProductTypeRepository(t)
+	mockSellerRepository := mocksSeller.NewRepositorySeller(t)
+
+	productService := service.CreateProductService(mockProductRepository, mockRepositoryProductRecords, mockProductTypeRepository, mockSellerRepository, domain.DefaultCodeFormat)
 
 	var expectedReportProductRecordsList = []domain.ProductRecordsReport{
 		{
@@ -302,7 +621,10 @@ func TestProductService_GetAllReportProductRecords(t *testing.T) {
 	mockProductRepository := mocks.NewProductRepository(t)
 	mockRepositoryProductRecords := mocksProductRecords.NewProductRecordsRepository(t)
 
-	productService := service.CreateProductService(mockProductRepository, mockRepositoryProductRecords)
+	mockProductTypeRepository := mocksProductType.NewProductTypeRepository(t)
+	mockSellerRepository := mocksSeller.NewRepositorySeller(t)
+
+	productService := service.CreateProductService(mockProductRepository, mockRepositoryProductRecords, mockProductTypeRepository, mockSellerRepository, domain.DefaultCodeFormat)
 
 	t.Run("get_report_ok: should return a list of number of records of each product", func(t *testing.T) {
 
@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"fmt"
+
+	product "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product/domain"
+	section "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/section/domain"
+)
+
+// CompatibilityOverride stores a batch in a section it is not compatible
+// with. Who the requester says they are and why is written to the audit log
+// together with the rules that were bypassed; nothing checks By, so the
+// entry records a claim, not an identity.
+type CompatibilityOverride struct {
+	By         string   `json:"by"`
+	Reason     string   `json:"reason"`
+	Violations []string `json:"violations"`
+}
+
+// CheckCompatibility lists why a batch of p with the given minimum
+// temperature cannot be stored in s. The product must be of the section
+// product type, and the section must get at least as cold as the batch
+// minimum temperature. A nil product skips the type rule.
+func CheckCompatibility(p *product.Product, minimumTemperature float64, s section.SectionModel) []string {
+	var violations []string
+
+	if p != nil && p.ProductTypeId != s.ProductTypeId {
+		violations = append(violations, fmt.Sprintf(
+			"product type %d does not match section product type %d", p.ProductTypeId, s.ProductTypeId))
+	}
+
+	if s.MinimumTemperature > minimumTemperature {
+		violations = append(violations, fmt.Sprintf(
+			"section minimum temperature %.2f is above the batch minimum temperature %.2f", s.MinimumTemperature, minimumTemperature))
+	}
+
+	return violations
+}
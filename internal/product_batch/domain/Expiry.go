@@ -0,0 +1,186 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// Batch statuses. Only active batches count as stock; a quarantined batch
+// can be released back to active or discarded, and discarding is final.
+const (
+	StatusActive      = "active"
+	StatusQuarantined = "quarantined"
+	StatusDiscarded   = "discarded"
+)
+
+// Expiry alert levels, raised at most once each per batch.
+const (
+	ExpiryExpiring = "expiring"
+	ExpiryExpired  = "expired"
+)
+
+const (
+	DefaultExpiringDays = 7
+	MaxExpiringDays     = 365
+)
+
+func IsStatus(status string) bool {
+	return status == StatusActive || status == StatusQuarantined || status == StatusDiscarded
+}
+
+// CanChangeStatus tells whether a batch may go from one status to another.
+func CanChangeStatus(from, to string) bool {
+	switch from {
+	case StatusActive:
+		return to == StatusQuarantined || to == StatusDiscarded
+	case StatusQuarantined:
+		return to == StatusActive || to == StatusDiscarded
+	default:
+		return false
+	}
+}
+
+// ExpiringBatch is an active batch with stock whose due date is near or
+// past, with where it is stored and its value at the current purchase price.
+type ExpiringBatch struct {
+	Id              int64     `json:"id"`
+	BatchNumber     int64     `json:"batch_number"`
+	ProductId       int64     `json:"product_id"`
+	ProductCode     string    `json:"product_code"`
+	Description     string    `json:"description"`
+	DueDate         time.Time `json:"due_date"`
+	DaysLeft        int       `json:"days_left"`
+	CurrentQuantity int64     `json:"current_quantity"`
+	PurchasePrice   *float64  `json:"purchase_price"`
+	Value           float64   `json:"value"`
+	ExpiryAlert     string    `json:"expiry_alert,omitempty"`
+	SectionId       int64     `json:"-"`
+	SectionNumber   int64     `json:"-"`
+	WarehouseId     int64     `json:"-"`
+	WarehouseCode   string    `json:"-"`
+}
+
+// Complete fills in the days left before now and the value of the batch.
+// A batch without a purchase price is worth 0.
+func (b *ExpiringBatch) Complete(now time.Time) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	due := time.Date(b.DueDate.Year(), b.DueDate.Month(), b.DueDate.Day(), 0, 0, 0, 0, time.UTC)
+	b.DaysLeft = int(due.Sub(today).Hours() / 24)
+
+	b.Value = 0
+	if b.PurchasePrice != nil {
+		b.Value = round(float64(b.CurrentQuantity) * *b.PurchasePrice)
+	}
+}
+
+// Level is the expiry alert the batch calls for at now.
+func (b ExpiringBatch) Level(now time.Time) string {
+	if b.DueDate.Before(now) {
+		return ExpiryExpired
+	}
+	return ExpiryExpiring
+}
+
+type ExpiringSection struct {
+	SectionId     int64           `json:"section_id"`
+	SectionNumber int64           `json:"section_number"`
+	Quantity      int64           `json:"quantity"`
+	Value         float64         `json:"value"`
+	Batches       []ExpiringBatch `json:"batches"`
+}
+
+type ExpiringWarehouse struct {
+	WarehouseId   int64             `json:"warehouse_id"`
+	WarehouseCode string            `json:"warehouse_code"`
+	Quantity      int64             `json:"quantity"`
+	Value         float64           `json:"value"`
+	Sections      []ExpiringSection `json:"sections"`
+}
+
+type ExpiringReport struct {
+	Days        int                 `json:"days"`
+	GeneratedAt time.Time           `json:"generated_at"`
+	Until       time.Time           `json:"until"`
+	Quantity    int64               `json:"quantity"`
+	Value       float64             `json:"value"`
+	Warehouses  []ExpiringWarehouse `json:"warehouses"`
+}
+
+// NewExpiringReport groups batches, ordered by warehouse and section, into
+// warehouses and sections with their totals.
+func NewExpiringReport(days int, now time.Time, until time.Time, batches []ExpiringBatch) *ExpiringReport {
+	report := &ExpiringReport{
+		Days:        days,
+		GeneratedAt: now,
+		Until:       until,
+		Warehouses:  []ExpiringWarehouse{},
+	}
+
+	for _, batch := range batches {
+		batch.Complete(now)
+
+		if n := len(report.Warehouses); n == 0 || report.Warehouses[n-1].WarehouseId != batch.WarehouseId {
+			report.Warehouses = append(report.Warehouses, ExpiringWarehouse{
+				WarehouseId:   batch.WarehouseId,
+				WarehouseCode: batch.WarehouseCode,
+				Sections:      []ExpiringSection{},
+			})
+		}
+		warehouse := &report.Warehouses[len(report.Warehouses)-1]
+
+		if n := len(warehouse.Sections); n == 0 || warehouse.Sections[n-1].SectionId != batch.SectionId {
+			warehouse.Sections = append(warehouse.Sections, ExpiringSection{
+				SectionId:     batch.SectionId,
+				SectionNumber: batch.SectionNumber,
+				Batches:       []ExpiringBatch{},
+			})
+		}
+		section := &warehouse.Sections[len(warehouse.Sections)-1]
+
+		section.Batches = append(section.Batches, batch)
+		section.Quantity += batch.CurrentQuantity
+		section.Value = round(section.Value + batch.Value)
+		warehouse.Quantity += batch.CurrentQuantity
+		warehouse.Value = round(warehouse.Value + batch.Value)
+		report.Quantity += batch.CurrentQuantity
+		report.Value = round(report.Value + batch.Value)
+	}
+
+	return report
+}
+
+// ExpiryAlert is published to the outbox when a batch starts expiring or
+// has expired.
+type ExpiryAlert struct {
+	Level           string    `json:"level"`
+	ProductBatchId  int64     `json:"product_batch_id"`
+	BatchNumber     int64     `json:"batch_number"`
+	ProductId       int64     `json:"product_id"`
+	SectionId       int64     `json:"section_id"`
+	WarehouseId     int64     `json:"warehouse_id"`
+	DueDate         time.Time `json:"due_date"`
+	DaysLeft        int       `json:"days_left"`
+	CurrentQuantity int64     `json:"current_quantity"`
+	Value           float64   `json:"value"`
+}
+
+func NewExpiryAlert(batch ExpiringBatch, now time.Time) ExpiryAlert {
+	batch.Complete(now)
+
+	return ExpiryAlert{
+		Level:           batch.Level(now),
+		ProductBatchId:  batch.Id,
+		BatchNumber:     batch.BatchNumber,
+		ProductId:       batch.ProductId,
+		SectionId:       batch.SectionId,
+		WarehouseId:     batch.WarehouseId,
+		DueDate:         batch.DueDate,
+		DaysLeft:        batch.DaysLeft,
+		CurrentQuantity: batch.CurrentQuantity,
+		Value:           batch.Value,
+	}
+}
+
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
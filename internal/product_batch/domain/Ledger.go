@@ -0,0 +1,63 @@
+package domain
+
+import "time"
+
+// Stock movement types. Every change of where stock is, or how much of it
+// there is, leaves an entry in the stock ledger.
+const (
+	MovementReceipt     = "receipt"
+	MovementTransferOut = "transfer_out"
+	MovementTransferIn  = "transfer_in"
+	MovementAdjustment  = "adjustment"
+	MovementPick        = "pick"
+	MovementDisposal    = "disposal"
+)
+
+// Documents a stock movement can reference.
+const (
+	ReferenceInboundOrder  = "inbound_order"
+	ReferenceProductBatch  = "product_batch"
+	ReferencePurchaseOrder = "purchase_order"
+)
+
+// StockMovement is an entry of the stock ledger. The quantity is signed:
+// stock leaving the batch is negative. The reference points at the document
+// behind the movement: the inbound order of a receipt, the other batch of a
+// transfer or the purchase order of a pick.
+type StockMovement struct {
+	Id             int64     `json:"id"`
+	ProductBatchId int64     `json:"product_batch_id"`
+	SectionId      int64     `json:"section_id"`
+	Type           string    `json:"movement_type"`
+	Quantity       int64     `json:"quantity"`
+	EmployeeId     *int64    `json:"employee_id"`
+	Reason         string    `json:"reason"`
+	ReferenceType  string    `json:"reference_type,omitempty"`
+	ReferenceId    *int64    `json:"reference_id,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// StockLedger is the movement history of a batch. Its movements add up to
+// the current quantity; Consistent tells whether they do.
+type StockLedger struct {
+	ProductBatchId  int64           `json:"product_batch_id"`
+	CurrentQuantity int64           `json:"current_quantity"`
+	LedgerQuantity  int64           `json:"ledger_quantity"`
+	Consistent      bool            `json:"consistent"`
+	Movements       []StockMovement `json:"movements"`
+}
+
+func NewStockLedger(productBatch ProductBatch, movements []StockMovement) StockLedger {
+	ledger := StockLedger{
+		ProductBatchId:  productBatch.Id,
+		CurrentQuantity: productBatch.CurrentQuantity,
+		Movements:       movements,
+	}
+
+	for _, movement := range movements {
+		ledger.LedgerQuantity += movement.Quantity
+	}
+	ledger.Consistent = ledger.LedgerQuantity == ledger.CurrentQuantity
+
+	return ledger
+}
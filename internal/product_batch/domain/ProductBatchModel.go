@@ -6,23 +6,81 @@ import (
 )
 
 type ProductBatch struct {
-	Id                 int64     `json:"id"`
-	BatchNumber        int64     `json:"batch_number"`
-	CurrentQuantity    int64     `json:"current_quantity"`
-	CurrentTemperature float64   `json:"current_temperature"`
-	DueDate            time.Time `json:"due_date"`
-	InitialQuantity    int64     `json:"initial_quantity"`
-	ManufacturingDate  time.Time `json:"manufacturing_date"`
-	ManufacturingHour  int64     `json:"manufacturing_hour"`
-	MinumumTemperature float64   `json:"minumum_temperature"`
-	ProductId          int64     `json:"product_id"`
-	SectionId          int64     `json:"section_id"`
+	Id                 int64      `json:"id"`
+	BatchNumber        int64      `json:"batch_number"`
+	CurrentQuantity    int64      `json:"current_quantity"`
+	CurrentTemperature float64    `json:"current_temperature"`
+	DueDate            time.Time  `json:"due_date"`
+	InitialQuantity    int64      `json:"initial_quantity"`
+	ManufacturingDate  time.Time  `json:"manufacturing_date"`
+	ManufacturingHour  int64      `json:"manufacturing_hour"`
+	MinumumTemperature float64    `json:"minumum_temperature"`
+	ProductId          int64      `json:"product_id"`
+	SectionId          int64      `json:"section_id"`
+	Status             string     `json:"status"`
+	StatusReason       string     `json:"status_reason,omitempty"`
+	StatusChangedAt    *time.Time `json:"status_changed_at,omitempty"`
+
+	// ShelfLife is computed by the service and never stored.
+	ShelfLife *ShelfLife `json:"shelf_life,omitempty"`
+	// Override is only set on the write that used it; it is kept in the
+	// audit log.
+	Override *CompatibilityOverride `json:"compatibility_override,omitempty"`
+	// Movement says why a write changes the quantity, for the stock ledger.
+	// Like Override, it is only set on the write.
+	Movement *StockMovement `json:"-"`
+}
+
+// ProductBatchFilter narrows the batch list down. Zero ids and nil bounds
+// mean no filtering; bounds are inclusive.
+type ProductBatchFilter struct {
+	ProductId      int64
+	SectionId      int64
+	WarehouseId    int64
+	Status         string
+	DueDateFrom    *time.Time
+	DueDateTo      *time.Time
+	MinTemperature *float64
+	MaxTemperature *float64
+	MinQuantity    *int64
+	MaxQuantity    *int64
+}
+
+// ProductBatchPatch holds the batch fields that change while it is stored.
+// Nil fields are left untouched. A quantity change is recorded in the stock
+// ledger as an adjustment, or as a pick when it names a purchase order.
+type ProductBatchPatch struct {
+	CurrentQuantity    *int64
+	CurrentTemperature *float64
+	Reason             string
+	EmployeeId         *int64
+	PurchaseOrderId    *int64
 }
 
 type ProductBatchRepository interface {
 	Create(ctx context.Context, productBatch *ProductBatch) (*ProductBatch, error)
+	GetById(ctx context.Context, id int64) (*ProductBatch, error)
+	GetAll(ctx context.Context, filter ProductBatchFilter) ([]ProductBatch, error)
+	Update(ctx context.Context, productBatch *ProductBatch) (*ProductBatch, error)
+	Delete(ctx context.Context, id int64) error
+	CountInboundOrders(ctx context.Context, id int64) (int64, error)
+	UpdateStatus(ctx context.Context, productBatch *ProductBatch) (*ProductBatch, error)
+	GetExpiring(ctx context.Context, now time.Time, until time.Time) ([]ExpiringBatch, error)
+	GetPendingExpiryAlerts(ctx context.Context, now time.Time, until time.Time) ([]ExpiringBatch, error)
+	RecordExpiryAlert(ctx context.Context, alert ExpiryAlert) error
+	Transfer(ctx context.Context, transfer Transfer) (*TransferResult, error)
+	GetMovements(ctx context.Context, id int64) ([]StockMovement, error)
 }
 
 type ProductBatchService interface {
 	Create(ctx context.Context, productBatch *ProductBatch) (*ProductBatch, error)
+	GetById(ctx context.Context, id int64) (*ProductBatch, error)
+	GetAll(ctx context.Context, filter ProductBatchFilter) ([]ProductBatch, error)
+	Update(ctx context.Context, id int64, patch ProductBatchPatch) (*ProductBatch, error)
+	Delete(ctx context.Context, id int64) error
+	UpdateStatus(ctx context.Context, id int64, status string, reason string) (*ProductBatch, error)
+	GetExpiringReport(ctx context.Context, days int) (*ExpiringReport, error)
+	SuggestSection(ctx context.Context, request PutAwayRequest) (*PutAwaySuggestion, error)
+	Transfer(ctx context.Context, transfer Transfer) (*TransferResult, error)
+	GetMovements(ctx context.Context, id int64) (*StockLedger, error)
 }
@@ -0,0 +1,138 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+
+	product "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product/domain"
+	section "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/section/domain"
+)
+
+// Weights of the put-away criteria. A section must be compatible and have
+// room for the batch to be eligible; the weights then rank eligible
+// sections, favouring consolidation over spreading stock.
+const (
+	PutAwayTypeScore          = 40.0
+	PutAwayTemperatureScore   = 30.0
+	PutAwayConsolidationScore = 25.0
+	PutAwayCapacityScore      = 20.0
+	PutAwayColdScore          = 10.0
+)
+
+// PutAwayRequest describes an incoming batch to find a section for. The
+// minimum temperature defaults to the recommended freezing temperature of
+// the product; a zero warehouse considers every warehouse.
+type PutAwayRequest struct {
+	ProductId          int64
+	Quantity           int64
+	WarehouseId        int64
+	MinimumTemperature *float64
+}
+
+// StoredProduct is how much of the product a section already holds.
+type StoredProduct struct {
+	Batches  int64
+	Quantity int64
+}
+
+type SectionCandidate struct {
+	SectionId     int64    `json:"section_id"`
+	SectionNumber int64    `json:"section_number"`
+	WarehouseId   int64    `json:"warehouse_id"`
+	FreeCapacity  int64    `json:"free_capacity"`
+	Eligible      bool     `json:"eligible"`
+	Score         float64  `json:"score"`
+	Reasons       []string `json:"reasons"`
+}
+
+type PutAwaySuggestion struct {
+	ProductId          int64              `json:"product_id"`
+	Quantity           int64              `json:"quantity"`
+	MinimumTemperature float64            `json:"minimum_temperature"`
+	Candidates         []SectionCandidate `json:"candidates"`
+}
+
+// RankSection scores s for a batch of quantity units of p, explaining
+// every criterion. stored is what s already holds of the product.
+func RankSection(p *product.Product, quantity int64, minimumTemperature float64, s section.SectionModel, stored StoredProduct) SectionCandidate {
+	candidate := SectionCandidate{
+		SectionId:     s.Id,
+		SectionNumber: s.SectionNumber,
+		WarehouseId:   s.WarehouseId,
+		FreeCapacity:  s.MaximumCapacity - s.CurrentCapacity,
+		Eligible:      true,
+		Reasons:       []string{},
+	}
+
+	reject := func(reason string) {
+		candidate.Eligible = false
+		candidate.Reasons = append(candidate.Reasons, reason)
+	}
+	award := func(score float64, reason string) {
+		candidate.Score += score
+		candidate.Reasons = append(candidate.Reasons, reason)
+	}
+
+	for _, violation := range CheckCompatibility(p, minimumTemperature, s) {
+		reject(violation)
+	}
+	if p.ProductTypeId == s.ProductTypeId {
+		award(PutAwayTypeScore, fmt.Sprintf("stores product type %d", s.ProductTypeId))
+	}
+	if s.MinimumTemperature <= minimumTemperature {
+		award(PutAwayTemperatureScore, fmt.Sprintf(
+			"reaches %.2f, at or below the batch minimum temperature %.2f", s.MinimumTemperature, minimumTemperature))
+	}
+
+	if s.CurrentTemperature <= p.RecommendedFreezingTemperature {
+		award(PutAwayColdScore, fmt.Sprintf(
+			"currently at %.2f, within the recommended freezing temperature %.2f", s.CurrentTemperature, p.RecommendedFreezingTemperature))
+	} else {
+		candidate.Reasons = append(candidate.Reasons, fmt.Sprintf(
+			"currently at %.2f, above the recommended freezing temperature %.2f", s.CurrentTemperature, p.RecommendedFreezingTemperature))
+	}
+
+	if candidate.FreeCapacity < quantity {
+		reject(fmt.Sprintf("has room for %d units, %d needed", max64(candidate.FreeCapacity, 0), quantity))
+	} else {
+		left := candidate.FreeCapacity - quantity
+		score := 0.0
+		if s.MaximumCapacity > 0 {
+			score = round(PutAwayCapacityScore * float64(left) / float64(s.MaximumCapacity))
+		}
+		award(score, fmt.Sprintf("has room for %d units, %d left after the batch", candidate.FreeCapacity, left))
+	}
+
+	if stored.Batches > 0 {
+		award(PutAwayConsolidationScore, fmt.Sprintf(
+			"already holds %d units of the product in %d batches", stored.Quantity, stored.Batches))
+	}
+
+	candidate.Score = round(candidate.Score)
+	return candidate
+}
+
+// SortCandidates puts eligible sections first, then by score, free capacity
+// and id.
+func SortCandidates(candidates []SectionCandidate) {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.Eligible != b.Eligible {
+			return a.Eligible
+		}
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.FreeCapacity != b.FreeCapacity {
+			return a.FreeCapacity > b.FreeCapacity
+		}
+		return a.SectionId < b.SectionId
+	})
+}
+
+func max64(a, b int64) int64 {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	product "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product/domain"
+)
+
+// ReferenceShelfLifeDays is the shelf life of a product with an expiration
+// rate of 1 kept at its recommended freezing temperature.
+const ReferenceShelfLifeDays = 60.0
+
+// DueDateTolerance is how far a supplied due date may be from the computed
+// expiry before the batch is flagged.
+const DueDateTolerance = 48 * time.Hour
+
+// ShelfLife is the expected expiry of a batch, computed from the product
+// rates and the temperature of the section it is stored in.
+type ShelfLife struct {
+	ShelfLifeDays      float64   `json:"shelf_life_days"`
+	StorageTemperature float64   `json:"storage_temperature"`
+	ExpectedExpiry     time.Time `json:"expected_expiry"`
+	Warning            string    `json:"warning,omitempty"`
+}
+
+// ComputeShelfLife estimates when a batch manufactured at manufacturedAt
+// expires when stored at storageTemperature.
+//
+// The expiration rate is how fast the product spoils at its recommended
+// freezing temperature relative to the reference, so a rate of 2 halves the
+// reference shelf life. Each degree above the recommended temperature speeds
+// spoilage up by a tenth of the freezing rate; storing colder does not extend
+// the shelf life. It returns false when the product has no expiration rate.
+func ComputeShelfLife(p product.Product, storageTemperature float64, manufacturedAt time.Time) (ShelfLife, bool) {
+	if p.ExpirationRate <= 0 {
+		return ShelfLife{}, false
+	}
+
+	excess := math.Max(0, storageTemperature-p.RecommendedFreezingTemperature)
+	days := ReferenceShelfLifeDays / (p.ExpirationRate * (1 + p.FreezingRate*excess/10))
+
+	return ShelfLife{
+		ShelfLifeDays:      math.Round(days*100) / 100,
+		StorageTemperature: storageTemperature,
+		ExpectedExpiry:     manufacturedAt.Add(time.Duration(days * float64(24*time.Hour))).Truncate(time.Minute),
+	}, true
+}
+
+// ManufacturedAt is the moment the batch was produced.
+func (b ProductBatch) ManufacturedAt() time.Time {
+	return b.ManufacturingDate.Add(time.Duration(b.ManufacturingHour) * time.Hour)
+}
+
+// CheckDueDate sets the warning when the due date of the batch is further
+// than DueDateTolerance from the expected expiry.
+func (s *ShelfLife) CheckDueDate(dueDate time.Time) {
+	difference := dueDate.Sub(s.ExpectedExpiry)
+	if difference < 0 {
+		difference = -difference
+	}
+	if difference <= DueDateTolerance {
+		s.Warning = ""
+		return
+	}
+	s.Warning = fmt.Sprintf(
+		"due date %s differs from the expected expiry %s by more than %d hours",
+		dueDate.Format("2006-01-02"),
+		s.ExpectedExpiry.Format("2006-01-02"),
+		int(DueDateTolerance.Hours()),
+	)
+}
@@ -0,0 +1,22 @@
+package domain
+
+// Transfer moves quantity units of a batch to another section. A zero
+// quantity, or the whole current quantity, moves the batch itself; less
+// than that splits the units off into a new batch in the target section.
+type Transfer struct {
+	ProductBatchId int64
+	ToSectionId    int64
+	Quantity       int64
+	EmployeeId     int64
+	Reason         string
+	Override       *CompatibilityOverride
+}
+
+// TransferResult holds the batch the units were taken from and the batch
+// they now make up. Both are the same batch when it moved whole.
+type TransferResult struct {
+	Source    *ProductBatch   `json:"source"`
+	Target    *ProductBatch   `json:"target"`
+	Split     bool            `json:"split"`
+	Movements []StockMovement `json:"movements"`
+}
@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrProductBatchNotFound   = errors.New("product batch not found")
+	ErrProductBatchInUse      = errors.New("product batch is referenced by inbound orders")
+	ErrProductBatchHasHistory = errors.New("product batch has stock movements besides its receipt, discard it instead")
+	ErrInvalidQuantity        = errors.New("current quantity must be between 0 and the initial quantity")
+	ErrInvalidDateRange       = errors.New("due_date_from must not be after due_date_to")
+	ErrInvalidStatus          = errors.New("status must be active, quarantined or discarded")
+	ErrStatusTransition       = errors.New("product batch cannot change to that status")
+	ErrProductBatchDiscarded  = errors.New("product batch has been discarded")
+	ErrInvalidDays            = errors.New("days must be between 1 and 365")
+	ErrCapacityExceeded       = errors.New("section capacity exceeded")
+	ErrIncompatibleSection    = errors.New("product batch is not compatible with the section")
+	ErrInvalidOverride        = errors.New("a compatibility override needs who makes it and why")
+	ErrSameSection            = errors.New("product batch is already in that section")
+	ErrTransferQuantity       = errors.New("quantity must be 0 for the whole batch or at most the current quantity, and the batch must hold stock")
+	ErrTransferReason         = errors.New("a transfer needs a reason")
+	ErrInvalidPick            = errors.New("a pick must take units from an active batch")
+)
+
+// NewCapacityError reports a write of requested units into a section that
+// only has room for free more.
+func NewCapacityError(sectionId int64, requested int64, free int64) error {
+	return fmt.Errorf("%w: section %d has room for %d more units, %d requested", ErrCapacityExceeded, sectionId, free, requested)
+}
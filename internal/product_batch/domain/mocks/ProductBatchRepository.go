@@ -7,6 +7,8 @@ import (
 
 	mock "github.com/stretchr/testify/mock"
 	domain "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_batch/domain"
+
+	time "time"
 )
 
 // ProductBatchRepository is an autogenerated mock type for the ProductBatchRepository type
@@ -14,6 +16,27 @@ type ProductBatchRepository struct {
 	mock.Mock
 }
 
+// CountInboundOrders provides a mock function with given fields: ctx, id
+func (_m *ProductBatchRepository) CountInboundOrders(ctx context.Context, id int64) (int64, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, int64) int64); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Create provides a mock function with given fields: ctx, productBatch
 func (_m *ProductBatchRepository) Create(ctx context.Context, productBatch *domain.ProductBatch) (*domain.ProductBatch, error) {
 	ret := _m.Called(ctx, productBatch)
@@ -37,6 +60,218 @@ func (_m *ProductBatchRepository) Create(ctx context.Context, productBatch *doma
 	return r0, r1
 }
 
+// Delete provides a mock function with given fields: ctx, id
+func (_m *ProductBatchRepository) Delete(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetAll provides a mock function with given fields: ctx, filter
+func (_m *ProductBatchRepository) GetAll(ctx context.Context, filter domain.ProductBatchFilter) ([]domain.ProductBatch, error) {
+	ret := _m.Called(ctx, filter)
+
+	var r0 []domain.ProductBatch
+	if rf, ok := ret.Get(0).(func(context.Context, domain.ProductBatchFilter) []domain.ProductBatch); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.ProductBatch)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, domain.ProductBatchFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetById provides a mock function with given fields: ctx, id
+func (_m *ProductBatchRepository) GetById(ctx context.Context, id int64) (*domain.ProductBatch, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *domain.ProductBatch
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *domain.ProductBatch); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ProductBatch)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetExpiring provides a mock function with given fields: ctx, now, until
+func (_m *ProductBatchRepository) GetExpiring(ctx context.Context, now time.Time, until time.Time) ([]domain.ExpiringBatch, error) {
+	ret := _m.Called(ctx, now, until)
+
+	var r0 []domain.ExpiringBatch
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time) []domain.ExpiringBatch); ok {
+		r0 = rf(ctx, now, until)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.ExpiringBatch)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, now, until)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetMovements provides a mock function with given fields: ctx, id
+func (_m *ProductBatchRepository) GetMovements(ctx context.Context, id int64) ([]domain.StockMovement, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 []domain.StockMovement
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []domain.StockMovement); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.StockMovement)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPendingExpiryAlerts provides a mock function with given fields: ctx, now, until
+func (_m *ProductBatchRepository) GetPendingExpiryAlerts(ctx context.Context, now time.Time, until time.Time) ([]domain.ExpiringBatch, error) {
+	ret := _m.Called(ctx, now, until)
+
+	var r0 []domain.ExpiringBatch
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time) []domain.ExpiringBatch); ok {
+		r0 = rf(ctx, now, until)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.ExpiringBatch)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, now, until)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RecordExpiryAlert provides a mock function with given fields: ctx, alert
+func (_m *ProductBatchRepository) RecordExpiryAlert(ctx context.Context, alert domain.ExpiryAlert) error {
+	ret := _m.Called(ctx, alert)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.ExpiryAlert) error); ok {
+		r0 = rf(ctx, alert)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Transfer provides a mock function with given fields: ctx, transfer
+func (_m *ProductBatchRepository) Transfer(ctx context.Context, transfer domain.Transfer) (*domain.TransferResult, error) {
+	ret := _m.Called(ctx, transfer)
+
+	var r0 *domain.TransferResult
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Transfer) *domain.TransferResult); ok {
+		r0 = rf(ctx, transfer)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.TransferResult)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, domain.Transfer) error); ok {
+		r1 = rf(ctx, transfer)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: ctx, productBatch
+func (_m *ProductBatchRepository) Update(ctx context.Context, productBatch *domain.ProductBatch) (*domain.ProductBatch, error) {
+	ret := _m.Called(ctx, productBatch)
+
+	var r0 *domain.ProductBatch
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.ProductBatch) *domain.ProductBatch); ok {
+		r0 = rf(ctx, productBatch)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ProductBatch)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.ProductBatch) error); ok {
+		r1 = rf(ctx, productBatch)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateStatus provides a mock function with given fields: ctx, productBatch
+func (_m *ProductBatchRepository) UpdateStatus(ctx context.Context, productBatch *domain.ProductBatch) (*domain.ProductBatch, error) {
+	ret := _m.Called(ctx, productBatch)
+
+	var r0 *domain.ProductBatch
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.ProductBatch) *domain.ProductBatch); ok {
+		r0 = rf(ctx, productBatch)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ProductBatch)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.ProductBatch) error); ok {
+		r1 = rf(ctx, productBatch)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 type mockConstructorTestingTNewProductBatchRepository interface {
 	mock.TestingT
 	Cleanup(func())
@@ -37,6 +37,204 @@ func (_m *ProductBatchService) Create(ctx context.Context, productBatch *domain.
 	return r0, r1
 }
 
+// Delete provides a mock function with given fields: ctx, id
+func (_m *ProductBatchService) Delete(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetAll provides a mock function with given fields: ctx, filter
+func (_m *ProductBatchService) GetAll(ctx context.Context, filter domain.ProductBatchFilter) ([]domain.ProductBatch, error) {
+	ret := _m.Called(ctx, filter)
+
+	var r0 []domain.ProductBatch
+	if rf, ok := ret.Get(0).(func(context.Context, domain.ProductBatchFilter) []domain.ProductBatch); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.ProductBatch)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, domain.ProductBatchFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetById provides a mock function with given fields: ctx, id
+func (_m *ProductBatchService) GetById(ctx context.Context, id int64) (*domain.ProductBatch, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *domain.ProductBatch
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *domain.ProductBatch); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ProductBatch)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetExpiringReport provides a mock function with given fields: ctx, days
+func (_m *ProductBatchService) GetExpiringReport(ctx context.Context, days int) (*domain.ExpiringReport, error) {
+	ret := _m.Called(ctx, days)
+
+	var r0 *domain.ExpiringReport
+	if rf, ok := ret.Get(0).(func(context.Context, int) *domain.ExpiringReport); ok {
+		r0 = rf(ctx, days)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ExpiringReport)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, days)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetMovements provides a mock function with given fields: ctx, id
+func (_m *ProductBatchService) GetMovements(ctx context.Context, id int64) (*domain.StockLedger, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *domain.StockLedger
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *domain.StockLedger); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.StockLedger)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SuggestSection provides a mock function with given fields: ctx, request
+func (_m *ProductBatchService) SuggestSection(ctx context.Context, request domain.PutAwayRequest) (*domain.PutAwaySuggestion, error) {
+	ret := _m.Called(ctx, request)
+
+	var r0 *domain.PutAwaySuggestion
+	if rf, ok := ret.Get(0).(func(context.Context, domain.PutAwayRequest) *domain.PutAwaySuggestion); ok {
+		r0 = rf(ctx, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.PutAwaySuggestion)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, domain.PutAwayRequest) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Transfer provides a mock function with given fields: ctx, transfer
+func (_m *ProductBatchService) Transfer(ctx context.Context, transfer domain.Transfer) (*domain.TransferResult, error) {
+	ret := _m.Called(ctx, transfer)
+
+	var r0 *domain.TransferResult
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Transfer) *domain.TransferResult); ok {
+		r0 = rf(ctx, transfer)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.TransferResult)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, domain.Transfer) error); ok {
+		r1 = rf(ctx, transfer)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: ctx, id, patch
+func (_m *ProductBatchService) Update(ctx context.Context, id int64, patch domain.ProductBatchPatch) (*domain.ProductBatch, error) {
+	ret := _m.Called(ctx, id, patch)
+
+	var r0 *domain.ProductBatch
+	if rf, ok := ret.Get(0).(func(context.Context, int64, domain.ProductBatchPatch) *domain.ProductBatch); ok {
+		r0 = rf(ctx, id, patch)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ProductBatch)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, domain.ProductBatchPatch) error); ok {
+		r1 = rf(ctx, id, patch)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateStatus provides a mock function with given fields: ctx, id, status, reason
+func (_m *ProductBatchService) UpdateStatus(ctx context.Context, id int64, status string, reason string) (*domain.ProductBatch, error) {
+	ret := _m.Called(ctx, id, status, reason)
+
+	var r0 *domain.ProductBatch
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string) *domain.ProductBatch); ok {
+		r0 = rf(ctx, id, status, reason)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ProductBatch)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string, string) error); ok {
+		r1 = rf(ctx, id, status, reason)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 type mockConstructorTestingTNewProductBatchService interface {
 	mock.TestingT
 	Cleanup(func())
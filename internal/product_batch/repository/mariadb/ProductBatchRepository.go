@@ -3,10 +3,20 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"time"
 
+	"github.com/go-sql-driver/mysql"
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_batch/domain"
+	purchaseOrders "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/purchase_orders/domain"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/libs/audit"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/libs/outbox"
 )
 
+// errRowReferenced is the MariaDB error number of a delete the stock ledger
+// holds back.
+const errRowReferenced = 1451
+
 type mariaDbProductBatchRepository struct {
 	db *sql.DB
 }
@@ -17,7 +27,17 @@ func NewMariadbProductBatchRepository(db *sql.DB) domain.ProductBatchRepository
 
 func (m mariaDbProductBatchRepository) Create(ctx context.Context, productBatch *domain.ProductBatch) (*domain.ProductBatch, error) {
 
-	productBatchResult, err := m.db.ExecContext(
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := adjustSectionCapacity(ctx, tx, productBatch.SectionId, productBatch.CurrentQuantity); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	productBatchResult, err := tx.ExecContext(
 		ctx,
 		SQLCreate,
 		&productBatch.BatchNumber,
@@ -33,6 +53,7 @@ func (m mariaDbProductBatchRepository) Create(ctx context.Context, productBatch
 	)
 
 	if err != nil {
+		tx.Rollback()
 		return nil, err
 	}
 
@@ -40,5 +61,643 @@ func (m mariaDbProductBatchRepository) Create(ctx context.Context, productBatch
 
 	productBatch.Id = lastId
 
+	// The inbound order of the batch is registered after it, and links
+	// itself to the receipt then.
+	err = createStockMovement(ctx, tx, &domain.StockMovement{
+		ProductBatchId: lastId,
+		SectionId:      productBatch.SectionId,
+		Type:           domain.MovementReceipt,
+		Quantity:       productBatch.CurrentQuantity,
+		CreatedAt:      time.Now().UTC(),
+	})
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	var warehouseId int64
+	if err := tx.QueryRowContext(ctx, SQLGetSectionWarehouseId, productBatch.SectionId).Scan(&warehouseId); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := outbox.PublishWarehouseEvent(ctx, tx, outbox.ProductBatchCreated, lastId, warehouseId, productBatch); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if override := productBatch.Override; override != nil {
+		err := audit.Record(ctx, tx, audit.Entry{
+			Action:     audit.ProductBatchCompatibilityOverride,
+			EntityType: "product_batch",
+			EntityId:   lastId,
+			Actor:      override.By,
+			Reason:     override.Reason,
+			Details: map[string]interface{}{
+				"product_id": productBatch.ProductId,
+				"section_id": productBatch.SectionId,
+				"violations": override.Violations,
+			},
+		})
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return productBatch, nil
+}
+
+func (m mariaDbProductBatchRepository) GetById(ctx context.Context, id int64) (*domain.ProductBatch, error) {
+	var productBatch domain.ProductBatch
+
+	err := scanProductBatch(m.db.QueryRowContext(ctx, SQLGetById, id), &productBatch)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrProductBatchNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &productBatch, nil
+}
+
+func (m mariaDbProductBatchRepository) GetAll(ctx context.Context, filter domain.ProductBatchFilter) ([]domain.ProductBatch, error) {
+	productBatches := []domain.ProductBatch{}
+
+	rows, err := m.db.QueryContext(
+		ctx,
+		SQLGetAll,
+		filter.ProductId, filter.ProductId,
+		filter.SectionId, filter.SectionId,
+		filter.WarehouseId, filter.WarehouseId,
+		filter.Status, filter.Status,
+		filter.DueDateFrom, filter.DueDateFrom,
+		filter.DueDateTo, filter.DueDateTo,
+		filter.MinTemperature, filter.MinTemperature,
+		filter.MaxTemperature, filter.MaxTemperature,
+		filter.MinQuantity, filter.MinQuantity,
+		filter.MaxQuantity, filter.MaxQuantity,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var productBatch domain.ProductBatch
+
+		if err := scanProductBatch(rows, &productBatch); err != nil {
+			return nil, err
+		}
+
+		productBatches = append(productBatches, productBatch)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return productBatches, nil
+}
+
+// Update and UpdateStatus move the section capacity by the change in
+// quantity, and record it in the stock ledger, in the same transaction as
+// the batch.
+func (m mariaDbProductBatchRepository) Update(ctx context.Context, productBatch *domain.ProductBatch) (*domain.ProductBatch, error) {
+	return m.updateStock(ctx, productBatch, SQLUpdate,
+		productBatch.CurrentQuantity,
+		productBatch.CurrentTemperature,
+		productBatch.Id,
+	)
+}
+
+func (m mariaDbProductBatchRepository) Delete(ctx context.Context, id int64) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	quantity, sectionId, err := getStockForUpdate(ctx, tx, id)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	var history int64
+	if err := tx.QueryRowContext(ctx, SQLCountStockHistory, id).Scan(&history); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if history > 0 {
+		tx.Rollback()
+		return domain.ErrProductBatchHasHistory
+	}
+
+	if err := adjustSectionCapacity(ctx, tx, sectionId, -quantity); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, SQLDeleteReceipt, id); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, SQLDelete, id); err != nil {
+		tx.Rollback()
+
+		var mysqlErr *mysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == errRowReferenced {
+			return domain.ErrProductBatchHasHistory
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (m mariaDbProductBatchRepository) CountInboundOrders(ctx context.Context, id int64) (int64, error) {
+	var count int64
+
+	if err := m.db.QueryRowContext(ctx, SQLCountInboundOrders, id).Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (m mariaDbProductBatchRepository) UpdateStatus(ctx context.Context, productBatch *domain.ProductBatch) (*domain.ProductBatch, error) {
+	return m.updateStock(ctx, productBatch, SQLUpdateStatus,
+		productBatch.Status,
+		productBatch.StatusReason,
+		productBatch.StatusChangedAt,
+		productBatch.CurrentQuantity,
+		productBatch.Id,
+	)
+}
+
+func (m mariaDbProductBatchRepository) updateStock(ctx context.Context, productBatch *domain.ProductBatch, query string, args ...any) (*domain.ProductBatch, error) {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	quantity, sectionId, err := getStockForUpdate(ctx, tx, productBatch.Id)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	delta := productBatch.CurrentQuantity - quantity
+
+	if err := adjustSectionCapacity(ctx, tx, sectionId, delta); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if delta != 0 {
+		movement := domain.StockMovement{Type: domain.MovementAdjustment}
+		if productBatch.Movement != nil {
+			movement = *productBatch.Movement
+		}
+		movement.ProductBatchId = productBatch.Id
+		movement.SectionId = sectionId
+		movement.Quantity = delta
+		movement.CreatedAt = time.Now().UTC()
+
+		if err := checkReference(ctx, tx, movement); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+
+		if err := createStockMovement(ctx, tx, &movement); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
 	return productBatch, nil
 }
+
+// checkReference makes sure the purchase order a pick is for exists; the
+// ledger references documents of several types, so no foreign key can.
+func checkReference(ctx context.Context, tx *sql.Tx, movement domain.StockMovement) error {
+	if movement.ReferenceType != domain.ReferencePurchaseOrder || movement.ReferenceId == nil {
+		return nil
+	}
+
+	var count int64
+	if err := tx.QueryRowContext(ctx, SQLCountPurchaseOrders, *movement.ReferenceId).Scan(&count); err != nil {
+		return err
+	}
+	if count == 0 {
+		return purchaseOrders.ErrIDNotFound
+	}
+
+	return nil
+}
+
+func (m mariaDbProductBatchRepository) GetMovements(ctx context.Context, id int64) ([]domain.StockMovement, error) {
+	movements := []domain.StockMovement{}
+
+	rows, err := m.db.QueryContext(ctx, SQLGetMovements, id)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var movement domain.StockMovement
+		var referenceType sql.NullString
+
+		err := rows.Scan(
+			&movement.Id,
+			&movement.ProductBatchId,
+			&movement.SectionId,
+			&movement.Type,
+			&movement.Quantity,
+			&movement.EmployeeId,
+			&movement.Reason,
+			&referenceType,
+			&movement.ReferenceId,
+			&movement.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		movement.ReferenceType = referenceType.String
+
+		movements = append(movements, movement)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return movements, nil
+}
+
+func (m mariaDbProductBatchRepository) GetExpiring(ctx context.Context, now time.Time, until time.Time) ([]domain.ExpiringBatch, error) {
+	return m.getExpiringBatches(ctx, SQLGetExpiring, now, until)
+}
+
+func (m mariaDbProductBatchRepository) GetPendingExpiryAlerts(ctx context.Context, now time.Time, until time.Time) ([]domain.ExpiringBatch, error) {
+	return m.getExpiringBatches(ctx, SQLGetPendingExpiryAlerts, now, until, now)
+}
+
+// RecordExpiryAlert marks the batch as alerted at the level of the alert and
+// publishes it in the same transaction, so each alert is sent exactly once.
+func (m mariaDbProductBatchRepository) RecordExpiryAlert(ctx context.Context, alert domain.ExpiryAlert) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, SQLSetExpiryAlert, alert.Level, alert.ProductBatchId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := outbox.PublishWarehouseEvent(ctx, tx, outbox.ProductBatchExpiry, alert.ProductBatchId, alert.WarehouseId, alert); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Transfer moves the units between sections in a single transaction: both
+// capacities, the batches and the ledger entries change together or not at
+// all. The stock is read under lock, so a partial transfer never takes more
+// than the batch holds.
+func (m mariaDbProductBatchRepository) Transfer(ctx context.Context, transfer domain.Transfer) (*domain.TransferResult, error) {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	quantity, fromSectionId, err := getStockForUpdate(ctx, tx, transfer.ProductBatchId)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if fromSectionId == transfer.ToSectionId {
+		tx.Rollback()
+		return nil, domain.ErrSameSection
+	}
+
+	moved := transfer.Quantity
+	if moved == 0 {
+		moved = quantity
+	}
+	if moved < 1 || moved > quantity {
+		tx.Rollback()
+		return nil, domain.ErrTransferQuantity
+	}
+
+	if err := adjustSectionCapacity(ctx, tx, transfer.ToSectionId, moved); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := adjustSectionCapacity(ctx, tx, fromSectionId, -moved); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	split := moved < quantity
+	targetId := transfer.ProductBatchId
+
+	if split {
+		if _, err := tx.ExecContext(ctx, SQLTakeProductBatchQuantity, moved, transfer.ProductBatchId); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+
+		result, err := tx.ExecContext(ctx, SQLSplitProductBatch, moved, moved, transfer.ToSectionId, transfer.ProductBatchId)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+
+		targetId, _ = result.LastInsertId()
+	} else {
+		if _, err := tx.ExecContext(ctx, SQLMoveProductBatch, transfer.ToSectionId, transfer.ProductBatchId); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	now := time.Now().UTC()
+	employeeId := transfer.EmployeeId
+	sourceId := transfer.ProductBatchId
+	movements := []domain.StockMovement{
+		{
+			ProductBatchId: transfer.ProductBatchId,
+			SectionId:      fromSectionId,
+			Type:           domain.MovementTransferOut,
+			Quantity:       -moved,
+			EmployeeId:     &employeeId,
+			Reason:         transfer.Reason,
+			ReferenceType:  domain.ReferenceProductBatch,
+			ReferenceId:    &targetId,
+			CreatedAt:      now,
+		},
+		{
+			ProductBatchId: targetId,
+			SectionId:      transfer.ToSectionId,
+			Type:           domain.MovementTransferIn,
+			Quantity:       moved,
+			EmployeeId:     &employeeId,
+			Reason:         transfer.Reason,
+			ReferenceType:  domain.ReferenceProductBatch,
+			ReferenceId:    &sourceId,
+			CreatedAt:      now,
+		},
+	}
+
+	for i := range movements {
+		if err := createStockMovement(ctx, tx, &movements[i]); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	if override := transfer.Override; override != nil {
+		err := audit.Record(ctx, tx, audit.Entry{
+			Action:     audit.ProductBatchCompatibilityOverride,
+			EntityType: "product_batch",
+			EntityId:   targetId,
+			Actor:      override.By,
+			Reason:     override.Reason,
+			Details: map[string]interface{}{
+				"transferred_from": transfer.ProductBatchId,
+				"section_id":       transfer.ToSectionId,
+				"violations":       override.Violations,
+			},
+		})
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	var warehouseId int64
+	if err := tx.QueryRowContext(ctx, SQLGetSectionWarehouseId, transfer.ToSectionId).Scan(&warehouseId); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := outbox.PublishWarehouseEvent(ctx, tx, outbox.ProductBatchTransferred, targetId, warehouseId, movements); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	source, err := m.GetById(ctx, transfer.ProductBatchId)
+	if err != nil {
+		return nil, err
+	}
+
+	target := source
+	if split {
+		target, err = m.GetById(ctx, targetId)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &domain.TransferResult{
+		Source:    source,
+		Target:    target,
+		Split:     split,
+		Movements: movements,
+	}, nil
+}
+
+func (m mariaDbProductBatchRepository) getExpiringBatches(ctx context.Context, query string, args ...any) ([]domain.ExpiringBatch, error) {
+	batches := []domain.ExpiringBatch{}
+
+	rows, err := m.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var batch domain.ExpiringBatch
+
+		err := rows.Scan(
+			&batch.Id,
+			&batch.BatchNumber,
+			&batch.ProductId,
+			&batch.ProductCode,
+			&batch.Description,
+			&batch.DueDate,
+			&batch.CurrentQuantity,
+			&batch.PurchasePrice,
+			&batch.ExpiryAlert,
+			&batch.SectionId,
+			&batch.SectionNumber,
+			&batch.WarehouseId,
+			&batch.WarehouseCode,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		batches = append(batches, batch)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return batches, nil
+}
+
+func getStockForUpdate(ctx context.Context, tx *sql.Tx, id int64) (quantity int64, sectionId int64, err error) {
+	err = tx.QueryRowContext(ctx, SQLGetStockForUpdate, id).Scan(&quantity, &sectionId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, 0, domain.ErrProductBatchNotFound
+	}
+
+	return quantity, sectionId, err
+}
+
+// adjustSectionCapacity adds delta units to the current capacity of the
+// section, failing with the free space when they do not fit. Every change is
+// published as a section.capacity_changed event in the same transaction.
+func adjustSectionCapacity(ctx context.Context, tx *sql.Tx, sectionId int64, delta int64) error {
+	if delta == 0 {
+		return nil
+	}
+
+	if delta < 0 {
+		if _, err := tx.ExecContext(ctx, SQLReleaseSectionCapacity, -delta, sectionId); err != nil {
+			return err
+		}
+		return publishSectionCapacity(ctx, tx, sectionId)
+	}
+
+	result, err := tx.ExecContext(ctx, SQLReserveSectionCapacity, delta, sectionId, delta)
+	if err != nil {
+		return err
+	}
+
+	if affected, _ := result.RowsAffected(); affected > 0 {
+		return publishSectionCapacity(ctx, tx, sectionId)
+	}
+
+	var free int64
+	if err := tx.QueryRowContext(ctx, SQLGetSectionFreeCapacity, sectionId).Scan(&free); err != nil {
+		return err
+	}
+	if free < 0 {
+		free = 0
+	}
+
+	return domain.NewCapacityError(sectionId, delta, free)
+}
+
+// sectionCapacity is the payload of section.capacity_changed events raised
+// by batch writes, named like the fields of the section itself.
+type sectionCapacity struct {
+	Id              int64 `json:"id"`
+	SectionNumber   int64 `json:"section_number"`
+	WarehouseId     int64 `json:"warehouse_id"`
+	CurrentCapacity int64 `json:"current_capacity"`
+	MinimumCapacity int64 `json:"minimum_capacity"`
+	MaximumCapacity int64 `json:"maximum_capacity"`
+}
+
+func publishSectionCapacity(ctx context.Context, tx *sql.Tx, sectionId int64) error {
+	var section sectionCapacity
+
+	err := tx.QueryRowContext(ctx, SQLGetSectionCapacity, sectionId).Scan(
+		&section.Id,
+		&section.SectionNumber,
+		&section.WarehouseId,
+		&section.CurrentCapacity,
+		&section.MinimumCapacity,
+		&section.MaximumCapacity,
+	)
+	if err != nil {
+		return err
+	}
+
+	return outbox.PublishWarehouseEvent(ctx, tx, outbox.SectionCapacityChanged, section.Id, section.WarehouseId, section)
+}
+
+func createStockMovement(ctx context.Context, tx *sql.Tx, movement *domain.StockMovement) error {
+	result, err := tx.ExecContext(
+		ctx,
+		SQLCreateStockMovement,
+		movement.ProductBatchId,
+		movement.SectionId,
+		movement.Type,
+		movement.Quantity,
+		movement.EmployeeId,
+		movement.Reason,
+		movement.ReferenceType,
+		movement.ReferenceId,
+		movement.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	movement.Id, _ = result.LastInsertId()
+
+	return nil
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanProductBatch(row scanner, productBatch *domain.ProductBatch) error {
+	var statusReason sql.NullString
+
+	err := row.Scan(
+		&productBatch.Id,
+		&productBatch.BatchNumber,
+		&productBatch.CurrentQuantity,
+		&productBatch.CurrentTemperature,
+		&productBatch.DueDate,
+		&productBatch.InitialQuantity,
+		&productBatch.ManufacturingDate,
+		&productBatch.ManufacturingHour,
+		&productBatch.MinumumTemperature,
+		&productBatch.ProductId,
+		&productBatch.SectionId,
+		&productBatch.Status,
+		&statusReason,
+		&productBatch.StatusChangedAt,
+	)
+	productBatch.StatusReason = statusReason.String
+
+	return err
+}
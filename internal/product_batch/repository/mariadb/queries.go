@@ -16,5 +16,242 @@ const (
     )
     VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
 
+    `
+
+	SQLGetSectionWarehouseId = `
+    SELECT warehouse_id FROM sections WHERE id = ?
+    `
+
+	SQLGetById = `
+    SELECT
+        id,
+        batch_number,
+        current_quantity,
+        current_temperature,
+        due_date,
+        initial_quantity,
+        manufacturing_date,
+        manufacturing_hour,
+        minimum_temperature,
+        product_id,
+        section_id,
+        status,
+        status_reason,
+        status_changed_at
+    FROM product_batches
+    WHERE id = ?
+    `
+
+	SQLGetAll = `
+    SELECT
+        pb.id,
+        pb.batch_number,
+        pb.current_quantity,
+        pb.current_temperature,
+        pb.due_date,
+        pb.initial_quantity,
+        pb.manufacturing_date,
+        pb.manufacturing_hour,
+        pb.minimum_temperature,
+        pb.product_id,
+        pb.section_id,
+        pb.status,
+        pb.status_reason,
+        pb.status_changed_at
+    FROM product_batches pb
+    JOIN sections s ON s.id = pb.section_id
+    WHERE (? = 0 OR pb.product_id = ?)
+    AND (? = 0 OR pb.section_id = ?)
+    AND (? = 0 OR s.warehouse_id = ?)
+    AND (? = '' OR pb.status = ?)
+    AND (? IS NULL OR pb.due_date >= ?)
+    AND (? IS NULL OR pb.due_date <= ?)
+    AND (? IS NULL OR pb.current_temperature >= ?)
+    AND (? IS NULL OR pb.current_temperature <= ?)
+    AND (? IS NULL OR pb.current_quantity >= ?)
+    AND (? IS NULL OR pb.current_quantity <= ?)
+    ORDER BY pb.id
+    `
+
+	SQLUpdate = `
+    UPDATE product_batches SET current_quantity = ?, current_temperature = ? WHERE id = ?
+    `
+
+	SQLDelete = `
+    DELETE FROM product_batches WHERE id = ?
+    `
+
+	// A batch written by mistake only has its receipt, not yet linked to an
+	// inbound order. The receipt goes with it; any other movement is history
+	// and keeps the batch.
+	SQLCountStockHistory = `
+    SELECT COUNT(*) FROM stock_movements
+    WHERE product_batch_id = ? AND NOT (movement_type = 'receipt' AND reference_id IS NULL)
+    `
+
+	SQLDeleteReceipt = `
+    DELETE FROM stock_movements
+    WHERE product_batch_id = ? AND movement_type = 'receipt' AND reference_id IS NULL
+    `
+
+	SQLCountInboundOrders = `
+    SELECT COUNT(*) FROM inbound_orders WHERE product_batch_id = ?
+    `
+
+	SQLUpdateStatus = `
+    UPDATE product_batches
+    SET status = ?, status_reason = ?, status_changed_at = ?, current_quantity = ?
+    WHERE id = ?
+    `
+
+	// The value of expiring stock uses the purchase price in effect now.
+	sqlExpiringBatches = `
+    SELECT
+        pb.id,
+        pb.batch_number,
+        pb.product_id,
+        p.product_code,
+        p.description,
+        pb.due_date,
+        pb.current_quantity,
+        (
+            SELECT pr.purchase_price FROM product_records pr
+            WHERE pr.product_id = pb.product_id AND pr.last_update_date <= ?
+            ORDER BY pr.last_update_date DESC, pr.id DESC
+            LIMIT 1
+        ),
+        IFNULL(pb.expiry_alert, ''),
+        s.id,
+        s.section_number,
+        w.id,
+        w.warehouse_code
+    FROM product_batches pb
+    JOIN products p ON p.id = pb.product_id
+    JOIN sections s ON s.id = pb.section_id
+    JOIN warehouses w ON w.id = s.warehouse_id
+    WHERE pb.status = 'active'
+    AND pb.current_quantity > 0
+    AND pb.due_date <= ?
+    `
+
+	SQLGetExpiring = sqlExpiringBatches + `
+    ORDER BY w.id, s.id, pb.due_date, pb.id
+    `
+
+	// A batch is alerted once when it enters the window and once more when
+	// it actually expires.
+	SQLGetPendingExpiryAlerts = sqlExpiringBatches + `
+    AND (pb.expiry_alert IS NULL OR (pb.expiry_alert = 'expiring' AND pb.due_date < ?))
+    ORDER BY pb.due_date, pb.id
+    `
+
+	SQLSetExpiryAlert = `
+    UPDATE product_batches SET expiry_alert = ? WHERE id = ?
+    `
+
+	SQLGetStockForUpdate = `
+    SELECT current_quantity, section_id FROM product_batches WHERE id = ? FOR UPDATE
+    `
+
+	// Reserving only succeeds while the section has room, which keeps
+	// concurrent writes from overfilling it.
+	SQLReserveSectionCapacity = `
+    UPDATE sections SET current_capacity = current_capacity + ?
+    WHERE id = ? AND current_capacity + ? <= maximum_capacity
+    `
+
+	SQLReleaseSectionCapacity = `
+    UPDATE sections SET current_capacity = GREATEST(current_capacity - ?, 0) WHERE id = ?
+    `
+
+	SQLGetSectionCapacity = `
+    SELECT id, section_number, warehouse_id, current_capacity, minimum_capacity, maximum_capacity
+    FROM sections WHERE id = ?
+    `
+
+	SQLGetSectionFreeCapacity = `
+    SELECT maximum_capacity - current_capacity FROM sections WHERE id = ?
+    `
+
+	SQLMoveProductBatch = `
+    UPDATE product_batches SET section_id = ? WHERE id = ?
+    `
+
+	SQLTakeProductBatchQuantity = `
+    UPDATE product_batches SET current_quantity = current_quantity - ? WHERE id = ?
+    `
+
+	// A split keeps everything about the batch but where it is and how much
+	// of it there is.
+	SQLSplitProductBatch = `
+    INSERT INTO product_batches (
+        batch_number,
+        current_quantity,
+        current_temperature,
+        due_date,
+        initial_quantity,
+        manufacturing_date,
+        manufacturing_hour,
+        minimum_temperature,
+        product_id,
+        section_id,
+        status,
+        status_reason,
+        status_changed_at,
+        expiry_alert
+    )
+    SELECT
+        batch_number,
+        ?,
+        current_temperature,
+        due_date,
+        ?,
+        manufacturing_date,
+        manufacturing_hour,
+        minimum_temperature,
+        product_id,
+        ?,
+        status,
+        status_reason,
+        status_changed_at,
+        expiry_alert
+    FROM product_batches
+    WHERE id = ?
+    `
+
+	SQLCreateStockMovement = `
+    INSERT INTO stock_movements (
+        product_batch_id,
+        section_id,
+        movement_type,
+        quantity,
+        employee_id,
+        reason,
+        reference_type,
+        reference_id,
+        created_at
+    )
+    VALUES (?, ?, ?, ?, ?, ?, NULLIF(?, ''), ?, ?)
+    `
+
+	SQLGetMovements = `
+    SELECT
+        id,
+        product_batch_id,
+        section_id,
+        movement_type,
+        quantity,
+        employee_id,
+        reason,
+        reference_type,
+        reference_id,
+        created_at
+    FROM stock_movements
+    WHERE product_batch_id = ?
+    ORDER BY created_at, id
+    `
+
+	SQLCountPurchaseOrders = `
+    SELECT COUNT(*) FROM purchase_orders WHERE id = ?
     `
 )
@@ -2,15 +2,21 @@ package repository_test
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
 	"regexp"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_batch/domain"
 	repository "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_batch/repository/mariadb"
+	purchaseOrders "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/purchase_orders/domain"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/libs/audit"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/libs/outbox"
 )
 
 var timeNow = time.Now()
@@ -37,6 +43,12 @@ func TestMariaDBProductRecordsRepository_Create(t *testing.T) {
 		assert.NoError(t, err)
 		defer db.Close()
 
+		mock.ExpectBegin()
+		mock.
+			ExpectExec(regexp.QuoteMeta(repository.SQLReserveSectionCapacity)).
+			WithArgs(expectedProductBatch.CurrentQuantity, expectedProductBatch.SectionId, expectedProductBatch.CurrentQuantity).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		expectSectionCapacityChanged(mock, expectedProductBatch.SectionId)
 		mock.
 			ExpectExec(regexp.QuoteMeta(repository.SQLCreate)).
 			WithArgs(
@@ -52,6 +64,19 @@ func TestMariaDBProductRecordsRepository_Create(t *testing.T) {
 				expectedProductBatch.SectionId,
 			).
 			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.
+			ExpectExec(regexp.QuoteMeta(repository.SQLCreateStockMovement)).
+			WithArgs(int64(1), expectedProductBatch.SectionId, domain.MovementReceipt, expectedProductBatch.CurrentQuantity, nil, "", "", nil, sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.
+			ExpectQuery(regexp.QuoteMeta(repository.SQLGetSectionWarehouseId)).
+			WithArgs(expectedProductBatch.SectionId).
+			WillReturnRows(sqlmock.NewRows([]string{"warehouse_id"}).AddRow(1))
+		mock.
+			ExpectExec(regexp.QuoteMeta(outbox.SQLCreateOutboxEvent)).
+			WithArgs(outbox.ProductBatchCreated, expectedProductBatch.Id, int64(1), sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
 
 		productBatchRepository := repository.NewMariadbProductBatchRepository(db)
 
@@ -59,6 +84,7 @@ func TestMariaDBProductRecordsRepository_Create(t *testing.T) {
 
 		assert.NoError(t, err)
 		assert.Equal(t, result, &expectedProductBatch)
+		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
 	t.Run("create_fail_exec: should return error when query execution fails", func(t *testing.T) {
@@ -68,14 +94,976 @@ func TestMariaDBProductRecordsRepository_Create(t *testing.T) {
 		assert.NoError(t, err)
 		defer db.Close()
 
+		mock.ExpectBegin()
 		mock.
 			ExpectExec(regexp.QuoteMeta(repository.SQLCreate)).
 			WithArgs(0, 0, 0, 0).
 			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectRollback()
+
+		productBatchRepository := repository.NewMariadbProductBatchRepository(db)
+		_, err = productBatchRepository.Create(context.TODO(), &expectedProductBatch)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("create_capacity_exceeded: should not insert a batch the section has no room for", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.
+			ExpectExec(regexp.QuoteMeta(repository.SQLReserveSectionCapacity)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.
+			ExpectQuery(regexp.QuoteMeta(repository.SQLGetSectionFreeCapacity)).
+			WithArgs(expectedProductBatch.SectionId).
+			WillReturnRows(sqlmock.NewRows([]string{"free"}).AddRow(0))
+		mock.ExpectRollback()
+
+		productBatchRepository := repository.NewMariadbProductBatchRepository(db)
+		_, err = productBatchRepository.Create(context.TODO(), &expectedProductBatch)
+
+		assert.ErrorIs(t, err, domain.ErrCapacityExceeded)
+		assert.Contains(t, err.Error(), "room for 0 more units")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("create_override: should write the compatibility override to the audit log", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		productBatch := expectedProductBatch
+		productBatch.Override = &domain.CompatibilityOverride{By: "maria", Reason: "freezer under repair", Violations: []string{"any"}}
+
+		mock.ExpectBegin()
+		mock.
+			ExpectExec(regexp.QuoteMeta(repository.SQLReserveSectionCapacity)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		expectSectionCapacityChanged(mock, 1)
+		mock.
+			ExpectExec(regexp.QuoteMeta(repository.SQLCreate)).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.
+			ExpectExec(regexp.QuoteMeta(repository.SQLCreateStockMovement)).
+			WithArgs(int64(1), int64(1), domain.MovementReceipt, int64(1), nil, "", "", nil, sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.
+			ExpectQuery(regexp.QuoteMeta(repository.SQLGetSectionWarehouseId)).
+			WillReturnRows(sqlmock.NewRows([]string{"warehouse_id"}).AddRow(1))
+		mock.
+			ExpectExec(regexp.QuoteMeta(outbox.SQLCreateOutboxEvent)).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.
+			ExpectExec(regexp.QuoteMeta(audit.SQLCreateAuditEntry)).
+			WithArgs(audit.ProductBatchCompatibilityOverride, "product_batch", int64(1), "maria", "freezer under repair", sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		productBatchRepository := repository.NewMariadbProductBatchRepository(db)
+		_, err = productBatchRepository.Create(context.TODO(), &productBatch)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("create_fail_outbox: should rollback when the domain event cannot be written", func(t *testing.T) {
+
+		db, mock, err := sqlmock.New()
+
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.
+			ExpectExec(regexp.QuoteMeta(repository.SQLReserveSectionCapacity)).
+			WithArgs(expectedProductBatch.CurrentQuantity, expectedProductBatch.SectionId, expectedProductBatch.CurrentQuantity).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		expectSectionCapacityChanged(mock, expectedProductBatch.SectionId)
+		mock.
+			ExpectExec(regexp.QuoteMeta(repository.SQLCreate)).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.
+			ExpectExec(regexp.QuoteMeta(repository.SQLCreateStockMovement)).
+			WithArgs(int64(1), int64(1), domain.MovementReceipt, int64(1), nil, "", "", nil, sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.
+			ExpectQuery(regexp.QuoteMeta(repository.SQLGetSectionWarehouseId)).
+			WillReturnRows(sqlmock.NewRows([]string{"warehouse_id"}).AddRow(1))
+		mock.
+			ExpectExec(regexp.QuoteMeta(outbox.SQLCreateOutboxEvent)).
+			WillReturnError(fmt.Errorf("outbox error"))
+		mock.ExpectRollback()
 
 		productBatchRepository := repository.NewMariadbProductBatchRepository(db)
 		_, err = productBatchRepository.Create(context.TODO(), &expectedProductBatch)
 
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("create_fail_section: should rollback when the section warehouse cannot be read", func(t *testing.T) {
+
+		db, mock, err := sqlmock.New()
+
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.
+			ExpectExec(regexp.QuoteMeta(repository.SQLReserveSectionCapacity)).
+			WithArgs(expectedProductBatch.CurrentQuantity, expectedProductBatch.SectionId, expectedProductBatch.CurrentQuantity).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		expectSectionCapacityChanged(mock, expectedProductBatch.SectionId)
+		mock.
+			ExpectExec(regexp.QuoteMeta(repository.SQLCreate)).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.
+			ExpectExec(regexp.QuoteMeta(repository.SQLCreateStockMovement)).
+			WithArgs(int64(1), int64(1), domain.MovementReceipt, int64(1), nil, "", "", nil, sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.
+			ExpectQuery(regexp.QuoteMeta(repository.SQLGetSectionWarehouseId)).
+			WillReturnError(sql.ErrNoRows)
+		mock.ExpectRollback()
+
+		productBatchRepository := repository.NewMariadbProductBatchRepository(db)
+		_, err = productBatchRepository.Create(context.TODO(), &expectedProductBatch)
+
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+var productBatchColumns = []string{
+	"id",
+	"batch_number",
+	"current_quantity",
+	"current_temperature",
+	"due_date",
+	"initial_quantity",
+	"manufacturing_date",
+	"manufacturing_hour",
+	"minimum_temperature",
+	"product_id",
+	"section_id",
+	"status",
+	"status_reason",
+	"status_changed_at",
+}
+
+// expectSectionCapacityChanged expects the section.capacity_changed event
+// published after every change to the current capacity of a section.
+func expectSectionCapacityChanged(mock sqlmock.Sqlmock, sectionId int64) {
+	mock.
+		ExpectQuery(regexp.QuoteMeta(repository.SQLGetSectionCapacity)).
+		WithArgs(sectionId).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "section_number", "warehouse_id", "current_capacity", "minimum_capacity", "maximum_capacity"}).
+			AddRow(sectionId, 10, 4, 20, 0, 100))
+	mock.
+		ExpectExec(regexp.QuoteMeta(outbox.SQLCreateOutboxEvent)).
+		WithArgs(outbox.SectionCapacityChanged, sectionId, int64(4), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+}
+
+func productBatchRow(rows *sqlmock.Rows, productBatch domain.ProductBatch) *sqlmock.Rows {
+	return rows.AddRow(
+		productBatch.Id,
+		productBatch.BatchNumber,
+		productBatch.CurrentQuantity,
+		productBatch.CurrentTemperature,
+		productBatch.DueDate,
+		productBatch.InitialQuantity,
+		productBatch.ManufacturingDate,
+		productBatch.ManufacturingHour,
+		productBatch.MinumumTemperature,
+		productBatch.ProductId,
+		productBatch.SectionId,
+		productBatch.Status,
+		nil,
+		productBatch.StatusChangedAt,
+	)
+}
+
+func TestMariaDBProductBatchRepository_GetById(t *testing.T) {
+
+	t.Run("get_by_id_ok: should return the product batch", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.
+			ExpectQuery(regexp.QuoteMeta(repository.SQLGetById)).
+			WithArgs(int64(1)).
+			WillReturnRows(productBatchRow(sqlmock.NewRows(productBatchColumns), expectedProductBatch))
+
+		productBatchRepository := repository.NewMariadbProductBatchRepository(db)
+		result, err := productBatchRepository.GetById(context.TODO(), 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &expectedProductBatch, result)
+	})
+
+	t.Run("get_by_id_not_found: should return ErrProductBatchNotFound", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.
+			ExpectQuery(regexp.QuoteMeta(repository.SQLGetById)).
+			WithArgs(int64(1)).
+			WillReturnError(sql.ErrNoRows)
+
+		productBatchRepository := repository.NewMariadbProductBatchRepository(db)
+		_, err = productBatchRepository.GetById(context.TODO(), 1)
+
+		assert.ErrorIs(t, err, domain.ErrProductBatchNotFound)
+	})
+}
+
+func TestMariaDBProductBatchRepository_GetAll(t *testing.T) {
+
+	t.Run("get_all_ok: should pass every filter twice and return the batches", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		minQuantity := int64(1)
+		filter := domain.ProductBatchFilter{WarehouseId: 3, MinQuantity: &minQuantity}
+
+		mock.
+			ExpectQuery(regexp.QuoteMeta(repository.SQLGetAll)).
+			WithArgs(
+				int64(0), int64(0),
+				int64(0), int64(0),
+				int64(3), int64(3),
+				"", "",
+				nil, nil,
+				nil, nil,
+				nil, nil,
+				nil, nil,
+				int64(1), int64(1),
+				nil, nil,
+			).
+			WillReturnRows(productBatchRow(sqlmock.NewRows(productBatchColumns), expectedProductBatch))
+
+		productBatchRepository := repository.NewMariadbProductBatchRepository(db)
+		result, err := productBatchRepository.GetAll(context.TODO(), filter)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.ProductBatch{expectedProductBatch}, result)
+	})
+
+	t.Run("get_all_fail: should return error when query fails", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.
+			ExpectQuery(regexp.QuoteMeta(repository.SQLGetAll)).
+			WillReturnError(fmt.Errorf("any error"))
+
+		productBatchRepository := repository.NewMariadbProductBatchRepository(db)
+		_, err = productBatchRepository.GetAll(context.TODO(), domain.ProductBatchFilter{})
+
+		assert.Error(t, err)
+	})
+}
+
+func TestMariaDBProductBatchRepository_Update(t *testing.T) {
+
+	t.Run("update_ok: should update quantity and temperature and take the extra units from the section", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		productBatch := expectedProductBatch
+		productBatch.CurrentQuantity = 5
+
+		mock.ExpectBegin()
+		mock.
+			ExpectQuery(regexp.QuoteMeta(repository.SQLGetStockForUpdate)).
+			WithArgs(productBatch.Id).
+			WillReturnRows(sqlmock.NewRows([]string{"current_quantity", "section_id"}).AddRow(2, 1))
+		mock.
+			ExpectExec(regexp.QuoteMeta(repository.SQLReserveSectionCapacity)).
+			WithArgs(int64(3), int64(1), int64(3)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		expectSectionCapacityChanged(mock, 1)
+		mock.
+			ExpectExec(regexp.QuoteMeta(repository.SQLUpdate)).
+			WithArgs(int64(5), productBatch.CurrentTemperature, productBatch.Id).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.
+			ExpectExec(regexp.QuoteMeta(repository.SQLCreateStockMovement)).
+			WithArgs(productBatch.Id, int64(1), domain.MovementAdjustment, int64(3), nil, "", "", nil, sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		productBatchRepository := repository.NewMariadbProductBatchRepository(db)
+		result, err := productBatchRepository.Update(context.TODO(), &productBatch)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &productBatch, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("update_capacity_exceeded: should state the free space of the section and rollback", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		productBatch := expectedProductBatch
+		productBatch.CurrentQuantity = 50
+
+		mock.ExpectBegin()
+		mock.
+			ExpectQuery(regexp.QuoteMeta(repository.SQLGetStockForUpdate)).
+			WillReturnRows(sqlmock.NewRows([]string{"current_quantity", "section_id"}).AddRow(10, 1))
+		mock.
+			ExpectExec(regexp.QuoteMeta(repository.SQLReserveSectionCapacity)).
+			WithArgs(int64(40), int64(1), int64(40)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.
+			ExpectQuery(regexp.QuoteMeta(repository.SQLGetSectionFreeCapacity)).
+			WithArgs(int64(1)).
+			WillReturnRows(sqlmock.NewRows([]string{"free"}).AddRow(15))
+		mock.ExpectRollback()
+
+		productBatchRepository := repository.NewMariadbProductBatchRepository(db)
+		_, err = productBatchRepository.Update(context.TODO(), &productBatch)
+
+		assert.ErrorIs(t, err, domain.ErrCapacityExceeded)
+		assert.EqualError(t, err, "section capacity exceeded: section 1 has room for 15 more units, 40 requested")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("update_fail: should return error when query execution fails", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.
+			ExpectQuery(regexp.QuoteMeta(repository.SQLGetStockForUpdate)).
+			WillReturnRows(sqlmock.NewRows([]string{"current_quantity", "section_id"}).AddRow(expectedProductBatch.CurrentQuantity, 1))
+		mock.
+			ExpectExec(regexp.QuoteMeta(repository.SQLUpdate)).
+			WillReturnError(fmt.Errorf("any error"))
+		mock.ExpectRollback()
+
+		productBatchRepository := repository.NewMariadbProductBatchRepository(db)
+		_, err = productBatchRepository.Update(context.TODO(), &expectedProductBatch)
+
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestMariaDBProductBatchRepository_Delete(t *testing.T) {
+
+	t.Run("delete_ok: should delete the product batch with its receipt and free its space", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.
+			ExpectQuery(regexp.QuoteMeta(repository.SQLGetStockForUpdate)).
+			WithArgs(int64(1)).
+			WillReturnRows(sqlmock.NewRows([]string{"current_quantity", "section_id"}).AddRow(8, 2))
+		mock.
+			ExpectQuery(regexp.QuoteMeta(repository.SQLCountStockHistory)).
+			WithArgs(int64(1)).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.
+			ExpectExec(regexp.QuoteMeta(repository.SQLReleaseSectionCapacity)).
+			WithArgs(int64(8), int64(2)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		expectSectionCapacityChanged(mock, 2)
+		mock.
+			ExpectExec(regexp.QuoteMeta(repository.SQLDeleteReceipt)).
+			WithArgs(int64(1)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.
+			ExpectExec(regexp.QuoteMeta(repository.SQLDelete)).
+			WithArgs(int64(1)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		productBatchRepository := repository.NewMariadbProductBatchRepository(db)
+
+		assert.NoError(t, productBatchRepository.Delete(context.TODO(), 1))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("delete_not_found: should return ErrProductBatchNotFound when the batch does not exist", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.
+			ExpectQuery(regexp.QuoteMeta(repository.SQLGetStockForUpdate)).
+			WithArgs(int64(1)).
+			WillReturnError(sql.ErrNoRows)
+		mock.ExpectRollback()
+
+		productBatchRepository := repository.NewMariadbProductBatchRepository(db)
+
+		assert.ErrorIs(t, productBatchRepository.Delete(context.TODO(), 1), domain.ErrProductBatchNotFound)
+	})
+
+	t.Run("delete_has_history: should refuse batches with stock movements besides their receipt", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.
+			ExpectQuery(regexp.QuoteMeta(repository.SQLGetStockForUpdate)).
+			WithArgs(int64(1)).
+			WillReturnRows(sqlmock.NewRows([]string{"current_quantity", "section_id"}).AddRow(0, 2))
+		mock.
+			ExpectQuery(regexp.QuoteMeta(repository.SQLCountStockHistory)).
+			WithArgs(int64(1)).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+		mock.ExpectRollback()
+
+		productBatchRepository := repository.NewMariadbProductBatchRepository(db)
+
+		assert.ErrorIs(t, productBatchRepository.Delete(context.TODO(), 1), domain.ErrProductBatchHasHistory)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("delete_referenced: should refuse batches the stock ledger still references", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.
+			ExpectQuery(regexp.QuoteMeta(repository.SQLGetStockForUpdate)).
+			WithArgs(int64(1)).
+			WillReturnRows(sqlmock.NewRows([]string{"current_quantity", "section_id"}).AddRow(0, 2))
+		mock.
+			ExpectQuery(regexp.QuoteMeta(repository.SQLCountStockHistory)).
+			WithArgs(int64(1)).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.
+			ExpectExec(regexp.QuoteMeta(repository.SQLDeleteReceipt)).
+			WithArgs(int64(1)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.
+			ExpectExec(regexp.QuoteMeta(repository.SQLDelete)).
+			WithArgs(int64(1)).
+			WillReturnError(&mysql.MySQLError{Number: 1451})
+		mock.ExpectRollback()
+
+		productBatchRepository := repository.NewMariadbProductBatchRepository(db)
+
+		assert.ErrorIs(t, productBatchRepository.Delete(context.TODO(), 1), domain.ErrProductBatchHasHistory)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("delete_fail_outbox: should rollback when the section capacity event cannot be written", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.
+			ExpectQuery(regexp.QuoteMeta(repository.SQLGetStockForUpdate)).
+			WithArgs(int64(1)).
+			WillReturnRows(sqlmock.NewRows([]string{"current_quantity", "section_id"}).AddRow(8, 2))
+		mock.
+			ExpectQuery(regexp.QuoteMeta(repository.SQLCountStockHistory)).
+			WithArgs(int64(1)).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.
+			ExpectExec(regexp.QuoteMeta(repository.SQLReleaseSectionCapacity)).
+			WithArgs(int64(8), int64(2)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.
+			ExpectQuery(regexp.QuoteMeta(repository.SQLGetSectionCapacity)).
+			WithArgs(int64(2)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "section_number", "warehouse_id", "current_capacity", "minimum_capacity", "maximum_capacity"}).
+				AddRow(2, 10, 4, 12, 0, 100))
+		mock.
+			ExpectExec(regexp.QuoteMeta(outbox.SQLCreateOutboxEvent)).
+			WithArgs(outbox.SectionCapacityChanged, int64(2), int64(4), sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnError(fmt.Errorf("outbox unavailable"))
+		mock.ExpectRollback()
+
+		productBatchRepository := repository.NewMariadbProductBatchRepository(db)
+
+		assert.Error(t, productBatchRepository.Delete(context.TODO(), 1))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestMariaDBProductBatchRepository_CountInboundOrders(t *testing.T) {
+
+	t.Run("count_inbound_orders_ok: should return how many inbound orders reference the batch", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.
+			ExpectQuery(regexp.QuoteMeta(repository.SQLCountInboundOrders)).
+			WithArgs(int64(1)).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+
+		productBatchRepository := repository.NewMariadbProductBatchRepository(db)
+		count, err := productBatchRepository.CountInboundOrders(context.TODO(), 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+	})
+}
+
+func TestMariaDBProductBatchRepository_UpdateStatus(t *testing.T) {
+
+	t.Run("update_status_ok: should store the status, its reason and the quantity, freeing discarded stock", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		productBatch := expectedProductBatch
+		productBatch.Status = domain.StatusDiscarded
+		productBatch.StatusReason = "mould"
+		productBatch.StatusChangedAt = &timeNow
+		productBatch.CurrentQuantity = 0
+		productBatch.Movement = &domain.StockMovement{Type: domain.MovementDisposal, Reason: "mould"}
+
+		mock.ExpectBegin()
+		mock.
+			ExpectQuery(regexp.QuoteMeta(repository.SQLGetStockForUpdate)).
+			WithArgs(productBatch.Id).
+			WillReturnRows(sqlmock.NewRows([]string{"current_quantity", "section_id"}).AddRow(6, 1))
+		mock.
+			ExpectExec(regexp.QuoteMeta(repository.SQLReleaseSectionCapacity)).
+			WithArgs(int64(6), int64(1)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		expectSectionCapacityChanged(mock, 1)
+		mock.
+			ExpectExec(regexp.QuoteMeta(repository.SQLUpdateStatus)).
+			WithArgs(domain.StatusDiscarded, "mould", &timeNow, int64(0), productBatch.Id).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.
+			ExpectExec(regexp.QuoteMeta(repository.SQLCreateStockMovement)).
+			WithArgs(productBatch.Id, int64(1), domain.MovementDisposal, int64(-6), nil, "mould", "", nil, sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		productBatchRepository := repository.NewMariadbProductBatchRepository(db)
+		result, err := productBatchRepository.UpdateStatus(context.TODO(), &productBatch)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &productBatch, result)
+	})
+}
+
+var expiringColumns = []string{
+	"id", "batch_number", "product_id", "product_code", "description", "due_date", "current_quantity",
+	"purchase_price", "expiry_alert", "section_id", "section_number", "warehouse_id", "warehouse_code",
+}
+
+func TestMariaDBProductBatchRepository_GetExpiring(t *testing.T) {
+
+	t.Run("get_expiring_ok: should return the batches with their location and purchase price", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		until := timeNow.AddDate(0, 0, 7)
+
+		mock.
+			ExpectQuery(regexp.QuoteMeta(repository.SQLGetExpiring)).
+			WithArgs(timeNow, until).
+			WillReturnRows(sqlmock.NewRows(expiringColumns).
+				AddRow(1, 10, 2, "PRD-000002", "Yogurt", timeNow, 5, 1.5, "", 3, 30, 4, "WH-4").
+				AddRow(2, 11, 2, "PRD-000002", "Yogurt", timeNow, 8, nil, "expiring", 3, 30, 4, "WH-4"))
+
+		productBatchRepository := repository.NewMariadbProductBatchRepository(db)
+		result, err := productBatchRepository.GetExpiring(context.TODO(), timeNow, until)
+
+		price := 1.5
+		assert.NoError(t, err)
+		assert.Len(t, result, 2)
+		assert.Equal(t, &price, result[0].PurchasePrice)
+		assert.Nil(t, result[1].PurchasePrice)
+		assert.Equal(t, "WH-4", result[1].WarehouseCode)
+		assert.Equal(t, domain.ExpiryExpiring, result[1].ExpiryAlert)
+	})
+}
+
+func TestMariaDBProductBatchRepository_GetPendingExpiryAlerts(t *testing.T) {
+
+	t.Run("get_pending_expiry_alerts_ok: should also pass now to pick up newly expired batches", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		until := timeNow.AddDate(0, 0, 7)
+
+		mock.
+			ExpectQuery(regexp.QuoteMeta(repository.SQLGetPendingExpiryAlerts)).
+			WithArgs(timeNow, until, timeNow).
+			WillReturnRows(sqlmock.NewRows(expiringColumns))
+
+		productBatchRepository := repository.NewMariadbProductBatchRepository(db)
+		result, err := productBatchRepository.GetPendingExpiryAlerts(context.TODO(), timeNow, until)
+
+		assert.NoError(t, err)
+		assert.Empty(t, result)
+	})
+}
+
+func TestMariaDBProductBatchRepository_RecordExpiryAlert(t *testing.T) {
+	alert := domain.ExpiryAlert{Level: domain.ExpiryExpired, ProductBatchId: 1, WarehouseId: 4}
+
+	t.Run("record_expiry_alert_ok: should mark the batch and publish the alert together", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.
+			ExpectExec(regexp.QuoteMeta(repository.SQLSetExpiryAlert)).
+			WithArgs(domain.ExpiryExpired, int64(1)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.
+			ExpectExec(regexp.QuoteMeta(outbox.SQLCreateOutboxEvent)).
+			WithArgs(outbox.ProductBatchExpiry, int64(1), int64(4), sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		productBatchRepository := repository.NewMariadbProductBatchRepository(db)
+
+		assert.NoError(t, productBatchRepository.RecordExpiryAlert(context.TODO(), alert))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("record_expiry_alert_fail_outbox: should rollback when the alert cannot be published", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.
+			ExpectExec(regexp.QuoteMeta(repository.SQLSetExpiryAlert)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.
+			ExpectExec(regexp.QuoteMeta(outbox.SQLCreateOutboxEvent)).
+			WillReturnError(fmt.Errorf("outbox error"))
+		mock.ExpectRollback()
+
+		productBatchRepository := repository.NewMariadbProductBatchRepository(db)
+
+		assert.Error(t, productBatchRepository.RecordExpiryAlert(context.TODO(), alert))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestMariaDBProductBatchRepository_Transfer(t *testing.T) {
+	transfer := domain.Transfer{ProductBatchId: 1, ToSectionId: 2, Quantity: 3, EmployeeId: 7, Reason: "rebalance"}
+
+	t.Run("transfer_split_ok: should split the units off into the target section and record both movements", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		source := expectedProductBatch
+		source.CurrentQuantity = 7
+		target := expectedProductBatch
+		target.Id = 2
+		target.CurrentQuantity = 3
+		target.SectionId = 2
+
+		mock.ExpectBegin()
+		mock.
+			ExpectQuery(regexp.QuoteMeta(repository.SQLGetStockForUpdate)).
+			WithArgs(int64(1)).
+			WillReturnRows(sqlmock.NewRows([]string{"current_quantity", "section_id"}).AddRow(10, 1))
+		mock.
+			ExpectExec(regexp.QuoteMeta(repository.SQLReserveSectionCapacity)).
+			WithArgs(int64(3), int64(2), int64(3)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		expectSectionCapacityChanged(mock, 2)
+		mock.
+			ExpectExec(regexp.QuoteMeta(repository.SQLReleaseSectionCapacity)).
+			WithArgs(int64(3), int64(1)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		expectSectionCapacityChanged(mock, 1)
+		mock.
+			ExpectExec(regexp.QuoteMeta(repository.SQLTakeProductBatchQuantity)).
+			WithArgs(int64(3), int64(1)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.
+			ExpectExec(regexp.QuoteMeta(repository.SQLSplitProductBatch)).
+			WithArgs(int64(3), int64(3), int64(2), int64(1)).
+			WillReturnResult(sqlmock.NewResult(2, 1))
+		mock.
+			ExpectExec(regexp.QuoteMeta(repository.SQLCreateStockMovement)).
+			WithArgs(int64(1), int64(1), domain.MovementTransferOut, int64(-3), int64(7), "rebalance", "product_batch", int64(2), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.
+			ExpectExec(regexp.QuoteMeta(repository.SQLCreateStockMovement)).
+			WithArgs(int64(2), int64(2), domain.MovementTransferIn, int64(3), int64(7), "rebalance", "product_batch", int64(1), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(2, 1))
+		mock.
+			ExpectQuery(regexp.QuoteMeta(repository.SQLGetSectionWarehouseId)).
+			WithArgs(int64(2)).
+			WillReturnRows(sqlmock.NewRows([]string{"warehouse_id"}).AddRow(4))
+		mock.
+			ExpectExec(regexp.QuoteMeta(outbox.SQLCreateOutboxEvent)).
+			WithArgs(outbox.ProductBatchTransferred, int64(2), int64(4), sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+		mock.
+			ExpectQuery(regexp.QuoteMeta(repository.SQLGetById)).
+			WithArgs(int64(1)).
+			WillReturnRows(productBatchRow(sqlmock.NewRows(productBatchColumns), source))
+		mock.
+			ExpectQuery(regexp.QuoteMeta(repository.SQLGetById)).
+			WithArgs(int64(2)).
+			WillReturnRows(productBatchRow(sqlmock.NewRows(productBatchColumns), target))
+
+		productBatchRepository := repository.NewMariadbProductBatchRepository(db)
+		result, err := productBatchRepository.Transfer(context.TODO(), transfer)
+
+		assert.NoError(t, err)
+		assert.True(t, result.Split)
+		assert.Equal(t, int64(7), result.Source.CurrentQuantity)
+		assert.Equal(t, int64(2), result.Target.Id)
+		assert.Len(t, result.Movements, 2)
+		assert.Equal(t, int64(-3), result.Movements[0].Quantity)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("transfer_whole_ok: should move the batch itself when it transfers all of it", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		moved := expectedProductBatch
+		moved.CurrentQuantity = 3
+		moved.SectionId = 2
+
+		mock.ExpectBegin()
+		mock.
+			ExpectQuery(regexp.QuoteMeta(repository.SQLGetStockForUpdate)).
+			WithArgs(int64(1)).
+			WillReturnRows(sqlmock.NewRows([]string{"current_quantity", "section_id"}).AddRow(3, 1))
+		mock.
+			ExpectExec(regexp.QuoteMeta(repository.SQLReserveSectionCapacity)).
+			WithArgs(int64(3), int64(2), int64(3)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		expectSectionCapacityChanged(mock, 2)
+		mock.
+			ExpectExec(regexp.QuoteMeta(repository.SQLReleaseSectionCapacity)).
+			WithArgs(int64(3), int64(1)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		expectSectionCapacityChanged(mock, 1)
+		mock.
+			ExpectExec(regexp.QuoteMeta(repository.SQLMoveProductBatch)).
+			WithArgs(int64(2), int64(1)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.
+			ExpectExec(regexp.QuoteMeta(repository.SQLCreateStockMovement)).
+			WithArgs(int64(1), int64(1), domain.MovementTransferOut, int64(-3), int64(7), "rebalance", "product_batch", int64(1), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.
+			ExpectExec(regexp.QuoteMeta(repository.SQLCreateStockMovement)).
+			WithArgs(int64(1), int64(2), domain.MovementTransferIn, int64(3), int64(7), "rebalance", "product_batch", int64(1), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(2, 1))
+		mock.
+			ExpectQuery(regexp.QuoteMeta(repository.SQLGetSectionWarehouseId)).
+			WillReturnRows(sqlmock.NewRows([]string{"warehouse_id"}).AddRow(4))
+		mock.
+			ExpectExec(regexp.QuoteMeta(outbox.SQLCreateOutboxEvent)).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+		mock.
+			ExpectQuery(regexp.QuoteMeta(repository.SQLGetById)).
+			WithArgs(int64(1)).
+			WillReturnRows(productBatchRow(sqlmock.NewRows(productBatchColumns), moved))
+
+		productBatchRepository := repository.NewMariadbProductBatchRepository(db)
+		result, err := productBatchRepository.Transfer(context.TODO(), transfer)
+
+		assert.NoError(t, err)
+		assert.False(t, result.Split)
+		assert.Same(t, result.Source, result.Target)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("transfer_quantity_changed: should rollback when the batch no longer holds the quantity", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.
+			ExpectQuery(regexp.QuoteMeta(repository.SQLGetStockForUpdate)).
+			WillReturnRows(sqlmock.NewRows([]string{"current_quantity", "section_id"}).AddRow(2, 1))
+		mock.ExpectRollback()
+
+		productBatchRepository := repository.NewMariadbProductBatchRepository(db)
+		_, err = productBatchRepository.Transfer(context.TODO(), transfer)
+
+		assert.ErrorIs(t, err, domain.ErrTransferQuantity)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("transfer_capacity_exceeded: should rollback when the target section has no room", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.
+			ExpectQuery(regexp.QuoteMeta(repository.SQLGetStockForUpdate)).
+			WillReturnRows(sqlmock.NewRows([]string{"current_quantity", "section_id"}).AddRow(10, 1))
+		mock.
+			ExpectExec(regexp.QuoteMeta(repository.SQLReserveSectionCapacity)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.
+			ExpectQuery(regexp.QuoteMeta(repository.SQLGetSectionFreeCapacity)).
+			WithArgs(int64(2)).
+			WillReturnRows(sqlmock.NewRows([]string{"free"}).AddRow(1))
+		mock.ExpectRollback()
+
+		productBatchRepository := repository.NewMariadbProductBatchRepository(db)
+		_, err = productBatchRepository.Transfer(context.TODO(), transfer)
+
+		assert.ErrorIs(t, err, domain.ErrCapacityExceeded)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestMariaDBProductBatchRepository_Update_Pick(t *testing.T) {
+	purchaseOrderId := int64(9)
+	employeeId := int64(7)
+
+	newPick := func() domain.ProductBatch {
+		productBatch := expectedProductBatch
+		productBatch.CurrentQuantity = 4
+		productBatch.Movement = &domain.StockMovement{
+			Type:          domain.MovementPick,
+			EmployeeId:    &employeeId,
+			ReferenceType: domain.ReferencePurchaseOrder,
+			ReferenceId:   &purchaseOrderId,
+		}
+		return productBatch
+	}
+
+	t.Run("pick_ok: should record the units taken for the purchase order", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		productBatch := newPick()
+
+		mock.ExpectBegin()
+		mock.
+			ExpectQuery(regexp.QuoteMeta(repository.SQLGetStockForUpdate)).
+			WillReturnRows(sqlmock.NewRows([]string{"current_quantity", "section_id"}).AddRow(10, 1))
+		mock.
+			ExpectExec(regexp.QuoteMeta(repository.SQLReleaseSectionCapacity)).
+			WithArgs(int64(6), int64(1)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		expectSectionCapacityChanged(mock, 1)
+		mock.
+			ExpectExec(regexp.QuoteMeta(repository.SQLUpdate)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.
+			ExpectQuery(regexp.QuoteMeta(repository.SQLCountPurchaseOrders)).
+			WithArgs(purchaseOrderId).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.
+			ExpectExec(regexp.QuoteMeta(repository.SQLCreateStockMovement)).
+			WithArgs(productBatch.Id, int64(1), domain.MovementPick, int64(-6), employeeId, "", domain.ReferencePurchaseOrder, purchaseOrderId, sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		productBatchRepository := repository.NewMariadbProductBatchRepository(db)
+		_, err = productBatchRepository.Update(context.TODO(), &productBatch)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("pick_purchase_order_not_found: should rollback", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		productBatch := newPick()
+
+		mock.ExpectBegin()
+		mock.
+			ExpectQuery(regexp.QuoteMeta(repository.SQLGetStockForUpdate)).
+			WillReturnRows(sqlmock.NewRows([]string{"current_quantity", "section_id"}).AddRow(10, 1))
+		mock.
+			ExpectExec(regexp.QuoteMeta(repository.SQLReleaseSectionCapacity)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		expectSectionCapacityChanged(mock, 1)
+		mock.
+			ExpectExec(regexp.QuoteMeta(repository.SQLUpdate)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.
+			ExpectQuery(regexp.QuoteMeta(repository.SQLCountPurchaseOrders)).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectRollback()
+
+		productBatchRepository := repository.NewMariadbProductBatchRepository(db)
+		_, err = productBatchRepository.Update(context.TODO(), &productBatch)
+
+		assert.ErrorIs(t, err, purchaseOrders.ErrIDNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestMariaDBProductBatchRepository_GetMovements(t *testing.T) {
+	t.Run("get_movements_ok: should return the ledger of the batch in order", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		columns := []string{"id", "product_batch_id", "section_id", "movement_type", "quantity", "employee_id", "reason", "reference_type", "reference_id", "created_at"}
+		mock.
+			ExpectQuery(regexp.QuoteMeta(repository.SQLGetMovements)).
+			WithArgs(int64(1)).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(1, 1, 1, domain.MovementReceipt, 10, 7, "", domain.ReferenceInboundOrder, 3, timeNow).
+				AddRow(2, 1, 1, domain.MovementAdjustment, -2, nil, "damaged", nil, nil, timeNow))
+
+		productBatchRepository := repository.NewMariadbProductBatchRepository(db)
+		movements, err := productBatchRepository.GetMovements(context.TODO(), 1)
+
+		assert.NoError(t, err)
+		assert.Len(t, movements, 2)
+		assert.Equal(t, domain.ReferenceInboundOrder, movements[0].ReferenceType)
+		assert.Equal(t, int64(3), *movements[0].ReferenceId)
+		assert.Nil(t, movements[1].EmployeeId)
+		assert.Equal(t, "", movements[1].ReferenceType)
+		assert.Equal(t, int64(-2), movements[1].Quantity)
+	})
+
+	t.Run("get_movements_fail: should return error when query fails", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta(repository.SQLGetMovements)).WillReturnError(fmt.Errorf("any error"))
+
+		productBatchRepository := repository.NewMariadbProductBatchRepository(db)
+		_, err = productBatchRepository.GetMovements(context.TODO(), 1)
+
 		assert.Error(t, err)
 	})
 }
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_batch/domain"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/libs/logger"
+)
+
+const expiryScanLabel = "product_batches.expiry_scan"
+
+// ExpiryScanner raises an alert for every active batch with stock that
+// enters the expiry window and again when it expires. Alerts are published
+// to the outbox and written to the log. Scan is run periodically by the job
+// scheduler.
+type ExpiryScanner struct {
+	repository domain.ProductBatchRepository
+	logger     logger.LogRepository
+	days       int
+}
+
+// NewExpiryScanner alerts days ahead of the due date. log may be nil.
+func NewExpiryScanner(r domain.ProductBatchRepository, log logger.LogRepository, days int) *ExpiryScanner {
+	return &ExpiryScanner{
+		repository: r,
+		logger:     log,
+		days:       days,
+	}
+}
+
+func (s *ExpiryScanner) Scan(ctx context.Context) error {
+	now := time.Now().UTC()
+
+	batches, err := s.repository.GetPendingExpiryAlerts(ctx, now, now.AddDate(0, 0, s.days))
+	if err != nil {
+		return err
+	}
+
+	for _, batch := range batches {
+		alert := domain.NewExpiryAlert(batch, now)
+
+		if err := s.repository.RecordExpiryAlert(ctx, alert); err != nil {
+			return err
+		}
+
+		if s.logger != nil {
+			s.logger.Info(ctx, "JOB", expiryScanLabel, fmt.Sprintf(
+				"product batch %d (%d units of product %d in section %d) %s on %s",
+				alert.ProductBatchId,
+				alert.CurrentQuantity,
+				alert.ProductId,
+				alert.SectionId,
+				expiryVerb(alert.Level),
+				alert.DueDate.Format("2006-01-02"),
+			), 0)
+		}
+	}
+
+	return nil
+}
+
+func expiryVerb(level string) string {
+	if level == domain.ExpiryExpired {
+		return "expired"
+	}
+	return "expires"
+}
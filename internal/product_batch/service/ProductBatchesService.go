@@ -2,37 +2,59 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"time"
 
+	employees "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/employees/domain"
 	product "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product/domain"
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_batch/domain"
 	section "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/section/domain"
 )
 
 type service struct {
-	repository        domain.ProductBatchRepository
-	repositoryProduct product.ProductRepository
-	repositorySection section.SectionRepository
+	repository         domain.ProductBatchRepository
+	repositoryProduct  product.ProductRepository
+	repositorySection  section.SectionRepository
+	repositoryEmployee employees.EmployeeRepository
 }
 
-func NewProductBatchService(r domain.ProductBatchRepository, rp product.ProductRepository, rs section.SectionRepository) domain.ProductBatchService {
+func NewProductBatchService(r domain.ProductBatchRepository, rp product.ProductRepository, rs section.SectionRepository, re employees.EmployeeRepository) domain.ProductBatchService {
 	return &service{
-		repository:        r,
-		repositoryProduct: rp,
-		repositorySection: rs,
+		repository:         r,
+		repositoryProduct:  rp,
+		repositorySection:  rs,
+		repositoryEmployee: re,
 	}
 }
 
 func (s *service) Create(ctx context.Context, productBatch *domain.ProductBatch) (*domain.ProductBatch, error) {
-	_, err := s.repositoryProduct.GetById(ctx, productBatch.ProductId)
+	if productBatch.CurrentQuantity < 0 || productBatch.CurrentQuantity > productBatch.InitialQuantity {
+		return nil, domain.ErrInvalidQuantity
+	}
+
+	batchProduct, err := s.repositoryProduct.GetById(ctx, productBatch.ProductId)
 	if err != nil {
 		return nil, err
 	}
 
-	_, err = s.repositorySection.GetById(ctx, productBatch.SectionId)
+	batchSection, err := s.repositorySection.GetById(ctx, productBatch.SectionId)
 	if err != nil {
 		return nil, err
 	}
 
+	if batchSection.ArchivedAt != nil {
+		return nil, section.ErrSectionArchived
+	}
+
+	violations := domain.CheckCompatibility(batchProduct, productBatch.MinumumTemperature, batchSection)
+	if err := applyOverride(productBatch, violations); err != nil {
+		return nil, err
+	}
+
+	setShelfLife(productBatch, batchProduct, batchSection.CurrentTemperature)
+	productBatch.Status = domain.StatusActive
+
 	newProductBatch, err := s.repository.Create(ctx, productBatch)
 	if err != nil {
 		return nil, err
@@ -40,3 +62,394 @@ func (s *service) Create(ctx context.Context, productBatch *domain.ProductBatch)
 
 	return newProductBatch, nil
 }
+
+func (s *service) GetById(ctx context.Context, id int64) (*domain.ProductBatch, error) {
+	productBatch, err := s.repository.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.withShelfLife(ctx, productBatch); err != nil {
+		return nil, err
+	}
+
+	return productBatch, nil
+}
+
+func (s *service) GetAll(ctx context.Context, filter domain.ProductBatchFilter) ([]domain.ProductBatch, error) {
+	if filter.DueDateFrom != nil && filter.DueDateTo != nil && filter.DueDateFrom.After(*filter.DueDateTo) {
+		return nil, domain.ErrInvalidDateRange
+	}
+
+	productBatches, err := s.repository.GetAll(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	products := map[int64]*product.Product{}
+	temperatures := map[int64]float64{}
+
+	for i := range productBatches {
+		productBatch := &productBatches[i]
+
+		batchProduct, ok := products[productBatch.ProductId]
+		if !ok {
+			batchProduct, err = s.repositoryProduct.GetById(ctx, productBatch.ProductId)
+			if err != nil {
+				return nil, err
+			}
+			products[productBatch.ProductId] = batchProduct
+		}
+
+		temperature, ok := temperatures[productBatch.SectionId]
+		if !ok {
+			batchSection, err := s.repositorySection.GetById(ctx, productBatch.SectionId)
+			if err != nil {
+				return nil, err
+			}
+			temperature = batchSection.CurrentTemperature
+			temperatures[productBatch.SectionId] = temperature
+		}
+
+		setShelfLife(productBatch, batchProduct, temperature)
+	}
+
+	return productBatches, nil
+}
+
+func (s *service) Update(ctx context.Context, id int64, patch domain.ProductBatchPatch) (*domain.ProductBatch, error) {
+	productBatch, err := s.repository.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if productBatch.Status == domain.StatusDiscarded {
+		return nil, domain.ErrProductBatchDiscarded
+	}
+
+	if patch.CurrentQuantity != nil {
+		if *patch.CurrentQuantity < 0 || *patch.CurrentQuantity > productBatch.InitialQuantity {
+			return nil, domain.ErrInvalidQuantity
+		}
+	}
+
+	movement := &domain.StockMovement{
+		Type:       domain.MovementAdjustment,
+		EmployeeId: patch.EmployeeId,
+		Reason:     strings.TrimSpace(patch.Reason),
+	}
+
+	if patch.PurchaseOrderId != nil {
+		if patch.CurrentQuantity == nil || *patch.CurrentQuantity >= productBatch.CurrentQuantity || productBatch.Status != domain.StatusActive {
+			return nil, domain.ErrInvalidPick
+		}
+		movement.Type = domain.MovementPick
+		movement.ReferenceType = domain.ReferencePurchaseOrder
+		movement.ReferenceId = patch.PurchaseOrderId
+	}
+
+	if patch.EmployeeId != nil {
+		if _, err := s.repositoryEmployee.GetById(ctx, *patch.EmployeeId); err != nil {
+			return nil, err
+		}
+	}
+
+	if patch.CurrentQuantity != nil {
+		productBatch.CurrentQuantity = *patch.CurrentQuantity
+	}
+	productBatch.Movement = movement
+
+	if patch.CurrentTemperature != nil {
+		productBatch.CurrentTemperature = *patch.CurrentTemperature
+	}
+
+	productBatch, err = s.repository.Update(ctx, productBatch)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.withShelfLife(ctx, productBatch); err != nil {
+		return nil, err
+	}
+
+	return productBatch, nil
+}
+
+// Delete is for batches written by mistake. It refuses batches that inbound
+// orders point to, since deleting one would silently erase the orders with
+// it, and batches with stock movements besides their receipt, which have to
+// be discarded instead.
+func (s *service) Delete(ctx context.Context, id int64) error {
+	if _, err := s.repository.GetById(ctx, id); err != nil {
+		return err
+	}
+
+	count, err := s.repository.CountInboundOrders(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if count > 0 {
+		return domain.ErrProductBatchInUse
+	}
+
+	return s.repository.Delete(ctx, id)
+}
+
+// UpdateStatus quarantines, releases or discards a batch. Discarded stock
+// leaves the warehouse, so its current quantity drops to 0 and the ledger
+// records its disposal.
+func (s *service) UpdateStatus(ctx context.Context, id int64, status string, reason string) (*domain.ProductBatch, error) {
+	if !domain.IsStatus(status) {
+		return nil, domain.ErrInvalidStatus
+	}
+
+	productBatch, err := s.repository.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !domain.CanChangeStatus(productBatch.Status, status) {
+		return nil, fmt.Errorf("%w: %s to %s", domain.ErrStatusTransition, productBatch.Status, status)
+	}
+
+	now := time.Now().UTC()
+	productBatch.Status = status
+	productBatch.StatusReason = strings.TrimSpace(reason)
+	productBatch.StatusChangedAt = &now
+	if status == domain.StatusDiscarded {
+		productBatch.CurrentQuantity = 0
+		productBatch.Movement = &domain.StockMovement{Type: domain.MovementDisposal, Reason: productBatch.StatusReason}
+	}
+
+	productBatch, err = s.repository.UpdateStatus(ctx, productBatch)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.withShelfLife(ctx, productBatch); err != nil {
+		return nil, err
+	}
+
+	return productBatch, nil
+}
+
+// GetExpiringReport lists the active stock due within the next days,
+// including stock already past its due date, by warehouse and section.
+func (s *service) GetExpiringReport(ctx context.Context, days int) (*domain.ExpiringReport, error) {
+	if days < 1 || days > domain.MaxExpiringDays {
+		return nil, domain.ErrInvalidDays
+	}
+
+	now := time.Now().UTC()
+	until := now.AddDate(0, 0, days)
+
+	batches, err := s.repository.GetExpiring(ctx, now, until)
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.NewExpiringReport(days, now, until, batches), nil
+}
+
+// SuggestSection ranks the sections, of the requested warehouse or of all,
+// that a new batch could be put away in.
+func (s *service) SuggestSection(ctx context.Context, request domain.PutAwayRequest) (*domain.PutAwaySuggestion, error) {
+	if request.Quantity <= 0 {
+		return nil, domain.ErrInvalidQuantity
+	}
+
+	batchProduct, err := s.repositoryProduct.GetById(ctx, request.ProductId)
+	if err != nil {
+		return nil, err
+	}
+
+	minimumTemperature := batchProduct.RecommendedFreezingTemperature
+	if request.MinimumTemperature != nil {
+		minimumTemperature = *request.MinimumTemperature
+	}
+
+	sections, err := s.repositorySection.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	batches, err := s.repository.GetAll(ctx, domain.ProductBatchFilter{
+		ProductId:   request.ProductId,
+		WarehouseId: request.WarehouseId,
+		Status:      domain.StatusActive,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stored := map[int64]domain.StoredProduct{}
+	for _, batch := range batches {
+		if batch.CurrentQuantity <= 0 {
+			continue
+		}
+		product := stored[batch.SectionId]
+		product.Batches++
+		product.Quantity += batch.CurrentQuantity
+		stored[batch.SectionId] = product
+	}
+
+	suggestion := &domain.PutAwaySuggestion{
+		ProductId:          request.ProductId,
+		Quantity:           request.Quantity,
+		MinimumTemperature: minimumTemperature,
+		Candidates:         []domain.SectionCandidate{},
+	}
+
+	for _, candidate := range sections {
+		if request.WarehouseId != 0 && candidate.WarehouseId != request.WarehouseId {
+			continue
+		}
+		suggestion.Candidates = append(suggestion.Candidates, domain.RankSection(
+			batchProduct, request.Quantity, minimumTemperature, candidate, stored[candidate.Id]))
+	}
+
+	domain.SortCandidates(suggestion.Candidates)
+
+	return suggestion, nil
+}
+
+// Transfer moves all or part of a batch to another section, which must be
+// compatible with it unless the transfer carries an override.
+func (s *service) Transfer(ctx context.Context, transfer domain.Transfer) (*domain.TransferResult, error) {
+	transfer.Reason = strings.TrimSpace(transfer.Reason)
+	if transfer.Reason == "" {
+		return nil, domain.ErrTransferReason
+	}
+
+	productBatch, err := s.repository.GetById(ctx, transfer.ProductBatchId)
+	if err != nil {
+		return nil, err
+	}
+
+	if productBatch.Status == domain.StatusDiscarded {
+		return nil, domain.ErrProductBatchDiscarded
+	}
+
+	if productBatch.SectionId == transfer.ToSectionId {
+		return nil, domain.ErrSameSection
+	}
+
+	if transfer.Quantity < 0 || transfer.Quantity > productBatch.CurrentQuantity || productBatch.CurrentQuantity == 0 {
+		return nil, domain.ErrTransferQuantity
+	}
+
+	if _, err := s.repositoryEmployee.GetById(ctx, transfer.EmployeeId); err != nil {
+		return nil, err
+	}
+
+	batchProduct, err := s.repositoryProduct.GetById(ctx, productBatch.ProductId)
+	if err != nil {
+		return nil, err
+	}
+
+	targetSection, err := s.repositorySection.GetById(ctx, transfer.ToSectionId)
+	if err != nil {
+		return nil, err
+	}
+
+	if targetSection.ArchivedAt != nil {
+		return nil, section.ErrSectionArchived
+	}
+
+	violations := domain.CheckCompatibility(batchProduct, productBatch.MinumumTemperature, targetSection)
+	productBatch.Override = transfer.Override
+	if err := applyOverride(productBatch, violations); err != nil {
+		return nil, err
+	}
+	transfer.Override = productBatch.Override
+
+	result, err := s.repository.Transfer(ctx, transfer)
+	if err != nil {
+		return nil, err
+	}
+
+	setShelfLife(result.Target, batchProduct, targetSection.CurrentTemperature)
+	if result.Split {
+		if err := s.withShelfLife(ctx, result.Source); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// GetMovements returns the stock ledger of the batch, checked against its
+// current quantity.
+func (s *service) GetMovements(ctx context.Context, id int64) (*domain.StockLedger, error) {
+	productBatch, err := s.repository.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	movements, err := s.repository.GetMovements(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	ledger := domain.NewStockLedger(*productBatch, movements)
+
+	return &ledger, nil
+}
+
+func (s *service) withShelfLife(ctx context.Context, productBatch *domain.ProductBatch) error {
+	batchProduct, err := s.repositoryProduct.GetById(ctx, productBatch.ProductId)
+	if err != nil {
+		return err
+	}
+
+	batchSection, err := s.repositorySection.GetById(ctx, productBatch.SectionId)
+	if err != nil {
+		return err
+	}
+
+	setShelfLife(productBatch, batchProduct, batchSection.CurrentTemperature)
+
+	return nil
+}
+
+// applyOverride rejects an incompatible batch unless it carries an override,
+// which then records the violations it bypasses. An override of a
+// compatible batch is dropped: there is nothing to audit.
+func applyOverride(productBatch *domain.ProductBatch, violations []string) error {
+	if len(violations) == 0 {
+		productBatch.Override = nil
+		return nil
+	}
+
+	override := productBatch.Override
+	if override == nil {
+		return fmt.Errorf("%w: %s", domain.ErrIncompatibleSection, strings.Join(violations, "; "))
+	}
+
+	override.By = strings.TrimSpace(override.By)
+	override.Reason = strings.TrimSpace(override.Reason)
+	if override.By == "" || override.Reason == "" {
+		return domain.ErrInvalidOverride
+	}
+
+	override.Violations = violations
+
+	return nil
+}
+
+// setShelfLife computes the expected expiry of the batch and flags a due
+// date that disagrees with it. Products without an expiration rate get none.
+func setShelfLife(productBatch *domain.ProductBatch, batchProduct *product.Product, storageTemperature float64) {
+	productBatch.ShelfLife = nil
+	if batchProduct == nil {
+		return
+	}
+
+	shelfLife, ok := domain.ComputeShelfLife(*batchProduct, storageTemperature, productBatch.ManufacturedAt())
+	if !ok {
+		return
+	}
+	shelfLife.CheckDueDate(productBatch.DueDate)
+	productBatch.ShelfLife = &shelfLife
+}
@@ -7,6 +7,10 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	employees "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/employees/domain"
+	employeeMocks "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/employees/domain/mocks"
+	product "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product/domain"
 	productMocks "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product/domain/mocks"
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_batch/domain"
 	productBatch "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_batch/domain/mocks"
@@ -39,7 +43,7 @@ func TestProductBatchService_Create(t *testing.T) {
 	mockRepositoryProduct := productMocks.NewProductRepository(t)
 	mockRepositorySection := sectionMocks.NewSectionRepository(t)
 
-	service := service.NewProductBatchService(mockRepositoryProductBatch, mockRepositoryProduct, mockRepositorySection)
+	service := service.NewProductBatchService(mockRepositoryProductBatch, mockRepositoryProduct, mockRepositorySection, employeeMocks.NewEmployeeRepository(t))
 
 	t.Run("create_ok: when it contains the mandatory fields, should create a product batch", func(t *testing.T) {
 		mockRepositorySection.
@@ -112,4 +116,765 @@ func TestProductBatchService_Create(t *testing.T) {
 		assert.Equal(t, nil, nil)
 	})
 
+	t.Run("create_invalid_quantity: should reject a negative or over-initial current quantity without touching the section", func(t *testing.T) {
+		for _, quantity := range []int64{-1, expectedProductBatch.InitialQuantity + 1} {
+			invalid := expectedProductBatch
+			invalid.CurrentQuantity = quantity
+
+			_, err := service.Create(context.TODO(), &invalid)
+
+			assert.ErrorIs(t, err, domain.ErrInvalidQuantity)
+		}
+	})
+
+	t.Run("create_shelf_life: should compute the expected expiry from the product rates and the section temperature", func(t *testing.T) {
+		manufacturing := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+		batch := domain.ProductBatch{
+			ManufacturingDate: manufacturing,
+			ManufacturingHour: 6,
+			DueDate:           time.Date(2022, 1, 21, 0, 0, 0, 0, time.UTC),
+			ProductId:         1,
+			SectionId:         1,
+		}
+
+		mockRepositoryProduct.
+			On("GetById", context.TODO(), int64(1)).
+			Return(&product.Product{ExpirationRate: 2, FreezingRate: 1, RecommendedFreezingTemperature: -18}, nil).
+			Once()
+
+		// 2 degrees above the recommendation: 60 / (2 * 1.2) = 25 days.
+		mockRepositorySection.
+			On("GetById", context.TODO(), int64(1)).
+			Return(section.SectionModel{CurrentTemperature: -16}, nil).
+			Once()
+
+		mockRepositoryProductBatch.
+			On("Create", context.TODO(), &batch).
+			Return(&batch, nil).
+			Once()
+
+		result, err := service.Create(context.TODO(), &batch)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 25.0, result.ShelfLife.ShelfLifeDays)
+		assert.Equal(t, -16.0, result.ShelfLife.StorageTemperature)
+		assert.Equal(t, time.Date(2022, 1, 26, 6, 0, 0, 0, time.UTC), result.ShelfLife.ExpectedExpiry)
+		assert.Contains(t, result.ShelfLife.Warning, "due date 2022-01-21 differs from the expected expiry 2022-01-26")
+	})
+
+	t.Run("create_shelf_life_within_tolerance: should not warn when the due date is close to the expected expiry", func(t *testing.T) {
+		batch := domain.ProductBatch{
+			ManufacturingDate: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+			DueDate:           time.Date(2022, 1, 31, 0, 0, 0, 0, time.UTC),
+			ProductId:         1,
+			SectionId:         1,
+		}
+
+		// Colder than recommended does not extend the 30 days of rate 2.
+		mockRepositoryProduct.
+			On("GetById", context.TODO(), int64(1)).
+			Return(&product.Product{ExpirationRate: 2, FreezingRate: 1, RecommendedFreezingTemperature: -18}, nil).
+			Once()
+
+		mockRepositorySection.
+			On("GetById", context.TODO(), int64(1)).
+			Return(section.SectionModel{CurrentTemperature: -25}, nil).
+			Once()
+
+		mockRepositoryProductBatch.
+			On("Create", context.TODO(), &batch).
+			Return(&batch, nil).
+			Once()
+
+		result, err := service.Create(context.TODO(), &batch)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 30.0, result.ShelfLife.ShelfLifeDays)
+		assert.Empty(t, result.ShelfLife.Warning)
+	})
+}
+
+func TestProductBatchService_GetById(t *testing.T) {
+	mockRepositoryProductBatch := productBatch.NewProductBatchRepository(t)
+	mockRepositoryProduct := productMocks.NewProductRepository(t)
+	mockRepositorySection := sectionMocks.NewSectionRepository(t)
+
+	service := service.NewProductBatchService(mockRepositoryProductBatch, mockRepositoryProduct, mockRepositorySection, employeeMocks.NewEmployeeRepository(t))
+
+	t.Run("get_by_id_ok: should return the batch with its shelf life", func(t *testing.T) {
+		stored := expectedProductBatch
+
+		mockRepositoryProductBatch.On("GetById", context.TODO(), int64(1)).Return(&stored, nil).Once()
+		mockRepositoryProduct.
+			On("GetById", context.TODO(), int64(1)).
+			Return(&product.Product{ExpirationRate: 1}, nil).
+			Once()
+		mockRepositorySection.On("GetById", context.TODO(), int64(1)).Return(section.SectionModel{}, nil).Once()
+
+		result, err := service.GetById(context.TODO(), 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 60.0, result.ShelfLife.ShelfLifeDays)
+	})
+
+	t.Run("get_by_id_not_found: should return ErrProductBatchNotFound", func(t *testing.T) {
+		mockRepositoryProductBatch.
+			On("GetById", context.TODO(), int64(2)).
+			Return(nil, domain.ErrProductBatchNotFound).
+			Once()
+
+		_, err := service.GetById(context.TODO(), 2)
+
+		assert.ErrorIs(t, err, domain.ErrProductBatchNotFound)
+	})
+}
+
+func TestProductBatchService_GetAll(t *testing.T) {
+	mockRepositoryProductBatch := productBatch.NewProductBatchRepository(t)
+	mockRepositoryProduct := productMocks.NewProductRepository(t)
+	mockRepositorySection := sectionMocks.NewSectionRepository(t)
+
+	service := service.NewProductBatchService(mockRepositoryProductBatch, mockRepositoryProduct, mockRepositorySection, employeeMocks.NewEmployeeRepository(t))
+
+	t.Run("get_all_ok: should look each product and section up once", func(t *testing.T) {
+		filter := domain.ProductBatchFilter{SectionId: 1}
+		batches := []domain.ProductBatch{expectedProductBatch, expectedProductBatch}
+
+		mockRepositoryProductBatch.On("GetAll", context.TODO(), filter).Return(batches, nil).Once()
+		mockRepositoryProduct.
+			On("GetById", context.TODO(), int64(1)).
+			Return(&product.Product{ExpirationRate: 2}, nil).
+			Once()
+		mockRepositorySection.On("GetById", context.TODO(), int64(1)).Return(section.SectionModel{}, nil).Once()
+
+		result, err := service.GetAll(context.TODO(), filter)
+
+		assert.NoError(t, err)
+		assert.Len(t, result, 2)
+		assert.Equal(t, 30.0, result[1].ShelfLife.ShelfLifeDays)
+	})
+
+	t.Run("get_all_invalid_date_range: should return ErrInvalidDateRange", func(t *testing.T) {
+		from := timeNow
+		to := timeNow.Add(-time.Hour)
+
+		_, err := service.GetAll(context.TODO(), domain.ProductBatchFilter{DueDateFrom: &from, DueDateTo: &to})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidDateRange)
+	})
+}
+
+func TestProductBatchService_Update(t *testing.T) {
+	mockRepositoryProductBatch := productBatch.NewProductBatchRepository(t)
+	mockRepositoryProduct := productMocks.NewProductRepository(t)
+	mockRepositorySection := sectionMocks.NewSectionRepository(t)
+
+	service := service.NewProductBatchService(mockRepositoryProductBatch, mockRepositoryProduct, mockRepositorySection, employeeMocks.NewEmployeeRepository(t))
+
+	t.Run("update_ok: should update only the given fields", func(t *testing.T) {
+		stored := expectedProductBatch
+		stored.InitialQuantity = 10
+		quantity := int64(4)
+
+		mockRepositoryProductBatch.On("GetById", context.TODO(), int64(1)).Return(&stored, nil).Once()
+		mockRepositoryProductBatch.
+			On("Update", context.TODO(), &stored).
+			Return(&stored, nil).
+			Once()
+		mockRepositoryProduct.On("GetById", context.TODO(), int64(1)).Return(nil, nil).Once()
+		mockRepositorySection.On("GetById", context.TODO(), int64(1)).Return(section.SectionModel{}, nil).Once()
+
+		result, err := service.Update(context.TODO(), 1, domain.ProductBatchPatch{CurrentQuantity: &quantity})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(4), result.CurrentQuantity)
+		assert.Equal(t, expectedProductBatch.CurrentTemperature, result.CurrentTemperature)
+		assert.Equal(t, domain.MovementAdjustment, result.Movement.Type)
+	})
+
+	t.Run("update_invalid_quantity: should not exceed the initial quantity", func(t *testing.T) {
+		stored := expectedProductBatch
+		stored.InitialQuantity = 10
+		quantity := int64(11)
+
+		mockRepositoryProductBatch.On("GetById", context.TODO(), int64(1)).Return(&stored, nil).Once()
+
+		_, err := service.Update(context.TODO(), 1, domain.ProductBatchPatch{CurrentQuantity: &quantity})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidQuantity)
+	})
+}
+
+func TestProductBatchService_Delete(t *testing.T) {
+	mockRepositoryProductBatch := productBatch.NewProductBatchRepository(t)
+
+	service := service.NewProductBatchService(mockRepositoryProductBatch, productMocks.NewProductRepository(t), sectionMocks.NewSectionRepository(t), employeeMocks.NewEmployeeRepository(t))
+
+	t.Run("delete_ok: should delete a batch no inbound order references", func(t *testing.T) {
+		mockRepositoryProductBatch.On("GetById", context.TODO(), int64(1)).Return(&expectedProductBatch, nil).Once()
+		mockRepositoryProductBatch.On("CountInboundOrders", context.TODO(), int64(1)).Return(int64(0), nil).Once()
+		mockRepositoryProductBatch.On("Delete", context.TODO(), int64(1)).Return(nil).Once()
+
+		assert.NoError(t, service.Delete(context.TODO(), 1))
+	})
+
+	t.Run("delete_in_use: should refuse a batch inbound orders reference", func(t *testing.T) {
+		mockRepositoryProductBatch.On("GetById", context.TODO(), int64(1)).Return(&expectedProductBatch, nil).Once()
+		mockRepositoryProductBatch.On("CountInboundOrders", context.TODO(), int64(1)).Return(int64(3), nil).Once()
+
+		assert.ErrorIs(t, service.Delete(context.TODO(), 1), domain.ErrProductBatchInUse)
+	})
+
+	t.Run("delete_not_found: should return ErrProductBatchNotFound", func(t *testing.T) {
+		mockRepositoryProductBatch.On("GetById", context.TODO(), int64(2)).Return(nil, domain.ErrProductBatchNotFound).Once()
+
+		assert.ErrorIs(t, service.Delete(context.TODO(), 2), domain.ErrProductBatchNotFound)
+	})
+}
+
+func TestProductBatchService_UpdateStatus(t *testing.T) {
+	mockRepositoryProductBatch := productBatch.NewProductBatchRepository(t)
+	mockRepositoryProduct := productMocks.NewProductRepository(t)
+	mockRepositorySection := sectionMocks.NewSectionRepository(t)
+
+	service := service.NewProductBatchService(mockRepositoryProductBatch, mockRepositoryProduct, mockRepositorySection, employeeMocks.NewEmployeeRepository(t))
+
+	t.Run("update_status_discard: should discard the stock and keep the reason", func(t *testing.T) {
+		stored := expectedProductBatch
+		stored.Status = domain.StatusQuarantined
+		stored.CurrentQuantity = 7
+
+		mockRepositoryProductBatch.On("GetById", context.TODO(), int64(1)).Return(&stored, nil).Once()
+		mockRepositoryProductBatch.
+			On("UpdateStatus", context.TODO(), &stored).
+			Return(&stored, nil).
+			Once()
+		mockRepositoryProduct.On("GetById", context.TODO(), int64(1)).Return(nil, nil).Once()
+		mockRepositorySection.On("GetById", context.TODO(), int64(1)).Return(section.SectionModel{}, nil).Once()
+
+		result, err := service.UpdateStatus(context.TODO(), 1, domain.StatusDiscarded, " mould ")
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.StatusDiscarded, result.Status)
+		assert.Equal(t, "mould", result.StatusReason)
+		assert.Equal(t, int64(0), result.CurrentQuantity)
+		assert.NotNil(t, result.StatusChangedAt)
+		assert.Equal(t, &domain.StockMovement{Type: domain.MovementDisposal, Reason: "mould"}, result.Movement)
+	})
+
+	t.Run("update_status_transition: should not change a discarded batch", func(t *testing.T) {
+		stored := expectedProductBatch
+		stored.Status = domain.StatusDiscarded
+
+		mockRepositoryProductBatch.On("GetById", context.TODO(), int64(1)).Return(&stored, nil).Once()
+
+		_, err := service.UpdateStatus(context.TODO(), 1, domain.StatusActive, "")
+
+		assert.ErrorIs(t, err, domain.ErrStatusTransition)
+		assert.Contains(t, err.Error(), "discarded to active")
+	})
+
+	t.Run("update_status_invalid: should reject unknown statuses", func(t *testing.T) {
+		_, err := service.UpdateStatus(context.TODO(), 1, "lost", "")
+
+		assert.ErrorIs(t, err, domain.ErrInvalidStatus)
+	})
+
+	t.Run("update_discarded: should not patch a discarded batch", func(t *testing.T) {
+		stored := expectedProductBatch
+		stored.Status = domain.StatusDiscarded
+		temperature := -5.0
+
+		mockRepositoryProductBatch.On("GetById", context.TODO(), int64(1)).Return(&stored, nil).Once()
+
+		_, err := service.Update(context.TODO(), 1, domain.ProductBatchPatch{CurrentTemperature: &temperature})
+
+		assert.ErrorIs(t, err, domain.ErrProductBatchDiscarded)
+	})
+}
+
+func TestProductBatchService_GetExpiringReport(t *testing.T) {
+	mockRepositoryProductBatch := productBatch.NewProductBatchRepository(t)
+
+	service := service.NewProductBatchService(mockRepositoryProductBatch, productMocks.NewProductRepository(t), sectionMocks.NewSectionRepository(t), employeeMocks.NewEmployeeRepository(t))
+
+	t.Run("get_expiring_report_ok: should group batches by warehouse and section with totals", func(t *testing.T) {
+		price := 2.5
+		due := time.Now().UTC().AddDate(0, 0, 3)
+		batches := []domain.ExpiringBatch{
+			{Id: 1, DueDate: due, CurrentQuantity: 4, PurchasePrice: &price, SectionId: 1, SectionNumber: 10, WarehouseId: 1, WarehouseCode: "A"},
+			{Id: 2, DueDate: due, CurrentQuantity: 6, SectionId: 1, SectionNumber: 10, WarehouseId: 1, WarehouseCode: "A"},
+			{Id: 3, DueDate: due, CurrentQuantity: 2, PurchasePrice: &price, SectionId: 2, SectionNumber: 20, WarehouseId: 1, WarehouseCode: "A"},
+			{Id: 4, DueDate: due, CurrentQuantity: 1, PurchasePrice: &price, SectionId: 5, SectionNumber: 50, WarehouseId: 2, WarehouseCode: "B"},
+		}
+
+		mockRepositoryProductBatch.On("GetExpiring", context.TODO(), mock.Anything, mock.Anything).Return(batches, nil).Once()
+
+		report, err := service.GetExpiringReport(context.TODO(), 7)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(13), report.Quantity)
+		assert.Equal(t, 17.5, report.Value)
+		assert.Len(t, report.Warehouses, 2)
+		assert.Len(t, report.Warehouses[0].Sections, 2)
+		assert.Equal(t, int64(10), report.Warehouses[0].Sections[0].Quantity)
+		assert.Equal(t, 10.0, report.Warehouses[0].Sections[0].Value)
+		assert.Equal(t, 3, report.Warehouses[0].Sections[0].Batches[0].DaysLeft)
+		assert.Equal(t, 2.5, report.Warehouses[1].Value)
+	})
+
+	t.Run("get_expiring_report_invalid_days: should return ErrInvalidDays", func(t *testing.T) {
+		_, err := service.GetExpiringReport(context.TODO(), 0)
+
+		assert.ErrorIs(t, err, domain.ErrInvalidDays)
+	})
+}
+
+func TestExpiryScanner_Scan(t *testing.T) {
+
+	t.Run("scan_ok: should record an alert at the level of each pending batch", func(t *testing.T) {
+		mockRepositoryProductBatch := productBatch.NewProductBatchRepository(t)
+		scanner := service.NewExpiryScanner(mockRepositoryProductBatch, nil, 7)
+
+		now := time.Now().UTC()
+		batches := []domain.ExpiringBatch{
+			{Id: 1, DueDate: now.AddDate(0, 0, -1), CurrentQuantity: 3, WarehouseId: 1},
+			{Id: 2, DueDate: now.AddDate(0, 0, 2), CurrentQuantity: 5, WarehouseId: 1},
+		}
+
+		mockRepositoryProductBatch.On("GetPendingExpiryAlerts", context.TODO(), mock.Anything, mock.Anything).Return(batches, nil).Once()
+		mockRepositoryProductBatch.
+			On("RecordExpiryAlert", context.TODO(), mock.MatchedBy(func(alert domain.ExpiryAlert) bool {
+				return alert.ProductBatchId == 1 && alert.Level == domain.ExpiryExpired && alert.DaysLeft == -1
+			})).
+			Return(nil).
+			Once()
+		mockRepositoryProductBatch.
+			On("RecordExpiryAlert", context.TODO(), mock.MatchedBy(func(alert domain.ExpiryAlert) bool {
+				return alert.ProductBatchId == 2 && alert.Level == domain.ExpiryExpiring && alert.DaysLeft == 2
+			})).
+			Return(nil).
+			Once()
+
+		assert.NoError(t, scanner.Scan(context.TODO()))
+	})
+
+	t.Run("scan_error: should stop when an alert cannot be recorded", func(t *testing.T) {
+		mockRepositoryProductBatch := productBatch.NewProductBatchRepository(t)
+		scanner := service.NewExpiryScanner(mockRepositoryProductBatch, nil, 7)
+
+		batches := []domain.ExpiringBatch{{Id: 1}, {Id: 2}}
+
+		mockRepositoryProductBatch.On("GetPendingExpiryAlerts", context.TODO(), mock.Anything, mock.Anything).Return(batches, nil).Once()
+		mockRepositoryProductBatch.On("RecordExpiryAlert", context.TODO(), mock.Anything).Return(fmt.Errorf("any error")).Once()
+
+		assert.Error(t, scanner.Scan(context.TODO()))
+	})
+}
+
+func TestProductBatchService_Create_Compatibility(t *testing.T) {
+	frozenFish := &product.Product{Id: 1, ProductTypeId: 1, ExpirationRate: 1}
+	ambientSection := section.SectionModel{Id: 2, ProductTypeId: 3, MinimumTemperature: 10}
+
+	newBatch := func() *domain.ProductBatch {
+		return &domain.ProductBatch{ProductId: 1, SectionId: 2, MinumumTemperature: -18}
+	}
+
+	setUp := func(t *testing.T) (domain.ProductBatchService, *productBatch.ProductBatchRepository) {
+		mockRepositoryProductBatch := productBatch.NewProductBatchRepository(t)
+		mockRepositoryProduct := productMocks.NewProductRepository(t)
+		mockRepositorySection := sectionMocks.NewSectionRepository(t)
+
+		mockRepositoryProduct.On("GetById", context.TODO(), int64(1)).Return(frozenFish, nil).Once()
+		mockRepositorySection.On("GetById", context.TODO(), int64(2)).Return(ambientSection, nil).Once()
+
+		return service.NewProductBatchService(mockRepositoryProductBatch, mockRepositoryProduct, mockRepositorySection, employeeMocks.NewEmployeeRepository(t)), mockRepositoryProductBatch
+	}
+
+	t.Run("create_incompatible: should reject a batch of another product type and temperature", func(t *testing.T) {
+		service, _ := setUp(t)
+
+		_, err := service.Create(context.TODO(), newBatch())
+
+		assert.ErrorIs(t, err, domain.ErrIncompatibleSection)
+		assert.Contains(t, err.Error(), "product type 1 does not match section product type 3")
+		assert.Contains(t, err.Error(), "section minimum temperature 10.00 is above the batch minimum temperature -18.00")
+	})
+
+	t.Run("create_override: should store the batch and pass the bypassed rules on for the audit log", func(t *testing.T) {
+		service, mockRepositoryProductBatch := setUp(t)
+
+		batch := newBatch()
+		batch.Override = &domain.CompatibilityOverride{By: " maria ", Reason: "freezer under repair"}
+
+		mockRepositoryProductBatch.
+			On("Create", context.TODO(), batch).
+			Return(func(_ context.Context, b *domain.ProductBatch) *domain.ProductBatch { return b }, nil).
+			Once()
+
+		result, err := service.Create(context.TODO(), batch)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "maria", result.Override.By)
+		assert.Len(t, result.Override.Violations, 2)
+	})
+
+	t.Run("create_override_without_reason: should return ErrInvalidOverride", func(t *testing.T) {
+		service, _ := setUp(t)
+
+		batch := newBatch()
+		batch.Override = &domain.CompatibilityOverride{By: "maria"}
+
+		_, err := service.Create(context.TODO(), batch)
+
+		assert.ErrorIs(t, err, domain.ErrInvalidOverride)
+	})
+
+	t.Run("create_archived_section: should reject a batch for an archived section", func(t *testing.T) {
+		archivedAt := timeNow
+		archivedSection := ambientSection
+		archivedSection.ArchivedAt = &archivedAt
+
+		mockRepositoryProduct := productMocks.NewProductRepository(t)
+		mockRepositorySection := sectionMocks.NewSectionRepository(t)
+		mockRepositoryProduct.On("GetById", context.TODO(), int64(1)).Return(frozenFish, nil).Once()
+		mockRepositorySection.On("GetById", context.TODO(), int64(2)).Return(archivedSection, nil).Once()
+
+		service := service.NewProductBatchService(productBatch.NewProductBatchRepository(t), mockRepositoryProduct, mockRepositorySection, employeeMocks.NewEmployeeRepository(t))
+
+		_, err := service.Create(context.TODO(), newBatch())
+
+		assert.ErrorIs(t, err, section.ErrSectionArchived)
+	})
+}
+
+func TestProductBatchService_SuggestSection(t *testing.T) {
+	fish := &product.Product{Id: 1, ProductTypeId: 1, RecommendedFreezingTemperature: -18}
+
+	sections := []section.SectionModel{
+		// Compatible, holds the product already.
+		{Id: 1, SectionNumber: 10, WarehouseId: 1, ProductTypeId: 1, MinimumTemperature: -25, CurrentTemperature: -20, CurrentCapacity: 80, MaximumCapacity: 100},
+		// Compatible and emptier, but no consolidation.
+		{Id: 2, SectionNumber: 11, WarehouseId: 1, ProductTypeId: 1, MinimumTemperature: -25, CurrentTemperature: -20, CurrentCapacity: 0, MaximumCapacity: 100},
+		// Compatible but full.
+		{Id: 3, SectionNumber: 12, WarehouseId: 1, ProductTypeId: 1, MinimumTemperature: -25, CurrentTemperature: -20, CurrentCapacity: 95, MaximumCapacity: 100},
+		// Another product type in another warehouse.
+		{Id: 4, SectionNumber: 20, WarehouseId: 2, ProductTypeId: 2, MinimumTemperature: 2, CurrentTemperature: 4, CurrentCapacity: 0, MaximumCapacity: 100},
+	}
+
+	setUp := func(t *testing.T) (domain.ProductBatchService, *productBatch.ProductBatchRepository, *productMocks.ProductRepository, *sectionMocks.SectionRepository) {
+		mockRepositoryProductBatch := productBatch.NewProductBatchRepository(t)
+		mockRepositoryProduct := productMocks.NewProductRepository(t)
+		mockRepositorySection := sectionMocks.NewSectionRepository(t)
+		return service.NewProductBatchService(mockRepositoryProductBatch, mockRepositoryProduct, mockRepositorySection, employeeMocks.NewEmployeeRepository(t)),
+			mockRepositoryProductBatch, mockRepositoryProduct, mockRepositorySection
+	}
+
+	t.Run("suggest_section_ok: should rank consolidation first and rejected sections last", func(t *testing.T) {
+		service, mockRepositoryProductBatch, mockRepositoryProduct, mockRepositorySection := setUp(t)
+
+		mockRepositoryProduct.On("GetById", mock.Anything, int64(1)).Return(fish, nil).Once()
+		mockRepositorySection.On("GetAll", mock.Anything).Return(sections, nil).Once()
+		mockRepositoryProductBatch.
+			On("GetAll", mock.Anything, domain.ProductBatchFilter{ProductId: 1, Status: domain.StatusActive}).
+			Return([]domain.ProductBatch{
+				{Id: 7, SectionId: 1, CurrentQuantity: 30},
+				{Id: 8, SectionId: 1, CurrentQuantity: 0},
+			}, nil).
+			Once()
+
+		suggestion, err := service.SuggestSection(context.TODO(), domain.PutAwayRequest{ProductId: 1, Quantity: 10})
+
+		assert.NoError(t, err)
+		assert.Equal(t, -18.0, suggestion.MinimumTemperature)
+
+		ranked := []int64{}
+		for _, candidate := range suggestion.Candidates {
+			ranked = append(ranked, candidate.SectionId)
+		}
+		assert.Equal(t, []int64{1, 2, 3, 4}, ranked)
+
+		assert.True(t, suggestion.Candidates[0].Eligible)
+		assert.Equal(t, 107.0, suggestion.Candidates[0].Score)
+		assert.Contains(t, suggestion.Candidates[0].Reasons, "already holds 30 units of the product in 1 batches")
+		assert.Equal(t, 98.0, suggestion.Candidates[1].Score)
+
+		assert.False(t, suggestion.Candidates[2].Eligible)
+		assert.Contains(t, suggestion.Candidates[2].Reasons, "has room for 5 units, 10 needed")
+		assert.False(t, suggestion.Candidates[3].Eligible)
+		assert.Contains(t, suggestion.Candidates[3].Reasons, "product type 1 does not match section product type 2")
+	})
+
+	t.Run("suggest_section_warehouse: should only rank the sections of the warehouse", func(t *testing.T) {
+		service, mockRepositoryProductBatch, mockRepositoryProduct, mockRepositorySection := setUp(t)
+
+		mockRepositoryProduct.On("GetById", mock.Anything, int64(1)).Return(fish, nil).Once()
+		mockRepositorySection.On("GetAll", mock.Anything).Return(sections, nil).Once()
+		mockRepositoryProductBatch.On("GetAll", mock.Anything, mock.Anything).Return([]domain.ProductBatch{}, nil).Once()
+
+		suggestion, err := service.SuggestSection(context.TODO(), domain.PutAwayRequest{ProductId: 1, Quantity: 10, WarehouseId: 2})
+
+		assert.NoError(t, err)
+		assert.Len(t, suggestion.Candidates, 1)
+		assert.Equal(t, int64(4), suggestion.Candidates[0].SectionId)
+	})
+
+	t.Run("suggest_section_invalid_quantity: should return ErrInvalidQuantity", func(t *testing.T) {
+		service, _, _, _ := setUp(t)
+
+		_, err := service.SuggestSection(context.TODO(), domain.PutAwayRequest{ProductId: 1})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidQuantity)
+	})
+}
+
+func TestProductBatchService_Transfer(t *testing.T) {
+	fish := &product.Product{Id: 1, ProductTypeId: 1}
+	freezer := section.SectionModel{Id: 2, ProductTypeId: 1, MinimumTemperature: -25, CurrentTemperature: -20}
+	shelf := section.SectionModel{Id: 3, ProductTypeId: 2, MinimumTemperature: 4}
+
+	storedBatch := func() *domain.ProductBatch {
+		return &domain.ProductBatch{Id: 1, ProductId: 1, SectionId: 1, CurrentQuantity: 10, MinumumTemperature: -18, Status: domain.StatusActive}
+	}
+
+	type mocks struct {
+		productBatch *productBatch.ProductBatchRepository
+		product      *productMocks.ProductRepository
+		section      *sectionMocks.SectionRepository
+		employee     *employeeMocks.EmployeeRepository
+	}
+
+	setUp := func(t *testing.T) (domain.ProductBatchService, mocks) {
+		m := mocks{
+			productBatch: productBatch.NewProductBatchRepository(t),
+			product:      productMocks.NewProductRepository(t),
+			section:      sectionMocks.NewSectionRepository(t),
+			employee:     employeeMocks.NewEmployeeRepository(t),
+		}
+		return service.NewProductBatchService(m.productBatch, m.product, m.section, m.employee), m
+	}
+
+	t.Run("transfer_split_ok: should move part of the batch to a compatible section", func(t *testing.T) {
+		service, m := setUp(t)
+
+		transfer := domain.Transfer{ProductBatchId: 1, ToSectionId: 2, Quantity: 4, EmployeeId: 7, Reason: " rebalance "}
+		source := storedBatch()
+		source.CurrentQuantity = 6
+		target := &domain.ProductBatch{Id: 2, ProductId: 1, SectionId: 2, CurrentQuantity: 4}
+
+		m.productBatch.On("GetById", mock.Anything, int64(1)).Return(storedBatch(), nil).Once()
+		m.employee.On("GetById", mock.Anything, int64(7)).Return(&employees.Employee{Id: 7}, nil).Once()
+		m.product.On("GetById", mock.Anything, int64(1)).Return(fish, nil)
+		m.section.On("GetById", mock.Anything, int64(2)).Return(freezer, nil).Once()
+		m.section.On("GetById", mock.Anything, int64(1)).Return(section.SectionModel{Id: 1}, nil).Once()
+
+		expected := transfer
+		expected.Reason = "rebalance"
+		m.productBatch.
+			On("Transfer", mock.Anything, expected).
+			Return(&domain.TransferResult{Source: source, Target: target, Split: true}, nil).
+			Once()
+
+		result, err := service.Transfer(context.TODO(), transfer)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(6), result.Source.CurrentQuantity)
+		assert.Equal(t, int64(2), result.Target.SectionId)
+	})
+
+	t.Run("transfer_incompatible: should reject a section of another type and temperature", func(t *testing.T) {
+		service, m := setUp(t)
+
+		m.productBatch.On("GetById", mock.Anything, int64(1)).Return(storedBatch(), nil).Once()
+		m.employee.On("GetById", mock.Anything, int64(7)).Return(&employees.Employee{Id: 7}, nil).Once()
+		m.product.On("GetById", mock.Anything, int64(1)).Return(fish, nil).Once()
+		m.section.On("GetById", mock.Anything, int64(3)).Return(shelf, nil).Once()
+
+		_, err := service.Transfer(context.TODO(), domain.Transfer{ProductBatchId: 1, ToSectionId: 3, EmployeeId: 7, Reason: "rebalance"})
+
+		assert.ErrorIs(t, err, domain.ErrIncompatibleSection)
+	})
+
+	t.Run("transfer_override: should pass the bypassed rules on for the audit log", func(t *testing.T) {
+		service, m := setUp(t)
+
+		moved := storedBatch()
+		moved.SectionId = 3
+
+		m.productBatch.On("GetById", mock.Anything, int64(1)).Return(storedBatch(), nil).Once()
+		m.employee.On("GetById", mock.Anything, int64(7)).Return(&employees.Employee{Id: 7}, nil).Once()
+		m.product.On("GetById", mock.Anything, int64(1)).Return(fish, nil).Once()
+		m.section.On("GetById", mock.Anything, int64(3)).Return(shelf, nil).Once()
+		m.productBatch.
+			On("Transfer", mock.Anything, mock.MatchedBy(func(transfer domain.Transfer) bool {
+				return transfer.Override != nil && len(transfer.Override.Violations) == 2
+			})).
+			Return(&domain.TransferResult{Source: moved, Target: moved}, nil).
+			Once()
+
+		_, err := service.Transfer(context.TODO(), domain.Transfer{
+			ProductBatchId: 1,
+			ToSectionId:    3,
+			EmployeeId:     7,
+			Reason:         "freezer under repair",
+			Override:       &domain.CompatibilityOverride{By: "maria", Reason: "freezer under repair"},
+		})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("transfer_employee_not_found: should return ErrEmployeeNotFound", func(t *testing.T) {
+		service, m := setUp(t)
+
+		m.productBatch.On("GetById", mock.Anything, int64(1)).Return(storedBatch(), nil).Once()
+		m.employee.On("GetById", mock.Anything, int64(9)).Return(nil, employees.ErrEmployeeNotFound).Once()
+
+		_, err := service.Transfer(context.TODO(), domain.Transfer{ProductBatchId: 1, ToSectionId: 2, EmployeeId: 9, Reason: "rebalance"})
+
+		assert.ErrorIs(t, err, employees.ErrEmployeeNotFound)
+	})
+
+	t.Run("transfer_invalid: should validate reason, section, quantity and status", func(t *testing.T) {
+		service, m := setUp(t)
+
+		discarded := storedBatch()
+		discarded.Status = domain.StatusDiscarded
+
+		empty := storedBatch()
+		empty.CurrentQuantity = 0
+
+		m.productBatch.On("GetById", mock.Anything, int64(1)).Return(storedBatch(), nil).Times(2)
+		m.productBatch.On("GetById", mock.Anything, int64(2)).Return(discarded, nil).Once()
+		m.productBatch.On("GetById", mock.Anything, int64(3)).Return(empty, nil).Once()
+
+		_, err := service.Transfer(context.TODO(), domain.Transfer{ProductBatchId: 1, ToSectionId: 2, EmployeeId: 7, Reason: " "})
+		assert.ErrorIs(t, err, domain.ErrTransferReason)
+
+		_, err = service.Transfer(context.TODO(), domain.Transfer{ProductBatchId: 1, ToSectionId: 1, EmployeeId: 7, Reason: "rebalance"})
+		assert.ErrorIs(t, err, domain.ErrSameSection)
+
+		_, err = service.Transfer(context.TODO(), domain.Transfer{ProductBatchId: 1, ToSectionId: 2, Quantity: 11, EmployeeId: 7, Reason: "rebalance"})
+		assert.ErrorIs(t, err, domain.ErrTransferQuantity)
+
+		_, err = service.Transfer(context.TODO(), domain.Transfer{ProductBatchId: 2, ToSectionId: 2, EmployeeId: 7, Reason: "rebalance"})
+		assert.ErrorIs(t, err, domain.ErrProductBatchDiscarded)
+
+		// Without a quantity the whole batch moves, which needs stock to move.
+		_, err = service.Transfer(context.TODO(), domain.Transfer{ProductBatchId: 3, ToSectionId: 2, EmployeeId: 7, Reason: "rebalance"})
+		assert.ErrorIs(t, err, domain.ErrTransferQuantity)
+	})
+}
+
+func TestProductBatchService_Update_Pick(t *testing.T) {
+	purchaseOrderId := int64(9)
+	employeeId := int64(7)
+
+	setUp := func(t *testing.T) (domain.ProductBatchService, *productBatch.ProductBatchRepository, *employeeMocks.EmployeeRepository) {
+		mockRepositoryProductBatch := productBatch.NewProductBatchRepository(t)
+		mockRepositoryEmployee := employeeMocks.NewEmployeeRepository(t)
+		return service.NewProductBatchService(mockRepositoryProductBatch, productMocks.NewProductRepository(t), sectionMocks.NewSectionRepository(t), mockRepositoryEmployee),
+			mockRepositoryProductBatch, mockRepositoryEmployee
+	}
+
+	stored := func() *domain.ProductBatch {
+		productBatch := expectedProductBatch
+		productBatch.CurrentQuantity = 10
+		productBatch.InitialQuantity = 10
+		productBatch.Status = domain.StatusActive
+		return &productBatch
+	}
+
+	t.Run("pick_ok: should record the decrease as a pick for the purchase order", func(t *testing.T) {
+		service, mockRepositoryProductBatch, mockRepositoryEmployee := setUp(t)
+		quantity := int64(6)
+
+		mockRepositoryProductBatch.On("GetById", mock.Anything, int64(1)).Return(stored(), nil).Once()
+		mockRepositoryEmployee.On("GetById", mock.Anything, employeeId).Return(&employees.Employee{Id: employeeId}, nil).Once()
+		mockRepositoryProductBatch.
+			On("Update", mock.Anything, mock.MatchedBy(func(productBatch *domain.ProductBatch) bool {
+				movement := productBatch.Movement
+				return productBatch.CurrentQuantity == 6 &&
+					movement.Type == domain.MovementPick &&
+					movement.ReferenceType == domain.ReferencePurchaseOrder &&
+					*movement.ReferenceId == purchaseOrderId &&
+					*movement.EmployeeId == employeeId
+			})).
+			Return(nil, fmt.Errorf("stop")).
+			Once()
+
+		_, err := service.Update(context.TODO(), 1, domain.ProductBatchPatch{
+			CurrentQuantity: &quantity,
+			EmployeeId:      &employeeId,
+			PurchaseOrderId: &purchaseOrderId,
+		})
+
+		assert.EqualError(t, err, "stop")
+	})
+
+	t.Run("pick_invalid: should only pick units from an active batch", func(t *testing.T) {
+		service, mockRepositoryProductBatch, _ := setUp(t)
+		more := int64(10)
+		less := int64(4)
+		quarantined := stored()
+		quarantined.Status = domain.StatusQuarantined
+
+		mockRepositoryProductBatch.On("GetById", mock.Anything, int64(1)).Return(stored(), nil).Twice()
+		mockRepositoryProductBatch.On("GetById", mock.Anything, int64(2)).Return(quarantined, nil).Once()
+
+		_, err := service.Update(context.TODO(), 1, domain.ProductBatchPatch{PurchaseOrderId: &purchaseOrderId})
+		assert.ErrorIs(t, err, domain.ErrInvalidPick)
+
+		_, err = service.Update(context.TODO(), 1, domain.ProductBatchPatch{CurrentQuantity: &more, PurchaseOrderId: &purchaseOrderId})
+		assert.ErrorIs(t, err, domain.ErrInvalidPick)
+
+		_, err = service.Update(context.TODO(), 2, domain.ProductBatchPatch{CurrentQuantity: &less, PurchaseOrderId: &purchaseOrderId})
+		assert.ErrorIs(t, err, domain.ErrInvalidPick)
+	})
+}
+
+func TestProductBatchService_GetMovements(t *testing.T) {
+	mockRepositoryProductBatch := productBatch.NewProductBatchRepository(t)
+	service := service.NewProductBatchService(mockRepositoryProductBatch, productMocks.NewProductRepository(t), sectionMocks.NewSectionRepository(t), employeeMocks.NewEmployeeRepository(t))
+
+	movements := []domain.StockMovement{
+		{Id: 1, Type: domain.MovementReceipt, Quantity: 10},
+		{Id: 2, Type: domain.MovementPick, Quantity: -4},
+		{Id: 3, Type: domain.MovementAdjustment, Quantity: -1},
+	}
+
+	t.Run("get_movements_ok: should derive the quantity from the ledger", func(t *testing.T) {
+		stored := expectedProductBatch
+		stored.CurrentQuantity = 5
+
+		mockRepositoryProductBatch.On("GetById", mock.Anything, int64(1)).Return(&stored, nil).Once()
+		mockRepositoryProductBatch.On("GetMovements", mock.Anything, int64(1)).Return(movements, nil).Once()
+
+		ledger, err := service.GetMovements(context.TODO(), 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(5), ledger.LedgerQuantity)
+		assert.True(t, ledger.Consistent)
+		assert.Len(t, ledger.Movements, 3)
+	})
+
+	t.Run("get_movements_inconsistent: should flag a quantity the ledger does not explain", func(t *testing.T) {
+		stored := expectedProductBatch
+		stored.CurrentQuantity = 8
+
+		mockRepositoryProductBatch.On("GetById", mock.Anything, int64(1)).Return(&stored, nil).Once()
+		mockRepositoryProductBatch.On("GetMovements", mock.Anything, int64(1)).Return(movements, nil).Once()
+
+		ledger, err := service.GetMovements(context.TODO(), 1)
+
+		assert.NoError(t, err)
+		assert.False(t, ledger.Consistent)
+	})
+
+	t.Run("get_movements_not_found: should return ErrProductBatchNotFound", func(t *testing.T) {
+		mockRepositoryProductBatch.On("GetById", mock.Anything, int64(2)).Return(nil, domain.ErrProductBatchNotFound).Once()
+
+		_, err := service.GetMovements(context.TODO(), 2)
+
+		assert.ErrorIs(t, err, domain.ErrProductBatchNotFound)
+	})
 }
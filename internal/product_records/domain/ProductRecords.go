@@ -2,6 +2,7 @@ package domain
 
 import (
 	"context"
+	"math"
 	"time"
 )
 
@@ -13,11 +14,34 @@ type ProductRecords struct {
 	ProductId      int64     `json:"product_id"`
 }
 
+// ProductPrice is a product record along with the margin between its sale
+// and purchase prices. MarginPercentage is relative to the sale price.
+type ProductPrice struct {
+	ProductRecords
+	Margin           float64 `json:"margin"`
+	MarginPercentage float64 `json:"margin_percentage"`
+}
+
+func NewProductPrice(record ProductRecords) ProductPrice {
+	price := ProductPrice{
+		ProductRecords: record,
+		Margin:         math.Round((record.SalePrice-record.PurchasePrice)*100) / 100,
+	}
+	if record.SalePrice != 0 {
+		price.MarginPercentage = math.Round(price.Margin/record.SalePrice*10000) / 100
+	}
+	return price
+}
+
 type ProductRecordsRepository interface {
 	Create(ctx context.Context, productRecords *ProductRecords) (*ProductRecords, error)
 	CountByProductId(ctx context.Context, productId int64) (int64, error)
+	GetByProductId(ctx context.Context, productId int64, from, to *time.Time) ([]ProductRecords, error)
+	GetEffective(ctx context.Context, productId int64, at time.Time) (*ProductRecords, error)
 }
 
 type ProductRecordsService interface {
 	Create(ctx context.Context, productRecords *ProductRecords) (*ProductRecords, error)
+	GetByProductId(ctx context.Context, productId int64, from, to *time.Time) ([]ProductPrice, error)
+	GetPriceAt(ctx context.Context, productId int64, at time.Time) (*ProductPrice, error)
 }
@@ -6,4 +6,8 @@ var (
 	ErrProductIdNotFound = errors.New("product id not found")
 
 	ErrInvalidDate = errors.New("the product record's date can't be before the current date")
+
+	ErrInvalidDateRange = errors.New("the start of the date range can't be after its end")
+
+	ErrPriceNotFound = errors.New("no price recorded for the product at the given time")
 )
@@ -7,6 +7,8 @@ import (
 
 	mock "github.com/stretchr/testify/mock"
 	domain "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_records/domain"
+
+	time "time"
 )
 
 // ProductRecordsRepository is an autogenerated mock type for the ProductRecordsRepository type
@@ -58,6 +60,52 @@ func (_m *ProductRecordsRepository) Create(ctx context.Context, productRecords *
 	return r0, r1
 }
 
+// GetByProductId provides a mock function with given fields: ctx, productId, from, to
+func (_m *ProductRecordsRepository) GetByProductId(ctx context.Context, productId int64, from *time.Time, to *time.Time) ([]domain.ProductRecords, error) {
+	ret := _m.Called(ctx, productId, from, to)
+
+	var r0 []domain.ProductRecords
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *time.Time, *time.Time) []domain.ProductRecords); ok {
+		r0 = rf(ctx, productId, from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.ProductRecords)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, *time.Time, *time.Time) error); ok {
+		r1 = rf(ctx, productId, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetEffective provides a mock function with given fields: ctx, productId, at
+func (_m *ProductRecordsRepository) GetEffective(ctx context.Context, productId int64, at time.Time) (*domain.ProductRecords, error) {
+	ret := _m.Called(ctx, productId, at)
+
+	var r0 *domain.ProductRecords
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time) *domain.ProductRecords); ok {
+		r0 = rf(ctx, productId, at)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ProductRecords)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, time.Time) error); ok {
+		r1 = rf(ctx, productId, at)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 type mockConstructorTestingTNewProductRecordsRepository interface {
 	mock.TestingT
 	Cleanup(func())
@@ -7,6 +7,8 @@ import (
 
 	mock "github.com/stretchr/testify/mock"
 	domain "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_records/domain"
+
+	time "time"
 )
 
 // ProductRecordsService is an autogenerated mock type for the ProductRecordsService type
@@ -37,6 +39,52 @@ func (_m *ProductRecordsService) Create(ctx context.Context, productRecords *dom
 	return r0, r1
 }
 
+// GetByProductId provides a mock function with given fields: ctx, productId, from, to
+func (_m *ProductRecordsService) GetByProductId(ctx context.Context, productId int64, from *time.Time, to *time.Time) ([]domain.ProductPrice, error) {
+	ret := _m.Called(ctx, productId, from, to)
+
+	var r0 []domain.ProductPrice
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *time.Time, *time.Time) []domain.ProductPrice); ok {
+		r0 = rf(ctx, productId, from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.ProductPrice)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, *time.Time, *time.Time) error); ok {
+		r1 = rf(ctx, productId, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPriceAt provides a mock function with given fields: ctx, productId, at
+func (_m *ProductRecordsService) GetPriceAt(ctx context.Context, productId int64, at time.Time) (*domain.ProductPrice, error) {
+	ret := _m.Called(ctx, productId, at)
+
+	var r0 *domain.ProductPrice
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time) *domain.ProductPrice); ok {
+		r0 = rf(ctx, productId, at)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ProductPrice)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, time.Time) error); ok {
+		r1 = rf(ctx, productId, at)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 type mockConstructorTestingTNewProductRecordsService interface {
 	mock.TestingT
 	Cleanup(func())
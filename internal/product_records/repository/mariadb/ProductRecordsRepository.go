@@ -4,7 +4,10 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"time"
+
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_records/domain"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/libs/outbox"
 )
 
 type mariaDBProductRecordsRepository struct {
@@ -16,7 +19,12 @@ func CreateProductRecordsRepository(db *sql.DB) domain.ProductRecordsRepository
 }
 
 func (m mariaDBProductRecordsRepository) Create(ctx context.Context, productRecords *domain.ProductRecords) (*domain.ProductRecords, error) {
-	productRecordsResult, err := m.db.ExecContext(
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	productRecordsResult, err := tx.ExecContext(
 		ctx,
 		SqlCreate,
 		&productRecords.LastUpdateDate,
@@ -26,6 +34,7 @@ func (m mariaDBProductRecordsRepository) Create(ctx context.Context, productReco
 	)
 
 	if err != nil {
+		tx.Rollback()
 		return nil, err
 	}
 
@@ -33,6 +42,15 @@ func (m mariaDBProductRecordsRepository) Create(ctx context.Context, productReco
 
 	productRecords.Id = lastId
 
+	if err := outbox.Publish(ctx, tx, outbox.ProductRecordCreated, lastId, productRecords); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
 	return productRecords, nil
 }
 
@@ -54,3 +72,60 @@ func (m mariaDBProductRecordsRepository) CountByProductId(ctx context.Context, p
 
 	return productRecordsCount, nil
 }
+
+func (m mariaDBProductRecordsRepository) GetByProductId(ctx context.Context, productId int64, from, to *time.Time) ([]domain.ProductRecords, error) {
+	rows, err := m.db.QueryContext(ctx, SqlGetByProductId, productId, from, from, to, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	productRecords := make([]domain.ProductRecords, 0)
+
+	for rows.Next() {
+		var record domain.ProductRecords
+
+		err := rows.Scan(
+			&record.Id,
+			&record.LastUpdateDate,
+			&record.PurchasePrice,
+			&record.SalePrice,
+			&record.ProductId,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		productRecords = append(productRecords, record)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return productRecords, nil
+}
+
+func (m mariaDBProductRecordsRepository) GetEffective(ctx context.Context, productId int64, at time.Time) (*domain.ProductRecords, error) {
+	row := m.db.QueryRowContext(ctx, SqlGetEffective, productId, at)
+
+	var record domain.ProductRecords
+
+	err := row.Scan(
+		&record.Id,
+		&record.LastUpdateDate,
+		&record.PurchasePrice,
+		&record.SalePrice,
+		&record.ProductId,
+	)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrPriceNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
@@ -13,4 +13,21 @@ const (
     `
 
 	SqlCountByProductId = `SELECT count(*) FROM product_records WHERE product_id=?`
+
+	SqlGetByProductId = `
+	SELECT id, last_update_date, purchase_price, sale_price, product_id
+	FROM product_records
+	WHERE product_id=?
+	AND (? IS NULL OR last_update_date >= ?)
+	AND (? IS NULL OR last_update_date <= ?)
+	ORDER BY last_update_date, id
+	`
+
+	SqlGetEffective = `
+	SELECT id, last_update_date, purchase_price, sale_price, product_id
+	FROM product_records
+	WHERE product_id=? AND last_update_date <= ?
+	ORDER BY last_update_date DESC, id DESC
+	LIMIT 1
+	`
 )
@@ -2,11 +2,13 @@ package mariadb_test
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_records/domain"
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_records/repository/mariadb"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/libs/outbox"
 	"regexp"
 	"testing"
 	"time"
@@ -29,6 +31,7 @@ func TestMariaDBProductRecordsRepository_Create(t *testing.T) {
 		assert.NoError(t, err)
 		defer db.Close()
 
+		mock.ExpectBegin()
 		mock.
 			ExpectExec(regexp.QuoteMeta(mariadb.SqlCreate)).
 			WithArgs(
@@ -37,6 +40,11 @@ func TestMariaDBProductRecordsRepository_Create(t *testing.T) {
 				expectedProductRecords.SalePrice,
 				expectedProductRecords.ProductId).
 			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.
+			ExpectExec(regexp.QuoteMeta(outbox.SQLCreateOutboxEvent)).
+			WithArgs(outbox.ProductRecordCreated, expectedProductRecords.Id, nil, sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
 
 		productRecordsRepository := mariadb.CreateProductRecordsRepository(db)
 
@@ -44,6 +52,7 @@ func TestMariaDBProductRecordsRepository_Create(t *testing.T) {
 
 		assert.NoError(t, err)
 		assert.Equal(t, result, &expectedProductRecords)
+		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
 	t.Run("create_fail_exec: should return error when query execution fails", func(t *testing.T) {
@@ -53,15 +62,40 @@ func TestMariaDBProductRecordsRepository_Create(t *testing.T) {
 		assert.NoError(t, err)
 		defer db.Close()
 
+		mock.ExpectBegin()
 		mock.
 			ExpectExec(regexp.QuoteMeta(mariadb.SqlCreate)).
 			WithArgs(0, 0, 0, 0).
 			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectRollback()
+
+		productRecordsRepository := mariadb.CreateProductRecordsRepository(db)
+		_, err = productRecordsRepository.Create(context.TODO(), &expectedProductRecords)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("create_fail_outbox: should rollback when the domain event cannot be written", func(t *testing.T) {
+
+		db, mock, err := sqlmock.New()
+
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.
+			ExpectExec(regexp.QuoteMeta(mariadb.SqlCreate)).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.
+			ExpectExec(regexp.QuoteMeta(outbox.SQLCreateOutboxEvent)).
+			WillReturnError(errors.New("outbox error"))
+		mock.ExpectRollback()
 
 		productRecordsRepository := mariadb.CreateProductRecordsRepository(db)
 		_, err = productRecordsRepository.Create(context.TODO(), &expectedProductRecords)
 
 		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 }
 
@@ -112,3 +146,97 @@ func TestMariaDBProductRecordsRepository_CountByProductId(t *testing.T) {
 
 	})
 }
+
+func TestMariaDBProductRecordsRepository_GetByProductId(t *testing.T) {
+
+	date := time.Date(2022, 4, 4, 0, 0, 0, 0, time.UTC)
+	columns := []string{"id", "last_update_date", "purchase_price", "sale_price", "product_id"}
+
+	t.Run("get_by_product_id_ok: should return the records in the range", func(t *testing.T) {
+
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		from := date
+		to := date.AddDate(0, 1, 0)
+
+		mock.
+			ExpectQuery(regexp.QuoteMeta(mariadb.SqlGetByProductId)).
+			WithArgs(int64(1), &from, &from, &to, &to).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(1, date, 10.5, 15.2, 1).
+				AddRow(2, date.AddDate(0, 0, 7), 11, 16, 1))
+
+		productRecordsRepository := mariadb.CreateProductRecordsRepository(db)
+
+		result, err := productRecordsRepository.GetByProductId(context.TODO(), 1, &from, &to)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.ProductRecords{
+			{Id: 1, LastUpdateDate: date, PurchasePrice: 10.5, SalePrice: 15.2, ProductId: 1},
+			{Id: 2, LastUpdateDate: date.AddDate(0, 0, 7), PurchasePrice: 11, SalePrice: 16, ProductId: 1},
+		}, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("get_by_product_id_fail: should return error when query fails", func(t *testing.T) {
+
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.
+			ExpectQuery(regexp.QuoteMeta(mariadb.SqlGetByProductId)).
+			WillReturnError(errors.New("any error"))
+
+		productRecordsRepository := mariadb.CreateProductRecordsRepository(db)
+
+		_, err = productRecordsRepository.GetByProductId(context.TODO(), 1, nil, nil)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestMariaDBProductRecordsRepository_GetEffective(t *testing.T) {
+
+	at := time.Date(2022, 5, 1, 12, 0, 0, 0, time.UTC)
+	columns := []string{"id", "last_update_date", "purchase_price", "sale_price", "product_id"}
+
+	t.Run("get_effective_ok: should return the latest record up to the time", func(t *testing.T) {
+
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.
+			ExpectQuery(regexp.QuoteMeta(mariadb.SqlGetEffective)).
+			WithArgs(int64(1), at).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(3, at.AddDate(0, 0, -2), 8, 10, 1))
+
+		productRecordsRepository := mariadb.CreateProductRecordsRepository(db)
+
+		result, err := productRecordsRepository.GetEffective(context.TODO(), 1, at)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &domain.ProductRecords{Id: 3, LastUpdateDate: at.AddDate(0, 0, -2), PurchasePrice: 8, SalePrice: 10, ProductId: 1}, result)
+	})
+
+	t.Run("get_effective_not_found: should return ErrPriceNotFound when there is no record", func(t *testing.T) {
+
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.
+			ExpectQuery(regexp.QuoteMeta(mariadb.SqlGetEffective)).
+			WithArgs(int64(1), at).
+			WillReturnError(sql.ErrNoRows)
+
+		productRecordsRepository := mariadb.CreateProductRecordsRepository(db)
+
+		_, err = productRecordsRepository.GetEffective(context.TODO(), 1, at)
+
+		assert.ErrorIs(t, err, domain.ErrPriceNotFound)
+	})
+}
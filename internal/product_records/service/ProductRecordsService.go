@@ -42,3 +42,42 @@ func (s *productRecordsService) Create(ctx context.Context, productRecords *doma
 
 	return newProductRecords, nil
 }
+
+func (s *productRecordsService) GetByProductId(ctx context.Context, productId int64, from, to *time.Time) ([]domain.ProductPrice, error) {
+
+	if from != nil && to != nil && from.After(*to) {
+		return nil, domain.ErrInvalidDateRange
+	}
+
+	if _, err := s.repositoryProduct.GetById(ctx, productId); err != nil {
+		return nil, err
+	}
+
+	productRecords, err := s.repositoryProductRecords.GetByProductId(ctx, productId, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	prices := make([]domain.ProductPrice, 0, len(productRecords))
+	for _, record := range productRecords {
+		prices = append(prices, domain.NewProductPrice(record))
+	}
+
+	return prices, nil
+}
+
+func (s *productRecordsService) GetPriceAt(ctx context.Context, productId int64, at time.Time) (*domain.ProductPrice, error) {
+
+	if _, err := s.repositoryProduct.GetById(ctx, productId); err != nil {
+		return nil, err
+	}
+
+	record, err := s.repositoryProductRecords.GetEffective(ctx, productId, at)
+	if err != nil {
+		return nil, err
+	}
+
+	price := domain.NewProductPrice(*record)
+
+	return &price, nil
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"github.com/stretchr/testify/assert"
+	product "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product/domain"
 	mocksProduct "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product/domain/mocks"
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_records/domain"
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_records/domain/mocks"
@@ -99,3 +100,94 @@ func TestProductService_Create(t *testing.T) {
 	})
 
 }
+
+func TestProductService_GetByProductId(t *testing.T) {
+
+	date := time.Date(2022, 4, 4, 0, 0, 0, 0, time.UTC)
+
+	t.Run("get_by_product_id_ok: should return the records with their margins", func(t *testing.T) {
+		mockRepositoryProductRecords := mocks.NewProductRecordsRepository(t)
+		mockRepositoryProduct := mocksProduct.NewProductRepository(t)
+
+		service := CreateProductRecordsService(mockRepositoryProductRecords, mockRepositoryProduct)
+
+		from := date
+		to := date.AddDate(0, 1, 0)
+		records := []domain.ProductRecords{
+			{Id: 1, LastUpdateDate: date, PurchasePrice: 10, SalePrice: 15, ProductId: 1},
+			{Id: 2, LastUpdateDate: date.AddDate(0, 0, 7), PurchasePrice: 12, SalePrice: 0, ProductId: 1},
+		}
+
+		mockRepositoryProduct.On("GetById", context.TODO(), int64(1)).Return(&product.Product{Id: 1}, nil).Once()
+		mockRepositoryProductRecords.On("GetByProductId", context.TODO(), int64(1), &from, &to).Return(records, nil).Once()
+
+		prices, err := service.GetByProductId(context.TODO(), 1, &from, &to)
+
+		assert.NoError(t, err)
+		assert.Len(t, prices, 2)
+		assert.Equal(t, records[0], prices[0].ProductRecords)
+		assert.Equal(t, 5.0, prices[0].Margin)
+		assert.Equal(t, 33.33, prices[0].MarginPercentage)
+		assert.Equal(t, -12.0, prices[1].Margin)
+		assert.Equal(t, 0.0, prices[1].MarginPercentage)
+	})
+
+	t.Run("get_by_product_id_invalid_range: when from is after to, should return error", func(t *testing.T) {
+		service := CreateProductRecordsService(mocks.NewProductRecordsRepository(t), mocksProduct.NewProductRepository(t))
+
+		from := date.AddDate(0, 1, 0)
+		to := date
+
+		_, err := service.GetByProductId(context.TODO(), 1, &from, &to)
+
+		assert.ErrorIs(t, err, domain.ErrInvalidDateRange)
+	})
+
+	t.Run("get_by_product_id_product_not_found: when the product does not exist, should return error", func(t *testing.T) {
+		mockRepositoryProduct := mocksProduct.NewProductRepository(t)
+
+		service := CreateProductRecordsService(mocks.NewProductRecordsRepository(t), mockRepositoryProduct)
+
+		mockRepositoryProduct.On("GetById", context.TODO(), int64(1)).Return(nil, product.ErrProductIdNotFound).Once()
+
+		_, err := service.GetByProductId(context.TODO(), 1, nil, nil)
+
+		assert.ErrorIs(t, err, product.ErrProductIdNotFound)
+	})
+}
+
+func TestProductService_GetPriceAt(t *testing.T) {
+
+	at := time.Date(2022, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("get_price_at_ok: should return the effective record with its margin", func(t *testing.T) {
+		mockRepositoryProductRecords := mocks.NewProductRecordsRepository(t)
+		mockRepositoryProduct := mocksProduct.NewProductRepository(t)
+
+		service := CreateProductRecordsService(mockRepositoryProductRecords, mockRepositoryProduct)
+
+		record := domain.ProductRecords{Id: 3, LastUpdateDate: at.AddDate(0, 0, -2), PurchasePrice: 8, SalePrice: 10, ProductId: 1}
+
+		mockRepositoryProduct.On("GetById", context.TODO(), int64(1)).Return(&product.Product{Id: 1}, nil).Once()
+		mockRepositoryProductRecords.On("GetEffective", context.TODO(), int64(1), at).Return(&record, nil).Once()
+
+		price, err := service.GetPriceAt(context.TODO(), 1, at)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.ProductPrice{ProductRecords: record, Margin: 2, MarginPercentage: 20}, *price)
+	})
+
+	t.Run("get_price_at_not_found: when no record precedes the time, should return error", func(t *testing.T) {
+		mockRepositoryProductRecords := mocks.NewProductRecordsRepository(t)
+		mockRepositoryProduct := mocksProduct.NewProductRepository(t)
+
+		service := CreateProductRecordsService(mockRepositoryProductRecords, mockRepositoryProduct)
+
+		mockRepositoryProduct.On("GetById", context.TODO(), int64(1)).Return(&product.Product{Id: 1}, nil).Once()
+		mockRepositoryProductRecords.On("GetEffective", context.TODO(), int64(1), at).Return(nil, domain.ErrPriceNotFound).Once()
+
+		_, err := service.GetPriceAt(context.TODO(), 1, at)
+
+		assert.ErrorIs(t, err, domain.ErrPriceNotFound)
+	})
+}
@@ -0,0 +1,48 @@
+package domain
+
+import "context"
+
+// Storage categories. Sections and batches holding a type are expected to
+// stay within its temperature range.
+const (
+	CategoryFrozen       = "frozen"
+	CategoryRefrigerated = "refrigerated"
+	CategoryAmbient      = "ambient"
+)
+
+var Categories = []string{CategoryFrozen, CategoryRefrigerated, CategoryAmbient}
+
+type ProductType struct {
+	Id                 int64   `json:"id"`
+	Description        string  `json:"description"`
+	Category           string  `json:"category"`
+	MinimumTemperature float64 `json:"minimum_temperature"`
+	MaximumTemperature float64 `json:"maximum_temperature"`
+}
+
+type ProductTypeRepository interface {
+	GetAll(ctx context.Context) ([]ProductType, error)
+	GetById(ctx context.Context, id int64) (*ProductType, error)
+	GetByDescription(ctx context.Context, description string) (*ProductType, error)
+	Create(ctx context.Context, productType *ProductType) (*ProductType, error)
+	Update(ctx context.Context, productType *ProductType) (*ProductType, error)
+	Delete(ctx context.Context, id int64) error
+	CountUsage(ctx context.Context, id int64) (int64, error)
+}
+
+type ProductTypeService interface {
+	GetAll(ctx context.Context) ([]ProductType, error)
+	GetById(ctx context.Context, id int64) (*ProductType, error)
+	Create(ctx context.Context, productType *ProductType) (*ProductType, error)
+	Update(ctx context.Context, id int64, description *string, category *string, minimumTemperature *float64, maximumTemperature *float64) (*ProductType, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+func IsCategory(category string) bool {
+	for _, c := range Categories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,12 @@
+package domain
+
+import "errors"
+
+var (
+	ErrProductTypeNotFound      = errors.New("product type not found")
+	ErrProductTypeAlreadyExists = errors.New("product type description already exists")
+	ErrProductTypeInUse         = errors.New("product type is used by products or sections")
+	ErrInvalidDescription       = errors.New("product type description must not be empty")
+	ErrInvalidCategory          = errors.New("category must be frozen, refrigerated or ambient")
+	ErrInvalidTemperatureRange  = errors.New("minimum temperature must not be greater than maximum temperature")
+)
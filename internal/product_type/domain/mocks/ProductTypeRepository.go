@@ -0,0 +1,180 @@
+// Code generated by mockery v2.13.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	domain "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_type/domain"
+)
+
+// ProductTypeRepository is an autogenerated mock type for the ProductTypeRepository type
+type ProductTypeRepository struct {
+	mock.Mock
+}
+
+// CountUsage provides a mock function with given fields: ctx, id
+func (_m *ProductTypeRepository) CountUsage(ctx context.Context, id int64) (int64, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, int64) int64); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Create provides a mock function with given fields: ctx, productType
+func (_m *ProductTypeRepository) Create(ctx context.Context, productType *domain.ProductType) (*domain.ProductType, error) {
+	ret := _m.Called(ctx, productType)
+
+	var r0 *domain.ProductType
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.ProductType) *domain.ProductType); ok {
+		r0 = rf(ctx, productType)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ProductType)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.ProductType) error); ok {
+		r1 = rf(ctx, productType)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Delete provides a mock function with given fields: ctx, id
+func (_m *ProductTypeRepository) Delete(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetAll provides a mock function with given fields: ctx
+func (_m *ProductTypeRepository) GetAll(ctx context.Context) ([]domain.ProductType, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []domain.ProductType
+	if rf, ok := ret.Get(0).(func(context.Context) []domain.ProductType); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.ProductType)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByDescription provides a mock function with given fields: ctx, description
+func (_m *ProductTypeRepository) GetByDescription(ctx context.Context, description string) (*domain.ProductType, error) {
+	ret := _m.Called(ctx, description)
+
+	var r0 *domain.ProductType
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.ProductType); ok {
+		r0 = rf(ctx, description)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ProductType)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, description)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetById provides a mock function with given fields: ctx, id
+func (_m *ProductTypeRepository) GetById(ctx context.Context, id int64) (*domain.ProductType, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *domain.ProductType
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *domain.ProductType); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ProductType)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: ctx, productType
+func (_m *ProductTypeRepository) Update(ctx context.Context, productType *domain.ProductType) (*domain.ProductType, error) {
+	ret := _m.Called(ctx, productType)
+
+	var r0 *domain.ProductType
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.ProductType) *domain.ProductType); ok {
+		r0 = rf(ctx, productType)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ProductType)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.ProductType) error); ok {
+		r1 = rf(ctx, productType)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewProductTypeRepository interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewProductTypeRepository creates a new instance of ProductTypeRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewProductTypeRepository(t mockConstructorTestingTNewProductTypeRepository) *ProductTypeRepository {
+	mock := &ProductTypeRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
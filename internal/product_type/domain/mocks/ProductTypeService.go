@@ -0,0 +1,136 @@
+// Code generated by mockery v2.13.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	domain "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_type/domain"
+)
+
+// ProductTypeService is an autogenerated mock type for the ProductTypeService type
+type ProductTypeService struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: ctx, productType
+func (_m *ProductTypeService) Create(ctx context.Context, productType *domain.ProductType) (*domain.ProductType, error) {
+	ret := _m.Called(ctx, productType)
+
+	var r0 *domain.ProductType
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.ProductType) *domain.ProductType); ok {
+		r0 = rf(ctx, productType)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ProductType)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.ProductType) error); ok {
+		r1 = rf(ctx, productType)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Delete provides a mock function with given fields: ctx, id
+func (_m *ProductTypeService) Delete(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetAll provides a mock function with given fields: ctx
+func (_m *ProductTypeService) GetAll(ctx context.Context) ([]domain.ProductType, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []domain.ProductType
+	if rf, ok := ret.Get(0).(func(context.Context) []domain.ProductType); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.ProductType)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetById provides a mock function with given fields: ctx, id
+func (_m *ProductTypeService) GetById(ctx context.Context, id int64) (*domain.ProductType, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *domain.ProductType
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *domain.ProductType); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ProductType)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: ctx, id, description, category, minimumTemperature, maximumTemperature
+func (_m *ProductTypeService) Update(ctx context.Context, id int64, description *string, category *string, minimumTemperature *float64, maximumTemperature *float64) (*domain.ProductType, error) {
+	ret := _m.Called(ctx, id, description, category, minimumTemperature, maximumTemperature)
+
+	var r0 *domain.ProductType
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *string, *string, *float64, *float64) *domain.ProductType); ok {
+		r0 = rf(ctx, id, description, category, minimumTemperature, maximumTemperature)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ProductType)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, *string, *string, *float64, *float64) error); ok {
+		r1 = rf(ctx, id, description, category, minimumTemperature, maximumTemperature)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewProductTypeService interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewProductTypeService creates a new instance of ProductTypeService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewProductTypeService(t mockConstructorTestingTNewProductTypeService) *ProductTypeService {
+	mock := &ProductTypeService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_type/domain"
+)
+
+type mariaDbProductTypeRepository struct {
+	db *sql.DB
+}
+
+func NewMariadbProductTypeRepository(db *sql.DB) domain.ProductTypeRepository {
+	return &mariaDbProductTypeRepository{db: db}
+}
+
+func (m *mariaDbProductTypeRepository) GetAll(ctx context.Context) ([]domain.ProductType, error) {
+	productTypes := []domain.ProductType{}
+
+	rows, err := m.db.QueryContext(ctx, SQLGetAllProductTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var productType domain.ProductType
+
+		if err := rows.Scan(
+			&productType.Id,
+			&productType.Description,
+			&productType.Category,
+			&productType.MinimumTemperature,
+			&productType.MaximumTemperature,
+		); err != nil {
+			return nil, err
+		}
+
+		productTypes = append(productTypes, productType)
+	}
+
+	return productTypes, nil
+}
+
+func (m *mariaDbProductTypeRepository) GetById(ctx context.Context, id int64) (*domain.ProductType, error) {
+	return m.getOne(ctx, SQLGetProductTypeById, id)
+}
+
+func (m *mariaDbProductTypeRepository) GetByDescription(ctx context.Context, description string) (*domain.ProductType, error) {
+	return m.getOne(ctx, SQLGetProductTypeByDescription, description)
+}
+
+func (m *mariaDbProductTypeRepository) getOne(ctx context.Context, query string, arg interface{}) (*domain.ProductType, error) {
+	var productType domain.ProductType
+
+	err := m.db.QueryRowContext(ctx, query, arg).Scan(
+		&productType.Id,
+		&productType.Description,
+		&productType.Category,
+		&productType.MinimumTemperature,
+		&productType.MaximumTemperature,
+	)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrProductTypeNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &productType, nil
+}
+
+func (m *mariaDbProductTypeRepository) Create(ctx context.Context, productType *domain.ProductType) (*domain.ProductType, error) {
+	result, err := m.db.ExecContext(
+		ctx,
+		SQLCreateProductType,
+		productType.Description,
+		productType.Category,
+		productType.MinimumTemperature,
+		productType.MaximumTemperature,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	productType.Id, _ = result.LastInsertId()
+
+	return productType, nil
+}
+
+func (m *mariaDbProductTypeRepository) Update(ctx context.Context, productType *domain.ProductType) (*domain.ProductType, error) {
+	_, err := m.db.ExecContext(
+		ctx,
+		SQLUpdateProductType,
+		productType.Description,
+		productType.Category,
+		productType.MinimumTemperature,
+		productType.MaximumTemperature,
+		productType.Id,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return productType, nil
+}
+
+func (m *mariaDbProductTypeRepository) Delete(ctx context.Context, id int64) error {
+	result, err := m.db.ExecContext(ctx, SQLDeleteProductType, id)
+	if err != nil {
+		return err
+	}
+
+	affected, _ := result.RowsAffected()
+	if affected == 0 {
+		return domain.ErrProductTypeNotFound
+	}
+
+	return nil
+}
+
+func (m *mariaDbProductTypeRepository) CountUsage(ctx context.Context, id int64) (int64, error) {
+	var count int64
+
+	if err := m.db.QueryRowContext(ctx, SQLCountProductTypeUsage, id, id).Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
@@ -0,0 +1,39 @@
+package repository
+
+const (
+	SQLGetAllProductTypes = `
+    SELECT id, description, category, minimum_temperature, maximum_temperature
+    FROM product_types ORDER BY id
+    `
+
+	SQLGetProductTypeById = `
+    SELECT id, description, category, minimum_temperature, maximum_temperature
+    FROM product_types WHERE id = ?
+    `
+
+	SQLGetProductTypeByDescription = `
+    SELECT id, description, category, minimum_temperature, maximum_temperature
+    FROM product_types WHERE description = ?
+    `
+
+	SQLCreateProductType = `
+    INSERT INTO product_types (description, category, minimum_temperature, maximum_temperature)
+    VALUES (?, ?, ?, ?)
+    `
+
+	SQLUpdateProductType = `
+    UPDATE product_types
+    SET description = ?, category = ?, minimum_temperature = ?, maximum_temperature = ?
+    WHERE id = ?
+    `
+
+	SQLDeleteProductType = `
+    DELETE FROM product_types WHERE id = ?
+    `
+
+	SQLCountProductTypeUsage = `
+    SELECT
+        (SELECT COUNT(*) FROM products WHERE product_type_id = ?) +
+        (SELECT COUNT(*) FROM sections WHERE product_type_id = ?)
+    `
+)
@@ -0,0 +1,225 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_type/domain"
+	repository "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_type/repository/mariadb"
+)
+
+var columns = []string{"id", "description", "category", "minimum_temperature", "maximum_temperature"}
+
+var mockProductType = domain.ProductType{
+	Id:                 1,
+	Description:        "Frozen",
+	Category:           domain.CategoryFrozen,
+	MinimumTemperature: -25,
+	MaximumTemperature: -18,
+}
+
+func TestProductTypeRepository_GetAll(t *testing.T) {
+	t.Run("get_all_ok: should return all product types", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		rows := sqlmock.NewRows(columns).
+			AddRow(1, "Frozen", "frozen", -25, -18).
+			AddRow(2, "Refrigerated", "refrigerated", 0, 8)
+		mock.ExpectQuery(regexp.QuoteMeta(repository.SQLGetAllProductTypes)).WillReturnRows(rows)
+
+		productTypeRepository := repository.NewMariadbProductTypeRepository(db)
+
+		result, err := productTypeRepository.GetAll(context.Background())
+
+		assert.NoError(t, err)
+		assert.Len(t, result, 2)
+		assert.Equal(t, mockProductType, result[0])
+	})
+
+	t.Run("get_all_error: should return error when query fails", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta(repository.SQLGetAllProductTypes)).WillReturnError(errors.New("any error"))
+
+		productTypeRepository := repository.NewMariadbProductTypeRepository(db)
+
+		_, err = productTypeRepository.GetAll(context.Background())
+
+		assert.EqualError(t, err, "any error")
+	})
+}
+
+func TestProductTypeRepository_GetById(t *testing.T) {
+	t.Run("get_by_id_ok: should return the product type", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		rows := sqlmock.NewRows(columns).AddRow(1, "Frozen", "frozen", -25, -18)
+		mock.ExpectQuery(regexp.QuoteMeta(repository.SQLGetProductTypeById)).WithArgs(int64(1)).WillReturnRows(rows)
+
+		productTypeRepository := repository.NewMariadbProductTypeRepository(db)
+
+		result, err := productTypeRepository.GetById(context.Background(), 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &mockProductType, result)
+	})
+
+	t.Run("get_by_id_non_existent: should return ErrProductTypeNotFound", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta(repository.SQLGetProductTypeById)).WithArgs(int64(1)).WillReturnError(sql.ErrNoRows)
+
+		productTypeRepository := repository.NewMariadbProductTypeRepository(db)
+
+		_, err = productTypeRepository.GetById(context.Background(), 1)
+
+		assert.ErrorIs(t, err, domain.ErrProductTypeNotFound)
+	})
+}
+
+func TestProductTypeRepository_GetByDescription(t *testing.T) {
+	t.Run("get_by_description_ok: should return the product type", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		rows := sqlmock.NewRows(columns).AddRow(1, "Frozen", "frozen", -25, -18)
+		mock.ExpectQuery(regexp.QuoteMeta(repository.SQLGetProductTypeByDescription)).WithArgs("Frozen").WillReturnRows(rows)
+
+		productTypeRepository := repository.NewMariadbProductTypeRepository(db)
+
+		result, err := productTypeRepository.GetByDescription(context.Background(), "Frozen")
+
+		assert.NoError(t, err)
+		assert.Equal(t, &mockProductType, result)
+	})
+}
+
+func TestProductTypeRepository_Create(t *testing.T) {
+	t.Run("create_ok: should return the product type with its id", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(repository.SQLCreateProductType)).
+			WithArgs("Frozen", "frozen", float64(-25), float64(-18)).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		productTypeRepository := repository.NewMariadbProductTypeRepository(db)
+
+		input := mockProductType
+		input.Id = 0
+		result, err := productTypeRepository.Create(context.Background(), &input)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &mockProductType, result)
+	})
+
+	t.Run("create_error: should return error when query execution fails", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(repository.SQLCreateProductType)).WillReturnError(errors.New("any error"))
+
+		productTypeRepository := repository.NewMariadbProductTypeRepository(db)
+
+		input := mockProductType
+		_, err = productTypeRepository.Create(context.Background(), &input)
+
+		assert.EqualError(t, err, "any error")
+	})
+}
+
+func TestProductTypeRepository_Update(t *testing.T) {
+	t.Run("update_ok: should return the updated product type", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(repository.SQLUpdateProductType)).
+			WithArgs("Frozen", "frozen", float64(-25), float64(-18), int64(1)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		productTypeRepository := repository.NewMariadbProductTypeRepository(db)
+
+		input := mockProductType
+		result, err := productTypeRepository.Update(context.Background(), &input)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &mockProductType, result)
+	})
+
+	t.Run("update_error: should return error when query execution fails", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(repository.SQLUpdateProductType)).WillReturnError(errors.New("any error"))
+
+		productTypeRepository := repository.NewMariadbProductTypeRepository(db)
+
+		input := mockProductType
+		_, err = productTypeRepository.Update(context.Background(), &input)
+
+		assert.EqualError(t, err, "any error")
+	})
+}
+
+func TestProductTypeRepository_Delete(t *testing.T) {
+	t.Run("delete_ok: should delete the product type", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(repository.SQLDeleteProductType)).WithArgs(int64(1)).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		productTypeRepository := repository.NewMariadbProductTypeRepository(db)
+
+		assert.NoError(t, productTypeRepository.Delete(context.Background(), 1))
+	})
+
+	t.Run("delete_non_existent: should return ErrProductTypeNotFound", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(repository.SQLDeleteProductType)).WithArgs(int64(1)).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		productTypeRepository := repository.NewMariadbProductTypeRepository(db)
+
+		assert.ErrorIs(t, productTypeRepository.Delete(context.Background(), 1), domain.ErrProductTypeNotFound)
+	})
+}
+
+func TestProductTypeRepository_CountUsage(t *testing.T) {
+	t.Run("count_usage_ok: should count products and sections of the type", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta(repository.SQLCountProductTypeUsage)).
+			WithArgs(int64(1), int64(1)).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+		productTypeRepository := repository.NewMariadbProductTypeRepository(db)
+
+		count, err := productTypeRepository.CountUsage(context.Background(), 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), count)
+	})
+}
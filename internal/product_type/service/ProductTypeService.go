@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_type/domain"
+)
+
+type service struct {
+	repository domain.ProductTypeRepository
+}
+
+func NewProductTypeService(r domain.ProductTypeRepository) domain.ProductTypeService {
+	return &service{repository: r}
+}
+
+func (s *service) GetAll(ctx context.Context) ([]domain.ProductType, error) {
+	return s.repository.GetAll(ctx)
+}
+
+func (s *service) GetById(ctx context.Context, id int64) (*domain.ProductType, error) {
+	return s.repository.GetById(ctx, id)
+}
+
+func (s *service) Create(ctx context.Context, productType *domain.ProductType) (*domain.ProductType, error) {
+	productType.Description = strings.TrimSpace(productType.Description)
+
+	if err := validate(productType); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkDescription(ctx, productType.Description, 0); err != nil {
+		return nil, err
+	}
+
+	return s.repository.Create(ctx, productType)
+}
+
+func (s *service) Update(
+	ctx context.Context,
+	id int64,
+	description *string,
+	category *string,
+	minimumTemperature *float64,
+	maximumTemperature *float64,
+) (*domain.ProductType, error) {
+	productType, err := s.repository.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if description != nil {
+		productType.Description = strings.TrimSpace(*description)
+	}
+	if category != nil {
+		productType.Category = *category
+	}
+	if minimumTemperature != nil {
+		productType.MinimumTemperature = *minimumTemperature
+	}
+	if maximumTemperature != nil {
+		productType.MaximumTemperature = *maximumTemperature
+	}
+
+	if err := validate(productType); err != nil {
+		return nil, err
+	}
+
+	if description != nil {
+		if err := s.checkDescription(ctx, productType.Description, id); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.repository.Update(ctx, productType)
+}
+
+// Delete refuses to remove a type still referenced by products or sections.
+func (s *service) Delete(ctx context.Context, id int64) error {
+	if _, err := s.repository.GetById(ctx, id); err != nil {
+		return err
+	}
+
+	count, err := s.repository.CountUsage(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if count > 0 {
+		return domain.ErrProductTypeInUse
+	}
+
+	return s.repository.Delete(ctx, id)
+}
+
+// checkDescription fails when another type, other than the one with the
+// given id, already uses the description.
+func (s *service) checkDescription(ctx context.Context, description string, id int64) error {
+	existing, err := s.repository.GetByDescription(ctx, description)
+	if errors.Is(err, domain.ErrProductTypeNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if existing.Id != id {
+		return domain.ErrProductTypeAlreadyExists
+	}
+
+	return nil
+}
+
+func validate(productType *domain.ProductType) error {
+	if productType.Description == "" {
+		return domain.ErrInvalidDescription
+	}
+
+	if !domain.IsCategory(productType.Category) {
+		return domain.ErrInvalidCategory
+	}
+
+	if productType.MinimumTemperature > productType.MaximumTemperature {
+		return domain.ErrInvalidTemperatureRange
+	}
+
+	return nil
+}
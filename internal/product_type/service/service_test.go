@@ -0,0 +1,165 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_type/domain"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_type/domain/mocks"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_type/service"
+)
+
+func newFrozen() *domain.ProductType {
+	return &domain.ProductType{
+		Id:                 1,
+		Description:        "Frozen",
+		Category:           domain.CategoryFrozen,
+		MinimumTemperature: -25,
+		MaximumTemperature: -18,
+	}
+}
+
+func TestProductTypeService_Create(t *testing.T) {
+	t.Run("create_ok: should create a valid product type", func(t *testing.T) {
+		input := newFrozen()
+		input.Id = 0
+		input.Description = " Frozen "
+
+		repository := mocks.NewProductTypeRepository(t)
+		repository.On("GetByDescription", mock.Anything, "Frozen").Return(nil, domain.ErrProductTypeNotFound).Once()
+		repository.On("Create", mock.Anything, mock.AnythingOfType("*domain.ProductType")).Return(newFrozen(), nil).Once()
+
+		result, err := service.NewProductTypeService(repository).Create(context.Background(), input)
+
+		assert.NoError(t, err)
+		assert.Equal(t, newFrozen(), result)
+		assert.Equal(t, "Frozen", input.Description)
+	})
+
+	t.Run("create_conflict: should reject a duplicate description", func(t *testing.T) {
+		repository := mocks.NewProductTypeRepository(t)
+		repository.On("GetByDescription", mock.Anything, "Frozen").Return(newFrozen(), nil).Once()
+
+		input := newFrozen()
+		input.Id = 0
+		_, err := service.NewProductTypeService(repository).Create(context.Background(), input)
+
+		assert.ErrorIs(t, err, domain.ErrProductTypeAlreadyExists)
+	})
+
+	t.Run("create_invalid: should validate description, category and range", func(t *testing.T) {
+		repository := mocks.NewProductTypeRepository(t)
+		productTypeService := service.NewProductTypeService(repository)
+
+		empty := newFrozen()
+		empty.Description = "  "
+		_, err := productTypeService.Create(context.Background(), empty)
+		assert.ErrorIs(t, err, domain.ErrInvalidDescription)
+
+		unknown := newFrozen()
+		unknown.Category = "hot"
+		_, err = productTypeService.Create(context.Background(), unknown)
+		assert.ErrorIs(t, err, domain.ErrInvalidCategory)
+
+		inverted := newFrozen()
+		inverted.MinimumTemperature = 0
+		_, err = productTypeService.Create(context.Background(), inverted)
+		assert.ErrorIs(t, err, domain.ErrInvalidTemperatureRange)
+	})
+}
+
+func TestProductTypeService_Update(t *testing.T) {
+	t.Run("update_ok: should change only the given fields", func(t *testing.T) {
+		maximum := float64(-15)
+
+		expected := newFrozen()
+		expected.MaximumTemperature = maximum
+
+		repository := mocks.NewProductTypeRepository(t)
+		repository.On("GetById", mock.Anything, int64(1)).Return(newFrozen(), nil).Once()
+		repository.On("Update", mock.Anything, expected).Return(expected, nil).Once()
+
+		result, err := service.NewProductTypeService(repository).Update(context.Background(), 1, nil, nil, nil, &maximum)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, result)
+	})
+
+	t.Run("update_same_description: should allow keeping its own description", func(t *testing.T) {
+		description := "Frozen"
+
+		repository := mocks.NewProductTypeRepository(t)
+		repository.On("GetById", mock.Anything, int64(1)).Return(newFrozen(), nil).Once()
+		repository.On("GetByDescription", mock.Anything, "Frozen").Return(newFrozen(), nil).Once()
+		repository.On("Update", mock.Anything, newFrozen()).Return(newFrozen(), nil).Once()
+
+		_, err := service.NewProductTypeService(repository).Update(context.Background(), 1, &description, nil, nil, nil)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("update_conflict: should reject the description of another type", func(t *testing.T) {
+		description := "Refrigerated"
+		other := &domain.ProductType{Id: 2, Description: "Refrigerated", Category: domain.CategoryRefrigerated}
+
+		repository := mocks.NewProductTypeRepository(t)
+		repository.On("GetById", mock.Anything, int64(1)).Return(newFrozen(), nil).Once()
+		repository.On("GetByDescription", mock.Anything, "Refrigerated").Return(other, nil).Once()
+
+		_, err := service.NewProductTypeService(repository).Update(context.Background(), 1, &description, nil, nil, nil)
+
+		assert.ErrorIs(t, err, domain.ErrProductTypeAlreadyExists)
+	})
+
+	t.Run("update_invalid_range: should validate the merged values", func(t *testing.T) {
+		minimum := float64(0)
+
+		repository := mocks.NewProductTypeRepository(t)
+		repository.On("GetById", mock.Anything, int64(1)).Return(newFrozen(), nil).Once()
+
+		_, err := service.NewProductTypeService(repository).Update(context.Background(), 1, nil, nil, &minimum, nil)
+
+		assert.ErrorIs(t, err, domain.ErrInvalidTemperatureRange)
+	})
+
+	t.Run("update_non_existent: should return ErrProductTypeNotFound", func(t *testing.T) {
+		repository := mocks.NewProductTypeRepository(t)
+		repository.On("GetById", mock.Anything, int64(1)).Return(nil, domain.ErrProductTypeNotFound).Once()
+
+		_, err := service.NewProductTypeService(repository).Update(context.Background(), 1, nil, nil, nil, nil)
+
+		assert.ErrorIs(t, err, domain.ErrProductTypeNotFound)
+	})
+}
+
+func TestProductTypeService_Delete(t *testing.T) {
+	t.Run("delete_ok: should delete an unused type", func(t *testing.T) {
+		repository := mocks.NewProductTypeRepository(t)
+		repository.On("GetById", mock.Anything, int64(1)).Return(newFrozen(), nil).Once()
+		repository.On("CountUsage", mock.Anything, int64(1)).Return(int64(0), nil).Once()
+		repository.On("Delete", mock.Anything, int64(1)).Return(nil).Once()
+
+		assert.NoError(t, service.NewProductTypeService(repository).Delete(context.Background(), 1))
+	})
+
+	t.Run("delete_in_use: should refuse to delete a type in use", func(t *testing.T) {
+		repository := mocks.NewProductTypeRepository(t)
+		repository.On("GetById", mock.Anything, int64(1)).Return(newFrozen(), nil).Once()
+		repository.On("CountUsage", mock.Anything, int64(1)).Return(int64(2), nil).Once()
+
+		err := service.NewProductTypeService(repository).Delete(context.Background(), 1)
+
+		assert.ErrorIs(t, err, domain.ErrProductTypeInUse)
+	})
+
+	t.Run("delete_non_existent: should return ErrProductTypeNotFound", func(t *testing.T) {
+		repository := mocks.NewProductTypeRepository(t)
+		repository.On("GetById", mock.Anything, int64(1)).Return(nil, domain.ErrProductTypeNotFound).Once()
+
+		err := service.NewProductTypeService(repository).Delete(context.Background(), 1)
+
+		assert.ErrorIs(t, err, domain.ErrProductTypeNotFound)
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/purchase_orders/domain"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/libs/outbox"
 )
 
 type mariadbPurchaseOrdersRepository struct {
@@ -28,7 +29,12 @@ func (repository *mariadbPurchaseOrdersRepository) Create(ctx context.Context, o
 		OrderStatusId:   orderStatusId,
 	}
 
-	newPurchaseOrders, err := repository.db.ExecContext(
+	tx, err := repository.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	newPurchaseOrders, err := tx.ExecContext(
 		ctx,
 		SQLCreatePurchaseOrders,
 		purchaseOrders.OrderNumber,
@@ -39,12 +45,22 @@ func (repository *mariadbPurchaseOrdersRepository) Create(ctx context.Context, o
 		purchaseOrders.OrderStatusId,
 	)
 	if err != nil {
+		tx.Rollback()
 		return nil, err
 	}
 
 	id, _ := newPurchaseOrders.LastInsertId()
 	purchaseOrders.Id = id
 
+	if err := outbox.Publish(ctx, tx, outbox.PurchaseOrderCreated, id, purchaseOrders); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
 	return &purchaseOrders, nil
 }
 
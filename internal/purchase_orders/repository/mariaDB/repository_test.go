@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/purchase_orders/domain"
 	repository "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/purchase_orders/repository/mariaDB"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/libs/outbox"
 )
 
 var expectedPurchaseOrders = domain.PurchaseOrders{
@@ -40,6 +41,7 @@ func TestPurchaseOrderRepository_Create(t *testing.T) {
 		assert.NoError(t, err)
 		defer db.Close()
 
+		mock.ExpectBegin()
 		mock.ExpectExec(regexp.QuoteMeta(repository.SQLCreatePurchaseOrders)).WithArgs(
 			mockPurchaseOrders.OrderNumber,
 			mockPurchaseOrders.OrderDate,
@@ -48,6 +50,10 @@ func TestPurchaseOrderRepository_Create(t *testing.T) {
 			mockPurchaseOrders.ProductRecordId,
 			mockPurchaseOrders.OrderStatusId,
 		).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(regexp.QuoteMeta(outbox.SQLCreateOutboxEvent)).
+			WithArgs(outbox.PurchaseOrderCreated, mockPurchaseOrders.Id, nil, sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
 
 		purchaseOrdersRepository := repository.NewMariadbPurchaseOrdersRepository(db)
 
@@ -62,6 +68,7 @@ func TestPurchaseOrderRepository_Create(t *testing.T) {
 
 		assert.NoError(t, err)
 		assert.Equal(t, newPurchaseOrders, mockPurchaseOrders)
+		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
 	t.Run("create_error: should return error when query execution fails", func(t *testing.T) {
@@ -69,6 +76,7 @@ func TestPurchaseOrderRepository_Create(t *testing.T) {
 		assert.NoError(t, err)
 		defer db.Close()
 
+		mock.ExpectBegin()
 		mock.ExpectExec(regexp.QuoteMeta(repository.SQLCreatePurchaseOrders)).WithArgs(
 			mockPurchaseOrders.OrderNumber,
 			mockPurchaseOrders.OrderDate,
@@ -77,6 +85,7 @@ func TestPurchaseOrderRepository_Create(t *testing.T) {
 			mockPurchaseOrders.ProductRecordId,
 			mockPurchaseOrders.OrderStatusId,
 		).WillReturnError(fmt.Errorf("erro"))
+		mock.ExpectRollback()
 
 		purchaseOrdersRepository := repository.NewMariadbPurchaseOrdersRepository(db)
 
@@ -94,6 +103,34 @@ func TestPurchaseOrderRepository_Create(t *testing.T) {
 
 	})
 
+	t.Run("create_error_outbox: should rollback when the domain event cannot be written", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(repository.SQLCreatePurchaseOrders)).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(regexp.QuoteMeta(outbox.SQLCreateOutboxEvent)).
+			WillReturnError(fmt.Errorf("erro"))
+		mock.ExpectRollback()
+
+		purchaseOrdersRepository := repository.NewMariadbPurchaseOrdersRepository(db)
+
+		newPurchaseOrders, err := purchaseOrdersRepository.Create(context.Background(),
+			mockPurchaseOrders.OrderNumber,
+			mockPurchaseOrders.OrderDate,
+			mockPurchaseOrders.TrackingCode,
+			mockPurchaseOrders.BuyerId,
+			mockPurchaseOrders.ProductRecordId,
+			mockPurchaseOrders.OrderStatusId,
+		)
+
+		assert.Error(t, err)
+		assert.Empty(t, newPurchaseOrders)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
 }
 
 func TestPurchaseOrderRepository_ContByBuyerId(t *testing.T) {
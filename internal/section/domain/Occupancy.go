@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// OccupancyFilter narrows the occupancy report down. Zero ids match every
+// warehouse and product type.
+type OccupancyFilter struct {
+	WarehouseId   int64
+	ProductTypeId int64
+}
+
+// SectionOccupancy is a row of the occupancy report. Only batches that
+// still hold stock and are not discarded count as products and batches.
+type SectionOccupancy struct {
+	WarehouseId      int64      `json:"warehouse_id"`
+	WarehouseCode    string     `json:"warehouse_code"`
+	SectionId        int64      `json:"section_id"`
+	SectionNumber    int64      `json:"section_number"`
+	ProductTypeId    int64      `json:"product_type_id"`
+	MaximumCapacity  int64      `json:"maximum_capacity"`
+	MinimumCapacity  int64      `json:"minimum_capacity"`
+	CurrentCapacity  int64      `json:"current_capacity"`
+	Utilization      float64    `json:"utilization"`
+	BelowMinimum     bool       `json:"below_minimum"`
+	DistinctProducts int64      `json:"distinct_products"`
+	Batches          int64      `json:"batches"`
+	NearestDueDate   *time.Time `json:"nearest_due_date"`
+}
+
+// Summarize fills the fields derived from the capacities. Utilization is
+// the percentage of the maximum capacity in use, to two decimal places.
+func (o *SectionOccupancy) Summarize() {
+	o.Utilization = 0
+	if o.MaximumCapacity > 0 {
+		o.Utilization = math.Round(float64(o.CurrentCapacity)/float64(o.MaximumCapacity)*10000) / 100
+	}
+	o.BelowMinimum = o.CurrentCapacity < o.MinimumCapacity
+}
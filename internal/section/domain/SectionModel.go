@@ -1,17 +1,98 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"fmt"
+	"time"
+)
 
 type SectionModel struct {
-	Id                 int64   `json:"id"`
-	SectionNumber      int64   `json:"section_number"`
-	CurrentTemperature float64 `json:"current_temperature"`
-	MinimumTemperature float64 `json:"minimum_temperature"`
-	CurrentCapacity    int64   `json:"current_capacity"`
-	MinimumCapacity    int64   `json:"minimum_capacity"`
-	MaximumCapacity    int64   `json:"maximum_capacity"`
-	WarehouseId        int64   `json:"warehouse_id"`
-	ProductTypeId      int64   `json:"product_type_id"`
+	Id                 int64      `json:"id"`
+	SectionNumber      int64      `json:"section_number"`
+	CurrentTemperature float64    `json:"current_temperature"`
+	MinimumTemperature float64    `json:"minimum_temperature"`
+	CurrentCapacity    int64      `json:"current_capacity"`
+	MinimumCapacity    int64      `json:"minimum_capacity"`
+	MaximumCapacity    int64      `json:"maximum_capacity"`
+	WarehouseId        int64      `json:"warehouse_id"`
+	ProductTypeId      int64      `json:"product_type_id"`
+	ArchivedAt         *time.Time `json:"archived_at,omitempty"`
+}
+
+// SectionBatch is a product batch stored in a section, as listed when the
+// section cannot be deleted.
+type SectionBatch struct {
+	Id              int64  `json:"id"`
+	BatchNumber     int64  `json:"batch_number"`
+	ProductId       int64  `json:"product_id"`
+	Status          string `json:"status"`
+	CurrentQuantity int64  `json:"current_quantity"`
+}
+
+// HoldsStock tells whether the batch still has units in the section.
+// Discarded and emptied batches only keep history.
+func (b SectionBatch) HoldsStock() bool {
+	return b.Status != "discarded" && b.CurrentQuantity > 0
+}
+
+// SectionPatch holds the section fields to change. Nil fields are left
+// untouched. The current capacity is not patchable: it follows the batches
+// stored in the section.
+type SectionPatch struct {
+	SectionNumber      *int64
+	CurrentTemperature *float64
+	MinimumTemperature *float64
+	MinimumCapacity    *int64
+	MaximumCapacity    *int64
+	WarehouseId        *int64
+	ProductTypeId      *int64
+}
+
+// Apply copies the fields set in the patch onto s.
+func (p SectionPatch) Apply(s *SectionModel) {
+	if p.SectionNumber != nil {
+		s.SectionNumber = *p.SectionNumber
+	}
+	if p.CurrentTemperature != nil {
+		s.CurrentTemperature = *p.CurrentTemperature
+	}
+	if p.MinimumTemperature != nil {
+		s.MinimumTemperature = *p.MinimumTemperature
+	}
+	if p.MinimumCapacity != nil {
+		s.MinimumCapacity = *p.MinimumCapacity
+	}
+	if p.MaximumCapacity != nil {
+		s.MaximumCapacity = *p.MaximumCapacity
+	}
+	if p.WarehouseId != nil {
+		s.WarehouseId = *p.WarehouseId
+	}
+	if p.ProductTypeId != nil {
+		s.ProductTypeId = *p.ProductTypeId
+	}
+}
+
+// Relocates tells whether the patch moves s to another warehouse or product
+// type, which is only allowed while the section holds no stock.
+func (p SectionPatch) Relocates(s SectionModel) bool {
+	return (p.WarehouseId != nil && *p.WarehouseId != s.WarehouseId) ||
+		(p.ProductTypeId != nil && *p.ProductTypeId != s.ProductTypeId)
+}
+
+// Validate checks the section number and the capacity invariants. A section
+// may hold less than its minimum capacity, which the occupancy report flags.
+// Section numbers are also unique per warehouse, which the database enforces.
+func (s SectionModel) Validate() error {
+	if s.SectionNumber <= 0 {
+		return ErrInvalidSectionNumber
+	}
+	if s.MinimumCapacity < 0 || s.MinimumCapacity > s.MaximumCapacity ||
+		s.CurrentCapacity < 0 || s.CurrentCapacity > s.MaximumCapacity {
+		return fmt.Errorf("%w: got minimum %d, current %d, maximum %d",
+			ErrInvalidCapacity, s.MinimumCapacity, s.CurrentCapacity, s.MaximumCapacity)
+	}
+	return nil
 }
 
 type ReportProductsModel struct {
@@ -22,8 +103,12 @@ type ReportProductsModel struct {
 
 type SectionRepository interface {
 	Delete(ctx context.Context, id int64) error
-	UpdateCurrentCapacity(ctx context.Context, section *SectionModel) (*SectionModel, error)
+	Archive(ctx context.Context, id int64) error
+	Restore(ctx context.Context, id int64) error
+	GetBatches(ctx context.Context, id int64) ([]SectionBatch, error)
+	Update(ctx context.Context, id int64, patch SectionPatch) (*SectionModel, error)
 	GetById(ctx context.Context, id int64) (SectionModel, error)
+	GetByNumber(ctx context.Context, warehouseCode string, sectionNumber int64) (SectionModel, error)
 	GetAll(ctx context.Context) ([]SectionModel, error)
 	Create(
 		ctx context.Context,
@@ -37,11 +122,14 @@ type SectionRepository interface {
 		productTypeId int64) (SectionModel, error)
 	GetAllProductCountBySection(ctx context.Context) (*[]ReportProductsModel, error)
 	GetByIdProductCountBySection(ctx context.Context, id int64) (*ReportProductsModel, error)
+	GetOccupancy(ctx context.Context, filter OccupancyFilter) ([]SectionOccupancy, error)
 }
 
 type SectionService interface {
 	Delete(ctx context.Context, id int64) error
-	UpdateCurrentCapacity(ctx context.Context, id int64, currentCapacity int64) (*SectionModel, error)
+	Archive(ctx context.Context, id int64) error
+	Restore(ctx context.Context, id int64) (SectionModel, error)
+	Update(ctx context.Context, id int64, patch SectionPatch) (*SectionModel, error)
 	Create(
 		ctx context.Context,
 		sectionNumber int64,
@@ -53,7 +141,9 @@ type SectionService interface {
 		warehouseId int64,
 		productTypeId int64) (SectionModel, error)
 	GetById(ctx context.Context, id int64) (SectionModel, error)
+	GetByNumber(ctx context.Context, warehouseCode string, sectionNumber int64) (SectionModel, error)
 	GetAll(ctx context.Context) ([]SectionModel, error)
 	GetAllProductCountBySection(ctx context.Context) (*[]ReportProductsModel, error)
 	GetByIdProductCountBySection(ctx context.Context, id int64) (*ReportProductsModel, error)
+	GetOccupancy(ctx context.Context, filter OccupancyFilter) ([]SectionOccupancy, error)
 }
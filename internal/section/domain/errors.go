@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrSectionNotFound      = errors.New("section not found")
+	ErrSectionNumberTaken   = errors.New("section number already exists in the warehouse")
+	ErrInvalidSectionNumber = errors.New("section number must be positive")
+	ErrInvalidCapacity      = errors.New("capacities must satisfy 0 <= minimum <= maximum and 0 <= current <= maximum")
+	ErrInvalidSectionLookup = errors.New("warehouse_code and a positive section_number are required")
+	ErrSectionInUse         = errors.New("section holds product batches")
+	ErrSectionHoldsStock    = errors.New("section holds stock, its warehouse and product type cannot change")
+	ErrCapacityReadOnly     = errors.New("current_capacity follows the product batches and cannot be set")
+	ErrSectionHasHistory    = errors.New("section has stock movements, archive it instead")
+	ErrSectionArchived      = errors.New("section is archived")
+	ErrSectionNotArchived   = errors.New("section is not archived")
+	ErrInvalidDeleteMode    = errors.New("mode must be delete or archive")
+)
+
+// SectionInUseError lists the batches that keep a section from being
+// deleted or archived.
+type SectionInUseError struct {
+	Batches []SectionBatch
+}
+
+func (e *SectionInUseError) Error() string {
+	return fmt.Sprintf("%s: %d", ErrSectionInUse, len(e.Batches))
+}
+
+func (e *SectionInUseError) Unwrap() error {
+	return ErrSectionInUse
+}
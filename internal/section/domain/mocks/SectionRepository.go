@@ -14,6 +14,20 @@ type SectionRepository struct {
 	mock.Mock
 }
 
+// Archive provides a mock function with given fields: ctx, id
+func (_m *SectionRepository) Archive(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Create provides a mock function with given fields: ctx, sectionNumber, currentTemperature, minimumTemperature, currentCapacity, minimumCapacity, maximumCapacity, warehouseId, productTypeId
 func (_m *SectionRepository) Create(ctx context.Context, sectionNumber int64, currentTemperature float64, minimumTemperature float64, currentCapacity int64, minimumCapacity int64, maximumCapacity int64, warehouseId int64, productTypeId int64) (domain.SectionModel, error) {
 	ret := _m.Called(ctx, sectionNumber, currentTemperature, minimumTemperature, currentCapacity, minimumCapacity, maximumCapacity, warehouseId, productTypeId)
@@ -95,6 +109,29 @@ func (_m *SectionRepository) GetAllProductCountBySection(ctx context.Context) (*
 	return r0, r1
 }
 
+// GetBatches provides a mock function with given fields: ctx, id
+func (_m *SectionRepository) GetBatches(ctx context.Context, id int64) ([]domain.SectionBatch, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 []domain.SectionBatch
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []domain.SectionBatch); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.SectionBatch)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetById provides a mock function with given fields: ctx, id
 func (_m *SectionRepository) GetById(ctx context.Context, id int64) (domain.SectionModel, error) {
 	ret := _m.Called(ctx, id)
@@ -139,13 +176,71 @@ func (_m *SectionRepository) GetByIdProductCountBySection(ctx context.Context, i
 	return r0, r1
 }
 
-// UpdateCurrentCapacity provides a mock function with given fields: ctx, section
-func (_m *SectionRepository) UpdateCurrentCapacity(ctx context.Context, section *domain.SectionModel) (*domain.SectionModel, error) {
-	ret := _m.Called(ctx, section)
+// GetByNumber provides a mock function with given fields: ctx, warehouseCode, sectionNumber
+func (_m *SectionRepository) GetByNumber(ctx context.Context, warehouseCode string, sectionNumber int64) (domain.SectionModel, error) {
+	ret := _m.Called(ctx, warehouseCode, sectionNumber)
+
+	var r0 domain.SectionModel
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) domain.SectionModel); ok {
+		r0 = rf(ctx, warehouseCode, sectionNumber)
+	} else {
+		r0 = ret.Get(0).(domain.SectionModel)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64) error); ok {
+		r1 = rf(ctx, warehouseCode, sectionNumber)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOccupancy provides a mock function with given fields: ctx, filter
+func (_m *SectionRepository) GetOccupancy(ctx context.Context, filter domain.OccupancyFilter) ([]domain.SectionOccupancy, error) {
+	ret := _m.Called(ctx, filter)
+
+	var r0 []domain.SectionOccupancy
+	if rf, ok := ret.Get(0).(func(context.Context, domain.OccupancyFilter) []domain.SectionOccupancy); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.SectionOccupancy)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, domain.OccupancyFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Restore provides a mock function with given fields: ctx, id
+func (_m *SectionRepository) Restore(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Update provides a mock function with given fields: ctx, id, patch
+func (_m *SectionRepository) Update(ctx context.Context, id int64, patch domain.SectionPatch) (*domain.SectionModel, error) {
+	ret := _m.Called(ctx, id, patch)
 
 	var r0 *domain.SectionModel
-	if rf, ok := ret.Get(0).(func(context.Context, *domain.SectionModel) *domain.SectionModel); ok {
-		r0 = rf(ctx, section)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, domain.SectionPatch) *domain.SectionModel); ok {
+		r0 = rf(ctx, id, patch)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*domain.SectionModel)
@@ -153,8 +248,8 @@ func (_m *SectionRepository) UpdateCurrentCapacity(ctx context.Context, section
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, *domain.SectionModel) error); ok {
-		r1 = rf(ctx, section)
+	if rf, ok := ret.Get(1).(func(context.Context, int64, domain.SectionPatch) error); ok {
+		r1 = rf(ctx, id, patch)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -14,6 +14,20 @@ type SectionService struct {
 	mock.Mock
 }
 
+// Archive provides a mock function with given fields: ctx, id
+func (_m *SectionService) Archive(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Create provides a mock function with given fields: ctx, sectionNumber, currentTemperature, minimumTemperature, currentCapacity, minimumCapacity, maximumCapacity, warehouseId, productTypeId
 func (_m *SectionService) Create(ctx context.Context, sectionNumber int64, currentTemperature float64, minimumTemperature float64, currentCapacity int64, minimumCapacity int64, maximumCapacity int64, warehouseId int64, productTypeId int64) (domain.SectionModel, error) {
 	ret := _m.Called(ctx, sectionNumber, currentTemperature, minimumTemperature, currentCapacity, minimumCapacity, maximumCapacity, warehouseId, productTypeId)
@@ -139,13 +153,78 @@ func (_m *SectionService) GetByIdProductCountBySection(ctx context.Context, id i
 	return r0, r1
 }
 
-// UpdateCurrentCapacity provides a mock function with given fields: ctx, id, currentCapacity
-func (_m *SectionService) UpdateCurrentCapacity(ctx context.Context, id int64, currentCapacity int64) (*domain.SectionModel, error) {
-	ret := _m.Called(ctx, id, currentCapacity)
+// GetByNumber provides a mock function with given fields: ctx, warehouseCode, sectionNumber
+func (_m *SectionService) GetByNumber(ctx context.Context, warehouseCode string, sectionNumber int64) (domain.SectionModel, error) {
+	ret := _m.Called(ctx, warehouseCode, sectionNumber)
+
+	var r0 domain.SectionModel
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) domain.SectionModel); ok {
+		r0 = rf(ctx, warehouseCode, sectionNumber)
+	} else {
+		r0 = ret.Get(0).(domain.SectionModel)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64) error); ok {
+		r1 = rf(ctx, warehouseCode, sectionNumber)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOccupancy provides a mock function with given fields: ctx, filter
+func (_m *SectionService) GetOccupancy(ctx context.Context, filter domain.OccupancyFilter) ([]domain.SectionOccupancy, error) {
+	ret := _m.Called(ctx, filter)
+
+	var r0 []domain.SectionOccupancy
+	if rf, ok := ret.Get(0).(func(context.Context, domain.OccupancyFilter) []domain.SectionOccupancy); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.SectionOccupancy)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, domain.OccupancyFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Restore provides a mock function with given fields: ctx, id
+func (_m *SectionService) Restore(ctx context.Context, id int64) (domain.SectionModel, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 domain.SectionModel
+	if rf, ok := ret.Get(0).(func(context.Context, int64) domain.SectionModel); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(domain.SectionModel)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: ctx, id, patch
+func (_m *SectionService) Update(ctx context.Context, id int64, patch domain.SectionPatch) (*domain.SectionModel, error) {
+	ret := _m.Called(ctx, id, patch)
 
 	var r0 *domain.SectionModel
-	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) *domain.SectionModel); ok {
-		r0 = rf(ctx, id, currentCapacity)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, domain.SectionPatch) *domain.SectionModel); ok {
+		r0 = rf(ctx, id, patch)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*domain.SectionModel)
@@ -153,8 +232,8 @@ func (_m *SectionService) UpdateCurrentCapacity(ctx context.Context, id int64, c
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
-		r1 = rf(ctx, id, currentCapacity)
+	if rf, ok := ret.Get(1).(func(context.Context, int64, domain.SectionPatch) error); ok {
+		r1 = rf(ctx, id, patch)
 	} else {
 		r1 = ret.Error(1)
 	}
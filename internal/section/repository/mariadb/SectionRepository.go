@@ -5,40 +5,161 @@ import (
 	"database/sql"
 	"errors"
 
+	"github.com/go-sql-driver/mysql"
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/section/domain"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/libs/outbox"
+)
+
+// MariaDB error numbers. Section numbers are unique per warehouse, and the
+// stock ledger keeps a section from being deleted once stock went through.
+const (
+	errDuplicateEntry = 1062
+	errRowReferenced  = 1451
 )
 
 type mariaDbSectionRepository struct {
 	db *sql.DB
 }
 
+// querier runs reads on the database or inside a transaction.
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
 func NewMariadbSectionRepository(db *sql.DB) domain.SectionRepository {
 	return &mariaDbSectionRepository{db: db}
 }
 
 func (m *mariaDbSectionRepository) Delete(ctx context.Context, id int64) error {
-	result, err := m.db.ExecContext(ctx, SQLDeleteSection, id)
+	return m.exec(ctx, SQLDeleteSection, id)
+}
+
+func (m *mariaDbSectionRepository) Archive(ctx context.Context, id int64) error {
+	return m.exec(ctx, SQLArchiveSection, id)
+}
+
+func (m *mariaDbSectionRepository) Restore(ctx context.Context, id int64) error {
+	return m.exec(ctx, SQLRestoreSection, id)
+}
+
+func (m *mariaDbSectionRepository) exec(ctx context.Context, query string, id int64) error {
+	result, err := m.db.ExecContext(ctx, query, id)
 	if err != nil {
-		return err
+		return mapError(err)
 	}
 
 	rowsAffected, _ := result.RowsAffected()
 	if rowsAffected == 0 {
-		return errors.New("section not found")
+		return domain.ErrSectionNotFound
 	}
 
 	return nil
 }
 
-func (m *mariaDbSectionRepository) UpdateCurrentCapacity(ctx context.Context, section *domain.SectionModel) (*domain.SectionModel, error) {
-	_, err := m.db.ExecContext(ctx, SQLUpdateCurrentCapacitySection, &section.CurrentCapacity, &section.Id)
+func (m *mariaDbSectionRepository) GetBatches(ctx context.Context, id int64) ([]domain.SectionBatch, error) {
+	return getBatches(ctx, m.db, id)
+}
+
+func getBatches(ctx context.Context, q querier, id int64) ([]domain.SectionBatch, error) {
+	batches := []domain.SectionBatch{}
+
+	rows, err := q.QueryContext(ctx, SQLGetSectionBatches, id)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var batch domain.SectionBatch
+
+		err := rows.Scan(
+			&batch.Id,
+			&batch.BatchNumber,
+			&batch.ProductId,
+			&batch.Status,
+			&batch.CurrentQuantity,
+		)
+		if err != nil {
+			return nil, err
+		}
+		batches = append(batches, batch)
+	}
+
+	return batches, rows.Err()
+}
+
+// Update applies the patch to the section row locked for the transaction.
+// Batch writes adjust current_capacity on that same row, so none can commit
+// between reading the capacities and validating the patched section.
+func (m *mariaDbSectionRepository) Update(ctx context.Context, id int64, patch domain.SectionPatch) (*domain.SectionModel, error) {
+	tx, err := m.db.BeginTx(ctx, nil)
 	if err != nil {
 		return nil, err
 	}
 
+	section, err := m.update(ctx, tx, id, patch)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
 	return section, nil
 }
 
+func (m *mariaDbSectionRepository) update(ctx context.Context, tx *sql.Tx, id int64, patch domain.SectionPatch) (*domain.SectionModel, error) {
+	section, err := getSection(ctx, tx, SQLLockSection, id)
+	if err != nil {
+		return nil, err
+	}
+
+	relocates := patch.Relocates(section)
+	patch.Apply(&section)
+
+	if err := section.Validate(); err != nil {
+		return nil, err
+	}
+
+	if relocates {
+		batches, err := getBatches(ctx, tx, id)
+		if err != nil {
+			return nil, err
+		}
+		for _, batch := range batches {
+			if batch.HoldsStock() {
+				return nil, domain.ErrSectionHoldsStock
+			}
+		}
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		SQLUpdateSection,
+		section.SectionNumber,
+		section.CurrentTemperature,
+		section.MinimumTemperature,
+		section.MinimumCapacity,
+		section.MaximumCapacity,
+		section.WarehouseId,
+		section.ProductTypeId,
+		section.Id,
+	)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	if err := outbox.PublishWarehouseEvent(ctx, tx, outbox.SectionCapacityChanged, section.Id, section.WarehouseId, section); err != nil {
+		return nil, err
+	}
+
+	return &section, nil
+}
+
 func (m *mariaDbSectionRepository) Create(ctx context.Context, sectionNumber int64, currentTemperature float64, minimumTemperature float64, currentCapacity int64, minimumCapacity int64, maximumCapacity int64, warehouseId int64, productTypeId int64) (domain.SectionModel, error) {
 	section, err := m.db.ExecContext(
 		ctx,
@@ -54,7 +175,7 @@ func (m *mariaDbSectionRepository) Create(ctx context.Context, sectionNumber int
 	)
 
 	if err != nil {
-		return domain.SectionModel{}, err
+		return domain.SectionModel{}, mapError(err)
 	}
 
 	newSectionId, _ := section.LastInsertId()
@@ -73,10 +194,18 @@ func (m *mariaDbSectionRepository) Create(ctx context.Context, sectionNumber int
 }
 
 func (m *mariaDbSectionRepository) GetById(ctx context.Context, id int64) (domain.SectionModel, error) {
+	return getSection(ctx, m.db, SQLGetByIdSection, id)
+}
 
-	row := m.db.QueryRowContext(ctx, SQLGetByIdSection, id)
+func (m *mariaDbSectionRepository) GetByNumber(ctx context.Context, warehouseCode string, sectionNumber int64) (domain.SectionModel, error) {
+	return getSection(ctx, m.db, SQLGetByNumberSection, warehouseCode, sectionNumber)
+}
+
+func getSection(ctx context.Context, q querier, query string, args ...any) (domain.SectionModel, error) {
+	row := q.QueryRowContext(ctx, query, args...)
 
 	var section domain.SectionModel
+	var archivedAt sql.NullTime
 
 	err := row.Scan(
 		&section.Id,
@@ -88,16 +217,21 @@ func (m *mariaDbSectionRepository) GetById(ctx context.Context, id int64) (domai
 		&section.MaximumCapacity,
 		&section.WarehouseId,
 		&section.ProductTypeId,
+		&archivedAt,
 	)
 
 	if errors.Is(err, sql.ErrNoRows) {
-		return section, errors.New("section not found")
+		return section, domain.ErrSectionNotFound
 	}
 
 	if err != nil {
 		return section, err
 	}
 
+	if archivedAt.Valid {
+		section.ArchivedAt = &archivedAt.Time
+	}
+
 	return section, nil
 }
 
@@ -144,7 +278,7 @@ func (m *mariaDbSectionRepository) GetByIdProductCountBySection(ctx context.Cont
 		&reportProducts.ProductsCount,
 	)
 	if errors.Is(err, sql.ErrNoRows) {
-		return nil, errors.New("section not found")
+		return nil, domain.ErrSectionNotFound
 	}
 
 	if err != nil {
@@ -179,3 +313,61 @@ func (m *mariaDbSectionRepository) GetAllProductCountBySection(ctx context.Conte
 	}
 	return &reportProducts, nil
 }
+
+func (m *mariaDbSectionRepository) GetOccupancy(ctx context.Context, filter domain.OccupancyFilter) ([]domain.SectionOccupancy, error) {
+	occupancy := []domain.SectionOccupancy{}
+
+	rows, err := m.db.QueryContext(
+		ctx,
+		SQLGetSectionOccupancy,
+		filter.WarehouseId, filter.WarehouseId,
+		filter.ProductTypeId, filter.ProductTypeId,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var section domain.SectionOccupancy
+		var nearestDueDate sql.NullTime
+
+		err := rows.Scan(
+			&section.WarehouseId,
+			&section.WarehouseCode,
+			&section.SectionId,
+			&section.SectionNumber,
+			&section.ProductTypeId,
+			&section.MaximumCapacity,
+			&section.MinimumCapacity,
+			&section.CurrentCapacity,
+			&section.DistinctProducts,
+			&section.Batches,
+			&nearestDueDate,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if nearestDueDate.Valid {
+			section.NearestDueDate = &nearestDueDate.Time
+		}
+		occupancy = append(occupancy, section)
+	}
+
+	return occupancy, rows.Err()
+}
+
+func mapError(err error) error {
+	var mysqlErr *mysql.MySQLError
+	if !errors.As(err, &mysqlErr) {
+		return err
+	}
+	switch mysqlErr.Number {
+	case errDuplicateEntry:
+		return domain.ErrSectionNumberTaken
+	case errRowReferenced:
+		return domain.ErrSectionHasHistory
+	}
+	return err
+}
@@ -13,6 +13,7 @@ const (
         warehouse_id,
         product_type_id 
     FROM sections
+    WHERE archived_at IS NULL
     `
 
 	SQLGetByIdSection = `
@@ -25,10 +26,15 @@ const (
         minimum_capacity,
         maximum_capacity,
         warehouse_id,
-        product_type_id
+        product_type_id,
+        archived_at
     FROM sections WHERE id=?
     `
 
+	// Batch writes change current_capacity on the same row, so they wait
+	// while a section is being patched.
+	SQLLockSection = SQLGetByIdSection + "FOR UPDATE"
+
 	SQLCreateSection = `
     INSERT INTO
     sections (
@@ -43,13 +49,49 @@ const (
     )
     VALUES (?, ?, ?, ?, ?, ?, ?, ?)
     `
-	SQLUpdateCurrentCapacitySection = `
+	SQLGetByNumberSection = `
+    SELECT
+        s.id,
+        s.section_number,
+        s.current_temperature,
+        s.minimum_temperature,
+        s.current_capacity,
+        s.minimum_capacity,
+        s.maximum_capacity,
+        s.warehouse_id,
+        s.product_type_id,
+        s.archived_at
+    FROM sections s
+    JOIN warehouses w ON w.id = s.warehouse_id
+    WHERE w.warehouse_code = ? AND s.section_number = ?
+    `
+
+	SQLUpdateSection = `
     UPDATE sections
-    SET current_capacity=?
+    SET
+        section_number=?,
+        current_temperature=?,
+        minimum_temperature=?,
+        minimum_capacity=?,
+        maximum_capacity=?,
+        warehouse_id=?,
+        product_type_id=?
     WHERE id=?
     `
 	SQLDeleteSection = "DELETE FROM sections WHERE id=?"
 
+	// Archived sections keep their batches, movements and temperatures but
+	// are left out of the listings and reports.
+	SQLArchiveSection = "UPDATE sections SET archived_at = NOW(6) WHERE id=?"
+	SQLRestoreSection = "UPDATE sections SET archived_at = NULL WHERE id=?"
+
+	SQLGetSectionBatches = `
+    SELECT id, batch_number, product_id, status, current_quantity
+    FROM product_batches
+    WHERE section_id = ?
+    ORDER BY id
+    `
+
 	SQLCountProductsBySectionWithSectionId = `
     SELECT s.id, s.section_number, 
     IFNULL(SUM(pb.current_quantity), 0) products_count  
@@ -66,6 +108,33 @@ const (
     FROM sections s
     LEFT JOIN product_batches pb
     ON s.id = pb.section_id 
+    WHERE s.archived_at IS NULL
     GROUP BY s.id
+    `
+
+	// SQLGetSectionOccupancy lists the sections with the batches that still
+	// hold stock. Discarded and emptied batches are left out of the counts.
+	SQLGetSectionOccupancy = `
+    SELECT
+        s.warehouse_id,
+        w.warehouse_code,
+        s.id,
+        s.section_number,
+        s.product_type_id,
+        s.maximum_capacity,
+        s.minimum_capacity,
+        s.current_capacity,
+        COUNT(DISTINCT pb.product_id),
+        COUNT(pb.id),
+        MIN(pb.due_date)
+    FROM sections s
+    JOIN warehouses w ON w.id = s.warehouse_id
+    LEFT JOIN product_batches pb
+        ON pb.section_id = s.id AND pb.status <> 'discarded' AND pb.current_quantity > 0
+    WHERE s.archived_at IS NULL
+    AND (? = 0 OR s.warehouse_id = ?)
+    AND (? = 0 OR s.product_type_id = ?)
+    GROUP BY s.id, w.warehouse_code
+    ORDER BY w.warehouse_code, s.section_number
     `
 )
@@ -3,14 +3,18 @@ package repository_test
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"errors"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
 	"github.com/stretchr/testify/assert"
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/section/domain"
 	repository "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/section/repository/mariadb"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/libs/outbox"
 )
 
 var mockSection = domain.SectionModel{
@@ -172,6 +176,7 @@ func TestSectionRepository_GetById(t *testing.T) {
 			"maximumCapacity",
 			"warehouseId",
 			"productTypeId",
+			"archivedAt",
 		}).AddRow(
 			mockSection.Id,
 			mockSection.SectionNumber,
@@ -182,6 +187,7 @@ func TestSectionRepository_GetById(t *testing.T) {
 			mockSection.MaximumCapacity,
 			mockSection.WarehouseId,
 			mockSection.ProductTypeId,
+			nil,
 		)
 
 		mock.ExpectQuery(regexp.QuoteMeta(repository.SQLGetByIdSection)).WillReturnRows(row)
@@ -209,7 +215,8 @@ func TestSectionRepository_GetById(t *testing.T) {
 			"maximumCapacity",
 			"warehouseId",
 			"productTypeId",
-		}).AddRow("", "", "", "", "", "", "", "", "")
+			"archivedAt",
+		}).AddRow("", "", "", "", "", "", "", "", "", nil)
 
 		mock.ExpectQuery(regexp.QuoteMeta(repository.SQLGetByIdSection)).WillReturnRows(row)
 
@@ -347,38 +354,305 @@ func TestSectionRepository_Delete(t *testing.T) {
 }
 
 func TestSectionRepository_Update(t *testing.T) {
-	newCurrentCapacity := int64(1)
+	lockedSection := domain.SectionModel{
+		Id:                 1,
+		SectionNumber:      1,
+		CurrentTemperature: 1,
+		MinimumTemperature: 1,
+		CurrentCapacity:    5,
+		MinimumCapacity:    1,
+		MaximumCapacity:    10,
+		WarehouseId:        1,
+		ProductTypeId:      1,
+	}
+
+	sectionRow := func(section domain.SectionModel) *sqlmock.Rows {
+		return sqlmock.NewRows([]string{
+			"id",
+			"section_number",
+			"current_temperature",
+			"minimum_temperature",
+			"current_capacity",
+			"minimum_capacity",
+			"maximum_capacity",
+			"warehouse_id",
+			"product_type_id",
+			"archived_at",
+		}).AddRow(
+			section.Id,
+			section.SectionNumber,
+			section.CurrentTemperature,
+			section.MinimumTemperature,
+			section.CurrentCapacity,
+			section.MinimumCapacity,
+			section.MaximumCapacity,
+			section.WarehouseId,
+			section.ProductTypeId,
+			nil,
+		)
+	}
+
+	maximumCapacity := int64(20)
+	patch := domain.SectionPatch{MaximumCapacity: &maximumCapacity}
+
+	updateArgs := []driver.Value{
+		lockedSection.SectionNumber,
+		lockedSection.CurrentTemperature,
+		lockedSection.MinimumTemperature,
+		lockedSection.MinimumCapacity,
+		maximumCapacity,
+		lockedSection.WarehouseId,
+		lockedSection.ProductTypeId,
+		id,
+	}
+
+	t.Run("update_ok: should lock the section and update the patched fields", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(repository.SQLLockSection)).
+			WithArgs(id).
+			WillReturnRows(sectionRow(lockedSection))
+		mock.ExpectExec(regexp.QuoteMeta(repository.SQLUpdateSection)).
+			WithArgs(updateArgs...).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(outbox.SQLCreateOutboxEvent)).
+			WithArgs(outbox.SectionCapacityChanged, id, lockedSection.WarehouseId, sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		sectionRepository := repository.NewMariadbSectionRepository(db)
+
+		result, err := sectionRepository.Update(context.Background(), id, patch)
+
+		assert.NoError(t, err)
+		assert.Equal(t, maximumCapacity, result.MaximumCapacity)
+		assert.Equal(t, lockedSection.CurrentCapacity, result.CurrentCapacity)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("update_not_found: should return ErrSectionNotFound", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(repository.SQLLockSection)).
+			WithArgs(id).
+			WillReturnError(sql.ErrNoRows)
+		mock.ExpectRollback()
+
+		sectionRepository := repository.NewMariadbSectionRepository(db)
+
+		_, err = sectionRepository.Update(context.Background(), id, patch)
+
+		assert.ErrorIs(t, err, domain.ErrSectionNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("update_maximum_below_current: should validate against the locked current capacity", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		maximumCapacity := int64(4)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(repository.SQLLockSection)).
+			WithArgs(id).
+			WillReturnRows(sectionRow(lockedSection))
+		mock.ExpectRollback()
+
+		sectionRepository := repository.NewMariadbSectionRepository(db)
+
+		_, err = sectionRepository.Update(context.Background(), id, domain.SectionPatch{MaximumCapacity: &maximumCapacity})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidCapacity)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("update_minimum_above_maximum: should not update", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		minimumCapacity := int64(11)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(repository.SQLLockSection)).
+			WithArgs(id).
+			WillReturnRows(sectionRow(lockedSection))
+		mock.ExpectRollback()
+
+		sectionRepository := repository.NewMariadbSectionRepository(db)
+
+		_, err = sectionRepository.Update(context.Background(), id, domain.SectionPatch{MinimumCapacity: &minimumCapacity})
 
-	t.Run("update_query_error: should return error when query execution fails ", func(t *testing.T) {
+		assert.ErrorIs(t, err, domain.ErrInvalidCapacity)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("update_relocate_with_stock: should refuse to change the warehouse of a section holding stock", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		warehouseId := int64(2)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(repository.SQLLockSection)).
+			WithArgs(id).
+			WillReturnRows(sectionRow(lockedSection))
+		mock.ExpectQuery(regexp.QuoteMeta(repository.SQLGetSectionBatches)).
+			WithArgs(id).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "batch_number", "product_id", "status", "current_quantity"}).
+				AddRow(4, 12, 2, "discarded", 0).
+				AddRow(3, 11, 2, "active", 5))
+		mock.ExpectRollback()
+
+		sectionRepository := repository.NewMariadbSectionRepository(db)
+
+		_, err = sectionRepository.Update(context.Background(), id, domain.SectionPatch{WarehouseId: &warehouseId})
+
+		assert.ErrorIs(t, err, domain.ErrSectionHoldsStock)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("update_relocate_without_stock: should change the product type of a section holding only history", func(t *testing.T) {
 		db, mock, err := sqlmock.New()
 		assert.NoError(t, err)
 		defer db.Close()
 
-		mock.ExpectExec(regexp.QuoteMeta(repository.SQLUpdateCurrentCapacitySection)).
+		productTypeId := int64(2)
+		empty := lockedSection
+		empty.CurrentCapacity = 0
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(repository.SQLLockSection)).
 			WithArgs(id).
+			WillReturnRows(sectionRow(empty))
+		mock.ExpectQuery(regexp.QuoteMeta(repository.SQLGetSectionBatches)).
+			WithArgs(id).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "batch_number", "product_id", "status", "current_quantity"}).
+				AddRow(4, 12, 2, "discarded", 0))
+		mock.ExpectExec(regexp.QuoteMeta(repository.SQLUpdateSection)).
+			WithArgs(empty.SectionNumber, empty.CurrentTemperature, empty.MinimumTemperature, empty.MinimumCapacity,
+				empty.MaximumCapacity, empty.WarehouseId, productTypeId, id).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(outbox.SQLCreateOutboxEvent)).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		sectionRepository := repository.NewMariadbSectionRepository(db)
+
+		result, err := sectionRepository.Update(context.Background(), id, domain.SectionPatch{ProductTypeId: &productTypeId})
+
+		assert.NoError(t, err)
+		assert.Equal(t, productTypeId, result.ProductTypeId)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("update_duplicate_number: should return ErrSectionNumberTaken when the number is taken in the warehouse", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(repository.SQLLockSection)).
+			WillReturnRows(sectionRow(lockedSection))
+		mock.ExpectExec(regexp.QuoteMeta(repository.SQLUpdateSection)).
+			WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry"})
+		mock.ExpectRollback()
+
+		sectionRepository := repository.NewMariadbSectionRepository(db)
+
+		_, err = sectionRepository.Update(context.Background(), id, patch)
+
+		assert.ErrorIs(t, err, domain.ErrSectionNumberTaken)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("update_outbox_error: should rollback when the domain event cannot be written", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(repository.SQLLockSection)).
+			WillReturnRows(sectionRow(lockedSection))
+		mock.ExpectExec(regexp.QuoteMeta(repository.SQLUpdateSection)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(outbox.SQLCreateOutboxEvent)).
 			WillReturnError(errors.New("any error"))
+		mock.ExpectRollback()
 
 		sectionRepository := repository.NewMariadbSectionRepository(db)
 
-		_, err = sectionRepository.UpdateCurrentCapacity(context.Background(), &mockSection)
+		_, err = sectionRepository.Update(context.Background(), id, patch)
 
 		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
 	})
+}
 
-	t.Run("update_ok: should update section current capacity", func(t *testing.T) {
+func TestSectionRepository_GetByNumber(t *testing.T) {
+	t.Run("get_by_number_ok: should return the section of the warehouse", func(t *testing.T) {
 		db, mock, err := sqlmock.New()
 		assert.NoError(t, err)
 		defer db.Close()
 
-		mock.ExpectExec(regexp.QuoteMeta(repository.SQLUpdateCurrentCapacitySection)).
-			WithArgs(id, newCurrentCapacity).
-			WillReturnResult(sqlmock.NewResult(0, 1))
+		row := sqlmock.NewRows([]string{
+			"id",
+			"sectionNumber",
+			"currentTemperature",
+			"minimumTemperature",
+			"currentCapacity",
+			"minimumCapacity",
+			"maximumCapacity",
+			"warehouseId",
+			"productTypeId",
+			"archivedAt",
+		}).AddRow(
+			mockSection.Id,
+			mockSection.SectionNumber,
+			mockSection.CurrentTemperature,
+			mockSection.MinimumTemperature,
+			mockSection.CurrentCapacity,
+			mockSection.MinimumCapacity,
+			mockSection.MaximumCapacity,
+			mockSection.WarehouseId,
+			mockSection.ProductTypeId,
+			nil,
+		)
+
+		mock.ExpectQuery(regexp.QuoteMeta(repository.SQLGetByNumberSection)).
+			WithArgs("WH-01", mockSection.SectionNumber).
+			WillReturnRows(row)
 
 		sectionRepository := repository.NewMariadbSectionRepository(db)
 
-		_, err = sectionRepository.UpdateCurrentCapacity(context.Background(), &mockSection)
+		result, err := sectionRepository.GetByNumber(context.Background(), "WH-01", mockSection.SectionNumber)
 
 		assert.NoError(t, err)
+		assert.Equal(t, mockSection, result)
+	})
+
+	t.Run("get_by_number_not_found: should return ErrSectionNotFound", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta(repository.SQLGetByNumberSection)).
+			WithArgs("WH-01", int64(9)).
+			WillReturnError(sql.ErrNoRows)
+
+		sectionRepository := repository.NewMariadbSectionRepository(db)
+
+		_, err = sectionRepository.GetByNumber(context.Background(), "WH-01", 9)
+
+		assert.ErrorIs(t, err, domain.ErrSectionNotFound)
 	})
 }
 
@@ -527,3 +801,153 @@ func TestSectionRepository_GetByIdProductCountBySection(t *testing.T) {
 
 	})
 }
+
+func TestSectionRepository_GetOccupancy(t *testing.T) {
+	columns := []string{
+		"warehouse_id",
+		"warehouse_code",
+		"section_id",
+		"section_number",
+		"product_type_id",
+		"maximum_capacity",
+		"minimum_capacity",
+		"current_capacity",
+		"distinct_products",
+		"batches",
+		"nearest_due_date",
+	}
+
+	t.Run("get_occupancy_ok: should return the occupancy of the filtered sections", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		dueDate := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+		rows := sqlmock.NewRows(columns).
+			AddRow(1, "WH-01", 1, 1, 2, 10, 1, 5, 2, 3, dueDate).
+			AddRow(1, "WH-01", 2, 2, 2, 10, 1, 0, 0, 0, nil)
+
+		mock.ExpectQuery(regexp.QuoteMeta(repository.SQLGetSectionOccupancy)).
+			WithArgs(int64(1), int64(1), int64(0), int64(0)).
+			WillReturnRows(rows)
+
+		sectionRepository := repository.NewMariadbSectionRepository(db)
+
+		result, err := sectionRepository.GetOccupancy(context.Background(), domain.OccupancyFilter{WarehouseId: 1})
+
+		assert.NoError(t, err)
+		assert.Len(t, result, 2)
+		assert.Equal(t, "WH-01", result[0].WarehouseCode)
+		assert.Equal(t, int64(2), result[0].DistinctProducts)
+		assert.Equal(t, int64(3), result[0].Batches)
+		assert.Equal(t, dueDate, *result[0].NearestDueDate)
+		assert.Nil(t, result[1].NearestDueDate)
+	})
+
+	t.Run("get_occupancy_query_error: should return error when query execution fails", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta(repository.SQLGetSectionOccupancy)).
+			WillReturnError(errors.New("any error"))
+
+		sectionRepository := repository.NewMariadbSectionRepository(db)
+
+		_, err = sectionRepository.GetOccupancy(context.Background(), domain.OccupancyFilter{})
+
+		assert.Error(t, err)
+	})
+}
+
+func TestSectionRepository_Archive(t *testing.T) {
+	t.Run("archive_ok: should archive the section", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(repository.SQLArchiveSection)).
+			WithArgs(id).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		sectionRepository := repository.NewMariadbSectionRepository(db)
+
+		err = sectionRepository.Archive(context.Background(), id)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("restore_not_found: should return ErrSectionNotFound when no row changes", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(repository.SQLRestoreSection)).
+			WithArgs(id).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		sectionRepository := repository.NewMariadbSectionRepository(db)
+
+		err = sectionRepository.Restore(context.Background(), id)
+
+		assert.ErrorIs(t, err, domain.ErrSectionNotFound)
+	})
+
+	t.Run("delete_referenced: should return ErrSectionHasHistory when stock movements point to the section", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(repository.SQLDeleteSection)).
+			WithArgs(id).
+			WillReturnError(&mysql.MySQLError{Number: 1451, Message: "Cannot delete or update a parent row"})
+
+		sectionRepository := repository.NewMariadbSectionRepository(db)
+
+		err = sectionRepository.Delete(context.Background(), id)
+
+		assert.ErrorIs(t, err, domain.ErrSectionHasHistory)
+	})
+}
+
+func TestSectionRepository_GetBatches(t *testing.T) {
+	t.Run("get_batches_ok: should return the batches of the section", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		rows := sqlmock.NewRows([]string{"id", "batch_number", "product_id", "status", "current_quantity"}).
+			AddRow(3, 11, 2, "active", 10).
+			AddRow(4, 12, 2, "discarded", 0)
+
+		mock.ExpectQuery(regexp.QuoteMeta(repository.SQLGetSectionBatches)).
+			WithArgs(id).
+			WillReturnRows(rows)
+
+		sectionRepository := repository.NewMariadbSectionRepository(db)
+
+		result, err := sectionRepository.GetBatches(context.Background(), id)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.SectionBatch{
+			{Id: 3, BatchNumber: 11, ProductId: 2, Status: "active", CurrentQuantity: 10},
+			{Id: 4, BatchNumber: 12, ProductId: 2, Status: "discarded", CurrentQuantity: 0},
+		}, result)
+	})
+
+	t.Run("get_batches_query_error: should return error when query execution fails", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta(repository.SQLGetSectionBatches)).
+			WillReturnError(errors.New("any error"))
+
+		sectionRepository := repository.NewMariadbSectionRepository(db)
+
+		_, err = sectionRepository.GetBatches(context.Background(), id)
+
+		assert.Error(t, err)
+	})
+}
@@ -2,42 +2,114 @@ package service
 
 import (
 	"context"
+	"strings"
 
+	productType "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_type/domain"
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/section/domain"
+	warehouse "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/warehouse/domain"
 )
 
 type service struct {
-	repository domain.SectionRepository
+	repository            domain.SectionRepository
+	productTypeRepository productType.ProductTypeRepository
+	warehouseRepository   warehouse.WarehouseRepository
 }
 
-func NewServiceSection(r domain.SectionRepository) domain.SectionService {
+func NewServiceSection(r domain.SectionRepository, rpt productType.ProductTypeRepository, rw warehouse.WarehouseRepository) domain.SectionService {
 	return &service{
-		repository: r,
+		repository:            r,
+		productTypeRepository: rpt,
+		warehouseRepository:   rw,
 	}
 }
 
+// Delete removes a section that holds no batches at all. Batches keep
+// their section, even discarded ones, so those sections can only be
+// archived.
 func (s *service) Delete(ctx context.Context, id int64) error {
+	if _, err := s.repository.GetById(ctx, id); err != nil {
+		return err
+	}
+
+	batches, err := s.repository.GetBatches(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if len(batches) > 0 {
+		return &domain.SectionInUseError{Batches: batches}
+	}
+
 	return s.repository.Delete(ctx, id)
 }
 
-func (s *service) UpdateCurrentCapacity(ctx context.Context, id int64, currentCapacity int64) (*domain.SectionModel, error) {
-	sectionCurrent, err := s.GetById(ctx, id)
+// Archive hides a section that no longer holds stock from the listings,
+// keeping its batches, movements and temperatures.
+func (s *service) Archive(ctx context.Context, id int64) error {
+	section, err := s.repository.GetById(ctx, id)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	if currentCapacity > 0 {
-		sectionCurrent.CurrentCapacity = currentCapacity
+	if section.ArchivedAt != nil {
+		return domain.ErrSectionArchived
 	}
 
-	section, err := s.repository.UpdateCurrentCapacity(ctx, &sectionCurrent)
+	batches, err := s.repository.GetBatches(ctx, id)
 	if err != nil {
-		return nil, err
+		return err
+	}
+
+	stocked := []domain.SectionBatch{}
+	for _, batch := range batches {
+		if batch.HoldsStock() {
+			stocked = append(stocked, batch)
+		}
 	}
 
+	if len(stocked) > 0 {
+		return &domain.SectionInUseError{Batches: stocked}
+	}
+
+	return s.repository.Archive(ctx, id)
+}
+
+func (s *service) Restore(ctx context.Context, id int64) (domain.SectionModel, error) {
+	section, err := s.repository.GetById(ctx, id)
+	if err != nil {
+		return domain.SectionModel{}, err
+	}
+
+	if section.ArchivedAt == nil {
+		return domain.SectionModel{}, domain.ErrSectionNotArchived
+	}
+
+	if err := s.repository.Restore(ctx, id); err != nil {
+		return domain.SectionModel{}, err
+	}
+
+	section.ArchivedAt = nil
 	return section, nil
 }
 
+// Update checks the warehouse and product type the patch points to, then
+// leaves the repository to validate the patch against the locked section.
+func (s *service) Update(ctx context.Context, id int64, patch domain.SectionPatch) (*domain.SectionModel, error) {
+	if patch.ProductTypeId != nil {
+		if _, err := s.productTypeRepository.GetById(ctx, *patch.ProductTypeId); err != nil {
+			return nil, err
+		}
+	}
+
+	if patch.WarehouseId != nil {
+		if _, err := s.warehouseRepository.GetById(ctx, *patch.WarehouseId); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.repository.Update(ctx, id, patch)
+}
+
 func (s *service) Create(
 	ctx context.Context,
 	sectionNumber int64,
@@ -49,6 +121,24 @@ func (s *service) Create(
 	warehouseId int64,
 	productTypeId int64) (domain.SectionModel, error) {
 
+	candidate := domain.SectionModel{
+		SectionNumber:   sectionNumber,
+		CurrentCapacity: currentCapacity,
+		MinimumCapacity: minimumCapacity,
+		MaximumCapacity: maximumCapacity,
+	}
+	if err := candidate.Validate(); err != nil {
+		return domain.SectionModel{}, err
+	}
+
+	if _, err := s.productTypeRepository.GetById(ctx, productTypeId); err != nil {
+		return domain.SectionModel{}, err
+	}
+
+	if _, err := s.warehouseRepository.GetById(ctx, warehouseId); err != nil {
+		return domain.SectionModel{}, err
+	}
+
 	section, err := s.repository.Create(
 		ctx,
 		sectionNumber,
@@ -74,6 +164,17 @@ func (s *service) GetById(ctx context.Context, id int64) (domain.SectionModel, e
 	return section, nil
 }
 
+// GetByNumber finds a section by the code of its warehouse and its number
+// there, which is how operators refer to sections.
+func (s *service) GetByNumber(ctx context.Context, warehouseCode string, sectionNumber int64) (domain.SectionModel, error) {
+	warehouseCode = strings.TrimSpace(warehouseCode)
+	if warehouseCode == "" || sectionNumber <= 0 {
+		return domain.SectionModel{}, domain.ErrInvalidSectionLookup
+	}
+
+	return s.repository.GetByNumber(ctx, warehouseCode, sectionNumber)
+}
+
 func (s *service) GetAll(ctx context.Context) ([]domain.SectionModel, error) {
 	listSection, err := s.repository.GetAll(ctx)
 	if err != nil {
@@ -104,3 +205,30 @@ func (s *service) GetByIdProductCountBySection(ctx context.Context, id int64) (*
 
 	return result, nil
 }
+
+// GetOccupancy reports how full the sections are. The warehouse and product
+// type of the filter must exist, so a typo does not read as an empty report.
+func (s *service) GetOccupancy(ctx context.Context, filter domain.OccupancyFilter) ([]domain.SectionOccupancy, error) {
+	if filter.WarehouseId != 0 {
+		if _, err := s.warehouseRepository.GetById(ctx, filter.WarehouseId); err != nil {
+			return nil, err
+		}
+	}
+
+	if filter.ProductTypeId != 0 {
+		if _, err := s.productTypeRepository.GetById(ctx, filter.ProductTypeId); err != nil {
+			return nil, err
+		}
+	}
+
+	occupancy, err := s.repository.GetOccupancy(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range occupancy {
+		occupancy[i].Summarize()
+	}
+
+	return occupancy, nil
+}
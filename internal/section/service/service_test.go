@@ -4,11 +4,16 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	productType "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_type/domain"
+	productTypeMocks "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_type/domain/mocks"
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/section/domain"
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/section/domain/mocks"
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/section/service"
+	warehouse "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/warehouse/domain"
+	warehouseMocks "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/warehouse/domain/mocks"
 )
 
 var expectedSection = domain.SectionModel{
@@ -29,8 +34,8 @@ var expectedUpdatedSection = domain.SectionModel{
 	CurrentTemperature: 28,
 	MinimumTemperature: 30.5,
 	CurrentCapacity:    int64(5),
-	MinimumCapacity:    int64(1),
-	MaximumCapacity:    int64(1),
+	MinimumCapacity:    int64(5),
+	MaximumCapacity:    int64(10),
 	WarehouseId:        int64(1),
 	ProductTypeId:      int64(1),
 }
@@ -50,8 +55,20 @@ var (
 
 func TestSectionService_Create(t *testing.T) {
 	mockRepository := mocks.NewSectionRepository(t)
+	mockProductTypeRepository := productTypeMocks.NewProductTypeRepository(t)
+	mockWarehouseRepository := warehouseMocks.NewWarehouseRepository(t)
 
 	t.Run("create_ok: when it contains the mandatory fields, should create a section", func(t *testing.T) {
+		mockProductTypeRepository.
+			On("GetById", ctx, expectedSection.ProductTypeId).
+			Return(&productType.ProductType{Id: expectedSection.ProductTypeId}, nil).
+			Once()
+
+		mockWarehouseRepository.
+			On("GetById", ctx, expectedSection.WarehouseId).
+			Return(warehouse.WarehouseModel{Id: expectedSection.WarehouseId}, nil).
+			Once()
+
 		mockRepository.
 			On("Create",
 				ctx,
@@ -67,7 +84,7 @@ func TestSectionService_Create(t *testing.T) {
 			Return(expectedSection, nil).
 			Once()
 
-		service := service.NewServiceSection(mockRepository)
+		service := service.NewServiceSection(mockRepository, mockProductTypeRepository, mockWarehouseRepository)
 		result, err := service.Create(ctx, 1, 28.0, 30.5, 1, 1, 1, 1, 1)
 
 		assert.Nil(t, err)
@@ -77,6 +94,16 @@ func TestSectionService_Create(t *testing.T) {
 	t.Run("create_conflict: when section_number already exists, should not create a section", func(t *testing.T) {
 		errorConflict := fmt.Errorf("already a section with the code: %d", expectedSection.SectionNumber)
 
+		mockProductTypeRepository.
+			On("GetById", ctx, expectedSection.ProductTypeId).
+			Return(&productType.ProductType{Id: expectedSection.ProductTypeId}, nil).
+			Once()
+
+		mockWarehouseRepository.
+			On("GetById", ctx, expectedSection.WarehouseId).
+			Return(warehouse.WarehouseModel{Id: expectedSection.WarehouseId}, nil).
+			Once()
+
 		mockRepository.
 			On("Create",
 				ctx,
@@ -92,12 +119,93 @@ func TestSectionService_Create(t *testing.T) {
 			Return(domain.SectionModel{}, errorConflict).
 			Once()
 
-		service := service.NewServiceSection(mockRepository)
+		service := service.NewServiceSection(mockRepository, mockProductTypeRepository, mockWarehouseRepository)
 		result, err := service.Create(ctx, 1, 28.0, 30.5, 1, 1, 1, 1, 1)
 
 		assert.Equal(t, domain.SectionModel{}, result)
 		assert.Equal(t, errorConflict, err)
 	})
+
+	t.Run("create_product_type_not_found: when product_type_id does not exist, should not create a section", func(t *testing.T) {
+		mockProductTypeRepository.
+			On("GetById", ctx, expectedSection.ProductTypeId).
+			Return(nil, productType.ErrProductTypeNotFound).
+			Once()
+
+		service := service.NewServiceSection(mockRepository, mockProductTypeRepository, mockWarehouseRepository)
+		result, err := service.Create(ctx, 1, 28.0, 30.5, 1, 1, 1, 1, 1)
+
+		assert.Equal(t, domain.SectionModel{}, result)
+		assert.ErrorIs(t, err, productType.ErrProductTypeNotFound)
+	})
+
+	t.Run("create_warehouse_not_found: when warehouse_id does not exist, should not create a section", func(t *testing.T) {
+		mockProductTypeRepository.
+			On("GetById", ctx, expectedSection.ProductTypeId).
+			Return(&productType.ProductType{Id: expectedSection.ProductTypeId}, nil).
+			Once()
+
+		mockWarehouseRepository.
+			On("GetById", ctx, expectedSection.WarehouseId).
+			Return(warehouse.WarehouseModel{}, warehouse.ErrWarehouseNotFound).
+			Once()
+
+		service := service.NewServiceSection(mockRepository, mockProductTypeRepository, mockWarehouseRepository)
+		result, err := service.Create(ctx, 1, 28.0, 30.5, 1, 1, 1, 1, 1)
+
+		assert.Equal(t, domain.SectionModel{}, result)
+		assert.ErrorIs(t, err, warehouse.ErrWarehouseNotFound)
+	})
+
+	t.Run("create_invalid_capacity: when the current capacity exceeds the maximum, should not create a section", func(t *testing.T) {
+		service := service.NewServiceSection(mockRepository, mockProductTypeRepository, mockWarehouseRepository)
+		result, err := service.Create(ctx, 1, 28.0, 30.5, 20, 1, 10, 1, 1)
+
+		assert.Equal(t, domain.SectionModel{}, result)
+		assert.ErrorIs(t, err, domain.ErrInvalidCapacity)
+	})
+
+	t.Run("create_empty_with_minimum: an empty section may start below its minimum capacity", func(t *testing.T) {
+		empty := domain.SectionModel{
+			Id:                 2,
+			SectionNumber:      2,
+			CurrentTemperature: 28.0,
+			MinimumTemperature: 30.5,
+			CurrentCapacity:    0,
+			MinimumCapacity:    10,
+			MaximumCapacity:    100,
+			WarehouseId:        1,
+			ProductTypeId:      1,
+		}
+
+		mockProductTypeRepository.
+			On("GetById", ctx, empty.ProductTypeId).
+			Return(&productType.ProductType{Id: empty.ProductTypeId}, nil).
+			Once()
+
+		mockWarehouseRepository.
+			On("GetById", ctx, empty.WarehouseId).
+			Return(warehouse.WarehouseModel{Id: empty.WarehouseId}, nil).
+			Once()
+
+		mockRepository.
+			On("Create", ctx, int64(2), 28.0, 30.5, int64(0), int64(10), int64(100), int64(1), int64(1)).
+			Return(empty, nil).
+			Once()
+
+		service := service.NewServiceSection(mockRepository, mockProductTypeRepository, mockWarehouseRepository)
+		result, err := service.Create(ctx, 2, 28.0, 30.5, 0, 10, 100, 1, 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, empty, result)
+	})
+
+	t.Run("create_invalid_section_number: when the section number is not positive, should not create a section", func(t *testing.T) {
+		service := service.NewServiceSection(mockRepository, mockProductTypeRepository, mockWarehouseRepository)
+		_, err := service.Create(ctx, 0, 28.0, 30.5, 1, 1, 1, 1, 1)
+
+		assert.ErrorIs(t, err, domain.ErrInvalidSectionNumber)
+	})
 }
 
 func TestSectionService_GetAll(t *testing.T) {
@@ -108,7 +216,7 @@ func TestSectionService_GetAll(t *testing.T) {
 			Return([]domain.SectionModel{expectedSection}, nil).
 			Once()
 
-		service := service.NewServiceSection(mockRepository)
+		service := service.NewServiceSection(mockRepository, productTypeMocks.NewProductTypeRepository(t), warehouseMocks.NewWarehouseRepository(t))
 		result, err := service.GetAll(ctx)
 
 		assert.Nil(t, err)
@@ -122,7 +230,7 @@ func TestSectionService_GetAll(t *testing.T) {
 			Return([]domain.SectionModel{}, anyError).
 			Once()
 
-		service := service.NewServiceSection(mockRepository)
+		service := service.NewServiceSection(mockRepository, productTypeMocks.NewProductTypeRepository(t), warehouseMocks.NewWarehouseRepository(t))
 		_, err := service.GetAll(ctx)
 
 		assert.NotNil(t, err)
@@ -139,7 +247,7 @@ func TestSectionService_GetById(t *testing.T) {
 			Return(expectedSection, nil).
 			Once()
 
-		service := service.NewServiceSection(mockRepository)
+		service := service.NewServiceSection(mockRepository, productTypeMocks.NewProductTypeRepository(t), warehouseMocks.NewWarehouseRepository(t))
 		result, err := service.GetById(ctx, 1)
 
 		assert.Nil(t, err)
@@ -153,7 +261,7 @@ func TestSectionService_GetById(t *testing.T) {
 			Return(domain.SectionModel{}, errorNotFound).
 			Once()
 
-		service := service.NewServiceSection(mockRepository)
+		service := service.NewServiceSection(mockRepository, productTypeMocks.NewProductTypeRepository(t), warehouseMocks.NewWarehouseRepository(t))
 		result, err := service.GetById(ctx, id)
 
 		assert.Equal(t, domain.SectionModel{}, result)
@@ -165,12 +273,20 @@ func TestSectionService_Delete(t *testing.T) {
 	mockRepository := mocks.NewSectionRepository(t)
 
 	t.Run("delete_ok: when the section exist, should delete a section", func(t *testing.T) {
+		mockRepository.
+			On("GetById", ctx, int64(1)).
+			Return(expectedSection, nil).
+			Once()
+		mockRepository.
+			On("GetBatches", ctx, int64(1)).
+			Return([]domain.SectionBatch{}, nil).
+			Once()
 		mockRepository.
 			On("Delete", ctx, int64(1)).
 			Return(nil).
 			Once()
 
-		service := service.NewServiceSection(mockRepository)
+		service := service.NewServiceSection(mockRepository, productTypeMocks.NewProductTypeRepository(t), warehouseMocks.NewWarehouseRepository(t))
 		err := service.Delete(ctx, 1)
 
 		assert.Nil(t, err)
@@ -178,75 +294,275 @@ func TestSectionService_Delete(t *testing.T) {
 
 	t.Run("delete_non_existent: when the section does not exist, should return an error", func(t *testing.T) {
 		mockRepository.
-			On("Delete", ctx, id).
-			Return(errorNotFound).
+			On("GetById", ctx, id).
+			Return(domain.SectionModel{}, errorNotFound).
 			Once()
 
-		service := service.NewServiceSection(mockRepository)
+		service := service.NewServiceSection(mockRepository, productTypeMocks.NewProductTypeRepository(t), warehouseMocks.NewWarehouseRepository(t))
 		err := service.Delete(ctx, id)
 
 		assert.Equal(t, errorNotFound, err)
 	})
+
+	t.Run("delete_in_use: when the section holds batches, should list them", func(t *testing.T) {
+		batches := []domain.SectionBatch{{Id: 4, Status: "discarded"}}
+
+		mockRepository.
+			On("GetById", ctx, id).
+			Return(expectedSection, nil).
+			Once()
+		mockRepository.
+			On("GetBatches", ctx, id).
+			Return(batches, nil).
+			Once()
+
+		service := service.NewServiceSection(mockRepository, productTypeMocks.NewProductTypeRepository(t), warehouseMocks.NewWarehouseRepository(t))
+		err := service.Delete(ctx, id)
+
+		var inUse *domain.SectionInUseError
+		assert.ErrorAs(t, err, &inUse)
+		assert.ErrorIs(t, err, domain.ErrSectionInUse)
+		assert.Equal(t, batches, inUse.Batches)
+	})
 }
 
-func TestSectionService_Update(t *testing.T) {
-	id := int64(1)
-	newCurrentCapacity := int64(5)
+func TestSectionService_Archive(t *testing.T) {
+	t.Run("archive_ok: when only emptied or discarded batches remain, should archive the section", func(t *testing.T) {
+		mockRepository := mocks.NewSectionRepository(t)
+		mockRepository.
+			On("GetById", ctx, id).
+			Return(expectedSection, nil).
+			Once()
+		mockRepository.
+			On("GetBatches", ctx, id).
+			Return([]domain.SectionBatch{
+				{Id: 4, Status: "discarded"},
+				{Id: 5, Status: "active", CurrentQuantity: 0},
+			}, nil).
+			Once()
+		mockRepository.
+			On("Archive", ctx, id).
+			Return(nil).
+			Once()
 
-	t.Run("update_existent: when the data update is successful, should return the updated session", func(t *testing.T) {
+		service := service.NewServiceSection(mockRepository, productTypeMocks.NewProductTypeRepository(t), warehouseMocks.NewWarehouseRepository(t))
+		err := service.Archive(ctx, id)
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("archive_in_use: when batches hold stock, should list only those", func(t *testing.T) {
 		mockRepository := mocks.NewSectionRepository(t)
+		mockRepository.
+			On("GetById", ctx, id).
+			Return(expectedSection, nil).
+			Once()
+		mockRepository.
+			On("GetBatches", ctx, id).
+			Return([]domain.SectionBatch{
+				{Id: 4, Status: "discarded"},
+				{Id: 6, Status: "quarantined", CurrentQuantity: 3},
+			}, nil).
+			Once()
 
+		service := service.NewServiceSection(mockRepository, productTypeMocks.NewProductTypeRepository(t), warehouseMocks.NewWarehouseRepository(t))
+		err := service.Archive(ctx, id)
+
+		var inUse *domain.SectionInUseError
+		assert.ErrorAs(t, err, &inUse)
+		assert.Equal(t, []domain.SectionBatch{{Id: 6, Status: "quarantined", CurrentQuantity: 3}}, inUse.Batches)
+	})
+
+	t.Run("archive_archived: when the section is already archived, should return an error", func(t *testing.T) {
+		archivedAt := time.Now()
+		archived := expectedSection
+		archived.ArchivedAt = &archivedAt
+
+		mockRepository := mocks.NewSectionRepository(t)
 		mockRepository.
-			On("GetById", context.TODO(), int64(1)).
+			On("GetById", ctx, id).
+			Return(archived, nil).
+			Once()
+
+		service := service.NewServiceSection(mockRepository, productTypeMocks.NewProductTypeRepository(t), warehouseMocks.NewWarehouseRepository(t))
+		err := service.Archive(ctx, id)
+
+		assert.ErrorIs(t, err, domain.ErrSectionArchived)
+	})
+}
+
+func TestSectionService_Restore(t *testing.T) {
+	t.Run("restore_ok: when the section is archived, should restore it", func(t *testing.T) {
+		archivedAt := time.Now()
+		archived := expectedSection
+		archived.ArchivedAt = &archivedAt
+
+		mockRepository := mocks.NewSectionRepository(t)
+		mockRepository.
+			On("GetById", ctx, id).
+			Return(archived, nil).
+			Once()
+		mockRepository.
+			On("Restore", ctx, id).
+			Return(nil).
+			Once()
+
+		service := service.NewServiceSection(mockRepository, productTypeMocks.NewProductTypeRepository(t), warehouseMocks.NewWarehouseRepository(t))
+		result, err := service.Restore(ctx, id)
+
+		assert.Nil(t, err)
+		assert.Equal(t, expectedSection, result)
+	})
+
+	t.Run("restore_not_archived: when the section is not archived, should return an error", func(t *testing.T) {
+		mockRepository := mocks.NewSectionRepository(t)
+		mockRepository.
+			On("GetById", ctx, id).
 			Return(expectedSection, nil).
 			Once()
 
+		service := service.NewServiceSection(mockRepository, productTypeMocks.NewProductTypeRepository(t), warehouseMocks.NewWarehouseRepository(t))
+		_, err := service.Restore(ctx, id)
+
+		assert.ErrorIs(t, err, domain.ErrSectionNotArchived)
+	})
+}
+
+func TestSectionService_Update(t *testing.T) {
+	id := int64(1)
+	newMinimumCapacity := int64(5)
+	newMaximumCapacity := int64(10)
+	patch := domain.SectionPatch{MinimumCapacity: &newMinimumCapacity, MaximumCapacity: &newMaximumCapacity}
+
+	t.Run("update_existent: when the data update is successful, should return the updated session", func(t *testing.T) {
+		mockRepository := mocks.NewSectionRepository(t)
+
 		mockRepository.
-			On("UpdateCurrentCapacity", context.TODO(), &expectedUpdatedSection).
+			On("Update", ctx, id, patch).
 			Return(&expectedUpdatedSection, nil).
 			Once()
 
-		service := service.NewServiceSection(mockRepository)
-		result, err := service.UpdateCurrentCapacity(context.TODO(), id, newCurrentCapacity)
+		service := service.NewServiceSection(mockRepository, productTypeMocks.NewProductTypeRepository(t), warehouseMocks.NewWarehouseRepository(t))
+		result, err := service.Update(ctx, id, patch)
 
 		assert.Nil(t, err)
-		assert.Equal(t, expectedUpdatedSection.CurrentCapacity, result.CurrentCapacity)
+		assert.Equal(t, &expectedUpdatedSection, result)
 	})
 
 	t.Run("update_non_existent: when the element searched for by id does not exist, should return an error", func(t *testing.T) {
 		mockRepository := mocks.NewSectionRepository(t)
 
 		mockRepository.
-			On("GetById", context.TODO(), id).
-			Return(domain.SectionModel{}, errorNotFound)
+			On("Update", ctx, id, patch).
+			Return(nil, domain.ErrSectionNotFound).
+			Once()
 
-		service := service.NewServiceSection(mockRepository)
-		result, err := service.UpdateCurrentCapacity(ctx, id, int64(5))
+		service := service.NewServiceSection(mockRepository, productTypeMocks.NewProductTypeRepository(t), warehouseMocks.NewWarehouseRepository(t))
+		result, err := service.Update(ctx, id, patch)
 
-		assert.Equal(t, errorNotFound, err)
+		assert.ErrorIs(t, err, domain.ErrSectionNotFound)
 		assert.Nil(t, result)
 	})
 
-	t.Run("update_existent: when the data update is successful, should return the updated session", func(t *testing.T) {
+	t.Run("update_repository_error: when the repository fails, should return the error", func(t *testing.T) {
 		mockRepository := mocks.NewSectionRepository(t)
 
 		mockRepository.
-			On("GetById", context.TODO(), int64(1)).
-			Return(expectedSection, nil).
-			Once()
-
-		mockRepository.
-			On("UpdateCurrentCapacity", context.TODO(), &expectedUpdatedSection).
+			On("Update", ctx, id, patch).
 			Return(nil, anyError).
 			Once()
 
-		service := service.NewServiceSection(mockRepository)
-		result, err := service.UpdateCurrentCapacity(context.TODO(), id, newCurrentCapacity)
+		service := service.NewServiceSection(mockRepository, productTypeMocks.NewProductTypeRepository(t), warehouseMocks.NewWarehouseRepository(t))
+		result, err := service.Update(ctx, id, patch)
 
 		assert.Equal(t, anyError, err)
 		assert.Nil(t, result)
 	})
 
+	t.Run("update_relocate: when the new warehouse and product type exist, should leave the stock check to the repository", func(t *testing.T) {
+		mockRepository := mocks.NewSectionRepository(t)
+		mockWarehouseRepository := warehouseMocks.NewWarehouseRepository(t)
+		mockProductTypeRepository := productTypeMocks.NewProductTypeRepository(t)
+		warehouseId := int64(2)
+		productTypeId := int64(2)
+		relocate := domain.SectionPatch{WarehouseId: &warehouseId, ProductTypeId: &productTypeId}
+
+		mockProductTypeRepository.
+			On("GetById", ctx, productTypeId).
+			Return(&productType.ProductType{Id: productTypeId}, nil).
+			Once()
+
+		mockWarehouseRepository.
+			On("GetById", ctx, warehouseId).
+			Return(warehouse.WarehouseModel{Id: warehouseId}, nil).
+			Once()
+
+		mockRepository.
+			On("Update", ctx, id, relocate).
+			Return(nil, domain.ErrSectionHoldsStock).
+			Once()
+
+		service := service.NewServiceSection(mockRepository, mockProductTypeRepository, mockWarehouseRepository)
+		_, err := service.Update(ctx, id, relocate)
+
+		assert.ErrorIs(t, err, domain.ErrSectionHoldsStock)
+	})
+
+	t.Run("update_warehouse_not_found: when the new warehouse does not exist, should not update", func(t *testing.T) {
+		mockWarehouseRepository := warehouseMocks.NewWarehouseRepository(t)
+		warehouseId := int64(2)
+
+		mockWarehouseRepository.
+			On("GetById", ctx, warehouseId).
+			Return(warehouse.WarehouseModel{}, warehouse.ErrWarehouseNotFound).
+			Once()
+
+		service := service.NewServiceSection(mocks.NewSectionRepository(t), productTypeMocks.NewProductTypeRepository(t), mockWarehouseRepository)
+		_, err := service.Update(ctx, id, domain.SectionPatch{WarehouseId: &warehouseId})
+
+		assert.ErrorIs(t, err, warehouse.ErrWarehouseNotFound)
+	})
+
+	t.Run("update_product_type_not_found: when the new product type does not exist, should not update", func(t *testing.T) {
+		mockProductTypeRepository := productTypeMocks.NewProductTypeRepository(t)
+		productTypeId := int64(2)
+
+		mockProductTypeRepository.
+			On("GetById", ctx, productTypeId).
+			Return(nil, productType.ErrProductTypeNotFound).
+			Once()
+
+		service := service.NewServiceSection(mocks.NewSectionRepository(t), mockProductTypeRepository, warehouseMocks.NewWarehouseRepository(t))
+		_, err := service.Update(ctx, id, domain.SectionPatch{ProductTypeId: &productTypeId})
+
+		assert.ErrorIs(t, err, productType.ErrProductTypeNotFound)
+	})
+}
+
+func TestSectionService_GetByNumber(t *testing.T) {
+	t.Run("get_by_number: when the section exists, should return it", func(t *testing.T) {
+		mockRepository := mocks.NewSectionRepository(t)
+		mockRepository.
+			On("GetByNumber", ctx, "WH-01", int64(1)).
+			Return(expectedSection, nil).
+			Once()
+
+		service := service.NewServiceSection(mockRepository, productTypeMocks.NewProductTypeRepository(t), warehouseMocks.NewWarehouseRepository(t))
+		result, err := service.GetByNumber(ctx, " WH-01 ", 1)
+
+		assert.Nil(t, err)
+		assert.Equal(t, expectedSection, result)
+	})
+
+	t.Run("get_by_number_invalid: when the lookup is incomplete, should return an error", func(t *testing.T) {
+		service := service.NewServiceSection(mocks.NewSectionRepository(t), productTypeMocks.NewProductTypeRepository(t), warehouseMocks.NewWarehouseRepository(t))
+
+		_, err := service.GetByNumber(ctx, "", 1)
+		assert.ErrorIs(t, err, domain.ErrInvalidSectionLookup)
+
+		_, err = service.GetByNumber(ctx, "WH-01", 0)
+		assert.ErrorIs(t, err, domain.ErrInvalidSectionLookup)
+	})
 }
 
 func TestSectionService_GetAllProductCountBySection(t *testing.T) {
@@ -257,7 +573,7 @@ func TestSectionService_GetAllProductCountBySection(t *testing.T) {
 			Return(&[]domain.ReportProductsModel{expectedRecordProductBySection}, nil).
 			Once()
 
-		service := service.NewServiceSection(mockRepository)
+		service := service.NewServiceSection(mockRepository, productTypeMocks.NewProductTypeRepository(t), warehouseMocks.NewWarehouseRepository(t))
 		result, err := service.GetAllProductCountBySection(ctx)
 
 		assert.Nil(t, err)
@@ -271,7 +587,7 @@ func TestSectionService_GetAllProductCountBySection(t *testing.T) {
 			Return(nil, anyError).
 			Once()
 
-		service := service.NewServiceSection(mockRepository)
+		service := service.NewServiceSection(mockRepository, productTypeMocks.NewProductTypeRepository(t), warehouseMocks.NewWarehouseRepository(t))
 		_, err := service.GetAllProductCountBySection(ctx)
 
 		assert.NotNil(t, err)
@@ -293,7 +609,7 @@ func TestSectionService_GetByIdProductCountBySection(t *testing.T) {
 			Return(&expectedRecordProductBySection, nil).
 			Once()
 
-		service := service.NewServiceSection(mockRepository)
+		service := service.NewServiceSection(mockRepository, productTypeMocks.NewProductTypeRepository(t), warehouseMocks.NewWarehouseRepository(t))
 		result, err := service.GetByIdProductCountBySection(ctx, id)
 
 		assert.Nil(t, err)
@@ -306,7 +622,7 @@ func TestSectionService_GetByIdProductCountBySection(t *testing.T) {
 			Return(domain.SectionModel{}, errorNotFound).
 			Once()
 
-		service := service.NewServiceSection(mockRepository)
+		service := service.NewServiceSection(mockRepository, productTypeMocks.NewProductTypeRepository(t), warehouseMocks.NewWarehouseRepository(t))
 		_, err := service.GetByIdProductCountBySection(ctx, id)
 
 		assert.Equal(t, errorNotFound, err)
@@ -324,9 +640,72 @@ func TestSectionService_GetByIdProductCountBySection(t *testing.T) {
 			Return(nil, anyError).
 			Once()
 
-		service := service.NewServiceSection(mockRepository)
+		service := service.NewServiceSection(mockRepository, productTypeMocks.NewProductTypeRepository(t), warehouseMocks.NewWarehouseRepository(t))
 		_, err := service.GetByIdProductCountBySection(ctx, id)
 
 		assert.NotNil(t, err)
 	})
 }
+
+func TestSectionService_GetOccupancy(t *testing.T) {
+	t.Run("get_occupancy: should compute utilization and flag sections below minimum", func(t *testing.T) {
+		mockRepository := mocks.NewSectionRepository(t)
+		mockRepository.
+			On("GetOccupancy", ctx, domain.OccupancyFilter{}).
+			Return([]domain.SectionOccupancy{
+				{SectionId: 1, CurrentCapacity: 1, MinimumCapacity: 2, MaximumCapacity: 3},
+				{SectionId: 2, CurrentCapacity: 5, MinimumCapacity: 1, MaximumCapacity: 10},
+				{SectionId: 3},
+			}, nil).
+			Once()
+
+		service := service.NewServiceSection(mockRepository, productTypeMocks.NewProductTypeRepository(t), warehouseMocks.NewWarehouseRepository(t))
+		result, err := service.GetOccupancy(ctx, domain.OccupancyFilter{})
+
+		assert.Nil(t, err)
+		assert.Equal(t, 33.33, result[0].Utilization)
+		assert.True(t, result[0].BelowMinimum)
+		assert.Equal(t, 50.0, result[1].Utilization)
+		assert.False(t, result[1].BelowMinimum)
+		assert.Equal(t, 0.0, result[2].Utilization)
+	})
+
+	t.Run("get_occupancy_filtered: should check the warehouse and product type of the filter", func(t *testing.T) {
+		filter := domain.OccupancyFilter{WarehouseId: 1, ProductTypeId: 2}
+		mockRepository := mocks.NewSectionRepository(t)
+		mockProductTypeRepository := productTypeMocks.NewProductTypeRepository(t)
+		mockWarehouseRepository := warehouseMocks.NewWarehouseRepository(t)
+
+		mockWarehouseRepository.
+			On("GetById", ctx, int64(1)).
+			Return(warehouse.WarehouseModel{Id: 1}, nil).
+			Once()
+		mockProductTypeRepository.
+			On("GetById", ctx, int64(2)).
+			Return(&productType.ProductType{Id: 2}, nil).
+			Once()
+		mockRepository.
+			On("GetOccupancy", ctx, filter).
+			Return([]domain.SectionOccupancy{}, nil).
+			Once()
+
+		service := service.NewServiceSection(mockRepository, mockProductTypeRepository, mockWarehouseRepository)
+		result, err := service.GetOccupancy(ctx, filter)
+
+		assert.Nil(t, err)
+		assert.Empty(t, result)
+	})
+
+	t.Run("get_occupancy_warehouse_not_found: should return an error", func(t *testing.T) {
+		mockWarehouseRepository := warehouseMocks.NewWarehouseRepository(t)
+		mockWarehouseRepository.
+			On("GetById", ctx, int64(9)).
+			Return(warehouse.WarehouseModel{}, warehouse.ErrWarehouseNotFound).
+			Once()
+
+		service := service.NewServiceSection(mocks.NewSectionRepository(t), productTypeMocks.NewProductTypeRepository(t), mockWarehouseRepository)
+		_, err := service.GetOccupancy(ctx, domain.OccupancyFilter{WarehouseId: 9})
+
+		assert.ErrorIs(t, err, warehouse.ErrWarehouseNotFound)
+	})
+}
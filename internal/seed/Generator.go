@@ -0,0 +1,550 @@
+package seed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	buyer "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/buyer/domain"
+	carry "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/carry/domain"
+	employee "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/employees/domain"
+	inboundOrders "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/inboud_orders/domain"
+	locality "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/locality/domain"
+	product "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product/domain"
+	productBatch "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_batch/domain"
+	productRecords "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_records/domain"
+	productType "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_type/domain"
+	purchaseOrders "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/purchase_orders/domain"
+	section "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/section/domain"
+	seller "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/seller/domain"
+	warehouse "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/warehouse/domain"
+)
+
+// Services are the domain services the generator writes through, so the
+// generated rows pass the same validation as the ones created by the API.
+type Services struct {
+	Localities     locality.LocalityService
+	Sellers        seller.ServiceSeller
+	ProductTypes   productType.ProductTypeService
+	Products       product.ProductService
+	ProductRecords productRecords.ProductRecordsService
+	Warehouses     warehouse.WarehouseService
+	Sections       section.SectionService
+	Batches        productBatch.ProductBatchService
+	Buyers         buyer.BuyerService
+	Carriers       carry.CarryService
+	Employees      employee.EmployeeService
+	InboundOrders  inboundOrders.InboundOrdersService
+	PurchaseOrders purchaseOrders.PurchaseOrdersService
+}
+
+// Config sets the volume of the generated data. Counts ending in "Per" are
+// multiplied by the number of parents, so the totals grow with the scale.
+type Config struct {
+	Seed int64
+	// Now anchors every generated date. Product records and purchase orders
+	// cannot be dated in the past, so it should be today or later.
+	Now time.Time
+
+	Localities             int
+	Sellers                int
+	ProductsPerSeller      int
+	Warehouses             int
+	SectionsPerWarehouse   int
+	BatchesPerSection      int
+	EmployeesPerWarehouse  int
+	Buyers                 int
+	Carriers               int
+	PurchaseOrdersPerBuyer int
+
+	// ProductTypes are looked up by description and created when missing.
+	ProductTypes []productType.ProductType
+	// OrderStatusIds must already exist, there is no API for them.
+	OrderStatusIds []int64
+}
+
+// DefaultConfig is a small but complete data set using the product types and
+// order statuses of db/fixtures/reference.sql. Scale multiplies every count.
+func DefaultConfig(seed int64, scale int) Config {
+	if scale < 1 {
+		scale = 1
+	}
+
+	return Config{
+		Seed: seed,
+		Now:  time.Now().UTC(),
+
+		Localities:             10 * scale,
+		Sellers:                20 * scale,
+		ProductsPerSeller:      5,
+		Warehouses:             3 * scale,
+		SectionsPerWarehouse:   6,
+		BatchesPerSection:      4,
+		EmployeesPerWarehouse:  5,
+		Buyers:                 30 * scale,
+		Carriers:               8 * scale,
+		PurchaseOrdersPerBuyer: 3,
+
+		ProductTypes: []productType.ProductType{
+			{Description: "Frozen", Category: productType.CategoryFrozen, MinimumTemperature: -25, MaximumTemperature: -18},
+			{Description: "Refrigerated", Category: productType.CategoryRefrigerated, MinimumTemperature: 0, MaximumTemperature: 8},
+			{Description: "Ambient", Category: productType.CategoryAmbient, MinimumTemperature: 15, MaximumTemperature: 25},
+		},
+		OrderStatusIds: []int64{1, 2, 3},
+	}
+}
+
+func (c Config) validate() error {
+	if len(c.ProductTypes) == 0 {
+		return errors.New("at least one product type is required")
+	}
+
+	if len(c.OrderStatusIds) == 0 {
+		return errors.New("at least one order status is required")
+	}
+
+	if c.Localities < 1 && (c.Sellers > 0 || c.Carriers > 0 || c.Warehouses > 0) {
+		return errors.New("sellers, carriers and warehouses need at least one locality")
+	}
+
+	return nil
+}
+
+// Summary counts the rows created by a run.
+type Summary struct {
+	ProductTypes   int `json:"product_types"`
+	Localities     int `json:"localities"`
+	Sellers        int `json:"sellers"`
+	Products       int `json:"products"`
+	ProductRecords int `json:"product_records"`
+	Warehouses     int `json:"warehouses"`
+	Sections       int `json:"sections"`
+	Batches        int `json:"product_batches"`
+	Employees      int `json:"employees"`
+	InboundOrders  int `json:"inbound_orders"`
+	Buyers         int `json:"buyers"`
+	Carriers       int `json:"carriers"`
+	PurchaseOrders int `json:"purchase_orders"`
+}
+
+// Generator creates a consistent data set: sellers live in generated
+// localities, products are stored in sections of their own product type,
+// every batch arrives through an inbound order handled by an employee of the
+// same warehouse, and purchase orders point to existing price records.
+//
+// The same seed and config always produce the same values. Unique codes are
+// derived from the position of the row, so the target database should not
+// already hold data generated with the same seed.
+type Generator struct {
+	services Services
+	config   Config
+	random   *rand.Rand
+
+	productTypes []productType.ProductType
+	localityIds  []int64
+	buyerIds     []int64
+	products     []product.Product
+	recordIds    []int64
+	summary      Summary
+}
+
+func NewGenerator(services Services, config Config) *Generator {
+	return &Generator{
+		services: services,
+		config:   config,
+		random:   rand.New(rand.NewSource(config.Seed)),
+	}
+}
+
+// Run creates everything in dependency order and stops at the first error.
+// The summary returned with an error tells how far it got.
+func (g *Generator) Run(ctx context.Context) (*Summary, error) {
+	if err := g.config.validate(); err != nil {
+		return &g.summary, err
+	}
+
+	steps := []struct {
+		name string
+		run  func(ctx context.Context) error
+	}{
+		{"product types", g.ensureProductTypes},
+		{"localities", g.createLocalities},
+		{"sellers and products", g.createSellers},
+		{"buyers", g.createBuyers},
+		{"purchase orders", g.createPurchaseOrders},
+		{"carriers", g.createCarriers},
+		{"warehouses", g.createWarehouses},
+	}
+
+	for _, step := range steps {
+		if err := step.run(ctx); err != nil {
+			return &g.summary, fmt.Errorf("generating %s: %w", step.name, err)
+		}
+	}
+
+	return &g.summary, nil
+}
+
+// ensureProductTypes reuses the types that already exist, so the generator
+// can run against a database seeded with the reference data.
+func (g *Generator) ensureProductTypes(ctx context.Context) error {
+	existing, err := g.services.ProductTypes.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	byDescription := map[string]productType.ProductType{}
+	for _, t := range existing {
+		byDescription[t.Description] = t
+	}
+
+	for _, wanted := range g.config.ProductTypes {
+		if found, ok := byDescription[wanted.Description]; ok {
+			g.productTypes = append(g.productTypes, found)
+			continue
+		}
+
+		created, err := g.services.ProductTypes.Create(ctx, &wanted)
+		if err != nil {
+			return err
+		}
+
+		g.productTypes = append(g.productTypes, *created)
+		g.summary.ProductTypes++
+	}
+
+	return nil
+}
+
+func (g *Generator) createLocalities(ctx context.Context) error {
+	for i := 0; i < g.config.Localities; i++ {
+		place := places[g.random.Intn(len(places))]
+
+		created, err := g.services.Localities.CreateLocality(ctx, &locality.LocalityModel{
+			LocalityName: fmt.Sprintf("%s %d", place.locality, i+1),
+			ProvinceName: place.province,
+			CountryName:  place.country,
+		})
+		if err != nil {
+			return err
+		}
+
+		g.localityIds = append(g.localityIds, created.Id)
+		g.summary.Localities++
+	}
+
+	return nil
+}
+
+func (g *Generator) createSellers(ctx context.Context) error {
+	for i := 0; i < g.config.Sellers; i++ {
+		created, err := g.services.Sellers.Create(ctx, &seller.Seller{
+			Cid:         int64(100000 + i + 1),
+			CompanyName: fmt.Sprintf("%s %s", g.pick(companyPrefixes), g.pick(companySuffixes)),
+			Address:     g.address(),
+			Telephone:   g.telephone(),
+			LocalityId:  g.localityId(),
+		})
+		if err != nil {
+			return err
+		}
+		g.summary.Sellers++
+
+		for j := 0; j < g.config.ProductsPerSeller; j++ {
+			if err := g.createProduct(ctx, created.Id); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (g *Generator) createProduct(ctx context.Context, sellerId int64) error {
+	t := g.productType()
+	name := g.pick(productNames)
+
+	created, err := g.services.Products.Create(ctx, &product.Product{
+		ProductCode:                    fmt.Sprintf("GEN-%d-%05d", g.config.Seed, len(g.products)+1),
+		Description:                    name,
+		Width:                          g.between(5, 40),
+		Height:                         g.between(2, 30),
+		Length:                         g.between(5, 50),
+		NetWeight:                      g.between(0.1, 10),
+		ExpirationRate:                 g.between(0.5, 2),
+		RecommendedFreezingTemperature: g.between(t.MinimumTemperature, t.MaximumTemperature),
+		FreezingRate:                   g.between(0, 1.5),
+		ProductTypeId:                  t.Id,
+		SellerId:                       sellerId,
+	})
+	if err != nil {
+		return err
+	}
+
+	g.products = append(g.products, *created)
+	g.summary.Products++
+
+	purchasePrice := g.between(1, 100)
+	record, err := g.services.ProductRecords.Create(ctx, &productRecords.ProductRecords{
+		LastUpdateDate: g.config.Now,
+		PurchasePrice:  purchasePrice,
+		SalePrice:      round(purchasePrice * g.between(1.1, 1.8)),
+		ProductId:      created.Id,
+	})
+	if err != nil {
+		return err
+	}
+
+	g.recordIds = append(g.recordIds, record.Id)
+	g.summary.ProductRecords++
+
+	return nil
+}
+
+func (g *Generator) createBuyers(ctx context.Context) error {
+	for i := 0; i < g.config.Buyers; i++ {
+		created, err := g.services.Buyers.Create(ctx, fmt.Sprintf("B%d-%06d", g.config.Seed, i+1), g.pick(firstNames), g.pick(lastNames))
+		if err != nil {
+			return err
+		}
+
+		g.buyerIds = append(g.buyerIds, created.Id)
+		g.summary.Buyers++
+	}
+
+	return nil
+}
+
+func (g *Generator) createPurchaseOrders(ctx context.Context) error {
+	if len(g.recordIds) == 0 {
+		return nil
+	}
+
+	orderNumber := 0
+	for _, buyerId := range g.buyerIds {
+		for j := 0; j < g.config.PurchaseOrdersPerBuyer; j++ {
+			orderNumber++
+
+			_, err := g.services.PurchaseOrders.Create(
+				ctx,
+				fmt.Sprintf("PO-%d-%06d", g.config.Seed, orderNumber),
+				g.config.Now.AddDate(0, 0, g.random.Intn(30)),
+				fmt.Sprintf("TRK%d%08d", g.config.Seed, g.random.Intn(100000000)),
+				buyerId,
+				g.recordIds[g.random.Intn(len(g.recordIds))],
+				g.config.OrderStatusIds[g.random.Intn(len(g.config.OrderStatusIds))],
+			)
+			if err != nil {
+				return err
+			}
+
+			g.summary.PurchaseOrders++
+		}
+	}
+
+	return nil
+}
+
+func (g *Generator) createCarriers(ctx context.Context) error {
+	for i := 0; i < g.config.Carriers; i++ {
+		_, err := g.services.Carriers.Create(ctx, &carry.CarryModel{
+			Cid:         int64(200000 + i + 1),
+			CompanyName: fmt.Sprintf("%s %s", g.pick(companyPrefixes), g.pick(carrierSuffixes)),
+			Address:     g.address(),
+			Telephone:   g.telephone(),
+			LocalityID:  g.localityId(),
+		})
+		if err != nil {
+			return err
+		}
+
+		g.summary.Carriers++
+	}
+
+	return nil
+}
+
+func (g *Generator) createWarehouses(ctx context.Context) error {
+	for i := 0; i < g.config.Warehouses; i++ {
+		created, err := g.services.Warehouses.Create(
+			ctx,
+			g.address(),
+			g.telephone(),
+			fmt.Sprintf("WH%d-%03d", g.config.Seed, i+1),
+			g.productTypes[0].MinimumTemperature,
+			int64(10+g.random.Intn(40)),
+			g.localityId(),
+		)
+		if err != nil {
+			return err
+		}
+		g.summary.Warehouses++
+
+		employeeIds, err := g.createEmployees(ctx, created.Id)
+		if err != nil {
+			return err
+		}
+
+		for j := 0; j < g.config.SectionsPerWarehouse; j++ {
+			if err := g.createSection(ctx, created.Id, int64(j+1), employeeIds); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (g *Generator) createEmployees(ctx context.Context, warehouseId int64) ([]int64, error) {
+	var ids []int64
+
+	for i := 0; i < g.config.EmployeesPerWarehouse; i++ {
+		created, err := g.services.Employees.Create(
+			ctx,
+			fmt.Sprintf("E%d-%06d", g.config.Seed, g.summary.Employees+1),
+			g.pick(firstNames),
+			g.pick(lastNames),
+			warehouseId,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, created.Id)
+		g.summary.Employees++
+	}
+
+	return ids, nil
+}
+
+// createSection creates a section of one product type and fills it with
+// batches of products of that type. The section starts empty and is sized
+// from the batches up front so it always has room for them; creating the
+// batches fills its current capacity.
+func (g *Generator) createSection(ctx context.Context, warehouseId int64, number int64, employeeIds []int64) error {
+	t := g.productTypes[int(number-1)%len(g.productTypes)]
+
+	candidates := g.productsOfType(t.Id)
+	batches := 0
+	if len(candidates) > 0 {
+		batches = g.config.BatchesPerSection
+	}
+
+	// Part of each batch may already have left the warehouse.
+	type quantity struct{ initial, current int64 }
+	quantities := make([]quantity, batches)
+	var used int64
+	for i := range quantities {
+		initial := int64(20 + g.random.Intn(180))
+		quantities[i] = quantity{initial, initial - int64(g.random.Intn(int(initial/2)+1))}
+		used += quantities[i].current
+	}
+
+	maximumCapacity := used + int64(100+g.random.Intn(400))
+	temperature := g.between(t.MinimumTemperature, t.MaximumTemperature)
+
+	created, err := g.services.Sections.Create(
+		ctx,
+		number,
+		temperature,
+		t.MinimumTemperature,
+		0,
+		maximumCapacity/10,
+		maximumCapacity,
+		warehouseId,
+		t.Id,
+	)
+	if err != nil {
+		return err
+	}
+	g.summary.Sections++
+
+	for _, q := range quantities {
+		p := candidates[g.random.Intn(len(candidates))]
+		manufacturing := g.config.Now.AddDate(0, 0, -g.random.Intn(20)).Truncate(24 * time.Hour)
+
+		batch, err := g.services.Batches.Create(ctx, &productBatch.ProductBatch{
+			BatchNumber:        int64(g.summary.Batches + 1),
+			CurrentQuantity:    q.current,
+			CurrentTemperature: temperature,
+			DueDate:            manufacturing.AddDate(0, 0, 10+g.random.Intn(80)),
+			InitialQuantity:    q.initial,
+			ManufacturingDate:  manufacturing,
+			ManufacturingHour:  int64(g.random.Intn(24)),
+			MinumumTemperature: t.MinimumTemperature,
+			ProductId:          p.Id,
+			SectionId:          created.Id,
+		})
+		if err != nil {
+			return err
+		}
+		g.summary.Batches++
+
+		if len(employeeIds) == 0 {
+			continue
+		}
+
+		_, err = g.services.InboundOrders.Create(
+			ctx,
+			manufacturing.AddDate(0, 0, 1),
+			fmt.Sprintf("IO-%d-%06d", g.config.Seed, g.summary.InboundOrders+1),
+			employeeIds[g.random.Intn(len(employeeIds))],
+			batch.Id,
+			warehouseId,
+		)
+		if err != nil {
+			return err
+		}
+		g.summary.InboundOrders++
+	}
+
+	return nil
+}
+
+func (g *Generator) productsOfType(productTypeId int64) []product.Product {
+	var result []product.Product
+	for _, p := range g.products {
+		if p.ProductTypeId == productTypeId {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+func (g *Generator) productType() productType.ProductType {
+	return g.productTypes[g.random.Intn(len(g.productTypes))]
+}
+
+func (g *Generator) localityId() int64 {
+	return g.localityIds[g.random.Intn(len(g.localityIds))]
+}
+
+func (g *Generator) pick(values []string) string {
+	return values[g.random.Intn(len(values))]
+}
+
+func (g *Generator) between(min, max float64) float64 {
+	return round(min + g.random.Float64()*(max-min))
+}
+
+func (g *Generator) address() string {
+	return fmt.Sprintf("%s %d", g.pick(streets), 1+g.random.Intn(3000))
+}
+
+func (g *Generator) telephone() string {
+	return fmt.Sprintf("%02d 9%04d-%04d", 11+g.random.Intn(89), g.random.Intn(10000), g.random.Intn(10000))
+}
+
+// round keeps two decimals, the precision of the DECIMAL(19,2) columns.
+func round(value float64) float64 {
+	return float64(int64(value*100+0.5*sign(value))) / 100
+}
+
+func sign(value float64) float64 {
+	if value < 0 {
+		return -1
+	}
+	return 1
+}
@@ -0,0 +1,348 @@
+package seed_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	buyer "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/buyer/domain"
+	buyerMocks "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/buyer/domain/mocks"
+	carry "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/carry/domain"
+	carryMocks "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/carry/domain/mocks"
+	employee "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/employees/domain"
+	employeeMocks "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/employees/domain/mocks"
+	inboundOrders "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/inboud_orders/domain"
+	inboundOrdersMocks "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/inboud_orders/domain/mocks"
+	locality "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/locality/domain"
+	localityMocks "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/locality/domain/mocks"
+	product "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product/domain"
+	productMocks "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product/domain/mocks"
+	productBatch "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_batch/domain"
+	productBatchMocks "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_batch/domain/mocks"
+	productRecords "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_records/domain"
+	productRecordsMocks "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_records/domain/mocks"
+	productType "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_type/domain"
+	productTypeMocks "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_type/domain/mocks"
+	purchaseOrders "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/purchase_orders/domain"
+	purchaseOrdersMocks "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/purchase_orders/domain/mocks"
+	section "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/section/domain"
+	sectionMocks "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/section/domain/mocks"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/seed"
+	seller "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/seller/domain"
+	sellerMocks "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/seller/domain/mocks"
+	warehouse "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/warehouse/domain"
+	warehouseMocks "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/warehouse/domain/mocks"
+)
+
+// recorder plays the database: it hands out ids and keeps what was created
+// so the tests can check the relationships between the generated rows.
+type recorder struct {
+	nextId int64
+	calls  []string
+
+	productTypes      map[int64]int64
+	sectionTypes      map[int64]int64
+	sectionWarehouses map[int64]int64
+	employeeWarehouse map[int64]int64
+	batchSections     map[int64]int64
+	sections          []section.SectionModel
+	batches           []productBatch.ProductBatch
+	inboundOrders     []inboundOrders.InboundOrders
+	purchaseOrders    []purchaseOrders.PurchaseOrders
+}
+
+func (r *recorder) id() int64 {
+	r.nextId++
+	return r.nextId
+}
+
+func (r *recorder) record(name string, args ...interface{}) {
+	r.calls = append(r.calls, fmt.Sprint(name, args))
+}
+
+func newServices(t *testing.T, r *recorder) seed.Services {
+	r.productTypes = map[int64]int64{}
+	r.sectionTypes = map[int64]int64{}
+	r.sectionWarehouses = map[int64]int64{}
+	r.employeeWarehouse = map[int64]int64{}
+	r.batchSections = map[int64]int64{}
+
+	// "Frozen" already exists, the other default types get created.
+	productTypes := productTypeMocks.NewProductTypeService(t)
+	productTypes.
+		On("GetAll", mock.Anything).
+		Return([]productType.ProductType{{Id: 900, Description: "Frozen", Category: productType.CategoryFrozen, MinimumTemperature: -25, MaximumTemperature: -18}}, nil).
+		Maybe()
+	productTypes.
+		On("Create", mock.Anything, mock.Anything).
+		Return(func(_ context.Context, p *productType.ProductType) *productType.ProductType {
+			r.record("product_type", *p)
+			p.Id = r.id()
+			return p
+		}, nil).
+		Maybe()
+
+	localities := localityMocks.NewLocalityService(t)
+	localities.
+		On("CreateLocality", mock.Anything, mock.Anything).
+		Return(func(_ context.Context, l *locality.LocalityModel) *locality.LocalityModel {
+			r.record("locality", *l)
+			l.Id = r.id()
+			return l
+		}, nil).
+		Maybe()
+
+	sellers := sellerMocks.NewServiceSeller(t)
+	sellers.
+		On("Create", mock.Anything, mock.Anything).
+		Return(func(_ context.Context, s *seller.Seller) *seller.Seller {
+			r.record("seller", *s)
+			s.Id = r.id()
+			return s
+		}, nil).
+		Maybe()
+
+	products := productMocks.NewProductService(t)
+	products.
+		On("Create", mock.Anything, mock.Anything).
+		Return(func(_ context.Context, p *product.Product) *product.Product {
+			r.record("product", *p)
+			p.Id = r.id()
+			r.productTypes[p.Id] = p.ProductTypeId
+			return p
+		}, nil).
+		Maybe()
+
+	records := productRecordsMocks.NewProductRecordsService(t)
+	records.
+		On("Create", mock.Anything, mock.Anything).
+		Return(func(_ context.Context, p *productRecords.ProductRecords) *productRecords.ProductRecords {
+			r.record("record", *p)
+			p.Id = r.id()
+			return p
+		}, nil).
+		Maybe()
+
+	buyers := buyerMocks.NewBuyerService(t)
+	buyers.
+		On("Create", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(func(_ context.Context, cardNumberId, firstName, lastName string) *buyer.Buyer {
+			r.record("buyer", cardNumberId, firstName, lastName)
+			return &buyer.Buyer{Id: r.id(), CardNumberId: cardNumberId, FirstName: firstName, LastName: lastName}
+		}, nil).
+		Maybe()
+
+	orders := purchaseOrdersMocks.NewPurchaseOrdersService(t)
+	orders.
+		On("Create", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(func(_ context.Context, orderNumber string, orderDate time.Time, trackingCode string, buyerId, recordId, statusId int64) *purchaseOrders.PurchaseOrders {
+			r.record("purchase_order", orderNumber, orderDate, trackingCode, buyerId, recordId, statusId)
+			order := purchaseOrders.PurchaseOrders{Id: r.id(), OrderNumber: orderNumber, OrderDate: orderDate, TrackingCode: trackingCode, BuyerId: buyerId, ProductRecordId: recordId, OrderStatusId: statusId}
+			r.purchaseOrders = append(r.purchaseOrders, order)
+			return &order
+		}, nil).
+		Maybe()
+
+	carriers := carryMocks.NewCarryService(t)
+	carriers.
+		On("Create", mock.Anything, mock.Anything).
+		Return(func(_ context.Context, c *carry.CarryModel) *carry.CarryModel {
+			r.record("carrier", *c)
+			c.Id = r.id()
+			return c
+		}, nil).
+		Maybe()
+
+	warehouses := warehouseMocks.NewWarehouseService(t)
+	warehouses.
+		On("Create", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(func(_ context.Context, address, telephone, code string, minTemperature float64, minCapacity int64, localityId int64) warehouse.WarehouseModel {
+			r.record("warehouse", address, telephone, code, minTemperature, minCapacity, localityId)
+			return warehouse.WarehouseModel{Id: r.id(), WarehouseCode: code}
+		}, nil).
+		Maybe()
+
+	employees := employeeMocks.NewEmployeeService(t)
+	employees.
+		On("Create", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(func(_ context.Context, cardNumberId, firstName, lastName string, warehouseId int64) employee.Employee {
+			r.record("employee", cardNumberId, firstName, lastName, warehouseId)
+			e := employee.Employee{Id: r.id(), CardNumberId: cardNumberId, WarehouseId: warehouseId}
+			r.employeeWarehouse[e.Id] = warehouseId
+			return e
+		}, nil).
+		Maybe()
+
+	sections := sectionMocks.NewSectionService(t)
+	sections.
+		On("Create", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(func(_ context.Context, number int64, currentTemperature, minimumTemperature float64, currentCapacity, minimumCapacity, maximumCapacity, warehouseId, productTypeId int64) section.SectionModel {
+			r.record("section", number, currentTemperature, minimumTemperature, currentCapacity, minimumCapacity, maximumCapacity, warehouseId, productTypeId)
+			s := section.SectionModel{
+				Id:                 r.id(),
+				SectionNumber:      number,
+				CurrentTemperature: currentTemperature,
+				MinimumTemperature: minimumTemperature,
+				CurrentCapacity:    currentCapacity,
+				MinimumCapacity:    minimumCapacity,
+				MaximumCapacity:    maximumCapacity,
+				WarehouseId:        warehouseId,
+				ProductTypeId:      productTypeId,
+			}
+			r.sections = append(r.sections, s)
+			r.sectionTypes[s.Id] = productTypeId
+			r.sectionWarehouses[s.Id] = warehouseId
+			return s
+		}, nil).
+		Maybe()
+
+	batches := productBatchMocks.NewProductBatchService(t)
+	batches.
+		On("Create", mock.Anything, mock.Anything).
+		Return(func(_ context.Context, b *productBatch.ProductBatch) *productBatch.ProductBatch {
+			r.record("batch", *b)
+			b.Id = r.id()
+			r.batches = append(r.batches, *b)
+			r.batchSections[b.Id] = b.SectionId
+			// Like the repository, a batch takes its quantity from the section.
+			for i := range r.sections {
+				if r.sections[i].Id == b.SectionId {
+					r.sections[i].CurrentCapacity += b.CurrentQuantity
+				}
+			}
+			return b
+		}, nil).
+		Maybe()
+
+	inbound := inboundOrdersMocks.NewInboundOrdersService(t)
+	inbound.
+		On("Create", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(func(_ context.Context, orderDate time.Time, orderNumber string, employeeId, batchId, warehouseId int64) inboundOrders.InboundOrders {
+			r.record("inbound_order", orderDate, orderNumber, employeeId, batchId, warehouseId)
+			order := inboundOrders.InboundOrders{Id: r.id(), OrderDate: orderDate, OrderNumber: orderNumber, EmployeeId: employeeId, ProductBatchId: batchId, WarehouseId: warehouseId}
+			r.inboundOrders = append(r.inboundOrders, order)
+			return order
+		}, nil).
+		Maybe()
+
+	return seed.Services{
+		ProductTypes:   productTypes,
+		Localities:     localities,
+		Sellers:        sellers,
+		Products:       products,
+		ProductRecords: records,
+		Warehouses:     warehouses,
+		Sections:       sections,
+		Batches:        batches,
+		Buyers:         buyers,
+		Carriers:       carriers,
+		Employees:      employees,
+		InboundOrders:  inbound,
+		PurchaseOrders: orders,
+	}
+}
+
+func testConfig(seedValue int64) seed.Config {
+	config := seed.DefaultConfig(seedValue, 1)
+	config.Now = time.Date(2030, 1, 15, 0, 0, 0, 0, time.UTC)
+	return config
+}
+
+func TestGenerator_Run(t *testing.T) {
+	t.Run("run_ok: should create the configured volume", func(t *testing.T) {
+		r := &recorder{}
+		config := testConfig(42)
+
+		summary, err := seed.NewGenerator(newServices(t, r), config).Run(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, summary.ProductTypes)
+		assert.Equal(t, config.Localities, summary.Localities)
+		assert.Equal(t, config.Sellers, summary.Sellers)
+		assert.Equal(t, config.Sellers*config.ProductsPerSeller, summary.Products)
+		assert.Equal(t, summary.Products, summary.ProductRecords)
+		assert.Equal(t, config.Warehouses, summary.Warehouses)
+		assert.Equal(t, config.Warehouses*config.SectionsPerWarehouse, summary.Sections)
+		assert.Equal(t, config.Warehouses*config.EmployeesPerWarehouse, summary.Employees)
+		assert.Equal(t, summary.Batches, summary.InboundOrders)
+		assert.Equal(t, config.Buyers, summary.Buyers)
+		assert.Equal(t, config.Buyers*config.PurchaseOrdersPerBuyer, summary.PurchaseOrders)
+		assert.Equal(t, config.Carriers, summary.Carriers)
+	})
+
+	t.Run("run_consistent: should keep relationships and invariants", func(t *testing.T) {
+		r := &recorder{}
+		config := testConfig(7)
+
+		_, err := seed.NewGenerator(newServices(t, r), config).Run(context.Background())
+		assert.NoError(t, err)
+
+		assert.NotEmpty(t, r.batches)
+		assert.NotEmpty(t, r.inboundOrders)
+
+		used := map[int64]int64{}
+		for _, b := range r.batches {
+			assert.Equal(t, r.sectionTypes[b.SectionId], r.productTypes[b.ProductId], "batch stored in a section of another product type")
+			assert.True(t, b.CurrentQuantity >= 0 && b.CurrentQuantity <= b.InitialQuantity)
+			assert.False(t, b.DueDate.Before(b.ManufacturingDate))
+			used[b.SectionId] += b.CurrentQuantity
+		}
+
+		for _, s := range r.sections {
+			assert.Equal(t, used[s.Id], s.CurrentCapacity)
+			assert.True(t, s.MinimumCapacity <= s.MaximumCapacity)
+			assert.True(t, s.CurrentCapacity <= s.MaximumCapacity)
+		}
+
+		for _, o := range r.inboundOrders {
+			assert.Equal(t, o.WarehouseId, r.employeeWarehouse[o.EmployeeId], "employee from another warehouse")
+			assert.Equal(t, o.WarehouseId, r.sectionWarehouses[r.batchSections[o.ProductBatchId]])
+		}
+
+		for _, o := range r.purchaseOrders {
+			assert.False(t, o.OrderDate.Before(config.Now), "purchase order dated in the past")
+		}
+	})
+
+	t.Run("run_deterministic: should produce the same data for the same seed", func(t *testing.T) {
+		first, second, other := &recorder{}, &recorder{}, &recorder{}
+
+		_, err := seed.NewGenerator(newServices(t, first), testConfig(1)).Run(context.Background())
+		assert.NoError(t, err)
+		_, err = seed.NewGenerator(newServices(t, second), testConfig(1)).Run(context.Background())
+		assert.NoError(t, err)
+		_, err = seed.NewGenerator(newServices(t, other), testConfig(2)).Run(context.Background())
+		assert.NoError(t, err)
+
+		assert.Equal(t, first.calls, second.calls)
+		assert.NotEqual(t, first.calls, other.calls)
+	})
+
+	t.Run("run_error: should stop at the first error and report progress", func(t *testing.T) {
+		r := &recorder{}
+		services := newServices(t, r)
+
+		sellers := sellerMocks.NewServiceSeller(t)
+		sellers.On("Create", mock.Anything, mock.Anything).Return(nil, errors.New("duplicate cid")).Once()
+		services.Sellers = sellers
+
+		summary, err := seed.NewGenerator(services, testConfig(1)).Run(context.Background())
+
+		assert.EqualError(t, err, "generating sellers and products: duplicate cid")
+		assert.Equal(t, 10, summary.Localities)
+		assert.Equal(t, 0, summary.Sellers)
+	})
+
+	t.Run("run_invalid_config: should reject a config without product types", func(t *testing.T) {
+		config := testConfig(1)
+		config.ProductTypes = nil
+
+		_, err := seed.NewGenerator(seed.Services{}, config).Run(context.Background())
+
+		assert.EqualError(t, err, "at least one product type is required")
+	})
+}
@@ -0,0 +1,53 @@
+package seed
+
+type place struct {
+	locality, province, country string
+}
+
+var places = []place{
+	{"Sao Paulo", "Sao Paulo", "Brazil"},
+	{"Campinas", "Sao Paulo", "Brazil"},
+	{"Santos", "Sao Paulo", "Brazil"},
+	{"Belo Horizonte", "Minas Gerais", "Brazil"},
+	{"Uberlandia", "Minas Gerais", "Brazil"},
+	{"Curitiba", "Parana", "Brazil"},
+	{"Porto Alegre", "Rio Grande do Sul", "Brazil"},
+	{"Buenos Aires", "Buenos Aires", "Argentina"},
+	{"Mar del Plata", "Buenos Aires", "Argentina"},
+	{"Cordoba", "Cordoba", "Argentina"},
+	{"Montevideo", "Montevideo", "Uruguay"},
+	{"Medellin", "Antioquia", "Colombia"},
+	{"Bogota", "Cundinamarca", "Colombia"},
+	{"Guadalajara", "Jalisco", "Mexico"},
+}
+
+var companyPrefixes = []string{
+	"Vale Verde", "Serra Azul", "Atlantico", "Pampa", "Sol Nascente",
+	"Rio Claro", "Campo Bello", "Horizonte", "Costa Sul", "Boa Safra",
+}
+
+var companySuffixes = []string{"Alimentos", "Distribuidora", "Hortifruti", "Laticinios", "Pescados", "Carnes"}
+
+var carrierSuffixes = []string{"Transportes", "Logistica", "Cargas", "Express"}
+
+var productNames = []string{
+	"Banana prata", "Morango", "Manga palmer", "Alface crespa", "Tomate italiano",
+	"Queijo minas", "Iogurte natural", "Manteiga", "Leite integral",
+	"File de tilapia", "Camarao", "Salmao", "Bife de chorizo", "Frango inteiro",
+	"Sorvete de creme", "Pao de queijo congelado", "Arroz", "Feijao preto", "Cafe torrado",
+}
+
+var firstNames = []string{
+	"Ana", "Bruno", "Carla", "Diego", "Elisa", "Felipe", "Gabriela", "Hugo",
+	"Isabela", "Joao", "Larissa", "Mateus", "Natalia", "Otavio", "Paula", "Rafael",
+}
+
+var lastNames = []string{
+	"Silva", "Santos", "Oliveira", "Souza", "Pereira", "Costa", "Rodrigues",
+	"Almeida", "Nascimento", "Lima", "Araujo", "Fernandes", "Gomez", "Martinez",
+}
+
+var streets = []string{
+	"Rua das Laranjeiras", "Avenida Brasil", "Rua do Porto", "Avenida Corrientes",
+	"Rua XV de Novembro", "Avenida Paulista", "Rua da Praia", "Avenida Rivadavia",
+}
@@ -2,6 +2,14 @@ package domain
 
 import (
 	"context"
+	"time"
+
+	product "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product/domain"
+)
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
 )
 
 type Seller struct {
@@ -13,12 +21,46 @@ type Seller struct {
 	LocalityId  int64  `json:"locality_id"`
 }
 
+// SellerProduct is a product of the seller with its current sale price, the
+// latest product record up to now. Both price fields are null without one.
+type SellerProduct struct {
+	product.Product
+	CurrentPrice   *float64   `json:"current_price"`
+	PriceUpdatedAt *time.Time `json:"price_updated_at"`
+}
+
+type SellerProductsPage struct {
+	Products []SellerProduct `json:"products"`
+	Page     int64           `json:"page"`
+	PageSize int64           `json:"page_size"`
+	Total    int64           `json:"total"`
+}
+
+type ProductTypeCount struct {
+	ProductTypeId int64  `json:"product_type_id"`
+	Description   string `json:"description"`
+	Products      int64  `json:"products"`
+}
+
+// SellerSummary counts the catalog of a seller. Active batches are those
+// with stock left that are not past their due date.
+type SellerSummary struct {
+	SellerId             int64              `json:"seller_id"`
+	Products             int64              `json:"products"`
+	ProductsByType       []ProductTypeCount `json:"products_by_type"`
+	ProductsWithoutPrice int64              `json:"products_without_price"`
+	ActiveBatches        int64              `json:"active_batches"`
+	ActiveStock          int64              `json:"active_stock"`
+}
+
 type ServiceSeller interface {
 	GetAll(ctx context.Context) (*[]Seller, error)
 	GetById(ctx context.Context, id int64) (*Seller, error)
 	Create(ctx context.Context, seller *Seller) (*Seller, error)
 	Update(ctx context.Context, id int64, adress, telephone string) (*Seller, error)
 	Delete(ctx context.Context, id int64) error
+	GetProducts(ctx context.Context, id, page, pageSize int64) (*SellerProductsPage, error)
+	GetSummary(ctx context.Context, id int64) (*SellerSummary, error)
 }
 
 type RepositorySeller interface {
@@ -28,4 +70,9 @@ type RepositorySeller interface {
 	Update(ctx context.Context, seller *Seller) (*Seller, error)
 	Delete(ctx context.Context, id int64) error
 	CountByLocalityId(ctx context.Context, localityId int64) (int64, error)
+	GetProducts(ctx context.Context, id int64, now time.Time, limit, offset int64) ([]SellerProduct, error)
+	CountProducts(ctx context.Context, id int64) (int64, error)
+	CountProductsByType(ctx context.Context, id int64) ([]ProductTypeCount, error)
+	CountProductsWithoutPrice(ctx context.Context, id int64, now time.Time) (int64, error)
+	GetActiveStock(ctx context.Context, id int64, now time.Time) (batches int64, quantity int64, err error)
 }
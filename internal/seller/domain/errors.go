@@ -3,5 +3,6 @@ package domain
 import "errors"
 
 var (
-	ErrIDNotFound = errors.New("seller id not found")
+	ErrIDNotFound  = errors.New("seller id not found")
+	ErrInvalidPage = errors.New("page must be positive and page_size between 1 and 100")
 )
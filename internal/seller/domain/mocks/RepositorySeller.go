@@ -7,6 +7,8 @@ import (
 
 	mock "github.com/stretchr/testify/mock"
 	domain "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/seller/domain"
+
+	time "time"
 )
 
 // RepositorySeller is an autogenerated mock type for the RepositorySeller type
@@ -35,6 +37,71 @@ func (_m *RepositorySeller) CountByLocalityId(ctx context.Context, localityId in
 	return r0, r1
 }
 
+// CountProducts provides a mock function with given fields: ctx, id
+func (_m *RepositorySeller) CountProducts(ctx context.Context, id int64) (int64, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, int64) int64); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CountProductsByType provides a mock function with given fields: ctx, id
+func (_m *RepositorySeller) CountProductsByType(ctx context.Context, id int64) ([]domain.ProductTypeCount, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 []domain.ProductTypeCount
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []domain.ProductTypeCount); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.ProductTypeCount)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CountProductsWithoutPrice provides a mock function with given fields: ctx, id, now
+func (_m *RepositorySeller) CountProductsWithoutPrice(ctx context.Context, id int64, now time.Time) (int64, error) {
+	ret := _m.Called(ctx, id, now)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time) int64); ok {
+		r0 = rf(ctx, id, now)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, time.Time) error); ok {
+		r1 = rf(ctx, id, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Create provides a mock function with given fields: ctx, seller
 func (_m *RepositorySeller) Create(ctx context.Context, seller *domain.Seller) (*domain.Seller, error) {
 	ret := _m.Called(ctx, seller)
@@ -72,6 +139,34 @@ func (_m *RepositorySeller) Delete(ctx context.Context, id int64) error {
 	return r0
 }
 
+// GetActiveStock provides a mock function with given fields: ctx, id, now
+func (_m *RepositorySeller) GetActiveStock(ctx context.Context, id int64, now time.Time) (int64, int64, error) {
+	ret := _m.Called(ctx, id, now)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time) int64); ok {
+		r0 = rf(ctx, id, now)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 int64
+	if rf, ok := ret.Get(1).(func(context.Context, int64, time.Time) int64); ok {
+		r1 = rf(ctx, id, now)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, int64, time.Time) error); ok {
+		r2 = rf(ctx, id, now)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetAll provides a mock function with given fields: ctx
 func (_m *RepositorySeller) GetAll(ctx context.Context) (*[]domain.Seller, error) {
 	ret := _m.Called(ctx)
@@ -118,6 +213,29 @@ func (_m *RepositorySeller) GetById(ctx context.Context, id int64) (*domain.Sell
 	return r0, r1
 }
 
+// GetProducts provides a mock function with given fields: ctx, id, now, limit, offset
+func (_m *RepositorySeller) GetProducts(ctx context.Context, id int64, now time.Time, limit int64, offset int64) ([]domain.SellerProduct, error) {
+	ret := _m.Called(ctx, id, now, limit, offset)
+
+	var r0 []domain.SellerProduct
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time, int64, int64) []domain.SellerProduct); ok {
+		r0 = rf(ctx, id, now, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.SellerProduct)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, time.Time, int64, int64) error); ok {
+		r1 = rf(ctx, id, now, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Update provides a mock function with given fields: ctx, seller
 func (_m *RepositorySeller) Update(ctx context.Context, seller *domain.Seller) (*domain.Seller, error) {
 	ret := _m.Called(ctx, seller)
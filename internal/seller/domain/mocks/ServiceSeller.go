@@ -97,6 +97,52 @@ func (_m *ServiceSeller) GetById(ctx context.Context, id int64) (*domain.Seller,
 	return r0, r1
 }
 
+// GetProducts provides a mock function with given fields: ctx, id, page, pageSize
+func (_m *ServiceSeller) GetProducts(ctx context.Context, id int64, page int64, pageSize int64) (*domain.SellerProductsPage, error) {
+	ret := _m.Called(ctx, id, page, pageSize)
+
+	var r0 *domain.SellerProductsPage
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, int64) *domain.SellerProductsPage); ok {
+		r0 = rf(ctx, id, page, pageSize)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.SellerProductsPage)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64, int64) error); ok {
+		r1 = rf(ctx, id, page, pageSize)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSummary provides a mock function with given fields: ctx, id
+func (_m *ServiceSeller) GetSummary(ctx context.Context, id int64) (*domain.SellerSummary, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *domain.SellerSummary
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *domain.SellerSummary); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.SellerSummary)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Update provides a mock function with given fields: ctx, id, adress, telephone
 func (_m *ServiceSeller) Update(ctx context.Context, id int64, adress string, telephone string) (*domain.Seller, error) {
 	ret := _m.Called(ctx, id, adress, telephone)
@@ -25,4 +25,44 @@ const (
 
 	QueryCountByLocalityId = `
     SELECT COUNT(*) FROM sellers WHERE locality_id = ?`
+
+	SqlGetSellerProducts = `
+	SELECT p.id, p.product_code, p.description, p.width, p.height, p.length, p.net_weight,
+	p.expiration_rate, p.recommended_freezing_temperature, p.freezing_rate, p.product_type_id, p.seller_id,
+	pr.sale_price, pr.last_update_date
+	FROM products p
+	LEFT JOIN product_records pr ON pr.id = (
+		SELECT r.id FROM product_records r
+		WHERE r.product_id = p.id AND r.last_update_date <= ?
+		ORDER BY r.last_update_date DESC, r.id DESC
+		LIMIT 1
+	)
+	WHERE p.seller_id = ?
+	ORDER BY p.id
+	LIMIT ? OFFSET ?`
+
+	SqlCountSellerProducts = `
+	SELECT COUNT(*) FROM products WHERE seller_id = ?`
+
+	SqlCountSellerProductsByType = `
+	SELECT p.product_type_id, IFNULL(pt.description, ''), COUNT(*)
+	FROM products p
+	LEFT JOIN product_types pt ON pt.id = p.product_type_id
+	WHERE p.seller_id = ?
+	GROUP BY p.product_type_id, pt.description
+	ORDER BY p.product_type_id`
+
+	SqlCountSellerProductsWithoutPrice = `
+	SELECT COUNT(*) FROM products p
+	WHERE p.seller_id = ?
+	AND NOT EXISTS (
+		SELECT 1 FROM product_records pr
+		WHERE pr.product_id = p.id AND pr.last_update_date <= ?
+	)`
+
+	SqlGetSellerActiveStock = `
+	SELECT COUNT(pb.id), IFNULL(SUM(pb.current_quantity), 0)
+	FROM product_batches pb
+	JOIN products p ON p.id = pb.product_id
+	WHERE p.seller_id = ? AND pb.status = 'active' AND pb.current_quantity > 0 AND pb.due_date >= ?`
 )
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/seller/domain"
 )
@@ -137,3 +138,103 @@ func (m *mariaDBSellerRepository) CountByLocalityId(ctx context.Context, localit
 
 	return countSellersInLocalityId, nil
 }
+
+func (m *mariaDBSellerRepository) GetProducts(ctx context.Context, id int64, now time.Time, limit, offset int64) ([]domain.SellerProduct, error) {
+	rows, err := m.db.QueryContext(ctx, SqlGetSellerProducts, now, id, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	products := []domain.SellerProduct{}
+
+	for rows.Next() {
+		var product domain.SellerProduct
+		var price sql.NullFloat64
+		var priceDate sql.NullTime
+
+		if err := rows.Scan(
+			&product.Id,
+			&product.ProductCode,
+			&product.Description,
+			&product.Width,
+			&product.Height,
+			&product.Length,
+			&product.NetWeight,
+			&product.ExpirationRate,
+			&product.RecommendedFreezingTemperature,
+			&product.FreezingRate,
+			&product.ProductTypeId,
+			&product.SellerId,
+			&price,
+			&priceDate,
+		); err != nil {
+			return nil, err
+		}
+
+		if price.Valid {
+			product.CurrentPrice = &price.Float64
+		}
+		if priceDate.Valid {
+			product.PriceUpdatedAt = &priceDate.Time
+		}
+
+		products = append(products, product)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return products, nil
+}
+
+func (m *mariaDBSellerRepository) CountProducts(ctx context.Context, id int64) (int64, error) {
+	var count int64
+	if err := m.db.QueryRowContext(ctx, SqlCountSellerProducts, id).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (m *mariaDBSellerRepository) CountProductsByType(ctx context.Context, id int64) ([]domain.ProductTypeCount, error) {
+	rows, err := m.db.QueryContext(ctx, SqlCountSellerProductsByType, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := []domain.ProductTypeCount{}
+
+	for rows.Next() {
+		var count domain.ProductTypeCount
+
+		if err := rows.Scan(&count.ProductTypeId, &count.Description, &count.Products); err != nil {
+			return nil, err
+		}
+
+		counts = append(counts, count)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+func (m *mariaDBSellerRepository) CountProductsWithoutPrice(ctx context.Context, id int64, now time.Time) (int64, error) {
+	var count int64
+	if err := m.db.QueryRowContext(ctx, SqlCountSellerProductsWithoutPrice, id, now).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (m *mariaDBSellerRepository) GetActiveStock(ctx context.Context, id int64, now time.Time) (int64, int64, error) {
+	var batches, quantity int64
+	if err := m.db.QueryRowContext(ctx, SqlGetSellerActiveStock, id, now).Scan(&batches, &quantity); err != nil {
+		return 0, 0, err
+	}
+	return batches, quantity, nil
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
@@ -378,3 +379,143 @@ func TestSellerRepository_CountByLocalityId(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func TestSellerRepository_GetProducts(t *testing.T) {
+	now := time.Date(2022, 5, 1, 12, 0, 0, 0, time.UTC)
+	columns := []string{
+		"id", "product_code", "description", "width", "height", "length", "net_weight",
+		"expiration_rate", "recommended_freezing_temperature", "freezing_rate", "product_type_id", "seller_id",
+		"sale_price", "last_update_date",
+	}
+
+	t.Run("get_products_ok: should return the page with current prices", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		priceDate := now.AddDate(0, 0, -3)
+
+		mock.
+			ExpectQuery(regexp.QuoteMeta(repository.SqlGetSellerProducts)).
+			WithArgs(now, int64(1), int64(20), int64(20)).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(21, "FRU-0021", "Banana", 12.5, 4.2, 18, 1, 0.8, 10, 0, 3, 1, 7.5, priceDate).
+				AddRow(22, "FRU-0022", "Morango", 10, 6, 15, 0.5, 1.5, 2, 0.5, 2, 1, nil, nil))
+
+		sellerRepository := repository.NewMariaDBSellerRepository(db)
+
+		products, err := sellerRepository.GetProducts(context.TODO(), 1, now, 20, 20)
+
+		assert.NoError(t, err)
+		assert.Len(t, products, 2)
+		assert.Equal(t, int64(21), products[0].Id)
+		assert.Equal(t, 7.5, *products[0].CurrentPrice)
+		assert.Equal(t, priceDate, *products[0].PriceUpdatedAt)
+		assert.Nil(t, products[1].CurrentPrice)
+		assert.Nil(t, products[1].PriceUpdatedAt)
+	})
+
+	t.Run("get_products_fail: should return error when query fails", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.
+			ExpectQuery(regexp.QuoteMeta(repository.SqlGetSellerProducts)).
+			WillReturnError(errors.New("any error"))
+
+		sellerRepository := repository.NewMariaDBSellerRepository(db)
+
+		_, err = sellerRepository.GetProducts(context.TODO(), 1, now, 20, 0)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestSellerRepository_Summary(t *testing.T) {
+	now := time.Date(2022, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("count_products_ok: should count the seller products", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.
+			ExpectQuery(regexp.QuoteMeta(repository.SqlCountSellerProducts)).
+			WithArgs(int64(1)).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(42))
+
+		count, err := repository.NewMariaDBSellerRepository(db).CountProducts(context.TODO(), 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(42), count)
+	})
+
+	t.Run("count_products_by_type_ok: should count the seller products of each type", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.
+			ExpectQuery(regexp.QuoteMeta(repository.SqlCountSellerProductsByType)).
+			WithArgs(int64(1)).
+			WillReturnRows(sqlmock.NewRows([]string{"product_type_id", "description", "count"}).
+				AddRow(1, "Frozen", 2).
+				AddRow(3, "Ambient", 5))
+
+		counts, err := repository.NewMariaDBSellerRepository(db).CountProductsByType(context.TODO(), 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.ProductTypeCount{
+			{ProductTypeId: 1, Description: "Frozen", Products: 2},
+			{ProductTypeId: 3, Description: "Ambient", Products: 5},
+		}, counts)
+	})
+
+	t.Run("count_products_without_price_ok: should count products without records in effect", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.
+			ExpectQuery(regexp.QuoteMeta(repository.SqlCountSellerProductsWithoutPrice)).
+			WithArgs(int64(1), now).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+		count, err := repository.NewMariaDBSellerRepository(db).CountProductsWithoutPrice(context.TODO(), 1, now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), count)
+	})
+
+	t.Run("get_active_stock_ok: should return active batches and their quantity", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.
+			ExpectQuery(regexp.QuoteMeta(repository.SqlGetSellerActiveStock)).
+			WithArgs(int64(1), now).
+			WillReturnRows(sqlmock.NewRows([]string{"batches", "quantity"}).AddRow(4, 350))
+
+		batches, quantity, err := repository.NewMariaDBSellerRepository(db).GetActiveStock(context.TODO(), 1, now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(4), batches)
+		assert.Equal(t, int64(350), quantity)
+	})
+
+	t.Run("get_active_stock_fail: should return error when query fails", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.
+			ExpectQuery(regexp.QuoteMeta(repository.SqlGetSellerActiveStock)).
+			WillReturnError(errors.New("any error"))
+
+		_, _, err = repository.NewMariaDBSellerRepository(db).GetActiveStock(context.TODO(), 1, now)
+
+		assert.Error(t, err)
+	})
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"time"
 
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/seller/domain"
 )
@@ -64,3 +65,67 @@ func (s *service) Delete(ctx context.Context, id int64) error {
 	}
 	return nil
 }
+
+func (s *service) GetProducts(ctx context.Context, id, page, pageSize int64) (*domain.SellerProductsPage, error) {
+	if page < 1 || pageSize < 1 || pageSize > domain.MaxPageSize {
+		return nil, domain.ErrInvalidPage
+	}
+
+	if _, err := s.repository.GetById(ctx, id); err != nil {
+		return nil, err
+	}
+
+	total, err := s.repository.CountProducts(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	products, err := s.repository.GetProducts(ctx, id, time.Now().UTC(), pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.SellerProductsPage{
+		Products: products,
+		Page:     page,
+		PageSize: pageSize,
+		Total:    total,
+	}, nil
+}
+
+func (s *service) GetSummary(ctx context.Context, id int64) (*domain.SellerSummary, error) {
+	if _, err := s.repository.GetById(ctx, id); err != nil {
+		return nil, err
+	}
+
+	byType, err := s.repository.CountProductsByType(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// A price dated in the future does not count yet, as in the product listing.
+	now := time.Now().UTC()
+
+	withoutPrice, err := s.repository.CountProductsWithoutPrice(ctx, id, now)
+	if err != nil {
+		return nil, err
+	}
+
+	batches, quantity, err := s.repository.GetActiveStock(ctx, id, now)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := domain.SellerSummary{
+		SellerId:             id,
+		ProductsByType:       byType,
+		ProductsWithoutPrice: withoutPrice,
+		ActiveBatches:        batches,
+		ActiveStock:          quantity,
+	}
+	for _, count := range byType {
+		summary.Products += count.Products
+	}
+
+	return &summary, nil
+}
@@ -6,6 +6,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	product "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product/domain"
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/seller/domain"
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/seller/domain/mocks"
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/seller/services"
@@ -221,3 +223,92 @@ func Test_Service_Delete(t *testing.T) {
 
 	})
 }
+
+func Test_Service_GetProducts(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("get_products_ok: should return the requested page", func(t *testing.T) {
+		repo := mocks.NewRepositorySeller(t)
+		price := 7.5
+		products := []domain.SellerProduct{
+			{Product: product.Product{Id: 21, SellerId: 1}, CurrentPrice: &price},
+		}
+
+		repo.On("GetById", ctx, int64(1)).Return(&expectedSeller, nil).Once()
+		repo.On("CountProducts", ctx, int64(1)).Return(int64(21), nil).Once()
+		repo.On("GetProducts", ctx, int64(1), mock.AnythingOfType("time.Time"), int64(20), int64(20)).Return(products, nil).Once()
+
+		result, err := services.NewSellerService(repo).GetProducts(ctx, 1, 2, 20)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &domain.SellerProductsPage{Products: products, Page: 2, PageSize: 20, Total: 21}, result)
+	})
+
+	t.Run("get_products_invalid_page: should reject pages out of range", func(t *testing.T) {
+		service := services.NewSellerService(mocks.NewRepositorySeller(t))
+
+		_, err := service.GetProducts(ctx, 1, 0, 20)
+		assert.ErrorIs(t, err, domain.ErrInvalidPage)
+
+		_, err = service.GetProducts(ctx, 1, 1, domain.MaxPageSize+1)
+		assert.ErrorIs(t, err, domain.ErrInvalidPage)
+	})
+
+	t.Run("get_products_not_found: when the seller does not exist, should return error", func(t *testing.T) {
+		repo := mocks.NewRepositorySeller(t)
+		repo.On("GetById", ctx, int64(9)).Return(nil, domain.ErrIDNotFound).Once()
+
+		_, err := services.NewSellerService(repo).GetProducts(ctx, 9, 1, 20)
+
+		assert.ErrorIs(t, err, domain.ErrIDNotFound)
+	})
+}
+
+func Test_Service_GetSummary(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("get_summary_ok: should aggregate the seller catalog", func(t *testing.T) {
+		repo := mocks.NewRepositorySeller(t)
+		byType := []domain.ProductTypeCount{
+			{ProductTypeId: 1, Description: "Frozen", Products: 2},
+			{ProductTypeId: 3, Description: "Ambient", Products: 5},
+		}
+
+		repo.On("GetById", ctx, int64(1)).Return(&expectedSeller, nil).Once()
+		repo.On("CountProductsByType", ctx, int64(1)).Return(byType, nil).Once()
+		repo.On("CountProductsWithoutPrice", ctx, int64(1), mock.AnythingOfType("time.Time")).Return(int64(3), nil).Once()
+		repo.On("GetActiveStock", ctx, int64(1), mock.AnythingOfType("time.Time")).Return(int64(4), int64(350), nil).Once()
+
+		result, err := services.NewSellerService(repo).GetSummary(ctx, 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &domain.SellerSummary{
+			SellerId:             1,
+			Products:             7,
+			ProductsByType:       byType,
+			ProductsWithoutPrice: 3,
+			ActiveBatches:        4,
+			ActiveStock:          350,
+		}, result)
+	})
+
+	t.Run("get_summary_error: should return repository errors", func(t *testing.T) {
+		repo := mocks.NewRepositorySeller(t)
+
+		repo.On("GetById", ctx, int64(1)).Return(&expectedSeller, nil).Once()
+		repo.On("CountProductsByType", ctx, int64(1)).Return(nil, fmt.Errorf("any error")).Once()
+
+		_, err := services.NewSellerService(repo).GetSummary(ctx, 1)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("get_summary_not_found: when the seller does not exist, should return error", func(t *testing.T) {
+		repo := mocks.NewRepositorySeller(t)
+		repo.On("GetById", ctx, int64(9)).Return(nil, domain.ErrIDNotFound).Once()
+
+		_, err := services.NewSellerService(repo).GetSummary(ctx, 9)
+
+		assert.ErrorIs(t, err, domain.ErrIDNotFound)
+	})
+}
@@ -0,0 +1,279 @@
+package domain
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+)
+
+// Incident statuses. An incident is pending while the excursion is shorter
+// than the minimum duration; it is dropped if the temperature recovers by
+// then, otherwise it opens and raises an alert, and closes on recovery.
+const (
+	IncidentPending = "pending"
+	IncidentOpen    = "open"
+	IncidentClosed  = "closed"
+)
+
+// Alert events published to the outbox.
+const (
+	ExcursionOpened = "excursion_opened"
+	ExcursionClosed = "excursion_closed"
+)
+
+const (
+	DefaultExcursionMinutes = 30
+
+	DefaultComplianceDays = 30
+	MaxComplianceDays     = 366
+)
+
+func IsIncidentStatus(status string) bool {
+	return status == IncidentPending || status == IncidentOpen || status == IncidentClosed
+}
+
+// Subject is what an excursion is tracked for: a section, with its minimum
+// temperature as threshold, or a product batch in it, with the recommended
+// freezing temperature of its product.
+type Subject struct {
+	SectionId      int64
+	WarehouseId    int64
+	ProductBatchId *int64
+	Threshold      float64
+}
+
+type Incident struct {
+	Id              int64      `json:"id"`
+	SectionId       int64      `json:"section_id"`
+	WarehouseId     int64      `json:"warehouse_id"`
+	ProductBatchId  *int64     `json:"product_batch_id,omitempty"`
+	Threshold       float64    `json:"threshold"`
+	Status          string     `json:"status"`
+	StartedAt       time.Time  `json:"started_at"`
+	EndedAt         *time.Time `json:"ended_at"`
+	PeakTemperature float64    `json:"peak_temperature"`
+	PeakAt          time.Time  `json:"peak_at"`
+	LastReadingAt   time.Time  `json:"last_reading_at"`
+	AffectedBatches []int64    `json:"affected_batches"`
+}
+
+// Duration is how long the excursion lasted within [from, to), counting an
+// incident that has not ended as lasting until to.
+func (i Incident) Duration(from, to time.Time) time.Duration {
+	start, end := i.StartedAt, to
+	if i.EndedAt != nil && i.EndedAt.Before(to) {
+		end = *i.EndedAt
+	}
+	if start.Before(from) {
+		start = from
+	}
+	if !end.After(start) {
+		return 0
+	}
+	return end.Sub(start)
+}
+
+// IncidentChange is an incident to be stored after tracking readings, with
+// the alerts to publish. Discard is set for a pending incident that the
+// temperature recovered from before it opened.
+type IncidentChange struct {
+	Incident *Incident
+	Events   []string
+	Discard  bool
+}
+
+// ExcursionTracker computes the incident changes of an ingest. active
+// returns the pending or open incident of a subject, or nil; the repository
+// reads it locked inside the write transaction, so concurrent ingests for a
+// subject take turns instead of each opening an incident.
+type ExcursionTracker func(ctx context.Context, active func(subject Subject) (*Incident, error)) ([]IncidentChange, error)
+
+// Alert is the payload of an outbox.TemperatureAlert event.
+type Alert struct {
+	Event string `json:"event"`
+	Incident
+}
+
+// TrackExcursion follows the readings of a subject from its active incident,
+// nil when it is within its threshold, and returns the incidents that
+// changed. Readings not after the last reading of the active incident are
+// ignored, so a resent batch does not move an incident back.
+func TrackExcursion(active *Incident, subject Subject, readings []Reading, minDuration time.Duration) []IncidentChange {
+	sorted := append([]Reading(nil), readings...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].RecordedAt.Before(sorted[j].RecordedAt) })
+
+	changes := []IncidentChange{}
+
+	var current *IncidentChange
+	if active != nil {
+		incident := *active
+		current = &IncidentChange{Incident: &incident}
+	}
+	touched := false
+
+	for _, reading := range sorted {
+		if current != nil && !reading.RecordedAt.After(current.Incident.LastReadingAt) {
+			continue
+		}
+
+		if reading.Temperature <= subject.Threshold {
+			if current == nil {
+				continue
+			}
+
+			incident := current.Incident
+			if incident.Status == IncidentPending {
+				current.Discard = true
+			} else {
+				endedAt := reading.RecordedAt
+				incident.Status = IncidentClosed
+				incident.EndedAt = &endedAt
+				incident.LastReadingAt = reading.RecordedAt
+				current.Events = append(current.Events, ExcursionClosed)
+			}
+
+			if incident.Id != 0 || !current.Discard {
+				changes = append(changes, *current)
+			}
+			current, touched = nil, false
+			continue
+		}
+
+		if current == nil {
+			current = &IncidentChange{Incident: &Incident{
+				SectionId:       subject.SectionId,
+				WarehouseId:     subject.WarehouseId,
+				ProductBatchId:  subject.ProductBatchId,
+				Threshold:       subject.Threshold,
+				Status:          IncidentPending,
+				StartedAt:       reading.RecordedAt,
+				PeakTemperature: reading.Temperature,
+				PeakAt:          reading.RecordedAt,
+			}}
+		}
+
+		incident := current.Incident
+		incident.LastReadingAt = reading.RecordedAt
+		if reading.Temperature > incident.PeakTemperature {
+			incident.PeakTemperature = reading.Temperature
+			incident.PeakAt = reading.RecordedAt
+		}
+		if incident.Status == IncidentPending && reading.RecordedAt.Sub(incident.StartedAt) >= minDuration {
+			incident.Status = IncidentOpen
+			current.Events = append(current.Events, ExcursionOpened)
+		}
+		touched = true
+	}
+
+	if current != nil && touched {
+		changes = append(changes, *current)
+	}
+
+	return changes
+}
+
+// IncidentFilter selects incidents; zero fields match everything. Pending
+// incidents are only returned when asked for by status.
+type IncidentFilter struct {
+	WarehouseId int64
+	SectionId   int64
+	Status      string
+	From        *time.Time
+	To          *time.Time
+}
+
+// SectionCompliance holds the readings of a section itself over the report
+// range; Min, Max and Avg are nil when there are none.
+type SectionCompliance struct {
+	SectionId        int64    `json:"section_id"`
+	SectionNumber    int64    `json:"section_number"`
+	Threshold        float64  `json:"threshold"`
+	Readings         int64    `json:"readings"`
+	Min              *float64 `json:"min"`
+	Max              *float64 `json:"max"`
+	Avg              *float64 `json:"avg"`
+	Incidents        int64    `json:"incidents"`
+	BatchIncidents   int64    `json:"batch_incidents"`
+	ExcursionMinutes float64  `json:"excursion_minutes"`
+	Compliance       float64  `json:"compliance"`
+}
+
+// ComplianceReport tells, per section of a warehouse, the share of the
+// range it spent within its threshold, from the incidents of the section.
+// Excursions of single batches are counted but do not lower compliance.
+type ComplianceReport struct {
+	WarehouseId      int64               `json:"warehouse_id"`
+	WarehouseCode    string              `json:"warehouse_code"`
+	From             time.Time           `json:"from"`
+	To               time.Time           `json:"to"`
+	Incidents        int64               `json:"incidents"`
+	BatchIncidents   int64               `json:"batch_incidents"`
+	ExcursionMinutes float64             `json:"excursion_minutes"`
+	Compliance       float64             `json:"compliance"`
+	Sections         []SectionCompliance `json:"sections"`
+	IncidentList     []Incident          `json:"incident_list"`
+}
+
+// NewComplianceReport computes compliance over [from, to), or up to now if
+// earlier. A warehouse without sections is fully compliant.
+func NewComplianceReport(warehouseId int64, warehouseCode string, from, to, now time.Time, sections []SectionCompliance, incidents []Incident) *ComplianceReport {
+	report := &ComplianceReport{
+		WarehouseId:   warehouseId,
+		WarehouseCode: warehouseCode,
+		From:          from,
+		To:            to,
+		Compliance:    100,
+		Sections:      sections,
+		IncidentList:  incidents,
+	}
+
+	end := to
+	if now.Before(end) {
+		end = now
+	}
+	period := end.Sub(from)
+
+	bySection := map[int64]int{}
+	for i := range report.Sections {
+		bySection[report.Sections[i].SectionId] = i
+	}
+
+	excursions := make([]time.Duration, len(report.Sections))
+	for _, incident := range incidents {
+		i, ok := bySection[incident.SectionId]
+		if !ok {
+			continue
+		}
+		if incident.ProductBatchId != nil {
+			report.Sections[i].BatchIncidents++
+			report.BatchIncidents++
+			continue
+		}
+		report.Sections[i].Incidents++
+		report.Incidents++
+		excursions[i] += incident.Duration(from, end)
+	}
+
+	total := 0.0
+	for i := range report.Sections {
+		section := &report.Sections[i]
+		section.ExcursionMinutes = round(excursions[i].Minutes())
+		section.Compliance = 100
+		if period > 0 {
+			section.Compliance = round(100 * (1 - float64(excursions[i])/float64(period)))
+		}
+		report.ExcursionMinutes = round(report.ExcursionMinutes + section.ExcursionMinutes)
+		total += section.Compliance
+	}
+
+	if len(report.Sections) > 0 {
+		report.Compliance = round(total / float64(len(report.Sections)))
+	}
+
+	return report
+}
+
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,228 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	// MaxReadings is how many readings a single ingestion may carry.
+	MaxReadings = 1000
+
+	// MaxClockSkew is how far in the future a reading may be timestamped.
+	MaxClockSkew = 5 * time.Minute
+
+	MinTemperature = -100.0
+	MaxTemperature = 100.0
+
+	// MaxSamples bounds the number of buckets a series query returns.
+	MaxSamples = 1000
+
+	MinResolution = time.Minute
+
+	// DefaultRange is the period a series covers when none is given.
+	DefaultRange = 24 * time.Hour
+
+	// DefaultRawRetentionDays is how long readings are kept as they were
+	// recorded before being rolled up into hourly aggregates.
+	DefaultRawRetentionDays = 7
+)
+
+// resolutions are tried in order when a series query does not set one.
+var resolutions = []time.Duration{
+	time.Minute,
+	5 * time.Minute,
+	15 * time.Minute,
+	time.Hour,
+	6 * time.Hour,
+	24 * time.Hour,
+}
+
+// Reading is a temperature measured in a section. A reading with a product
+// batch was taken from that batch, otherwise it is the temperature of the
+// section itself.
+type Reading struct {
+	SectionId      int64     `json:"section_id"`
+	ProductBatchId *int64    `json:"product_batch_id,omitempty"`
+	Temperature    float64   `json:"temperature"`
+	RecordedAt     time.Time `json:"recorded_at"`
+}
+
+// Validate checks a reading received at now.
+func (r Reading) Validate(now time.Time) error {
+	switch {
+	case r.SectionId <= 0:
+		return fmt.Errorf("%w: section_id is required", ErrInvalidReading)
+	case r.ProductBatchId != nil && *r.ProductBatchId <= 0:
+		return fmt.Errorf("%w: invalid product_batch_id %d", ErrInvalidReading, *r.ProductBatchId)
+	case r.RecordedAt.IsZero():
+		return fmt.Errorf("%w: recorded_at is required", ErrInvalidReading)
+	case r.RecordedAt.After(now.Add(MaxClockSkew)):
+		return fmt.Errorf("%w: recorded_at %s is in the future", ErrInvalidReading, r.RecordedAt.Format(time.RFC3339))
+	case r.Temperature < MinTemperature || r.Temperature > MaxTemperature:
+		return fmt.Errorf("%w: temperature %.2f is outside [%.0f, %.0f]", ErrInvalidReading, r.Temperature, MinTemperature, MaxTemperature)
+	}
+	return nil
+}
+
+// LatestReading is the most recent reading of a section or product batch.
+type LatestReading struct {
+	Id          int64
+	Temperature float64
+	RecordedAt  time.Time
+}
+
+// Latest returns, ordered by id, the most recent reading of every section
+// and of every product batch among readings.
+func Latest(readings []Reading) (sections []LatestReading, batches []LatestReading) {
+	sectionReadings := map[int64]LatestReading{}
+	batchReadings := map[int64]LatestReading{}
+
+	for _, reading := range readings {
+		target, id := sectionReadings, reading.SectionId
+		if reading.ProductBatchId != nil {
+			target, id = batchReadings, *reading.ProductBatchId
+		}
+
+		if latest, ok := target[id]; !ok || reading.RecordedAt.After(latest.RecordedAt) {
+			target[id] = LatestReading{Id: id, Temperature: reading.Temperature, RecordedAt: reading.RecordedAt}
+		}
+	}
+
+	return sortLatest(sectionReadings), sortLatest(batchReadings)
+}
+
+func sortLatest(readings map[int64]LatestReading) []LatestReading {
+	sorted := make([]LatestReading, 0, len(readings))
+	for _, reading := range readings {
+		sorted = append(sorted, reading)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Id < sorted[j].Id })
+	return sorted
+}
+
+type IngestResult struct {
+	Accepted int `json:"accepted"`
+}
+
+// SeriesQuery selects the readings of a section, or of a product batch in
+// it, recorded in [From, To), grouped in buckets of Resolution.
+type SeriesQuery struct {
+	SectionId      int64
+	ProductBatchId *int64
+	From           time.Time
+	To             time.Time
+	Resolution     time.Duration
+}
+
+// Validate checks the range and picks the finest resolution that fits in
+// MaxSamples buckets when none is set.
+func (q *SeriesQuery) Validate() error {
+	if !q.From.Before(q.To) {
+		return fmt.Errorf("%w: from must be before to", ErrInvalidRange)
+	}
+
+	period := q.To.Sub(q.From)
+
+	if q.Resolution == 0 {
+		for _, resolution := range resolutions {
+			if period/resolution < MaxSamples {
+				q.Resolution = resolution
+				return nil
+			}
+		}
+		return fmt.Errorf("%w: the range is longer than %d days", ErrInvalidRange, MaxSamples)
+	}
+
+	if q.Resolution < MinResolution || q.Resolution%time.Second != 0 {
+		return fmt.Errorf("%w: use whole seconds of at least %s", ErrInvalidResolution, MinResolution)
+	}
+	if period/q.Resolution >= MaxSamples {
+		return fmt.Errorf("%w: the range holds more than %d buckets of %s", ErrInvalidResolution, MaxSamples, q.Resolution)
+	}
+
+	return nil
+}
+
+// Sample aggregates the readings of one bucket. Readings older than the raw
+// retention are stored hourly, so buckets finer than an hour hold at most
+// one sample per hour for that period.
+type Sample struct {
+	From     time.Time `json:"from"`
+	Min      float64   `json:"min"`
+	Max      float64   `json:"max"`
+	Avg      float64   `json:"avg"`
+	Readings int64     `json:"readings"`
+}
+
+type Series struct {
+	SectionId         int64     `json:"section_id"`
+	ProductBatchId    *int64    `json:"product_batch_id,omitempty"`
+	From              time.Time `json:"from"`
+	To                time.Time `json:"to"`
+	ResolutionSeconds int64     `json:"resolution_seconds"`
+	Readings          int64     `json:"readings"`
+	Min               *float64  `json:"min"`
+	Max               *float64  `json:"max"`
+	Avg               *float64  `json:"avg"`
+	Samples           []Sample  `json:"samples"`
+}
+
+// NewSeries aggregates the samples over the whole range; the average is
+// weighted by the readings of every sample. Min, Max and Avg are nil when
+// there are no readings.
+func NewSeries(query SeriesQuery, samples []Sample) *Series {
+	series := &Series{
+		SectionId:         query.SectionId,
+		ProductBatchId:    query.ProductBatchId,
+		From:              query.From,
+		To:                query.To,
+		ResolutionSeconds: int64(query.Resolution / time.Second),
+		Samples:           samples,
+	}
+
+	if len(samples) == 0 {
+		series.Samples = []Sample{}
+		return series
+	}
+
+	min, max, sum := samples[0].Min, samples[0].Max, 0.0
+	for _, sample := range samples {
+		min = math.Min(min, sample.Min)
+		max = math.Max(max, sample.Max)
+		sum += sample.Avg * float64(sample.Readings)
+		series.Readings += sample.Readings
+	}
+
+	avg := 0.0
+	if series.Readings > 0 {
+		avg = round(sum / float64(series.Readings))
+	}
+
+	series.Min, series.Max, series.Avg = &min, &max, &avg
+	return series
+}
+
+type TemperatureRepository interface {
+	// Create stores the readings with the incidents that track changed,
+	// publishing their alerts, and moves the current temperature of the
+	// sections and product batches to their latest reading, unless a more
+	// recent one was already recorded. track runs inside the transaction.
+	Create(ctx context.Context, readings []Reading, track ExcursionTracker) error
+	GetSamples(ctx context.Context, query SeriesQuery) ([]Sample, error)
+	// Downsample rolls the readings recorded before the given time up into
+	// hourly aggregates and deletes them, returning how many were rolled up.
+	Downsample(ctx context.Context, before time.Time) (int64, error)
+	GetIncidents(ctx context.Context, filter IncidentFilter) ([]Incident, error)
+	GetSectionCompliance(ctx context.Context, warehouseId int64, from, to time.Time) ([]SectionCompliance, error)
+}
+
+type TemperatureService interface {
+	Ingest(ctx context.Context, readings []Reading) (*IngestResult, error)
+	GetSeries(ctx context.Context, query SeriesQuery) (*Series, error)
+	GetIncidents(ctx context.Context, filter IncidentFilter) ([]Incident, error)
+	GetComplianceReport(ctx context.Context, warehouseId int64, from, to time.Time) (*ComplianceReport, error)
+}
@@ -0,0 +1,13 @@
+package domain
+
+import "errors"
+
+var (
+	ErrNoReadings        = errors.New("at least one reading is required")
+	ErrTooManyReadings   = errors.New("too many readings")
+	ErrInvalidReading    = errors.New("invalid reading")
+	ErrInvalidRange      = errors.New("invalid time range")
+	ErrInvalidResolution = errors.New("invalid resolution")
+
+	ErrInvalidIncidentStatus = errors.New("status must be pending, open or closed")
+)
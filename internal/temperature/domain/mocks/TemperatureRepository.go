@@ -0,0 +1,136 @@
+// Code generated by mockery v2.13.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	domain "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/temperature/domain"
+
+	time "time"
+)
+
+// TemperatureRepository is an autogenerated mock type for the TemperatureRepository type
+type TemperatureRepository struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: ctx, readings, track
+func (_m *TemperatureRepository) Create(ctx context.Context, readings []domain.Reading, track domain.ExcursionTracker) error {
+	ret := _m.Called(ctx, readings, track)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []domain.Reading, domain.ExcursionTracker) error); ok {
+		r0 = rf(ctx, readings, track)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Downsample provides a mock function with given fields: ctx, before
+func (_m *TemperatureRepository) Downsample(ctx context.Context, before time.Time) (int64, error) {
+	ret := _m.Called(ctx, before)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, before)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, before)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetIncidents provides a mock function with given fields: ctx, filter
+func (_m *TemperatureRepository) GetIncidents(ctx context.Context, filter domain.IncidentFilter) ([]domain.Incident, error) {
+	ret := _m.Called(ctx, filter)
+
+	var r0 []domain.Incident
+	if rf, ok := ret.Get(0).(func(context.Context, domain.IncidentFilter) []domain.Incident); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Incident)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, domain.IncidentFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSamples provides a mock function with given fields: ctx, query
+func (_m *TemperatureRepository) GetSamples(ctx context.Context, query domain.SeriesQuery) ([]domain.Sample, error) {
+	ret := _m.Called(ctx, query)
+
+	var r0 []domain.Sample
+	if rf, ok := ret.Get(0).(func(context.Context, domain.SeriesQuery) []domain.Sample); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Sample)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, domain.SeriesQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSectionCompliance provides a mock function with given fields: ctx, warehouseId, from, to
+func (_m *TemperatureRepository) GetSectionCompliance(ctx context.Context, warehouseId int64, from time.Time, to time.Time) ([]domain.SectionCompliance, error) {
+	ret := _m.Called(ctx, warehouseId, from, to)
+
+	var r0 []domain.SectionCompliance
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time, time.Time) []domain.SectionCompliance); ok {
+		r0 = rf(ctx, warehouseId, from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.SectionCompliance)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, warehouseId, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewTemperatureRepository interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewTemperatureRepository creates a new instance of TemperatureRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewTemperatureRepository(t mockConstructorTestingTNewTemperatureRepository) *TemperatureRepository {
+	mock := &TemperatureRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
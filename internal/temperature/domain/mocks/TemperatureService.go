@@ -0,0 +1,124 @@
+// Code generated by mockery v2.13.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	domain "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/temperature/domain"
+
+	time "time"
+)
+
+// TemperatureService is an autogenerated mock type for the TemperatureService type
+type TemperatureService struct {
+	mock.Mock
+}
+
+// GetComplianceReport provides a mock function with given fields: ctx, warehouseId, from, to
+func (_m *TemperatureService) GetComplianceReport(ctx context.Context, warehouseId int64, from time.Time, to time.Time) (*domain.ComplianceReport, error) {
+	ret := _m.Called(ctx, warehouseId, from, to)
+
+	var r0 *domain.ComplianceReport
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time, time.Time) *domain.ComplianceReport); ok {
+		r0 = rf(ctx, warehouseId, from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ComplianceReport)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, warehouseId, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetIncidents provides a mock function with given fields: ctx, filter
+func (_m *TemperatureService) GetIncidents(ctx context.Context, filter domain.IncidentFilter) ([]domain.Incident, error) {
+	ret := _m.Called(ctx, filter)
+
+	var r0 []domain.Incident
+	if rf, ok := ret.Get(0).(func(context.Context, domain.IncidentFilter) []domain.Incident); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Incident)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, domain.IncidentFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSeries provides a mock function with given fields: ctx, query
+func (_m *TemperatureService) GetSeries(ctx context.Context, query domain.SeriesQuery) (*domain.Series, error) {
+	ret := _m.Called(ctx, query)
+
+	var r0 *domain.Series
+	if rf, ok := ret.Get(0).(func(context.Context, domain.SeriesQuery) *domain.Series); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Series)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, domain.SeriesQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Ingest provides a mock function with given fields: ctx, readings
+func (_m *TemperatureService) Ingest(ctx context.Context, readings []domain.Reading) (*domain.IngestResult, error) {
+	ret := _m.Called(ctx, readings)
+
+	var r0 *domain.IngestResult
+	if rf, ok := ret.Get(0).(func(context.Context, []domain.Reading) *domain.IngestResult); ok {
+		r0 = rf(ctx, readings)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.IngestResult)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []domain.Reading) error); ok {
+		r1 = rf(ctx, readings)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewTemperatureService interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewTemperatureService creates a new instance of TemperatureService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewTemperatureService(t mockConstructorTestingTNewTemperatureService) *TemperatureService {
+	mock := &TemperatureService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,328 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/temperature/domain"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/libs/outbox"
+)
+
+type mariaDbTemperatureRepository struct {
+	db *sql.DB
+}
+
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+func NewMariadbTemperatureRepository(db *sql.DB) domain.TemperatureRepository {
+	return &mariaDbTemperatureRepository{db: db}
+}
+
+func (m *mariaDbTemperatureRepository) Create(ctx context.Context, readings []domain.Reading, track domain.ExcursionTracker) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	incidents, err := track(ctx, func(subject domain.Subject) (*domain.Incident, error) {
+		return lockActiveIncident(ctx, tx, subject)
+	})
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	stmt, err := tx.PrepareContext(ctx, SQLCreateReading)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+
+	for _, reading := range readings {
+		if _, err := stmt.ExecContext(ctx, reading.SectionId, reading.ProductBatchId, reading.Temperature, reading.RecordedAt); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	sections, batches := domain.Latest(readings)
+
+	for _, latest := range sections {
+		if _, err := tx.ExecContext(ctx, SQLUpdateSectionTemperature, latest.Temperature, latest.RecordedAt, latest.Id, latest.RecordedAt); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	for _, latest := range batches {
+		if _, err := tx.ExecContext(ctx, SQLUpdateProductBatchTemperature, latest.Temperature, latest.RecordedAt, latest.Id, latest.RecordedAt); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	for _, change := range incidents {
+		if err := saveIncident(ctx, tx, change); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func saveIncident(ctx context.Context, tx *sql.Tx, change domain.IncidentChange) error {
+	incident := change.Incident
+
+	if change.Discard {
+		_, err := tx.ExecContext(ctx, SQLDeleteIncident, incident.Id)
+		return err
+	}
+
+	if incident.Id == 0 {
+		result, err := tx.ExecContext(
+			ctx,
+			SQLCreateIncident,
+			incident.SectionId,
+			incident.ProductBatchId,
+			incident.Threshold,
+			incident.Status,
+			incident.StartedAt,
+			incident.EndedAt,
+			incident.PeakTemperature,
+			incident.PeakAt,
+			incident.LastReadingAt,
+		)
+		if err != nil {
+			return err
+		}
+		incident.Id, _ = result.LastInsertId()
+	} else {
+		_, err := tx.ExecContext(
+			ctx,
+			SQLUpdateIncident,
+			incident.Status,
+			incident.EndedAt,
+			incident.PeakTemperature,
+			incident.PeakAt,
+			incident.LastReadingAt,
+			incident.Id,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, event := range change.Events {
+		if event == domain.ExcursionOpened {
+			for _, batchId := range incident.AffectedBatches {
+				if _, err := tx.ExecContext(ctx, SQLCreateIncidentBatch, incident.Id, batchId); err != nil {
+					return err
+				}
+			}
+		}
+
+		alert := domain.Alert{Event: event, Incident: *incident}
+		if err := outbox.PublishWarehouseEvent(ctx, tx, outbox.TemperatureAlert, incident.Id, incident.WarehouseId, alert); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// lockActiveIncident reads the pending or open incident of a subject, or nil,
+// holding the lock of its section until the transaction ends.
+func lockActiveIncident(ctx context.Context, tx *sql.Tx, subject domain.Subject) (*domain.Incident, error) {
+	var sectionId int64
+	if err := tx.QueryRowContext(ctx, SQLLockSection, subject.SectionId).Scan(&sectionId); err != nil {
+		return nil, err
+	}
+
+	incidents, err := queryIncidents(ctx, tx, SQLLockActiveIncident, subject.SectionId, subject.ProductBatchId)
+	if err != nil || len(incidents) == 0 {
+		return nil, err
+	}
+	return &incidents[0], nil
+}
+
+func (m *mariaDbTemperatureRepository) GetIncidents(ctx context.Context, filter domain.IncidentFilter) ([]domain.Incident, error) {
+	return queryIncidents(
+		ctx,
+		m.db,
+		SQLGetIncidents,
+		filter.WarehouseId, filter.WarehouseId,
+		filter.SectionId, filter.SectionId,
+		filter.Status, filter.Status,
+		filter.From, filter.From,
+		filter.To, filter.To,
+	)
+}
+
+func queryIncidents(ctx context.Context, q querier, query string, args ...interface{}) ([]domain.Incident, error) {
+	incidents := []domain.Incident{}
+
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var incident domain.Incident
+		var productBatchId sql.NullInt64
+		var endedAt sql.NullTime
+		var affected string
+
+		err := rows.Scan(
+			&incident.Id,
+			&incident.SectionId,
+			&incident.WarehouseId,
+			&productBatchId,
+			&incident.Threshold,
+			&incident.Status,
+			&incident.StartedAt,
+			&endedAt,
+			&incident.PeakTemperature,
+			&incident.PeakAt,
+			&incident.LastReadingAt,
+			&affected,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if productBatchId.Valid {
+			incident.ProductBatchId = &productBatchId.Int64
+		}
+		if endedAt.Valid {
+			incident.EndedAt = &endedAt.Time
+		}
+
+		incident.AffectedBatches = []int64{}
+		if affected != "" {
+			for _, id := range strings.Split(affected, ",") {
+				batchId, err := strconv.ParseInt(id, 10, 64)
+				if err != nil {
+					return nil, err
+				}
+				incident.AffectedBatches = append(incident.AffectedBatches, batchId)
+			}
+		}
+
+		incidents = append(incidents, incident)
+	}
+
+	return incidents, rows.Err()
+}
+
+func (m *mariaDbTemperatureRepository) GetSectionCompliance(ctx context.Context, warehouseId int64, from, to time.Time) ([]domain.SectionCompliance, error) {
+	sections := []domain.SectionCompliance{}
+
+	rows, err := m.db.QueryContext(ctx, SQLGetSectionCompliance, from, to, from, to, warehouseId)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var section domain.SectionCompliance
+		var min, max, avg sql.NullFloat64
+
+		err := rows.Scan(
+			&section.SectionId,
+			&section.SectionNumber,
+			&section.Threshold,
+			&section.Readings,
+			&min,
+			&max,
+			&avg,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if section.Readings > 0 {
+			average := math.Round(avg.Float64*100) / 100
+			section.Min, section.Max, section.Avg = &min.Float64, &max.Float64, &average
+		}
+
+		sections = append(sections, section)
+	}
+
+	return sections, rows.Err()
+}
+
+func (m *mariaDbTemperatureRepository) GetSamples(ctx context.Context, query domain.SeriesQuery) ([]domain.Sample, error) {
+	samples := []domain.Sample{}
+
+	seconds := int64(query.Resolution / time.Second)
+
+	var rollupBatchId int64
+	if query.ProductBatchId != nil {
+		rollupBatchId = *query.ProductBatchId
+	}
+
+	rows, err := m.db.QueryContext(
+		ctx,
+		SQLGetSamples,
+		seconds,
+		query.SectionId, query.ProductBatchId, query.From, query.To,
+		query.SectionId, rollupBatchId, query.From, query.To,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var sample domain.Sample
+		var bucket int64
+
+		if err := rows.Scan(&bucket, &sample.Min, &sample.Max, &sample.Avg, &sample.Readings); err != nil {
+			return nil, err
+		}
+
+		sample.From = time.Unix(bucket*seconds, 0).UTC()
+		sample.Avg = math.Round(sample.Avg*100) / 100
+
+		samples = append(samples, sample)
+	}
+
+	return samples, rows.Err()
+}
+
+func (m *mariaDbTemperatureRepository) Downsample(ctx context.Context, before time.Time) (int64, error) {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := tx.ExecContext(ctx, SQLRollUpReadings, before); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	result, err := tx.ExecContext(ctx, SQLDeleteReadingsBefore, before)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	return rowsAffected, nil
+}
@@ -0,0 +1,160 @@
+package repository
+
+const (
+	SQLCreateReading = `
+    INSERT INTO section_temperatures (section_id, product_batch_id, temperature, recorded_at)
+    VALUES (?, ?, ?, ?)
+    `
+
+	SQLUpdateSectionTemperature = `
+    UPDATE sections
+    SET current_temperature = ?, temperature_recorded_at = ?
+    WHERE id = ? AND (temperature_recorded_at IS NULL OR temperature_recorded_at <= ?)
+    `
+
+	SQLUpdateProductBatchTemperature = `
+    UPDATE product_batches
+    SET current_temperature = ?, temperature_recorded_at = ?
+    WHERE id = ? AND (temperature_recorded_at IS NULL OR temperature_recorded_at <= ?)
+    `
+
+	// Raw readings and hourly rollups are merged before bucketing, so a
+	// range spanning the raw retention is aggregated as a whole.
+	SQLGetSamples = `
+    SELECT
+        FLOOR(UNIX_TIMESTAMP(t.recorded_at) / ?) AS bucket,
+        MIN(t.min_temperature),
+        MAX(t.max_temperature),
+        SUM(t.sum_temperature) / SUM(t.readings),
+        SUM(t.readings)
+    FROM (
+        SELECT recorded_at, temperature AS min_temperature, temperature AS max_temperature, temperature AS sum_temperature, 1 AS readings
+        FROM section_temperatures
+        WHERE section_id = ? AND product_batch_id <=> ? AND recorded_at >= ? AND recorded_at < ?
+        UNION ALL
+        SELECT bucket_start, min_temperature, max_temperature, sum_temperature, readings
+        FROM section_temperature_rollups
+        WHERE section_id = ? AND product_batch_id = ? AND bucket_start >= ? AND bucket_start < ?
+    ) t
+    GROUP BY bucket
+    ORDER BY bucket
+    `
+
+	SQLRollUpReadings = `
+    INSERT INTO section_temperature_rollups
+        (section_id, product_batch_id, bucket_start, min_temperature, max_temperature, sum_temperature, readings)
+    SELECT
+        section_id,
+        COALESCE(product_batch_id, 0),
+        DATE_FORMAT(recorded_at, '%Y-%m-%d %H:00:00'),
+        MIN(temperature),
+        MAX(temperature),
+        SUM(temperature),
+        COUNT(*)
+    FROM section_temperatures
+    WHERE recorded_at < ?
+    GROUP BY section_id, COALESCE(product_batch_id, 0), DATE_FORMAT(recorded_at, '%Y-%m-%d %H:00:00')
+    ON DUPLICATE KEY UPDATE
+        min_temperature = LEAST(min_temperature, VALUES(min_temperature)),
+        max_temperature = GREATEST(max_temperature, VALUES(max_temperature)),
+        sum_temperature = sum_temperature + VALUES(sum_temperature),
+        readings = readings + VALUES(readings)
+    `
+
+	SQLDeleteReadingsBefore = `
+    DELETE FROM section_temperatures
+    WHERE recorded_at < ?
+    `
+
+	SQLCreateIncident = `
+    INSERT INTO temperature_incidents (
+        section_id,
+        product_batch_id,
+        threshold,
+        status,
+        started_at,
+        ended_at,
+        peak_temperature,
+        peak_at,
+        last_reading_at
+    )
+    VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+    `
+
+	SQLUpdateIncident = `
+    UPDATE temperature_incidents
+    SET status = ?, ended_at = ?, peak_temperature = ?, peak_at = ?, last_reading_at = ?
+    WHERE id = ?
+    `
+
+	SQLDeleteIncident = "DELETE FROM temperature_incidents WHERE id = ?"
+
+	SQLCreateIncidentBatch = `
+    INSERT INTO temperature_incident_batches (incident_id, product_batch_id)
+    VALUES (?, ?)
+    `
+
+	sqlSelectIncidents = `
+    SELECT
+        i.id,
+        i.section_id,
+        s.warehouse_id,
+        i.product_batch_id,
+        i.threshold,
+        i.status,
+        i.started_at,
+        i.ended_at,
+        i.peak_temperature,
+        i.peak_at,
+        i.last_reading_at,
+        COALESCE(GROUP_CONCAT(b.product_batch_id ORDER BY b.product_batch_id), '')
+    FROM temperature_incidents i
+    JOIN sections s ON s.id = i.section_id
+    LEFT JOIN temperature_incident_batches b ON b.incident_id = i.id
+    `
+
+	// Ingests for a section take turns on its row, so that only one of them
+	// opens an incident.
+	SQLLockSection = "SELECT id FROM sections WHERE id = ? FOR UPDATE"
+
+	SQLLockActiveIncident = sqlSelectIncidents + `
+    WHERE i.section_id = ? AND i.product_batch_id <=> ? AND i.status IN ('pending', 'open')
+    GROUP BY i.id
+    FOR UPDATE
+    `
+
+	// Incidents overlapping [from, to).
+	SQLGetIncidents = sqlSelectIncidents + `
+    WHERE (? = 0 OR s.warehouse_id = ?)
+    AND (? = 0 OR i.section_id = ?)
+    AND ((? = '' AND i.status <> 'pending') OR i.status = ?)
+    AND (? IS NULL OR i.ended_at IS NULL OR i.ended_at > ?)
+    AND (? IS NULL OR i.started_at < ?)
+    GROUP BY i.id
+    ORDER BY i.started_at, i.id
+    `
+
+	SQLGetSectionCompliance = `
+    SELECT
+        s.id,
+        s.section_number,
+        s.minimum_temperature,
+        COALESCE(SUM(t.readings), 0),
+        MIN(t.min_temperature),
+        MAX(t.max_temperature),
+        SUM(t.sum_temperature) / SUM(t.readings)
+    FROM sections s
+    LEFT JOIN (
+        SELECT section_id, temperature AS min_temperature, temperature AS max_temperature, temperature AS sum_temperature, 1 AS readings
+        FROM section_temperatures
+        WHERE product_batch_id IS NULL AND recorded_at >= ? AND recorded_at < ?
+        UNION ALL
+        SELECT section_id, min_temperature, max_temperature, sum_temperature, readings
+        FROM section_temperature_rollups
+        WHERE product_batch_id = 0 AND bucket_start >= ? AND bucket_start < ?
+    ) t ON t.section_id = s.id
+    WHERE s.warehouse_id = ?
+    GROUP BY s.id, s.section_number, s.minimum_temperature
+    ORDER BY s.section_number
+    `
+)
@@ -0,0 +1,374 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/temperature/domain"
+	repository "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/temperature/repository/mariadb"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/libs/outbox"
+)
+
+var recordedAt = time.Date(2022, 8, 10, 12, 0, 0, 0, time.UTC)
+
+func batchId(id int64) *int64 {
+	return &id
+}
+
+// tracking is a tracker that computes the given changes.
+func tracking(changes ...domain.IncidentChange) domain.ExcursionTracker {
+	return func(ctx context.Context, active func(domain.Subject) (*domain.Incident, error)) ([]domain.IncidentChange, error) {
+		return changes, nil
+	}
+}
+
+func TestTemperatureRepository_Create(t *testing.T) {
+	readings := []domain.Reading{
+		{SectionId: 1, Temperature: -18, RecordedAt: recordedAt},
+		{SectionId: 1, Temperature: -17, RecordedAt: recordedAt.Add(time.Minute)},
+		{SectionId: 1, ProductBatchId: batchId(7), Temperature: -16, RecordedAt: recordedAt},
+	}
+
+	t.Run("create_ok: should store the readings and move the current temperatures", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		prepared := mock.ExpectPrepare(regexp.QuoteMeta(repository.SQLCreateReading))
+		prepared.ExpectExec().WithArgs(int64(1), nil, -18.0, recordedAt).WillReturnResult(sqlmock.NewResult(1, 1))
+		prepared.ExpectExec().WithArgs(int64(1), nil, -17.0, recordedAt.Add(time.Minute)).WillReturnResult(sqlmock.NewResult(2, 1))
+		prepared.ExpectExec().WithArgs(int64(1), int64(7), -16.0, recordedAt).WillReturnResult(sqlmock.NewResult(3, 1))
+		mock.
+			ExpectExec(regexp.QuoteMeta(repository.SQLUpdateSectionTemperature)).
+			WithArgs(-17.0, recordedAt.Add(time.Minute), int64(1), recordedAt.Add(time.Minute)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.
+			ExpectExec(regexp.QuoteMeta(repository.SQLUpdateProductBatchTemperature)).
+			WithArgs(-16.0, recordedAt, int64(7), recordedAt).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err = repository.NewMariadbTemperatureRepository(db).Create(context.TODO(), readings, tracking())
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("create_fail: should rollback when a reading cannot be stored", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectPrepare(regexp.QuoteMeta(repository.SQLCreateReading)).
+			ExpectExec().
+			WillReturnError(errors.New("any error"))
+		mock.ExpectRollback()
+
+		err = repository.NewMariadbTemperatureRepository(db).Create(context.TODO(), readings, tracking())
+
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestTemperatureRepository_Create_Incidents(t *testing.T) {
+	readings := []domain.Reading{{SectionId: 1, Temperature: -10, RecordedAt: recordedAt}}
+
+	t.Run("create_incident_opened: should store the incident, its batches and the alert", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		incident := &domain.Incident{
+			SectionId:       1,
+			WarehouseId:     2,
+			Threshold:       -15,
+			Status:          domain.IncidentOpen,
+			StartedAt:       recordedAt,
+			PeakTemperature: -10,
+			PeakAt:          recordedAt,
+			LastReadingAt:   recordedAt,
+			AffectedBatches: []int64{7},
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectPrepare(regexp.QuoteMeta(repository.SQLCreateReading)).ExpectExec().WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(regexp.QuoteMeta(repository.SQLUpdateSectionTemperature)).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.
+			ExpectExec(regexp.QuoteMeta(repository.SQLCreateIncident)).
+			WithArgs(int64(1), nil, -15.0, domain.IncidentOpen, recordedAt, nil, -10.0, recordedAt, recordedAt).
+			WillReturnResult(sqlmock.NewResult(5, 1))
+		mock.ExpectExec(regexp.QuoteMeta(repository.SQLCreateIncidentBatch)).WithArgs(int64(5), int64(7)).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.
+			ExpectExec(regexp.QuoteMeta(outbox.SQLCreateOutboxEvent)).
+			WithArgs(outbox.TemperatureAlert, int64(5), int64(2), sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		err = repository.NewMariadbTemperatureRepository(db).Create(context.TODO(), readings, tracking(
+			domain.IncidentChange{Incident: incident, Events: []string{domain.ExcursionOpened}},
+		))
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(5), incident.Id)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("create_incident_discarded: should delete a pending incident", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectPrepare(regexp.QuoteMeta(repository.SQLCreateReading)).ExpectExec().WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(regexp.QuoteMeta(repository.SQLUpdateSectionTemperature)).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(repository.SQLDeleteIncident)).WithArgs(int64(6)).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err = repository.NewMariadbTemperatureRepository(db).Create(context.TODO(), readings, tracking(
+			domain.IncidentChange{Incident: &domain.Incident{Id: 6}, Discard: true},
+		))
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("create_incident_fail: should rollback when the alert cannot be written", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectPrepare(regexp.QuoteMeta(repository.SQLCreateReading)).ExpectExec().WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(regexp.QuoteMeta(repository.SQLUpdateSectionTemperature)).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(repository.SQLUpdateIncident)).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(outbox.SQLCreateOutboxEvent)).WillReturnError(errors.New("any error"))
+		mock.ExpectRollback()
+
+		err = repository.NewMariadbTemperatureRepository(db).Create(context.TODO(), readings, tracking(
+			domain.IncidentChange{Incident: &domain.Incident{Id: 5, Status: domain.IncidentClosed}, Events: []string{domain.ExcursionClosed}},
+		))
+
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestTemperatureRepository_Create_ActiveIncident(t *testing.T) {
+	readings := []domain.Reading{{SectionId: 1, Temperature: -10, RecordedAt: recordedAt}}
+	subject := domain.Subject{SectionId: 1, WarehouseId: 2, Threshold: -15}
+
+	t.Run("create_active_incident: should read the active incident under the section lock", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(repository.SQLLockSection)).WithArgs(int64(1)).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.
+			ExpectQuery(regexp.QuoteMeta(repository.SQLLockActiveIncident)).
+			WithArgs(int64(1), nil).
+			WillReturnRows(sqlmock.NewRows(incidentColumns).
+				AddRow(5, 1, 2, nil, -15, domain.IncidentPending, recordedAt, nil, -12, recordedAt, recordedAt, ""))
+		mock.ExpectPrepare(regexp.QuoteMeta(repository.SQLCreateReading)).ExpectExec().WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(regexp.QuoteMeta(repository.SQLUpdateSectionTemperature)).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		var active *domain.Incident
+		err = repository.NewMariadbTemperatureRepository(db).Create(context.TODO(), readings, func(ctx context.Context, get func(domain.Subject) (*domain.Incident, error)) ([]domain.IncidentChange, error) {
+			var err error
+			active, err = get(subject)
+			return nil, err
+		})
+
+		assert.NoError(t, err)
+		if assert.NotNil(t, active) {
+			assert.Equal(t, int64(5), active.Id)
+			assert.Equal(t, domain.IncidentPending, active.Status)
+		}
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("create_active_incident_none: should track the subject without an incident", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(repository.SQLLockSection)).WithArgs(int64(1)).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectQuery(regexp.QuoteMeta(repository.SQLLockActiveIncident)).WithArgs(int64(1), nil).WillReturnRows(sqlmock.NewRows(incidentColumns))
+		mock.ExpectPrepare(regexp.QuoteMeta(repository.SQLCreateReading)).ExpectExec().WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(regexp.QuoteMeta(repository.SQLUpdateSectionTemperature)).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		active := &domain.Incident{}
+		err = repository.NewMariadbTemperatureRepository(db).Create(context.TODO(), readings, func(ctx context.Context, get func(domain.Subject) (*domain.Incident, error)) ([]domain.IncidentChange, error) {
+			var err error
+			active, err = get(subject)
+			return nil, err
+		})
+
+		assert.NoError(t, err)
+		assert.Nil(t, active)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("create_active_incident_fail: should rollback when the section cannot be locked", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(repository.SQLLockSection)).WillReturnError(errors.New("any error"))
+		mock.ExpectRollback()
+
+		err = repository.NewMariadbTemperatureRepository(db).Create(context.TODO(), readings, func(ctx context.Context, get func(domain.Subject) (*domain.Incident, error)) ([]domain.IncidentChange, error) {
+			_, err := get(subject)
+			return nil, err
+		})
+
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+var incidentColumns = []string{
+	"id", "section_id", "warehouse_id", "product_batch_id", "threshold", "status", "started_at",
+	"ended_at", "peak_temperature", "peak_at", "last_reading_at", "affected_batches",
+}
+
+func TestTemperatureRepository_GetIncidents(t *testing.T) {
+	t.Run("get_incidents_ok: should return the incidents with their batches", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		endedAt := recordedAt.Add(time.Hour)
+		filter := domain.IncidentFilter{WarehouseId: 2}
+
+		mock.
+			ExpectQuery(regexp.QuoteMeta(repository.SQLGetIncidents)).
+			WithArgs(int64(2), int64(2), int64(0), int64(0), "", "", nil, nil, nil, nil).
+			WillReturnRows(sqlmock.NewRows(incidentColumns).
+				AddRow(1, 1, 2, nil, -15, domain.IncidentClosed, recordedAt, endedAt, -10, recordedAt, endedAt, "7,8").
+				AddRow(2, 1, 2, 7, -20, domain.IncidentOpen, recordedAt, nil, -17, recordedAt, recordedAt, ""))
+
+		incidents, err := repository.NewMariadbTemperatureRepository(db).GetIncidents(context.TODO(), filter)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.Incident{
+			{
+				Id: 1, SectionId: 1, WarehouseId: 2, Threshold: -15, Status: domain.IncidentClosed,
+				StartedAt: recordedAt, EndedAt: &endedAt, PeakTemperature: -10, PeakAt: recordedAt,
+				LastReadingAt: endedAt, AffectedBatches: []int64{7, 8},
+			},
+			{
+				Id: 2, SectionId: 1, WarehouseId: 2, ProductBatchId: batchId(7), Threshold: -20, Status: domain.IncidentOpen,
+				StartedAt: recordedAt, PeakTemperature: -17, PeakAt: recordedAt,
+				LastReadingAt: recordedAt, AffectedBatches: []int64{},
+			},
+		}, incidents)
+	})
+}
+
+func TestTemperatureRepository_GetSectionCompliance(t *testing.T) {
+	t.Run("get_section_compliance_ok: should leave the aggregates of a section without readings empty", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		to := recordedAt.Add(24 * time.Hour)
+
+		mock.
+			ExpectQuery(regexp.QuoteMeta(repository.SQLGetSectionCompliance)).
+			WithArgs(recordedAt, to, recordedAt, to, int64(2)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "section_number", "minimum_temperature", "readings", "min", "max", "avg"}).
+				AddRow(1, 10, -15, 4, -19, -16, -17.3333).
+				AddRow(3, 11, -15, 0, nil, nil, nil))
+
+		sections, err := repository.NewMariadbTemperatureRepository(db).GetSectionCompliance(context.TODO(), 2, recordedAt, to)
+
+		min, max, avg := -19.0, -16.0, -17.33
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.SectionCompliance{
+			{SectionId: 1, SectionNumber: 10, Threshold: -15, Readings: 4, Min: &min, Max: &max, Avg: &avg},
+			{SectionId: 3, SectionNumber: 11, Threshold: -15},
+		}, sections)
+	})
+}
+
+func TestTemperatureRepository_GetSamples(t *testing.T) {
+	query := domain.SeriesQuery{SectionId: 1, From: recordedAt, To: recordedAt.Add(time.Hour), Resolution: 15 * time.Minute}
+
+	t.Run("get_samples_ok: should return the buckets", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		bucket := recordedAt.Unix() / 900
+
+		mock.
+			ExpectQuery(regexp.QuoteMeta(repository.SQLGetSamples)).
+			WithArgs(int64(900), int64(1), nil, query.From, query.To, int64(1), int64(0), query.From, query.To).
+			WillReturnRows(sqlmock.NewRows([]string{"bucket", "min", "max", "avg", "readings"}).
+				AddRow(bucket, -18.5, -17, -17.666666, 3))
+
+		samples, err := repository.NewMariadbTemperatureRepository(db).GetSamples(context.TODO(), query)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.Sample{{From: recordedAt, Min: -18.5, Max: -17, Avg: -17.67, Readings: 3}}, samples)
+	})
+
+	t.Run("get_samples_fail: should return the query error", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta(repository.SQLGetSamples)).WillReturnError(errors.New("any error"))
+
+		_, err = repository.NewMariadbTemperatureRepository(db).GetSamples(context.TODO(), query)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestTemperatureRepository_Downsample(t *testing.T) {
+	t.Run("downsample_ok: should roll up then delete the readings", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(repository.SQLRollUpReadings)).WithArgs(recordedAt).WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec(regexp.QuoteMeta(repository.SQLDeleteReadingsBefore)).WithArgs(recordedAt).WillReturnResult(sqlmock.NewResult(0, 12))
+		mock.ExpectCommit()
+
+		rolledUp, err := repository.NewMariadbTemperatureRepository(db).Downsample(context.TODO(), recordedAt)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(12), rolledUp)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("downsample_fail: should rollback when the rollup fails", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(repository.SQLRollUpReadings)).WillReturnError(errors.New("any error"))
+		mock.ExpectRollback()
+
+		_, err = repository.NewMariadbTemperatureRepository(db).Downsample(context.TODO(), recordedAt)
+
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
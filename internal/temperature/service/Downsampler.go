@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/temperature/domain"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/libs/logger"
+)
+
+const downsampleLabel = "temperatures.downsample"
+
+// Downsampler rolls readings older than the retention up into hourly
+// aggregates. Run is run periodically by the job scheduler.
+type Downsampler struct {
+	repository domain.TemperatureRepository
+	logger     logger.LogRepository
+	retention  time.Duration
+}
+
+// NewDownsampler keeps readings as recorded for retention. log may be nil.
+func NewDownsampler(r domain.TemperatureRepository, log logger.LogRepository, retention time.Duration) *Downsampler {
+	return &Downsampler{
+		repository: r,
+		logger:     log,
+		retention:  retention,
+	}
+}
+
+// Run rolls up whole hours only, so the hour at the retention boundary is
+// aggregated once all of it is past the retention.
+func (d *Downsampler) Run(ctx context.Context) error {
+	before := time.Now().UTC().Add(-d.retention).Truncate(time.Hour)
+
+	rolledUp, err := d.repository.Downsample(ctx, before)
+	if err != nil {
+		return err
+	}
+
+	if d.logger != nil && rolledUp > 0 {
+		d.logger.Info(ctx, "JOB", downsampleLabel, fmt.Sprintf(
+			"rolled %d readings recorded before %s up into hourly aggregates",
+			rolledUp,
+			before.Format(time.RFC3339),
+		), 0)
+	}
+
+	return nil
+}
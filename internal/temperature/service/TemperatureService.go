@@ -0,0 +1,294 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	product "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product/domain"
+	productBatch "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_batch/domain"
+	section "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/section/domain"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/temperature/domain"
+	warehouse "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/warehouse/domain"
+)
+
+type service struct {
+	repository             domain.TemperatureRepository
+	sectionRepository      section.SectionRepository
+	productBatchRepository productBatch.ProductBatchRepository
+	productRepository      product.ProductRepository
+	warehouseRepository    warehouse.WarehouseRepository
+	excursionDuration      time.Duration
+}
+
+// NewTemperatureService opens an incident once a section or product batch
+// has been above its threshold for excursionDuration.
+func NewTemperatureService(
+	r domain.TemperatureRepository,
+	sectionRepository section.SectionRepository,
+	productBatchRepository productBatch.ProductBatchRepository,
+	productRepository product.ProductRepository,
+	warehouseRepository warehouse.WarehouseRepository,
+	excursionDuration time.Duration,
+) domain.TemperatureService {
+	return &service{
+		repository:             r,
+		sectionRepository:      sectionRepository,
+		productBatchRepository: productBatchRepository,
+		productRepository:      productRepository,
+		warehouseRepository:    warehouseRepository,
+		excursionDuration:      excursionDuration,
+	}
+}
+
+// Ingest validates the whole set before storing any reading, so a rejected
+// request can be resent as is once fixed.
+func (s *service) Ingest(ctx context.Context, readings []domain.Reading) (*domain.IngestResult, error) {
+	if len(readings) == 0 {
+		return nil, domain.ErrNoReadings
+	}
+	if len(readings) > domain.MaxReadings {
+		return nil, fmt.Errorf("%w: %d readings, at most %d per request", domain.ErrTooManyReadings, len(readings), domain.MaxReadings)
+	}
+
+	now := time.Now()
+	sections := map[int64]section.SectionModel{}
+	batches := map[int64]*productBatch.ProductBatch{}
+
+	for i, reading := range readings {
+		if err := reading.Validate(now); err != nil {
+			return nil, fmt.Errorf("reading %d: %w", i, err)
+		}
+
+		if _, ok := sections[reading.SectionId]; !ok {
+			sectionModel, err := s.sectionRepository.GetById(ctx, reading.SectionId)
+			if err != nil {
+				if errors.Is(err, section.ErrSectionNotFound) {
+					return nil, fmt.Errorf("reading %d: %w: section %d not found", i, domain.ErrInvalidReading, reading.SectionId)
+				}
+				return nil, err
+			}
+			sections[reading.SectionId] = sectionModel
+		}
+
+		if reading.ProductBatchId == nil {
+			continue
+		}
+
+		batch, ok := batches[*reading.ProductBatchId]
+		if !ok {
+			var err error
+			batch, err = s.productBatchRepository.GetById(ctx, *reading.ProductBatchId)
+			if err != nil {
+				if errors.Is(err, productBatch.ErrProductBatchNotFound) {
+					return nil, fmt.Errorf("reading %d: %w: product batch %d not found", i, domain.ErrInvalidReading, *reading.ProductBatchId)
+				}
+				return nil, err
+			}
+			batches[*reading.ProductBatchId] = batch
+		}
+
+		if batch.SectionId != reading.SectionId {
+			return nil, fmt.Errorf(
+				"reading %d: %w: product batch %d is stored in section %d, not %d",
+				i, domain.ErrInvalidReading, batch.Id, batch.SectionId, reading.SectionId,
+			)
+		}
+	}
+
+	track, err := s.trackExcursions(ctx, readings, sections, batches)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repository.Create(ctx, readings, track); err != nil {
+		return nil, err
+	}
+
+	return &domain.IngestResult{Accepted: len(readings)}, nil
+}
+
+// trackExcursions returns the tracker that follows every section and
+// product batch with readings from its active incident, filling in the
+// batches affected by the incidents that open.
+func (s *service) trackExcursions(
+	ctx context.Context,
+	readings []domain.Reading,
+	sections map[int64]section.SectionModel,
+	batches map[int64]*productBatch.ProductBatch,
+) (domain.ExcursionTracker, error) {
+	sectionReadings := map[int64][]domain.Reading{}
+	batchReadings := map[int64][]domain.Reading{}
+	for _, reading := range readings {
+		if reading.ProductBatchId == nil {
+			sectionReadings[reading.SectionId] = append(sectionReadings[reading.SectionId], reading)
+		} else {
+			batchReadings[*reading.ProductBatchId] = append(batchReadings[*reading.ProductBatchId], reading)
+		}
+	}
+
+	subjects := []domain.Subject{}
+	for _, id := range sortedKeys(sectionReadings) {
+		subjects = append(subjects, domain.Subject{
+			SectionId:   id,
+			WarehouseId: sections[id].WarehouseId,
+			Threshold:   sections[id].MinimumTemperature,
+		})
+	}
+	for _, id := range sortedKeys(batchReadings) {
+		batch := batches[id]
+
+		p, err := s.productRepository.GetById(ctx, batch.ProductId)
+		if err != nil {
+			return nil, err
+		}
+
+		subjects = append(subjects, domain.Subject{
+			SectionId:      batch.SectionId,
+			WarehouseId:    sections[batch.SectionId].WarehouseId,
+			ProductBatchId: &batch.Id,
+			Threshold:      p.RecommendedFreezingTemperature,
+		})
+	}
+
+	return func(ctx context.Context, active func(domain.Subject) (*domain.Incident, error)) ([]domain.IncidentChange, error) {
+		changes := []domain.IncidentChange{}
+
+		for _, subject := range subjects {
+			subjectReadings := sectionReadings[subject.SectionId]
+			if subject.ProductBatchId != nil {
+				subjectReadings = batchReadings[*subject.ProductBatchId]
+			}
+
+			incident, err := active(subject)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, change := range domain.TrackExcursion(incident, subject, subjectReadings, s.excursionDuration) {
+				if opens(change) {
+					affected, err := s.affectedBatches(ctx, subject)
+					if err != nil {
+						return nil, err
+					}
+					change.Incident.AffectedBatches = affected
+				}
+				changes = append(changes, change)
+			}
+		}
+
+		return changes, nil
+	}, nil
+}
+
+// affectedBatches are the active batches with stock in an excursing
+// section, or the excursing batch itself.
+func (s *service) affectedBatches(ctx context.Context, subject domain.Subject) ([]int64, error) {
+	if subject.ProductBatchId != nil {
+		return []int64{*subject.ProductBatchId}, nil
+	}
+
+	minQuantity := int64(1)
+	stored, err := s.productBatchRepository.GetAll(ctx, productBatch.ProductBatchFilter{
+		SectionId:   subject.SectionId,
+		Status:      productBatch.StatusActive,
+		MinQuantity: &minQuantity,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	affected := make([]int64, 0, len(stored))
+	for _, batch := range stored {
+		affected = append(affected, batch.Id)
+	}
+	return affected, nil
+}
+
+func opens(change domain.IncidentChange) bool {
+	for _, event := range change.Events {
+		if event == domain.ExcursionOpened {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(readings map[int64][]domain.Reading) []int64 {
+	keys := make([]int64, 0, len(readings))
+	for key := range readings {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
+
+func (s *service) GetSeries(ctx context.Context, query domain.SeriesQuery) (*domain.Series, error) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.sectionRepository.GetById(ctx, query.SectionId); err != nil {
+		return nil, err
+	}
+
+	if query.ProductBatchId != nil {
+		batch, err := s.productBatchRepository.GetById(ctx, *query.ProductBatchId)
+		if err != nil {
+			return nil, err
+		}
+		if batch.SectionId != query.SectionId {
+			return nil, fmt.Errorf("%w: not in section %d", productBatch.ErrProductBatchNotFound, query.SectionId)
+		}
+	}
+
+	samples, err := s.repository.GetSamples(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.NewSeries(query, samples), nil
+}
+
+func (s *service) GetIncidents(ctx context.Context, filter domain.IncidentFilter) ([]domain.Incident, error) {
+	if filter.Status != "" && !domain.IsIncidentStatus(filter.Status) {
+		return nil, domain.ErrInvalidIncidentStatus
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, fmt.Errorf("%w: from must be before to", domain.ErrInvalidRange)
+	}
+
+	return s.repository.GetIncidents(ctx, filter)
+}
+
+func (s *service) GetComplianceReport(ctx context.Context, warehouseId int64, from, to time.Time) (*domain.ComplianceReport, error) {
+	if !from.Before(to) {
+		return nil, fmt.Errorf("%w: from must be before to", domain.ErrInvalidRange)
+	}
+	if to.Sub(from) > domain.MaxComplianceDays*24*time.Hour {
+		return nil, fmt.Errorf("%w: at most %d days", domain.ErrInvalidRange, domain.MaxComplianceDays)
+	}
+
+	w, err := s.warehouseRepository.GetById(ctx, warehouseId)
+	if err != nil {
+		return nil, err
+	}
+
+	sections, err := s.repository.GetSectionCompliance(ctx, warehouseId, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	incidents, err := s.repository.GetIncidents(ctx, domain.IncidentFilter{
+		WarehouseId: warehouseId,
+		From:        &from,
+		To:          &to,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.NewComplianceReport(w.Id, w.WarehouseCode, from, to, time.Now(), sections, incidents), nil
+}
@@ -0,0 +1,463 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	product "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product/domain"
+	productMocks "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product/domain/mocks"
+	productBatch "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_batch/domain"
+	productBatchMocks "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/product_batch/domain/mocks"
+	section "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/section/domain"
+	sectionMocks "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/section/domain/mocks"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/temperature/domain"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/temperature/domain/mocks"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/temperature/service"
+	warehouse "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/warehouse/domain"
+	warehouseMocks "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/warehouse/domain/mocks"
+)
+
+const excursionDuration = 30 * time.Minute
+
+type fixture struct {
+	repository         *mocks.TemperatureRepository
+	sections           *sectionMocks.SectionRepository
+	productBatches     *productBatchMocks.ProductBatchRepository
+	products           *productMocks.ProductRepository
+	warehouses         *warehouseMocks.WarehouseRepository
+	temperatureService domain.TemperatureService
+}
+
+func setUp(t *testing.T) fixture {
+	f := fixture{
+		repository:     mocks.NewTemperatureRepository(t),
+		sections:       sectionMocks.NewSectionRepository(t),
+		productBatches: productBatchMocks.NewProductBatchRepository(t),
+		products:       productMocks.NewProductRepository(t),
+		warehouses:     warehouseMocks.NewWarehouseRepository(t),
+	}
+	f.temperatureService = service.NewTemperatureService(
+		f.repository,
+		f.sections,
+		f.productBatches,
+		f.products,
+		f.warehouses,
+		excursionDuration,
+	)
+	return f
+}
+
+func batchId(id int64) *int64 {
+	return &id
+}
+
+type activeIncident struct {
+	sectionId      int64
+	productBatchId *int64
+	incident       *domain.Incident
+}
+
+// expectCreate expects Create to be called with the readings and runs the
+// tracker it gets, answering each subject with its active incident in order.
+// The changes computed by the tracker are stored in the returned slice.
+func expectCreate(t *testing.T, f fixture, readings interface{}, err error, active ...activeIncident) *[]domain.IncidentChange {
+	changes := &[]domain.IncidentChange{}
+
+	f.repository.
+		On("Create", mock.Anything, readings, mock.Anything).
+		Run(func(args mock.Arguments) {
+			asked := 0
+			track := args.Get(2).(domain.ExcursionTracker)
+
+			tracked, err := track(context.TODO(), func(subject domain.Subject) (*domain.Incident, error) {
+				if asked == len(active) {
+					return nil, errors.New("unexpected subject")
+				}
+				expected := active[asked]
+				asked++
+				assert.Equal(t, expected.sectionId, subject.SectionId)
+				assert.Equal(t, expected.productBatchId, subject.ProductBatchId)
+				return expected.incident, nil
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, len(active), asked)
+			*changes = tracked
+		}).
+		Return(err).
+		Once()
+
+	return changes
+}
+
+func TestTemperatureService_Ingest(t *testing.T) {
+	recordedAt := time.Now().Add(-time.Minute)
+
+	t.Run("ingest_ok: should store section and batch readings", func(t *testing.T) {
+		f := setUp(t)
+
+		readings := []domain.Reading{
+			{SectionId: 1, Temperature: -18, RecordedAt: recordedAt},
+			{SectionId: 1, ProductBatchId: batchId(7), Temperature: -17.5, RecordedAt: recordedAt},
+			{SectionId: 1, ProductBatchId: batchId(7), Temperature: -17, RecordedAt: recordedAt.Add(time.Second)},
+		}
+
+		f.sections.On("GetById", mock.Anything, int64(1)).Return(section.SectionModel{Id: 1}, nil).Once()
+		f.productBatches.On("GetById", mock.Anything, int64(7)).Return(&productBatch.ProductBatch{Id: 7, SectionId: 1, ProductId: 3}, nil).Once()
+		f.products.On("GetById", mock.Anything, int64(3)).Return(&product.Product{Id: 3}, nil).Once()
+		changes := expectCreate(t, f, readings, nil, activeIncident{1, nil, nil}, activeIncident{1, batchId(7), nil})
+
+		result, err := f.temperatureService.Ingest(context.TODO(), readings)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &domain.IngestResult{Accepted: 3}, result)
+		assert.Equal(t, []domain.IncidentChange{}, *changes)
+	})
+
+	t.Run("ingest_empty: should return ErrNoReadings", func(t *testing.T) {
+		f := setUp(t)
+
+		_, err := f.temperatureService.Ingest(context.TODO(), nil)
+
+		assert.ErrorIs(t, err, domain.ErrNoReadings)
+	})
+
+	t.Run("ingest_too_many: should return ErrTooManyReadings", func(t *testing.T) {
+		f := setUp(t)
+
+		_, err := f.temperatureService.Ingest(context.TODO(), make([]domain.Reading, domain.MaxReadings+1))
+
+		assert.ErrorIs(t, err, domain.ErrTooManyReadings)
+	})
+
+	t.Run("ingest_future: should reject a reading recorded in the future", func(t *testing.T) {
+		f := setUp(t)
+
+		_, err := f.temperatureService.Ingest(context.TODO(), []domain.Reading{
+			{SectionId: 1, Temperature: -18, RecordedAt: time.Now().Add(time.Hour)},
+		})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidReading)
+		assert.Contains(t, err.Error(), "reading 0")
+	})
+
+	t.Run("ingest_unknown_section: should reject the readings", func(t *testing.T) {
+		f := setUp(t)
+
+		f.sections.On("GetById", mock.Anything, int64(9)).Return(section.SectionModel{}, section.ErrSectionNotFound).Once()
+
+		_, err := f.temperatureService.Ingest(context.TODO(), []domain.Reading{
+			{SectionId: 9, Temperature: -18, RecordedAt: recordedAt},
+		})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidReading)
+		assert.Contains(t, err.Error(), "section 9 not found")
+	})
+
+	t.Run("ingest_batch_elsewhere: should reject a batch reading under another section", func(t *testing.T) {
+		f := setUp(t)
+
+		f.sections.On("GetById", mock.Anything, int64(1)).Return(section.SectionModel{Id: 1}, nil).Once()
+		f.productBatches.On("GetById", mock.Anything, int64(7)).Return(&productBatch.ProductBatch{Id: 7, SectionId: 2}, nil).Once()
+
+		_, err := f.temperatureService.Ingest(context.TODO(), []domain.Reading{
+			{SectionId: 1, ProductBatchId: batchId(7), Temperature: -18, RecordedAt: recordedAt},
+		})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidReading)
+		assert.Contains(t, err.Error(), "product batch 7 is stored in section 2, not 1")
+	})
+
+	t.Run("ingest_fail: should return the repository error", func(t *testing.T) {
+		f := setUp(t)
+
+		f.sections.On("GetById", mock.Anything, int64(1)).Return(section.SectionModel{Id: 1}, nil).Once()
+		expectCreate(t, f, mock.Anything, errors.New("any error"), activeIncident{1, nil, nil})
+
+		_, err := f.temperatureService.Ingest(context.TODO(), []domain.Reading{
+			{SectionId: 1, Temperature: -18, RecordedAt: recordedAt},
+		})
+
+		assert.EqualError(t, err, "any error")
+	})
+}
+
+func TestTemperatureService_Ingest_Excursions(t *testing.T) {
+	startedAt := time.Now().Add(-time.Hour).Truncate(time.Second)
+	freezer := section.SectionModel{Id: 1, WarehouseId: 2, MinimumTemperature: -15}
+
+	t.Run("excursion_opens: should open an incident with the stored batches once it lasts long enough", func(t *testing.T) {
+		f := setUp(t)
+
+		readings := []domain.Reading{
+			{SectionId: 1, Temperature: -12, RecordedAt: startedAt.Add(40 * time.Minute)},
+			{SectionId: 1, Temperature: -10, RecordedAt: startedAt},
+			{SectionId: 1, Temperature: -14, RecordedAt: startedAt.Add(20 * time.Minute)},
+		}
+
+		f.sections.On("GetById", mock.Anything, int64(1)).Return(freezer, nil).Once()
+		f.productBatches.
+			On("GetAll", mock.Anything, mock.MatchedBy(func(filter productBatch.ProductBatchFilter) bool {
+				return filter.SectionId == 1 && filter.Status == productBatch.StatusActive && *filter.MinQuantity == 1
+			})).
+			Return([]productBatch.ProductBatch{{Id: 7}, {Id: 8}}, nil).
+			Once()
+		changes := expectCreate(t, f, readings, nil, activeIncident{1, nil, nil})
+
+		_, err := f.temperatureService.Ingest(context.TODO(), readings)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.IncidentChange{{
+			Incident: &domain.Incident{
+				SectionId:       1,
+				WarehouseId:     2,
+				Threshold:       -15,
+				Status:          domain.IncidentOpen,
+				StartedAt:       startedAt,
+				PeakTemperature: -10,
+				PeakAt:          startedAt,
+				LastReadingAt:   startedAt.Add(40 * time.Minute),
+				AffectedBatches: []int64{7, 8},
+			},
+			Events: []string{domain.ExcursionOpened},
+		}}, *changes)
+	})
+
+	t.Run("excursion_closes: should close the open incident when the temperature recovers", func(t *testing.T) {
+		f := setUp(t)
+
+		open := &domain.Incident{
+			Id:              5,
+			SectionId:       1,
+			WarehouseId:     2,
+			Threshold:       -15,
+			Status:          domain.IncidentOpen,
+			StartedAt:       startedAt,
+			PeakTemperature: -10,
+			PeakAt:          startedAt,
+			LastReadingAt:   startedAt.Add(40 * time.Minute),
+		}
+		recoveredAt := startedAt.Add(50 * time.Minute)
+
+		f.sections.On("GetById", mock.Anything, int64(1)).Return(freezer, nil).Once()
+		changes := expectCreate(t, f, mock.Anything, nil, activeIncident{1, nil, open})
+
+		_, err := f.temperatureService.Ingest(context.TODO(), []domain.Reading{
+			{SectionId: 1, Temperature: -18, RecordedAt: recoveredAt},
+		})
+
+		assert.NoError(t, err)
+		if assert.Len(t, *changes, 1) {
+			change := (*changes)[0]
+			assert.Equal(t, int64(5), change.Incident.Id)
+			assert.Equal(t, domain.IncidentClosed, change.Incident.Status)
+			assert.True(t, change.Incident.EndedAt.Equal(recoveredAt))
+			assert.Equal(t, []string{domain.ExcursionClosed}, change.Events)
+		}
+	})
+
+	t.Run("excursion_short: should drop a pending incident that recovers in time", func(t *testing.T) {
+		f := setUp(t)
+
+		pending := &domain.Incident{
+			Id:            6,
+			SectionId:     1,
+			Threshold:     -15,
+			Status:        domain.IncidentPending,
+			StartedAt:     startedAt,
+			LastReadingAt: startedAt,
+		}
+
+		f.sections.On("GetById", mock.Anything, int64(1)).Return(freezer, nil).Once()
+		changes := expectCreate(t, f, mock.Anything, nil, activeIncident{1, nil, pending})
+
+		_, err := f.temperatureService.Ingest(context.TODO(), []domain.Reading{
+			{SectionId: 1, Temperature: -16, RecordedAt: startedAt.Add(10 * time.Minute)},
+		})
+
+		assert.NoError(t, err)
+		if assert.Len(t, *changes, 1) {
+			change := (*changes)[0]
+			assert.True(t, change.Discard)
+			assert.Equal(t, int64(6), change.Incident.Id)
+			assert.Empty(t, change.Events)
+		}
+	})
+
+	t.Run("batch_excursion: should compare batch readings with the product freezing temperature", func(t *testing.T) {
+		f := setUp(t)
+
+		f.sections.On("GetById", mock.Anything, int64(1)).Return(freezer, nil).Once()
+		f.productBatches.On("GetById", mock.Anything, int64(7)).Return(&productBatch.ProductBatch{Id: 7, SectionId: 1, ProductId: 3}, nil).Once()
+		f.products.On("GetById", mock.Anything, int64(3)).Return(&product.Product{Id: 3, RecommendedFreezingTemperature: -20}, nil).Once()
+		changes := expectCreate(t, f, mock.Anything, nil, activeIncident{1, batchId(7), nil})
+
+		_, err := f.temperatureService.Ingest(context.TODO(), []domain.Reading{
+			{SectionId: 1, ProductBatchId: batchId(7), Temperature: -17, RecordedAt: startedAt},
+		})
+
+		assert.NoError(t, err)
+		if assert.Len(t, *changes, 1) {
+			change := (*changes)[0]
+			assert.Equal(t, int64(7), *change.Incident.ProductBatchId)
+			assert.Equal(t, -20.0, change.Incident.Threshold)
+			assert.Equal(t, domain.IncidentPending, change.Incident.Status)
+			assert.Empty(t, change.Events)
+		}
+	})
+}
+
+func TestTemperatureService_GetComplianceReport(t *testing.T) {
+	from := time.Date(2022, 8, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(10 * time.Hour)
+	endedAt := from.Add(2 * time.Hour)
+
+	t.Run("compliance_ok: should count the excursion time of section incidents", func(t *testing.T) {
+		f := setUp(t)
+
+		f.warehouses.On("GetById", mock.Anything, int64(2)).Return(warehouse.WarehouseModel{Id: 2, WarehouseCode: "WH-2"}, nil).Once()
+		f.repository.On("GetSectionCompliance", mock.Anything, int64(2), from, to).Return([]domain.SectionCompliance{
+			{SectionId: 1, SectionNumber: 10, Threshold: -15, Readings: 100},
+			{SectionId: 3, SectionNumber: 11, Threshold: -15, Readings: 100},
+		}, nil).Once()
+		f.repository.
+			On("GetIncidents", mock.Anything, domain.IncidentFilter{WarehouseId: 2, From: &from, To: &to}).
+			Return([]domain.Incident{
+				{Id: 1, SectionId: 1, Status: domain.IncidentClosed, StartedAt: from.Add(-time.Hour), EndedAt: &endedAt},
+				{Id: 2, SectionId: 3, ProductBatchId: batchId(7), Status: domain.IncidentOpen, StartedAt: from},
+			}, nil).
+			Once()
+
+		report, err := f.temperatureService.GetComplianceReport(context.TODO(), 2, from, to)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "WH-2", report.WarehouseCode)
+		assert.Equal(t, 120.0, report.Sections[0].ExcursionMinutes)
+		assert.Equal(t, 80.0, report.Sections[0].Compliance)
+		assert.Equal(t, int64(1), report.Sections[1].BatchIncidents)
+		assert.Equal(t, 100.0, report.Sections[1].Compliance)
+		assert.Equal(t, 90.0, report.Compliance)
+	})
+
+	t.Run("compliance_not_found: should return ErrWarehouseNotFound", func(t *testing.T) {
+		f := setUp(t)
+
+		f.warehouses.On("GetById", mock.Anything, int64(9)).Return(warehouse.WarehouseModel{}, warehouse.ErrWarehouseNotFound).Once()
+
+		_, err := f.temperatureService.GetComplianceReport(context.TODO(), 9, from, to)
+
+		assert.ErrorIs(t, err, warehouse.ErrWarehouseNotFound)
+	})
+
+	t.Run("compliance_too_long: should return ErrInvalidRange", func(t *testing.T) {
+		f := setUp(t)
+
+		_, err := f.temperatureService.GetComplianceReport(context.TODO(), 2, from, from.AddDate(2, 0, 0))
+
+		assert.ErrorIs(t, err, domain.ErrInvalidRange)
+	})
+}
+
+func TestTemperatureService_GetIncidents(t *testing.T) {
+	t.Run("get_incidents_invalid_status: should return ErrInvalidIncidentStatus", func(t *testing.T) {
+		f := setUp(t)
+
+		_, err := f.temperatureService.GetIncidents(context.TODO(), domain.IncidentFilter{Status: "resolved"})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidIncidentStatus)
+	})
+}
+
+func TestTemperatureService_GetSeries(t *testing.T) {
+	to := time.Date(2022, 8, 10, 0, 0, 0, 0, time.UTC)
+
+	t.Run("get_series_ok: should pick a resolution and aggregate the samples", func(t *testing.T) {
+		f := setUp(t)
+
+		query := domain.SeriesQuery{SectionId: 1, From: to.Add(-24 * time.Hour), To: to}
+		expectedQuery := query
+		expectedQuery.Resolution = 5 * time.Minute
+
+		f.sections.On("GetById", mock.Anything, int64(1)).Return(section.SectionModel{Id: 1}, nil).Once()
+		f.repository.On("GetSamples", mock.Anything, expectedQuery).Return([]domain.Sample{
+			{From: query.From, Min: -19, Max: -17, Avg: -18, Readings: 3},
+			{From: query.From.Add(5 * time.Minute), Min: -16, Max: -16, Avg: -16, Readings: 1},
+		}, nil).Once()
+
+		series, err := f.temperatureService.GetSeries(context.TODO(), query)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(300), series.ResolutionSeconds)
+		assert.Equal(t, int64(4), series.Readings)
+		assert.Equal(t, -19.0, *series.Min)
+		assert.Equal(t, -16.0, *series.Max)
+		assert.Equal(t, -17.5, *series.Avg)
+	})
+
+	t.Run("get_series_empty: should return no aggregates", func(t *testing.T) {
+		f := setUp(t)
+
+		query := domain.SeriesQuery{SectionId: 1, From: to.Add(-time.Hour), To: to, Resolution: time.Minute}
+
+		f.sections.On("GetById", mock.Anything, int64(1)).Return(section.SectionModel{Id: 1}, nil).Once()
+		f.repository.On("GetSamples", mock.Anything, query).Return([]domain.Sample{}, nil).Once()
+
+		series, err := f.temperatureService.GetSeries(context.TODO(), query)
+
+		assert.NoError(t, err)
+		assert.Nil(t, series.Avg)
+		assert.Empty(t, series.Samples)
+	})
+
+	t.Run("get_series_too_fine: should return ErrInvalidResolution", func(t *testing.T) {
+		f := setUp(t)
+
+		_, err := f.temperatureService.GetSeries(context.TODO(), domain.SeriesQuery{
+			SectionId: 1, From: to.Add(-30 * 24 * time.Hour), To: to, Resolution: time.Minute,
+		})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidResolution)
+	})
+
+	t.Run("get_series_inverted_range: should return ErrInvalidRange", func(t *testing.T) {
+		f := setUp(t)
+
+		_, err := f.temperatureService.GetSeries(context.TODO(), domain.SeriesQuery{SectionId: 1, From: to, To: to.Add(-time.Hour)})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidRange)
+	})
+
+	t.Run("get_series_batch_elsewhere: should return ErrProductBatchNotFound", func(t *testing.T) {
+		f := setUp(t)
+
+		f.sections.On("GetById", mock.Anything, int64(1)).Return(section.SectionModel{Id: 1}, nil).Once()
+		f.productBatches.On("GetById", mock.Anything, int64(7)).Return(&productBatch.ProductBatch{Id: 7, SectionId: 2}, nil).Once()
+
+		_, err := f.temperatureService.GetSeries(context.TODO(), domain.SeriesQuery{
+			SectionId: 1, ProductBatchId: batchId(7), From: to.Add(-time.Hour), To: to,
+		})
+
+		assert.ErrorIs(t, err, productBatch.ErrProductBatchNotFound)
+	})
+}
+
+func TestDownsampler_Run(t *testing.T) {
+	t.Run("run_ok: should roll up whole hours past the retention", func(t *testing.T) {
+		repository := mocks.NewTemperatureRepository(t)
+
+		repository.
+			On("Downsample", mock.Anything, mock.MatchedBy(func(before time.Time) bool {
+				return before.Equal(before.Truncate(time.Hour)) && time.Since(before) >= 48*time.Hour
+			})).
+			Return(int64(10), nil).
+			Once()
+
+		err := service.NewDownsampler(repository, nil, 48*time.Hour).Run(context.TODO())
+
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type User struct {
+	Id           int64  `json:"id"`
+	Username     string `json:"username"`
+	PasswordHash string `json:"-"`
+}
+
+// ApiKey is stored as a SHA-256 hash; the plain key is only shown once, when
+// it is created. The prefix identifies the key without revealing it.
+type ApiKey struct {
+	Id        int64     `json:"id"`
+	UserId    int64     `json:"user_id"`
+	Name      string    `json:"name"`
+	Prefix    string    `json:"prefix"`
+	KeyHash   string    `json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type UserRepository interface {
+	GetByUsername(ctx context.Context, username string) (*User, error)
+	Create(ctx context.Context, user *User) (*User, error)
+	CreateApiKey(ctx context.Context, apiKey *ApiKey) (*ApiKey, error)
+}
+
+type UserService interface {
+	Create(ctx context.Context, username string, password string) (*User, error)
+	CreateApiKey(ctx context.Context, username string, name string) (*ApiKey, string, error)
+}
@@ -0,0 +1,10 @@
+package domain
+
+import "errors"
+
+var (
+	ErrUserNotFound     = errors.New("user not found")
+	ErrUsernameTaken    = errors.New("username already exists")
+	ErrInvalidUsername  = errors.New("username must not be empty")
+	ErrPasswordTooShort = errors.New("password must have at least 8 characters")
+)
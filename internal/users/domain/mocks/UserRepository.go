@@ -0,0 +1,99 @@
+// Code generated by mockery v2.13.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	domain "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/users/domain"
+)
+
+// UserRepository is an autogenerated mock type for the UserRepository type
+type UserRepository struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: ctx, user
+func (_m *UserRepository) Create(ctx context.Context, user *domain.User) (*domain.User, error) {
+	ret := _m.Called(ctx, user)
+
+	var r0 *domain.User
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.User) *domain.User); ok {
+		r0 = rf(ctx, user)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.User) error); ok {
+		r1 = rf(ctx, user)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateApiKey provides a mock function with given fields: ctx, apiKey
+func (_m *UserRepository) CreateApiKey(ctx context.Context, apiKey *domain.ApiKey) (*domain.ApiKey, error) {
+	ret := _m.Called(ctx, apiKey)
+
+	var r0 *domain.ApiKey
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.ApiKey) *domain.ApiKey); ok {
+		r0 = rf(ctx, apiKey)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ApiKey)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.ApiKey) error); ok {
+		r1 = rf(ctx, apiKey)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByUsername provides a mock function with given fields: ctx, username
+func (_m *UserRepository) GetByUsername(ctx context.Context, username string) (*domain.User, error) {
+	ret := _m.Called(ctx, username)
+
+	var r0 *domain.User
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.User); ok {
+		r0 = rf(ctx, username)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, username)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewUserRepository interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewUserRepository creates a new instance of UserRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewUserRepository(t mockConstructorTestingTNewUserRepository) *UserRepository {
+	mock := &UserRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
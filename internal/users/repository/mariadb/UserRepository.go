@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/users/domain"
+)
+
+type mariaDbUserRepository struct {
+	db *sql.DB
+}
+
+func NewMariadbUserRepository(db *sql.DB) domain.UserRepository {
+	return &mariaDbUserRepository{db: db}
+}
+
+func (m *mariaDbUserRepository) GetByUsername(ctx context.Context, username string) (*domain.User, error) {
+	var user domain.User
+
+	err := m.db.QueryRowContext(ctx, SQLGetUserByUsername, username).Scan(
+		&user.Id,
+		&user.Username,
+		&user.PasswordHash,
+	)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrUserNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (m *mariaDbUserRepository) Create(ctx context.Context, user *domain.User) (*domain.User, error) {
+	result, err := m.db.ExecContext(ctx, SQLCreateUser, user.Username, user.PasswordHash)
+	if err != nil {
+		return nil, err
+	}
+
+	user.Id, _ = result.LastInsertId()
+
+	return user, nil
+}
+
+func (m *mariaDbUserRepository) CreateApiKey(ctx context.Context, apiKey *domain.ApiKey) (*domain.ApiKey, error) {
+	result, err := m.db.ExecContext(
+		ctx,
+		SQLCreateApiKey,
+		apiKey.UserId,
+		apiKey.Name,
+		apiKey.Prefix,
+		apiKey.KeyHash,
+		apiKey.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	apiKey.Id, _ = result.LastInsertId()
+
+	return apiKey, nil
+}
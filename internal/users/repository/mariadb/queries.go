@@ -0,0 +1,16 @@
+package repository
+
+const (
+	SQLGetUserByUsername = `
+    SELECT id, username, passoword FROM users WHERE username = ?
+    `
+
+	SQLCreateUser = `
+    INSERT INTO users (username, passoword) VALUES (?, ?)
+    `
+
+	SQLCreateApiKey = `
+    INSERT INTO api_keys (user_id, name, prefix, key_hash, created_at)
+    VALUES (?, ?, ?, ?, ?)
+    `
+)
@@ -0,0 +1,131 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/users/domain"
+	repository "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/users/repository/mariadb"
+)
+
+func TestUserRepository_GetByUsername(t *testing.T) {
+	t.Run("get_by_username_ok: should return the user", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		rows := sqlmock.NewRows([]string{"id", "username", "passoword"}).AddRow(1, "admin", "hash")
+		mock.ExpectQuery(regexp.QuoteMeta(repository.SQLGetUserByUsername)).WithArgs("admin").WillReturnRows(rows)
+
+		userRepository := repository.NewMariadbUserRepository(db)
+
+		user, err := userRepository.GetByUsername(context.Background(), "admin")
+
+		assert.NoError(t, err)
+		assert.Equal(t, &domain.User{Id: 1, Username: "admin", PasswordHash: "hash"}, user)
+	})
+
+	t.Run("get_by_username_non_existent: should return ErrUserNotFound", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta(repository.SQLGetUserByUsername)).WithArgs("admin").WillReturnError(sql.ErrNoRows)
+
+		userRepository := repository.NewMariadbUserRepository(db)
+
+		_, err = userRepository.GetByUsername(context.Background(), "admin")
+
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	})
+
+	t.Run("get_by_username_error: should return error when query fails", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta(repository.SQLGetUserByUsername)).WillReturnError(errors.New("any error"))
+
+		userRepository := repository.NewMariadbUserRepository(db)
+
+		_, err = userRepository.GetByUsername(context.Background(), "admin")
+
+		assert.EqualError(t, err, "any error")
+	})
+}
+
+func TestUserRepository_Create(t *testing.T) {
+	t.Run("create_ok: should return the user with its id", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(repository.SQLCreateUser)).
+			WithArgs("admin", "hash").
+			WillReturnResult(sqlmock.NewResult(3, 1))
+
+		userRepository := repository.NewMariadbUserRepository(db)
+
+		user, err := userRepository.Create(context.Background(), &domain.User{Username: "admin", PasswordHash: "hash"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), user.Id)
+	})
+
+	t.Run("create_error: should return error when query execution fails", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(repository.SQLCreateUser)).WillReturnError(errors.New("any error"))
+
+		userRepository := repository.NewMariadbUserRepository(db)
+
+		_, err = userRepository.Create(context.Background(), &domain.User{Username: "admin", PasswordHash: "hash"})
+
+		assert.EqualError(t, err, "any error")
+	})
+}
+
+func TestUserRepository_CreateApiKey(t *testing.T) {
+	apiKey := domain.ApiKey{UserId: 1, Name: "ci", Prefix: "mf_0123abcd", KeyHash: "hash", CreatedAt: time.Now()}
+
+	t.Run("create_api_key_ok: should return the key with its id", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(repository.SQLCreateApiKey)).
+			WithArgs(apiKey.UserId, apiKey.Name, apiKey.Prefix, apiKey.KeyHash, apiKey.CreatedAt).
+			WillReturnResult(sqlmock.NewResult(5, 1))
+
+		userRepository := repository.NewMariadbUserRepository(db)
+
+		input := apiKey
+		result, err := userRepository.CreateApiKey(context.Background(), &input)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(5), result.Id)
+	})
+
+	t.Run("create_api_key_error: should return error when query execution fails", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(repository.SQLCreateApiKey)).WillReturnError(errors.New("any error"))
+
+		userRepository := repository.NewMariadbUserRepository(db)
+
+		input := apiKey
+		_, err = userRepository.CreateApiKey(context.Background(), &input)
+
+		assert.EqualError(t, err, "any error")
+	})
+}
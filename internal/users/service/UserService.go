@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/users/domain"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	minPasswordLength = 8
+	apiKeyBytes       = 32
+	apiKeyPrefixSize  = 8
+)
+
+type service struct {
+	repository domain.UserRepository
+}
+
+func NewUserService(r domain.UserRepository) domain.UserService {
+	return &service{repository: r}
+}
+
+func (s *service) Create(ctx context.Context, username string, password string) (*domain.User, error) {
+	username = strings.TrimSpace(username)
+	if username == "" {
+		return nil, domain.ErrInvalidUsername
+	}
+
+	if len(password) < minPasswordLength {
+		return nil, domain.ErrPasswordTooShort
+	}
+
+	_, err := s.repository.GetByUsername(ctx, username)
+	if err == nil {
+		return nil, domain.ErrUsernameTaken
+	}
+	if !errors.Is(err, domain.ErrUserNotFound) {
+		return nil, err
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repository.Create(ctx, &domain.User{
+		Username:     username,
+		PasswordHash: string(hash),
+	})
+}
+
+// CreateApiKey returns the stored key and the plain key, which is not kept
+// anywhere and must be handed to the user right away.
+func (s *service) CreateApiKey(ctx context.Context, username string, name string) (*domain.ApiKey, string, error) {
+	user, err := s.repository.GetByUsername(ctx, username)
+	if err != nil {
+		return nil, "", err
+	}
+
+	random := make([]byte, apiKeyBytes)
+	if _, err := rand.Read(random); err != nil {
+		return nil, "", err
+	}
+
+	key := "mf_" + hex.EncodeToString(random)
+	hash := sha256.Sum256([]byte(key))
+
+	apiKey, err := s.repository.CreateApiKey(ctx, &domain.ApiKey{
+		UserId:    user.Id,
+		Name:      name,
+		Prefix:    key[:len("mf_")+apiKeyPrefixSize],
+		KeyHash:   hex.EncodeToString(hash[:]),
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	return apiKey, key, nil
+}
@@ -0,0 +1,118 @@
+package service_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/users/domain"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/users/domain/mocks"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/users/service"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestUserService_Create(t *testing.T) {
+	t.Run("create_ok: should store a bcrypt hash of the password", func(t *testing.T) {
+		repository := mocks.NewUserRepository(t)
+		repository.On("GetByUsername", mock.Anything, "admin").Return(nil, domain.ErrUserNotFound).Once()
+		repository.
+			On("Create", mock.Anything, mock.MatchedBy(func(user *domain.User) bool {
+				return user.Username == "admin" &&
+					bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte("secret123")) == nil
+			})).
+			Return(&domain.User{Id: 1, Username: "admin"}, nil).
+			Once()
+
+		userService := service.NewUserService(repository)
+
+		user, err := userService.Create(context.Background(), " admin ", "secret123")
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), user.Id)
+	})
+
+	t.Run("create_invalid_username: should return ErrInvalidUsername", func(t *testing.T) {
+		repository := mocks.NewUserRepository(t)
+
+		userService := service.NewUserService(repository)
+
+		_, err := userService.Create(context.Background(), "  ", "secret123")
+
+		assert.ErrorIs(t, err, domain.ErrInvalidUsername)
+	})
+
+	t.Run("create_short_password: should return ErrPasswordTooShort", func(t *testing.T) {
+		repository := mocks.NewUserRepository(t)
+
+		userService := service.NewUserService(repository)
+
+		_, err := userService.Create(context.Background(), "admin", "short")
+
+		assert.ErrorIs(t, err, domain.ErrPasswordTooShort)
+	})
+
+	t.Run("create_conflict: should return ErrUsernameTaken", func(t *testing.T) {
+		repository := mocks.NewUserRepository(t)
+		repository.On("GetByUsername", mock.Anything, "admin").Return(&domain.User{Id: 1, Username: "admin"}, nil).Once()
+
+		userService := service.NewUserService(repository)
+
+		_, err := userService.Create(context.Background(), "admin", "secret123")
+
+		assert.ErrorIs(t, err, domain.ErrUsernameTaken)
+	})
+
+	t.Run("create_error: should return error when the lookup fails", func(t *testing.T) {
+		repository := mocks.NewUserRepository(t)
+		repository.On("GetByUsername", mock.Anything, "admin").Return(nil, errors.New("any error")).Once()
+
+		userService := service.NewUserService(repository)
+
+		_, err := userService.Create(context.Background(), "admin", "secret123")
+
+		assert.EqualError(t, err, "any error")
+	})
+}
+
+func TestUserService_CreateApiKey(t *testing.T) {
+	t.Run("create_api_key_ok: should store only the hash of the key", func(t *testing.T) {
+		var stored *domain.ApiKey
+
+		repository := mocks.NewUserRepository(t)
+		repository.On("GetByUsername", mock.Anything, "admin").Return(&domain.User{Id: 7, Username: "admin"}, nil).Once()
+		repository.
+			On("CreateApiKey", mock.Anything, mock.AnythingOfType("*domain.ApiKey")).
+			Run(func(args mock.Arguments) { stored = args.Get(1).(*domain.ApiKey) }).
+			Return(func(_ context.Context, apiKey *domain.ApiKey) *domain.ApiKey { return apiKey }, nil).
+			Once()
+
+		userService := service.NewUserService(repository)
+
+		apiKey, key, err := userService.CreateApiKey(context.Background(), "admin", "ci")
+
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(key, "mf_"))
+		assert.True(t, strings.HasPrefix(key, apiKey.Prefix))
+
+		hash := sha256.Sum256([]byte(key))
+		assert.Equal(t, hex.EncodeToString(hash[:]), stored.KeyHash)
+		assert.Equal(t, int64(7), stored.UserId)
+		assert.Equal(t, "ci", stored.Name)
+	})
+
+	t.Run("create_api_key_non_existent_user: should return ErrUserNotFound", func(t *testing.T) {
+		repository := mocks.NewUserRepository(t)
+		repository.On("GetByUsername", mock.Anything, "ghost").Return(nil, domain.ErrUserNotFound).Once()
+
+		userService := service.NewUserService(repository)
+
+		_, _, err := userService.CreateApiKey(context.Background(), "ghost", "ci")
+
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	})
+}
@@ -0,0 +1,212 @@
+package domain
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Inventory groupings.
+const (
+	GroupByProductType = "product_type"
+	GroupBySeller      = "seller"
+)
+
+const (
+	DefaultInventoryExpiringDays = 7
+	MaxInventoryExpiringDays     = 365
+)
+
+// InventoryQuery selects the warehouse, how to group its products and how
+// many days ahead a batch counts as nearing expiry.
+type InventoryQuery struct {
+	WarehouseId  int64
+	GroupBy      string
+	ExpiringDays int
+}
+
+func IsInventoryGroup(groupBy string) bool {
+	return groupBy == "" || groupBy == GroupByProductType || groupBy == GroupBySeller
+}
+
+// InventoryProduct is the stock of a product in the warehouse: the active
+// batches that still hold units, valued at the latest purchase price. A
+// product without a purchase price is worth 0.
+type InventoryProduct struct {
+	ProductId     int64    `json:"product_id"`
+	ProductCode   string   `json:"product_code"`
+	Description   string   `json:"description"`
+	ProductTypeId int64    `json:"product_type_id"`
+	ProductType   string   `json:"product_type"`
+	SellerId      *int64   `json:"seller_id"`
+	Seller        string   `json:"seller"`
+	Batches       int64    `json:"batches"`
+	Quantity      int64    `json:"quantity"`
+	PurchasePrice *float64 `json:"purchase_price"`
+	Value         float64  `json:"value"`
+}
+
+// InventoryBatch is an active batch with stock due within the expiring
+// days, or already past its due date.
+type InventoryBatch struct {
+	Id              int64     `json:"id"`
+	BatchNumber     int64     `json:"batch_number"`
+	ProductId       int64     `json:"product_id"`
+	ProductCode     string    `json:"product_code"`
+	SectionId       int64     `json:"section_id"`
+	SectionNumber   int64     `json:"section_number"`
+	DueDate         time.Time `json:"due_date"`
+	DaysLeft        int       `json:"days_left"`
+	CurrentQuantity int64     `json:"current_quantity"`
+	Value           float64   `json:"value"`
+}
+
+// InventorySection is how full a section of the warehouse is. The current
+// capacity and utilization count all the stock the section holds, while the
+// products and batches of the inventory only count active stock, so the
+// quarantined batches that also take up room are reported apart.
+type InventorySection struct {
+	SectionId           int64   `json:"section_id"`
+	SectionNumber       int64   `json:"section_number"`
+	ProductTypeId       int64   `json:"product_type_id"`
+	MaximumCapacity     int64   `json:"maximum_capacity"`
+	CurrentCapacity     int64   `json:"current_capacity"`
+	Utilization         float64 `json:"utilization"`
+	Batches             int64   `json:"batches"`
+	QuarantinedBatches  int64   `json:"quarantined_batches"`
+	QuarantinedQuantity int64   `json:"quarantined_quantity"`
+}
+
+// InventoryGroup adds up the products of a product type or seller. Products
+// without a seller fall in the group with id 0.
+type InventoryGroup struct {
+	Id       int64   `json:"id"`
+	Name     string  `json:"name"`
+	Products int64   `json:"products"`
+	Batches  int64   `json:"batches"`
+	Quantity int64   `json:"quantity"`
+	Value    float64 `json:"value"`
+}
+
+type WarehouseInventory struct {
+	WarehouseId         int64              `json:"warehouse_id"`
+	WarehouseCode       string             `json:"warehouse_code"`
+	GeneratedAt         time.Time          `json:"generated_at"`
+	ExpiringDays        int                `json:"expiring_days"`
+	Quantity            int64              `json:"quantity"`
+	Value               float64            `json:"value"`
+	Utilization         float64            `json:"utilization"`
+	QuarantinedQuantity int64              `json:"quarantined_quantity"`
+	GroupBy             string             `json:"group_by,omitempty"`
+	Groups              []InventoryGroup   `json:"groups,omitempty"`
+	Products            []InventoryProduct `json:"products"`
+	ExpiringBatches     []InventoryBatch   `json:"expiring_batches"`
+	Sections            []InventorySection `json:"sections"`
+}
+
+// NewWarehouseInventory values the products and expiring batches, computes
+// the utilization of each section and of the whole warehouse, and groups
+// the products when asked to. Quarantined stock takes up room but is left
+// out of the quantity and value until released; it adds up on its own.
+func NewWarehouseInventory(
+	warehouse WarehouseModel,
+	query InventoryQuery,
+	now time.Time,
+	products []InventoryProduct,
+	expiring []InventoryBatch,
+	sections []InventorySection,
+) *WarehouseInventory {
+	inventory := &WarehouseInventory{
+		WarehouseId:     warehouse.Id,
+		WarehouseCode:   warehouse.WarehouseCode,
+		GeneratedAt:     now,
+		ExpiringDays:    query.ExpiringDays,
+		GroupBy:         query.GroupBy,
+		Products:        products,
+		ExpiringBatches: expiring,
+		Sections:        sections,
+	}
+
+	prices := map[int64]float64{}
+	for i := range inventory.Products {
+		product := &inventory.Products[i]
+		product.Value = 0
+		if product.PurchasePrice != nil {
+			prices[product.ProductId] = *product.PurchasePrice
+			product.Value = round(float64(product.Quantity) * *product.PurchasePrice)
+		}
+		inventory.Quantity += product.Quantity
+		inventory.Value += product.Value
+	}
+	inventory.Value = round(inventory.Value)
+
+	today := day(now)
+	for i := range inventory.ExpiringBatches {
+		batch := &inventory.ExpiringBatches[i]
+		batch.DaysLeft = int(day(batch.DueDate).Sub(today).Hours() / 24)
+		batch.Value = round(float64(batch.CurrentQuantity) * prices[batch.ProductId])
+	}
+
+	var current, maximum int64
+	for i := range inventory.Sections {
+		section := &inventory.Sections[i]
+		section.Utilization = utilization(section.CurrentCapacity, section.MaximumCapacity)
+		current += section.CurrentCapacity
+		maximum += section.MaximumCapacity
+		inventory.QuarantinedQuantity += section.QuarantinedQuantity
+	}
+	inventory.Utilization = utilization(current, maximum)
+
+	if query.GroupBy != "" {
+		inventory.Groups = groupProducts(query.GroupBy, inventory.Products)
+	}
+
+	return inventory
+}
+
+func groupProducts(groupBy string, products []InventoryProduct) []InventoryGroup {
+	groups := []InventoryGroup{}
+	index := map[int64]int{}
+
+	for _, product := range products {
+		id, name := product.ProductTypeId, product.ProductType
+		if groupBy == GroupBySeller {
+			id, name = 0, product.Seller
+			if product.SellerId != nil {
+				id = *product.SellerId
+			}
+		}
+
+		i, ok := index[id]
+		if !ok {
+			i = len(groups)
+			index[id] = i
+			groups = append(groups, InventoryGroup{Id: id, Name: name})
+		}
+
+		groups[i].Products++
+		groups[i].Batches += product.Batches
+		groups[i].Quantity += product.Quantity
+		groups[i].Value = round(groups[i].Value + product.Value)
+	}
+
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Id < groups[j].Id })
+	return groups
+}
+
+// utilization is the percentage of the capacity in use, to two decimal
+// places. No capacity means no utilization.
+func utilization(current, maximum int64) float64 {
+	if maximum <= 0 {
+		return 0
+	}
+	return round(float64(current) / float64(maximum) * 100)
+}
+
+func day(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
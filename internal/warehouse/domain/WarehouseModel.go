@@ -1,6 +1,22 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	maxWarehouseCodeLength = 32
+	minTelephoneDigits     = 8
+	maxTelephoneDigits     = 15
+)
+
+var (
+	warehouseCodeFormat = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9-]*$`)
+	telephoneFormat     = regexp.MustCompile(`^\+?[0-9][0-9 ()-]*$`)
+)
 
 type WarehouseModel struct {
 	Id                 int64   `json:"id"`
@@ -12,18 +28,116 @@ type WarehouseModel struct {
 	LocalityID         int64   `json:"locality_id"`
 }
 
+// Validate checks the fields a warehouse is looked up and contacted by.
+// Warehouse codes are also unique, which the database enforces.
+func (w WarehouseModel) Validate() error {
+	if err := validateAddress(w.Address); err != nil {
+		return err
+	}
+	if err := validateWarehouseCode(w.WarehouseCode); err != nil {
+		return err
+	}
+	return validateTelephone(w.Telephone)
+}
+
+func validateAddress(address string) error {
+	if strings.TrimSpace(address) == "" {
+		return ErrInvalidAddress
+	}
+	return nil
+}
+
+func validateWarehouseCode(code string) error {
+	if len(code) > maxWarehouseCodeLength || !warehouseCodeFormat.MatchString(code) {
+		return ErrInvalidWarehouseCode
+	}
+	return nil
+}
+
+func validateTelephone(telephone string) error {
+	if !telephoneFormat.MatchString(telephone) {
+		return ErrInvalidTelephone
+	}
+	digits := 0
+	for _, r := range telephone {
+		if r >= '0' && r <= '9' {
+			digits++
+		}
+	}
+	if digits < minTelephoneDigits || digits > maxTelephoneDigits {
+		return ErrInvalidTelephone
+	}
+	return nil
+}
+
+// WarehousePatch holds the warehouse fields to change. Nil fields are left
+// untouched.
+type WarehousePatch struct {
+	Address            *string
+	Telephone          *string
+	WarehouseCode      *string
+	MinimunCapacity    *int64
+	MinimunTemperature *float64
+	LocalityID         *int64
+}
+
+// Validate checks only the fields set in the patch, so warehouses stored
+// before the rules existed can still be patched field by field.
+func (p WarehousePatch) Validate() error {
+	if p.Address != nil {
+		if err := validateAddress(*p.Address); err != nil {
+			return err
+		}
+	}
+	if p.WarehouseCode != nil {
+		if err := validateWarehouseCode(*p.WarehouseCode); err != nil {
+			return err
+		}
+	}
+	if p.Telephone != nil {
+		return validateTelephone(*p.Telephone)
+	}
+	return nil
+}
+
+// Apply copies the fields set in the patch onto w.
+func (p WarehousePatch) Apply(w *WarehouseModel) {
+	if p.Address != nil {
+		w.Address = *p.Address
+	}
+	if p.Telephone != nil {
+		w.Telephone = *p.Telephone
+	}
+	if p.WarehouseCode != nil {
+		w.WarehouseCode = *p.WarehouseCode
+	}
+	if p.MinimunCapacity != nil {
+		w.MinimunCapacity = *p.MinimunCapacity
+	}
+	if p.MinimunTemperature != nil {
+		w.MinimunTemperature = *p.MinimunTemperature
+	}
+	if p.LocalityID != nil {
+		w.LocalityID = *p.LocalityID
+	}
+}
+
 type WarehouseRepository interface {
 	Create(ctx context.Context, wr *WarehouseModel) (WarehouseModel, error)
 	GetAll(ctx context.Context) ([]WarehouseModel, error)
 	GetById(ctx context.Context, id int64) (WarehouseModel, error)
 	Delete(ctx context.Context, id int64) error
 	Update(ctx context.Context, id int64, wh *WarehouseModel) (WarehouseModel, error)
+	GetInventoryProducts(ctx context.Context, id int64, at time.Time) ([]InventoryProduct, error)
+	GetExpiringBatches(ctx context.Context, id int64, until time.Time) ([]InventoryBatch, error)
+	GetSectionUsage(ctx context.Context, id int64) ([]InventorySection, error)
 }
 
 type WarehouseService interface {
 	GetAll(ctx context.Context) ([]WarehouseModel, error)
 	GetById(ctx context.Context, id int64) (WarehouseModel, error)
 	Delete(ctx context.Context, id int64) error
-	UpdateTempAndCap(ctx context.Context, id int64, mintemp float64, mincap int64) (WarehouseModel, error)
+	Update(ctx context.Context, id int64, patch WarehousePatch) (WarehouseModel, error)
 	Create(ctx context.Context, adress, tel, code string, mintemp float64, mincap int64, locality int64) (WarehouseModel, error)
+	GetInventory(ctx context.Context, query InventoryQuery) (*WarehouseInventory, error)
 }
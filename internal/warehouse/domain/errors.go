@@ -0,0 +1,13 @@
+package domain
+
+import "errors"
+
+var (
+	ErrWarehouseNotFound     = errors.New("warehouse not found")
+	ErrWarehouseCodeTaken    = errors.New("warehouse code already exists")
+	ErrInvalidAddress        = errors.New("address must not be empty")
+	ErrInvalidWarehouseCode  = errors.New("warehouse_code must be up to 32 letters, digits and dashes, starting with a letter or digit")
+	ErrInvalidTelephone      = errors.New("telephone must have 8 to 15 digits, optionally led by + and separated by spaces, dashes or parentheses")
+	ErrInvalidInventoryGroup = errors.New("group_by must be product_type or seller")
+	ErrInvalidExpiringDays   = errors.New("expiring_days must be between 1 and 365")
+)
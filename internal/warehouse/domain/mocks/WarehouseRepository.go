@@ -6,7 +6,9 @@ import (
 	context "context"
 
 	mock "github.com/stretchr/testify/mock"
-	warehouse "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/warehouse/domain"
+	domain "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/warehouse/domain"
+
+	time "time"
 )
 
 // WarehouseRepository is an autogenerated mock type for the WarehouseRepository type
@@ -15,18 +17,18 @@ type WarehouseRepository struct {
 }
 
 // Create provides a mock function with given fields: ctx, wr
-func (_m *WarehouseRepository) Create(ctx context.Context, wr *warehouse.WarehouseModel) (warehouse.WarehouseModel, error) {
+func (_m *WarehouseRepository) Create(ctx context.Context, wr *domain.WarehouseModel) (domain.WarehouseModel, error) {
 	ret := _m.Called(ctx, wr)
 
-	var r0 warehouse.WarehouseModel
-	if rf, ok := ret.Get(0).(func(context.Context, *warehouse.WarehouseModel) warehouse.WarehouseModel); ok {
+	var r0 domain.WarehouseModel
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.WarehouseModel) domain.WarehouseModel); ok {
 		r0 = rf(ctx, wr)
 	} else {
-		r0 = ret.Get(0).(warehouse.WarehouseModel)
+		r0 = ret.Get(0).(domain.WarehouseModel)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, *warehouse.WarehouseModel) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.WarehouseModel) error); ok {
 		r1 = rf(ctx, wr)
 	} else {
 		r1 = ret.Error(1)
@@ -50,15 +52,15 @@ func (_m *WarehouseRepository) Delete(ctx context.Context, id int64) error {
 }
 
 // GetAll provides a mock function with given fields: ctx
-func (_m *WarehouseRepository) GetAll(ctx context.Context) ([]warehouse.WarehouseModel, error) {
+func (_m *WarehouseRepository) GetAll(ctx context.Context) ([]domain.WarehouseModel, error) {
 	ret := _m.Called(ctx)
 
-	var r0 []warehouse.WarehouseModel
-	if rf, ok := ret.Get(0).(func(context.Context) []warehouse.WarehouseModel); ok {
+	var r0 []domain.WarehouseModel
+	if rf, ok := ret.Get(0).(func(context.Context) []domain.WarehouseModel); ok {
 		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]warehouse.WarehouseModel)
+			r0 = ret.Get(0).([]domain.WarehouseModel)
 		}
 	}
 
@@ -73,14 +75,83 @@ func (_m *WarehouseRepository) GetAll(ctx context.Context) ([]warehouse.Warehous
 }
 
 // GetById provides a mock function with given fields: ctx, id
-func (_m *WarehouseRepository) GetById(ctx context.Context, id int64) (warehouse.WarehouseModel, error) {
+func (_m *WarehouseRepository) GetById(ctx context.Context, id int64) (domain.WarehouseModel, error) {
 	ret := _m.Called(ctx, id)
 
-	var r0 warehouse.WarehouseModel
-	if rf, ok := ret.Get(0).(func(context.Context, int64) warehouse.WarehouseModel); ok {
+	var r0 domain.WarehouseModel
+	if rf, ok := ret.Get(0).(func(context.Context, int64) domain.WarehouseModel); ok {
 		r0 = rf(ctx, id)
 	} else {
-		r0 = ret.Get(0).(warehouse.WarehouseModel)
+		r0 = ret.Get(0).(domain.WarehouseModel)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetExpiringBatches provides a mock function with given fields: ctx, id, until
+func (_m *WarehouseRepository) GetExpiringBatches(ctx context.Context, id int64, until time.Time) ([]domain.InventoryBatch, error) {
+	ret := _m.Called(ctx, id, until)
+
+	var r0 []domain.InventoryBatch
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time) []domain.InventoryBatch); ok {
+		r0 = rf(ctx, id, until)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.InventoryBatch)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, time.Time) error); ok {
+		r1 = rf(ctx, id, until)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetInventoryProducts provides a mock function with given fields: ctx, id, at
+func (_m *WarehouseRepository) GetInventoryProducts(ctx context.Context, id int64, at time.Time) ([]domain.InventoryProduct, error) {
+	ret := _m.Called(ctx, id, at)
+
+	var r0 []domain.InventoryProduct
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time) []domain.InventoryProduct); ok {
+		r0 = rf(ctx, id, at)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.InventoryProduct)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, time.Time) error); ok {
+		r1 = rf(ctx, id, at)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSectionUsage provides a mock function with given fields: ctx, id
+func (_m *WarehouseRepository) GetSectionUsage(ctx context.Context, id int64) ([]domain.InventorySection, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 []domain.InventorySection
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []domain.InventorySection); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.InventorySection)
+		}
 	}
 
 	var r1 error
@@ -94,18 +165,18 @@ func (_m *WarehouseRepository) GetById(ctx context.Context, id int64) (warehouse
 }
 
 // Update provides a mock function with given fields: ctx, id, wh
-func (_m *WarehouseRepository) Update(ctx context.Context, id int64, wh *warehouse.WarehouseModel) (warehouse.WarehouseModel, error) {
+func (_m *WarehouseRepository) Update(ctx context.Context, id int64, wh *domain.WarehouseModel) (domain.WarehouseModel, error) {
 	ret := _m.Called(ctx, id, wh)
 
-	var r0 warehouse.WarehouseModel
-	if rf, ok := ret.Get(0).(func(context.Context, int64, *warehouse.WarehouseModel) warehouse.WarehouseModel); ok {
+	var r0 domain.WarehouseModel
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *domain.WarehouseModel) domain.WarehouseModel); ok {
 		r0 = rf(ctx, id, wh)
 	} else {
-		r0 = ret.Get(0).(warehouse.WarehouseModel)
+		r0 = ret.Get(0).(domain.WarehouseModel)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, int64, *warehouse.WarehouseModel) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, int64, *domain.WarehouseModel) error); ok {
 		r1 = rf(ctx, id, wh)
 	} else {
 		r1 = ret.Error(1)
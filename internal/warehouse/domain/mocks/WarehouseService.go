@@ -6,7 +6,7 @@ import (
 	context "context"
 
 	mock "github.com/stretchr/testify/mock"
-	warehouse "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/warehouse/domain"
+	domain "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/warehouse/domain"
 )
 
 // WarehouseService is an autogenerated mock type for the WarehouseService type
@@ -15,14 +15,14 @@ type WarehouseService struct {
 }
 
 // Create provides a mock function with given fields: ctx, adress, tel, code, mintemp, mincap, locality
-func (_m *WarehouseService) Create(ctx context.Context, adress string, tel string, code string, mintemp float64, mincap int64, locality int64) (warehouse.WarehouseModel, error) {
+func (_m *WarehouseService) Create(ctx context.Context, adress string, tel string, code string, mintemp float64, mincap int64, locality int64) (domain.WarehouseModel, error) {
 	ret := _m.Called(ctx, adress, tel, code, mintemp, mincap, locality)
 
-	var r0 warehouse.WarehouseModel
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, float64, int64, int64) warehouse.WarehouseModel); ok {
+	var r0 domain.WarehouseModel
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, float64, int64, int64) domain.WarehouseModel); ok {
 		r0 = rf(ctx, adress, tel, code, mintemp, mincap, locality)
 	} else {
-		r0 = ret.Get(0).(warehouse.WarehouseModel)
+		r0 = ret.Get(0).(domain.WarehouseModel)
 	}
 
 	var r1 error
@@ -50,15 +50,15 @@ func (_m *WarehouseService) Delete(ctx context.Context, id int64) error {
 }
 
 // GetAll provides a mock function with given fields: ctx
-func (_m *WarehouseService) GetAll(ctx context.Context) ([]warehouse.WarehouseModel, error) {
+func (_m *WarehouseService) GetAll(ctx context.Context) ([]domain.WarehouseModel, error) {
 	ret := _m.Called(ctx)
 
-	var r0 []warehouse.WarehouseModel
-	if rf, ok := ret.Get(0).(func(context.Context) []warehouse.WarehouseModel); ok {
+	var r0 []domain.WarehouseModel
+	if rf, ok := ret.Get(0).(func(context.Context) []domain.WarehouseModel); ok {
 		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]warehouse.WarehouseModel)
+			r0 = ret.Get(0).([]domain.WarehouseModel)
 		}
 	}
 
@@ -73,14 +73,14 @@ func (_m *WarehouseService) GetAll(ctx context.Context) ([]warehouse.WarehouseMo
 }
 
 // GetById provides a mock function with given fields: ctx, id
-func (_m *WarehouseService) GetById(ctx context.Context, id int64) (warehouse.WarehouseModel, error) {
+func (_m *WarehouseService) GetById(ctx context.Context, id int64) (domain.WarehouseModel, error) {
 	ret := _m.Called(ctx, id)
 
-	var r0 warehouse.WarehouseModel
-	if rf, ok := ret.Get(0).(func(context.Context, int64) warehouse.WarehouseModel); ok {
+	var r0 domain.WarehouseModel
+	if rf, ok := ret.Get(0).(func(context.Context, int64) domain.WarehouseModel); ok {
 		r0 = rf(ctx, id)
 	} else {
-		r0 = ret.Get(0).(warehouse.WarehouseModel)
+		r0 = ret.Get(0).(domain.WarehouseModel)
 	}
 
 	var r1 error
@@ -93,20 +93,43 @@ func (_m *WarehouseService) GetById(ctx context.Context, id int64) (warehouse.Wa
 	return r0, r1
 }
 
-// UpdateTempAndCap provides a mock function with given fields: ctx, id, mintemp, mincap
-func (_m *WarehouseService) UpdateTempAndCap(ctx context.Context, id int64, mintemp float64, mincap int64) (warehouse.WarehouseModel, error) {
-	ret := _m.Called(ctx, id, mintemp, mincap)
+// GetInventory provides a mock function with given fields: ctx, query
+func (_m *WarehouseService) GetInventory(ctx context.Context, query domain.InventoryQuery) (*domain.WarehouseInventory, error) {
+	ret := _m.Called(ctx, query)
 
-	var r0 warehouse.WarehouseModel
-	if rf, ok := ret.Get(0).(func(context.Context, int64, float64, int64) warehouse.WarehouseModel); ok {
-		r0 = rf(ctx, id, mintemp, mincap)
+	var r0 *domain.WarehouseInventory
+	if rf, ok := ret.Get(0).(func(context.Context, domain.InventoryQuery) *domain.WarehouseInventory); ok {
+		r0 = rf(ctx, query)
 	} else {
-		r0 = ret.Get(0).(warehouse.WarehouseModel)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.WarehouseInventory)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, domain.InventoryQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: ctx, id, patch
+func (_m *WarehouseService) Update(ctx context.Context, id int64, patch domain.WarehousePatch) (domain.WarehouseModel, error) {
+	ret := _m.Called(ctx, id, patch)
+
+	var r0 domain.WarehouseModel
+	if rf, ok := ret.Get(0).(func(context.Context, int64, domain.WarehousePatch) domain.WarehouseModel); ok {
+		r0 = rf(ctx, id, patch)
+	} else {
+		r0 = ret.Get(0).(domain.WarehouseModel)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, int64, float64, int64) error); ok {
-		r1 = rf(ctx, id, mintemp, mincap)
+	if rf, ok := ret.Get(1).(func(context.Context, int64, domain.WarehousePatch) error); ok {
+		r1 = rf(ctx, id, patch)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -13,8 +13,78 @@ const (
 
 	UpdateWarehouse = `
     UPDATE warehouses
-    SET minimun_capacity = ?, minimun_temperature  = ?
+    SET address = ?, telephone = ?, warehouse_code = ?, minimun_capacity = ?, minimun_temperature = ?, locality_id = ?
     WHERE id=?
     `
 	DeleteWarehouse = "DELETE FROM warehouses WHERE id=?"
+
+	// Inventory counts the active batches that still hold stock, valued at
+	// the latest purchase price recorded up to the given time.
+	GetInventoryProducts = `
+    SELECT
+        p.id,
+        p.product_code,
+        p.description,
+        p.product_type_id,
+        pt.description,
+        p.seller_id,
+        IFNULL(se.company_name, ''),
+        COUNT(pb.id),
+        SUM(pb.current_quantity),
+        (
+            SELECT pr.purchase_price FROM product_records pr
+            WHERE pr.product_id = p.id AND pr.last_update_date <= ?
+            ORDER BY pr.last_update_date DESC, pr.id DESC
+            LIMIT 1
+        )
+    FROM product_batches pb
+    JOIN sections s ON s.id = pb.section_id
+    JOIN products p ON p.id = pb.product_id
+    JOIN product_types pt ON pt.id = p.product_type_id
+    LEFT JOIN sellers se ON se.id = p.seller_id
+    WHERE s.warehouse_id = ?
+    AND pb.status = 'active'
+    AND pb.current_quantity > 0
+    GROUP BY p.id, pt.description, se.company_name
+    ORDER BY p.product_code
+    `
+
+	GetInventoryExpiringBatches = `
+    SELECT
+        pb.id,
+        pb.batch_number,
+        pb.product_id,
+        p.product_code,
+        s.id,
+        s.section_number,
+        pb.due_date,
+        pb.current_quantity
+    FROM product_batches pb
+    JOIN sections s ON s.id = pb.section_id
+    JOIN products p ON p.id = pb.product_id
+    WHERE s.warehouse_id = ?
+    AND pb.status = 'active'
+    AND pb.current_quantity > 0
+    AND pb.due_date <= ?
+    ORDER BY pb.due_date, pb.id
+    `
+
+	GetInventorySections = `
+    SELECT
+        s.id,
+        s.section_number,
+        s.product_type_id,
+        s.maximum_capacity,
+        s.current_capacity,
+        COUNT(CASE WHEN pb.status = 'active' THEN pb.id END),
+        COUNT(CASE WHEN pb.status = 'quarantined' THEN pb.id END),
+        IFNULL(SUM(CASE WHEN pb.status = 'quarantined' THEN pb.current_quantity END), 0)
+    FROM sections s
+    LEFT JOIN product_batches pb
+        ON pb.section_id = s.id AND pb.status IN ('active', 'quarantined') AND pb.current_quantity > 0
+    WHERE s.warehouse_id = ?
+    AND s.archived_at IS NULL
+    GROUP BY s.id
+    ORDER BY s.section_number
+    `
 )
@@ -3,11 +3,17 @@ package respository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"time"
 
+	"github.com/go-sql-driver/mysql"
 	warehouse "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/warehouse/domain"
 )
 
+// Warehouse codes are unique.
+const errDuplicateEntry = 1062
+
 type mariadbWarehouse struct {
 	db *sql.DB
 }
@@ -31,7 +37,7 @@ func (r *mariadbWarehouse) Create(ctx context.Context, wr *warehouse.WarehouseMo
 	)
 
 	if err != nil {
-		return warehouse.WarehouseModel{}, err
+		return warehouse.WarehouseModel{}, mapError(err)
 	}
 
 	newWarehouseId, _ := result.LastInsertId()
@@ -87,7 +93,7 @@ func (r *mariadbWarehouse) GetById(ctx context.Context, id int64) (warehouse.War
 
 	var warehouseRow warehouse.WarehouseModel
 
-	if err := result.Scan(
+	err := result.Scan(
 		&warehouseRow.Id,
 		&warehouseRow.Address,
 		&warehouseRow.Telephone,
@@ -95,7 +101,11 @@ func (r *mariadbWarehouse) GetById(ctx context.Context, id int64) (warehouse.War
 		&warehouseRow.MinimunCapacity,
 		&warehouseRow.MinimunTemperature,
 		&warehouseRow.LocalityID,
-	); err != nil {
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return warehouse.WarehouseModel{}, warehouse.ErrWarehouseNotFound
+	}
+	if err != nil {
 		return warehouse.WarehouseModel{}, err
 	}
 
@@ -122,18 +132,132 @@ func (r *mariadbWarehouse) Update(ctx context.Context, id int64, wh *warehouse.W
 	_, err := r.db.ExecContext(
 		ctx,
 		UpdateWarehouse,
+		wh.Address,
+		wh.Telephone,
+		wh.WarehouseCode,
 		wh.MinimunCapacity,
 		wh.MinimunTemperature,
+		wh.LocalityID,
 		id,
 	)
 
 	if err != nil {
-		return warehouse.WarehouseModel{}, err
+		return warehouse.WarehouseModel{}, mapError(err)
 	}
 
-	return warehouse.WarehouseModel{
-		MinimunTemperature: wh.MinimunTemperature,
-		MinimunCapacity:    wh.MinimunCapacity,
-	}, nil
+	return *wh, nil
+}
+
+func (r *mariadbWarehouse) GetInventoryProducts(ctx context.Context, id int64, at time.Time) ([]warehouse.InventoryProduct, error) {
+	products := []warehouse.InventoryProduct{}
+
+	rows, err := r.db.QueryContext(ctx, GetInventoryProducts, at, id)
+	if err != nil {
+		return nil, err
+	}
 
+	defer rows.Close()
+
+	for rows.Next() {
+		var product warehouse.InventoryProduct
+		var sellerId sql.NullInt64
+		var purchasePrice sql.NullFloat64
+
+		err := rows.Scan(
+			&product.ProductId,
+			&product.ProductCode,
+			&product.Description,
+			&product.ProductTypeId,
+			&product.ProductType,
+			&sellerId,
+			&product.Seller,
+			&product.Batches,
+			&product.Quantity,
+			&purchasePrice,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if sellerId.Valid {
+			product.SellerId = &sellerId.Int64
+		}
+		if purchasePrice.Valid {
+			product.PurchasePrice = &purchasePrice.Float64
+		}
+		products = append(products, product)
+	}
+
+	return products, rows.Err()
+}
+
+func (r *mariadbWarehouse) GetExpiringBatches(ctx context.Context, id int64, until time.Time) ([]warehouse.InventoryBatch, error) {
+	batches := []warehouse.InventoryBatch{}
+
+	rows, err := r.db.QueryContext(ctx, GetInventoryExpiringBatches, id, until)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var batch warehouse.InventoryBatch
+
+		err := rows.Scan(
+			&batch.Id,
+			&batch.BatchNumber,
+			&batch.ProductId,
+			&batch.ProductCode,
+			&batch.SectionId,
+			&batch.SectionNumber,
+			&batch.DueDate,
+			&batch.CurrentQuantity,
+		)
+		if err != nil {
+			return nil, err
+		}
+		batches = append(batches, batch)
+	}
+
+	return batches, rows.Err()
+}
+
+func (r *mariadbWarehouse) GetSectionUsage(ctx context.Context, id int64) ([]warehouse.InventorySection, error) {
+	sections := []warehouse.InventorySection{}
+
+	rows, err := r.db.QueryContext(ctx, GetInventorySections, id)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var section warehouse.InventorySection
+
+		err := rows.Scan(
+			&section.SectionId,
+			&section.SectionNumber,
+			&section.ProductTypeId,
+			&section.MaximumCapacity,
+			&section.CurrentCapacity,
+			&section.Batches,
+			&section.QuarantinedBatches,
+			&section.QuarantinedQuantity,
+		)
+		if err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+
+	return sections, rows.Err()
+}
+
+func mapError(err error) error {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == errDuplicateEntry {
+		return warehouse.ErrWarehouseCodeTaken
+	}
+	return err
 }
@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
 	"github.com/stretchr/testify/assert"
 	warehouse "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/warehouse/domain"
 )
@@ -52,8 +54,13 @@ var mockWarehouse warehouse.WarehouseModel = warehouse.WarehouseModel{
 }
 
 var updateWarehouse warehouse.WarehouseModel = warehouse.WarehouseModel{
+	Id:                 1,
+	Address:            "Avenida Teste 3",
+	Telephone:          "31 777777777",
+	WarehouseCode:      "31",
 	MinimunCapacity:    77777,
 	MinimunTemperature: 888888,
+	LocalityID:         2,
 }
 
 func Test_repository_create(t *testing.T) {
@@ -105,6 +112,21 @@ func Test_repository_create(t *testing.T) {
 		assert.Empty(t, newWarehouse)
 	})
 
+	t.Run("create_duplicate_code: return ErrWarehouseCodeTaken when the code is in use", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(CreateWarehouse)).
+			WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry '30' for key 'warehouse_code_UNIQUE'"})
+
+		mariadbWarehouse := NewMariadbWarehouseRepository(db)
+
+		_, err = mariadbWarehouse.Create(context.TODO(), &mockWarehouse)
+
+		assert.ErrorIs(t, err, warehouse.ErrWarehouseCodeTaken)
+	})
+
 }
 
 func Test_repository_update(t *testing.T) {
@@ -134,8 +156,12 @@ func Test_repository_update(t *testing.T) {
 		defer db.Close()
 
 		mock.ExpectExec(regexp.QuoteMeta(UpdateWarehouse)).WithArgs(
+			updateWarehouse.Address,
+			updateWarehouse.Telephone,
+			updateWarehouse.WarehouseCode,
 			updateWarehouse.MinimunCapacity,
 			updateWarehouse.MinimunTemperature,
+			updateWarehouse.LocalityID,
 			expectedWarehouse.Id,
 		).WillReturnResult(sqlmock.NewResult(0, 1))
 
@@ -146,6 +172,20 @@ func Test_repository_update(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, expectReturn, updateWarehouse)
 	})
+	t.Run("update_duplicate_code: return ErrWarehouseCodeTaken when the code is in use", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(UpdateWarehouse)).
+			WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry '31' for key 'warehouse_code_UNIQUE'"})
+
+		mariadbWarehouse := NewMariadbWarehouseRepository(db)
+
+		_, err = mariadbWarehouse.Update(context.Background(), expectedWarehouse.Id, &updateWarehouse)
+
+		assert.ErrorIs(t, err, warehouse.ErrWarehouseCodeTaken)
+	})
 }
 
 func Test_repository_getall(t *testing.T) {
@@ -326,3 +366,97 @@ func Test_repository_delete(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func Test_repository_inventory(t *testing.T) {
+	at := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("get_inventory_products: should return the stock per product with its latest price", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		rows := sqlmock.NewRows([]string{
+			"id", "product_code", "description", "product_type_id", "product_type",
+			"seller_id", "seller", "batches", "quantity", "purchase_price",
+		}).
+			AddRow(1, "P-1", "fish", 1, "frozen", 7, "Acme", 2, "10", 2.5).
+			AddRow(2, "P-2", "lettuce", 2, "fresh", nil, "", 1, "4", nil)
+
+		mock.ExpectQuery(regexp.QuoteMeta(GetInventoryProducts)).
+			WithArgs(at, int64(1)).
+			WillReturnRows(rows)
+
+		repo := NewMariadbWarehouseRepository(db)
+		result, err := repo.GetInventoryProducts(context.Background(), 1, at)
+
+		assert.NoError(t, err)
+		assert.Len(t, result, 2)
+		assert.Equal(t, int64(10), result[0].Quantity)
+		assert.Equal(t, int64(7), *result[0].SellerId)
+		assert.Equal(t, 2.5, *result[0].PurchasePrice)
+		assert.Nil(t, result[1].SellerId)
+		assert.Nil(t, result[1].PurchasePrice)
+	})
+
+	t.Run("get_expiring_batches: should return the batches due until the given time", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		rows := sqlmock.NewRows([]string{
+			"id", "batch_number", "product_id", "product_code", "section_id", "section_number", "due_date", "current_quantity",
+		}).AddRow(9, 99, 1, "P-1", 3, 30, at, 4)
+
+		mock.ExpectQuery(regexp.QuoteMeta(GetInventoryExpiringBatches)).
+			WithArgs(int64(1), at).
+			WillReturnRows(rows)
+
+		repo := NewMariadbWarehouseRepository(db)
+		result, err := repo.GetExpiringBatches(context.Background(), 1, at)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []warehouse.InventoryBatch{
+			{Id: 9, BatchNumber: 99, ProductId: 1, ProductCode: "P-1", SectionId: 3, SectionNumber: 30, DueDate: at, CurrentQuantity: 4},
+		}, result)
+	})
+
+	t.Run("get_section_usage: should return the sections of the warehouse", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		rows := sqlmock.NewRows([]string{
+			"id", "section_number", "product_type_id", "maximum_capacity", "current_capacity", "batches",
+			"quarantined_batches", "quarantined_quantity",
+		}).AddRow(3, 30, 1, 10, 5, 2, 1, 3)
+
+		mock.ExpectQuery(regexp.QuoteMeta(GetInventorySections)).
+			WithArgs(int64(1)).
+			WillReturnRows(rows)
+
+		repo := NewMariadbWarehouseRepository(db)
+		result, err := repo.GetSectionUsage(context.Background(), 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []warehouse.InventorySection{
+			{
+				SectionId: 3, SectionNumber: 30, ProductTypeId: 1, MaximumCapacity: 10, CurrentCapacity: 5,
+				Batches: 2, QuarantinedBatches: 1, QuarantinedQuantity: 3,
+			},
+		}, result)
+	})
+
+	t.Run("get_inventory_query_error: should return the error", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta(GetInventoryProducts)).
+			WillReturnError(fmt.Errorf("any error"))
+
+		repo := NewMariadbWarehouseRepository(db)
+		_, err = repo.GetInventoryProducts(context.Background(), 1, at)
+
+		assert.Error(t, err)
+	})
+}
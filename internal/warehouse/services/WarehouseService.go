@@ -2,28 +2,40 @@ package services
 
 import (
 	"context"
+	"time"
 
+	locality "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/locality/domain"
 	warehouse "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/warehouse/domain"
 )
 
 type service struct {
-	repository warehouse.WarehouseRepository
+	repository         warehouse.WarehouseRepository
+	localityRepository locality.LocalityRepository
 }
 
-func NewWarehouseService(r warehouse.WarehouseRepository) warehouse.WarehouseService {
+func NewWarehouseService(r warehouse.WarehouseRepository, localityRepository locality.LocalityRepository) warehouse.WarehouseService {
 	return &service{
-		repository: r,
+		repository:         r,
+		localityRepository: localityRepository,
 	}
 }
 
-func (s service) Create(ctx context.Context, adress, tel, code string, mintemp float64, mincap int64, locality int64) (warehouse.WarehouseModel, error) {
+func (s service) Create(ctx context.Context, adress, tel, code string, mintemp float64, mincap int64, localityId int64) (warehouse.WarehouseModel, error) {
 	new := warehouse.WarehouseModel{
 		Address:            adress,
 		Telephone:          tel,
 		WarehouseCode:      code,
 		MinimunCapacity:    mincap,
 		MinimunTemperature: mintemp,
-		LocalityID:         locality,
+		LocalityID:         localityId,
+	}
+
+	if err := new.Validate(); err != nil {
+		return warehouse.WarehouseModel{}, err
+	}
+
+	if _, err := s.localityRepository.GetById(ctx, localityId); err != nil {
+		return warehouse.WarehouseModel{}, err
 	}
 
 	wh, err := s.repository.Create(ctx, &new)
@@ -65,23 +77,62 @@ func (s service) Delete(ctx context.Context, id int64) error {
 	return nil
 }
 
-func (s service) UpdateTempAndCap(ctx context.Context, id int64, mintemp float64, mincap int64) (warehouse.WarehouseModel, error) {
-	requestWarehouse := warehouse.WarehouseModel{
-		MinimunCapacity:    mincap,
-		MinimunTemperature: mintemp,
+func (s service) Update(ctx context.Context, id int64, patch warehouse.WarehousePatch) (warehouse.WarehouseModel, error) {
+	if err := patch.Validate(); err != nil {
+		return warehouse.WarehouseModel{}, err
 	}
 
-	_, err := s.repository.Update(ctx, id, &requestWarehouse)
-
+	wh, err := s.repository.GetById(ctx, id)
 	if err != nil {
 		return warehouse.WarehouseModel{}, err
 	}
 
-	warehouseUpdated, err := s.repository.GetById(ctx, id)
+	patch.Apply(&wh)
+
+	if patch.LocalityID != nil {
+		if _, err := s.localityRepository.GetById(ctx, wh.LocalityID); err != nil {
+			return warehouse.WarehouseModel{}, err
+		}
+	}
+
+	return s.repository.Update(ctx, id, &wh)
+}
+
+// GetInventory gathers what the warehouse holds: its products, the batches
+// nearing expiry and how full its sections are.
+func (s service) GetInventory(ctx context.Context, query warehouse.InventoryQuery) (*warehouse.WarehouseInventory, error) {
+	if !warehouse.IsInventoryGroup(query.GroupBy) {
+		return nil, warehouse.ErrInvalidInventoryGroup
+	}
+
+	if query.ExpiringDays == 0 {
+		query.ExpiringDays = warehouse.DefaultInventoryExpiringDays
+	}
+	if query.ExpiringDays < 1 || query.ExpiringDays > warehouse.MaxInventoryExpiringDays {
+		return nil, warehouse.ErrInvalidExpiringDays
+	}
 
+	wh, err := s.repository.GetById(ctx, query.WarehouseId)
 	if err != nil {
-		return warehouse.WarehouseModel{}, err
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+
+	products, err := s.repository.GetInventoryProducts(ctx, wh.Id, now)
+	if err != nil {
+		return nil, err
+	}
+
+	expiring, err := s.repository.GetExpiringBatches(ctx, wh.Id, now.AddDate(0, 0, query.ExpiringDays))
+	if err != nil {
+		return nil, err
+	}
+
+	sections, err := s.repository.GetSectionUsage(ctx, wh.Id)
+	if err != nil {
+		return nil, err
 	}
 
-	return warehouseUpdated, nil
+	return warehouse.NewWarehouseInventory(wh, query, now, products, expiring, sections), nil
 }
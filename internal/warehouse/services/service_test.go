@@ -4,9 +4,13 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 
+	locality "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/locality/domain"
+	localityMocks "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/locality/domain/mocks"
 	warehouse "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/warehouse/domain"
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/warehouse/domain/mocks"
 	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/warehouse/services"
@@ -49,10 +53,12 @@ func Test_Service_Create(t *testing.T) {
 		ctx := context.Background()
 
 		repo := mocks.NewWarehouseRepository(t)
+		localities := localityMocks.NewLocalityRepository(t)
 
+		localities.On("GetById", ctx, int64(1)).Return(&locality.LocalityModel{Id: 1}, nil)
 		repo.On("Create", ctx, &expectedWarehouse).Return(expectedWarehouse, nil)
 
-		service := services.NewWarehouseService(repo)
+		service := services.NewWarehouseService(repo, localities)
 
 		result, _ := service.Create(ctx, "Avenida Teste", "31 999999999", "30", 9, 10, 1)
 
@@ -62,19 +68,49 @@ func Test_Service_Create(t *testing.T) {
 	t.Run("create_conflict: return erro when try to register a warehouse with code the already exist", func(t *testing.T) {
 		ctx := context.Background()
 
-		errMsg := fmt.Errorf("the product with code %d has already been registered", expectedWarehouse.Id)
-
 		repo := mocks.NewWarehouseRepository(t)
+		localities := localityMocks.NewLocalityRepository(t)
 
-		repo.On("Create", ctx, &expectedWarehouse).Return(warehouse.WarehouseModel{}, errMsg)
+		localities.On("GetById", ctx, int64(1)).Return(&locality.LocalityModel{Id: 1}, nil)
+		repo.On("Create", ctx, &expectedWarehouse).Return(warehouse.WarehouseModel{}, warehouse.ErrWarehouseCodeTaken)
 
-		service := services.NewWarehouseService(repo)
+		service := services.NewWarehouseService(repo, localities)
 
 		_, err := service.Create(ctx, "Avenida Teste", "31 999999999", "30", 9, 10, 1)
 
-		assert.Error(t, err)
+		assert.ErrorIs(t, err, warehouse.ErrWarehouseCodeTaken)
 	})
 
+	t.Run("create_invalid_fields: return the validation error without touching the database", func(t *testing.T) {
+		ctx := context.Background()
+
+		service := services.NewWarehouseService(mocks.NewWarehouseRepository(t), localityMocks.NewLocalityRepository(t))
+
+		_, err := service.Create(ctx, "Avenida Teste", "31 999999999", "wh 30", 9, 10, 1)
+		assert.ErrorIs(t, err, warehouse.ErrInvalidWarehouseCode)
+
+		_, err = service.Create(ctx, "Avenida Teste", "9999", "30", 9, 10, 1)
+		assert.ErrorIs(t, err, warehouse.ErrInvalidTelephone)
+
+		_, err = service.Create(ctx, "Avenida Teste", "phone 31 999999999", "30", 9, 10, 1)
+		assert.ErrorIs(t, err, warehouse.ErrInvalidTelephone)
+
+		_, err = service.Create(ctx, " ", "31 999999999", "30", 9, 10, 1)
+		assert.ErrorIs(t, err, warehouse.ErrInvalidAddress)
+	})
+
+	t.Run("create_locality_not_found: return error when the locality does not exist", func(t *testing.T) {
+		ctx := context.Background()
+
+		localities := localityMocks.NewLocalityRepository(t)
+		localities.On("GetById", ctx, int64(1)).Return(nil, locality.ErrLocalityNotFound)
+
+		service := services.NewWarehouseService(mocks.NewWarehouseRepository(t), localities)
+
+		_, err := service.Create(ctx, "Avenida Teste", "31 999999999", "30", 9, 10, 1)
+
+		assert.ErrorIs(t, err, locality.ErrLocalityNotFound)
+	})
 }
 
 func Test_Service_GetAll(t *testing.T) {
@@ -86,7 +122,7 @@ func Test_Service_GetAll(t *testing.T) {
 
 		repo.On("GetAll", ctx).Return(expectedWarehouseList, nil)
 
-		service := services.NewWarehouseService(repo)
+		service := services.NewWarehouseService(repo, localityMocks.NewLocalityRepository(t))
 
 		resultList, _ := service.GetAll(ctx)
 
@@ -102,7 +138,7 @@ func Test_Service_GetAll(t *testing.T) {
 
 		repo.On("GetAll", ctx).Return([]warehouse.WarehouseModel{}, errMsg)
 
-		service := services.NewWarehouseService(repo)
+		service := services.NewWarehouseService(repo, localityMocks.NewLocalityRepository(t))
 
 		_, err := service.GetAll(ctx)
 
@@ -119,7 +155,7 @@ func Test_Service_GetByID(t *testing.T) {
 
 		repo.On("GetById", ctx, int64(1)).Return(expectedWarehouseList[1], nil)
 
-		service := services.NewWarehouseService(repo)
+		service := services.NewWarehouseService(repo, localityMocks.NewLocalityRepository(t))
 
 		result, _ := service.GetById(ctx, int64(1))
 
@@ -136,7 +172,7 @@ func Test_Service_GetByID(t *testing.T) {
 
 		repo.On("GetById", ctx, int64(Id)).Return(warehouse.WarehouseModel{}, errMsg)
 
-		service := services.NewWarehouseService(repo)
+		service := services.NewWarehouseService(repo, localityMocks.NewLocalityRepository(t))
 
 		_, err := service.GetById(ctx, int64(Id))
 
@@ -144,59 +180,138 @@ func Test_Service_GetByID(t *testing.T) {
 	})
 }
 
-func Test_Service_UpdateTempAndCap(t *testing.T) {
+func Test_Service_Update(t *testing.T) {
+	code := "wh-31"
+	telephone := "+55 (31) 98888-7777"
+	localityId := int64(2)
+	mincap := int64(999)
 
-	updateWarehouse := warehouse.WarehouseModel{
-		MinimunCapacity:    999.0,
-		MinimunTemperature: 777.0,
-	}
+	updatedWarehouse := expectedWarehouse
+	updatedWarehouse.WarehouseCode = code
+	updatedWarehouse.Telephone = telephone
+	updatedWarehouse.LocalityID = localityId
+	updatedWarehouse.MinimunCapacity = mincap
 
 	t.Run("update_existent: Se os campos forem atualizados com sucesso retornará a informação do elemento atualizado", func(t *testing.T) {
 		ctx := context.Background()
 
 		repo := mocks.NewWarehouseRepository(t)
+		localities := localityMocks.NewLocalityRepository(t)
 
-		repo.On("Update", ctx, expectedWarehouse.Id, &updateWarehouse).Return(expectedWarehouse, nil)
-		repo.On("GetById", ctx, expectedWarehouse.Id).Return(expectedWarehouse, nil)
+		repo.On("GetById", ctx, expectedWarehouse.Id).Return(expectedWarehouse, nil).Once()
+		localities.On("GetById", ctx, localityId).Return(&locality.LocalityModel{Id: localityId}, nil).Once()
+		repo.On("Update", ctx, expectedWarehouse.Id, &updatedWarehouse).Return(updatedWarehouse, nil).Once()
 
-		service := services.NewWarehouseService(repo)
+		service := services.NewWarehouseService(repo, localities)
 
-		result, _ := service.UpdateTempAndCap(ctx, expectedWarehouse.Id, updateWarehouse.MinimunTemperature, updateWarehouse.MinimunCapacity)
+		result, err := service.Update(ctx, expectedWarehouse.Id, warehouse.WarehousePatch{
+			Telephone:       &telephone,
+			WarehouseCode:   &code,
+			MinimunCapacity: &mincap,
+			LocalityID:      &localityId,
+		})
 
-		assert.Equal(t, expectedWarehouse, result)
+		assert.NoError(t, err)
+		assert.Equal(t, updatedWarehouse, result)
 	})
 
-	t.Run("update_non_existent: Se não for encontrado um warehouse com o ID retornar um error informando", func(t *testing.T) {
+	t.Run("update_without_locality: the locality is only checked when it changes", func(t *testing.T) {
 		ctx := context.Background()
 
-		errMsg := fmt.Errorf("erros: no warehouse was found with id %d", expectedWarehouse.Id)
+		onlyCapacity := expectedWarehouse
+		onlyCapacity.MinimunCapacity = mincap
 
 		repo := mocks.NewWarehouseRepository(t)
+		repo.On("GetById", ctx, expectedWarehouse.Id).Return(expectedWarehouse, nil).Once()
+		repo.On("Update", ctx, expectedWarehouse.Id, &onlyCapacity).Return(onlyCapacity, nil).Once()
 
-		repo.On("Update", ctx, expectedWarehouse.Id, &updateWarehouse).Return(warehouse.WarehouseModel{}, errMsg)
+		service := services.NewWarehouseService(repo, localityMocks.NewLocalityRepository(t))
 
-		service := services.NewWarehouseService(repo)
+		result, err := service.Update(ctx, expectedWarehouse.Id, warehouse.WarehousePatch{MinimunCapacity: &mincap})
 
-		_, err := service.UpdateTempAndCap(ctx, expectedWarehouse.Id, updateWarehouse.MinimunTemperature, updateWarehouse.MinimunCapacity)
+		assert.NoError(t, err)
+		assert.Equal(t, onlyCapacity, result)
+	})
 
-		assert.Error(t, err)
+	t.Run("update_non_existent: Se não for encontrado um warehouse com o ID retornar um error informando", func(t *testing.T) {
+		ctx := context.Background()
+
+		repo := mocks.NewWarehouseRepository(t)
+		repo.On("GetById", ctx, expectedWarehouse.Id).Return(warehouse.WarehouseModel{}, warehouse.ErrWarehouseNotFound).Once()
+
+		service := services.NewWarehouseService(repo, localityMocks.NewLocalityRepository(t))
+
+		_, err := service.Update(ctx, expectedWarehouse.Id, warehouse.WarehousePatch{MinimunCapacity: &mincap})
+
+		assert.ErrorIs(t, err, warehouse.ErrWarehouseNotFound)
+	})
+
+	t.Run("update_invalid_code: return the validation error without updating", func(t *testing.T) {
+		ctx := context.Background()
+		invalid := "wh/31"
+
+		repo := mocks.NewWarehouseRepository(t)
+
+		service := services.NewWarehouseService(repo, localityMocks.NewLocalityRepository(t))
+
+		_, err := service.Update(ctx, expectedWarehouse.Id, warehouse.WarehousePatch{WarehouseCode: &invalid})
+
+		assert.ErrorIs(t, err, warehouse.ErrInvalidWarehouseCode)
 	})
 
-	t.Run("get_by_non_existent: Se não for encontrado um warehouse com o ID retornar um error informando", func(t *testing.T) {
+	t.Run("update_legacy_record: patch a warehouse stored before the validation rules", func(t *testing.T) {
 		ctx := context.Background()
 
-		errMsg := fmt.Errorf("erros: no warehouse was found with id %d", expectedWarehouse.Id)
+		legacy := expectedWarehouse
+		legacy.Telephone = "n/a"
+		legacy.WarehouseCode = "WH 31"
+
+		patched := legacy
+		patched.MinimunCapacity = mincap
 
 		repo := mocks.NewWarehouseRepository(t)
+		repo.On("GetById", ctx, expectedWarehouse.Id).Return(legacy, nil).Once()
+		repo.On("Update", ctx, expectedWarehouse.Id, &patched).Return(patched, nil).Once()
 
-		repo.On("Update", ctx, expectedWarehouse.Id, &updateWarehouse).Return(expectedWarehouse, nil)
-		repo.On("GetById", ctx, expectedWarehouse.Id).Return(warehouse.WarehouseModel{}, errMsg)
+		service := services.NewWarehouseService(repo, localityMocks.NewLocalityRepository(t))
 
-		service := services.NewWarehouseService(repo)
+		result, err := service.Update(ctx, expectedWarehouse.Id, warehouse.WarehousePatch{MinimunCapacity: &mincap})
 
-		_, err := service.UpdateTempAndCap(ctx, expectedWarehouse.Id, updateWarehouse.MinimunTemperature, updateWarehouse.MinimunCapacity)
+		assert.NoError(t, err)
+		assert.Equal(t, patched, result)
+	})
 
-		assert.Error(t, err)
+	t.Run("update_locality_not_found: return error when the new locality does not exist", func(t *testing.T) {
+		ctx := context.Background()
+
+		repo := mocks.NewWarehouseRepository(t)
+		localities := localityMocks.NewLocalityRepository(t)
+
+		repo.On("GetById", ctx, expectedWarehouse.Id).Return(expectedWarehouse, nil).Once()
+		localities.On("GetById", ctx, localityId).Return(nil, locality.ErrLocalityNotFound).Once()
+
+		service := services.NewWarehouseService(repo, localities)
+
+		_, err := service.Update(ctx, expectedWarehouse.Id, warehouse.WarehousePatch{LocalityID: &localityId})
+
+		assert.ErrorIs(t, err, locality.ErrLocalityNotFound)
+	})
+
+	t.Run("update_duplicate_code: return ErrWarehouseCodeTaken from the repository", func(t *testing.T) {
+		ctx := context.Background()
+
+		onlyCode := expectedWarehouse
+		onlyCode.WarehouseCode = code
+
+		repo := mocks.NewWarehouseRepository(t)
+		repo.On("GetById", ctx, expectedWarehouse.Id).Return(expectedWarehouse, nil).Once()
+		repo.On("Update", ctx, expectedWarehouse.Id, &onlyCode).Return(warehouse.WarehouseModel{}, warehouse.ErrWarehouseCodeTaken).Once()
+
+		service := services.NewWarehouseService(repo, localityMocks.NewLocalityRepository(t))
+
+		_, err := service.Update(ctx, expectedWarehouse.Id, warehouse.WarehousePatch{WarehouseCode: &code})
+
+		assert.ErrorIs(t, err, warehouse.ErrWarehouseCodeTaken)
 	})
 }
 
@@ -212,7 +327,7 @@ func Test_Service_Delete(t *testing.T) {
 
 		repo.On("Delete", ctx, id).Return(errMsg)
 
-		service := services.NewWarehouseService(repo)
+		service := services.NewWarehouseService(repo, localityMocks.NewLocalityRepository(t))
 
 		err := service.Delete(ctx, id)
 
@@ -228,10 +343,100 @@ func Test_Service_Delete(t *testing.T) {
 
 		repo.On("Delete", ctx, id).Return(nil)
 
-		service := services.NewWarehouseService(repo)
+		service := services.NewWarehouseService(repo, localityMocks.NewLocalityRepository(t))
 
 		err := service.Delete(ctx, id)
 
 		assert.Nil(t, err)
 	})
 }
+
+func Test_Service_GetInventory(t *testing.T) {
+	price := 2.5
+	sellerId := int64(7)
+	products := func() []warehouse.InventoryProduct {
+		return []warehouse.InventoryProduct{
+			{ProductId: 1, ProductTypeId: 1, ProductType: "frozen", SellerId: &sellerId, Seller: "Acme", Batches: 2, Quantity: 10, PurchasePrice: &price},
+			{ProductId: 2, ProductTypeId: 2, ProductType: "fresh", Batches: 1, Quantity: 4},
+			{ProductId: 3, ProductTypeId: 1, ProductType: "frozen", SellerId: &sellerId, Seller: "Acme", Batches: 1, Quantity: 2, PurchasePrice: &price},
+		}
+	}
+
+	t.Run("get_inventory_ok: should value the stock, group it and compute the utilization", func(t *testing.T) {
+		ctx := context.Background()
+		repo := mocks.NewWarehouseRepository(t)
+
+		repo.On("GetById", ctx, int64(1)).Return(expectedWarehouseList[1], nil).Once()
+		repo.On("GetInventoryProducts", ctx, int64(1), mock.AnythingOfType("time.Time")).Return(products(), nil).Once()
+		repo.On("GetExpiringBatches", ctx, int64(1), mock.AnythingOfType("time.Time")).
+			Return([]warehouse.InventoryBatch{{Id: 9, ProductId: 1, CurrentQuantity: 4, DueDate: time.Now().AddDate(0, 0, 3)}}, nil).
+			Once()
+		repo.On("GetSectionUsage", ctx, int64(1)).
+			Return([]warehouse.InventorySection{
+				{SectionId: 1, MaximumCapacity: 10, CurrentCapacity: 5},
+				{SectionId: 2, MaximumCapacity: 20, CurrentCapacity: 5, QuarantinedBatches: 1, QuarantinedQuantity: 2},
+			}, nil).
+			Once()
+
+		service := services.NewWarehouseService(repo, localityMocks.NewLocalityRepository(t))
+		result, err := service.GetInventory(ctx, warehouse.InventoryQuery{WarehouseId: 1, GroupBy: warehouse.GroupBySeller})
+
+		assert.NoError(t, err)
+		assert.Equal(t, warehouse.DefaultInventoryExpiringDays, result.ExpiringDays)
+		assert.Equal(t, int64(16), result.Quantity)
+		assert.Equal(t, 30.0, result.Value)
+		assert.Equal(t, 25.0, result.Products[0].Value)
+		assert.Equal(t, 0.0, result.Products[1].Value)
+		assert.Equal(t, 10.0, result.ExpiringBatches[0].Value)
+		assert.Equal(t, 3, result.ExpiringBatches[0].DaysLeft)
+		assert.Equal(t, 50.0, result.Sections[0].Utilization)
+		assert.Equal(t, 33.33, result.Utilization)
+		assert.Equal(t, int64(2), result.QuarantinedQuantity)
+		assert.Equal(t, []warehouse.InventoryGroup{
+			{Id: 0, Name: "", Products: 1, Batches: 1, Quantity: 4, Value: 0},
+			{Id: 7, Name: "Acme", Products: 2, Batches: 3, Quantity: 12, Value: 30},
+		}, result.Groups)
+	})
+
+	t.Run("get_inventory_by_product_type: should group the products by product type", func(t *testing.T) {
+		ctx := context.Background()
+		repo := mocks.NewWarehouseRepository(t)
+
+		repo.On("GetById", ctx, int64(1)).Return(expectedWarehouseList[1], nil).Once()
+		repo.On("GetInventoryProducts", ctx, int64(1), mock.AnythingOfType("time.Time")).Return(products(), nil).Once()
+		repo.On("GetExpiringBatches", ctx, int64(1), mock.AnythingOfType("time.Time")).Return([]warehouse.InventoryBatch{}, nil).Once()
+		repo.On("GetSectionUsage", ctx, int64(1)).Return([]warehouse.InventorySection{}, nil).Once()
+
+		service := services.NewWarehouseService(repo, localityMocks.NewLocalityRepository(t))
+		result, err := service.GetInventory(ctx, warehouse.InventoryQuery{WarehouseId: 1, GroupBy: warehouse.GroupByProductType, ExpiringDays: 30})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 30, result.ExpiringDays)
+		assert.Equal(t, 0.0, result.Utilization)
+		assert.Len(t, result.Groups, 2)
+		assert.Equal(t, "frozen", result.Groups[0].Name)
+		assert.Equal(t, int64(12), result.Groups[0].Quantity)
+	})
+
+	t.Run("get_inventory_invalid_query: should reject an unknown grouping or expiring days", func(t *testing.T) {
+		ctx := context.Background()
+		service := services.NewWarehouseService(mocks.NewWarehouseRepository(t), localityMocks.NewLocalityRepository(t))
+
+		_, err := service.GetInventory(ctx, warehouse.InventoryQuery{WarehouseId: 1, GroupBy: "locality"})
+		assert.ErrorIs(t, err, warehouse.ErrInvalidInventoryGroup)
+
+		_, err = service.GetInventory(ctx, warehouse.InventoryQuery{WarehouseId: 1, ExpiringDays: 400})
+		assert.ErrorIs(t, err, warehouse.ErrInvalidExpiringDays)
+	})
+
+	t.Run("get_inventory_not_found: should return ErrWarehouseNotFound", func(t *testing.T) {
+		ctx := context.Background()
+		repo := mocks.NewWarehouseRepository(t)
+		repo.On("GetById", ctx, int64(9)).Return(warehouse.WarehouseModel{}, warehouse.ErrWarehouseNotFound).Once()
+
+		service := services.NewWarehouseService(repo, localityMocks.NewLocalityRepository(t))
+		_, err := service.GetInventory(ctx, warehouse.InventoryQuery{WarehouseId: 9})
+
+		assert.ErrorIs(t, err, warehouse.ErrWarehouseNotFound)
+	})
+}
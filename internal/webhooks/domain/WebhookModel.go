@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	DeliveryStatusPending   = "pending"
+	DeliveryStatusDelivered = "delivered"
+	DeliveryStatusDead      = "dead"
+)
+
+type Webhook struct {
+	Id         int64     `json:"id"`
+	Url        string    `json:"url"`
+	Secret     string    `json:"-"`
+	EventTypes []string  `json:"event_types"`
+	Active     bool      `json:"active"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type OutboxEvent struct {
+	Id          int64     `json:"id"`
+	EventType   string    `json:"event_type"`
+	AggregateId int64     `json:"aggregate_id"`
+	Payload     string    `json:"payload"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type Delivery struct {
+	Id             int64     `json:"id"`
+	WebhookId      int64     `json:"webhook_id"`
+	EventId        int64     `json:"event_id"`
+	EventType      string    `json:"event_type"`
+	Status         string    `json:"status"`
+	Attempts       int64     `json:"attempts"`
+	NextAttemptAt  time.Time `json:"next_attempt_at"`
+	LastError      string    `json:"last_error"`
+	LastStatusCode int64     `json:"last_status_code"`
+	Url            string    `json:"url"`
+	Secret         string    `json:"-"`
+	Payload        string    `json:"-"`
+	CreatedAt      time.Time `json:"-"`
+}
+
+type WebhookRepository interface {
+	GetAll(ctx context.Context) ([]Webhook, error)
+	GetById(ctx context.Context, id int64) (*Webhook, error)
+	Create(ctx context.Context, webhook *Webhook) (*Webhook, error)
+	Update(ctx context.Context, webhook *Webhook) (*Webhook, error)
+	Delete(ctx context.Context, id int64) error
+	GetActiveByEventType(ctx context.Context, eventType string) ([]Webhook, error)
+}
+
+type DeliveryRepository interface {
+	GetUndispatchedEvents(ctx context.Context, limit int64) ([]OutboxEvent, error)
+	Enqueue(ctx context.Context, event OutboxEvent, webhooks []Webhook) error
+	GetDue(ctx context.Context, now time.Time, limit int64) ([]Delivery, error)
+	MarkDelivered(ctx context.Context, id int64, statusCode int64) error
+	MarkFailed(ctx context.Context, delivery *Delivery) error
+	GetDeadLetters(ctx context.Context) ([]Delivery, error)
+	Requeue(ctx context.Context, id int64) error
+}
+
+type WebhookService interface {
+	GetAll(ctx context.Context) ([]Webhook, error)
+	GetById(ctx context.Context, id int64) (*Webhook, error)
+	Create(ctx context.Context, url string, secret string, eventTypes []string) (*Webhook, error)
+	Update(ctx context.Context, id int64, url *string, eventTypes *[]string, active *bool) (*Webhook, error)
+	Delete(ctx context.Context, id int64) error
+	GetDeadLetters(ctx context.Context) ([]Delivery, error)
+	RetryDelivery(ctx context.Context, id int64) error
+}
@@ -0,0 +1,12 @@
+package domain
+
+import "errors"
+
+var (
+	ErrWebhookNotFound  = errors.New("webhook not found")
+	ErrDeliveryNotFound = errors.New("dead-lettered delivery not found")
+	ErrInvalidURL       = errors.New("webhook url must be an absolute http or https url")
+	ErrUnknownEventType = errors.New("unknown event type")
+	ErrNoEventTypes     = errors.New("at least one event type is required")
+	ErrNoSecret         = errors.New("webhook secret is required to sign deliveries")
+)
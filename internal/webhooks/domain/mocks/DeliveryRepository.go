@@ -0,0 +1,157 @@
+// Code generated by mockery v2.13.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	domain "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/webhooks/domain"
+
+	time "time"
+)
+
+// DeliveryRepository is an autogenerated mock type for the DeliveryRepository type
+type DeliveryRepository struct {
+	mock.Mock
+}
+
+// Enqueue provides a mock function with given fields: ctx, event, webhooks
+func (_m *DeliveryRepository) Enqueue(ctx context.Context, event domain.OutboxEvent, webhooks []domain.Webhook) error {
+	ret := _m.Called(ctx, event, webhooks)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.OutboxEvent, []domain.Webhook) error); ok {
+		r0 = rf(ctx, event, webhooks)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetDeadLetters provides a mock function with given fields: ctx
+func (_m *DeliveryRepository) GetDeadLetters(ctx context.Context) ([]domain.Delivery, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []domain.Delivery
+	if rf, ok := ret.Get(0).(func(context.Context) []domain.Delivery); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Delivery)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDue provides a mock function with given fields: ctx, now, limit
+func (_m *DeliveryRepository) GetDue(ctx context.Context, now time.Time, limit int64) ([]domain.Delivery, error) {
+	ret := _m.Called(ctx, now, limit)
+
+	var r0 []domain.Delivery
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int64) []domain.Delivery); ok {
+		r0 = rf(ctx, now, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Delivery)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, int64) error); ok {
+		r1 = rf(ctx, now, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUndispatchedEvents provides a mock function with given fields: ctx, limit
+func (_m *DeliveryRepository) GetUndispatchedEvents(ctx context.Context, limit int64) ([]domain.OutboxEvent, error) {
+	ret := _m.Called(ctx, limit)
+
+	var r0 []domain.OutboxEvent
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []domain.OutboxEvent); ok {
+		r0 = rf(ctx, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.OutboxEvent)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MarkDelivered provides a mock function with given fields: ctx, id, statusCode
+func (_m *DeliveryRepository) MarkDelivered(ctx context.Context, id int64, statusCode int64) error {
+	ret := _m.Called(ctx, id, statusCode)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) error); ok {
+		r0 = rf(ctx, id, statusCode)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MarkFailed provides a mock function with given fields: ctx, delivery
+func (_m *DeliveryRepository) MarkFailed(ctx context.Context, delivery *domain.Delivery) error {
+	ret := _m.Called(ctx, delivery)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Delivery) error); ok {
+		r0 = rf(ctx, delivery)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Requeue provides a mock function with given fields: ctx, id
+func (_m *DeliveryRepository) Requeue(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewDeliveryRepository interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewDeliveryRepository creates a new instance of DeliveryRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewDeliveryRepository(t mockConstructorTestingTNewDeliveryRepository) *DeliveryRepository {
+	mock := &DeliveryRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,159 @@
+// Code generated by mockery v2.13.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	domain "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/webhooks/domain"
+)
+
+// WebhookRepository is an autogenerated mock type for the WebhookRepository type
+type WebhookRepository struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: ctx, webhook
+func (_m *WebhookRepository) Create(ctx context.Context, webhook *domain.Webhook) (*domain.Webhook, error) {
+	ret := _m.Called(ctx, webhook)
+
+	var r0 *domain.Webhook
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Webhook) *domain.Webhook); ok {
+		r0 = rf(ctx, webhook)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Webhook)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.Webhook) error); ok {
+		r1 = rf(ctx, webhook)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Delete provides a mock function with given fields: ctx, id
+func (_m *WebhookRepository) Delete(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetActiveByEventType provides a mock function with given fields: ctx, eventType
+func (_m *WebhookRepository) GetActiveByEventType(ctx context.Context, eventType string) ([]domain.Webhook, error) {
+	ret := _m.Called(ctx, eventType)
+
+	var r0 []domain.Webhook
+	if rf, ok := ret.Get(0).(func(context.Context, string) []domain.Webhook); ok {
+		r0 = rf(ctx, eventType)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Webhook)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, eventType)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAll provides a mock function with given fields: ctx
+func (_m *WebhookRepository) GetAll(ctx context.Context) ([]domain.Webhook, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []domain.Webhook
+	if rf, ok := ret.Get(0).(func(context.Context) []domain.Webhook); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Webhook)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetById provides a mock function with given fields: ctx, id
+func (_m *WebhookRepository) GetById(ctx context.Context, id int64) (*domain.Webhook, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *domain.Webhook
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *domain.Webhook); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Webhook)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: ctx, webhook
+func (_m *WebhookRepository) Update(ctx context.Context, webhook *domain.Webhook) (*domain.Webhook, error) {
+	ret := _m.Called(ctx, webhook)
+
+	var r0 *domain.Webhook
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Webhook) *domain.Webhook); ok {
+		r0 = rf(ctx, webhook)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Webhook)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.Webhook) error); ok {
+		r1 = rf(ctx, webhook)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewWebhookRepository interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewWebhookRepository creates a new instance of WebhookRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewWebhookRepository(t mockConstructorTestingTNewWebhookRepository) *WebhookRepository {
+	mock := &WebhookRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
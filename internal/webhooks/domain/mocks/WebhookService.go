@@ -0,0 +1,173 @@
+// Code generated by mockery v2.13.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	domain "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/webhooks/domain"
+)
+
+// WebhookService is an autogenerated mock type for the WebhookService type
+type WebhookService struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: ctx, url, secret, eventTypes
+func (_m *WebhookService) Create(ctx context.Context, url string, secret string, eventTypes []string) (*domain.Webhook, error) {
+	ret := _m.Called(ctx, url, secret, eventTypes)
+
+	var r0 *domain.Webhook
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, []string) *domain.Webhook); ok {
+		r0 = rf(ctx, url, secret, eventTypes)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Webhook)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, []string) error); ok {
+		r1 = rf(ctx, url, secret, eventTypes)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Delete provides a mock function with given fields: ctx, id
+func (_m *WebhookService) Delete(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetAll provides a mock function with given fields: ctx
+func (_m *WebhookService) GetAll(ctx context.Context) ([]domain.Webhook, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []domain.Webhook
+	if rf, ok := ret.Get(0).(func(context.Context) []domain.Webhook); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Webhook)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetById provides a mock function with given fields: ctx, id
+func (_m *WebhookService) GetById(ctx context.Context, id int64) (*domain.Webhook, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *domain.Webhook
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *domain.Webhook); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Webhook)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDeadLetters provides a mock function with given fields: ctx
+func (_m *WebhookService) GetDeadLetters(ctx context.Context) ([]domain.Delivery, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []domain.Delivery
+	if rf, ok := ret.Get(0).(func(context.Context) []domain.Delivery); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Delivery)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RetryDelivery provides a mock function with given fields: ctx, id
+func (_m *WebhookService) RetryDelivery(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Update provides a mock function with given fields: ctx, id, url, eventTypes, active
+func (_m *WebhookService) Update(ctx context.Context, id int64, url *string, eventTypes *[]string, active *bool) (*domain.Webhook, error) {
+	ret := _m.Called(ctx, id, url, eventTypes, active)
+
+	var r0 *domain.Webhook
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *string, *[]string, *bool) *domain.Webhook); ok {
+		r0 = rf(ctx, id, url, eventTypes, active)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Webhook)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, *string, *[]string, *bool) error); ok {
+		r1 = rf(ctx, id, url, eventTypes, active)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewWebhookService interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewWebhookService creates a new instance of WebhookService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewWebhookService(t mockConstructorTestingTNewWebhookService) *WebhookService {
+	mock := &WebhookService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
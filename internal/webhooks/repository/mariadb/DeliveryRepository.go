@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/webhooks/domain"
+)
+
+type mariaDbDeliveryRepository struct {
+	db *sql.DB
+}
+
+func NewMariadbDeliveryRepository(db *sql.DB) domain.DeliveryRepository {
+	return &mariaDbDeliveryRepository{db: db}
+}
+
+func (m *mariaDbDeliveryRepository) GetUndispatchedEvents(ctx context.Context, limit int64) ([]domain.OutboxEvent, error) {
+	events := []domain.OutboxEvent{}
+
+	rows, err := m.db.QueryContext(ctx, SQLGetUndispatchedEvents, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var event domain.OutboxEvent
+
+		err := rows.Scan(
+			&event.Id,
+			&event.EventType,
+			&event.AggregateId,
+			&event.Payload,
+			&event.CreatedAt,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+// Enqueue creates one pending delivery per subscribed webhook and marks the
+// event as dispatched, so an event is fanned out exactly once.
+func (m *mariaDbDeliveryRepository) Enqueue(ctx context.Context, event domain.OutboxEvent, webhooks []domain.Webhook) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	for _, webhook := range webhooks {
+		if _, err := tx.ExecContext(ctx, SQLCreateDelivery, webhook.Id, event.Id, now); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, SQLMarkEventDispatched, now, event.Id); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (m *mariaDbDeliveryRepository) GetDue(ctx context.Context, now time.Time, limit int64) ([]domain.Delivery, error) {
+	return m.query(ctx, SQLGetDueDeliveries, now, limit)
+}
+
+func (m *mariaDbDeliveryRepository) GetDeadLetters(ctx context.Context) ([]domain.Delivery, error) {
+	return m.query(ctx, SQLGetDeadLetters)
+}
+
+func (m *mariaDbDeliveryRepository) MarkDelivered(ctx context.Context, id int64, statusCode int64) error {
+	_, err := m.db.ExecContext(ctx, SQLMarkDeliveryDelivered, statusCode, time.Now(), id)
+	return err
+}
+
+func (m *mariaDbDeliveryRepository) MarkFailed(ctx context.Context, delivery *domain.Delivery) error {
+	_, err := m.db.ExecContext(
+		ctx,
+		SQLMarkDeliveryFailed,
+		delivery.Status,
+		delivery.Attempts,
+		delivery.NextAttemptAt,
+		delivery.LastError,
+		delivery.LastStatusCode,
+		delivery.Id,
+	)
+	return err
+}
+
+func (m *mariaDbDeliveryRepository) Requeue(ctx context.Context, id int64) error {
+	result, err := m.db.ExecContext(ctx, SQLRequeueDelivery, time.Now(), id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+
+	if rowsAffected == 0 {
+		return domain.ErrDeliveryNotFound
+	}
+
+	return nil
+}
+
+func (m *mariaDbDeliveryRepository) query(ctx context.Context, query string, args ...interface{}) ([]domain.Delivery, error) {
+	deliveries := []domain.Delivery{}
+
+	rows, err := m.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var delivery domain.Delivery
+
+		err := rows.Scan(
+			&delivery.Id,
+			&delivery.WebhookId,
+			&delivery.EventId,
+			&delivery.EventType,
+			&delivery.Status,
+			&delivery.Attempts,
+			&delivery.NextAttemptAt,
+			&delivery.LastError,
+			&delivery.LastStatusCode,
+			&delivery.Url,
+			&delivery.Secret,
+			&delivery.Payload,
+			&delivery.CreatedAt,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, nil
+}
@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/webhooks/domain"
+)
+
+type mariaDbWebhookRepository struct {
+	db *sql.DB
+}
+
+func NewMariadbWebhookRepository(db *sql.DB) domain.WebhookRepository {
+	return &mariaDbWebhookRepository{db: db}
+}
+
+func (m *mariaDbWebhookRepository) GetAll(ctx context.Context) ([]domain.Webhook, error) {
+	return m.query(ctx, SQLGetAllWebhooks)
+}
+
+func (m *mariaDbWebhookRepository) GetActiveByEventType(ctx context.Context, eventType string) ([]domain.Webhook, error) {
+	return m.query(ctx, SQLGetActiveWebhooksByEventType, eventType)
+}
+
+func (m *mariaDbWebhookRepository) GetById(ctx context.Context, id int64) (*domain.Webhook, error) {
+	row := m.db.QueryRowContext(ctx, SQLGetWebhookById, id)
+
+	var webhook domain.Webhook
+	var eventTypes string
+
+	err := row.Scan(
+		&webhook.Id,
+		&webhook.Url,
+		&webhook.Secret,
+		&eventTypes,
+		&webhook.Active,
+		&webhook.CreatedAt,
+	)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrWebhookNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	webhook.EventTypes = strings.Split(eventTypes, ",")
+
+	return &webhook, nil
+}
+
+func (m *mariaDbWebhookRepository) Create(ctx context.Context, webhook *domain.Webhook) (*domain.Webhook, error) {
+	webhook.CreatedAt = time.Now()
+
+	result, err := m.db.ExecContext(
+		ctx,
+		SQLCreateWebhook,
+		webhook.Url,
+		webhook.Secret,
+		strings.Join(webhook.EventTypes, ","),
+		webhook.Active,
+		webhook.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	lastId, _ := result.LastInsertId()
+
+	webhook.Id = lastId
+
+	return webhook, nil
+}
+
+func (m *mariaDbWebhookRepository) Update(ctx context.Context, webhook *domain.Webhook) (*domain.Webhook, error) {
+	_, err := m.db.ExecContext(
+		ctx,
+		SQLUpdateWebhook,
+		webhook.Url,
+		strings.Join(webhook.EventTypes, ","),
+		webhook.Active,
+		webhook.Id,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return webhook, nil
+}
+
+func (m *mariaDbWebhookRepository) Delete(ctx context.Context, id int64) error {
+	result, err := m.db.ExecContext(ctx, SQLDeleteWebhook, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+
+	if rowsAffected == 0 {
+		return domain.ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+func (m *mariaDbWebhookRepository) query(ctx context.Context, query string, args ...interface{}) ([]domain.Webhook, error) {
+	webhooks := []domain.Webhook{}
+
+	rows, err := m.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var webhook domain.Webhook
+		var eventTypes string
+
+		err := rows.Scan(
+			&webhook.Id,
+			&webhook.Url,
+			&webhook.Secret,
+			&eventTypes,
+			&webhook.Active,
+			&webhook.CreatedAt,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+
+		webhook.EventTypes = strings.Split(eventTypes, ",")
+		webhooks = append(webhooks, webhook)
+	}
+
+	return webhooks, nil
+}
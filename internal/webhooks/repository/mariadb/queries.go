@@ -0,0 +1,93 @@
+package repository
+
+const (
+	SQLGetAllWebhooks = `
+    SELECT id, url, secret, event_types, active, created_at
+    FROM webhooks
+    `
+
+	SQLGetWebhookById = `
+    SELECT id, url, secret, event_types, active, created_at
+    FROM webhooks
+    WHERE id=?
+    `
+
+	SQLGetActiveWebhooksByEventType = `
+    SELECT id, url, secret, event_types, active, created_at
+    FROM webhooks
+    WHERE active = 1 AND FIND_IN_SET(?, event_types) > 0
+    `
+
+	SQLCreateWebhook = `
+    INSERT INTO
+    webhooks (url, secret, event_types, active, created_at)
+    VALUES (?, ?, ?, ?, ?)
+    `
+
+	SQLUpdateWebhook = `
+    UPDATE webhooks
+    SET url=?, event_types=?, active=?
+    WHERE id=?
+    `
+
+	SQLDeleteWebhook = "DELETE FROM webhooks WHERE id=?"
+
+	SQLGetUndispatchedEvents = `
+    SELECT id, event_type, aggregate_id, payload, created_at
+    FROM outbox_events
+    WHERE dispatched_at IS NULL
+    ORDER BY id
+    LIMIT ?
+    `
+
+	SQLCreateDelivery = `
+    INSERT INTO
+    webhook_deliveries (webhook_id, outbox_event_id, status, attempts, next_attempt_at)
+    VALUES (?, ?, 'pending', 0, ?)
+    `
+
+	SQLMarkEventDispatched = `
+    UPDATE outbox_events
+    SET dispatched_at=?
+    WHERE id=?
+    `
+
+	SQLGetDueDeliveries = `
+    SELECT d.id, d.webhook_id, d.outbox_event_id, e.event_type, d.status, d.attempts,
+    d.next_attempt_at, d.last_error, d.last_status_code, w.url, w.secret, e.payload, e.created_at
+    FROM webhook_deliveries d
+    INNER JOIN webhooks w ON w.id = d.webhook_id
+    INNER JOIN outbox_events e ON e.id = d.outbox_event_id
+    WHERE d.status = 'pending' AND d.next_attempt_at <= ?
+    ORDER BY d.next_attempt_at
+    LIMIT ?
+    `
+
+	SQLMarkDeliveryDelivered = `
+    UPDATE webhook_deliveries
+    SET status='delivered', attempts=attempts + 1, last_status_code=?, last_error='', delivered_at=?
+    WHERE id=?
+    `
+
+	SQLMarkDeliveryFailed = `
+    UPDATE webhook_deliveries
+    SET status=?, attempts=?, next_attempt_at=?, last_error=?, last_status_code=?
+    WHERE id=?
+    `
+
+	SQLGetDeadLetters = `
+    SELECT d.id, d.webhook_id, d.outbox_event_id, e.event_type, d.status, d.attempts,
+    d.next_attempt_at, d.last_error, d.last_status_code, w.url, w.secret, e.payload, e.created_at
+    FROM webhook_deliveries d
+    INNER JOIN webhooks w ON w.id = d.webhook_id
+    INNER JOIN outbox_events e ON e.id = d.outbox_event_id
+    WHERE d.status = 'dead'
+    ORDER BY d.id DESC
+    `
+
+	SQLRequeueDelivery = `
+    UPDATE webhook_deliveries
+    SET status='pending', attempts=0, next_attempt_at=?
+    WHERE id=? AND status='dead'
+    `
+)
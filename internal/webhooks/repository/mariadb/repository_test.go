@@ -0,0 +1,533 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/webhooks/domain"
+	repository "github.com/vinigracindo/mercado-fresco-stranger-strings/internal/webhooks/repository/mariadb"
+)
+
+var now = time.Now()
+
+var mockWebhook = domain.Webhook{
+	Id:         1,
+	Url:        "https://erp.example.com/hooks",
+	Secret:     "s3cr3t",
+	EventTypes: []string{"purchase_order.created", "inbound_order.created"},
+	Active:     true,
+	CreatedAt:  now,
+}
+
+var webhookColumns = []string{"id", "url", "secret", "event_types", "active", "created_at"}
+
+var deliveryColumns = []string{
+	"id", "webhook_id", "outbox_event_id", "event_type", "status", "attempts",
+	"next_attempt_at", "last_error", "last_status_code", "url", "secret", "payload", "created_at",
+}
+
+var mockDelivery = domain.Delivery{
+	Id:            1,
+	WebhookId:     1,
+	EventId:       1,
+	EventType:     "purchase_order.created",
+	Status:        domain.DeliveryStatusPending,
+	NextAttemptAt: now,
+	Url:           "https://erp.example.com/hooks",
+	Secret:        "s3cr3t",
+	Payload:       `{"id":1}`,
+	CreatedAt:     now,
+}
+
+func deliveryRow(delivery domain.Delivery) *sqlmock.Rows {
+	return sqlmock.NewRows(deliveryColumns).AddRow(
+		delivery.Id,
+		delivery.WebhookId,
+		delivery.EventId,
+		delivery.EventType,
+		delivery.Status,
+		delivery.Attempts,
+		delivery.NextAttemptAt,
+		delivery.LastError,
+		delivery.LastStatusCode,
+		delivery.Url,
+		delivery.Secret,
+		delivery.Payload,
+		delivery.CreatedAt,
+	)
+}
+
+func TestWebhookRepository_GetAll(t *testing.T) {
+	t.Run("get_all_ok: should return all webhooks", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		rows := sqlmock.NewRows(webhookColumns).
+			AddRow(1, mockWebhook.Url, mockWebhook.Secret, "purchase_order.created,inbound_order.created", true, now)
+
+		mock.ExpectQuery(regexp.QuoteMeta(repository.SQLGetAllWebhooks)).WillReturnRows(rows)
+
+		webhookRepository := repository.NewMariadbWebhookRepository(db)
+
+		result, err := webhookRepository.GetAll(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.Webhook{mockWebhook}, result)
+	})
+
+	t.Run("get_all_scan_error: should return error when scan fail", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		rows := sqlmock.NewRows(webhookColumns).AddRow("", "", "", "", "", "")
+
+		mock.ExpectQuery(regexp.QuoteMeta(repository.SQLGetAllWebhooks)).WillReturnRows(rows)
+
+		webhookRepository := repository.NewMariadbWebhookRepository(db)
+
+		_, err = webhookRepository.GetAll(context.Background())
+
+		assert.Error(t, err)
+	})
+
+	t.Run("get_all_query_error: should return error when query fails", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta(repository.SQLGetAllWebhooks)).WillReturnError(errors.New("any error"))
+
+		webhookRepository := repository.NewMariadbWebhookRepository(db)
+
+		_, err = webhookRepository.GetAll(context.Background())
+
+		assert.Error(t, err)
+	})
+}
+
+func TestWebhookRepository_GetActiveByEventType(t *testing.T) {
+	t.Run("get_active_ok: should return the webhooks subscribed to the event", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		rows := sqlmock.NewRows(webhookColumns).
+			AddRow(1, mockWebhook.Url, mockWebhook.Secret, "purchase_order.created,inbound_order.created", true, now)
+
+		mock.ExpectQuery(regexp.QuoteMeta(repository.SQLGetActiveWebhooksByEventType)).
+			WithArgs("purchase_order.created").
+			WillReturnRows(rows)
+
+		webhookRepository := repository.NewMariadbWebhookRepository(db)
+
+		result, err := webhookRepository.GetActiveByEventType(context.Background(), "purchase_order.created")
+
+		assert.NoError(t, err)
+		assert.Len(t, result, 1)
+	})
+}
+
+func TestWebhookRepository_GetById(t *testing.T) {
+	t.Run("get_by_id_ok: should return webhook by id", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		row := sqlmock.NewRows(webhookColumns).
+			AddRow(1, mockWebhook.Url, mockWebhook.Secret, "purchase_order.created,inbound_order.created", true, now)
+
+		mock.ExpectQuery(regexp.QuoteMeta(repository.SQLGetWebhookById)).WithArgs(1).WillReturnRows(row)
+
+		webhookRepository := repository.NewMariadbWebhookRepository(db)
+
+		result, err := webhookRepository.GetById(context.Background(), 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &mockWebhook, result)
+	})
+
+	t.Run("get_by_id_not_found: should return error when webhook not found", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta(repository.SQLGetWebhookById)).WillReturnError(sql.ErrNoRows)
+
+		webhookRepository := repository.NewMariadbWebhookRepository(db)
+
+		_, err = webhookRepository.GetById(context.Background(), 1)
+
+		assert.ErrorIs(t, err, domain.ErrWebhookNotFound)
+	})
+
+	t.Run("get_by_id_scan_error: should return error when scan fail", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		row := sqlmock.NewRows(webhookColumns).AddRow("", "", "", "", "", "")
+
+		mock.ExpectQuery(regexp.QuoteMeta(repository.SQLGetWebhookById)).WillReturnRows(row)
+
+		webhookRepository := repository.NewMariadbWebhookRepository(db)
+
+		_, err = webhookRepository.GetById(context.Background(), 1)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestWebhookRepository_Create(t *testing.T) {
+	t.Run("create_ok: should create webhook", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(repository.SQLCreateWebhook)).
+			WithArgs(mockWebhook.Url, mockWebhook.Secret, "purchase_order.created,inbound_order.created", true, sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		webhookRepository := repository.NewMariadbWebhookRepository(db)
+
+		webhook := mockWebhook
+		webhook.Id = 0
+
+		result, err := webhookRepository.Create(context.Background(), &webhook)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), result.Id)
+	})
+
+	t.Run("create_error: should return error when query execution fails", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(repository.SQLCreateWebhook)).WillReturnError(errors.New("any error"))
+
+		webhookRepository := repository.NewMariadbWebhookRepository(db)
+
+		webhook := mockWebhook
+
+		_, err = webhookRepository.Create(context.Background(), &webhook)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestWebhookRepository_Update(t *testing.T) {
+	t.Run("update_ok: should update webhook", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(repository.SQLUpdateWebhook)).
+			WithArgs(mockWebhook.Url, "purchase_order.created,inbound_order.created", true, mockWebhook.Id).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		webhookRepository := repository.NewMariadbWebhookRepository(db)
+
+		webhook := mockWebhook
+
+		result, err := webhookRepository.Update(context.Background(), &webhook)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &mockWebhook, result)
+	})
+
+	t.Run("update_error: should return error when query execution fails", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(repository.SQLUpdateWebhook)).WillReturnError(errors.New("any error"))
+
+		webhookRepository := repository.NewMariadbWebhookRepository(db)
+
+		webhook := mockWebhook
+
+		_, err = webhookRepository.Update(context.Background(), &webhook)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestWebhookRepository_Delete(t *testing.T) {
+	t.Run("delete_ok: should delete webhook", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(repository.SQLDeleteWebhook)).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		webhookRepository := repository.NewMariadbWebhookRepository(db)
+
+		err = webhookRepository.Delete(context.Background(), 1)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("delete_not_found: should return error when webhook not found", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(repository.SQLDeleteWebhook)).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		webhookRepository := repository.NewMariadbWebhookRepository(db)
+
+		err = webhookRepository.Delete(context.Background(), 1)
+
+		assert.ErrorIs(t, err, domain.ErrWebhookNotFound)
+	})
+
+	t.Run("delete_error: should return error when query execution fails", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(repository.SQLDeleteWebhook)).WithArgs(1).WillReturnError(errors.New("any error"))
+
+		webhookRepository := repository.NewMariadbWebhookRepository(db)
+
+		err = webhookRepository.Delete(context.Background(), 1)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestDeliveryRepository_GetUndispatchedEvents(t *testing.T) {
+	t.Run("get_undispatched_ok: should return the events not yet fanned out", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		rows := sqlmock.NewRows([]string{"id", "event_type", "aggregate_id", "payload", "created_at"}).
+			AddRow(1, "purchase_order.created", 10, `{"id":10}`, now)
+
+		mock.ExpectQuery(regexp.QuoteMeta(repository.SQLGetUndispatchedEvents)).WithArgs(100).WillReturnRows(rows)
+
+		deliveryRepository := repository.NewMariadbDeliveryRepository(db)
+
+		result, err := deliveryRepository.GetUndispatchedEvents(context.Background(), 100)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.OutboxEvent{
+			{Id: 1, EventType: "purchase_order.created", AggregateId: 10, Payload: `{"id":10}`, CreatedAt: now},
+		}, result)
+	})
+
+	t.Run("get_undispatched_error: should return error when query fails", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta(repository.SQLGetUndispatchedEvents)).WillReturnError(errors.New("any error"))
+
+		deliveryRepository := repository.NewMariadbDeliveryRepository(db)
+
+		_, err = deliveryRepository.GetUndispatchedEvents(context.Background(), 100)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestDeliveryRepository_Enqueue(t *testing.T) {
+	event := domain.OutboxEvent{Id: 7, EventType: "purchase_order.created"}
+	webhooks := []domain.Webhook{{Id: 1}, {Id: 2}}
+
+	t.Run("enqueue_ok: should create a delivery per webhook and mark the event dispatched", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(repository.SQLCreateDelivery)).
+			WithArgs(1, 7, sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(regexp.QuoteMeta(repository.SQLCreateDelivery)).
+			WithArgs(2, 7, sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(2, 1))
+		mock.ExpectExec(regexp.QuoteMeta(repository.SQLMarkEventDispatched)).
+			WithArgs(sqlmock.AnyArg(), 7).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		deliveryRepository := repository.NewMariadbDeliveryRepository(db)
+
+		err = deliveryRepository.Enqueue(context.Background(), event, webhooks)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("enqueue_error: should rollback when a delivery cannot be created", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(repository.SQLCreateDelivery)).WillReturnError(errors.New("any error"))
+		mock.ExpectRollback()
+
+		deliveryRepository := repository.NewMariadbDeliveryRepository(db)
+
+		err = deliveryRepository.Enqueue(context.Background(), event, webhooks)
+
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestDeliveryRepository_GetDue(t *testing.T) {
+	t.Run("get_due_ok: should return pending deliveries", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta(repository.SQLGetDueDeliveries)).
+			WithArgs(now, 100).
+			WillReturnRows(deliveryRow(mockDelivery))
+
+		deliveryRepository := repository.NewMariadbDeliveryRepository(db)
+
+		result, err := deliveryRepository.GetDue(context.Background(), now, 100)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.Delivery{mockDelivery}, result)
+	})
+
+	t.Run("get_due_scan_error: should return error when scan fail", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		rows := sqlmock.NewRows(deliveryColumns).AddRow("", "", "", "", "", "", "", "", "", "", "", "", "")
+
+		mock.ExpectQuery(regexp.QuoteMeta(repository.SQLGetDueDeliveries)).WillReturnRows(rows)
+
+		deliveryRepository := repository.NewMariadbDeliveryRepository(db)
+
+		_, err = deliveryRepository.GetDue(context.Background(), now, 100)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestDeliveryRepository_GetDeadLetters(t *testing.T) {
+	t.Run("get_dead_letters_ok: should return dead deliveries", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		dead := mockDelivery
+		dead.Status = domain.DeliveryStatusDead
+		dead.Attempts = 8
+
+		mock.ExpectQuery(regexp.QuoteMeta(repository.SQLGetDeadLetters)).WillReturnRows(deliveryRow(dead))
+
+		deliveryRepository := repository.NewMariadbDeliveryRepository(db)
+
+		result, err := deliveryRepository.GetDeadLetters(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.Delivery{dead}, result)
+	})
+
+	t.Run("get_dead_letters_error: should return error when query fails", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta(repository.SQLGetDeadLetters)).WillReturnError(errors.New("any error"))
+
+		deliveryRepository := repository.NewMariadbDeliveryRepository(db)
+
+		_, err = deliveryRepository.GetDeadLetters(context.Background())
+
+		assert.Error(t, err)
+	})
+}
+
+func TestDeliveryRepository_MarkDelivered(t *testing.T) {
+	t.Run("mark_delivered_ok: should mark the delivery as delivered", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(repository.SQLMarkDeliveryDelivered)).
+			WithArgs(200, sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		deliveryRepository := repository.NewMariadbDeliveryRepository(db)
+
+		err = deliveryRepository.MarkDelivered(context.Background(), 1, 200)
+
+		assert.NoError(t, err)
+	})
+}
+
+func TestDeliveryRepository_MarkFailed(t *testing.T) {
+	t.Run("mark_failed_ok: should persist the failed attempt", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		failed := mockDelivery
+		failed.Attempts = 1
+		failed.LastError = "webhook responded with status 500"
+		failed.LastStatusCode = 500
+
+		mock.ExpectExec(regexp.QuoteMeta(repository.SQLMarkDeliveryFailed)).
+			WithArgs(failed.Status, failed.Attempts, failed.NextAttemptAt, failed.LastError, failed.LastStatusCode, failed.Id).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		deliveryRepository := repository.NewMariadbDeliveryRepository(db)
+
+		err = deliveryRepository.MarkFailed(context.Background(), &failed)
+
+		assert.NoError(t, err)
+	})
+}
+
+func TestDeliveryRepository_Requeue(t *testing.T) {
+	t.Run("requeue_ok: should put the dead delivery back in the queue", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(repository.SQLRequeueDelivery)).
+			WithArgs(sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		deliveryRepository := repository.NewMariadbDeliveryRepository(db)
+
+		err = deliveryRepository.Requeue(context.Background(), 1)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("requeue_not_found: should return error when the delivery is not dead-lettered", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(repository.SQLRequeueDelivery)).
+			WithArgs(sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		deliveryRepository := repository.NewMariadbDeliveryRepository(db)
+
+		err = deliveryRepository.Requeue(context.Background(), 1)
+
+		assert.ErrorIs(t, err, domain.ErrDeliveryNotFound)
+	})
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/webhooks/domain"
+)
+
+const (
+	MaxDeliveryAttempts = 8
+	BaseRetryDelay      = 30 * time.Second
+	MaxRetryDelay       = 6 * time.Hour
+
+	SignatureHeader = "X-Webhook-Signature"
+	EventHeader     = "X-Webhook-Event"
+	DeliveryHeader  = "X-Webhook-Delivery"
+
+	dispatchBatchSize = 100
+	lastErrorMaxSize  = 255
+)
+
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+type webhookPayload struct {
+	Id        int64           `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// Dispatcher fans outbox events out to the subscribed webhooks and delivers
+// them, retrying failures with exponential backoff until they are dead-lettered.
+// Dispatch is run periodically by the job scheduler.
+type Dispatcher struct {
+	repository         domain.WebhookRepository
+	deliveryRepository domain.DeliveryRepository
+	client             HTTPClient
+}
+
+func NewDispatcher(r domain.WebhookRepository, rd domain.DeliveryRepository, client HTTPClient) *Dispatcher {
+	return &Dispatcher{
+		repository:         r,
+		deliveryRepository: rd,
+		client:             client,
+	}
+}
+
+func (d *Dispatcher) Dispatch(ctx context.Context) error {
+	if err := d.FanOut(ctx); err != nil {
+		return err
+	}
+
+	return d.Deliver(ctx)
+}
+
+func (d *Dispatcher) FanOut(ctx context.Context) error {
+	events, err := d.deliveryRepository.GetUndispatchedEvents(ctx, dispatchBatchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, event := range events {
+		webhooks, err := d.repository.GetActiveByEventType(ctx, event.EventType)
+		if err != nil {
+			return err
+		}
+
+		if err := d.deliveryRepository.Enqueue(ctx, event, webhooks); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *Dispatcher) Deliver(ctx context.Context) error {
+	deliveries, err := d.deliveryRepository.GetDue(ctx, time.Now(), dispatchBatchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, delivery := range deliveries {
+		if err := d.deliver(ctx, delivery); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, delivery domain.Delivery) error {
+	body, err := json.Marshal(webhookPayload{
+		Id:        delivery.EventId,
+		Type:      delivery.EventType,
+		CreatedAt: delivery.CreatedAt,
+		Data:      json.RawMessage(delivery.Payload),
+	})
+	if err != nil {
+		return err
+	}
+
+	statusCode, err := d.send(ctx, delivery, body)
+	if err == nil {
+		return d.deliveryRepository.MarkDelivered(ctx, delivery.Id, statusCode)
+	}
+
+	delivery.Attempts++
+	delivery.LastStatusCode = statusCode
+	delivery.LastError = err.Error()
+	if len(delivery.LastError) > lastErrorMaxSize {
+		delivery.LastError = delivery.LastError[:lastErrorMaxSize]
+	}
+
+	if delivery.Attempts >= MaxDeliveryAttempts {
+		delivery.Status = domain.DeliveryStatusDead
+	} else {
+		delivery.NextAttemptAt = time.Now().Add(Backoff(delivery.Attempts))
+	}
+
+	return d.deliveryRepository.MarkFailed(ctx, &delivery)
+}
+
+func (d *Dispatcher) send(ctx context.Context, delivery domain.Delivery, body []byte) (int64, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(EventHeader, delivery.EventType)
+	request.Header.Set(DeliveryHeader, strconv.FormatInt(delivery.Id, 10))
+	request.Header.Set(SignatureHeader, Sign(delivery.Secret, body))
+
+	response, err := d.client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return int64(response.StatusCode), fmt.Errorf("webhook responded with status %d", response.StatusCode)
+	}
+
+	return int64(response.StatusCode), nil
+}
+
+// Backoff returns the delay before the next attempt after the given number of failed attempts.
+func Backoff(attempts int64) time.Duration {
+	delay := BaseRetryDelay
+	for i := int64(1); i < attempts; i++ {
+		delay *= 2
+		if delay >= MaxRetryDelay {
+			return MaxRetryDelay
+		}
+	}
+
+	return delay
+}
+
+// Sign computes the HMAC-SHA256 signature receivers use to authenticate a delivery.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
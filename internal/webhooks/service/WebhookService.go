@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/webhooks/domain"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/libs/outbox"
+)
+
+type service struct {
+	repository         domain.WebhookRepository
+	deliveryRepository domain.DeliveryRepository
+}
+
+func NewWebhookService(r domain.WebhookRepository, rd domain.DeliveryRepository) domain.WebhookService {
+	return &service{
+		repository:         r,
+		deliveryRepository: rd,
+	}
+}
+
+func (s *service) GetAll(ctx context.Context) ([]domain.Webhook, error) {
+	webhooks, err := s.repository.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+func (s *service) GetById(ctx context.Context, id int64) (*domain.Webhook, error) {
+	webhook, err := s.repository.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return webhook, nil
+}
+
+func (s *service) Create(ctx context.Context, url string, secret string, eventTypes []string) (*domain.Webhook, error) {
+	webhook := domain.Webhook{
+		Url:        url,
+		Secret:     secret,
+		EventTypes: eventTypes,
+		Active:     true,
+	}
+
+	if err := validate(&webhook); err != nil {
+		return nil, err
+	}
+
+	newWebhook, err := s.repository.Create(ctx, &webhook)
+	if err != nil {
+		return nil, err
+	}
+
+	return newWebhook, nil
+}
+
+func (s *service) Update(ctx context.Context, id int64, url *string, eventTypes *[]string, active *bool) (*domain.Webhook, error) {
+	webhook, err := s.repository.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if url != nil {
+		webhook.Url = *url
+	}
+
+	if eventTypes != nil {
+		webhook.EventTypes = *eventTypes
+	}
+
+	if active != nil {
+		webhook.Active = *active
+	}
+
+	if err := validate(webhook); err != nil {
+		return nil, err
+	}
+
+	webhookUpdated, err := s.repository.Update(ctx, webhook)
+	if err != nil {
+		return nil, err
+	}
+
+	return webhookUpdated, nil
+}
+
+func (s *service) Delete(ctx context.Context, id int64) error {
+	return s.repository.Delete(ctx, id)
+}
+
+func (s *service) GetDeadLetters(ctx context.Context) ([]domain.Delivery, error) {
+	deliveries, err := s.deliveryRepository.GetDeadLetters(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+func (s *service) RetryDelivery(ctx context.Context, id int64) error {
+	return s.deliveryRepository.Requeue(ctx, id)
+}
+
+func validate(webhook *domain.Webhook) error {
+	parsed, err := url.Parse(webhook.Url)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return domain.ErrInvalidURL
+	}
+
+	// Receivers check the signature of every delivery, which an empty secret
+	// would let anyone forge.
+	if strings.TrimSpace(webhook.Secret) == "" {
+		return domain.ErrNoSecret
+	}
+
+	if len(webhook.EventTypes) == 0 {
+		return domain.ErrNoEventTypes
+	}
+
+	for _, eventType := range webhook.EventTypes {
+		if !outbox.IsEventType(eventType) {
+			return domain.ErrUnknownEventType
+		}
+	}
+
+	return nil
+}
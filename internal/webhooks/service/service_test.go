@@ -0,0 +1,296 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/webhooks/domain"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/webhooks/domain/mocks"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/internal/webhooks/service"
+)
+
+func makeWebhook() domain.Webhook {
+	return domain.Webhook{
+		Id:         1,
+		Url:        "https://erp.example.com/hooks",
+		Secret:     "s3cr3t",
+		EventTypes: []string{"purchase_order.created"},
+		Active:     true,
+	}
+}
+
+func TestWebhookService_Create(t *testing.T) {
+	t.Run("create_ok: should create a webhook", func(t *testing.T) {
+		repo := mocks.NewWebhookRepository(t)
+		deliveryRepo := mocks.NewDeliveryRepository(t)
+		webhookService := service.NewWebhookService(repo, deliveryRepo)
+
+		expected := makeWebhook()
+
+		repo.
+			On("Create", mock.Anything, mock.AnythingOfType("*domain.Webhook")).
+			Return(&expected, nil).
+			Once()
+
+		result, err := webhookService.Create(context.TODO(), expected.Url, expected.Secret, expected.EventTypes)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &expected, result)
+	})
+
+	t.Run("create_invalid_url: should not create a webhook with an invalid url", func(t *testing.T) {
+		repo := mocks.NewWebhookRepository(t)
+		deliveryRepo := mocks.NewDeliveryRepository(t)
+		webhookService := service.NewWebhookService(repo, deliveryRepo)
+
+		_, err := webhookService.Create(context.TODO(), "ftp://erp.example.com", "s3cr3t", []string{"purchase_order.created"})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidURL)
+	})
+
+	t.Run("create_no_secret: should not create a webhook without a secret", func(t *testing.T) {
+		repo := mocks.NewWebhookRepository(t)
+		deliveryRepo := mocks.NewDeliveryRepository(t)
+		webhookService := service.NewWebhookService(repo, deliveryRepo)
+
+		_, err := webhookService.Create(context.TODO(), "https://erp.example.com", "  ", []string{"purchase_order.created"})
+
+		assert.ErrorIs(t, err, domain.ErrNoSecret)
+	})
+
+	t.Run("create_no_event_types: should not create a webhook without event types", func(t *testing.T) {
+		repo := mocks.NewWebhookRepository(t)
+		deliveryRepo := mocks.NewDeliveryRepository(t)
+		webhookService := service.NewWebhookService(repo, deliveryRepo)
+
+		_, err := webhookService.Create(context.TODO(), "https://erp.example.com", "s3cr3t", []string{})
+
+		assert.ErrorIs(t, err, domain.ErrNoEventTypes)
+	})
+
+	t.Run("create_unknown_event_type: should not create a webhook with an unknown event type", func(t *testing.T) {
+		repo := mocks.NewWebhookRepository(t)
+		deliveryRepo := mocks.NewDeliveryRepository(t)
+		webhookService := service.NewWebhookService(repo, deliveryRepo)
+
+		_, err := webhookService.Create(context.TODO(), "https://erp.example.com", "s3cr3t", []string{"seller.deleted"})
+
+		assert.ErrorIs(t, err, domain.ErrUnknownEventType)
+	})
+}
+
+func TestWebhookService_Update(t *testing.T) {
+	t.Run("update_ok: should update only the given fields", func(t *testing.T) {
+		repo := mocks.NewWebhookRepository(t)
+		deliveryRepo := mocks.NewDeliveryRepository(t)
+		webhookService := service.NewWebhookService(repo, deliveryRepo)
+
+		current := makeWebhook()
+		active := false
+
+		expected := makeWebhook()
+		expected.Active = false
+
+		repo.On("GetById", mock.Anything, int64(1)).Return(&current, nil).Once()
+		repo.On("Update", mock.Anything, &expected).Return(&expected, nil).Once()
+
+		result, err := webhookService.Update(context.TODO(), 1, nil, nil, &active)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &expected, result)
+	})
+
+	t.Run("update_not_found: should return error when webhook does not exist", func(t *testing.T) {
+		repo := mocks.NewWebhookRepository(t)
+		deliveryRepo := mocks.NewDeliveryRepository(t)
+		webhookService := service.NewWebhookService(repo, deliveryRepo)
+
+		repo.On("GetById", mock.Anything, int64(1)).Return(nil, domain.ErrWebhookNotFound).Once()
+
+		_, err := webhookService.Update(context.TODO(), 1, nil, nil, nil)
+
+		assert.ErrorIs(t, err, domain.ErrWebhookNotFound)
+	})
+
+	t.Run("update_invalid: should not update with an unknown event type", func(t *testing.T) {
+		repo := mocks.NewWebhookRepository(t)
+		deliveryRepo := mocks.NewDeliveryRepository(t)
+		webhookService := service.NewWebhookService(repo, deliveryRepo)
+
+		current := makeWebhook()
+		eventTypes := []string{"unknown"}
+
+		repo.On("GetById", mock.Anything, int64(1)).Return(&current, nil).Once()
+
+		_, err := webhookService.Update(context.TODO(), 1, nil, &eventTypes, nil)
+
+		assert.ErrorIs(t, err, domain.ErrUnknownEventType)
+	})
+}
+
+func TestWebhookService_RetryDelivery(t *testing.T) {
+	t.Run("retry_ok: should requeue the delivery", func(t *testing.T) {
+		repo := mocks.NewWebhookRepository(t)
+		deliveryRepo := mocks.NewDeliveryRepository(t)
+		webhookService := service.NewWebhookService(repo, deliveryRepo)
+
+		deliveryRepo.On("Requeue", mock.Anything, int64(1)).Return(nil).Once()
+
+		err := webhookService.RetryDelivery(context.TODO(), 1)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("retry_not_found: should return error when the delivery is not dead-lettered", func(t *testing.T) {
+		repo := mocks.NewWebhookRepository(t)
+		deliveryRepo := mocks.NewDeliveryRepository(t)
+		webhookService := service.NewWebhookService(repo, deliveryRepo)
+
+		deliveryRepo.On("Requeue", mock.Anything, int64(1)).Return(domain.ErrDeliveryNotFound).Once()
+
+		err := webhookService.RetryDelivery(context.TODO(), 1)
+
+		assert.ErrorIs(t, err, domain.ErrDeliveryNotFound)
+	})
+}
+
+func TestDispatcher_FanOut(t *testing.T) {
+	t.Run("fan_out_ok: should enqueue a delivery for the subscribed webhooks", func(t *testing.T) {
+		repo := mocks.NewWebhookRepository(t)
+		deliveryRepo := mocks.NewDeliveryRepository(t)
+		dispatcher := service.NewDispatcher(repo, deliveryRepo, http.DefaultClient)
+
+		event := domain.OutboxEvent{Id: 1, EventType: "purchase_order.created"}
+		webhooks := []domain.Webhook{makeWebhook()}
+
+		deliveryRepo.On("GetUndispatchedEvents", mock.Anything, mock.Anything).Return([]domain.OutboxEvent{event}, nil).Once()
+		repo.On("GetActiveByEventType", mock.Anything, "purchase_order.created").Return(webhooks, nil).Once()
+		deliveryRepo.On("Enqueue", mock.Anything, event, webhooks).Return(nil).Once()
+
+		err := dispatcher.FanOut(context.TODO())
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("fan_out_error: should return error when events cannot be read", func(t *testing.T) {
+		repo := mocks.NewWebhookRepository(t)
+		deliveryRepo := mocks.NewDeliveryRepository(t)
+		dispatcher := service.NewDispatcher(repo, deliveryRepo, http.DefaultClient)
+
+		deliveryRepo.On("GetUndispatchedEvents", mock.Anything, mock.Anything).Return(nil, errors.New("any error")).Once()
+
+		err := dispatcher.FanOut(context.TODO())
+
+		assert.Error(t, err)
+	})
+}
+
+func TestDispatcher_Deliver(t *testing.T) {
+	t.Run("deliver_ok: should post a signed payload and mark it delivered", func(t *testing.T) {
+		var signature, event string
+		var body []byte
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			signature = r.Header.Get(service.SignatureHeader)
+			event = r.Header.Get(service.EventHeader)
+			body, _ = io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		repo := mocks.NewWebhookRepository(t)
+		deliveryRepo := mocks.NewDeliveryRepository(t)
+		dispatcher := service.NewDispatcher(repo, deliveryRepo, server.Client())
+
+		delivery := domain.Delivery{
+			Id:        1,
+			EventId:   1,
+			EventType: "purchase_order.created",
+			Url:       server.URL,
+			Secret:    "s3cr3t",
+			Payload:   `{"id":1}`,
+		}
+
+		deliveryRepo.On("GetDue", mock.Anything, mock.Anything, mock.Anything).Return([]domain.Delivery{delivery}, nil).Once()
+		deliveryRepo.On("MarkDelivered", mock.Anything, int64(1), int64(http.StatusOK)).Return(nil).Once()
+
+		err := dispatcher.Deliver(context.TODO())
+
+		assert.NoError(t, err)
+		assert.Equal(t, "purchase_order.created", event)
+		assert.Equal(t, service.Sign("s3cr3t", body), signature)
+	})
+
+	t.Run("deliver_fail: should schedule a retry when the receiver fails", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		repo := mocks.NewWebhookRepository(t)
+		deliveryRepo := mocks.NewDeliveryRepository(t)
+		dispatcher := service.NewDispatcher(repo, deliveryRepo, server.Client())
+
+		delivery := domain.Delivery{Id: 1, Status: domain.DeliveryStatusPending, Url: server.URL, Payload: `{}`}
+
+		deliveryRepo.On("GetDue", mock.Anything, mock.Anything, mock.Anything).Return([]domain.Delivery{delivery}, nil).Once()
+		deliveryRepo.
+			On("MarkFailed", mock.Anything, mock.MatchedBy(func(d *domain.Delivery) bool {
+				return d.Attempts == 1 &&
+					d.Status == domain.DeliveryStatusPending &&
+					d.LastStatusCode == http.StatusInternalServerError &&
+					d.NextAttemptAt.After(time.Now())
+			})).
+			Return(nil).
+			Once()
+
+		err := dispatcher.Deliver(context.TODO())
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("deliver_dead: should dead-letter the delivery after the last attempt", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer server.Close()
+
+		repo := mocks.NewWebhookRepository(t)
+		deliveryRepo := mocks.NewDeliveryRepository(t)
+		dispatcher := service.NewDispatcher(repo, deliveryRepo, server.Client())
+
+		delivery := domain.Delivery{
+			Id:       1,
+			Status:   domain.DeliveryStatusPending,
+			Attempts: service.MaxDeliveryAttempts - 1,
+			Url:      server.URL,
+			Payload:  `{}`,
+		}
+
+		deliveryRepo.On("GetDue", mock.Anything, mock.Anything, mock.Anything).Return([]domain.Delivery{delivery}, nil).Once()
+		deliveryRepo.
+			On("MarkFailed", mock.Anything, mock.MatchedBy(func(d *domain.Delivery) bool {
+				return d.Status == domain.DeliveryStatusDead && d.Attempts == service.MaxDeliveryAttempts
+			})).
+			Return(nil).
+			Once()
+
+		err := dispatcher.Deliver(context.TODO())
+
+		assert.NoError(t, err)
+	})
+}
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, service.BaseRetryDelay, service.Backoff(1))
+	assert.Equal(t, 2*service.BaseRetryDelay, service.Backoff(2))
+	assert.Equal(t, 8*service.BaseRetryDelay, service.Backoff(4))
+	assert.Equal(t, service.MaxRetryDelay, service.Backoff(20))
+}
@@ -0,0 +1,46 @@
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+const (
+	ProductBatchCompatibilityOverride = "product_batch.compatibility_override"
+)
+
+// Entry is a deliberate exception to a business rule: who made it, why, and
+// what it bypassed.
+type Entry struct {
+	Action     string
+	EntityType string
+	EntityId   int64
+	Actor      string
+	Reason     string
+	Details    interface{}
+}
+
+// Record writes the entry using the transaction of the change it explains,
+// so the change is never committed without it.
+func Record(ctx context.Context, tx *sql.Tx, entry Entry) error {
+	details, err := json.Marshal(entry.Details)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		SQLCreateAuditEntry,
+		entry.Action,
+		entry.EntityType,
+		entry.EntityId,
+		entry.Actor,
+		entry.Reason,
+		string(details),
+		time.Now(),
+	)
+
+	return err
+}
@@ -0,0 +1,17 @@
+package audit
+
+const (
+	SQLCreateAuditEntry = `
+	INSERT INTO
+	audit_log (
+	action,
+	entity_type,
+	entity_id,
+	actor,
+	reason,
+	details,
+	created_at
+	)
+    VALUES (?, ?, ?, ?, ?, ?, ?)
+    `
+)
@@ -10,6 +10,7 @@ type LogRepository interface {
 	createLog(ctx context.Context, method, label, level, message string, status int)
 	Error(ctx context.Context, method, label, message string, status int)
 	Info(ctx context.Context, method, label, message string, status int)
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
 }
 
 type loggerRepoImpl struct {
@@ -49,3 +50,12 @@ func (l loggerRepoImpl) Error(ctx context.Context, method, label, message string
 func (l loggerRepoImpl) Info(ctx context.Context, method, label, message string, status int) {
 	l.createLog(ctx, "INFO", method, label, message, status)
 }
+
+func (l loggerRepoImpl) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	result, err := l.db.ExecContext(ctx, sqlDeleteLogsBefore, before)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
@@ -13,4 +13,8 @@ const (
 	)
     VALUES (?, ?, ?, ?, ?, ?)
     `
+
+	sqlDeleteLogsBefore = `
+	DELETE FROM logs WHERE insert_date < ?
+	`
 )
@@ -0,0 +1,70 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+const (
+	PurchaseOrderCreated    = "purchase_order.created"
+	InboundOrderCreated     = "inbound_order.created"
+	ProductBatchCreated     = "product_batch.created"
+	ProductBatchExpiry      = "product_batch.expiry_alert"
+	ProductBatchTransferred = "product_batch.transferred"
+	ProductRecordCreated    = "product_record.created"
+	SectionCapacityChanged  = "section.capacity_changed"
+	TemperatureAlert        = "temperature.alert"
+)
+
+var EventTypes = []string{
+	PurchaseOrderCreated,
+	InboundOrderCreated,
+	ProductBatchCreated,
+	ProductBatchExpiry,
+	ProductBatchTransferred,
+	ProductRecordCreated,
+	SectionCapacityChanged,
+	TemperatureAlert,
+}
+
+func IsEventType(eventType string) bool {
+	for _, known := range EventTypes {
+		if known == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Publish writes a domain event to the outbox using the transaction of the
+// change that produced it, so the event exists if and only if the change is committed.
+func Publish(ctx context.Context, tx *sql.Tx, eventType string, aggregateId int64, payload interface{}) error {
+	return publish(ctx, tx, eventType, aggregateId, nil, payload)
+}
+
+// PublishWarehouseEvent is like Publish for events that happen inside a
+// warehouse, so consumers such as the live event stream can filter by it.
+func PublishWarehouseEvent(ctx context.Context, tx *sql.Tx, eventType string, aggregateId int64, warehouseId int64, payload interface{}) error {
+	return publish(ctx, tx, eventType, aggregateId, warehouseId, payload)
+}
+
+func publish(ctx context.Context, tx *sql.Tx, eventType string, aggregateId int64, warehouseId interface{}, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		SQLCreateOutboxEvent,
+		eventType,
+		aggregateId,
+		warehouseId,
+		string(data),
+		time.Now(),
+	)
+
+	return err
+}
@@ -0,0 +1,15 @@
+package outbox
+
+const (
+	SQLCreateOutboxEvent = `
+	INSERT INTO
+	outbox_events (
+	event_type,
+	aggregate_id,
+	warehouse_id,
+	payload,
+	created_at
+	)
+    VALUES (?, ?, ?, ?, ?)
+    `
+)
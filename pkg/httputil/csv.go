@@ -0,0 +1,28 @@
+package httputil
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vinigracindo/mercado-fresco-stranger-strings/libs/logger"
+)
+
+// NewCSV answers with a CSV attachment: the header line followed by the
+// records.
+func NewCSV(ctx *gin.Context, filename string, header []string, records [][]string) {
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	ctx.Header("Content-Type", "text/csv; charset=utf-8")
+	ctx.Status(http.StatusOK)
+
+	w := csv.NewWriter(ctx.Writer)
+	w.Write(header)
+	w.WriteAll(records)
+	if err := w.Error(); err != nil {
+		logger.Logger.Error(ctx, ctx.Request.Method, ctx.Request.RequestURI, err.Error(), ctx.Writer.Status())
+		return
+	}
+
+	logger.Logger.Info(ctx, ctx.Request.Method, ctx.Request.RequestURI, "", ctx.Writer.Status())
+}
@@ -6,9 +6,16 @@ import (
 )
 
 func NewError(ctx *gin.Context, status int, err error) {
+	NewErrorWithDetails(ctx, status, err, nil)
+}
+
+// NewErrorWithDetails answers with an error that carries data the client
+// needs to act on it, such as the records in the way of a delete.
+func NewErrorWithDetails(ctx *gin.Context, status int, err error, details interface{}) {
 	er := HTTPError{
 		Code:    status,
 		Message: err.Error(),
+		Details: details,
 	}
 	ctx.JSON(status, er)
 
@@ -16,6 +23,7 @@ func NewError(ctx *gin.Context, status int, err error) {
 }
 
 type HTTPError struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Details interface{} `json:"details,omitempty"`
 }